@@ -91,6 +91,9 @@ func (in *Call) ValidateCreate() (admission.Warnings, error) {
 		if err := ValidateTaskScheduler(schedule); err != nil {
 			return nil, errors.Wrapf(err, "schedule error")
 		}
+		if schedule.Trace != nil {
+			return nil, errors.Errorf("trace scheduling is not supported by calls")
+		}
 	}
 
 	// Suspend Field
@@ -87,6 +87,9 @@ func (in *Cascade) ValidateCreate() (admission.Warnings, error) {
 		if err := ValidateTaskScheduler(schedule); err != nil {
 			return nil, errors.Wrapf(err, "schedule error")
 		}
+		if schedule.Trace != nil {
+			return nil, errors.Errorf("trace scheduling is not supported by cascades")
+		}
 	}
 
 	return nil, nil
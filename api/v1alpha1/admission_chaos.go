@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -57,11 +58,35 @@ func (in *Chaos) ValidateCreate() (admission.Warnings, error) {
 		"name", in.GetNamespace()+"/"+in.GetName(),
 	)
 
+	if err := in.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := ValidatePolicies(in); err != nil {
+		return nil, errors.Wrapf(err, "chaos '%s' violates the policies", in.GetName())
+	}
+
 	return nil, nil
 }
 
+// validate checks the fault, without the policies.
+func (in *Chaos) validate() error {
+	// Chaos generated from templates (e.g, by Cascades) are rendered by now. Thus, the stressors can be validated.
+	if fault, err := ParseRawChaos(in.Spec.Raw); err == nil && fault.GetKind() == "StressChaos" {
+		if err := CheckStressChaos(fault); err != nil {
+			return errors.Wrapf(err, "chaos '%s' has invalid stressors", in.GetName())
+		}
+	}
+
+	return nil
+}
+
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
 func (in *Chaos) ValidateUpdate(runtime.Object) (admission.Warnings, error) {
+	if err := ValidatePoliciesOnUpdate(in); err != nil {
+		return nil, errors.Wrapf(err, "chaos '%s' violates the policies", in.GetName())
+	}
+
 	return nil, nil
 }
 
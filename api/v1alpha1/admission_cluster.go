@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -58,6 +59,20 @@ func (in *Cluster) Default() {
 	if in.Spec.DefaultDistributionSpec != nil {
 		in.Spec.DefaultDistributionSpec = &DistributionSpec{Name: DistributionConstant}
 	}
+
+	// Lifetime field
+	if lifetime := in.Spec.Lifetime; lifetime != nil {
+		if lifetime.Distribution == "" {
+			lifetime.Distribution = LifetimeFixed
+		}
+	}
+
+	// RetryPolicy field
+	if retry := in.Spec.RetryPolicy; retry != nil {
+		if retry.Backoff == nil {
+			retry.Backoff = &metav1.Duration{Duration: DefaultRetryBackoff}
+		}
+	}
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
@@ -66,9 +81,28 @@ func (in *Cluster) ValidateCreate() (admission.Warnings, error) {
 		"name", in.GetNamespace()+"/"+in.GetName(),
 	)
 
-	// Set missing values for the template
-	if err := in.Spec.GenerateObjectFromTemplate.Prepare(true); err != nil {
-		clusterlog.Error(err, "template error")
+	// Variants field
+	if len(in.Spec.Variants) > 0 {
+		templates, err := in.Spec.VariantTemplates()
+		if err != nil {
+			return nil, errors.Wrapf(err, "variants error")
+		}
+
+		// the instances of the cluster are given by the instances of the variants.
+		in.Spec.MaxInstances = 0
+
+		for i := range templates {
+			if err := templates[i].Prepare(true); err != nil {
+				return nil, errors.Wrapf(err, "variants[%d] error", i)
+			}
+
+			in.Spec.MaxInstances += templates[i].MaxInstances
+		}
+	} else {
+		// Set missing values for the template
+		if err := in.Spec.GenerateObjectFromTemplate.Prepare(true); err != nil {
+			clusterlog.Error(err, "template error")
+		}
 	}
 
 	// TestData field
@@ -93,13 +127,27 @@ func (in *Cluster) ValidateCreate() (admission.Warnings, error) {
 
 	// Schedule field
 	if schedule := in.Spec.Schedule; schedule != nil {
-		if in.Spec.MaxInstances < 1 {
-			return nil, errors.Errorf("scheduling requires at least one instance")
-		}
-
 		if err := ValidateTaskScheduler(schedule); err != nil {
 			return nil, errors.Wrapf(err, "schedule error")
 		}
+
+		// with traces, the instances of the cluster are given by the records of the trace.
+		if trace := schedule.Trace; trace != nil {
+			if len(in.Spec.Variants) > 0 {
+				return nil, errors.Errorf("trace scheduling conflicts with variants")
+			}
+
+			if trace.Data != "" {
+				records, err := ParseTrace(trace.Data)
+				if err != nil {
+					return nil, errors.Wrapf(err, "trace error")
+				}
+
+				in.Spec.MaxInstances = len(records)
+			}
+		} else if in.Spec.MaxInstances < 1 {
+			return nil, errors.Errorf("scheduling requires at least one instance")
+		}
 	}
 
 	// Suspend Field
@@ -110,13 +158,16 @@ func (in *Cluster) ValidateCreate() (admission.Warnings, error) {
 	}
 
 	// Resources field
-	// if distributionSpec is nil, Default() will set it to constant.
+	// if neither distributionSpec nor weights are set, the resources are split evenly.
 	if resources := in.Spec.Resources; resources != nil {
 		if in.Spec.SuspendWhen != nil {
 			return nil, errors.Errorf("resource distribution conflicts with SuspendWhen conditions")
 		}
 
-		if in.Spec.MaxInstances < 1 {
+		// traces loaded from ConfigMaps are counted by the controller.
+		tracing := in.Spec.Schedule != nil && in.Spec.Schedule.Trace != nil
+
+		if in.Spec.MaxInstances < 1 && !tracing {
 			return nil, errors.Errorf("resource distribution requires at least one services")
 		}
 
@@ -125,8 +176,56 @@ func (in *Cluster) ValidateCreate() (admission.Warnings, error) {
 		}
 	}
 
+	// Topology field
+	if topology := in.Spec.Topology; topology != nil {
+		if len(topology.NodeSelectors) == 0 {
+			return nil, errors.Errorf("topology requires at least one node selector")
+		}
+
+		for i, selector := range topology.NodeSelectors {
+			if len(selector) == 0 {
+				return nil, errors.Errorf("topology.nodeSelectors[%d] is empty", i)
+			}
+		}
+	}
+
+	// DisruptionBudget field
+	if budget := in.Spec.DisruptionBudget; budget != nil {
+		if (budget.MinAvailable == nil) == (budget.MaxUnavailable == nil) {
+			return nil, errors.Errorf("disruptionBudget requires exactly one of minAvailable and maxUnavailable")
+		}
+	}
+
+	// Lifetime field
+	if lifetime := in.Spec.Lifetime; lifetime != nil {
+		if err := lifetime.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "lifetime error")
+		}
+	}
+
+	// RetryPolicy field
+	if retry := in.Spec.RetryPolicy; retry != nil {
+		if err := retry.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "retryPolicy error")
+		}
+	}
+
+	// Size guard
+	// -- the queue of jobs is kept in the status of the cluster, and must fit in etcd.
+	if err := in.ValidateSize(); err != nil {
+		return nil, errors.Wrapf(err, "size error")
+	}
+
 	// Placement Field
-	// -- Validated in the scenario, because it involves references to other actions
+	// -- References to other actions are validated in the scenario.
+	if in.Spec.Placement.GetMaxCreationsPerMinute() < 0 {
+		return nil, errors.Errorf("placement.maxCreationsPerMinute cannot be negative")
+	}
+
+	// MaxConcurrentJobs Field
+	if in.Spec.MaxConcurrentJobs < 0 {
+		return nil, errors.Errorf("maxConcurrentJobs cannot be negative")
+	}
 
 	return nil, nil
 }
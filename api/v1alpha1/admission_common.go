@@ -47,6 +47,12 @@ func ValidateExpr(expr *ConditionalExpr) error {
 		}
 	}
 
+	if expr.HasAlertmanagerExpr() {
+		if _, _, err := expr.Alertmanager.Parse(); err != nil {
+			return errors.Wrapf(err, "wrong alertmanager expr")
+		}
+	}
+
 	return nil
 }
 
@@ -87,6 +93,24 @@ func ValidateTaskScheduler(sch *TaskSchedulerSpec) error {
 		}
 	}
 
+	// ramp
+	if ramp := sch.Ramp; ramp != nil {
+		enabledPolicies++
+
+		if err := ramp.Validate(); err != nil {
+			merr = multierror.Append(merr, errors.Wrapf(err, "RampError"))
+		}
+	}
+
+	// trace
+	if trace := sch.Trace; trace != nil {
+		enabledPolicies++
+
+		if err := trace.Validate(); err != nil {
+			merr = multierror.Append(merr, errors.Wrapf(err, "TraceError"))
+		}
+	}
+
 	// check for conflicts
 	if enabledPolicies != 1 {
 		merr = multierror.Append(merr, errors.Errorf("Expected 1 scheduling policy but got %d", enabledPolicies))
@@ -0,0 +1,89 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// +kubebuilder:object:generate=false
+
+// PolicyValidator evaluates organization-wide policies on the objects that are created or updated.
+// It is installed by the operator at startup. If no validator is installed, policies are not enforced.
+type PolicyValidator interface {
+	Validate(obj runtime.Object) error
+}
+
+var policyValidator PolicyValidator
+
+// SetPolicyValidator installs the validator that is used by the admission webhooks.
+func SetPolicyValidator(validator PolicyValidator) {
+	policyValidator = validator
+}
+
+var (
+	// policyScheme resolves the kind of the objects that are decoded without type information. It is built lazily,
+	// because the types are registered by the init functions of the package.
+	policyScheme     *runtime.Scheme
+	policySchemeOnce sync.Once
+)
+
+func getPolicyScheme() *runtime.Scheme {
+	policySchemeOnce.Do(func() {
+		policyScheme = runtime.NewScheme()
+		utilruntime.Must(AddToScheme(policyScheme))
+	})
+
+	return policyScheme
+}
+
+// ValidatePolicies checks the object against the installed policies. The policies match objects by kind, and
+// therefore the kind is derived from the type of the object, if it is not set.
+func ValidatePolicies(obj runtime.Object) error {
+	if policyValidator == nil {
+		return nil
+	}
+
+	if obj.GetObjectKind().GroupVersionKind().Kind == "" {
+		gvk, err := apiutil.GVKForObject(obj, getPolicyScheme())
+		if err != nil {
+			return errors.Wrapf(err, "cannot resolve the kind of the object")
+		}
+
+		obj = obj.DeepCopyObject()
+		obj.GetObjectKind().SetGroupVersionKind(gvk)
+	}
+
+	return policyValidator.Validate(obj)
+}
+
+// ValidatePoliciesOnUpdate checks the updated object against the installed policies, so that admitted objects
+// cannot be edited into violating ones. Objects that are being deleted are not checked, so that their finalizers
+// can be removed even if the policies have changed since their admission.
+func ValidatePoliciesOnUpdate(obj client.Object) error {
+	if !obj.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+
+	return ValidatePolicies(obj)
+}
@@ -17,8 +17,13 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"encoding/json"
+	"net"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/carv-ics-forth/frisbee/pkg/images"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -76,39 +81,488 @@ func (in *Scenario) Default() {
 				scenariolog.Error(err, "definition error", "action", action.Name)
 			}
 
-		case ActionCall, ActionDelete:
-			// calls and deletes do not involve templates.
+		case ActionCall, ActionDelete, ActionSeed, ActionSnapshot, ActionRestore, ActionAssertSQL, ActionConsistency,
+			ActionClockSkew, ActionDiskFault, ActionTLSFault, ActionRegistryOutage,
+			ActionControlPlaneFault, ActionRawChaos, ActionTrigger:
+			// calls, deletes, seeds, snapshots, and checkers are not parameterized by the user.
 			continue
 		}
 	}
+
+	// Chained scenarios
+	if next := in.Spec.Next; next != nil {
+		if next.OnSuccess != nil {
+			if err := next.OnSuccess.Prepare(false); err != nil {
+				scenariolog.Error(err, "definition error", "next", "onSuccess")
+			}
+		}
+
+		if next.OnFailure != nil {
+			if err := next.OnFailure.Prepare(false); err != nil {
+				scenariolog.Error(err, "definition error", "next", "onFailure")
+			}
+		}
+
+		if next.MaxDepth == nil {
+			next.MaxDepth = &DefaultMaxChainDepth
+		}
+	}
+
+	// Run Metadata
+	if in.Spec.Run == nil {
+		in.Spec.Run = &RunMetadata{}
+	}
+
+	if in.Spec.Run.ID == "" {
+		in.Spec.Run.ID = NewRunID()
+	}
+
+	// Network Profile
+	for i := 0; i < len(in.Spec.NetworkProfile); i++ {
+		if in.Spec.NetworkProfile[i].Direction == "" {
+			in.Spec.NetworkProfile[i].Direction = NetworkDirectionBoth
+		}
+	}
+
+	// Ingestion
+	if ingestion := in.Spec.Ingestion; ingestion != nil && ingestion.Database == "" {
+		ingestion.Database = DefaultIngestionDatabase
+	}
+
+	// Tracing
+	if tracing := in.Spec.Tracing; tracing != nil && tracing.Backend == "" {
+		tracing.Backend = TracingTempo
+	}
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
 func (in *Scenario) ValidateCreate() (admission.Warnings, error) {
+	if err := in.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := ValidatePolicies(in); err != nil {
+		return nil, errors.Wrapf(err, "scenario '%s' violates the policies", in.GetName())
+	}
+
+	return nil, nil
+}
+
+// validate checks the actions and the fields of the scenario, without the policies.
+func (in *Scenario) validate() error {
 	legitReferences, err := BuildDependencyGraph(in)
 	if err != nil {
-		return nil, errors.Wrapf(err, "invalid scenario [%s]", in.GetName())
+		return errors.Wrapf(err, "invalid scenario [%s]", in.GetName())
 	}
 
 	for i, action := range in.Spec.Actions {
 		// Check that expressions used in the assertions are ok
 		if !action.Assert.IsZero() {
 			if err := ValidateExpr(action.Assert); err != nil {
-				return nil, errors.Wrapf(err, "Invalid expr in assertion")
+				return errors.Wrapf(err, "Invalid expr in assertion")
 			}
 		}
 
+		if action.Timeout != nil && action.Timeout.Duration <= 0 {
+			return errors.Errorf("action [%s] has non-positive timeout", action.Name)
+		}
+
 		// Ensure that the type of action is supported and is correctly set
 		if err := CheckAction(&in.Spec.Actions[i], legitReferences); err != nil {
-			return nil, errors.Wrapf(err, "incorrent spec for type [%s] of action [%s]", action.ActionType, action.Name)
+			return errors.Wrapf(err, "incorrent spec for type [%s] of action [%s]", action.ActionType, action.Name)
+		}
+
+		// seeding files requires the shared volume of the scenario.
+		if action.ActionType == ActionSeed && action.Seed.Files != nil && in.Spec.TestData == nil {
+			return errors.Errorf("action [%s] seeds files, but the scenario has no testData", action.Name)
+		}
+
+		// the histories are collected from the shared volume of the scenario.
+		if action.ActionType == ActionConsistency && in.Spec.TestData == nil {
+			return errors.Errorf("action [%s] checks histories, but the scenario has no testData", action.Name)
 		}
 	}
 
 	if err := CheckForBoundedExecution(legitReferences); err != nil {
-		return nil, errors.Wrapf(err, "infinity error")
+		return errors.Wrapf(err, "infinity error")
 	}
 
-	return nil, nil
+	if err := CheckTeardown(in, legitReferences); err != nil {
+		return errors.Wrapf(err, "teardown error")
+	}
+
+	if err := CheckNetworkProfile(in, legitReferences); err != nil {
+		return errors.Wrapf(err, "networkProfile error")
+	}
+
+	if err := CheckHostAliases(in); err != nil {
+		return errors.Wrapf(err, "hostAliases error")
+	}
+
+	if err := CheckIsolation(in); err != nil {
+		return errors.Wrapf(err, "isolation error")
+	}
+
+	if err := CheckTargetNamespaces(in); err != nil {
+		return errors.Wrapf(err, "targetNamespaces error")
+	}
+
+	if err := images.Overrides(in.Spec.ImageOverrides).Validate(); err != nil {
+		return errors.Wrapf(err, "imageOverrides error")
+	}
+
+	if err := in.Spec.Propagation.Validate(); err != nil {
+		return errors.Wrapf(err, "propagation error")
+	}
+
+	if err := in.Spec.Run.Validate(); err != nil {
+		return errors.Wrapf(err, "run error")
+	}
+
+	if err := CheckDatasources(in); err != nil {
+		return errors.Wrapf(err, "datasources error")
+	}
+
+	if err := CheckIngestion(in); err != nil {
+		return errors.Wrapf(err, "ingestion error")
+	}
+
+	if err := CheckTracing(in); err != nil {
+		return errors.Wrapf(err, "tracing error")
+	}
+
+	if err := CheckResourceBudget(in); err != nil {
+		return errors.Wrapf(err, "resources error")
+	}
+
+	// Abort Field
+	if abort := in.Spec.Abort; abort != nil && *abort {
+		return errors.Errorf("Cannot create a scenario that is already aborted")
+	}
+
+	// Next Field
+	if next := in.Spec.Next; next != nil {
+		if next.OnSuccess == nil && next.OnFailure == nil {
+			return errors.Errorf("next requires at least one of onSuccess or onFailure")
+		}
+
+		if next.MaxDepth != nil && GetChainDepth(in) > *next.MaxDepth {
+			return errors.Errorf("chain depth '%d' exceeds the maximum depth '%d'", GetChainDepth(in), *next.MaxDepth)
+		}
+	}
+
+	return nil
+}
+
+// CheckIsolation validates the placement of the actions into dedicated namespaces.
+// 1. Ensures that the namespaces of the actions are valid.
+// 2. Rejects the features that require the actions to share a namespace.
+func CheckIsolation(scenario *Scenario) error {
+	if !scenario.Spec.IsNamespaceIsolated() {
+		return nil
+	}
+
+	if scenario.Spec.TestData != nil {
+		return errors.Errorf("testData volumes cannot be shared across namespaces")
+	}
+
+	if len(scenario.Spec.NetworkProfile) > 0 {
+		return errors.Errorf("networkProfile is not supported across namespaces")
+	}
+
+	if len(scenario.Spec.Teardown) > 0 {
+		return errors.Errorf("teardown actions are not supported across namespaces")
+	}
+
+	for _, action := range scenario.Spec.Actions {
+		switch action.ActionType {
+		case ActionService, ActionCluster:
+			namespace := ActionNamespace(scenario.GetNamespace(), action.Name)
+
+			if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+				return errors.Errorf("invalid namespace '%s' for action '%s': %s",
+					namespace, action.Name, strings.Join(errs, "; "))
+			}
+
+		case ActionChaos, ActionCascade, ActionCall, ActionSeed, ActionSnapshot, ActionRestore, ActionAssertSQL,
+			ActionConsistency, ActionClockSkew, ActionDiskFault, ActionTLSFault, ActionRegistryOutage,
+			ActionControlPlaneFault, ActionRawChaos:
+			// these actions address the services of other actions, which are placed in different namespaces.
+			return errors.Errorf("action '%s' of type '%s' is not supported across namespaces",
+				action.Name, action.ActionType)
+		}
+	}
+
+	return nil
+}
+
+// CheckTargetNamespaces validates the placement of actions into the target namespaces of the scenario.
+// 1. Ensures that the target namespaces are unique and valid.
+// 2. Ensures that only Service and Cluster actions are placed, and only into declared target namespaces.
+// 3. Rejects the features that require the placed actions to share the namespace of the scenario.
+// 4. Rejects the actions that target services of placed actions, as they address services by name.
+func CheckTargetNamespaces(scenario *Scenario) error {
+	targets := make(map[string]struct{}, len(scenario.Spec.TargetNamespaces))
+
+	for _, target := range scenario.Spec.TargetNamespaces {
+		if _, exists := targets[target]; exists {
+			return errors.Errorf("duplicate target namespace '%s'", target)
+		}
+
+		targets[target] = struct{}{}
+
+		namespace := ActionNamespace(scenario.GetNamespace(), target)
+
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return errors.Errorf("invalid namespace '%s' for target '%s': %s", namespace, target, strings.Join(errs, "; "))
+		}
+	}
+
+	if len(targets) > 0 && scenario.Spec.IsNamespaceIsolated() {
+		return errors.Errorf("targetNamespaces cannot be combined with %s isolation", IsolationNamespace)
+	}
+
+	placed := make(map[string]string)
+
+	actions := append(append([]Action{}, scenario.Spec.Actions...), scenario.Spec.Teardown...)
+
+	for _, action := range actions {
+		if action.TargetNamespace == "" {
+			continue
+		}
+
+		if action.ActionType != ActionService && action.ActionType != ActionCluster {
+			return errors.Errorf("action '%s' of type '%s' cannot be placed in a target namespace",
+				action.Name, action.ActionType)
+		}
+
+		if _, exists := targets[action.TargetNamespace]; !exists {
+			return errors.Errorf("action '%s' refers to undeclared target namespace '%s'",
+				action.Name, action.TargetNamespace)
+		}
+
+		placed[action.Name] = action.TargetNamespace
+	}
+
+	if len(placed) == 0 {
+		return nil
+	}
+
+	if scenario.Spec.TestData != nil {
+		return errors.Errorf("testData volumes cannot be shared across namespaces")
+	}
+
+	for _, link := range scenario.Spec.NetworkProfile {
+		for _, end := range []string{link.From, link.To} {
+			if target, exists := placed[end]; exists {
+				return errors.Errorf("networkProfile link '%s-%s' cannot end in target namespace '%s'",
+					link.From, link.To, target)
+			}
+		}
+	}
+
+	for i, action := range actions {
+		for _, ref := range serviceReferences(&actions[i]) {
+			if target, exists := placed[ref]; exists {
+				return errors.Errorf("action '%s' of type '%s' cannot address the services of '%s' in target namespace '%s'",
+					action.Name, action.ActionType, ref, target)
+			}
+		}
+	}
+
+	return nil
+}
+
+// serviceReferences returns the actions whose services are targeted by the given action, as referenced by macros
+// (e.g, .cluster.servers.all) and selectors. The inputs of Service and Cluster actions are not included, because
+// their macros resolve to qualified DNS names.
+func serviceReferences(action *Action) []string {
+	var (
+		refs   []string
+		macros []string
+	)
+
+	addInputs := func(inputs []UserInputs) {
+		for _, input := range inputs {
+			for _, value := range input {
+				var str string
+
+				if value != nil && json.Unmarshal(value.Raw, &str) == nil {
+					macros = append(macros, str)
+				}
+			}
+		}
+	}
+
+	addSelector := func(selector *ServiceSelector) {
+		if selector.Macro != nil {
+			macros = append(macros, *selector.Macro)
+		}
+
+		for _, cluster := range selector.Match.ByCluster {
+			refs = append(refs, cluster)
+		}
+
+		for _, names := range selector.Match.ByName {
+			refs = append(refs, names...)
+		}
+	}
+
+	switch action.ActionType {
+	case ActionChaos:
+		addInputs(action.Chaos.Inputs)
+	case ActionCascade:
+		addInputs(action.Cascade.Inputs)
+	case ActionCall:
+		macros = append(macros, action.Call.Services...)
+		refs = append(refs, action.Call.Services...)
+	case ActionClockSkew:
+		macros = append(macros, action.ClockSkew.Services...)
+		refs = append(refs, action.ClockSkew.Services...)
+	case ActionDiskFault:
+		addSelector(&action.DiskFault.Selector)
+	case ActionRegistryOutage:
+		addSelector(&action.RegistryOutage.Selector)
+	}
+
+	for _, macro := range macros {
+		// .cluster.<name>.<filter>
+		if fields := strings.Split(macro, "."); len(fields) == 4 && fields[0] == "" && fields[1] == "cluster" {
+			refs = append(refs, fields[2])
+		}
+	}
+
+	return refs
+}
+
+// CheckNetworkProfile validates the emulated links between the groups of the scenario.
+// 1. Ensures that the ends of a link are Service or Cluster actions.
+// 2. Ensures that there is at most one link between two groups.
+// 3. Ensures that the network conditions are parsable.
+func CheckNetworkProfile(scenario *Scenario, references map[string]*Action) error {
+	links := make(map[string]struct{}, len(scenario.Spec.NetworkProfile))
+
+	for _, link := range scenario.Spec.NetworkProfile {
+		for _, end := range []string{link.From, link.To} {
+			action, exists := references[end]
+			if !exists {
+				return errors.Errorf("link '%s' refers to non-existing action '%s'", link.Name(), end)
+			}
+
+			if action.ActionType != ActionService && action.ActionType != ActionCluster {
+				return errors.Errorf("link '%s' refers to action '%s' of type '%s'. Only '%s' and '%s' are supported",
+					link.Name(), end, action.ActionType, ActionService, ActionCluster)
+			}
+		}
+
+		if link.From == link.To {
+			return errors.Errorf("link '%s' cannot connect a group to itself", link.Name())
+		}
+
+		if _, exists := links[link.Name()]; exists {
+			return errors.Errorf("Duplicate link '%s'", link.Name())
+		}
+
+		links[link.Name()] = struct{}{}
+
+		if link.Latency == "" && link.Loss == "" {
+			return errors.Errorf("link '%s' requires at least one of latency or loss", link.Name())
+		}
+
+		if link.Latency != "" {
+			if _, err := time.ParseDuration(link.Latency); err != nil {
+				return errors.Wrapf(err, "invalid latency for link '%s'", link.Name())
+			}
+		}
+
+		if link.Jitter != "" {
+			if link.Latency == "" {
+				return errors.Errorf("link '%s' has jitter but no latency", link.Name())
+			}
+
+			if _, err := time.ParseDuration(link.Jitter); err != nil {
+				return errors.Wrapf(err, "invalid jitter for link '%s'", link.Name())
+			}
+		}
+
+		if link.Loss != "" {
+			loss, err := strconv.ParseFloat(link.Loss, 64)
+			if err != nil {
+				return errors.Wrapf(err, "invalid loss for link '%s'", link.Name())
+			}
+
+			if loss < 0 || loss > 100 {
+				return errors.Errorf("loss for link '%s' must be a percentage in [0, 100]", link.Name())
+			}
+		}
+	}
+
+	return nil
+}
+
+// CheckHostAliases validates that every alias has hostnames, and resolves to exactly one of Service or IP.
+func CheckHostAliases(scenario *Scenario) error {
+	for i, alias := range scenario.Spec.HostAliases {
+		if len(alias.Hostnames) == 0 {
+			return errors.Errorf("alias [%d] has no hostnames", i)
+		}
+
+		for _, hostname := range alias.Hostnames {
+			if errs := validation.IsDNS1123Subdomain(hostname); errs != nil {
+				return errors.Errorf("invalid hostname '%s': %s", hostname, strings.Join(errs, "; "))
+			}
+		}
+
+		if (alias.Service == "") == (alias.IP == "") {
+			return errors.Errorf("alias [%d] requires exactly one of service or ip", i)
+		}
+
+		if alias.IP != "" && net.ParseIP(alias.IP) == nil {
+			return errors.Errorf("alias [%d] has invalid ip '%s'", i, alias.IP)
+		}
+	}
+
+	return nil
+}
+
+// CheckTeardown validates the actions that run when the scenario is aborted.
+// 1. Ensures that only Call actions are used, since teardown operates on already running services.
+// 2. Ensures that teardown names do not collide with the names of the scenario actions, or with each other.
+func CheckTeardown(scenario *Scenario, references map[string]*Action) error {
+	teardownIndex := make(map[string]struct{}, len(scenario.Spec.Teardown))
+
+	for i, action := range scenario.Spec.Teardown {
+		if errs := validation.IsDNS1123Subdomain(action.Name); errs != nil {
+			err := errors.New(strings.Join(errs, "; "))
+
+			return errors.Wrapf(err, "invalid actioname %s", action.Name)
+		}
+
+		if action.ActionType != ActionCall {
+			return errors.Errorf("teardown action '%s' is of type '%s'. Only '%s' is supported",
+				action.Name, action.ActionType, ActionCall)
+		}
+
+		if action.DependsOn != nil || action.Assert != nil {
+			return errors.Errorf("teardown action '%s' cannot have dependencies or assertions", action.Name)
+		}
+
+		if _, exists := references[action.Name]; exists {
+			return errors.Errorf("teardown action '%s' conflicts with a scenario action", action.Name)
+		}
+
+		if _, exists := teardownIndex[action.Name]; exists {
+			return errors.Errorf("Duplicate teardown action '%s'", action.Name)
+		}
+
+		teardownIndex[action.Name] = struct{}{}
+
+		if err := CheckAction(&scenario.Spec.Teardown[i], references); err != nil {
+			return errors.Wrapf(err, "incorrent spec for teardown action [%s]", action.Name)
+		}
+	}
+
+	return nil
 }
 
 // BuildDependencyGraph validates the execution workflow.
@@ -310,6 +764,90 @@ func CheckAction(action *Action, references map[string]*Action) error {
 		_, err := call.ValidateCreate()
 		return err
 
+	case ActionSeed:
+		if action.EmbedActions.Seed == nil {
+			return errors.Errorf("empty seed definition")
+		}
+
+		return CheckSeed(action.EmbedActions.Seed)
+
+	case ActionSnapshot:
+		if action.EmbedActions.Snapshot == nil {
+			return errors.Errorf("empty snapshot definition")
+		}
+
+		return CheckSnapshot(action.EmbedActions.Snapshot)
+
+	case ActionRestore:
+		if action.EmbedActions.Restore == nil {
+			return errors.Errorf("empty restore definition")
+		}
+
+		return CheckRestore(action.EmbedActions.Restore)
+
+	case ActionAssertSQL:
+		if action.EmbedActions.AssertSQL == nil {
+			return errors.Errorf("empty assertSQL definition")
+		}
+
+		return CheckAssertSQL(action.EmbedActions.AssertSQL)
+
+	case ActionConsistency:
+		if action.EmbedActions.Consistency == nil {
+			return errors.Errorf("empty consistency definition")
+		}
+
+		return CheckConsistency(action.EmbedActions.Consistency)
+
+	case ActionClockSkew:
+		if action.EmbedActions.ClockSkew == nil {
+			return errors.Errorf("empty clockSkew definition")
+		}
+
+		return CheckClockSkew(action.EmbedActions.ClockSkew)
+
+	case ActionDiskFault:
+		if action.EmbedActions.DiskFault == nil {
+			return errors.Errorf("empty diskFault definition")
+		}
+
+		return CheckDiskFault(action.EmbedActions.DiskFault)
+
+	case ActionTLSFault:
+		if action.EmbedActions.TLSFault == nil {
+			return errors.Errorf("empty tlsFault definition")
+		}
+
+		return CheckTLSFault(action.EmbedActions.TLSFault)
+
+	case ActionRegistryOutage:
+		if action.EmbedActions.RegistryOutage == nil {
+			return errors.Errorf("empty registryOutage definition")
+		}
+
+		return CheckRegistryOutage(action.EmbedActions.RegistryOutage)
+
+	case ActionControlPlaneFault:
+		if action.EmbedActions.ControlPlaneFault == nil {
+			return errors.Errorf("empty controlPlaneFault definition")
+		}
+
+		return CheckControlPlaneFault(action.EmbedActions.ControlPlaneFault)
+
+	case ActionRawChaos:
+		if action.EmbedActions.RawChaos == nil {
+			return errors.Errorf("empty rawChaos definition")
+		}
+
+		return CheckRawChaos(action.EmbedActions.RawChaos)
+
+	case ActionTrigger:
+		if action.EmbedActions.Trigger == nil {
+			return errors.Errorf("empty trigger definition")
+		}
+
+		return CheckTrigger(action.EmbedActions.Trigger)
+
 	default:
 		return errors.Errorf("Unknown action")
 	}
@@ -317,6 +855,10 @@ func CheckAction(action *Action, references map[string]*Action) error {
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
 func (in *Scenario) ValidateUpdate(runtime.Object) (admission.Warnings, error) {
+	if err := ValidatePoliciesOnUpdate(in); err != nil {
+		return nil, errors.Wrapf(err, "scenario '%s' violates the policies", in.GetName())
+	}
+
 	return nil, nil
 }
 
@@ -62,21 +62,64 @@ func (in *Service) ValidateCreate() (admission.Warnings, error) {
 		"name", in.GetNamespace()+"/"+in.GetName(),
 	)
 
+	if err := in.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := ValidatePolicies(in); err != nil {
+		return nil, errors.Wrapf(err, "service '%s' violates the policies", in.GetName())
+	}
+
+	return nil, nil
+}
+
+// validate checks the definition of the service, without the policies.
+func (in *Service) validate() error {
 	for i := range in.Spec.Containers {
 		container := in.Spec.Containers[i]
 
 		if container.Name == MainContainerName { // Validate Main container(s)
 			if err := in.validateMainContainer(&container); err != nil {
-				return nil, errors.Wrapf(err, "error in service template '%s'", in.GetName())
+				return errors.Wrapf(err, "error in service template '%s'", in.GetName())
 			}
 		} else { // Validate Sidecar container(s)
 			if err := in.validateSidecarContainer(&container); err != nil {
-				return nil, errors.Wrapf(err, "service '%s' definition error", in.GetName())
+				return errors.Wrapf(err, "service '%s' definition error", in.GetName())
 			}
 		}
 	}
 
-	return nil, nil
+	if err := in.validateHooks(); err != nil {
+		return errors.Wrapf(err, "hooks error in service '%s'", in.GetName())
+	}
+
+	return nil
+}
+
+func (in *Service) validateHooks() error {
+	for containerName, hooks := range in.Spec.Hooks {
+		if len(hooks.PostStart) == 0 && len(hooks.PreStop) == 0 {
+			return errors.Errorf("empty hooks for container '%s'", containerName)
+		}
+
+		// The existence of the container is checked when the Pod is created, because the hooks may refer
+		// to containers that are inherited from other templates.
+		for _, container := range in.Spec.Containers {
+			if container.Name != containerName || container.Lifecycle == nil {
+				continue
+			}
+
+			if len(hooks.PostStart) > 0 && container.Lifecycle.PostStart != nil {
+				return errors.Errorf("container '%s' already defines a postStart handler", containerName)
+			}
+
+			if len(hooks.PreStop) > 0 && container.Lifecycle.PreStop != nil {
+				return errors.Errorf("container '%s' already defines a preStop handler", containerName)
+			}
+		}
+	}
+
+	return nil
 }
 
 func (in *Service) validateMainContainer(container *corev1.Container) error {
@@ -116,7 +159,11 @@ func (in *Service) validateSidecarContainer(container *corev1.Container) error {
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (in *Service) ValidateUpdate(_ runtime.Object) (admission.Warnings, error) {
+func (in *Service) ValidateUpdate(runtime.Object) (admission.Warnings, error) {
+	if err := ValidatePoliciesOnUpdate(in); err != nil {
+		return nil, errors.Wrapf(err, "service '%s' violates the policies", in.GetName())
+	}
+
 	return nil, nil
 }
 
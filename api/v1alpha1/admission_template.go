@@ -17,10 +17,16 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"context"
+
+	"github.com/carv-ics-forth/frisbee/pkg/dashboard"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/json"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -37,7 +43,12 @@ var _ webhook.Validator = &Template{}
 // log is for logging in this package.
 var templatelog = logf.Log.WithName("template-hook")
 
+// dashboardReader is used by the webhook to read the dashboards of the telemetry agents.
+var dashboardReader client.Reader
+
 func (in *Template) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	dashboardReader = mgr.GetAPIReader()
+
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(in).
 		Complete()
@@ -56,10 +67,18 @@ func (in *Template) ValidateCreate() (admission.Warnings, error) {
 		"name", in.GetNamespace()+"/"+in.GetName(),
 	)
 
+	if in.Spec.Extends == in.GetName() && in.GetName() != "" {
+		return nil, errors.Errorf("template '%s' cannot extend itself", in.GetName())
+	}
+
 	if err := in.validateTemplateLanguage(); err != nil {
 		return nil, errors.Wrapf(err, "erroneous template '%s'", in.GetName())
 	}
 
+	if err := in.validateDashboards(); err != nil {
+		return nil, errors.Wrapf(err, "erroneous dashboards for template '%s'", in.GetName())
+	}
+
 	return nil, nil
 }
 
@@ -86,12 +105,19 @@ func (in *Template) validateTemplateLanguage() error {
 		}
 	}
 
+	// templates that only extend another template embed no spec. The inherited spec is validated on its own.
+	if in.Spec.EmbedSpecs == nil {
+		return nil
+	}
+
+	// The embedded specs are not yet rendered, and they carry no kind. Thus, they are checked against the policies
+	// only once the respective jobs are created.
 	if in.Spec.Service != nil {
 		service := Service{
 			Spec: *in.Spec.Service,
 		}
 
-		_, err := service.ValidateCreate()
+		err := service.validate()
 		return errors.Wrapf(err, "service definition error")
 	}
 
@@ -100,13 +126,51 @@ func (in *Template) validateTemplateLanguage() error {
 			Spec: *in.Spec.Chaos,
 		}
 
-		_, err := chaos.ValidateCreate()
+		err := chaos.validate()
 		return errors.Wrapf(err, "chaos definition error")
 	}
 
+	if in.Spec.Scenario != nil {
+		scenario := Scenario{
+			Spec: *in.Spec.Scenario,
+		}
+
+		scenario.Default()
+
+		err := scenario.validate()
+		return errors.Wrapf(err, "scenario definition error")
+	}
+
 	return nil
 }
 
+// validateDashboards checks the dashboards (<template>.config) that accompany a telemetry agent. Dashboards
+// that are not yet installed, and references to datasources that may be declared by scenarios, are checked
+// when the scenario mounts the dashboards into Grafana.
+func (in *Template) validateDashboards() error {
+	if dashboardReader == nil || in.Spec.EmbedSpecs == nil || in.Spec.Service == nil {
+		return nil
+	}
+
+	if _, isAgent := in.Spec.Service.Decorators.Annotations[SidecarTelemetry]; !isAgent {
+		return nil
+	}
+
+	var dashboards corev1.ConfigMap
+
+	key := client.ObjectKey{Namespace: in.GetNamespace(), Name: in.GetName() + ".config"}
+
+	if err := dashboardReader.Get(context.Background(), key, &dashboards); err != nil {
+		if k8errors.IsNotFound(err) {
+			return nil
+		}
+
+		return errors.Wrapf(err, "cannot get configmap '%s'", key)
+	}
+
+	return dashboard.ValidateSchema(dashboards.Data)
+}
+
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
 func (in *Template) ValidateUpdate(runtime.Object) (admission.Warnings, error) {
 	return nil, nil
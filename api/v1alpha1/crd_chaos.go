@@ -33,9 +33,45 @@ type Chaos struct {
 	Status ChaosStatus `json:"status,omitempty"`
 }
 
+// DisruptionPolicy defines how chaos injection treats the PodDisruptionBudgets of the targeted pods.
+type DisruptionPolicy string
+
+const (
+	// DisruptionPolicyRespect defers the injection until the disruption budgets allow it.
+	// It emulates voluntary disruptions, such as node drains.
+	DisruptionPolicyRespect = DisruptionPolicy("Respect")
+
+	// DisruptionPolicyViolate injects the fault even if it exceeds the disruption budgets, and records the violation.
+	// It emulates forced disruptions, such as node crashes.
+	DisruptionPolicyViolate = DisruptionPolicy("Violate")
+)
+
+// CleanupProbe verifies that a recovered fault has left no residue in its targets (e.g, tc rules, IO hooks).
+type CleanupProbe struct {
+	// Container is the container of the targets that runs the probe. If empty, the default container is used.
+	// +optional
+	Container string `json:"container,omitempty"`
+
+	// Command runs, without a shell, in every target of the fault. A non-zero exit code indicates that the target
+	// still has residue of the fault (e.g, ["sh", "-c", "! tc qdisc show | grep -q netem"]).
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command"`
+}
+
 // ChaosSpec defines the desired state of Chaos.
 type ChaosSpec struct {
 	Raw string `json:"raw,omitempty"`
+
+	// DisruptionPolicy checks the fault against the PodDisruptionBudgets of the targeted pods.
+	// If empty, the budgets are not checked.
+	// +kubebuilder:validation:Enum=Respect;Violate
+	// +optional
+	DisruptionPolicy DisruptionPolicy `json:"disruptionPolicy,omitempty"`
+
+	// CleanupProbe runs in the targets once the fault is recovered, in addition to the records of Chaos-Mesh,
+	// for verifying that the fault has been actually removed. The outcome is reported by the FaultResidue condition.
+	// +optional
+	CleanupProbe *CleanupProbe `json:"cleanupProbe,omitempty"`
 }
 
 // ChaosStatus defines the observed state of Chaos.
@@ -44,6 +80,11 @@ type ChaosStatus struct {
 
 	// LastScheduleTime provide information about  the last time a Pod was scheduled.
 	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// ExpiresAt is the time at which the fault is expected to be recovered, according to its duration.
+	// Faults without duration (e.g, pod-kill) do not expire.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
 }
 
 func (in *Chaos) GetReconcileStatus() Lifecycle {
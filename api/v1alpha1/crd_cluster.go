@@ -17,11 +17,22 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Progress",type=integer,JSONPath=`.status.percentComplete`,description="Percentage of completed jobs"
+// +kubebuilder:printcolumn:name="Rate",type=string,JSONPath=`.status.schedulingRate`,description="Scheduled jobs per minute"
+// +kubebuilder:printcolumn:name="ETA",type=date,JSONPath=`.status.expectedCompletionTime`,description="Expected time for scheduling the last job"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Cluster is the Schema for the clusters API.
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -46,6 +57,69 @@ type PlacementSpec struct {
 	// Nodes will place all the Services of this Cluster within the specific set of nodes.
 	// +optional
 	Nodes []string `json:"nodes,omitempty"`
+
+	// MaxCreationsPerMinute bounds the number of Services that are created within any minute, so that large
+	// Clusters do not overwhelm the API server. Recreations by the RetryPolicy count as well. Zero means no limit.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxCreationsPerMinute int `json:"maxCreationsPerMinute,omitempty"`
+}
+
+// GetMaxCreationsPerMinute returns the limit of the creation rate, or zero if the rate is not limited.
+func (in *PlacementSpec) GetMaxCreationsPerMinute() int {
+	if in == nil {
+		return 0
+	}
+
+	return in.MaxCreationsPerMinute
+}
+
+// TopologySpec pins the instances of the Cluster to specific nodes, or failure domains.
+type TopologySpec struct {
+	// NodeSelectors are assigned to the instances in order. Instance i is placed according to
+	// NodeSelectors[i mod len(NodeSelectors)]. For example, [{"topology.kubernetes.io/zone": "us-east-1a"},
+	// {"topology.kubernetes.io/zone": "eu-west-1a"}] alternates the instances between the two zones.
+	// +kubebuilder:validation:MinItems=1
+	NodeSelectors []map[string]string `json:"nodeSelectors"`
+}
+
+// DisruptionBudgetSpec limits the number of Services that can be voluntarily disrupted at the same time.
+// The budget is materialized as a PodDisruptionBudget that covers all the Services of the Cluster.
+// Exactly one of MinAvailable and MaxUnavailable must be set.
+type DisruptionBudgetSpec struct {
+	// MinAvailable is the number (or percentage) of Services that must remain available.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable is the number (or percentage) of Services that can be unavailable.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// ClusterVariant is a subset of the services of a Cluster, generated by its own template and inputs.
+// The size of the subset is given either by Instances, or by Weight. All the variants of a Cluster must use the same
+// method. With Instances, the Cluster has as many services as the sum of the instances of its variants. With Weight,
+// the instances of the Cluster are split proportionally to the weights (e.g, 7 and 3 for 70% readers and 30% writers).
+type ClusterVariant struct {
+	// TemplateRef refers to the template of the variant. Defaults to the TemplateRef of the Cluster.
+	// +optional
+	TemplateRef string `json:"templateRef,omitempty"`
+
+	// Inputs are the parameters of the variant. Defaults to the Inputs of the Cluster.
+	// +optional
+	Inputs []UserInputs `json:"inputs,omitempty"`
+
+	// Patches are applied after the Patches of the Cluster.
+	// +optional
+	Patches []SpecPatch `json:"patches,omitempty"`
+
+	// Instances is the number of services of the variant.
+	// +optional
+	Instances int `json:"instances,omitempty"`
+
+	// Weight is the share of the instances of the Cluster that is assigned to the variant.
+	// +optional
+	Weight int `json:"weight,omitempty"`
 }
 
 // ClusterSpec defines the desired state of Cluster.
@@ -66,6 +140,11 @@ type ClusterSpec struct {
 	// +optional
 	DefaultDistributionSpec *DistributionSpec `json:"defaultDistribution,omitempty"`
 
+	// Variants mix services from different templates (or inputs) under the lifecycle of a single Cluster.
+	// If set, the services are generated by the variants instead of the TemplateRef of the Cluster.
+	// +optional
+	Variants []ClusterVariant `json:"variants,omitempty"`
+
 	/*
 		Job Scheduling
 	*/
@@ -78,10 +157,33 @@ type ClusterSpec struct {
 	// +optional
 	Schedule *TaskSchedulerSpec `json:"schedule,omitempty"`
 
+	// MaxConcurrentJobs bounds the number of Services that run simultaneously. The remaining Services stay queued,
+	// and they are created as the running ones complete. It applies along with the Schedule, which paces the
+	// creation of the Services. Zero means no limit.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxConcurrentJobs int `json:"maxConcurrentJobs,omitempty"`
+
 	// Placement defines rules for placing the containers across the available nodes.
 	// +optional
 	Placement *PlacementSpec `json:"placement,omitempty"`
 
+	// GroupService creates a Kubernetes Service, named after the Cluster, that spreads the requests across
+	// all the Services of the Cluster (DNS round-robin, if the Services expose no ports). With a service mesh,
+	// it becomes the virtual service on which the mesh retry and timeout policies apply.
+	// The group service is addressable via the macro ".cluster.<name>.service".
+	// +optional
+	GroupService bool `json:"groupService,omitempty"`
+
+	// Topology pins specific instances to specific nodes, or failure domains.
+	// +optional
+	Topology *TopologySpec `json:"topology,omitempty"`
+
+	// DisruptionBudget creates a PodDisruptionBudget for the Services of the Cluster.
+	// Whether chaos injection respects the budget is decided by the Chaos (see ChaosSpec.DisruptionPolicy).
+	// +optional
+	DisruptionBudget *DisruptionBudgetSpec `json:"disruptionBudget,omitempty"`
+
 	/*
 		Execution Flow
 	*/
@@ -97,15 +199,30 @@ type ClusterSpec struct {
 	// Tolerate forces the Controller to continue in spite of failed jobs.
 	// +optional
 	Tolerate *TolerateSpec `json:"tolerate,omitempty"`
+
+	// Lifetime terminates every job once its lifetime expires, and counts it as successful.
+	// Along with the scheduling policy, it allows for churn experiments (e.g, nodes joining and leaving).
+	// +optional
+	Lifetime *LifetimeSpec `json:"lifetime,omitempty"`
+
+	// RetryPolicy recreates the failed services, before they are counted as failures.
+	// +optional
+	RetryPolicy *RetryPolicySpec `json:"retryPolicy,omitempty"`
 }
 
 // ClusterStatus defines the observed state of Cluster.
 type ClusterStatus struct {
 	Lifecycle `json:",inline"`
 
-	// QueuedJobs is a list of jobs that the controller has to scheduled.
+	// QueuedJobs is a list of jobs that the controller has to scheduled. To keep the status small,
+	// the specs of the jobs are not stored, but are constructed on demand from the templates.
+	// +optional
+	QueuedJobs []QueuedJob `json:"queuedJobs,omitempty"`
+
+	// TemplateHash identifies the templates and the inputs from which the QueuedJobs are constructed.
+	// If the templates change after the initialization of the Cluster, the construction of the jobs fails.
 	// +optional
-	QueuedJobs []ServiceSpec `json:"queuedJobs,omitempty"`
+	TemplateHash string `json:"templateHash,omitempty"`
 
 	// DefaultDistribution keeps the evaluated expression of GenerateObjectFromTemplate.DefaultDistributionSpec.
 	// +optional
@@ -120,6 +237,74 @@ type ClusterStatus struct {
 
 	// LastScheduleTime provide information about  the last time a Job was successfully scheduled.
 	LastScheduleTime metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// PercentComplete is the percentage of the jobs that are completed, either successfully or not.
+	// +optional
+	PercentComplete int `json:"percentComplete,omitempty"`
+
+	// SchedulingRate is the number of jobs scheduled per minute, from the creation of the cluster until the last
+	// scheduled job.
+	// +optional
+	SchedulingRate string `json:"schedulingRate,omitempty"`
+
+	// CreationsLastMinute is the number of Services that have been created within the last minute. It is compared
+	// against the Placement.MaxCreationsPerMinute, and the backpressure is reported by ConditionCreationThrottled.
+	// +optional
+	CreationsLastMinute int `json:"creationsLastMinute,omitempty"`
+
+	// ExpectedCompletionTime is the time the last job is expected to be scheduled. For time-based schedules
+	// (cron, timeline) it is given by the schedule. Otherwise, it is estimated by the scheduling rate.
+	// +optional
+	ExpectedCompletionTime *metav1.Time `json:"expectedCompletionTime,omitempty"`
+
+	// PerZone summarizes the jobs per failure domain (zone). Jobs that are not yet placed, or run on
+	// nodes without zone, are accounted under UnknownZone.
+	// +optional
+	PerZone map[string]DomainStatus `json:"perZone,omitempty"`
+
+	// Retries keeps the recreations of the services, as dictated by the RetryPolicy.
+	// +optional
+	Retries map[string]JobRetry `json:"retries,omitempty"`
+}
+
+// QueuedJob points to the template and to the inputs from which a job is constructed.
+type QueuedJob struct {
+	// Variant is the index of the variant whose template generates the job.
+	// Without variants, the job is generated by the template of the Cluster.
+	// +optional
+	Variant int `json:"variant,omitempty"`
+
+	// Inputs is the index of the input set that is passed to the template.
+	// +optional
+	Inputs int `json:"inputs,omitempty"`
+}
+
+// JobRetry keeps the recreations of a service.
+type JobRetry struct {
+	// Count is the number of times that the service has been recreated.
+	Count int `json:"count"`
+
+	// RecreateAfter is set while the failed service is deleted, and waits to be recreated.
+	// +optional
+	RecreateAfter *metav1.Time `json:"recreateAfter,omitempty"`
+}
+
+// UnknownZone is the failure domain of jobs whose zone is not known.
+const UnknownZone = "unknown"
+
+// DomainStatus summarizes the jobs that run within a failure domain.
+type DomainStatus struct {
+	// +optional
+	PendingJobs int `json:"pendingJobs,omitempty"`
+
+	// +optional
+	RunningJobs int `json:"runningJobs,omitempty"`
+
+	// +optional
+	SuccessfulJobs int `json:"successfulJobs,omitempty"`
+
+	// +optional
+	FailedJobs int `json:"failedJobs,omitempty"`
 }
 
 func (in *Cluster) GetReconcileStatus() Lifecycle {
@@ -142,3 +327,130 @@ type ClusterList struct {
 func init() {
 	SchemeBuilder.Register(&Cluster{}, &ClusterList{})
 }
+
+// MaxClusterObjectSize bounds the estimated size of a Cluster object, so that it can be stored in etcd
+// (by default, etcd limits the size of a request to 1.5MiB).
+var MaxClusterObjectSize = 1024 * 1024
+
+// ValidateSize estimates the size of the Cluster once all of its jobs are queued, and returns an error if the size
+// exceeds the MaxClusterObjectSize.
+func (in *Cluster) ValidateSize() error {
+	encoded, err := json.Marshal(in)
+	if err != nil {
+		return errors.Wrapf(err, "cannot encode cluster")
+	}
+
+	// every job costs a queue entry, and possibly a point in the expected timeline and the default distribution.
+	perJob := len(`{"variant":,"inputs":},`) + 2*len(strconv.Itoa(in.Spec.MaxInstances))
+
+	if in.Spec.Schedule != nil {
+		perJob += len(`"2006-01-02T15:04:05Z",`)
+	}
+
+	if in.Spec.DefaultDistributionSpec != nil {
+		perJob += len(`0.00000000000000001,`)
+	}
+
+	if size := len(encoded) + in.Spec.MaxInstances*perJob; size > MaxClusterObjectSize {
+		return errors.Errorf("the estimated size of the cluster '%d' exceeds the limit '%d'. Consider splitting the cluster",
+			size, MaxClusterObjectSize)
+	}
+
+	return nil
+}
+
+// VariantTemplates returns the template of every variant, with the instances of the variant and the defaults
+// inherited by the Cluster. The services of the variant i are generated by the template i.
+func (in *ClusterSpec) VariantTemplates() ([]GenerateObjectFromTemplate, error) {
+	instances, err := in.variantInstances()
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]GenerateObjectFromTemplate, len(in.Variants))
+
+	for i, variant := range in.Variants {
+		templates[i] = GenerateObjectFromTemplate{
+			TemplateRef:  variant.TemplateRef,
+			MaxInstances: instances[i],
+			Inputs:       variant.Inputs,
+			Patches:      append(append([]SpecPatch(nil), in.Patches...), variant.Patches...),
+		}
+
+		if templates[i].TemplateRef == "" {
+			templates[i].TemplateRef = in.TemplateRef
+		}
+
+		if len(templates[i].Inputs) == 0 {
+			templates[i].Inputs = in.Inputs
+		}
+	}
+
+	return templates, nil
+}
+
+// variantInstances returns the number of instances of every variant. Weights are converted to instances using
+// the largest remainder method, so that the instances of the variants sum to the instances of the Cluster.
+func (in *ClusterSpec) variantInstances() ([]int, error) {
+	instances := make([]int, len(in.Variants))
+
+	var sumInstances, sumWeights int
+
+	for i, variant := range in.Variants {
+		switch {
+		case variant.Instances < 0 || variant.Weight < 0:
+			return nil, errors.Errorf("variants[%d] has negative size", i)
+		case (variant.Instances > 0) == (variant.Weight > 0):
+			return nil, errors.Errorf("variants[%d] must set exactly one of instances and weight", i)
+		}
+
+		instances[i] = variant.Instances
+		sumInstances += variant.Instances
+		sumWeights += variant.Weight
+	}
+
+	switch {
+	case sumInstances > 0 && sumWeights > 0:
+		return nil, errors.New("variants cannot mix instances and weights")
+
+	case sumInstances > 0:
+		if in.MaxInstances > 0 && in.MaxInstances != sumInstances {
+			return nil, errors.Errorf("instances '%d' differ from the instances of the variants '%d'",
+				in.MaxInstances, sumInstances)
+		}
+
+		return instances, nil
+
+	default:
+		if in.MaxInstances < 1 {
+			return nil, errors.New("weighted variants require the instances of the cluster")
+		}
+
+		type remainder struct {
+			variant int
+			value   int
+		}
+
+		assigned := 0
+		remainders := make([]remainder, len(in.Variants))
+
+		for i, variant := range in.Variants {
+			instances[i] = in.MaxInstances * variant.Weight / sumWeights
+			assigned += instances[i]
+
+			remainders[i] = remainder{variant: i, value: in.MaxInstances * variant.Weight % sumWeights}
+		}
+
+		// the variants with the largest remainders get the instances that are left due to rounding.
+		sort.SliceStable(remainders, func(i, j int) bool {
+			return remainders[i].value > remainders[j].value
+		})
+
+		for i := 0; assigned < in.MaxInstances; i++ {
+			instances[remainders[i].variant]++
+			assigned++
+		}
+
+		return instances, nil
+	}
+}
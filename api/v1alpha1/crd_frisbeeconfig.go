@@ -0,0 +1,112 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FrisbeeConfigName is the name of the FrisbeeConfig that configures the operator. Other FrisbeeConfigs are ignored.
+const FrisbeeConfigName = "frisbee"
+
+const (
+	// ConditionConfigApplied indicates whether the configuration is used by the operator.
+	ConditionConfigApplied = ConditionType("Applied")
+
+	// ReasonConfigApplied indicates that the configuration is used by the operator.
+	ReasonConfigApplied = "Applied"
+
+	// ReasonConfigInvalid indicates that the configuration is rejected, and the previous one is still in use.
+	ReasonConfigInvalid = "Invalid"
+
+	// ReasonConfigIgnored indicates that the configuration is not named after FrisbeeConfigName.
+	ReasonConfigIgnored = "Ignored"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+
+// FrisbeeConfig is the configuration of the operator. It is watched at runtime, and the changes apply to the
+// objects that are created afterwards, without restarting the operator. Settings that are bound when the operator
+// starts (e.g, the ports of the webhooks) remain flags of the operator.
+type FrisbeeConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FrisbeeConfigSpec   `json:"spec,omitempty"`
+	Status FrisbeeConfigStatus `json:"status,omitempty"`
+}
+
+// FrisbeeConfigSpec defines the desired configuration of the operator.
+type FrisbeeConfigSpec struct {
+	// DeveloperMode indicates that the operator runs outside the cluster, and reaches the services via the ingress.
+	// +optional
+	DeveloperMode bool `json:"developerMode,omitempty"`
+
+	// Namespace is the namespace of the platform.
+	Namespace string `json:"namespace"`
+
+	// DomainName is the domain of the ingress endpoints (e.g, grafana-<test>.<domainName>).
+	DomainName string `json:"domainName"`
+
+	// IngressClassName is the class of the ingresses that expose the services.
+	IngressClassName string `json:"ingressClassName"`
+
+	// ControllerName is the name of the operator.
+	ControllerName string `json:"controllerName"`
+
+	// Templates replace the system templates by custom ones, with the names of the system templates as keys
+	// (e.g, frisbee.system.telemetry.prometheus: my.prometheus). The custom templates must accept the same inputs.
+	// +optional
+	Templates map[string]string `json:"templates,omitempty"`
+
+	// LogLevel changes the verbosity of the operator: debug, info, error, or a verbosity for the V() messages.
+	// If empty, the level is given by the flags of the operator.
+	// +kubebuilder:validation:Pattern=`^(debug|info|error|[0-9]+)$`
+	// +optional
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// DebugScenarios enables detailed logging for the given scenarios (<namespace>/<name>), regardless of the
+	// LogLevel.
+	// +optional
+	DebugScenarios []string `json:"debugScenarios,omitempty"`
+}
+
+// FrisbeeConfigStatus defines the observed state of FrisbeeConfig.
+type FrisbeeConfigStatus struct {
+	// ObservedGeneration is the generation of the spec that has been last processed by the operator.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions describe whether the configuration is in use.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FrisbeeConfigList contains a list of FrisbeeConfig.
+type FrisbeeConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FrisbeeConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FrisbeeConfig{}, &FrisbeeConfigList{})
+}
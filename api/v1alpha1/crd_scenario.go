@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"hash/fnv"
 	"sort"
 	"strings"
 	"time"
@@ -26,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // +kubebuilder:object:root=true
@@ -47,6 +49,7 @@ func (in *Scenario) Table() (header []string, data [][]string) {
 		"Scenario",
 		"Age",
 		"Actions",
+		"Progress",
 		"Phase",
 		"Duration",
 	}
@@ -83,11 +86,17 @@ func (in *Scenario) Table() (header []string, data [][]string) {
 		duration = cond.LastTransitionTime.Sub(in.GetCreationTimestamp().Time)
 	}
 
+	if meta.IsStatusConditionTrue(in.Status.Conditions, ConditionAborted.String()) {
+		cond := meta.FindStatusCondition(in.Status.Conditions, ConditionAborted.String())
+		duration = cond.LastTransitionTime.Sub(in.GetCreationTimestamp().Time)
+	}
+
 	data = append(data, []string{
 		in.GetNamespace(),
 		in.GetName(),
 		age.Round(time.Second).String(),
 		scheduled,
+		fmt.Sprintf("%d%%", in.Status.PercentComplete),
 		in.Status.Phase.String(),
 		duration.Round(time.Second).String(),
 	})
@@ -110,12 +119,36 @@ const (
 	ActionDelete ActionType = "Delete"
 	// ActionCall starts a remote process execution, from the controller to the targeted services.
 	ActionCall ActionType = "Call"
+	// ActionSeed populates the system under test with synthetic data (i.e, files, database rows).
+	ActionSeed ActionType = "Seed"
+	// ActionSnapshot takes snapshots of the volumes of the system under test.
+	ActionSnapshot ActionType = "Snapshot"
+	// ActionRestore provisions the volumes of the system under test from snapshots.
+	ActionRestore ActionType = "Restore"
+	// ActionAssertSQL verifies the data of a database, by comparing the result of a query against a threshold.
+	ActionAssertSQL ActionType = "AssertSQL"
+	// ActionConsistency validates the operation histories of the clients against a consistency model.
+	ActionConsistency ActionType = "Consistency"
+	// ActionClockSkew shifts the clocks of the targeted services, according to a skew profile.
+	ActionClockSkew ActionType = "ClockSkew"
+	// ActionDiskFault disrupts the storage of the selected services (e.g, latency, errors, full disks).
+	ActionDiskFault ActionType = "DiskFault"
+	// ActionTLSFault swaps the certificate of a service with a faulty one (e.g, expired), and restores it afterwards.
+	ActionTLSFault ActionType = "TLSFault"
+	// ActionRegistryOutage makes the image registries unreachable, and restarts the selected services.
+	ActionRegistryOutage ActionType = "RegistryOutage"
+	// ActionControlPlaneFault degrades the control plane of the cluster (e.g, API server latency, paused controllers).
+	ActionControlPlaneFault ActionType = "ControlPlaneFault"
+	// ActionRawChaos injects an inline Chaos-Mesh manifest, for faults whose fields are not modeled by Frisbee.
+	ActionRawChaos ActionType = "RawChaos"
+	// ActionTrigger blocks until an external system releases the action with a signed callback.
+	ActionTrigger ActionType = "Trigger"
 )
 
 // Action is a step in a workflow that defines a particular part of a testing process.
 type Action struct {
 	// ActionType refers to a category of actions that can be associated with a specific controller.
-	// +kubebuilder:validation:Enum=Service;Cluster;Chaos;Cascade;Delete;Call
+	// +kubebuilder:validation:Enum=Service;Cluster;Chaos;Cascade;Delete;Call;Seed;Snapshot;Restore;AssertSQL;Consistency;ClockSkew;DiskFault;TLSFault;RegistryOutage;ControlPlaneFault;RawChaos;Trigger
 	ActionType ActionType `json:"action"`
 
 	// Name is a unique identifier of the action
@@ -130,6 +163,16 @@ type Action struct {
 	// +optional
 	Assert *ConditionalExpr `json:"assert,omitempty"`
 
+	// Timeout fails the Scenario if the job of the action is not completed within the given duration since the
+	// action was started. The job is killed along with the rest of the running jobs.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// TargetNamespace places the job of a Service or Cluster action in one of the targetNamespaces of the
+	// scenario, instead of the namespace of the scenario.
+	// +optional
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+
 	*EmbedActions `json:",inline"`
 }
 
@@ -170,6 +213,42 @@ type EmbedActions struct {
 
 	// +optional
 	Call *CallSpec `json:"call,omitempty"`
+
+	// +optional
+	Seed *SeedSpec `json:"seed,omitempty"`
+
+	// +optional
+	Snapshot *SnapshotSpec `json:"snapshot,omitempty"`
+
+	// +optional
+	Restore *RestoreSpec `json:"restore,omitempty"`
+
+	// +optional
+	AssertSQL *AssertSQLSpec `json:"assertSQL,omitempty"`
+
+	// +optional
+	Consistency *ConsistencySpec `json:"consistency,omitempty"`
+
+	// +optional
+	ClockSkew *ClockSkewSpec `json:"clockSkew,omitempty"`
+
+	// +optional
+	DiskFault *DiskFaultSpec `json:"diskFault,omitempty"`
+
+	// +optional
+	TLSFault *TLSFaultSpec `json:"tlsFault,omitempty"`
+
+	// +optional
+	RegistryOutage *RegistryOutageSpec `json:"registryOutage,omitempty"`
+
+	// +optional
+	ControlPlaneFault *ControlPlaneFaultSpec `json:"controlPlaneFault,omitempty"`
+
+	// +optional
+	RawChaos *ChaosSpec `json:"rawChaos,omitempty"`
+
+	// +optional
+	Trigger *TriggerSpec `json:"trigger,omitempty"`
 }
 
 type TestdataVolume struct {
@@ -181,11 +260,95 @@ type TestdataVolume struct {
 	GlobalNamespace bool `json:"globalNamespace,omitempty"`
 }
 
+// NextSpec defines the scenarios that are automatically submitted once this scenario is completed.
+// The next scenario runs in the same namespace, and is generated from a template that embeds a scenario spec.
+// Because jobs are named after their actions, chained scenarios should use distinct action names.
+type NextSpec struct {
+	// OnSuccess generates a scenario that runs after this scenario completes successfully.
+	// +optional
+	OnSuccess *GenerateObjectFromTemplate `json:"onSuccess,omitempty"`
+
+	// OnFailure generates a scenario that runs after this scenario fails (e.g, for deeper diagnosis or cleanup).
+	// +optional
+	OnFailure *GenerateObjectFromTemplate `json:"onFailure,omitempty"`
+
+	// MaxDepth is the maximum number of chained scenarios. It protects from scenarios that trigger each other
+	// in a loop. Defaults to DefaultMaxChainDepth.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxDepth *int `json:"maxDepth,omitempty"`
+}
+
+// DefaultMaxChainDepth is the maximum number of chained scenarios, if not set by the user.
+var DefaultMaxChainDepth = 5
+
+// NetworkDirection specifies the direction of the traffic affected by a network link.
+type NetworkDirection string
+
+const (
+	NetworkDirectionTo   = NetworkDirection("to")
+	NetworkDirectionFrom = NetworkDirection("from")
+	NetworkDirectionBoth = NetworkDirection("both")
+)
+
+// NetworkLinkSpec emulates the network conditions between two groups of services, such as a WAN link
+// between geo-distributed datacenters (e.g, us-east <-> eu-west: 80ms, 1% loss).
+type NetworkLinkSpec struct {
+	// From is the name of a Service or Cluster action at the one end of the link.
+	From string `json:"from"`
+
+	// To is the name of a Service or Cluster action at the other end of the link.
+	To string `json:"to"`
+
+	// Direction of the affected traffic. Defaults to both.
+	// +kubebuilder:validation:Enum=to;from;both
+	// +optional
+	Direction NetworkDirection `json:"direction,omitempty"`
+
+	// Latency is the delay added to the packets (e.g, 80ms).
+	// +optional
+	Latency string `json:"latency,omitempty"`
+
+	// Jitter is the variation of the latency (e.g, 10ms).
+	// +optional
+	Jitter string `json:"jitter,omitempty"`
+
+	// Loss is the percentage of the dropped packets (e.g, 1).
+	// +optional
+	Loss string `json:"loss,omitempty"`
+}
+
+// Name returns a unique identifier of the link.
+func (in NetworkLinkSpec) Name() string {
+	return fmt.Sprintf("network-%s-%s", in.From, in.To)
+}
+
+// HostAliasSpec maps a set of hostnames to the address of a Service, or to a fixed IP.
+// It is useful for testing software with hardcoded hostnames.
+type HostAliasSpec struct {
+	// Hostnames for the above address.
+	Hostnames []string `json:"hostnames"`
+
+	// Service is the name of a Service whose address the hostnames resolve to.
+	// The Service must be running before the Pods that use the alias are created.
+	// +optional
+	Service string `json:"service,omitempty"`
+
+	// IP is a fixed address that the hostnames resolve to. It conflicts with Service.
+	// +optional
+	IP string `json:"ip,omitempty"`
+}
+
 // ScenarioSpec defines the desired state of Scenario.
 type ScenarioSpec struct {
 	// TestData defines a volume that will be mounted across the Scenario's Services.
 	TestData *TestdataVolume `json:"testData,omitempty"`
 
+	// Vars are scenario-wide variables that are available to the templates of all actions
+	// (as {{.inputs.vars.<name>}}), in addition to the per-action inputs.
+	// +optional
+	Vars map[string]string `json:"vars,omitempty"`
+
 	// Actions are the tasks that will be taken.
 	Actions []Action `json:"actions"`
 
@@ -193,6 +356,147 @@ type ScenarioSpec struct {
 	// not apply to already started executions.  Defaults to false.
 	// +optional
 	Suspend *bool `json:"suspend,omitempty"`
+
+	// Teardown are actions that run when the scenario is aborted, before the remaining jobs are removed.
+	// They are used for graceful termination, e.g, to flush data or to stop the load generators.
+	// Only Call actions are supported.
+	// +optional
+	Teardown []Action `json:"teardown,omitempty"`
+
+	// Abort tells the controller to stop the scenario. The scenario transitions to the Aborted phase,
+	// runs the Teardown actions, and then removes the remaining jobs. Defaults to false.
+	// +optional
+	Abort *bool `json:"abort,omitempty"`
+
+	// Next defines the scenarios to be submitted once this scenario is completed.
+	// Aborted scenarios do not trigger the next scenarios.
+	// +optional
+	Next *NextSpec `json:"next,omitempty"`
+
+	// NetworkProfile emulates the network conditions between the groups of the scenario, without explicit
+	// chaos actions. A link is established once both of its ends are running, and is removed when the
+	// scenario is completed.
+	// +optional
+	NetworkProfile []NetworkLinkSpec `json:"networkProfile,omitempty"`
+
+	// HostAliases are injected into the hosts file of every Pod generated by the scenario.
+	// +optional
+	HostAliases []HostAliasSpec `json:"hostAliases,omitempty"`
+
+	// DNSConfig is merged into the DNS configuration of every Pod generated by the scenario
+	// (e.g, to add a custom search domain).
+	// +optional
+	DNSConfig *v1.PodDNSConfig `json:"dnsConfig,omitempty"`
+
+	// ImageOverrides replace the tags of matching images in every Pod generated by the scenario, e.g,
+	// icsforth/ycsb: candidate. This allows testing a freshly built image without editing the templates.
+	// +optional
+	ImageOverrides map[string]string `json:"imageOverrides,omitempty"`
+
+	// ImagePullSecrets are attached to every Pod generated by the scenario, in addition to the secrets of the
+	// operator. The secrets must exist in the namespace of the scenario.
+	// +optional
+	ImagePullSecrets []v1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// Isolation defines where the resources of the actions are placed. Defaults to Shared.
+	// With Namespace isolation, every Service and Cluster action gets a dedicated namespace (<namespace>-<action>)
+	// that is labeled with the scenario and the action, and is removed along with the scenario. Services of other
+	// actions must then be addressed by their qualified DNS name (<service>.<namespace>). Actions that target the
+	// services of other actions (Chaos, Cascade, Call) are not yet supported in this mode.
+	// +kubebuilder:validation:Enum=Shared;Namespace
+	// +optional
+	Isolation IsolationMode `json:"isolation,omitempty"`
+
+	// Propagation defines the labels and annotations of the scenario that flow into every generated object.
+	// +optional
+	Propagation *PropagationSpec `json:"propagation,omitempty"`
+
+	// TargetNamespaces are additional namespaces (e.g, tenants) where Service and Cluster actions can be placed,
+	// through the targetNamespace of the action. Every target namespace is created as <namespace>-<target>, is
+	// labeled with the scenario, and is removed along with the scenario. Services in a target namespace are
+	// addressed by their qualified DNS name (<service>.<namespace>), which is also what macros resolve to.
+	// Actions that target services (Chaos, Cascade, Call, ...) can address only the services in the namespace
+	// of the scenario.
+	// +optional
+	TargetNamespaces []string `json:"targetNamespaces,omitempty"`
+
+	// Run identifies the run, and the system that submitted it (e.g, CI job, git commit, user).
+	// +optional
+	Run *RunMetadata `json:"run,omitempty"`
+
+	// Datasources are additional datasources (e.g, InfluxDB, Elasticsearch, Tempo) that are provisioned to the
+	// Grafana of the scenario, along with Prometheus. Declaring a datasource deploys the telemetry stack, even if
+	// no telemetry agent is used.
+	// +optional
+	Datasources []GrafanaDatasource `json:"datasources,omitempty"`
+
+	// Ingestion deploys an ingestion service (InfluxDB/Graphite) for tools that push their metrics, and provisions it
+	// to the Grafana of the scenario. Enabling the ingestion deploys the telemetry stack, even if no telemetry agent
+	// is used.
+	// +optional
+	Ingestion *IngestionSpec `json:"ingestion,omitempty"`
+
+	// Tracing deploys a tracing backend (Tempo/Jaeger), injects its OTLP endpoint into the services of the scenario,
+	// and provisions it to the Grafana of the scenario. Enabling the tracing deploys the telemetry stack, even if no
+	// telemetry agent is used.
+	// +optional
+	Tracing *TracingSpec `json:"tracing,omitempty"`
+
+	// Resources bounds the total CPU and memory that are requested by the Pods of the scenario. Actions whose
+	// requests do not fit into the remaining budget are queued until enough resources are released.
+	// +optional
+	Resources *ResourceBudget `json:"resources,omitempty"`
+}
+
+// IsolationMode defines where the resources of the actions are placed.
+type IsolationMode string
+
+const (
+	// IsolationShared places the resources of all actions in the namespace of the scenario.
+	IsolationShared = IsolationMode("Shared")
+
+	// IsolationNamespace places the resources of every Service and Cluster action in a dedicated namespace.
+	IsolationNamespace = IsolationMode("Namespace")
+)
+
+// IsNamespaceIsolated returns true if every action of the scenario is placed in its own namespace.
+func (in *ScenarioSpec) IsNamespaceIsolated() bool {
+	return in.Isolation == IsolationNamespace
+}
+
+// IsMultiNamespace returns true if the jobs of the scenario may be placed outside the namespace of the scenario.
+func (in *ScenarioSpec) IsMultiNamespace() bool {
+	return in.IsNamespaceIsolated() || len(in.TargetNamespaces) > 0
+}
+
+// JobNamespace returns the namespace where the job of the action is placed.
+func (in *Scenario) JobNamespace(action *Action) string {
+	switch {
+	case action.ActionType != ActionService && action.ActionType != ActionCluster:
+		return in.GetNamespace()
+	case in.Spec.IsNamespaceIsolated():
+		return ActionNamespace(in.GetNamespace(), action.Name)
+	case action.TargetNamespace != "":
+		return ActionNamespace(in.GetNamespace(), action.TargetNamespace)
+	default:
+		return in.GetNamespace()
+	}
+}
+
+// ActionNamespace returns the dedicated namespace of an action. Names that exceed the length limit of
+// namespaces are truncated, and suffixed with a hash of the full name.
+func ActionNamespace(namespace string, action string) string {
+	name := namespace + "-" + action
+	if len(name) <= validation.DNS1123LabelMaxLength {
+		return name
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(name))
+
+	suffix := fmt.Sprintf("-%08x", hash.Sum32())
+
+	return strings.TrimRight(name[:validation.DNS1123LabelMaxLength-len(suffix)], "-") + suffix
 }
 
 // ScenarioStatus defines the observed state of Scenario.
@@ -203,6 +507,26 @@ type ScenarioStatus struct {
 	// +optional
 	ScheduledJobs []string `json:"scheduledJobs,omitempty"`
 
+	// Actions is the timeline of the scheduled actions, in the order they were scheduled.
+	// +optional
+	Actions []ActionStatus `json:"actions,omitempty"`
+
+	// PercentComplete is the progress of the scenario, as the average progress of its actions.
+	// +optional
+	PercentComplete int `json:"percentComplete,omitempty"`
+
+	// TeardownJobs is a list of references to the names of executed teardown actions.
+	// +optional
+	TeardownJobs []string `json:"teardownJobs,omitempty"`
+
+	// NextScenario points to the scenario that was submitted once this scenario was completed.
+	// +optional
+	NextScenario string `json:"nextScenario,omitempty"`
+
+	// NetworkLinks is a list of references to the established links of the network profile.
+	// +optional
+	NetworkLinks []string `json:"networkLinks,omitempty"`
+
 	// GrafanaEndpoint points to the local Grafana instance
 	GrafanaEndpoint string `json:"grafanaEndpoint,omitempty"`
 
@@ -211,6 +535,100 @@ type ScenarioStatus struct {
 
 	// Dataviewer points to the local Dataviewer instance
 	DataviewerEndpoint string `json:"dataviewerEndpoint,omitempty"`
+
+	// IngestionEndpoint points to the local ingestion service
+	IngestionEndpoint string `json:"ingestionEndpoint,omitempty"`
+
+	// TracingEndpoint points to the local tracing backend
+	TracingEndpoint string `json:"tracingEndpoint,omitempty"`
+
+	// TelemetryRepairs counts how many times a crashed telemetry component has been recreated.
+	// +optional
+	TelemetryRepairs int `json:"telemetryRepairs,omitempty"`
+
+	// Alerts is the history of the most recent Grafana alerts dispatched to the objects of the scenario.
+	// +optional
+	Alerts []AlertRecord `json:"alerts,omitempty"`
+
+	// PayloadsRef points to the ConfigMap that holds the messages and the evaluated values (of the scenario and
+	// of the actions) that are too large for the status.
+	// +optional
+	PayloadsRef string `json:"payloadsRef,omitempty"`
+
+	// Run is the metadata of the run, with the generated fields filled in.
+	// +optional
+	Run *RunMetadata `json:"run,omitempty"`
+}
+
+// AlertRecord is an entry of the alert history.
+type AlertRecord struct {
+	// Name is the name of the alert rule.
+	Name string `json:"name"`
+
+	// State is the state of the alert, as reported by Grafana (e.g, alerting, ok).
+	State string `json:"state"`
+
+	// Target is the object that the alert refers to, as Kind/Name.
+	// +optional
+	Target string `json:"target,omitempty"`
+
+	// Time is the time the alert was dispatched.
+	Time metav1.Time `json:"time"`
+}
+
+// ActionStatus is the observed state of an action.
+type ActionStatus struct {
+	// Name is the name of the action.
+	Name string `json:"name"`
+
+	// ActionType is the type of the action.
+	ActionType ActionType `json:"action"`
+
+	// Phase is the phase of the job created by the action.
+	// +optional
+	Phase Phase `json:"phase,omitempty"`
+
+	// StartTime is the time the action was scheduled.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// EndTime is the time the action was found completed, either successfully or not.
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+
+	// JobsCreated is the number of jobs created by the action (e.g, the services of a cluster).
+	// +optional
+	JobsCreated int `json:"jobsCreated,omitempty"`
+
+	// PercentComplete is the percentage of the jobs of the action that are completed (e.g, the chunks of a seed).
+	// +optional
+	PercentComplete int `json:"percentComplete,omitempty"`
+
+	// Reason is the reason for the failure of the action, if any.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human-readable explanation of the failure, if any.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// AssertionFailure captures the evaluated values at the time the assertion of the action was violated.
+	// +optional
+	AssertionFailure *AssertionFailure `json:"assertionFailure,omitempty"`
+}
+
+// AssertionFailure is the context of a violated assertion.
+type AssertionFailure struct {
+	// Expression is the violated expression.
+	Expression string `json:"expression"`
+
+	// Values are the values observed during the evaluation of the expression. For state expressions, they are
+	// the jobs per phase. For metrics expressions, they are the metric values and the threshold of the evaluator.
+	// +optional
+	Values map[string]string `json:"values,omitempty"`
+
+	// Time is the time the violation was detected.
+	Time metav1.Time `json:"time"`
 }
 
 func (in *ScenarioStatus) Table() (header []string, data [][]string) {
@@ -256,6 +674,41 @@ func (in *Scenario) SetReconcileStatus(lifecycle Lifecycle) {
 	in.Status.Lifecycle = lifecycle
 }
 
+func (in *Scenario) OffloadPayloads(threshold int) map[string]string {
+	payloads := make(map[string]string)
+
+	offload := func(value *string, parts ...string) {
+		if len(*value) > threshold {
+			key := PayloadKey(parts...)
+
+			payloads[key] = *value
+			*value = OffloadedPayload(key, len(payloads[key]))
+		}
+	}
+
+	offload(&in.Status.Message, "message")
+
+	for i := range in.Status.Actions {
+		action := &in.Status.Actions[i]
+
+		offload(&action.Message, action.Name, "message")
+
+		if failure := action.AssertionFailure; failure != nil {
+			for name, value := range failure.Values {
+				offload(&value, action.Name, "values", name)
+
+				failure.Values[name] = value
+			}
+		}
+	}
+
+	return payloads
+}
+
+func (in *Scenario) SetPayloadsRef(name string) {
+	in.Status.PayloadsRef = name
+}
+
 // +kubebuilder:object:root=true
 
 // ScenarioList contains a list of Scenario.
@@ -282,6 +735,7 @@ func (in *ScenarioList) Table() (header []string, data [][]string) {
 		"Scenario",
 		"Age",
 		"Actions",
+		"Progress",
 		"Phase",
 		"Duration",
 	}
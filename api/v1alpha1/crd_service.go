@@ -42,6 +42,30 @@ type SetField struct {
 	Value string `json:"value"`
 }
 
+// MeshProvider is a service mesh that injects its proxies into the Pods.
+type MeshProvider string
+
+const (
+	MeshIstio   = MeshProvider("Istio")
+	MeshLinkerd = MeshProvider("Linkerd")
+)
+
+// PodSecurityProfile is the hardening applied to the securityContexts of the generated Pods.
+type PodSecurityProfile string
+
+const (
+	// PodSecurityRestricted makes the Pod compliant with the restricted Pod Security Standard.
+	PodSecurityRestricted = PodSecurityProfile("Restricted")
+
+	// PodSecurityPrivileged leaves the securityContexts as defined in the templates.
+	PodSecurityPrivileged = PodSecurityProfile("Privileged")
+)
+
+// AnnotationRestart requests the restart of a Service. The value is the UID of the Pod to be replaced.
+// The replacement runs on the same node, pulls the images of the Service again, and the annotation is removed
+// once the replacement is created.
+const AnnotationRestart = "service.frisbee.dev/restart"
+
 // Decorators takes-in a PodSpec, add some functionality and returns it.
 type Decorators struct {
 	// +optional
@@ -62,6 +86,20 @@ type Decorators struct {
 	// IngressPort builds an ingress for making the service's port accessible outside the Kubernetes cluster.
 	// +optional
 	IngressPort *netv1.ServiceBackendPort `json:"ingressPort,omitempty"`
+
+	// Mesh enables the injection of the mesh proxy into the Service. The mesh must be already installed on
+	// the Kubernetes cluster, and its retry and timeout policies apply to the traffic of the Service.
+	// Notice that terminating Services must stop the proxy upon completion, otherwise the Pod never completes.
+	// +kubebuilder:validation:Enum=Istio;Linkerd
+	// +optional
+	Mesh MeshProvider `json:"mesh,omitempty"`
+
+	// PodSecurity overrides the pod security profile of the operator. Services that require privileges
+	// (e.g, access to the host) must opt out of the hardening with Privileged. Telemetry agents that opt out,
+	// opt out the Services they are attached to.
+	// +kubebuilder:validation:Enum=Restricted;Privileged
+	// +optional
+	PodSecurity PodSecurityProfile `json:"podSecurity,omitempty"`
 }
 
 // Callable is a script that is executed within the service container, and returns a value.
@@ -74,6 +112,18 @@ type Callable struct {
 	Command []string `json:"command"`
 }
 
+// ContainerHooks are commands that are executed within a container at certain points of its lifecycle.
+type ContainerHooks struct {
+	// PostStart is executed immediately after the container is created (e.g, to setup the cgroup for telemetry).
+	// The container is not marked as running until the command completes.
+	// +optional
+	PostStart []string `json:"postStart,omitempty"`
+
+	// PreStop is executed immediately before the container is terminated (e.g, to flush data).
+	// +optional
+	PreStop []string `json:"preStop,omitempty"`
+}
+
 // ServiceSpec defines the desired state of Service.
 type ServiceSpec struct {
 	// +optional
@@ -82,6 +132,16 @@ type ServiceSpec struct {
 	// +optional
 	Callables map[string]Callable `json:"callables,omitempty"`
 
+	// Hooks are translated to lifecycle handlers of the containers. They are indexed by the container name.
+	// Hooks defined in telemetry templates are applied to the respective sidecar containers.
+	// +optional
+	Hooks map[string]ContainerHooks `json:"hooks,omitempty"`
+
+	// Lifetime terminates the Service once the given duration has passed since it was scheduled.
+	// A Service whose lifetime has expired is considered successful.
+	// +optional
+	Lifetime *metav1.Duration `json:"lifetime,omitempty"`
+
 	corev1.PodSpec `json:",inline"`
 }
 
@@ -91,6 +151,14 @@ type ServiceStatus struct {
 
 	// LastScheduleTime provide information about  the last time a Pod was scheduled.
 	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// NodeName is the node on which the Pod is scheduled.
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	// Zone is the failure domain of the node, as given by the topology.kubernetes.io/zone label.
+	// +optional
+	Zone string `json:"zone,omitempty"`
 }
 
 func (in *Service) GetReconcileStatus() Lifecycle {
@@ -19,11 +19,14 @@ package v1alpha1
 import (
 	"reflect"
 
+	jsonpatch "github.com/evanphx/json-patch/v5"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/yaml"
 )
 
 // +kubebuilder:object:root=true
@@ -75,10 +78,26 @@ type TemplateInputs struct {
 	// Scenario returns the scenario from which the template is called from.
 	// +optional
 	Scenario string `json:"scenario,omitempty"`
+
+	// RunID returns the id of the run from which the template is called from.
+	// +optional
+	RunID string `json:"runID,omitempty"`
+
+	// Vars are the default values for the variables of the scenario from which the template is called from.
+	// A template must declare the variables it uses. At runtime, the defaults are overridden by the
+	// scenario's vars.
+	// +optional
+	Vars map[string]string `json:"vars,omitempty"`
 }
 
 // TemplateSpec defines the desired state of Template.
 type TemplateSpec struct {
+	// Extends refers to a template (in the same namespace) whose spec is inherited by this template.
+	// Fields set in this template override the inherited ones. Embedded specs are merged using
+	// strategic merge (e.g, containers are merged by name), and parameters are merged by key.
+	// +optional
+	Extends string `json:"extends,omitempty"`
+
 	// Inputs are dynamic fields that populate the spec.
 	// +optional
 	Inputs *TemplateInputs `json:"inputs,omitempty"`
@@ -93,6 +112,9 @@ type EmbedSpecs struct {
 
 	// +optional
 	Chaos *ChaosSpec `json:"chaos,omitempty"`
+
+	// +optional
+	Scenario *ScenarioSpec `json:"scenario,omitempty"`
 }
 
 // TemplateStatus defines the observed state of Template.
@@ -163,12 +185,92 @@ type GenerateObjectFromTemplate struct {
 	// then inputs are recursively iteration.
 	// +optional
 	Inputs []UserInputs `json:"inputs,omitempty"`
+
+	// Patches are applied, in order, to the generated spec after the template is evaluated.
+	// They tweak fields that are not covered by the template inputs, without forking the template.
+	// +optional
+	Patches []SpecPatch `json:"patches,omitempty"`
+}
+
+// PatchType is the format of a SpecPatch.
+// +kubebuilder:validation:Enum=json;strategic
+type PatchType string
+
+const (
+	// PatchTypeJSON is a list of JSON6902 operations (e.g, [{"op": "replace", "path": "/containers/0/image", ...}]).
+	PatchTypeJSON = PatchType("json")
+
+	// PatchTypeStrategic is a partial spec merged using strategic merge (e.g, containers are merged by name).
+	PatchTypeStrategic = PatchType("strategic")
+)
+
+type SpecPatch struct {
+	// Type is the format of the patch.
+	Type PatchType `json:"type"`
+
+	// Patch is the body of the patch, in JSON or YAML.
+	Patch string `json:"patch"`
+}
+
+// Apply patches the original JSON document. The dataStruct is the type of the document, and is used
+// for looking up the merge keys of strategic patches.
+func (in SpecPatch) Apply(original []byte, dataStruct interface{}) ([]byte, error) {
+	patch, err := yaml.YAMLToJSON([]byte(in.Patch))
+	if err != nil {
+		return nil, errors.Wrapf(err, "malformed patch")
+	}
+
+	switch in.Type {
+	case PatchTypeJSON:
+		decoded, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, errors.Wrapf(err, "malformed json patch")
+		}
+
+		return decoded.Apply(original)
+
+	case PatchTypeStrategic:
+		return strategicpatch.StrategicMergePatch(original, patch, dataStruct)
+
+	default:
+		return nil, errors.Errorf("unknown patch type '%s'", in.Type)
+	}
+}
+
+func (in SpecPatch) validate() error {
+	patch, err := yaml.YAMLToJSON([]byte(in.Patch))
+	if err != nil {
+		return errors.Wrapf(err, "malformed patch")
+	}
+
+	switch in.Type {
+	case PatchTypeJSON:
+		if _, err := jsonpatch.DecodePatch(patch); err != nil {
+			return errors.Wrapf(err, "malformed json patch")
+		}
+
+		return nil
+
+	case PatchTypeStrategic:
+		var partial map[string]interface{}
+
+		return json.Unmarshal(patch, &partial)
+
+	default:
+		return errors.Errorf("unknown patch type '%s'", in.Type)
+	}
 }
 
 // Prepare automatically fills missing values from the template, according to the following rules:
 // * Without inputs and without instances, there is 1 instance with default values.
 // * Without instances, the number of instances is inferred by the number of inputs.
 func (in *GenerateObjectFromTemplate) Prepare(allowMultipleInputs bool) error {
+	for i, patch := range in.Patches {
+		if err := patch.validate(); err != nil {
+			return errors.Wrapf(err, "invalid patch '%d'", i)
+		}
+	}
+
 	switch {
 	case in.TemplateRef == "":
 		return errors.New("empty templateRef")
@@ -221,6 +323,21 @@ func (in *GenerateObjectFromTemplate) GetInputs(inputIndex uint) UserInputs {
 	}
 }
 
+// Select returns a copy of the template that generates a single object, using the given input set.
+func (in *GenerateObjectFromTemplate) Select(inputSet int) GenerateObjectFromTemplate {
+	selected := GenerateObjectFromTemplate{
+		TemplateRef:  in.TemplateRef,
+		MaxInstances: 1,
+		Patches:      in.Patches,
+	}
+
+	if len(in.Inputs) > 0 {
+		selected.Inputs = []UserInputs{in.Inputs[inputSet%len(in.Inputs)]}
+	}
+
+	return selected
+}
+
 func (in *GenerateObjectFromTemplate) IterateInputs(callBack func(nextInputSet uint) error) error {
 	if len(in.Inputs) == 0 {
 		for i := 0; i < in.MaxInstances; i++ {
@@ -246,12 +363,14 @@ func (in *GenerateObjectFromTemplate) Generate(spec interface{}, userInputsSet u
 			Parameters map[string]interface{} `json:"parameters"`
 			Namespace  string                 `json:"namespace"`
 			Scenario   string                 `json:"scenario"`
+			Vars       map[string]string      `json:"vars"`
 		} `json:"inputs"`
 	}{}
 
 	// Step 1. Expose Scope Information
 	evaluationParams.Inputs.Namespace = tSpec.Inputs.Namespace
 	evaluationParams.Inputs.Scenario = tSpec.Inputs.Scenario
+	evaluationParams.Inputs.Vars = tSpec.Inputs.Vars
 
 	// Step 2: Initialize using the default templat evalues
 	templateParams, err := tSpec.Inputs.Parameters.Unmarshal()
@@ -287,11 +406,21 @@ func (in *GenerateObjectFromTemplate) Generate(spec interface{}, userInputsSet u
 		}
 	}
 
-	// Step 4: Evaluate the template and decode it to the caller's type.
+	// Step 4: Evaluate the template.
 	expandedTemplateBody, err := ExprState(templateBody).Evaluate(evaluationParams)
 	if err != nil {
 		return errors.Wrapf(err, "template execution error")
 	}
 
-	return json.Unmarshal([]byte(expandedTemplateBody), spec)
+	// Step 5: Apply the user patches and decode it to the caller's type.
+	patchedBody := []byte(expandedTemplateBody)
+
+	for i, patch := range in.Patches {
+		patchedBody, err = patch.Apply(patchedBody, spec)
+		if err != nil {
+			return errors.Wrapf(err, "cannot apply patch '%d'", i)
+		}
+	}
+
+	return json.Unmarshal(patchedBody, spec)
 }
@@ -61,6 +61,10 @@ type VirtualObjectStatus struct {
 	// the BinaryData field, this is enforced during validation process.
 	// +optional
 	Data map[string]string `json:"data,omitempty"`
+
+	// PayloadsRef points to the ConfigMap that holds the values of Data that are too large for the status.
+	// +optional
+	PayloadsRef string `json:"payloadsRef,omitempty"`
 }
 
 func (in *VirtualObjectStatus) Table() (header []string, data [][]string) {
@@ -88,6 +92,25 @@ func (in *VirtualObject) SetReconcileStatus(lifecycle Lifecycle) {
 	in.Status.Lifecycle = lifecycle
 }
 
+func (in *VirtualObject) OffloadPayloads(threshold int) map[string]string {
+	payloads := make(map[string]string)
+
+	for key, value := range in.Status.Data {
+		if len(value) > threshold {
+			payloadKey := PayloadKey(key)
+
+			payloads[payloadKey] = value
+			in.Status.Data[key] = OffloadedPayload(payloadKey, len(value))
+		}
+	}
+
+	return payloads
+}
+
+func (in *VirtualObject) SetPayloadsRef(name string) {
+	in.Status.PayloadsRef = name
+}
+
 // +kubebuilder:object:root=true
 
 // VirtualObjectList contains a list of Virtual Objects.
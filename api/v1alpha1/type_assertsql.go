@@ -0,0 +1,89 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// AssertSQLSpec verifies the data of a database (e.g, after a failure has been injected), by comparing the scalar
+// result of a SQL query against a threshold. The query runs from a helper pod, and the action fails if the
+// comparison does not hold.
+type AssertSQLSpec struct {
+	DatabaseConnection `json:",inline"`
+
+	// Query must return a single scalar value, e.g, "SELECT count(*) FROM orders".
+	Query string `json:"query"`
+
+	// Operator compares the result of the query (left operand) against the threshold (right operand).
+	// +kubebuilder:validation:Enum=eq;ne;lt;le;gt;ge
+	Operator SQLOperator `json:"operator"`
+
+	// Threshold is the value that the result of the query is compared against.
+	Threshold float64 `json:"threshold"`
+}
+
+// SQLOperator is the comparison between the result of a query and a threshold.
+type SQLOperator string
+
+const (
+	SQLEqual          SQLOperator = "eq"
+	SQLNotEqual       SQLOperator = "ne"
+	SQLLessThan       SQLOperator = "lt"
+	SQLLessOrEqual    SQLOperator = "le"
+	SQLGreaterThan    SQLOperator = "gt"
+	SQLGreaterOrEqual SQLOperator = "ge"
+)
+
+// sqlOperatorSymbols maps the operators to their symbols.
+var sqlOperatorSymbols = map[SQLOperator]string{
+	SQLEqual:          "==",
+	SQLNotEqual:       "!=",
+	SQLLessThan:       "<",
+	SQLLessOrEqual:    "<=",
+	SQLGreaterThan:    ">",
+	SQLGreaterOrEqual: ">=",
+}
+
+// Symbol returns the symbol of the operator (e.g, "<=" for le), or an empty string for unknown operators.
+func (op SQLOperator) Symbol() string {
+	return sqlOperatorSymbols[op]
+}
+
+// CheckAssertSQL validates the SQL assertion of an action.
+func CheckAssertSQL(assert *AssertSQLSpec) error {
+	if err := CheckDatabaseConnection(&assert.DatabaseConnection); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(assert.Query) == "" {
+		return errors.New("empty query")
+	}
+
+	if assert.Operator.Symbol() == "" {
+		return errors.Errorf("unknown operator '%s'", assert.Operator)
+	}
+
+	if math.IsNaN(assert.Threshold) || math.IsInf(assert.Threshold, 0) {
+		return errors.Errorf("invalid threshold '%v'", assert.Threshold)
+	}
+
+	return nil
+}
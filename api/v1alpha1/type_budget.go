@@ -0,0 +1,110 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// DefaultBudgetRequests are assigned to the containers that request no resources, so that they are accounted in the
+// budget of the scenario.
+var DefaultBudgetRequests = corev1.ResourceList{
+	corev1.ResourceCPU:    resource.MustParse("100m"),
+	corev1.ResourceMemory: resource.MustParse("128Mi"),
+}
+
+// ResourceBudget bounds the total resources that are requested by the Pods of a scenario, including the telemetry
+// stack. The budget is enforced by a ResourceQuota in the namespace of the scenario.
+type ResourceBudget struct {
+	// CPU is the total CPU that can be requested by the Pods of the scenario (e.g, 16).
+	// +optional
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+
+	// Memory is the total memory that can be requested by the Pods of the scenario (e.g, 64Gi).
+	// +optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+
+	// DefaultRequests are assigned to the containers that do not request resources.
+	// Defaults to 100m CPU and 128Mi memory.
+	// +optional
+	DefaultRequests corev1.ResourceList `json:"defaultRequests,omitempty"`
+}
+
+// Limits returns the budget as a list of resources. Resources that are not bounded are omitted.
+func (in *ResourceBudget) Limits() corev1.ResourceList {
+	limits := corev1.ResourceList{}
+
+	if in.CPU != nil {
+		limits[corev1.ResourceCPU] = *in.CPU
+	}
+
+	if in.Memory != nil {
+		limits[corev1.ResourceMemory] = *in.Memory
+	}
+
+	return limits
+}
+
+// GetDefaultRequests returns the requests of the containers that do not request resources.
+func (in *ResourceBudget) GetDefaultRequests() corev1.ResourceList {
+	defaults := DefaultBudgetRequests.DeepCopy()
+
+	for name, quantity := range in.DefaultRequests {
+		defaults[name] = quantity
+	}
+
+	return defaults
+}
+
+// CheckResourceBudget validates the resource budget of the scenario.
+func CheckResourceBudget(scenario *Scenario) error {
+	budget := scenario.Spec.Resources
+	if budget == nil {
+		return nil
+	}
+
+	if budget.CPU == nil && budget.Memory == nil {
+		return errors.Errorf("at least one of cpu or memory is required")
+	}
+
+	for name, quantity := range budget.Limits() {
+		if quantity.Sign() <= 0 {
+			return errors.Errorf("%s must be positive", name)
+		}
+	}
+
+	for name, quantity := range budget.DefaultRequests {
+		switch name {
+		case corev1.ResourceCPU, corev1.ResourceMemory:
+		default:
+			return errors.Errorf("default requests of '%s' are not supported", name)
+		}
+
+		if quantity.Sign() < 0 {
+			return errors.Errorf("default requests of '%s' cannot be negative", name)
+		}
+	}
+
+	// the quota is bound to a namespace, and it cannot account the jobs in other namespaces.
+	if scenario.Spec.IsMultiNamespace() {
+		return errors.Errorf("the budget cannot be combined with isolation or targetNamespaces")
+	}
+
+	return nil
+}
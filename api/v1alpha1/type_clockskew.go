@@ -0,0 +1,123 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClockSkewSpec shifts the clocks of the selected services, in order to test the protocols that depend on time
+// (e.g, leases, consensus). It runs as a Cascade of TimeChaos faults, one for every step of the profile, and the
+// skew windows are annotated in Grafana.
+type ClockSkewSpec struct {
+	// Services are the services whose clocks are skewed. Macros are supported (e.g, ".cluster.servers.all").
+	// +kubebuilder:validation:MinItems=1
+	Services []string `json:"services"`
+
+	// Offset is the initial skew of the clocks. Negative offsets set the clocks in the past.
+	Offset metav1.Duration `json:"offset"`
+
+	// DriftRate is the skew that is added for every second of real time (e.g, 10ms). The drift is approximated
+	// by an offset that increases at every step.
+	// +optional
+	DriftRate *metav1.Duration `json:"driftRate,omitempty"`
+
+	// Duration is how long the clocks remain skewed.
+	Duration metav1.Duration `json:"duration"`
+
+	// Step is the duration of every step of the drift. Defaults to DefaultClockSkewStep.
+	// +optional
+	Step *metav1.Duration `json:"step,omitempty"`
+}
+
+const (
+	// DefaultClockSkewStep is the duration of every step of the drift, if not set by the user.
+	DefaultClockSkewStep = 10 * time.Second
+
+	// MaxClockSkewSteps bounds the number of faults that a clock skew is translated into.
+	MaxClockSkewSteps = 1000
+)
+
+// ClockSkewStep is the part of the clock skew that is injected by a single fault.
+type ClockSkewStep struct {
+	// Offset is the skew of the clocks during the step.
+	Offset time.Duration
+
+	// Duration is how long the step lasts.
+	Duration time.Duration
+}
+
+// GetStep returns the duration of every step of the drift.
+func (in *ClockSkewSpec) GetStep() time.Duration {
+	if in.Step == nil || in.Step.Duration <= 0 {
+		return DefaultClockSkewStep
+	}
+
+	return in.Step.Duration
+}
+
+// Steps splits the clock skew into faults of constant offset. Without drift, the skew is a single step.
+func (in *ClockSkewSpec) Steps() []ClockSkewStep {
+	total := in.Duration.Duration
+
+	if in.DriftRate == nil || in.DriftRate.Duration == 0 {
+		return []ClockSkewStep{{Offset: in.Offset.Duration, Duration: total}}
+	}
+
+	step := in.GetStep()
+	steps := make([]ClockSkewStep, 0, (total+step-1)/step)
+
+	for elapsed := time.Duration(0); elapsed < total; elapsed += step {
+		drift := time.Duration(float64(in.DriftRate.Duration) * elapsed.Seconds())
+
+		duration := step
+		if remaining := total - elapsed; remaining < step {
+			duration = remaining
+		}
+
+		steps = append(steps, ClockSkewStep{Offset: in.Offset.Duration + drift, Duration: duration})
+	}
+
+	return steps
+}
+
+// CheckClockSkew validates the clock skew of an action.
+func CheckClockSkew(skew *ClockSkewSpec) error {
+	if len(skew.Services) == 0 {
+		return errors.New("no services to skew")
+	}
+
+	if skew.Duration.Duration <= 0 {
+		return errors.Errorf("invalid duration '%s'", skew.Duration.Duration)
+	}
+
+	if skew.Step != nil && skew.Step.Duration < 0 {
+		return errors.Errorf("invalid step '%s'", skew.Step.Duration)
+	}
+
+	if skew.DriftRate != nil && skew.DriftRate.Duration != 0 {
+		if steps := skew.Duration.Duration / skew.GetStep(); steps > MaxClockSkewSteps {
+			return errors.Errorf("the drift yields '%d' steps, more than the maximum of '%d'. Use a larger step",
+				steps, MaxClockSkewSteps)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ConsistencySpec validates the operation histories that are recorded by the clients of the system under test,
+// in the style of Jepsen. It runs as a final job, and the action fails if the checker finds an anomaly.
+//
+// The clients record their histories on the TestData volume, under the Histories directory, as files with the
+// ".history" extension (e.g, /testdata/histories/client-1.history). Every line is an operation, given as the
+// invocation or completion time in nanoseconds, followed by a tab, followed by the operation in JSON, e.g,
+//
+//	1690000000000000000	{"process": 1, "type": "invoke", "f": "append", "value": [["append", 3, 1]]}
+//	1690000000000500000	{"process": 1, "type": "ok", "f": "append", "value": [["append", 3, 1]]}
+//
+// Every file must be in ascending time order. The checker merges the files into a single history, which is written
+// next to the verdict of the checker, in the Histories directory at the root of the TestData volume.
+type ConsistencySpec struct {
+	// Histories is the directory, relative to the TestData volume of the clients, where the clients record their
+	// histories. Defaults to DefaultConsistencyHistories.
+	// +optional
+	Histories string `json:"histories,omitempty"`
+
+	// Model is the consistency model (or the workload) that the histories are checked against, e.g,
+	// "list-append" or "rw-register" for Elle.
+	Model string `json:"model"`
+
+	// TemplateRef replaces the built-in checker (Elle) with a custom one, e.g, a Porcupine binary.
+	// The template receives the inputs "histories" (the directory of the histories) and "model". It must exit with
+	// a non-zero code if the histories are not consistent.
+	// +optional
+	TemplateRef string `json:"templateRef,omitempty"`
+}
+
+// DefaultConsistencyHistories is the directory of the histories, if not set by the user.
+const DefaultConsistencyHistories = "histories"
+
+// GetHistories returns the directory of the histories.
+func (in *ConsistencySpec) GetHistories() string {
+	if in.Histories == "" {
+		return DefaultConsistencyHistories
+	}
+
+	return in.Histories
+}
+
+// consistencyModel and consistencyHistories restrict the inputs of the checker, as they are inlined into its command.
+var (
+	consistencyModel     = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+	consistencyHistories = regexp.MustCompile(`^[a-zA-Z0-9._/-]+$`)
+)
+
+// CheckConsistency validates the consistency check of an action.
+func CheckConsistency(consistency *ConsistencySpec) error {
+	histories := consistency.GetHistories()
+
+	if filepath.IsAbs(histories) || strings.Contains(histories, "..") {
+		return errors.Errorf("histories '%s' must be relative to the testData volume", histories)
+	}
+
+	if !consistencyHistories.MatchString(histories) {
+		return errors.Errorf("invalid histories '%s'", histories)
+	}
+
+	if !consistencyModel.MatchString(consistency.Model) {
+		return errors.Errorf("invalid model '%s'", consistency.Model)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ControlPlaneComponent is a component of the Kubernetes control plane, as given by the 'component' label of its
+// pods in the kube-system namespace.
+type ControlPlaneComponent string
+
+const (
+	// ControlPlaneAPIServer is the API server.
+	ControlPlaneAPIServer ControlPlaneComponent = "kube-apiserver"
+
+	// ControlPlaneControllerManager runs the built-in controllers (e.g, Deployments, Nodes).
+	ControlPlaneControllerManager ControlPlaneComponent = "kube-controller-manager"
+
+	// ControlPlaneScheduler assigns the pending pods to nodes.
+	ControlPlaneScheduler ControlPlaneComponent = "kube-scheduler"
+)
+
+// ControlPlaneFaultSpec degrades the control plane of the cluster, in order to test how operators and controllers
+// behave when the API server is slow, or when the built-in controllers stop reconciling.
+//
+// The fault affects the whole cluster, including the other tenants and Frisbee itself. Therefore, it is rejected
+// unless the operator is installed with control-plane faults explicitly allowed (operator.controlPlaneFaults.allowed).
+// The control plane must run as pods in the kube-system namespace, which is not the case for managed clusters.
+type ControlPlaneFaultSpec struct {
+	// Duration is how long the control plane remains degraded.
+	Duration metav1.Duration `json:"duration"`
+
+	// APIServerLatency delays the traffic of the API servers.
+	// +optional
+	APIServerLatency *APIServerLatencySpec `json:"apiServerLatency,omitempty"`
+
+	// Pause freezes the processes of the given components on every control-plane node, and resumes them
+	// once the duration has passed.
+	// +optional
+	Pause *ControlPlanePauseSpec `json:"pause,omitempty"`
+}
+
+// APIServerLatencySpec delays the traffic of the API servers. The API servers usually share the network of their
+// nodes (hostNetwork), in which case the latency applies to all the traffic of the control-plane nodes.
+type APIServerLatencySpec struct {
+	// Latency is the delay added to every packet.
+	Latency metav1.Duration `json:"latency"`
+
+	// Jitter is the variation of the latency.
+	// +optional
+	Jitter metav1.Duration `json:"jitter,omitempty"`
+}
+
+// ControlPlanePauseSpec freezes (SIGSTOP) the processes of control-plane components. Paused components keep their
+// connections and leases open, but do not make any progress, which is harder to detect than a crash.
+type ControlPlanePauseSpec struct {
+	// Components are the components to pause. The API server cannot be paused, as Frisbee depends on it for
+	// resuming the components.
+	// +kubebuilder:validation:MinItems=1
+	Components []ControlPlaneComponent `json:"components"`
+}
+
+// CheckControlPlaneFault validates the control-plane fault of an action.
+func CheckControlPlaneFault(fault *ControlPlaneFaultSpec) error {
+	if fault.Duration.Duration <= 0 {
+		return errors.Errorf("invalid duration '%s'", fault.Duration.Duration)
+	}
+
+	switch {
+	case fault.APIServerLatency != nil && fault.Pause != nil:
+		return errors.New("apiServerLatency and pause are mutually exclusive")
+
+	case fault.APIServerLatency != nil:
+		if fault.APIServerLatency.Latency.Duration <= 0 {
+			return errors.Errorf("invalid latency '%s'", fault.APIServerLatency.Latency.Duration)
+		}
+
+		if fault.APIServerLatency.Jitter.Duration < 0 {
+			return errors.Errorf("invalid jitter '%s'", fault.APIServerLatency.Jitter.Duration)
+		}
+
+		return nil
+
+	case fault.Pause != nil:
+		if len(fault.Pause.Components) == 0 {
+			return errors.New("no components to pause")
+		}
+
+		for _, component := range fault.Pause.Components {
+			switch component {
+			case ControlPlaneControllerManager, ControlPlaneScheduler:
+			case ControlPlaneAPIServer:
+				return errors.Errorf("component '%s' cannot be paused. Use apiServerLatency instead", component)
+			default:
+				return errors.Errorf("unknown component '%s'", component)
+			}
+		}
+
+		return nil
+
+	default:
+		return errors.New("one of apiServerLatency or pause must be given")
+	}
+}
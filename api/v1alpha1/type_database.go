@@ -0,0 +1,79 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"text/template"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DatabaseDriver is the client that connects to the database.
+type DatabaseDriver string
+
+const (
+	// DriverPostgres connects to PostgreSQL, or compatible databases (e.g, CockroachDB, YugabyteDB).
+	DriverPostgres DatabaseDriver = "postgres"
+
+	// DriverMySQL connects to MySQL, or compatible databases (e.g, MariaDB, TiDB).
+	DriverMySQL DatabaseDriver = "mysql"
+)
+
+// DatabaseConnection points to a database of the system under test.
+type DatabaseConnection struct {
+	// Driver is the client that connects to the database.
+	// +kubebuilder:validation:Enum=postgres;mysql
+	Driver DatabaseDriver `json:"driver"`
+
+	// Connection is the connection string (URI) of the database. It is a template that is evaluated against the
+	// scenario, e.g, "postgres://bench:$(DB_PASSWORD)@{{.vars.db}}:5432/bench". The password, if any,
+	// is given by PasswordRef and is referred as $(DB_PASSWORD).
+	Connection string `json:"connection"`
+
+	// PasswordRef selects a key of a Secret, in the namespace of the scenario, that holds the password.
+	// +optional
+	PasswordRef *corev1.SecretKeySelector `json:"passwordRef,omitempty"`
+}
+
+// RenderConnection evaluates the connection string against the scenario.
+func (in *DatabaseConnection) RenderConnection(scenario *Scenario) (string, error) {
+	return renderTemplate("connection", in.Connection, scenarioTemplateData(scenario))
+}
+
+// CheckDatabaseConnection validates the connection to a database.
+func CheckDatabaseConnection(conn *DatabaseConnection) error {
+	switch conn.Driver {
+	case DriverPostgres, DriverMySQL:
+	default:
+		return errors.Errorf("unknown driver '%s'", conn.Driver)
+	}
+
+	if conn.Connection == "" {
+		return errors.New("empty connection")
+	}
+
+	if _, err := template.New("connection").Funcs(sprigFuncMap).Parse(conn.Connection); err != nil {
+		return errors.Wrapf(err, "invalid connection template")
+	}
+
+	if ref := conn.PasswordRef; ref != nil && (ref.Name == "" || ref.Key == "") {
+		return errors.New("incomplete password reference")
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// DefaultDatasourceName is the name of the Prometheus datasource that is provisioned to every Grafana.
+const DefaultDatasourceName = "Prometheus"
+
+// GrafanaDatasource is an additional datasource that is provisioned to the Grafana of the scenario,
+// for visualizing telemetry that is not stored in Prometheus.
+type GrafanaDatasource struct {
+	// Name is the name of the datasource, as referred by the dashboards.
+	Name string `json:"name"`
+
+	// Type is the type of the datasource (e.g, influxdb, elasticsearch, tempo).
+	Type string `json:"type"`
+
+	// UID is the unique identifier of the datasource, as referred by the dashboards.
+	// If empty, it is generated by Grafana.
+	// +kubebuilder:validation:MaxLength=40
+	// +optional
+	UID string `json:"uid,omitempty"`
+
+	// URL points to the datasource. Services of the scenario are reachable by their name.
+	URL string `json:"url"`
+
+	// Database is the name of the database, or of the index, for the datasources that need one.
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// JSONData holds the type-specific settings of the datasource (e.g, version, timeField, httpMode).
+	// +optional
+	JSONData map[string]*apiextensionsv1.JSON `json:"jsonData,omitempty"`
+
+	// SecureJSONData holds the type-specific secrets of the datasource (e.g, token, password), as references
+	// to keys of Secrets in the namespace of the scenario. The secrets are passed to Grafana as environment
+	// variables, and are never copied into the configuration.
+	// +optional
+	SecureJSONData map[string]corev1.SecretKeySelector `json:"secureJsonData,omitempty"`
+}
+
+// CheckDatasources validates the additional Grafana datasources of the scenario.
+func CheckDatasources(scenario *Scenario) error {
+	names := make(map[string]struct{}, len(scenario.Spec.Datasources))
+	uids := make(map[string]struct{}, len(scenario.Spec.Datasources))
+
+	for _, ds := range scenario.Spec.Datasources {
+		switch {
+		case ds.Name == "":
+			return errors.New("datasource without name")
+		case ds.Name == DefaultDatasourceName:
+			return errors.Errorf("datasource '%s' is reserved", ds.Name)
+		case ds.Type == "":
+			return errors.Errorf("datasource '%s' has no type", ds.Name)
+		case ds.URL == "":
+			return errors.Errorf("datasource '%s' has no url", ds.Name)
+		}
+
+		if _, exists := names[ds.Name]; exists {
+			return errors.Errorf("duplicate datasource '%s'", ds.Name)
+		}
+
+		names[ds.Name] = struct{}{}
+
+		if ds.UID != "" {
+			if _, exists := uids[ds.UID]; exists {
+				return errors.Errorf("duplicate uid '%s' for datasource '%s'", ds.UID, ds.Name)
+			}
+
+			uids[ds.UID] = struct{}{}
+		}
+
+		for key, ref := range ds.SecureJSONData {
+			if ref.Name == "" || ref.Key == "" {
+				return errors.Errorf("datasource '%s' has incomplete secret reference for '%s'", ds.Name, key)
+			}
+		}
+	}
+
+	return nil
+}
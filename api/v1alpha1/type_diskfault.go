@@ -0,0 +1,231 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DiskFaultSpec disrupts the storage of the selected services, without writing the raw Chaos Mesh manifests.
+// Latency and Errno are injected as IOChaos, whereas Fill runs a helper pod that fills the volume with garbage,
+// and frees it once the duration has passed. Exactly one of the faults must be set.
+type DiskFaultSpec struct {
+	// Selector selects the services whose storage is disrupted. The services are selected when the action runs.
+	Selector ServiceSelector `json:"selector"`
+
+	// Duration is how long the fault lasts.
+	Duration metav1.Duration `json:"duration"`
+
+	// Latency delays the I/O operations on a volume.
+	// +optional
+	Latency *IOLatencySpec `json:"latency,omitempty"`
+
+	// Errno fails the I/O operations on a volume with the given error.
+	// +optional
+	Errno *IOErrnoSpec `json:"errno,omitempty"`
+
+	// Fill fills a volume up to the given percentage of its capacity.
+	// +optional
+	Fill *DiskFillSpec `json:"fill,omitempty"`
+}
+
+// IOTarget is the part of a volume whose I/O operations are disrupted.
+type IOTarget struct {
+	// VolumePath is the mount path of the volume in the containers of the services (e.g, /var/lib/mysql).
+	VolumePath string `json:"volumePath"`
+
+	// Path is a glob of the files, within the volume, whose operations are disrupted. Defaults to all the files.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Percent is the probability that an operation is disrupted. Defaults to 100.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Percent int `json:"percent,omitempty"`
+}
+
+// GetPath returns the glob of the disrupted files.
+func (in *IOTarget) GetPath() string {
+	if in.Path == "" {
+		return filepath.Join(in.VolumePath, "**", "*")
+	}
+
+	return in.Path
+}
+
+// GetPercent returns the probability that an operation is disrupted.
+func (in *IOTarget) GetPercent() int {
+	if in.Percent == 0 {
+		return 100
+	}
+
+	return in.Percent
+}
+
+// IOLatencySpec delays the I/O operations on a volume.
+type IOLatencySpec struct {
+	IOTarget `json:",inline"`
+
+	// Delay is the latency that is added to every disrupted operation.
+	Delay metav1.Duration `json:"delay"`
+}
+
+// IOErrnoSpec fails the I/O operations on a volume.
+type IOErrnoSpec struct {
+	IOTarget `json:",inline"`
+
+	// Errno is the error number that the disrupted operations return (e.g, 5 for EIO, 28 for ENOSPC).
+	// +kubebuilder:validation:Minimum=1
+	Errno int32 `json:"errno"`
+}
+
+// DiskFillSpec fills a volume up to the given percentage of its capacity.
+type DiskFillSpec struct {
+	// VolumePath is the mount path of the volume in the containers of the services. The volume must be a
+	// PersistentVolumeClaim that can be mounted by a second pod on the same node (e.g, ReadWriteOnce).
+	VolumePath string `json:"volumePath"`
+
+	// Percent is the usage of the volume, in percent of its capacity, once it is filled. Volumes that are already
+	// above this usage are left as they are.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Percent int `json:"percent"`
+}
+
+// CheckDiskFault validates the disk fault of an action.
+func CheckDiskFault(fault *DiskFaultSpec) error {
+	if err := CheckServiceSelector(&fault.Selector); err != nil {
+		return errors.Wrapf(err, "selector error")
+	}
+
+	if fault.Duration.Duration <= 0 {
+		return errors.Errorf("invalid duration '%s'", fault.Duration.Duration)
+	}
+
+	var faults int
+
+	if fault.Latency != nil {
+		faults++
+
+		if err := checkIOTarget(&fault.Latency.IOTarget); err != nil {
+			return errors.Wrapf(err, "latency error")
+		}
+
+		if fault.Latency.Delay.Duration <= 0 {
+			return errors.Errorf("invalid delay '%s'", fault.Latency.Delay.Duration)
+		}
+	}
+
+	if fault.Errno != nil {
+		faults++
+
+		if err := checkIOTarget(&fault.Errno.IOTarget); err != nil {
+			return errors.Wrapf(err, "errno error")
+		}
+
+		if fault.Errno.Errno <= 0 {
+			return errors.Errorf("invalid errno '%d'", fault.Errno.Errno)
+		}
+	}
+
+	if fault.Fill != nil {
+		faults++
+
+		if !filepath.IsAbs(fault.Fill.VolumePath) {
+			return errors.Errorf("volumePath '%s' must be absolute", fault.Fill.VolumePath)
+		}
+
+		if fault.Fill.Percent < 1 || fault.Fill.Percent > 100 {
+			return errors.Errorf("percent '%d' must be in [1,100]", fault.Fill.Percent)
+		}
+	}
+
+	if faults != 1 {
+		return errors.Errorf("exactly one of latency, errno, and fill must be set. Found '%d'", faults)
+	}
+
+	return nil
+}
+
+func checkIOTarget(target *IOTarget) error {
+	if !filepath.IsAbs(target.VolumePath) {
+		return errors.Errorf("volumePath '%s' must be absolute", target.VolumePath)
+	}
+
+	if target.Path != "" && !strings.HasPrefix(target.Path, target.VolumePath) {
+		return errors.Errorf("path '%s' is not within volumePath '%s'", target.Path, target.VolumePath)
+	}
+
+	if target.Percent < 0 || target.Percent > 100 {
+		return errors.Errorf("percent '%d' must be in [0,100]", target.Percent)
+	}
+
+	return nil
+}
+
+// CheckServiceSelector validates a selector, before it is resolved into services.
+func CheckServiceSelector(selector *ServiceSelector) error {
+	if selector.Macro != nil {
+		if len(selector.Match.ByName) > 0 || len(selector.Match.ByCluster) > 0 {
+			return errors.New("macro conflicts with match")
+		}
+
+		// the filter of the macro is the mode of the selector (e.g, .cluster.servers.one).
+		fields := strings.Split(*selector.Macro, ".")
+		if len(fields) != 4 || fields[0] != "" || fields[1] != "cluster" {
+			return errors.Errorf("'%s' is not a valid macro", *selector.Macro)
+		}
+
+		return checkSelectorMode(Mode(fields[3]), selector.Value, true)
+	}
+
+	if len(selector.Match.ByName) == 0 && len(selector.Match.ByCluster) == 0 {
+		return errors.New("empty selector")
+	}
+
+	return checkSelectorMode(selector.Mode, selector.Value, false)
+}
+
+func checkSelectorMode(mode Mode, value string, fromMacro bool) error {
+	switch mode {
+	case "":
+		if fromMacro {
+			return errors.New("empty mode")
+		}
+
+		return nil
+
+	case OneMode, AllMode:
+		return nil
+
+	case FixedMode, FixedPercentMode, RandomMaxPercentMode:
+		if _, err := strconv.Atoi(value); err != nil {
+			return errors.Wrapf(err, "mode '%s' requires a numeric value", mode)
+		}
+
+		return nil
+
+	default:
+		return errors.Errorf("unknown mode '%s'", mode)
+	}
+}
@@ -99,7 +99,7 @@ func (in Timeline) Next(ref time.Time) time.Time {
 
 	// bad hack. If there is no actual schedule, return something far in the future
 	// for the controller to keep running, but also to raise trigger to the test.
-	return time.Now().Add(12 * time.Hour)
+	return ref.Add(12 * time.Hour)
 }
 
 func (in Timeline) String() string {
@@ -125,7 +125,15 @@ type ResourceDistributionSpec struct {
 	TotalResources corev1.ResourceList `json:"total"`
 
 	// DistributionSpec defines how the TotalResources will be assigned to resources.
+	// If neither the DistributionSpec nor the Weights are set, the TotalResources are split evenly.
+	// +optional
 	DistributionSpec *DistributionSpec `json:"distribution,omitempty"`
+
+	// Weights split the TotalResources among the services proportionally to the given weights. The service i
+	// is assigned Weights[i mod len(Weights)]. For example, [2, 1] assigns twice the resources to the even services.
+	// It cannot be used together with the DistributionSpec.
+	// +optional
+	Weights []float64 `json:"weights,omitempty"`
 }
 
 func (in ResourceDistributionSpec) Validate() error {
@@ -139,9 +147,26 @@ func (in ResourceDistributionSpec) Validate() error {
 		}
 	}
 
+	// Validate the weights.
+	if len(in.Weights) > 0 {
+		if in.DistributionSpec != nil {
+			return errors.Errorf("weights and distribution are mutually exclusive")
+		}
+
+		for i, weight := range in.Weights {
+			if weight <= 0 {
+				return errors.Errorf("weights[%d] must be positive", i)
+			}
+		}
+
+		return nil
+	}
+
 	// Validate the distribution method.
-	if err := ValidateDistribution(in.DistributionSpec); err != nil {
-		return errors.Wrapf(err, "distribution error")
+	if in.DistributionSpec != nil {
+		if err := ValidateDistribution(in.DistributionSpec); err != nil {
+			return errors.Wrapf(err, "distribution error")
+		}
 	}
 
 	return nil
@@ -195,3 +220,62 @@ func (in ResourceDistribution) String() string {
 
 	return out.String()
 }
+
+/*
+
+	Lifetime Distribution
+
+*/
+
+type LifetimeDistributionName string
+
+const (
+	// LifetimeFixed assigns the same lifetime to all jobs.
+	LifetimeFixed LifetimeDistributionName = "fixed"
+
+	// LifetimeUniform draws lifetimes from a continuous uniform distribution within [Min, Duration].
+	LifetimeUniform LifetimeDistributionName = "uniform"
+
+	// LifetimeExponential draws lifetimes from an exponential distribution with mean Duration.
+	LifetimeExponential LifetimeDistributionName = "exponential"
+)
+
+// LifetimeSpec defines how long the jobs run before being terminated. Jobs whose lifetime expires are
+// counted as successful.
+type LifetimeSpec struct {
+	// Distribution defines how the lifetimes are drawn. Defaults to fixed.
+	// +kubebuilder:validation:Enum=fixed;uniform;exponential
+	// +optional
+	Distribution LifetimeDistributionName `json:"distribution,omitempty"`
+
+	// Duration is the lifetime for the fixed distribution, the upper bound for the uniform distribution,
+	// and the mean for the exponential distribution.
+	Duration metav1.Duration `json:"duration"`
+
+	// Min is the lower bound for the uniform distribution. Defaults to zero.
+	// +optional
+	Min *metav1.Duration `json:"min,omitempty"`
+}
+
+func (in LifetimeSpec) Validate() error {
+	if in.Duration.Duration <= 0 {
+		return errors.Errorf("duration must be positive")
+	}
+
+	switch in.Distribution {
+	case "", LifetimeFixed, LifetimeExponential:
+		if in.Min != nil {
+			return errors.Errorf("min is only applicable to the '%s' distribution", LifetimeUniform)
+		}
+
+	case LifetimeUniform:
+		if in.Min != nil && (in.Min.Duration < 0 || in.Min.Duration > in.Duration.Duration) {
+			return errors.Errorf("min must be within [0, %s]", in.Duration.Duration)
+		}
+
+	default:
+		return errors.Errorf("unknown distribution '%s'", in.Distribution)
+	}
+
+	return nil
+}
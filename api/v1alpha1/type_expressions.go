@@ -44,6 +44,13 @@ type ConditionalExpr struct {
 	// +optional
 	// +nullable
 	State ExprState `json:"state,omitempty"`
+
+	// Alertmanager refers to an alert managed by an external Prometheus Alertmanager. The condition is violated
+	// once the alert is firing. The alert is given by name, optionally followed by label matchers.
+	// Example: HighLatency{namespace="prod", severity="critical"}
+	// +optional
+	// +nullable
+	Alertmanager ExprAlertmanager `json:"alertmanager,omitempty"`
 }
 
 func (in *ConditionalExpr) IsZero() bool {
@@ -58,6 +65,10 @@ func (in *ConditionalExpr) HasStateExpr() bool {
 	return in != nil && in.State != ""
 }
 
+func (in *ConditionalExpr) HasAlertmanagerExpr() bool {
+	return in != nil && in.Alertmanager != ""
+}
+
 /*
 	Validate State Expressions
 */
@@ -186,3 +197,44 @@ func (query ExprMetrics) Parse() ([]string, error) {
 
 	return matches, nil
 }
+
+/*
+	Validate Alertmanager Expressions
+*/
+
+// +kubebuilder:object:generate=false
+
+// ExprAlertmanagerValidator expects an alert name, optionally followed by a list of label matchers.
+var ExprAlertmanagerValidator = regexp.MustCompile(`^(?P<alertname>[a-zA-Z_:][a-zA-Z0-9_:]*)\s*(\{(?P<matchers>.*)\})?\s*$`)
+
+// ExprAlertmanagerMatcher expects a label matcher in the form of label="value", label!="value", label=~"regex",
+// or label!~"regex".
+var ExprAlertmanagerMatcher = regexp.MustCompile(`^\s*[a-zA-Z_][a-zA-Z0-9_]*\s*(=|!=|=~|!~)\s*"[^"]*"\s*$`)
+
+type ExprAlertmanager string
+
+// Parse returns the name of the alert and the label matchers.
+func (query ExprAlertmanager) Parse() (alertname string, matchers []string, err error) {
+	matches := ExprAlertmanagerValidator.FindStringSubmatch(string(query))
+	if len(matches) == 0 {
+		return "", nil, errors.Errorf(`erroneous alert '%s'.
+		Examples:
+			- 'HighLatency'
+			- 'HighLatency{namespace="prod"}'
+			- 'HighLatency{namespace="prod", severity=~"critical|warning"}'`, query)
+	}
+
+	alertname = matches[1]
+
+	if list := strings.TrimSpace(matches[3]); list != "" {
+		for _, matcher := range strings.Split(list, ",") {
+			if !ExprAlertmanagerMatcher.MatchString(matcher) {
+				return "", nil, errors.Errorf("erroneous matcher '%s' in alert '%s'", matcher, query)
+			}
+
+			matchers = append(matchers, strings.TrimSpace(matcher))
+		}
+	}
+
+	return alertname, matchers, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/pkg/errors"
+)
+
+// IngestionProtocol is the protocol over which the benchmark tools push their metrics.
+type IngestionProtocol string
+
+const (
+	// IngestionInfluxDB accepts metrics over the InfluxDB line protocol (HTTP).
+	IngestionInfluxDB IngestionProtocol = "InfluxDB"
+
+	// IngestionGraphite accepts metrics over the Graphite plaintext protocol (TCP), in addition to the InfluxDB one.
+	IngestionGraphite IngestionProtocol = "Graphite"
+)
+
+const (
+	// IngestionDatasourceName is the name of the Grafana datasource that points to the ingestion service.
+	IngestionDatasourceName = "InfluxDB"
+
+	// DefaultIngestionDatabase is the database where the ingested metrics are stored, unless specified otherwise.
+	DefaultIngestionDatabase = "frisbee"
+)
+
+// IngestionSpec deploys an ingestion service, for tools that push their metrics (e.g, YCSB, sysbench) instead of
+// exposing them to Prometheus. The ingested metrics are provisioned to Grafana as the IngestionDatasourceName datasource.
+type IngestionSpec struct {
+	// Protocol is the protocol over which the tools push their metrics.
+	// +kubebuilder:validation:Enum=InfluxDB;Graphite
+	Protocol IngestionProtocol `json:"protocol"`
+
+	// Database is the database where the metrics are stored. Defaults to DefaultIngestionDatabase.
+	// +optional
+	Database string `json:"database,omitempty"`
+}
+
+// GetDatabase returns the database where the metrics are stored.
+func (in *IngestionSpec) GetDatabase() string {
+	if in.Database == "" {
+		return DefaultIngestionDatabase
+	}
+
+	return in.Database
+}
+
+// CheckIngestion validates the ingestion service of the scenario.
+func CheckIngestion(scenario *Scenario) error {
+	ingestion := scenario.Spec.Ingestion
+	if ingestion == nil {
+		return nil
+	}
+
+	switch ingestion.Protocol {
+	case IngestionInfluxDB, IngestionGraphite:
+	default:
+		return errors.Errorf("unknown protocol '%s'", ingestion.Protocol)
+	}
+
+	for _, ds := range scenario.Spec.Datasources {
+		if ds.Name == IngestionDatasourceName {
+			return errors.Errorf("datasource '%s' is reserved for the ingestion service", ds.Name)
+		}
+	}
+
+	return nil
+}
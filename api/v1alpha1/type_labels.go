@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"strconv"
+
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -61,6 +63,20 @@ const (
 	// LabelComponent describes the role of the component within the architecture (e.g, SUT or SYS).
 	// It is used to handle differently the SUT resources from the SYS resources (e.g, delete the actions but not grafana).
 	LabelComponent = "scenario.frisbee.dev/component"
+
+	// LabelChainDepth indicates the position of a scenario within a chain of scenarios.
+	// It is used to protect from scenarios that trigger each other in a loop.
+	LabelChainDepth = "scenario.frisbee.dev/chain-depth"
+
+	// LabelParentNamespace points to the namespace of the scenario, for resources that are placed
+	// in the dedicated namespace of their action (see IsolationNamespace).
+	LabelParentNamespace = "scenario.frisbee.dev/parent-namespace"
+
+	// LabelRunID identifies the run of the scenario that the resource belongs to (see RunMetadata).
+	LabelRunID = "scenario.frisbee.dev/run-id"
+
+	// LabelSnapshotSet points to the snapshot set that a VolumeSnapshot belongs to (see SnapshotSpec).
+	LabelSnapshotSet = "scenario.frisbee.dev/snapshot-set"
 )
 
 func SetScenarioLabel(obj *metav1.ObjectMeta, scenario string) {
@@ -151,6 +167,36 @@ func GetScenarioLabel(obj metav1.Object) string {
 	return scenario
 }
 
+// GetScenarioNamespace returns the namespace of the scenario that the object belongs to.
+// It differs from the namespace of the object only if the object is placed in the namespace of its action.
+func GetScenarioNamespace(obj metav1.Object) string {
+	if namespace, isolated := obj.GetLabels()[LabelParentNamespace]; isolated {
+		return namespace
+	}
+
+	return obj.GetNamespace()
+}
+
+// GetScenarioKey returns the key of the scenario that the object belongs to.
+func GetScenarioKey(obj metav1.Object) client.ObjectKey {
+	return client.ObjectKey{Namespace: GetScenarioNamespace(obj), Name: GetScenarioLabel(obj)}
+}
+
+// GetChainDepth returns the position of the scenario within a chain of scenarios.
+// Scenarios that are not triggered by other scenarios have depth 0.
+func GetChainDepth(obj metav1.Object) int {
+	depth, err := strconv.Atoi(obj.GetLabels()[LabelChainDepth])
+	if err != nil {
+		return 0
+	}
+
+	return depth
+}
+
+func SetChainDepth(obj *metav1.ObjectMeta, depth int) {
+	metav1.SetMetaDataLabel(obj, LabelChainDepth, strconv.Itoa(depth))
+}
+
 func IsSYSComponent(obj metav1.Object) bool {
 	return obj.GetLabels()[LabelComponent] == string(ComponentSys)
 }
@@ -193,6 +239,45 @@ func GetComponentLabel(obj metav1.Object) Component {
 const (
 	// SidecarTelemetry is an annotation's value indicating the annotation's key is a telemetry agent.
 	SidecarTelemetry = "sidecar.frisbee.dev/telemetry"
+
+	// AnnotationGrafanaCredentials points to a Secret, in the namespace of the Scenario, with the credentials for
+	// Grafana. The Secret holds either an "apiKey", or a "username" and a "password".
+	AnnotationGrafanaCredentials = "telemetry.frisbee.dev/grafana-credentials"
+
+	// AnnotationTraceParent carries the W3C trace context of the reconciliation that created the resource.
+	// It links the traces of the controllers together, and it is exposed to Pods with telemetry agents via
+	// TelemetryPodInfoDir, so that the workload traces can be correlated with the scenario.
+	AnnotationTraceParent = "telemetry.frisbee.dev/traceparent"
+)
+
+/*
+	Telemetry Agent Contract
+
+	Frisbee mounts a handshake volume to all the containers of a Pod with telemetry agents, at TelemetryHandshakePath.
+	The volume contains:
+	  * TelemetryPodInfoDir: the labels, annotations, and resource limits of the main container (downward API).
+	  * TelemetryMainPIDFile: the PID of the main container's process, only if the process namespace of the Pod
+	    is shared. The file is written by a postStart hook of the main container, and therefore it may be missing
+	    when the agent starts (use e.g, inotifywait). The main container must provide /bin/sh.
+
+	The process namespace is not shared by default. Agents that enter the namespaces of the main container
+	(e.g, nsenter -t $(cat main.pid) -C to tap into its cgroup) opt in by setting shareProcessNamespace in
+	their template, which then applies to the whole Pod.
+*/
+
+const (
+	// TelemetryHandshakeVolume is the name of the volume used for the handshake between the main container
+	// and the telemetry agents.
+	TelemetryHandshakeVolume = "frisbee-telemetry"
+
+	// TelemetryHandshakePath is where the handshake volume is mounted in every container of the Pod.
+	TelemetryHandshakePath = "/frisbee/telemetry"
+
+	// TelemetryMainPIDFile contains the PID of the main container's process.
+	TelemetryMainPIDFile = "main.pid"
+
+	// TelemetryPodInfoDir contains the downward API information of the main container.
+	TelemetryPodInfoDir = "podinfo"
 )
 
 const (
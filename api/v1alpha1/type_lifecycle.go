@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -47,11 +49,79 @@ const (
 	// ConditionAssertionError indicate that an assertion condition is false.
 	ConditionAssertionError = ConditionType("AssertError")
 
+	// ConditionTelemetryReady indicates whether the telemetry stack of the scenario is healthy. That is, Prometheus and
+	// Grafana are running, and the controller is connected to Grafana with the alerting channel registered.
+	ConditionTelemetryReady = ConditionType("TelemetryReady")
+
+	// ConditionAborted indicates that the execution was intentionally stopped by the user.
+	ConditionAborted = ConditionType("Aborted")
+
+	// ConditionFaultResidue indicates whether a recovered fault has left residue in its targets (e.g, tc rules),
+	// which would silently distort the measurements of the subsequent actions.
+	ConditionFaultResidue = ConditionType("FaultResidue")
+
+	// ConditionActionTimeout indicates that an action has not been completed within its timeout.
+	ConditionActionTimeout = ConditionType("ActionTimeout")
+
+	// ConditionCreationThrottled indicates that the creation of new jobs is delayed, because the creation rate of
+	// the jobs has reached its limit (e.g, Cluster.Spec.Placement.MaxCreationsPerMinute).
+	ConditionCreationThrottled = ConditionType("CreationThrottled")
+
+	// ConditionBudgetExceeded indicates that actions are queued, because their requests do not fit into the
+	// remaining resource budget of the scenario.
+	ConditionBudgetExceeded = ConditionType("BudgetExceeded")
+
 	// ConditionInvalidStateTransition indicates the transition of a resource into another state.
 	// This is used for debugging.
 	ConditionInvalidStateTransition = ConditionType("InvalidStateTransition")
 )
 
+// These are the reasons of conditions that are not derived from the phases of the jobs. The reasons that are derived
+// from the jobs (e.g, AllJobsAreSuccessful, AtLeastOneJobHasFailed) are documented in pkg/lifecycle, and the reasons
+// of conditional expressions (e.g, AssertError) are documented in pkg/expressions.
+const (
+	// ReasonInitialized is used with ConditionCRInitialized, once the controller has accepted the CR.
+	ReasonInitialized = "Initialized"
+
+	// ReasonAborted is used with ConditionAborted, once the user has stopped the execution.
+	ReasonAborted = "Aborted"
+
+	// ReasonTooManyFailedZones is used with ConditionJobUnexpectedTermination, once the failed zones of a
+	// cluster exceed the tolerated zones.
+	ReasonTooManyFailedZones = "TooManyFailedZones"
+
+	// ReasonActionTimeout is used with ConditionActionTimeout, once the timeout of an action has expired.
+	ReasonActionTimeout = "ActionTimeout"
+
+	// ReasonNoResidue is used with ConditionFaultResidue, once the recovery of the fault has been verified.
+	ReasonNoResidue = "NoResidue"
+
+	// ReasonUnrecoveredRecords is used with ConditionFaultResidue, if Chaos-Mesh has not recovered all the
+	// targets of the fault.
+	ReasonUnrecoveredRecords = "UnrecoveredRecords"
+
+	// ReasonRecoveryTimeout is used with ConditionFaultResidue, if the recovery of an expired fault is not reported
+	// within a grace period. The recovery is verified again, if it is eventually reported.
+	ReasonRecoveryTimeout = "RecoveryTimeout"
+
+	// ReasonCleanupProbeFailed is used with ConditionFaultResidue, if the cleanup probe has failed in a target.
+	ReasonCleanupProbeFailed = "CleanupProbeFailed"
+
+	// ReasonCreationRateExceeded is used with ConditionCreationThrottled, while the jobs that have been created
+	// within the last minute have reached the limit.
+	ReasonCreationRateExceeded = "CreationRateExceeded"
+
+	// ReasonWithinCreationRate is used with ConditionCreationThrottled, once the creation of jobs is resumed.
+	ReasonWithinCreationRate = "WithinCreationRate"
+
+	// ReasonInsufficientBudget is used with ConditionBudgetExceeded, while the remaining budget of the scenario
+	// cannot fit the requests of the next action.
+	ReasonInsufficientBudget = "InsufficientBudget"
+
+	// ReasonWithinBudget is used with ConditionBudgetExceeded, once the queued actions are scheduled.
+	ReasonWithinBudget = "WithinBudget"
+)
+
 // Phase is a simple, high-level summary of where the Object is in its lifecycle.
 type Phase string
 
@@ -61,6 +131,7 @@ type Phase string
 // PhaseUninitialized -> PhaseRunning* -> PhaseFailed
 // PhaseUninitialized -> PhaseChaos* -> Completed
 // PhaseUninitialized -> PhaseRunning* -> PhaseChaos -> Completed
+// PhaseUninitialized -> PhaseRunning* -> PhaseAborted
 // The asterix (*) Indicate that the same phase may appear recursively.
 const (
 	// PhaseUninitialized means that request is not yet accepted by the controller.
@@ -82,6 +153,10 @@ const (
 	// PhaseFailed means that at least one job of the CR has terminated in a failure (exited with a
 	// non-zero exit code or was stopped by the system).
 	PhaseFailed = Phase("Failed")
+
+	// PhaseAborted means that the execution was intentionally stopped by the user. Unlike PhaseFailed,
+	// it does not indicate a problem with the jobs. It is used only by Scenarios.
+	PhaseAborted = Phase("Aborted")
 )
 
 func (p Phase) Is(refs ...Phase) bool {
@@ -119,6 +194,54 @@ type Lifecycle struct {
 	// Conditions describe sequences of events that warrant the present Phase.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the generation of the CR that was observed by the last status update.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// Transition moves the lifecycle to the phase, reason, and message of the next lifecycle, and sets the given
+// condition, if any. Unlike the assignment of the next lifecycle, the existing conditions are retained.
+// It returns true if the lifecycle has been changed.
+func (in *Lifecycle) Transition(next Lifecycle, cond *metav1.Condition) bool {
+	updated := in.DeepCopy()
+
+	updated.Phase = next.Phase
+	updated.Reason = next.Reason
+	updated.Message = next.Message
+
+	if cond != nil {
+		cond.ObservedGeneration = in.ObservedGeneration
+
+		meta.SetStatusCondition(&updated.Conditions, *cond)
+	}
+
+	if equality.Semantic.DeepEqual(in, updated) {
+		return false
+	}
+
+	*in = *updated
+
+	return true
+}
+
+// Observe records the generation of the CR on the lifecycle and on its conditions.
+func (in *Lifecycle) Observe(generation int64) {
+	in.ObservedGeneration = generation
+
+	for i := range in.Conditions {
+		in.Conditions[i].ObservedGeneration = generation
+	}
+}
+
+// FindCondition returns the condition of the given type, or nil if the condition is not set.
+func (in *Lifecycle) FindCondition(conditionType ConditionType) *metav1.Condition {
+	return meta.FindStatusCondition(in.Conditions, conditionType.String())
+}
+
+// IsConditionTrue returns true if the condition of the given type is set and its status is True.
+func (in *Lifecycle) IsConditionTrue(conditionType ConditionType) bool {
+	return meta.IsStatusConditionTrue(in.Conditions, conditionType.String())
 }
 
 // +kubebuilder:object:generate=false
@@ -0,0 +1,64 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// +kubebuilder:object:generate=false
+
+// OffloadableStatus is implemented by objects whose status may carry payloads that are too large for etcd.
+// The large payloads are moved to a ConfigMap, and the status keeps a reference to the ConfigMap.
+type OffloadableStatus interface {
+	ReconcileStatusAware
+
+	// OffloadPayloads removes from the status the payloads that are larger than the threshold, and returns them
+	// keyed by PayloadKey. The removed payloads are replaced by OffloadedPayload placeholders.
+	OffloadPayloads(threshold int) map[string]string
+
+	// SetPayloadsRef records the name of the ConfigMap that holds the offloaded payloads.
+	SetPayloadsRef(name string)
+}
+
+// PayloadsConfigMapName returns the name of the ConfigMap that holds the offloaded payloads of the object.
+func PayloadsConfigMapName(objName string) string {
+	return objName + "-payloads"
+}
+
+var invalidPayloadKey = regexp.MustCompile(`[^-._a-zA-Z0-9]+`)
+
+// PayloadKey joins the given parts into a valid ConfigMap key.
+func PayloadKey(parts ...string) string {
+	var key string
+
+	for i, part := range parts {
+		if i > 0 {
+			key += "."
+		}
+
+		key += invalidPayloadKey.ReplaceAllString(part, "_")
+	}
+
+	return key
+}
+
+// OffloadedPayload is the placeholder of a payload that has been moved to the ConfigMap of the object.
+func OffloadedPayload(key string, size int) string {
+	return fmt.Sprintf("<offloaded: %d bytes at key '%s'>", size, key)
+}
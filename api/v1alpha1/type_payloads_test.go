@@ -0,0 +1,157 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+)
+
+const threshold = 16
+
+var (
+	small = strings.Repeat("s", threshold)
+	large = strings.Repeat("l", threshold+1)
+)
+
+func TestScenario_OffloadPayloads(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       v1alpha1.ScenarioStatus
+		wantPayloads map[string]string
+		wantStatus   v1alpha1.ScenarioStatus
+	}{
+		{
+			name: "within the threshold",
+			status: v1alpha1.ScenarioStatus{
+				Lifecycle: v1alpha1.Lifecycle{Message: small},
+				Actions:   []v1alpha1.ActionStatus{{Name: "a", Message: small}},
+			},
+			wantPayloads: map[string]string{},
+			wantStatus: v1alpha1.ScenarioStatus{
+				Lifecycle: v1alpha1.Lifecycle{Message: small},
+				Actions:   []v1alpha1.ActionStatus{{Name: "a", Message: small}},
+			},
+		},
+		{
+			name:         "scenario message",
+			status:       v1alpha1.ScenarioStatus{Lifecycle: v1alpha1.Lifecycle{Message: large}},
+			wantPayloads: map[string]string{"message": large},
+			wantStatus: v1alpha1.ScenarioStatus{
+				Lifecycle: v1alpha1.Lifecycle{Message: "<offloaded: 17 bytes at key 'message'>"},
+			},
+		},
+		{
+			name: "action message",
+			status: v1alpha1.ScenarioStatus{
+				Actions: []v1alpha1.ActionStatus{{Name: "a", Message: small}, {Name: "b", Message: large}},
+			},
+			wantPayloads: map[string]string{"b.message": large},
+			wantStatus: v1alpha1.ScenarioStatus{
+				Actions: []v1alpha1.ActionStatus{
+					{Name: "a", Message: small},
+					{Name: "b", Message: "<offloaded: 17 bytes at key 'b.message'>"},
+				},
+			},
+		},
+		{
+			name: "assertion values",
+			status: v1alpha1.ScenarioStatus{
+				Actions: []v1alpha1.ActionStatus{{
+					Name: "a",
+					AssertionFailure: &v1alpha1.AssertionFailure{
+						Values: map[string]string{"small": small, "avg(rate[1m])": large},
+					},
+				}},
+			},
+			wantPayloads: map[string]string{"a.values.avg_rate_1m_": large},
+			wantStatus: v1alpha1.ScenarioStatus{
+				Actions: []v1alpha1.ActionStatus{{
+					Name: "a",
+					AssertionFailure: &v1alpha1.AssertionFailure{
+						Values: map[string]string{
+							"small":         small,
+							"avg(rate[1m])": "<offloaded: 17 bytes at key 'a.values.avg_rate_1m_'>",
+						},
+					},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenario := v1alpha1.Scenario{Status: tt.status}
+
+			payloads := scenario.OffloadPayloads(threshold)
+
+			if !reflect.DeepEqual(payloads, tt.wantPayloads) {
+				t.Errorf("OffloadPayloads() = %v, want %v", payloads, tt.wantPayloads)
+			}
+
+			if !reflect.DeepEqual(scenario.Status, tt.wantStatus) {
+				t.Errorf("status = %+v, want %+v", scenario.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestVirtualObject_OffloadPayloads(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         map[string]string
+		wantPayloads map[string]string
+		wantData     map[string]string
+	}{
+		{
+			name:         "within the threshold",
+			data:         map[string]string{"report": small},
+			wantPayloads: map[string]string{},
+			wantData:     map[string]string{"report": small},
+		},
+		{
+			name:         "large data",
+			data:         map[string]string{"report": small, "logs/client-1": large},
+			wantPayloads: map[string]string{"logs_client-1": large},
+			wantData: map[string]string{
+				"report":        small,
+				"logs/client-1": "<offloaded: 17 bytes at key 'logs_client-1'>",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var vobj v1alpha1.VirtualObject
+
+			vobj.Status.Data = tt.data
+
+			payloads := vobj.OffloadPayloads(threshold)
+
+			if !reflect.DeepEqual(payloads, tt.wantPayloads) {
+				t.Errorf("OffloadPayloads() = %v, want %v", payloads, tt.wantPayloads)
+			}
+
+			if !reflect.DeepEqual(vobj.Status.Data, tt.wantData) {
+				t.Errorf("data = %v, want %v", vobj.Status.Data, tt.wantData)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// PropagationSpec defines the metadata of the scenario that flow into every generated object, down to the Pods.
+// It allows external systems (e.g, cost or observability tools) to attribute the resources to experiments.
+type PropagationSpec struct {
+	// Labels lists the keys of the scenario labels to propagate.
+	// +optional
+	Labels []string `json:"labels,omitempty"`
+
+	// Annotations lists the keys of the scenario annotations to propagate.
+	// +optional
+	Annotations []string `json:"annotations,omitempty"`
+
+	// ExtraLabels are additional labels to propagate. The values are templates that are evaluated against
+	// the scenario, e.g, runID: "{{.run.id}}", gitCommit: "{{.run.gitCommit}}", or team: '{{index .labels "team"}}'.
+	// +optional
+	ExtraLabels map[string]string `json:"extraLabels,omitempty"`
+
+	// ExtraAnnotations are additional annotations to propagate. The values are templates, as in ExtraLabels.
+	// +optional
+	ExtraAnnotations map[string]string `json:"extraAnnotations,omitempty"`
+}
+
+const (
+	// AnnotationPropagatedLabels lists the keys of the labels that an object propagates to its children.
+	AnnotationPropagatedLabels = "propagation.frisbee.dev/labels"
+
+	// AnnotationPropagatedAnnotations lists the keys of the annotations that an object propagates to its children.
+	AnnotationPropagatedAnnotations = "propagation.frisbee.dev/annotations"
+)
+
+// Validate checks the keys and the templates of the policy.
+func (in *PropagationSpec) Validate() error {
+	if in == nil {
+		return nil
+	}
+
+	keys := append(append([]string{}, in.Labels...), in.Annotations...)
+
+	for key := range in.ExtraLabels {
+		keys = append(keys, key)
+	}
+
+	for key := range in.ExtraAnnotations {
+		keys = append(keys, key)
+	}
+
+	for _, key := range keys {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return errors.Errorf("invalid key '%s': %s", key, strings.Join(errs, "; "))
+		}
+
+		if strings.Contains(key, "frisbee.dev/") {
+			return errors.Errorf("key '%s' is reserved by Frisbee", key)
+		}
+	}
+
+	for _, extra := range []map[string]string{in.ExtraLabels, in.ExtraAnnotations} {
+		for key, value := range extra {
+			if _, err := template.New(key).Funcs(sprigFuncMap).Parse(value); err != nil {
+				return errors.Wrapf(err, "invalid template for '%s'", key)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Render returns the labels and annotations that the scenario propagates to its children.
+func (in *PropagationSpec) Render(scenario *Scenario) (labels map[string]string, annotations map[string]string, err error) {
+	if in == nil {
+		return nil, nil, nil
+	}
+
+	labels = make(map[string]string)
+	annotations = make(map[string]string)
+
+	for _, key := range in.Labels {
+		if value, exists := scenario.GetLabels()[key]; exists {
+			labels[key] = value
+		}
+	}
+
+	for _, key := range in.Annotations {
+		if value, exists := scenario.GetAnnotations()[key]; exists {
+			annotations[key] = value
+		}
+	}
+
+	data := scenarioTemplateData(scenario)
+
+	render := func(key, value string) (string, error) {
+		return renderTemplate(key, value, data)
+	}
+
+	for key, value := range in.ExtraLabels {
+		rendered, err := render(key, value)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if errs := validation.IsValidLabelValue(rendered); len(errs) > 0 {
+			return nil, nil, errors.Errorf("invalid value '%s' for label '%s': %s", rendered, key, strings.Join(errs, "; "))
+		}
+
+		labels[key] = rendered
+	}
+
+	for key, value := range in.ExtraAnnotations {
+		rendered, err := render(key, value)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		annotations[key] = rendered
+	}
+
+	return labels, annotations, nil
+}
+
+// scenarioTemplateData returns the fields of the scenario that are available to the templates evaluated against it.
+func scenarioTemplateData(scenario *Scenario) map[string]interface{} {
+	return map[string]interface{}{
+		"scenario":    scenario.GetName(),
+		"namespace":   scenario.GetNamespace(),
+		"uid":         string(scenario.GetUID()),
+		"vars":        scenario.Spec.Vars,
+		"labels":      scenario.GetLabels(),
+		"annotations": scenario.GetAnnotations(),
+		"run":         runData(scenario.Status.Run),
+	}
+}
+
+// renderTemplate evaluates the template against the given data. Missing keys evaluate to their zero value.
+func renderTemplate(key, value string, data map[string]interface{}) (string, error) {
+	t, err := template.New(key).Funcs(sprigFuncMap).Option("missingkey=zero").Parse(value)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid template for '%s'", key)
+	}
+
+	var out strings.Builder
+
+	if err := t.Execute(&out, data); err != nil {
+		return "", errors.Wrapf(err, "cannot evaluate '%s'", key)
+	}
+
+	return out.String(), nil
+}
+
+// PropagateMetadata copies the propagated labels and annotations of the parent to the child, without
+// overriding the metadata of the child. If the parent is a Scenario, the metadata are given by its propagation
+// policy. Otherwise, they are the ones listed in the propagation annotations of the parent.
+func PropagateMetadata(child, parent metav1.Object) error {
+	var labels, annotations map[string]string
+
+	if scenario, ok := parent.(*Scenario); ok {
+		rendered, renderedAnnotations, err := scenario.Spec.Propagation.Render(scenario)
+		if err != nil {
+			return errors.Wrapf(err, "propagation error")
+		}
+
+		labels, annotations = rendered, renderedAnnotations
+
+		// the run id always flows into the children, for tracing them back to the run.
+		if runID := GetRunID(scenario); runID != "" {
+			if labels == nil {
+				labels = make(map[string]string, 1)
+			}
+
+			labels[LabelRunID] = runID
+		}
+	} else {
+		labels = pick(parent.GetLabels(), parent.GetAnnotations()[AnnotationPropagatedLabels])
+		annotations = pick(parent.GetAnnotations(), parent.GetAnnotations()[AnnotationPropagatedAnnotations])
+	}
+
+	if len(labels) == 0 && len(annotations) == 0 {
+		return nil
+	}
+
+	childLabels := child.GetLabels()
+	if childLabels == nil {
+		childLabels = make(map[string]string, len(labels))
+	}
+
+	for key, value := range labels {
+		if _, exists := childLabels[key]; !exists {
+			childLabels[key] = value
+		}
+	}
+
+	childAnnotations := child.GetAnnotations()
+	if childAnnotations == nil {
+		childAnnotations = make(map[string]string, len(annotations)+2)
+	}
+
+	for key, value := range annotations {
+		if _, exists := childAnnotations[key]; !exists {
+			childAnnotations[key] = value
+		}
+	}
+
+	// let the child propagate the same metadata to its own children.
+	if len(labels) > 0 {
+		childAnnotations[AnnotationPropagatedLabels] = joinKeys(labels)
+	}
+
+	if len(annotations) > 0 {
+		childAnnotations[AnnotationPropagatedAnnotations] = joinKeys(annotations)
+	}
+
+	child.SetLabels(childLabels)
+	child.SetAnnotations(childAnnotations)
+
+	return nil
+}
+
+// pick returns the entries whose keys are in the comma-separated list.
+func pick(from map[string]string, keys string) map[string]string {
+	if keys == "" {
+		return nil
+	}
+
+	picked := make(map[string]string)
+
+	for _, key := range strings.Split(keys, ",") {
+		if value, exists := from[key]; exists {
+			picked[key] = value
+		}
+	}
+
+	return picked
+}
+
+func joinKeys(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return strings.Join(keys, ",")
+}
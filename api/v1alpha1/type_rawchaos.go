@@ -0,0 +1,172 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// ChaosMeshGroup is the API group of the Chaos-Mesh faults.
+const ChaosMeshGroup = "chaos-mesh.org"
+
+// RawChaosKinds are the kinds of Chaos-Mesh faults whose lifecycle is tracked by the Chaos controller.
+// Faults of other kinds would never be observed, and they are rejected.
+var RawChaosKinds = []string{"IOChaos", "KernelChaos", "NetworkChaos", "PodChaos", "StressChaos", "TimeChaos"}
+
+// +kubebuilder:object:generate=false
+
+// ChaosSchemaValidator validates a Chaos-Mesh manifest against the schema of its CRD, as installed in the cluster.
+// It is installed by the operator at startup. If no validator is installed, only the structure of the manifest
+// is validated, and the schema errors surface when the fault is injected.
+type ChaosSchemaValidator interface {
+	Validate(fault *unstructured.Unstructured) error
+}
+
+var chaosSchemaValidator ChaosSchemaValidator
+
+// SetChaosSchemaValidator installs the validator that is used by the admission webhooks.
+func SetChaosSchemaValidator(validator ChaosSchemaValidator) {
+	chaosSchemaValidator = validator
+}
+
+// ParseRawChaos decodes the inline manifest of a chaos.
+func ParseRawChaos(raw string) (*unstructured.Unstructured, error) {
+	var body map[string]interface{}
+
+	if err := yaml.Unmarshal([]byte(raw), &body); err != nil {
+		return nil, errors.Wrapf(err, "cannot decode manifest")
+	}
+
+	if len(body) == 0 {
+		return nil, errors.New("empty manifest")
+	}
+
+	return &unstructured.Unstructured{Object: body}, nil
+}
+
+// CheckRawChaos validates the inline Chaos-Mesh manifest of an action. The name and the namespace of the manifest
+// are managed by Frisbee, and they are overwritten when the fault is injected.
+func CheckRawChaos(chaos *ChaosSpec) error {
+	fault, err := ParseRawChaos(chaos.Raw)
+	if err != nil {
+		return errors.Wrapf(err, "raw error")
+	}
+
+	gvk := fault.GroupVersionKind()
+
+	if gvk.Group != ChaosMeshGroup {
+		return errors.Errorf("apiVersion '%s' is not a %s fault", fault.GetAPIVersion(), ChaosMeshGroup)
+	}
+
+	if !isRawChaosKind(gvk.Kind) {
+		return errors.Errorf("kind '%s' is not supported. Supported kinds are: %s",
+			gvk.Kind, strings.Join(RawChaosKinds, ", "))
+	}
+
+	if _, found, _ := unstructured.NestedMap(fault.Object, "spec"); !found {
+		return errors.New("manifest has no spec")
+	}
+
+	// the duration determines the expiry of the fault.
+	if duration, found, _ := unstructured.NestedString(fault.Object, "spec", "duration"); found {
+		if _, err := time.ParseDuration(duration); err != nil {
+			return errors.Wrapf(err, "invalid duration '%s'", duration)
+		}
+	}
+
+	if gvk.Kind == "StressChaos" {
+		if err := CheckStressChaos(fault); err != nil {
+			return errors.Wrapf(err, "stress error")
+		}
+	}
+
+	if probe := chaos.CleanupProbe; probe != nil && len(probe.Command) == 0 {
+		return errors.New("cleanupProbe has no command")
+	}
+
+	if chaosSchemaValidator == nil {
+		return nil
+	}
+
+	if err := chaosSchemaValidator.Validate(fault); err != nil {
+		return errors.Wrapf(err, "schema error")
+	}
+
+	return nil
+}
+
+func isRawChaosKind(kind string) bool {
+	for _, supported := range RawChaosKinds {
+		if kind == supported {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckStressChaos validates the stressors of a StressChaos manifest. At least one of the cpu, memory, or
+// stress-ng stressors must be defined, and every stressor must have at least one worker.
+func CheckStressChaos(fault *unstructured.Unstructured) error {
+	stressors, _, _ := unstructured.NestedMap(fault.Object, "spec", "stressors")
+	stressng, _, _ := unstructured.NestedString(fault.Object, "spec", "stressngStressors")
+
+	if len(stressors) == 0 && stressng == "" {
+		return errors.New("at least one of stressors or stressngStressors must be defined")
+	}
+
+	for name := range stressors {
+		if name != "cpu" && name != "memory" {
+			return errors.Errorf("stressor '%s' is not supported. Supported stressors are: cpu, memory", name)
+		}
+
+		workers, found, err := unstructured.NestedFieldNoCopy(stressors, name, "workers")
+		if err != nil || !found {
+			return errors.Errorf("%s stressor has no workers", name)
+		}
+
+		if n, ok := numeric(workers); !ok || n < 1 {
+			return errors.Errorf("%s stressor requires at least one worker, got '%v'", name, workers)
+		}
+	}
+
+	// the load is the percentage of a CPU that every worker occupies.
+	if load, found, _ := unstructured.NestedFieldNoCopy(stressors, "cpu", "load"); found {
+		if percent, ok := numeric(load); !ok || percent < 0 || percent > 100 {
+			return errors.Errorf("cpu load must be between 0 and 100, got '%v'", load)
+		}
+	}
+
+	return nil
+}
+
+// numeric returns the value of a number, as decoded from either YAML or JSON.
+func numeric(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// RegistryOutageSpec makes the image registries unreachable from the nodes of the selected services, and restarts
+// the services, so that their images have to be pulled while the registries are down.
+//
+// Images are pulled by the nodes, not by the pods. Therefore, the outage is injected as a NetworkChaos into helper
+// pods that share the network of the nodes (hostNetwork). The restarted services pull their images again, even if
+// the images are cached on the nodes, and remain pending until the outage is over.
+type RegistryOutageSpec struct {
+	// Selector selects the services that are restarted. The services are selected when the action runs.
+	Selector ServiceSelector `json:"selector"`
+
+	// Registries are the addresses of the image registries, given as CIDRs (e.g, 10.0.0.0/8), IPs,
+	// or domains (e.g, registry-1.docker.io).
+	// +kubebuilder:validation:MinItems=1
+	Registries []string `json:"registries"`
+
+	// Duration is how long the registries remain unreachable.
+	Duration metav1.Duration `json:"duration"`
+}
+
+// CheckRegistryOutage validates the registry outage of an action.
+func CheckRegistryOutage(outage *RegistryOutageSpec) error {
+	if err := CheckServiceSelector(&outage.Selector); err != nil {
+		return errors.Wrapf(err, "selector error")
+	}
+
+	if len(outage.Registries) == 0 {
+		return errors.New("no registries")
+	}
+
+	for _, registry := range outage.Registries {
+		if _, _, err := net.ParseCIDR(registry); err == nil {
+			continue
+		}
+
+		if net.ParseIP(registry) != nil {
+			continue
+		}
+
+		if errs := validation.IsDNS1123Subdomain(registry); len(errs) > 0 {
+			return errors.Errorf("registry '%s' is neither a CIDR, nor an IP, nor a domain", registry)
+		}
+	}
+
+	if outage.Duration.Duration <= 0 {
+		return errors.Errorf("invalid duration '%s'", outage.Duration.Duration)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// RunMetadata identifies a run of a scenario, and links it to the system that submitted it (e.g, a CI pipeline).
+// The run is reported in the status, in the test reports, in the Grafana annotations, and in the external
+// labels of Prometheus.
+type RunMetadata struct {
+	// ID uniquely identifies the run. If empty, a UUID is generated.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// TriggeredBy is the user, or the service account, that submitted the scenario.
+	// +optional
+	TriggeredBy string `json:"triggeredBy,omitempty"`
+
+	// Source points to the origin of the run.
+	// +optional
+	Source RunSource `json:"source,omitempty"`
+}
+
+// RunSource points to the origin of a run.
+type RunSource struct {
+	// CIJobURL points to the CI job that submitted the scenario.
+	// +optional
+	CIJobURL string `json:"ciJobURL,omitempty"`
+
+	// GitCommit is the commit of the code under test.
+	// +optional
+	GitCommit string `json:"gitCommit,omitempty"`
+}
+
+// Validate checks that the run id can be used as a label value.
+func (in *RunMetadata) Validate() error {
+	if in == nil || in.ID == "" {
+		return nil
+	}
+
+	if errs := validation.IsValidLabelValue(in.ID); len(errs) > 0 {
+		return errors.Errorf("invalid run id '%s': %s", in.ID, strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// NewRunID returns a new unique run id.
+func NewRunID() string {
+	return uuid.NewString()
+}
+
+// ResolveRun returns the run metadata of the scenario, with the id filled in. If the id is not set by the
+// admission webhook, the uid of the scenario is used.
+func ResolveRun(scenario *Scenario) *RunMetadata {
+	run := scenario.Spec.Run.DeepCopy()
+	if run == nil {
+		run = &RunMetadata{}
+	}
+
+	if run.ID == "" {
+		run.ID = string(scenario.GetUID())
+	}
+
+	return run
+}
+
+// GetRunID returns the id of the run that the object belongs to. For scenarios the id is taken from the
+// status, whereas for the generated objects it is taken from the run label.
+func GetRunID(obj metav1.Object) string {
+	if scenario, ok := obj.(*Scenario); ok && scenario.Status.Run != nil {
+		return scenario.Status.Run.ID
+	}
+
+	return obj.GetLabels()[LabelRunID]
+}
+
+// runData returns the run metadata in the form used by the propagation templates.
+func runData(run *RunMetadata) map[string]string {
+	if run == nil {
+		return map[string]string{}
+	}
+
+	return map[string]string{
+		"id":          run.ID,
+		"triggeredBy": run.TriggeredBy,
+		"ciJobURL":    run.Source.CIJobURL,
+		"gitCommit":   run.Source.GitCommit,
+	}
+}
@@ -16,6 +16,22 @@ limitations under the License.
 
 package v1alpha1
 
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // TaskSchedulerSpec determines the conditions for creating new tasks of a Job.
 // The scheduler will schedule up to spec.GenerateObjectFromTemplate.Instances or spec.GenerateObjectFromTemplate.Until.
 type TaskSchedulerSpec struct {
@@ -49,6 +65,278 @@ type TaskSchedulerSpec struct {
 	// Multiple tasks may run concurrently.
 	// +optional
 	Event *ConditionalExpr `json:"event,omitempty"`
+
+	// Ramp paces the creation of tasks according to a ramp-up profile, in order to model the arrival of users.
+	// Multiple tasks may run concurrently.
+	// +optional
+	Ramp *RampSpec `json:"ramp,omitempty"`
+
+	// Trace replays the arrival times of a recorded workload trace. It is only supported by Clusters.
+	// Multiple tasks may run concurrently.
+	// +optional
+	Trace *TraceSpec `json:"trace,omitempty"`
+}
+
+// RampProfile is the shape of a ramp-up curve.
+// +kubebuilder:validation:Enum=linear;exponential;step
+type RampProfile string
+
+const (
+	// RampLinear creates the tasks at a constant rate. The first task is created at the beginning of the ramp,
+	// and the last task at the end of the ramp.
+	RampLinear = RampProfile("linear")
+
+	// RampExponential creates the tasks in waves of doubling size (1, 2, 4, ...), evenly spaced within the ramp.
+	RampExponential = RampProfile("exponential")
+
+	// RampStep creates the tasks in equally sized waves, evenly spaced within the ramp.
+	RampStep = RampProfile("step")
+)
+
+// RampSpec describes a ramp-up curve.
+type RampSpec struct {
+	// Profile is the shape of the curve.
+	Profile RampProfile `json:"profile"`
+
+	// Duration is the time from the creation of the object until the creation of the last task.
+	Duration metav1.Duration `json:"duration"`
+
+	// Steps is the number of waves for the step profile.
+	// +optional
+	Steps int `json:"steps,omitempty"`
+}
+
+// Validate checks the parameters of the ramp.
+func (in *RampSpec) Validate() error {
+	if in.Duration.Duration <= 0 {
+		return errors.New("duration must be positive")
+	}
+
+	switch in.Profile {
+	case RampLinear, RampExponential:
+		return nil
+	case RampStep:
+		if in.Steps < 1 {
+			return errors.New("step profile requires at least one step")
+		}
+
+		return nil
+	default:
+		return errors.Errorf("unknown profile '%s'", in.Profile)
+	}
+}
+
+// At returns the time, relative to the beginning of the ramp, at which the k-th task (starting from 1)
+// out of total tasks is due.
+func (in *RampSpec) At(k int, total int) time.Duration {
+	// wave returns the offset of the given wave, when waves are evenly spaced within the ramp.
+	wave := func(w int, waves int) time.Duration {
+		if waves <= 1 {
+			return 0
+		}
+
+		return time.Duration(int64(in.Duration.Duration) * int64(w) / int64(waves-1))
+	}
+
+	switch {
+	case k <= 1 || total <= 1:
+		return 0
+	case k > total:
+		return in.Duration.Duration
+	}
+
+	switch in.Profile {
+	case RampExponential:
+		// the task k belongs to the wave floor(log2(k)), with waves of 1, 2, 4, ... tasks.
+		return wave(bits.Len(uint(k))-1, bits.Len(uint(total)))
+
+	case RampStep:
+		steps := in.Steps
+		if steps > total {
+			steps = total
+		}
+
+		return wave((k-1)*steps/total, steps)
+
+	default:
+		// every task is a wave on its own.
+		return wave(k-1, total)
+	}
+}
+
+// Timeline returns the due time of every task.
+func (in *RampSpec) Timeline(start metav1.Time, total int) Timeline {
+	timeline := make(Timeline, total)
+
+	for k := 1; k <= total; k++ {
+		timeline[k-1] = metav1.NewTime(start.Add(in.At(k, total)))
+	}
+
+	return timeline
+}
+
+// TraceSpec points to a recorded workload trace, in CSV format.
+//
+// The first line is the header. The first column is the arrival time of the task, given either in seconds
+// (e.g, a Unix timestamp), in RFC3339, or as a duration (e.g, 1m30s). Arrival times are relative to the first
+// task of the trace. The remaining columns, if any, are passed to the tasks as inputs, named after the header.
+//
+// For example:
+//
+//	timestamp,size
+//	0,10
+//	1.5,20
+//	4,10
+type TraceSpec struct {
+	// Data is the inline contents of the trace.
+	// +optional
+	Data string `json:"data,omitempty"`
+
+	// ConfigMapRef selects a key of a ConfigMap that holds the trace
+	// (e.g, kubectl create configmap mytrace --from-file=trace.csv).
+	// +optional
+	ConfigMapRef *corev1.ConfigMapKeySelector `json:"configMapRef,omitempty"`
+}
+
+// Validate checks the source of the trace, and parses the inline data.
+func (in *TraceSpec) Validate() error {
+	if (in.Data == "") == (in.ConfigMapRef == nil) {
+		return errors.New("expected exactly one of data and configMapRef")
+	}
+
+	if in.Data != "" {
+		if _, err := ParseTrace(in.Data); err != nil {
+			return errors.Wrapf(err, "invalid data")
+		}
+	}
+
+	return nil
+}
+
+// TraceRecord is a task of the trace.
+type TraceRecord struct {
+	// Offset is the arrival time of the task, relative to the first task of the trace.
+	Offset time.Duration
+
+	// Inputs are the parameters of the task.
+	Inputs UserInputs
+}
+
+// Trace is a list of records, sorted by arrival time.
+type Trace []TraceRecord
+
+// HasInputs returns true if the trace provides inputs for the tasks.
+func (in Trace) HasInputs() bool {
+	return len(in) > 0 && len(in[0].Inputs) > 0
+}
+
+// Inputs returns the inputs of the tasks, in order of arrival.
+func (in Trace) Inputs() []UserInputs {
+	inputs := make([]UserInputs, len(in))
+
+	for i, record := range in {
+		inputs[i] = record.Inputs
+	}
+
+	return inputs
+}
+
+// Timeline returns the arrival time of every task.
+func (in Trace) Timeline(start metav1.Time) Timeline {
+	timeline := make(Timeline, len(in))
+
+	for i, record := range in {
+		timeline[i] = metav1.NewTime(start.Add(record.Offset))
+	}
+
+	return timeline
+}
+
+// ParseTrace parses a trace in CSV format. See TraceSpec for the details.
+func ParseTrace(data string) (Trace, error) {
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read header")
+	}
+
+	var (
+		trace   Trace
+		arrival []time.Time
+	)
+
+	for line := 2; ; line++ {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot read record")
+		}
+
+		at, err := parseTraceTime(fields[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", line)
+		}
+
+		record := TraceRecord{Inputs: make(UserInputs, len(fields)-1)}
+
+		for i, field := range fields[1:] {
+			raw, err := json.Marshal(field)
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d", line)
+			}
+
+			record.Inputs[header[i+1]] = &apiextensionsv1.JSON{Raw: raw}
+		}
+
+		trace = append(trace, record)
+		arrival = append(arrival, at)
+	}
+
+	if len(trace) == 0 {
+		return nil, errors.New("trace has no records")
+	}
+
+	// the arrival times are relative to the earliest task.
+	earliest := arrival[0]
+
+	for i := range trace {
+		if arrival[i].Before(earliest) {
+			earliest = arrival[i]
+		}
+	}
+
+	for i := range trace {
+		trace[i].Offset = arrival[i].Sub(earliest)
+	}
+
+	sort.SliceStable(trace, func(i, j int) bool {
+		return trace[i].Offset < trace[j].Offset
+	})
+
+	return trace, nil
+}
+
+// parseTraceTime parses the arrival time of a task. Durations are represented as offsets from the zero time.
+func parseTraceTime(value string) (time.Time, error) {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Time{}.Add(time.Duration(seconds * float64(time.Second))), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Time{}.Add(d), nil
+	}
+
+	return time.Time{}, errors.Errorf("invalid arrival time '%s'", value)
 }
 
 // DefaultStartingDeadlineSeconds hints to abort the experiment if the schedule is skewed more than 1 minuted.
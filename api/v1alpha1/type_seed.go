@@ -0,0 +1,196 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// SeedSpec populates the system under test with synthetic data, before the actual experiment.
+// The seeding is split into chunks that run in parallel, as the services of a Cluster. The progress of the action
+// is the percentage of the completed chunks.
+type SeedSpec struct {
+	// Files generates random files onto the TestData volume of the scenario.
+	// +optional
+	Files *SeedFilesSpec `json:"files,omitempty"`
+
+	// Database loads rows into a target database.
+	// +optional
+	Database *SeedDatabaseSpec `json:"database,omitempty"`
+
+	// Parallelism is the number of chunks that the seeding is split into. Defaults to DefaultSeedParallelism.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Parallelism int `json:"parallelism,omitempty"`
+}
+
+// DefaultSeedParallelism is the number of chunks that the seeding is split into, if not set by the user.
+const DefaultSeedParallelism = 4
+
+// SeedFilesSpec generates random files.
+type SeedFilesSpec struct {
+	// Size is the total size of the generated files (e.g, 10Gi).
+	Size resource.Quantity `json:"size"`
+
+	// Path is the directory, relative to the root of the TestData volume, where the files are generated.
+	// Every chunk generates a single file (chunk-<index>). Defaults to the name of the action.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// SeedDatabaseSpec loads rows into a database.
+type SeedDatabaseSpec struct {
+	DatabaseConnection `json:",inline"`
+
+	// Table is the table where the rows are loaded. It is created if it does not exist, with an integer key (id)
+	// and a text value (payload). Defaults to DefaultSeedTable.
+	// +optional
+	Table string `json:"table,omitempty"`
+
+	// Rows is the total number of rows.
+	// +kubebuilder:validation:Minimum=1
+	Rows int64 `json:"rows"`
+
+	// RowSize is the size, in bytes, of the payload of every row. Defaults to DefaultSeedRowSize.
+	// +optional
+	RowSize int `json:"rowSize,omitempty"`
+}
+
+const (
+	// DefaultSeedTable is the table where the rows are loaded, if not set by the user.
+	DefaultSeedTable = "frisbee_seed"
+
+	// DefaultSeedRowSize is the size of the payload of every row, if not set by the user.
+	DefaultSeedRowSize = 100
+)
+
+// SeedChunk is the part of the seeding that is assigned to a single job.
+type SeedChunk struct {
+	// Index is the position of the chunk.
+	Index int
+
+	// Offset is the first byte (for files), or the first row (for databases) of the chunk.
+	Offset int64
+
+	// Count is the number of bytes (for files), or rows (for databases) of the chunk.
+	Count int64
+}
+
+// Chunks splits the seeding into equal parts. The remainder is assigned to the last chunk.
+// Chunks that would be empty are omitted.
+func (in *SeedSpec) Chunks() []SeedChunk {
+	var total int64
+
+	switch {
+	case in.Files != nil:
+		total = in.Files.Size.Value()
+	case in.Database != nil:
+		total = in.Database.Rows
+	}
+
+	parallelism := int64(in.GetParallelism())
+	if parallelism > total {
+		parallelism = total
+	}
+
+	chunks := make([]SeedChunk, 0, parallelism)
+
+	for i := int64(0); i < parallelism; i++ {
+		chunk := SeedChunk{Index: int(i), Offset: i * (total / parallelism), Count: total / parallelism}
+
+		if i == parallelism-1 {
+			chunk.Count = total - chunk.Offset
+		}
+
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks
+}
+
+// GetParallelism returns the number of chunks that the seeding is split into.
+func (in *SeedSpec) GetParallelism() int {
+	if in.Parallelism <= 0 {
+		return DefaultSeedParallelism
+	}
+
+	return in.Parallelism
+}
+
+// GetTable returns the table where the rows are loaded.
+func (in *SeedDatabaseSpec) GetTable() string {
+	if in.Table == "" {
+		return DefaultSeedTable
+	}
+
+	return in.Table
+}
+
+// GetRowSize returns the size of the payload of every row.
+func (in *SeedDatabaseSpec) GetRowSize() int {
+	if in.RowSize <= 0 {
+		return DefaultSeedRowSize
+	}
+
+	return in.RowSize
+}
+
+// seedTableName restricts the tables to plain identifiers, as they are inlined into the SQL statements.
+var seedTableName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// CheckSeed validates the seeding of an action.
+func CheckSeed(seed *SeedSpec) error {
+	switch {
+	case seed.Files == nil && seed.Database == nil:
+		return errors.New("either files or database must be set")
+	case seed.Files != nil && seed.Database != nil:
+		return errors.New("files and database are mutually exclusive")
+	case seed.Parallelism < 0:
+		return errors.Errorf("invalid parallelism '%d'", seed.Parallelism)
+	}
+
+	if files := seed.Files; files != nil {
+		if files.Size.Value() <= 0 {
+			return errors.Errorf("invalid size '%s'", files.Size.String())
+		}
+
+		if filepath.IsAbs(files.Path) || strings.Contains(files.Path, "..") {
+			return errors.Errorf("path '%s' must be relative to the testData volume", files.Path)
+		}
+	}
+
+	if db := seed.Database; db != nil {
+		if err := CheckDatabaseConnection(&db.DatabaseConnection); err != nil {
+			return err
+		}
+
+		if db.Table != "" && !seedTableName.MatchString(db.Table) {
+			return errors.Errorf("invalid table '%s'", db.Table)
+		}
+
+		if db.Rows <= 0 {
+			return errors.Errorf("invalid rows '%d'", db.Rows)
+		}
+	}
+
+	return nil
+}
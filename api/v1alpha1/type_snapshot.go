@@ -0,0 +1,149 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// SnapshotSpec takes CSI VolumeSnapshots of the volumes (PersistentVolumeClaims) of the system under test,
+// typically after a setup phase. The snapshots are not owned by the scenario, and therefore they survive across
+// runs, until they are explicitly deleted.
+type SnapshotSpec struct {
+	// Set is the name of the snapshot set. Subsequent runs refer to the set in order to restore the volumes.
+	// The snapshot of every claim is named <set>-<claim>.
+	Set string `json:"set"`
+
+	// Claims are the names of the PersistentVolumeClaims, in the namespace of the scenario, to snapshot.
+	// +kubebuilder:validation:MinItems=1
+	Claims []string `json:"claims"`
+
+	// VolumeSnapshotClassName is the class of the snapshots. If empty, the default class of the CSI driver is used.
+	// +optional
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty"`
+
+	// Timeout is the time to wait for the snapshots to become ready to use. Defaults to DefaultSnapshotTimeout.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// RestoreSpec provisions the volumes (PersistentVolumeClaims) of the system under test from a snapshot set,
+// typically at the start of a run. The restored claims have the names and the specs of the original claims,
+// and therefore the services of the scenario can mount them as usual.
+type RestoreSpec struct {
+	// Set is the name of the snapshot set to restore from.
+	Set string `json:"set"`
+
+	// Claims limits the restoration to the given claims. If empty, all the claims of the set are restored.
+	// +optional
+	Claims []string `json:"claims,omitempty"`
+
+	// StorageClassName overrides the storage class of the restored claims.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+}
+
+const (
+	// AnnotationSnapshotClaim points to the claim that a VolumeSnapshot is taken from.
+	AnnotationSnapshotClaim = "scenario.frisbee.dev/snapshot-claim"
+
+	// AnnotationSnapshotClaimSpec holds the spec of the claim that a VolumeSnapshot is taken from, so that
+	// the claim can be recreated by subsequent runs.
+	AnnotationSnapshotClaimSpec = "scenario.frisbee.dev/snapshot-claim-spec"
+)
+
+// DefaultSnapshotTimeout is the time to wait for the snapshots to become ready to use, if not set by the user.
+const DefaultSnapshotTimeout = 10 * time.Minute
+
+// GetTimeout returns the time to wait for the snapshots to become ready to use.
+func (in *SnapshotSpec) GetTimeout() time.Duration {
+	if in.Timeout == nil || in.Timeout.Duration <= 0 {
+		return DefaultSnapshotTimeout
+	}
+
+	return in.Timeout.Duration
+}
+
+// SnapshotName returns the name of the snapshot that holds the given claim.
+func SnapshotName(set string, claim string) string {
+	return set + "-" + claim
+}
+
+// CheckSnapshot validates the snapshot of an action.
+func CheckSnapshot(snapshot *SnapshotSpec) error {
+	if err := checkSnapshotSet(snapshot.Set); err != nil {
+		return err
+	}
+
+	if len(snapshot.Claims) == 0 {
+		return errors.New("no claims to snapshot")
+	}
+
+	if err := checkSnapshotClaims(snapshot.Set, snapshot.Claims); err != nil {
+		return err
+	}
+
+	if snapshot.Timeout != nil && snapshot.Timeout.Duration < 0 {
+		return errors.Errorf("invalid timeout '%s'", snapshot.Timeout.Duration)
+	}
+
+	return nil
+}
+
+// CheckRestore validates the restoration of an action.
+func CheckRestore(restore *RestoreSpec) error {
+	if err := checkSnapshotSet(restore.Set); err != nil {
+		return err
+	}
+
+	return checkSnapshotClaims(restore.Set, restore.Claims)
+}
+
+func checkSnapshotSet(set string) error {
+	if set == "" {
+		return errors.New("empty snapshot set")
+	}
+
+	// the set is used as a label value.
+	if errs := validation.IsDNS1123Label(set); len(errs) > 0 {
+		return errors.Errorf("invalid snapshot set '%s': %v", set, errs)
+	}
+
+	return nil
+}
+
+func checkSnapshotClaims(set string, claims []string) error {
+	unique := make(map[string]struct{}, len(claims))
+
+	for _, claim := range claims {
+		if _, exists := unique[claim]; exists {
+			return errors.Errorf("duplicate claim '%s'", claim)
+		}
+
+		unique[claim] = struct{}{}
+
+		if errs := validation.IsDNS1123Subdomain(SnapshotName(set, claim)); len(errs) > 0 {
+			return errors.Errorf("invalid snapshot name for claim '%s': %v", claim, errs)
+		}
+	}
+
+	return nil
+}
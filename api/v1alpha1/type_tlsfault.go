@@ -0,0 +1,93 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// TLSFaultType is the defect of the certificate that replaces the certificate of a service.
+type TLSFaultType string
+
+const (
+	// TLSFaultExpired is a certificate whose validity period has ended.
+	TLSFaultExpired TLSFaultType = "Expired"
+
+	// TLSFaultNotYetValid is a certificate whose validity period has not started.
+	TLSFaultNotYetValid TLSFaultType = "NotYetValid"
+
+	// TLSFaultWrongHost is a certificate that is issued for hosts other than the hosts of the service.
+	TLSFaultWrongHost TLSFaultType = "WrongHost"
+
+	// TLSFaultUntrusted is a certificate that is issued by an unknown authority, even if an issuer is given.
+	TLSFaultUntrusted TLSFaultType = "Untrusted"
+)
+
+// TLSFaultSpec swaps the certificate of a TLS secret (kubernetes.io/tls) with a faulty one for a duration, and
+// restores the original certificate afterwards, in order to test how the services handle failed rotations.
+// The services must reload their certificates from the secret (e.g, a mounted volume) for the fault to take effect.
+//
+// The original data of the secret are kept in a backup secret (see TLSBackupName) while the fault lasts.
+// If the controller is interrupted, the backup secret is left in place, and the original certificate must be
+// restored from it.
+type TLSFaultSpec struct {
+	// Secret is the TLS secret of the service, in the namespace of the scenario.
+	Secret string `json:"secret"`
+
+	// Fault is the defect of the faulty certificate.
+	// +kubebuilder:validation:Enum=Expired;NotYetValid;WrongHost;Untrusted
+	Fault TLSFaultType `json:"fault"`
+
+	// Duration is how long the faulty certificate remains in place.
+	Duration metav1.Duration `json:"duration"`
+
+	// IssuerSecret is a TLS secret with the certificate and the key of the authority that signs the faulty
+	// certificate. If it is given, the faulty certificate differs from the original one only in the fault.
+	// Otherwise, the faulty certificate is self-signed.
+	// +optional
+	IssuerSecret string `json:"issuerSecret,omitempty"`
+}
+
+// TLSBackupName returns the name of the secret that keeps the original data of a secret, while a fault lasts.
+func TLSBackupName(secret string) string {
+	return secret + "-frisbee-backup"
+}
+
+// CheckTLSFault validates the TLS fault of an action.
+func CheckTLSFault(fault *TLSFaultSpec) error {
+	if errs := validation.IsDNS1123Subdomain(TLSBackupName(fault.Secret)); len(errs) > 0 {
+		return errors.Errorf("invalid secret '%s': %v", fault.Secret, errs)
+	}
+
+	switch fault.Fault {
+	case TLSFaultExpired, TLSFaultNotYetValid, TLSFaultWrongHost, TLSFaultUntrusted:
+	default:
+		return errors.Errorf("unknown fault '%s'", fault.Fault)
+	}
+
+	if fault.Duration.Duration <= 0 {
+		return errors.Errorf("invalid duration '%s'", fault.Duration.Duration)
+	}
+
+	if fault.IssuerSecret == fault.Secret {
+		return errors.New("the secret cannot be its own issuer")
+	}
+
+	return nil
+}
@@ -18,6 +18,10 @@ package v1alpha1
 
 import (
 	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // TolerateSpec specifies the system's ability to continue operating despite failures or malfunctions.
@@ -28,6 +32,13 @@ type TolerateSpec struct {
 	// +optional
 	// +kubebuilder:validation:Minimum=1
 	FailedJobs int `json:"failedJobs"`
+
+	// FailedZones indicate the number of failure domains (zones) in which failures are tolerated.
+	// If the failed jobs span more zones, the cluster fails, regardless of FailedJobs.
+	// For example, set FailedZones to 1 for tolerating at most one zone down. It applies only to Clusters.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	FailedZones int `json:"failedZones,omitempty"`
 }
 
 func (in *TolerateSpec) String() string {
@@ -35,5 +46,99 @@ func (in *TolerateSpec) String() string {
 		return "None"
 	}
 
+	if in.FailedZones > 0 {
+		return fmt.Sprintf("Failed Jobs:%d Failed Zones:%d", in.FailedJobs, in.FailedZones)
+	}
+
 	return fmt.Sprintf("Failed Jobs:%d", in.FailedJobs)
 }
+
+// RetryCondition is a class of failures for which the failed services are recreated.
+// +kubebuilder:validation:Enum=Failed;Evicted
+type RetryCondition string
+
+const (
+	// RetryOnFailed covers the services that have failed by themselves (e.g, the container exited with an error).
+	RetryOnFailed RetryCondition = "Failed"
+
+	// RetryOnEvicted covers the services whose Pod has been evicted, or deleted, before completion.
+	RetryOnEvicted RetryCondition = "Evicted"
+)
+
+const (
+	// DefaultRetryBackoff is the delay before the first recreation of a failed service.
+	DefaultRetryBackoff = 10 * time.Second
+
+	// MaxRetryBackoff caps the delay between the recreations of a failed service.
+	MaxRetryBackoff = 5 * time.Minute
+)
+
+// RetryPolicySpec recreates the failed services of a Cluster, before they are counted as failures (see Tolerate).
+// It is meant for transient failures (e.g, a flaky pod) that should not fail the whole Cluster.
+type RetryPolicySpec struct {
+	// MaxRetries is the number of times that every service can be recreated.
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries int `json:"maxRetries"`
+
+	// Backoff is the delay before the first recreation of a service. The delay doubles on every subsequent
+	// recreation, up to 5 minutes. Defaults to 10s.
+	// +optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+
+	// RetryOn limits the recreations to specific failures. Defaults to all failures.
+	// +optional
+	RetryOn []RetryCondition `json:"retryOn,omitempty"`
+}
+
+func (in RetryPolicySpec) Validate() error {
+	if in.MaxRetries < 0 {
+		return errors.Errorf("maxRetries must be non-negative")
+	}
+
+	if in.Backoff != nil && in.Backoff.Duration <= 0 {
+		return errors.Errorf("backoff must be positive")
+	}
+
+	for _, cond := range in.RetryOn {
+		switch cond {
+		case RetryOnFailed, RetryOnEvicted:
+		default:
+			return errors.Errorf("unknown retry condition '%s'", cond)
+		}
+	}
+
+	return nil
+}
+
+// Covers returns true if the policy recreates the services that fail due to the given condition.
+func (in RetryPolicySpec) Covers(cond RetryCondition) bool {
+	if len(in.RetryOn) == 0 {
+		return true
+	}
+
+	for _, retryOn := range in.RetryOn {
+		if retryOn == cond {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Delay returns the backoff before the given recreation of a service, starting from 1.
+func (in RetryPolicySpec) Delay(retry int) time.Duration {
+	delay := DefaultRetryBackoff
+	if in.Backoff != nil {
+		delay = in.Backoff.Duration
+	}
+
+	for i := 1; i < retry && delay < MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > MaxRetryBackoff {
+		return MaxRetryBackoff
+	}
+
+	return delay
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/pkg/errors"
+)
+
+// TracingBackend is the backend that stores the traces of the system under test.
+type TracingBackend string
+
+const (
+	// TracingTempo stores the traces in Grafana Tempo.
+	TracingTempo TracingBackend = "Tempo"
+
+	// TracingJaeger stores the traces in Jaeger (all-in-one).
+	TracingJaeger TracingBackend = "Jaeger"
+)
+
+// TracingDatasourceName is the name of the Grafana datasource that points to the tracing backend.
+const TracingDatasourceName = "Traces"
+
+// TracingSpec deploys a tracing backend, and points the services of the scenario to its OTLP endpoint.
+type TracingSpec struct {
+	// Backend is the backend that stores the traces. Defaults to Tempo.
+	// +kubebuilder:validation:Enum=Tempo;Jaeger
+	// +optional
+	Backend TracingBackend `json:"backend,omitempty"`
+
+	// DisableInjection stops the injection of the OpenTelemetry environment (e.g, OTEL_EXPORTER_OTLP_ENDPOINT) into
+	// the services of the scenario. The backend remains reachable to the services that are configured explicitly.
+	// +optional
+	DisableInjection bool `json:"disableInjection,omitempty"`
+}
+
+// GetBackend returns the backend that stores the traces.
+func (in *TracingSpec) GetBackend() TracingBackend {
+	if in.Backend == "" {
+		return TracingTempo
+	}
+
+	return in.Backend
+}
+
+// CheckTracing validates the tracing backend of the scenario.
+func CheckTracing(scenario *Scenario) error {
+	tracing := scenario.Spec.Tracing
+	if tracing == nil {
+		return nil
+	}
+
+	switch tracing.GetBackend() {
+	case TracingTempo, TracingJaeger:
+	default:
+		return errors.Errorf("unknown backend '%s'", tracing.Backend)
+	}
+
+	for _, ds := range scenario.Spec.Datasources {
+		if ds.Name == TracingDatasourceName {
+			return errors.Errorf("datasource '%s' is reserved for the tracing backend", ds.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// TriggerSpec blocks the action until an external system (e.g, a CI pipeline, or a human operator) sends a signed
+// callback to the webhook of the controller, in order to gate the stages of a scenario.
+//
+// The callback is a POST to TriggerPath/<namespace>/<scenario>/<action>, whose body is a TriggerPayload.
+// The body is signed with HMAC-SHA256, using the key of the Secret, and the hex-encoded signature is given in the
+// TriggerSignatureHeader as "sha256=<signature>".
+type TriggerSpec struct {
+	// Secret is the name of the Secret, in the namespace of the scenario, that holds the key of the signatures.
+	Secret string `json:"secret"`
+
+	// Key is the entry of the Secret that holds the key. Defaults to DefaultTriggerKey.
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// Timeout fails the action if no callback is received within the given duration.
+	// Defaults to DefaultTriggerTimeout.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+const (
+	// TriggerPath is the path of the webhook that receives the callbacks.
+	TriggerPath = "/triggers/"
+
+	// TriggerSignatureHeader is the header that carries the signature of the callback.
+	TriggerSignatureHeader = "X-Frisbee-Signature"
+
+	// DefaultTriggerKey is the entry of the Secret that holds the key, if not set by the user.
+	DefaultTriggerKey = "key"
+
+	// DefaultTriggerTimeout is how long the action waits for the callback, if not set by the user.
+	DefaultTriggerTimeout = 1 * time.Hour
+)
+
+// TriggerPayload is the body of the callback.
+type TriggerPayload struct {
+	// Action is the name of the action to release. It must match the path of the callback, so that a signed
+	// payload cannot be replayed against another action that shares the same key.
+	Action string `json:"action"`
+
+	// Reject fails the action, instead of releasing it.
+	// +optional
+	Reject bool `json:"reject,omitempty"`
+
+	// Message is a free-form note, recorded along with the completion of the action.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// GetKey returns the entry of the Secret that holds the key.
+func (in *TriggerSpec) GetKey() string {
+	if in.Key == "" {
+		return DefaultTriggerKey
+	}
+
+	return in.Key
+}
+
+// GetTimeout returns how long the action waits for the callback.
+func (in *TriggerSpec) GetTimeout() time.Duration {
+	if in.Timeout == nil {
+		return DefaultTriggerTimeout
+	}
+
+	return in.Timeout.Duration
+}
+
+// CheckTrigger validates the trigger of an action.
+func CheckTrigger(trigger *TriggerSpec) error {
+	if errs := validation.IsDNS1123Subdomain(trigger.Secret); len(errs) > 0 {
+		return errors.Errorf("invalid secret '%s': %v", trigger.Secret, errs)
+	}
+
+	if errs := validation.IsConfigMapKey(trigger.GetKey()); len(errs) > 0 {
+		return errors.Errorf("invalid key '%s': %v", trigger.Key, errs)
+	}
+
+	if trigger.Timeout != nil && trigger.Timeout.Duration <= 0 {
+		return errors.Errorf("invalid timeout '%s'", trigger.Timeout.Duration)
+	}
+
+	return nil
+}
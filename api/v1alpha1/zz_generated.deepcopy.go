@@ -24,11 +24,29 @@ package v1alpha1
 import (
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
-	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerLatencySpec) DeepCopyInto(out *APIServerLatencySpec) {
+	*out = *in
+	out.Latency = in.Latency
+	out.Jitter = in.Jitter
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerLatencySpec.
+func (in *APIServerLatencySpec) DeepCopy() *APIServerLatencySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerLatencySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Action) DeepCopyInto(out *Action) {
 	*out = *in
@@ -42,6 +60,11 @@ func (in *Action) DeepCopyInto(out *Action) {
 		*out = new(ConditionalExpr)
 		**out = **in
 	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	if in.EmbedActions != nil {
 		in, out := &in.EmbedActions, &out.EmbedActions
 		*out = new(EmbedActions)
@@ -59,6 +82,89 @@ func (in *Action) DeepCopy() *Action {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionStatus) DeepCopyInto(out *ActionStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+	if in.AssertionFailure != nil {
+		in, out := &in.AssertionFailure, &out.AssertionFailure
+		*out = new(AssertionFailure)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionStatus.
+func (in *ActionStatus) DeepCopy() *ActionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertRecord) DeepCopyInto(out *AlertRecord) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertRecord.
+func (in *AlertRecord) DeepCopy() *AlertRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AssertSQLSpec) DeepCopyInto(out *AssertSQLSpec) {
+	*out = *in
+	in.DatabaseConnection.DeepCopyInto(&out.DatabaseConnection)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AssertSQLSpec.
+func (in *AssertSQLSpec) DeepCopy() *AssertSQLSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AssertSQLSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AssertionFailure) DeepCopyInto(out *AssertionFailure) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AssertionFailure.
+func (in *AssertionFailure) DeepCopy() *AssertionFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(AssertionFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Call) DeepCopyInto(out *Call) {
 	*out = *in
@@ -313,7 +419,9 @@ func (in *CascadeStatus) DeepCopyInto(out *CascadeStatus) {
 	if in.QueuedJobs != nil {
 		in, out := &in.QueuedJobs, &out.QueuedJobs
 		*out = make([]ChaosSpec, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.ExpectedTimeline != nil {
 		in, out := &in.ExpectedTimeline, &out.ExpectedTimeline
@@ -340,7 +448,7 @@ func (in *Chaos) DeepCopyInto(out *Chaos) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -397,6 +505,11 @@ func (in *ChaosList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChaosSpec) DeepCopyInto(out *ChaosSpec) {
 	*out = *in
+	if in.CleanupProbe != nil {
+		in, out := &in.CleanupProbe, &out.CleanupProbe
+		*out = new(CleanupProbe)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChaosSpec.
@@ -417,6 +530,10 @@ func (in *ChaosStatus) DeepCopyInto(out *ChaosStatus) {
 		in, out := &in.LastScheduleTime, &out.LastScheduleTime
 		*out = (*in).DeepCopy()
 	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChaosStatus.
@@ -429,6 +546,73 @@ func (in *ChaosStatus) DeepCopy() *ChaosStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CleanupProbe) DeepCopyInto(out *CleanupProbe) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupProbe.
+func (in *CleanupProbe) DeepCopy() *CleanupProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(CleanupProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClockSkewSpec) DeepCopyInto(out *ClockSkewSpec) {
+	*out = *in
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Offset = in.Offset
+	if in.DriftRate != nil {
+		in, out := &in.DriftRate, &out.DriftRate
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	out.Duration = in.Duration
+	if in.Step != nil {
+		in, out := &in.Step, &out.Step
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClockSkewSpec.
+func (in *ClockSkewSpec) DeepCopy() *ClockSkewSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClockSkewSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClockSkewStep) DeepCopyInto(out *ClockSkewStep) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClockSkewStep.
+func (in *ClockSkewStep) DeepCopy() *ClockSkewStep {
+	if in == nil {
+		return nil
+	}
+	out := new(ClockSkewStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cluster) DeepCopyInto(out *Cluster) {
 	*out = *in
@@ -502,6 +686,13 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(DistributionSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Variants != nil {
+		in, out := &in.Variants, &out.Variants
+		*out = make([]ClusterVariant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
 		*out = new(ResourceDistributionSpec)
@@ -517,6 +708,16 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(PlacementSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Topology != nil {
+		in, out := &in.Topology, &out.Topology
+		*out = new(TopologySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DisruptionBudget != nil {
+		in, out := &in.DisruptionBudget, &out.DisruptionBudget
+		*out = new(DisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Suspend != nil {
 		in, out := &in.Suspend, &out.Suspend
 		*out = new(bool)
@@ -532,6 +733,16 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(TolerateSpec)
 		**out = **in
 	}
+	if in.Lifetime != nil {
+		in, out := &in.Lifetime, &out.Lifetime
+		*out = new(LifetimeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
@@ -550,10 +761,8 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 	in.Lifecycle.DeepCopyInto(&out.Lifecycle)
 	if in.QueuedJobs != nil {
 		in, out := &in.QueuedJobs, &out.QueuedJobs
-		*out = make([]ServiceSpec, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+		*out = make([]QueuedJob, len(*in))
+		copy(*out, *in)
 	}
 	if in.DefaultDistribution != nil {
 		in, out := &in.DefaultDistribution, &out.DefaultDistribution
@@ -568,6 +777,24 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		}
 	}
 	in.LastScheduleTime.DeepCopyInto(&out.LastScheduleTime)
+	if in.ExpectedCompletionTime != nil {
+		in, out := &in.ExpectedCompletionTime, &out.ExpectedCompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PerZone != nil {
+		in, out := &in.PerZone, &out.PerZone
+		*out = make(map[string]DomainStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = make(map[string]JobRetry, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -580,6 +807,47 @@ func (in *ClusterStatus) DeepCopy() *ClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterVariant) DeepCopyInto(out *ClusterVariant) {
+	*out = *in
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make([]UserInputs, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make(UserInputs, len(*in))
+				for key, val := range *in {
+					var outVal *v1.JSON
+					if val == nil {
+						(*out)[key] = nil
+					} else {
+						in, out := &val, &outVal
+						*out = new(v1.JSON)
+						(*in).DeepCopyInto(*out)
+					}
+					(*out)[key] = outVal
+				}
+			}
+		}
+	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]SpecPatch, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterVariant.
+func (in *ClusterVariant) DeepCopy() *ClusterVariant {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterVariant)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConditionalExpr) DeepCopyInto(out *ConditionalExpr) {
 	*out = *in
@@ -595,6 +863,112 @@ func (in *ConditionalExpr) DeepCopy() *ConditionalExpr {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsistencySpec) DeepCopyInto(out *ConsistencySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsistencySpec.
+func (in *ConsistencySpec) DeepCopy() *ConsistencySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsistencySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerHooks) DeepCopyInto(out *ContainerHooks) {
+	*out = *in
+	if in.PostStart != nil {
+		in, out := &in.PostStart, &out.PostStart
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PreStop != nil {
+		in, out := &in.PreStop, &out.PreStop
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerHooks.
+func (in *ContainerHooks) DeepCopy() *ContainerHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneFaultSpec) DeepCopyInto(out *ControlPlaneFaultSpec) {
+	*out = *in
+	out.Duration = in.Duration
+	if in.APIServerLatency != nil {
+		in, out := &in.APIServerLatency, &out.APIServerLatency
+		*out = new(APIServerLatencySpec)
+		**out = **in
+	}
+	if in.Pause != nil {
+		in, out := &in.Pause, &out.Pause
+		*out = new(ControlPlanePauseSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneFaultSpec.
+func (in *ControlPlaneFaultSpec) DeepCopy() *ControlPlaneFaultSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneFaultSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlanePauseSpec) DeepCopyInto(out *ControlPlanePauseSpec) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ControlPlaneComponent, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlanePauseSpec.
+func (in *ControlPlanePauseSpec) DeepCopy() *ControlPlanePauseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlanePauseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseConnection) DeepCopyInto(out *DatabaseConnection) {
+	*out = *in
+	if in.PasswordRef != nil {
+		in, out := &in.PasswordRef, &out.PasswordRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseConnection.
+func (in *DatabaseConnection) DeepCopy() *DatabaseConnection {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseConnection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Decorators) DeepCopyInto(out *Decorators) {
 	*out = *in
@@ -675,11 +1049,83 @@ func (in *DeleteSpec) DeepCopy() *DeleteSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DistParamsPareto) DeepCopyInto(out *DistParamsPareto) {
+func (in *DiskFaultSpec) DeepCopyInto(out *DiskFaultSpec) {
 	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DistParamsPareto.
+	in.Selector.DeepCopyInto(&out.Selector)
+	out.Duration = in.Duration
+	if in.Latency != nil {
+		in, out := &in.Latency, &out.Latency
+		*out = new(IOLatencySpec)
+		**out = **in
+	}
+	if in.Errno != nil {
+		in, out := &in.Errno, &out.Errno
+		*out = new(IOErrnoSpec)
+		**out = **in
+	}
+	if in.Fill != nil {
+		in, out := &in.Fill, &out.Fill
+		*out = new(DiskFillSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskFaultSpec.
+func (in *DiskFaultSpec) DeepCopy() *DiskFaultSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskFaultSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskFillSpec) DeepCopyInto(out *DiskFillSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskFillSpec.
+func (in *DiskFillSpec) DeepCopy() *DiskFillSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskFillSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisruptionBudgetSpec) DeepCopyInto(out *DisruptionBudgetSpec) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionBudgetSpec.
+func (in *DisruptionBudgetSpec) DeepCopy() *DisruptionBudgetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionBudgetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DistParamsPareto) DeepCopyInto(out *DistParamsPareto) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DistParamsPareto.
 func (in *DistParamsPareto) DeepCopy() *DistParamsPareto {
 	if in == nil {
 		return nil
@@ -709,6 +1155,21 @@ func (in *DistributionSpec) DeepCopy() *DistributionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainStatus) DeepCopyInto(out *DomainStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainStatus.
+func (in *DomainStatus) DeepCopy() *DomainStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EmbedActions) DeepCopyInto(out *EmbedActions) {
 	*out = *in
@@ -742,6 +1203,66 @@ func (in *EmbedActions) DeepCopyInto(out *EmbedActions) {
 		*out = new(CallSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Seed != nil {
+		in, out := &in.Seed, &out.Seed
+		*out = new(SeedSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Snapshot != nil {
+		in, out := &in.Snapshot, &out.Snapshot
+		*out = new(SnapshotSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(RestoreSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AssertSQL != nil {
+		in, out := &in.AssertSQL, &out.AssertSQL
+		*out = new(AssertSQLSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Consistency != nil {
+		in, out := &in.Consistency, &out.Consistency
+		*out = new(ConsistencySpec)
+		**out = **in
+	}
+	if in.ClockSkew != nil {
+		in, out := &in.ClockSkew, &out.ClockSkew
+		*out = new(ClockSkewSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DiskFault != nil {
+		in, out := &in.DiskFault, &out.DiskFault
+		*out = new(DiskFaultSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLSFault != nil {
+		in, out := &in.TLSFault, &out.TLSFault
+		*out = new(TLSFaultSpec)
+		**out = **in
+	}
+	if in.RegistryOutage != nil {
+		in, out := &in.RegistryOutage, &out.RegistryOutage
+		*out = new(RegistryOutageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControlPlaneFault != nil {
+		in, out := &in.ControlPlaneFault, &out.ControlPlaneFault
+		*out = new(ControlPlaneFaultSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RawChaos != nil {
+		in, out := &in.RawChaos, &out.RawChaos
+		*out = new(ChaosSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Trigger != nil {
+		in, out := &in.Trigger, &out.Trigger
+		*out = new(TriggerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmbedActions.
@@ -765,7 +1286,12 @@ func (in *EmbedSpecs) DeepCopyInto(out *EmbedSpecs) {
 	if in.Chaos != nil {
 		in, out := &in.Chaos, &out.Chaos
 		*out = new(ChaosSpec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scenario != nil {
+		in, out := &in.Scenario, &out.Scenario
+		*out = new(ScenarioSpec)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -780,198 +1306,640 @@ func (in *EmbedSpecs) DeepCopy() *EmbedSpecs {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GenerateObjectFromTemplate) DeepCopyInto(out *GenerateObjectFromTemplate) {
+func (in *FrisbeeConfig) DeepCopyInto(out *FrisbeeConfig) {
 	*out = *in
-	if in.Inputs != nil {
-		in, out := &in.Inputs, &out.Inputs
-		*out = make([]UserInputs, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = make(UserInputs, len(*in))
-				for key, val := range *in {
-					var outVal *apiextensionsv1.JSON
-					if val == nil {
-						(*out)[key] = nil
-					} else {
-						in, out := &val, &outVal
-						*out = new(apiextensionsv1.JSON)
-						(*in).DeepCopyInto(*out)
-					}
-					(*out)[key] = outVal
-				}
-			}
-		}
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenerateObjectFromTemplate.
-func (in *GenerateObjectFromTemplate) DeepCopy() *GenerateObjectFromTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrisbeeConfig.
+func (in *FrisbeeConfig) DeepCopy() *FrisbeeConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(GenerateObjectFromTemplate)
+	out := new(FrisbeeConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FrisbeeConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Lifecycle) DeepCopyInto(out *Lifecycle) {
+func (in *FrisbeeConfigList) DeepCopyInto(out *FrisbeeConfigList) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FrisbeeConfig, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Lifecycle.
-func (in *Lifecycle) DeepCopy() *Lifecycle {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrisbeeConfigList.
+func (in *FrisbeeConfigList) DeepCopy() *FrisbeeConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(Lifecycle)
+	out := new(FrisbeeConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FrisbeeConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MatchBy) DeepCopyInto(out *MatchBy) {
+func (in *FrisbeeConfigSpec) DeepCopyInto(out *FrisbeeConfigSpec) {
 	*out = *in
-	if in.ByName != nil {
-		in, out := &in.ByName, &out.ByName
-		*out = make(map[string][]string, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
-		}
-	}
-	if in.ByCluster != nil {
-		in, out := &in.ByCluster, &out.ByCluster
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
+	if in.DebugScenarios != nil {
+		in, out := &in.DebugScenarios, &out.DebugScenarios
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatchBy.
-func (in *MatchBy) DeepCopy() *MatchBy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrisbeeConfigSpec.
+func (in *FrisbeeConfigSpec) DeepCopy() *FrisbeeConfigSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MatchBy)
+	out := new(FrisbeeConfigSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MatchOutputs) DeepCopyInto(out *MatchOutputs) {
+func (in *FrisbeeConfigStatus) DeepCopyInto(out *FrisbeeConfigStatus) {
 	*out = *in
-	if in.Stdout != nil {
-		in, out := &in.Stdout, &out.Stdout
-		*out = new(string)
-		**out = **in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.Stderr != nil {
-		in, out := &in.Stderr, &out.Stderr
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrisbeeConfigStatus.
+func (in *FrisbeeConfigStatus) DeepCopy() *FrisbeeConfigStatus {
+	if in == nil {
+		return nil
 	}
+	out := new(FrisbeeConfigStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatchOutputs.
-func (in *MatchOutputs) DeepCopy() *MatchOutputs {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GenerateObjectFromTemplate) DeepCopyInto(out *GenerateObjectFromTemplate) {
+	*out = *in
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make([]UserInputs, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make(UserInputs, len(*in))
+				for key, val := range *in {
+					var outVal *v1.JSON
+					if val == nil {
+						(*out)[key] = nil
+					} else {
+						in, out := &val, &outVal
+						*out = new(v1.JSON)
+						(*in).DeepCopyInto(*out)
+					}
+					(*out)[key] = outVal
+				}
+			}
+		}
+	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]SpecPatch, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenerateObjectFromTemplate.
+func (in *GenerateObjectFromTemplate) DeepCopy() *GenerateObjectFromTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(MatchOutputs)
+	out := new(GenerateObjectFromTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in Parameters) DeepCopyInto(out *Parameters) {
-	{
-		in := &in
-		*out = make(Parameters, len(*in))
+func (in *GrafanaDatasource) DeepCopyInto(out *GrafanaDatasource) {
+	*out = *in
+	if in.JSONData != nil {
+		in, out := &in.JSONData, &out.JSONData
+		*out = make(map[string]*v1.JSON, len(*in))
 		for key, val := range *in {
-			var outVal *apiextensionsv1.JSON
+			var outVal *v1.JSON
 			if val == nil {
 				(*out)[key] = nil
 			} else {
 				in, out := &val, &outVal
-				*out = new(apiextensionsv1.JSON)
+				*out = new(v1.JSON)
 				(*in).DeepCopyInto(*out)
 			}
 			(*out)[key] = outVal
 		}
 	}
+	if in.SecureJSONData != nil {
+		in, out := &in.SecureJSONData, &out.SecureJSONData
+		*out = make(map[string]corev1.SecretKeySelector, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Parameters.
-func (in Parameters) DeepCopy() Parameters {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDatasource.
+func (in *GrafanaDatasource) DeepCopy() *GrafanaDatasource {
 	if in == nil {
 		return nil
 	}
-	out := new(Parameters)
+	out := new(GrafanaDatasource)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PlacementSpec) DeepCopyInto(out *PlacementSpec) {
+func (in *HostAliasSpec) DeepCopyInto(out *HostAliasSpec) {
 	*out = *in
-	if in.ConflictsWith != nil {
-		in, out := &in.ConflictsWith, &out.ConflictsWith
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Nodes != nil {
-		in, out := &in.Nodes, &out.Nodes
+	if in.Hostnames != nil {
+		in, out := &in.Hostnames, &out.Hostnames
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementSpec.
-func (in *PlacementSpec) DeepCopy() *PlacementSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostAliasSpec.
+func (in *HostAliasSpec) DeepCopy() *HostAliasSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PlacementSpec)
+	out := new(HostAliasSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in ResourceDistribution) DeepCopyInto(out *ResourceDistribution) {
-	{
-		in := &in
-		*out = make(ResourceDistribution, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = make(corev1.ResourceList, len(*in))
-				for key, val := range *in {
-					(*out)[key] = val.DeepCopy()
-				}
-			}
-		}
-	}
+func (in *IOErrnoSpec) DeepCopyInto(out *IOErrnoSpec) {
+	*out = *in
+	out.IOTarget = in.IOTarget
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceDistribution.
-func (in ResourceDistribution) DeepCopy() ResourceDistribution {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IOErrnoSpec.
+func (in *IOErrnoSpec) DeepCopy() *IOErrnoSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IOErrnoSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IOLatencySpec) DeepCopyInto(out *IOLatencySpec) {
+	*out = *in
+	out.IOTarget = in.IOTarget
+	out.Delay = in.Delay
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IOLatencySpec.
+func (in *IOLatencySpec) DeepCopy() *IOLatencySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IOLatencySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IOTarget) DeepCopyInto(out *IOTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IOTarget.
+func (in *IOTarget) DeepCopy() *IOTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(IOTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngestionSpec) DeepCopyInto(out *IngestionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngestionSpec.
+func (in *IngestionSpec) DeepCopy() *IngestionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IngestionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobRetry) DeepCopyInto(out *JobRetry) {
+	*out = *in
+	if in.RecreateAfter != nil {
+		in, out := &in.RecreateAfter, &out.RecreateAfter
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobRetry.
+func (in *JobRetry) DeepCopy() *JobRetry {
+	if in == nil {
+		return nil
+	}
+	out := new(JobRetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Lifecycle) DeepCopyInto(out *Lifecycle) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Lifecycle.
+func (in *Lifecycle) DeepCopy() *Lifecycle {
+	if in == nil {
+		return nil
+	}
+	out := new(Lifecycle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LifetimeSpec) DeepCopyInto(out *LifetimeSpec) {
+	*out = *in
+	out.Duration = in.Duration
+	if in.Min != nil {
+		in, out := &in.Min, &out.Min
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LifetimeSpec.
+func (in *LifetimeSpec) DeepCopy() *LifetimeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LifetimeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MatchBy) DeepCopyInto(out *MatchBy) {
+	*out = *in
+	if in.ByName != nil {
+		in, out := &in.ByName, &out.ByName
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.ByCluster != nil {
+		in, out := &in.ByCluster, &out.ByCluster
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatchBy.
+func (in *MatchBy) DeepCopy() *MatchBy {
+	if in == nil {
+		return nil
+	}
+	out := new(MatchBy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MatchOutputs) DeepCopyInto(out *MatchOutputs) {
+	*out = *in
+	if in.Stdout != nil {
+		in, out := &in.Stdout, &out.Stdout
+		*out = new(string)
+		**out = **in
+	}
+	if in.Stderr != nil {
+		in, out := &in.Stderr, &out.Stderr
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatchOutputs.
+func (in *MatchOutputs) DeepCopy() *MatchOutputs {
+	if in == nil {
+		return nil
+	}
+	out := new(MatchOutputs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkLinkSpec) DeepCopyInto(out *NetworkLinkSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkLinkSpec.
+func (in *NetworkLinkSpec) DeepCopy() *NetworkLinkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkLinkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NextSpec) DeepCopyInto(out *NextSpec) {
+	*out = *in
+	if in.OnSuccess != nil {
+		in, out := &in.OnSuccess, &out.OnSuccess
+		*out = new(GenerateObjectFromTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OnFailure != nil {
+		in, out := &in.OnFailure, &out.OnFailure
+		*out = new(GenerateObjectFromTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxDepth != nil {
+		in, out := &in.MaxDepth, &out.MaxDepth
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NextSpec.
+func (in *NextSpec) DeepCopy() *NextSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NextSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in Parameters) DeepCopyInto(out *Parameters) {
+	{
+		in := &in
+		*out = make(Parameters, len(*in))
+		for key, val := range *in {
+			var outVal *v1.JSON
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(v1.JSON)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Parameters.
+func (in Parameters) DeepCopy() Parameters {
+	if in == nil {
+		return nil
+	}
+	out := new(Parameters)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementSpec) DeepCopyInto(out *PlacementSpec) {
+	*out = *in
+	if in.ConflictsWith != nil {
+		in, out := &in.ConflictsWith, &out.ConflictsWith
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementSpec.
+func (in *PlacementSpec) DeepCopy() *PlacementSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PropagationSpec) DeepCopyInto(out *PropagationSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraLabels != nil {
+		in, out := &in.ExtraLabels, &out.ExtraLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExtraAnnotations != nil {
+		in, out := &in.ExtraAnnotations, &out.ExtraAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropagationSpec.
+func (in *PropagationSpec) DeepCopy() *PropagationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PropagationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueuedJob) DeepCopyInto(out *QueuedJob) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueuedJob.
+func (in *QueuedJob) DeepCopy() *QueuedJob {
+	if in == nil {
+		return nil
+	}
+	out := new(QueuedJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RampSpec) DeepCopyInto(out *RampSpec) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RampSpec.
+func (in *RampSpec) DeepCopy() *RampSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RampSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryOutageSpec) DeepCopyInto(out *RegistryOutageSpec) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	if in.Registries != nil {
+		in, out := &in.Registries, &out.Registries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryOutageSpec.
+func (in *RegistryOutageSpec) DeepCopy() *RegistryOutageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryOutageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceBudget) DeepCopyInto(out *ResourceBudget) {
+	*out = *in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.DefaultRequests != nil {
+		in, out := &in.DefaultRequests, &out.DefaultRequests
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceBudget.
+func (in *ResourceBudget) DeepCopy() *ResourceBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in ResourceDistribution) DeepCopyInto(out *ResourceDistribution) {
+	{
+		in := &in
+		*out = make(ResourceDistribution, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make(corev1.ResourceList, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val.DeepCopy()
+				}
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceDistribution.
+func (in ResourceDistribution) DeepCopy() ResourceDistribution {
 	if in == nil {
 		return nil
 	}
@@ -990,131 +1958,408 @@ func (in *ResourceDistributionSpec) DeepCopyInto(out *ResourceDistributionSpec)
 			(*out)[key] = val.DeepCopy()
 		}
 	}
-	if in.DistributionSpec != nil {
-		in, out := &in.DistributionSpec, &out.DistributionSpec
-		*out = new(DistributionSpec)
-		(*in).DeepCopyInto(*out)
+	if in.DistributionSpec != nil {
+		in, out := &in.DistributionSpec, &out.DistributionSpec
+		*out = new(DistributionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Weights != nil {
+		in, out := &in.Weights, &out.Weights
+		*out = make([]float64, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceDistributionSpec.
+func (in *ResourceDistributionSpec) DeepCopy() *ResourceDistributionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceDistributionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreSpec) DeepCopyInto(out *RestoreSpec) {
+	*out = *in
+	if in.Claims != nil {
+		in, out := &in.Claims, &out.Claims
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreSpec.
+func (in *RestoreSpec) DeepCopy() *RestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicySpec) DeepCopyInto(out *RetryPolicySpec) {
+	*out = *in
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RetryOn != nil {
+		in, out := &in.RetryOn, &out.RetryOn
+		*out = make([]RetryCondition, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicySpec.
+func (in *RetryPolicySpec) DeepCopy() *RetryPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunMetadata) DeepCopyInto(out *RunMetadata) {
+	*out = *in
+	out.Source = in.Source
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunMetadata.
+func (in *RunMetadata) DeepCopy() *RunMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(RunMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunSource) DeepCopyInto(out *RunSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunSource.
+func (in *RunSource) DeepCopy() *RunSource {
+	if in == nil {
+		return nil
+	}
+	out := new(RunSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Scenario) DeepCopyInto(out *Scenario) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Scenario.
+func (in *Scenario) DeepCopy() *Scenario {
+	if in == nil {
+		return nil
+	}
+	out := new(Scenario)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Scenario) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScenarioList) DeepCopyInto(out *ScenarioList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Scenario, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScenarioList.
+func (in *ScenarioList) DeepCopy() *ScenarioList {
+	if in == nil {
+		return nil
+	}
+	out := new(ScenarioList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScenarioList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScenarioSpec) DeepCopyInto(out *ScenarioSpec) {
+	*out = *in
+	if in.TestData != nil {
+		in, out := &in.TestData, &out.TestData
+		*out = new(TestdataVolume)
+		**out = **in
+	}
+	if in.Vars != nil {
+		in, out := &in.Vars, &out.Vars
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]Action, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Teardown != nil {
+		in, out := &in.Teardown, &out.Teardown
+		*out = make([]Action, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Abort != nil {
+		in, out := &in.Abort, &out.Abort
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Next != nil {
+		in, out := &in.Next, &out.Next
+		*out = new(NextSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkProfile != nil {
+		in, out := &in.NetworkProfile, &out.NetworkProfile
+		*out = make([]NetworkLinkSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostAliases != nil {
+		in, out := &in.HostAliases, &out.HostAliases
+		*out = make([]HostAliasSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DNSConfig != nil {
+		in, out := &in.DNSConfig, &out.DNSConfig
+		*out = new(corev1.PodDNSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageOverrides != nil {
+		in, out := &in.ImageOverrides, &out.ImageOverrides
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Propagation != nil {
+		in, out := &in.Propagation, &out.Propagation
+		*out = new(PropagationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TargetNamespaces != nil {
+		in, out := &in.TargetNamespaces, &out.TargetNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Run != nil {
+		in, out := &in.Run, &out.Run
+		*out = new(RunMetadata)
+		**out = **in
+	}
+	if in.Datasources != nil {
+		in, out := &in.Datasources, &out.Datasources
+		*out = make([]GrafanaDatasource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Ingestion != nil {
+		in, out := &in.Ingestion, &out.Ingestion
+		*out = new(IngestionSpec)
+		**out = **in
+	}
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		*out = new(TracingSpec)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(ResourceBudget)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScenarioSpec.
+func (in *ScenarioSpec) DeepCopy() *ScenarioSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScenarioSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScenarioStatus) DeepCopyInto(out *ScenarioStatus) {
+	*out = *in
+	in.Lifecycle.DeepCopyInto(&out.Lifecycle)
+	if in.ScheduledJobs != nil {
+		in, out := &in.ScheduledJobs, &out.ScheduledJobs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]ActionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TeardownJobs != nil {
+		in, out := &in.TeardownJobs, &out.TeardownJobs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NetworkLinks != nil {
+		in, out := &in.NetworkLinks, &out.NetworkLinks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = make([]AlertRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Run != nil {
+		in, out := &in.Run, &out.Run
+		*out = new(RunMetadata)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceDistributionSpec.
-func (in *ResourceDistributionSpec) DeepCopy() *ResourceDistributionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScenarioStatus.
+func (in *ScenarioStatus) DeepCopy() *ScenarioStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceDistributionSpec)
+	out := new(ScenarioStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Scenario) DeepCopyInto(out *Scenario) {
+func (in *SeedChunk) DeepCopyInto(out *SeedChunk) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Scenario.
-func (in *Scenario) DeepCopy() *Scenario {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedChunk.
+func (in *SeedChunk) DeepCopy() *SeedChunk {
 	if in == nil {
 		return nil
 	}
-	out := new(Scenario)
+	out := new(SeedChunk)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Scenario) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScenarioList) DeepCopyInto(out *ScenarioList) {
+func (in *SeedDatabaseSpec) DeepCopyInto(out *SeedDatabaseSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Scenario, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.DatabaseConnection.DeepCopyInto(&out.DatabaseConnection)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScenarioList.
-func (in *ScenarioList) DeepCopy() *ScenarioList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedDatabaseSpec.
+func (in *SeedDatabaseSpec) DeepCopy() *SeedDatabaseSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ScenarioList)
+	out := new(SeedDatabaseSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ScenarioList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScenarioSpec) DeepCopyInto(out *ScenarioSpec) {
+func (in *SeedFilesSpec) DeepCopyInto(out *SeedFilesSpec) {
 	*out = *in
-	if in.TestData != nil {
-		in, out := &in.TestData, &out.TestData
-		*out = new(TestdataVolume)
-		**out = **in
-	}
-	if in.Actions != nil {
-		in, out := &in.Actions, &out.Actions
-		*out = make([]Action, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Suspend != nil {
-		in, out := &in.Suspend, &out.Suspend
-		*out = new(bool)
-		**out = **in
-	}
+	out.Size = in.Size.DeepCopy()
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScenarioSpec.
-func (in *ScenarioSpec) DeepCopy() *ScenarioSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedFilesSpec.
+func (in *SeedFilesSpec) DeepCopy() *SeedFilesSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ScenarioSpec)
+	out := new(SeedFilesSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScenarioStatus) DeepCopyInto(out *ScenarioStatus) {
+func (in *SeedSpec) DeepCopyInto(out *SeedSpec) {
 	*out = *in
-	in.Lifecycle.DeepCopyInto(&out.Lifecycle)
-	if in.ScheduledJobs != nil {
-		in, out := &in.ScheduledJobs, &out.ScheduledJobs
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = new(SeedFilesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Database != nil {
+		in, out := &in.Database, &out.Database
+		*out = new(SeedDatabaseSpec)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScenarioStatus.
-func (in *ScenarioStatus) DeepCopy() *ScenarioStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedSpec.
+func (in *SeedSpec) DeepCopy() *SeedSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ScenarioStatus)
+	out := new(SeedSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1210,6 +2455,18 @@ func (in *ServiceSpec) DeepCopyInto(out *ServiceSpec) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make(map[string]ContainerHooks, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Lifetime != nil {
+		in, out := &in.Lifetime, &out.Lifetime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	in.PodSpec.DeepCopyInto(&out.PodSpec)
 }
 
@@ -1258,6 +2515,67 @@ func (in *SetField) DeepCopy() *SetField {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotSpec) DeepCopyInto(out *SnapshotSpec) {
+	*out = *in
+	if in.Claims != nil {
+		in, out := &in.Claims, &out.Claims
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VolumeSnapshotClassName != nil {
+		in, out := &in.VolumeSnapshotClassName, &out.VolumeSnapshotClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotSpec.
+func (in *SnapshotSpec) DeepCopy() *SnapshotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpecPatch) DeepCopyInto(out *SpecPatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecPatch.
+func (in *SpecPatch) DeepCopy() *SpecPatch {
+	if in == nil {
+		return nil
+	}
+	out := new(SpecPatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSFaultSpec) DeepCopyInto(out *TLSFaultSpec) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSFaultSpec.
+func (in *TLSFaultSpec) DeepCopy() *TLSFaultSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSFaultSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TaskSchedulerSpec) DeepCopyInto(out *TaskSchedulerSpec) {
 	*out = *in
@@ -1286,6 +2604,16 @@ func (in *TaskSchedulerSpec) DeepCopyInto(out *TaskSchedulerSpec) {
 		*out = new(ConditionalExpr)
 		**out = **in
 	}
+	if in.Ramp != nil {
+		in, out := &in.Ramp, &out.Ramp
+		*out = new(RampSpec)
+		**out = **in
+	}
+	if in.Trace != nil {
+		in, out := &in.Trace, &out.Trace
+		*out = new(TraceSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskSchedulerSpec.
@@ -1332,17 +2660,24 @@ func (in *TemplateInputs) DeepCopyInto(out *TemplateInputs) {
 		in, out := &in.Parameters, &out.Parameters
 		*out = make(Parameters, len(*in))
 		for key, val := range *in {
-			var outVal *apiextensionsv1.JSON
+			var outVal *v1.JSON
 			if val == nil {
 				(*out)[key] = nil
 			} else {
 				in, out := &val, &outVal
-				*out = new(apiextensionsv1.JSON)
+				*out = new(v1.JSON)
 				(*in).DeepCopyInto(*out)
 			}
 			(*out)[key] = outVal
 		}
 	}
+	if in.Vars != nil {
+		in, out := &in.Vars, &out.Vars
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateInputs.
@@ -1470,7 +2805,7 @@ func (in *TimelineDistributionSpec) DeepCopyInto(out *TimelineDistributionSpec)
 	*out = *in
 	if in.TotalDuration != nil {
 		in, out := &in.TotalDuration, &out.TotalDuration
-		*out = new(v1.Duration)
+		*out = new(metav1.Duration)
 		**out = **in
 	}
 	if in.DistributionSpec != nil {
@@ -1505,6 +2840,155 @@ func (in *TolerateSpec) DeepCopy() *TolerateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologySpec) DeepCopyInto(out *TopologySpec) {
+	*out = *in
+	if in.NodeSelectors != nil {
+		in, out := &in.NodeSelectors, &out.NodeSelectors
+		*out = make([]map[string]string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologySpec.
+func (in *TopologySpec) DeepCopy() *TopologySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in Trace) DeepCopyInto(out *Trace) {
+	{
+		in := &in
+		*out = make(Trace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Trace.
+func (in Trace) DeepCopy() Trace {
+	if in == nil {
+		return nil
+	}
+	out := new(Trace)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TraceRecord) DeepCopyInto(out *TraceRecord) {
+	*out = *in
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make(UserInputs, len(*in))
+		for key, val := range *in {
+			var outVal *v1.JSON
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(v1.JSON)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraceRecord.
+func (in *TraceRecord) DeepCopy() *TraceRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(TraceRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TraceSpec) DeepCopyInto(out *TraceSpec) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraceSpec.
+func (in *TraceSpec) DeepCopy() *TraceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TraceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TracingSpec) DeepCopyInto(out *TracingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TracingSpec.
+func (in *TracingSpec) DeepCopy() *TracingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TracingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TriggerPayload) DeepCopyInto(out *TriggerPayload) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerPayload.
+func (in *TriggerPayload) DeepCopy() *TriggerPayload {
+	if in == nil {
+		return nil
+	}
+	out := new(TriggerPayload)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TriggerSpec) DeepCopyInto(out *TriggerSpec) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerSpec.
+func (in *TriggerSpec) DeepCopy() *TriggerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TriggerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualObject) DeepCopyInto(out *VirtualObject) {
 	*out = *in
@@ -1617,7 +3101,7 @@ func (in *WaitSpec) DeepCopyInto(out *WaitSpec) {
 	}
 	if in.After != nil {
 		in, out := &in.After, &out.After
-		*out = new(v1.Duration)
+		*out = new(metav1.Duration)
 		**out = **in
 	}
 }
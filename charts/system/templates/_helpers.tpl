@@ -0,0 +1,17 @@
+{{/*
+Pins the telemetry components to nodes of the given architectures (e.g, amd64), for clusters
+that mix architectures and run telemetry images that are not multi-arch.
+*/}}
+{{- define "system.telemetry.affinity" -}}
+{{- with .Values.telemetry.nodeArchitectures }}
+affinity:
+  nodeAffinity:
+    requiredDuringSchedulingIgnoredDuringExecution:
+      nodeSelectorTerms:
+        - matchExpressions:
+            - key: kubernetes.io/arch
+              operator: In
+              values:
+                {{- toYaml . | nindent 16 }}
+{{- end }}
+{{- end }}
@@ -0,0 +1,106 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package charts
+
+import (
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+type AddChartCmdOptions struct {
+	// Version selects a specific version of the chart. Applies only to repository references.
+	Version string
+
+	// Digest is the expected sha256 of the chart archive.
+	Digest string
+}
+
+func AddChartCmdCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch {
+	case len(args) == 0:
+		return common.OfficialCharts, cobra.ShellCompDirectiveNoFileComp
+
+	case len(args) == 1:
+		return nil, cobra.ShellCompDirectiveFilterDirs
+
+	default:
+		return common.CompleteFlags(cmd, args, toComplete)
+	}
+}
+
+func NewAddChartCmd() *cobra.Command {
+	var options AddChartCmdOptions
+
+	cmd := &cobra.Command{
+		Use:   "add <name> [<source>]",
+		Short: "Add a chart to the local cache",
+		Long: `Fetch a chart into the local cache, and register it under a short name.
+If the source is omitted, the name must refer to an official Frisbee chart.
+The source can be a repository reference (e.g, frisbee/ycsb), a URL, or a local directory.`,
+		Example: `# Add an official chart:
+  kubectl frisbee charts add ycsb
+# Add a chart from a local directory:
+  kubectl frisbee charts add mydb ./charts/mydb
+# Add a chart and verify its integrity:
+  kubectl frisbee charts add ycsb --sha256 <digest>
+`,
+		ValidArgsFunction: AddChartCmdCompletion,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 || len(args) > 2 {
+				ui.Failf("Pass the chart name, and optionally the chart source.")
+			}
+
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			name := args[0]
+
+			source := common.OfficialChartSource(name)
+			if len(args) == 2 {
+				source = args[1]
+			}
+
+			if source == "" {
+				ui.Failf("'%s' is not an official chart. Pass the chart source.", name)
+			}
+
+			index, err := common.LoadChartIndex()
+			ui.ExitOnError("Loading charts index", err)
+
+			if _, exists := index.Charts[name]; exists {
+				ui.Failf("chart '%s' already exists. Use 'kubectl frisbee charts update %s'", name, name)
+			}
+
+			entry, err := common.FetchChart(name, source, options.Version, options.Digest)
+			ui.ExitOnError("Fetching chart from "+source, err)
+
+			index.Charts[name] = entry
+
+			ui.ExitOnError("Saving charts index", index.Save())
+			ui.Success("Chart added:", name, entry.Version, entry.Digest)
+
+			env.Default.Hint("To use the chart in a test:", "kubectl frisbee submit test <testName> <scenario> ", name)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Version, "version", "", "the version of the chart (repository references only).")
+	cmd.Flags().StringVar(&options.Digest, "sha256", "", "the expected sha256 digest of the chart archive.")
+
+	return cmd
+}
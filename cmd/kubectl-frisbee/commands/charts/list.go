@@ -0,0 +1,50 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package charts
+
+import (
+	"os"
+
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewListChartsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "list",
+		Aliases:           []string{"ls", "l"},
+		Short:             "List the charts in the local cache, and verify their integrity",
+		ValidArgsFunction: common.NoArgs,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				ui.Failf("list does not accept arguments")
+			}
+
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			index, err := common.LoadChartIndex()
+			ui.ExitOnError("Loading charts index", err)
+
+			err = common.RenderList(index, os.Stdout)
+			ui.PrintOnError("Rendering list", err)
+		},
+	}
+
+	return cmd
+}
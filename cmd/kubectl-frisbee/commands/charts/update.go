@@ -0,0 +1,84 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package charts
+
+import (
+	"sort"
+
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+func UpdateChartsCmdCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	index, err := common.LoadChartIndex()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	names := make([]string, 0, len(index.Charts))
+	for name := range index.Charts {
+		names = append(names, name)
+	}
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func NewUpdateChartsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "update [<name>...]",
+		Aliases:           []string{"up", "u"},
+		Short:             "Fetch again the charts of the local cache from their sources",
+		Long:              `Update the given charts. If no chart is given, all the charts of the cache are updated.`,
+		ValidArgsFunction: UpdateChartsCmdCompletion,
+		Run: func(cmd *cobra.Command, args []string) {
+			index, err := common.LoadChartIndex()
+			ui.ExitOnError("Loading charts index", err)
+
+			names := args
+			if len(names) == 0 {
+				for name := range index.Charts {
+					names = append(names, name)
+				}
+
+				sort.Strings(names)
+			}
+
+			for _, name := range names {
+				entry, exists := index.Charts[name]
+				if !exists {
+					ui.Failf("chart '%s' is not in the cache. Use 'kubectl frisbee charts add %s'", name, name)
+				}
+
+				updated, err := common.FetchChart(name, entry.Source, "", "")
+				ui.ExitOnError("Updating chart "+name, err)
+
+				index.Charts[name] = updated
+
+				if updated.Digest == entry.Digest {
+					ui.Success("Chart is up to date:", name, updated.Version)
+				} else {
+					ui.Success("Chart updated:", name, entry.Version+" -> "+updated.Version, updated.Digest)
+				}
+			}
+
+			ui.ExitOnError("Saving charts index", index.Save())
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"strings"
+
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/pkg/errors"
+)
+
+/*******************************************************************
+
+			Chaos Mesh of the tests
+
+*******************************************************************/
+
+// ChaosScope defines which Chaos Mesh instance injects the faults of a test.
+type ChaosScope string
+
+const (
+	// ChaosScopeCluster uses the cluster-wide instance, as installed by the platform.
+	ChaosScopeCluster = ChaosScope("cluster")
+
+	// ChaosScopeNamespace installs a dedicated instance in the namespace of the test. The instance, and its RBAC,
+	// are confined to the namespace, and therefore they can be installed without cluster-admin. The CRDs of
+	// Chaos Mesh must still be installed in the cluster.
+	ChaosScopeNamespace = ChaosScope("namespace")
+)
+
+const (
+	// ChaosMeshRelease is the name of the Helm release of the namespace-scoped instances.
+	ChaosMeshRelease = "chaos-mesh"
+
+	// ChaosMeshChartVersion is the version of the namespace-scoped instances. It follows the platform chart.
+	ChaosMeshChartVersion = "2.1.2"
+
+	// ChaosMeshCRD is the CRD that indicates the installation of Chaos Mesh.
+	ChaosMeshCRD = "networkchaos.chaos-mesh.org"
+
+	// ChaosMeshControllers selects the controllers of Chaos Mesh, in any namespace.
+	ChaosMeshControllers = "app.kubernetes.io/name=chaos-mesh,app.kubernetes.io/component=controller-manager"
+
+	// ChaosMeshController is the deployment of the controller of Chaos Mesh.
+	ChaosMeshController = "deployment/chaos-controller-manager"
+)
+
+// namespacedChaosMeshValues confine the instance to the namespace of the test. Components that are irrelevant to
+// the tests (e.g, dashboard) are not installed.
+var namespacedChaosMeshValues = []string{
+	"clusterScoped=false",
+	"rbac.create=true",
+	"controllerManager.replicaCount=1",
+	"controllerManager.leaderElection.enabled=false",
+	"dashboard.create=false",
+	"dnsServer.create=false",
+}
+
+// VerifyClusterChaosMesh checks that Chaos Mesh is installed cluster-wide. The CRDs are required, whereas the
+// controllers may not be visible to users without cluster-wide permissions.
+func VerifyClusterChaosMesh() error {
+	if !CRDsExist(ChaosMeshCRD) {
+		return errors.Errorf("Chaos Mesh is not installed. Its CRDs (e.g, %s) are missing", ChaosMeshCRD)
+	}
+
+	out, err := Kubectl(ClusterScope, "get", "deployments", "--all-namespaces",
+		"-l", ChaosMeshControllers, "-o", "jsonpath={.items[*].metadata.namespace}")
+
+	switch {
+	case err != nil:
+		ui.Warn("Cannot verify the controllers of Chaos Mesh:", strings.TrimSpace(string(out)))
+
+		return nil
+
+	case strings.TrimSpace(string(out)) == "":
+		return errors.New("no controller of Chaos Mesh is running. Use a namespace-scoped instance instead")
+
+	default:
+		return nil
+	}
+}
+
+// InstallNamespacedChaosMesh installs, or upgrades, the Chaos Mesh instance of the test, and waits for its
+// controller to become available. Values (e.g, chaosDaemon.runtime=containerd) override the defaults.
+func InstallNamespacedChaosMesh(testName string, values []string) error {
+	if !CRDsExist(ChaosMeshCRD) {
+		return errors.Errorf("the CRDs of Chaos Mesh (e.g, %s) must be installed by the cluster administrator", ChaosMeshCRD)
+	}
+
+	_, err := Helm("", "repo", "add", "chaos-mesh", ChaosMeshRepo)
+	if err != nil && !strings.Contains(err.Error(), "Error: repository name (chaos-mesh) already exists") {
+		return errors.Wrapf(err, "cannot add chaos-mesh repo")
+	}
+
+	if _, err := Helm("", "repo", "update"); err != nil {
+		return errors.Wrapf(err, "cannot update repositories")
+	}
+
+	command := []string{
+		"upgrade", "--install",
+		ChaosMeshRelease, "chaos-mesh/chaos-mesh",
+		"--version", ChaosMeshChartVersion,
+		// the CRDs are cluster-wide, and they are managed by the cluster administrator.
+		"--skip-crds",
+		"--set", "controllerManager.targetNamespace=" + testName,
+	}
+
+	for _, value := range append(namespacedChaosMeshValues, values...) {
+		command = append(command, "--set", value)
+	}
+
+	if _, err := Helm(testName, command...); err != nil {
+		return errors.Wrapf(err, "cannot install Chaos Mesh")
+	}
+
+	if _, err := Kubectl(testName, "rollout", "status", ChaosMeshController, "--timeout", "5m"); err != nil {
+		return errors.Wrapf(err, "Chaos Mesh is not available")
+	}
+
+	return nil
+}
+
+// UninstallNamespacedChaosMesh removes the Chaos Mesh instance of the test, if any. Unlike the deletion of the
+// namespace, it also removes the cluster-wide objects of the release (e.g, webhook configurations).
+func UninstallNamespacedChaosMesh(testName string) error {
+	_, err := Helm(testName, "uninstall", ChaosMeshRelease)
+
+	return HelmIgnoreNotFound(err)
+}
@@ -0,0 +1,304 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/pkg/home"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+/*******************************************************************
+
+			Management of the Charts Cache
+
+*******************************************************************/
+
+// OfficialCharts are the system charts maintained by the Frisbee project, and are pulled from the Frisbee repository.
+var OfficialCharts = []string{"ycsb", "iperf2", "iperf3", "fio", "redis", "mongodb", "cockroachdb", "tikv"}
+
+// ChartsCache is the directory where the charts and their index are stored.
+var ChartsCache = home.CachePath("charts")
+
+const chartsIndex = "index.yaml"
+
+// ChartEntry describes a chart in the cache.
+type ChartEntry struct {
+	// Name is the short name of the chart, used for referencing the chart in tests.
+	Name string `json:"name"`
+
+	// Source is where the chart was fetched from. It can be a repository reference (e.g, frisbee/ycsb),
+	// a URL, or a local path.
+	Source string `json:"source"`
+
+	// Version is the version of the chart.
+	Version string `json:"version,omitempty"`
+
+	// Digest is the sha256 of the chart archive, used for verifying the integrity of the cache.
+	Digest string `json:"digest"`
+
+	// Updated is the last time the chart was fetched.
+	Updated time.Time `json:"updated"`
+}
+
+// Path returns the location of the chart archive in the cache.
+func (e ChartEntry) Path() string {
+	return filepath.Join(ChartsCache, e.Name+".tgz")
+}
+
+// Verify checks that the chart archive in the cache matches the recorded digest.
+func (e ChartEntry) Verify() error {
+	digest, err := fileDigest(e.Path())
+	if err != nil {
+		return errors.Wrapf(err, "cannot read chart '%s'", e.Name)
+	}
+
+	if digest != e.Digest {
+		return errors.Errorf("chart '%s' is modified. expected digest '%s' but got '%s'", e.Name, e.Digest, digest)
+	}
+
+	return nil
+}
+
+// ChartIndex is the index of the charts in the cache.
+type ChartIndex struct {
+	Charts map[string]ChartEntry `json:"charts"`
+}
+
+func (in *ChartIndex) Table() (header []string, output [][]string) {
+	header = []string{"Name", "Version", "Source", "Updated", "Integrity"}
+
+	names := make([]string, 0, len(in.Charts))
+	for name := range in.Charts {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		entry := in.Charts[name]
+
+		integrity := "ok"
+		if err := entry.Verify(); err != nil {
+			integrity = "corrupted"
+		}
+
+		output = append(output, []string{
+			entry.Name,
+			entry.Version,
+			entry.Source,
+			entry.Updated.Format(time.RFC3339),
+			integrity,
+		})
+	}
+
+	return header, output
+}
+
+// LoadChartIndex reads the index of the cache. If the index does not exist, it returns an empty index.
+func LoadChartIndex() (*ChartIndex, error) {
+	index := &ChartIndex{Charts: map[string]ChartEntry{}}
+
+	data, err := os.ReadFile(filepath.Join(ChartsCache, chartsIndex))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return index, nil
+		}
+
+		return nil, errors.Wrapf(err, "cannot read charts index")
+	}
+
+	if err := yaml.Unmarshal(data, index); err != nil {
+		return nil, errors.Wrapf(err, "cannot decode charts index")
+	}
+
+	if index.Charts == nil {
+		index.Charts = map[string]ChartEntry{}
+	}
+
+	return index, nil
+}
+
+// Save writes the index of the cache.
+func (in *ChartIndex) Save() error {
+	data, err := yaml.Marshal(in)
+	if err != nil {
+		return errors.Wrapf(err, "cannot encode charts index")
+	}
+
+	if err := os.MkdirAll(ChartsCache, os.ModePerm); err != nil {
+		return errors.Wrapf(err, "cannot create cache directory '%s'", ChartsCache)
+	}
+
+	return errors.Wrapf(os.WriteFile(filepath.Join(ChartsCache, chartsIndex), data, 0o600), "cannot write charts index")
+}
+
+// OfficialChartSource returns the source of an official chart, or an empty string if the chart is not official.
+func OfficialChartSource(name string) string {
+	for _, official := range OfficialCharts {
+		if official == name {
+			return "frisbee/" + name
+		}
+	}
+
+	return ""
+}
+
+// FetchChart downloads the chart from the source into the cache, and returns the respective entry.
+// If expectedDigest is not empty, the chart is accepted only if it matches the digest.
+func FetchChart(name, source, version, expectedDigest string) (ChartEntry, error) {
+	tmpDir, err := os.MkdirTemp("", "frisbee-chart-")
+	if err != nil {
+		return ChartEntry{}, errors.Wrapf(err, "cannot create temporary directory")
+	}
+
+	defer os.RemoveAll(tmpDir)
+
+	/*---------------------------------------------------
+	 * Fetch the chart archive
+	 *---------------------------------------------------*/
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		_, err = Helm("", "package", source, "--destination", tmpDir)
+		if err != nil {
+			return ChartEntry{}, errors.Wrapf(err, "cannot package '%s'", source)
+		}
+	} else {
+		if strings.HasPrefix(source, "frisbee/") {
+			updateHelmFrisbeeRepo()
+		}
+
+		args := []string{"pull", source, "--destination", tmpDir}
+		if version != "" {
+			args = append(args, "--version", version)
+		}
+
+		if _, err := Helm("", args...); err != nil {
+			return ChartEntry{}, errors.Wrapf(err, "cannot pull '%s'", source)
+		}
+	}
+
+	archives, err := filepath.Glob(filepath.Join(tmpDir, "*.tgz"))
+	if err != nil || len(archives) != 1 {
+		return ChartEntry{}, errors.Errorf("expected a single chart archive from '%s'", source)
+	}
+
+	/*---------------------------------------------------
+	 * Verify the integrity of the chart
+	 *---------------------------------------------------*/
+	digest, err := fileDigest(archives[0])
+	if err != nil {
+		return ChartEntry{}, errors.Wrapf(err, "cannot compute digest")
+	}
+
+	if expectedDigest != "" && !strings.EqualFold(expectedDigest, digest) {
+		return ChartEntry{}, errors.Errorf("integrity check failed for '%s'. expected digest '%s' but got '%s'",
+			source, expectedDigest, digest)
+	}
+
+	out, err := Helm("", "show", "chart", archives[0])
+	if err != nil {
+		return ChartEntry{}, errors.Wrapf(err, "cannot inspect chart")
+	}
+
+	var metadata struct {
+		Version string `json:"version"`
+	}
+
+	if err := yaml.Unmarshal(out, &metadata); err != nil {
+		return ChartEntry{}, errors.Wrapf(err, "cannot decode chart metadata")
+	}
+
+	/*---------------------------------------------------
+	 * Store the chart into the cache
+	 *---------------------------------------------------*/
+	entry := ChartEntry{
+		Name:    name,
+		Source:  source,
+		Version: metadata.Version,
+		Digest:  digest,
+		Updated: time.Now(),
+	}
+
+	if err := os.MkdirAll(ChartsCache, os.ModePerm); err != nil {
+		return ChartEntry{}, errors.Wrapf(err, "cannot create cache directory '%s'", ChartsCache)
+	}
+
+	if err := copyFile(archives[0], entry.Path()); err != nil {
+		return ChartEntry{}, errors.Wrapf(err, "cannot store chart")
+	}
+
+	return entry, nil
+}
+
+// ResolveChart returns the location of a chart dependency. Paths and repository references are returned as they are,
+// whereas short names are resolved to the verified chart in the cache.
+func ResolveChart(dependency string) (string, error) {
+	if _, err := os.Stat(dependency); err == nil || strings.Contains(dependency, "/") {
+		return dependency, nil
+	}
+
+	index, err := LoadChartIndex()
+	if err != nil {
+		return "", err
+	}
+
+	entry, exists := index.Charts[dependency]
+	if !exists {
+		return "", errors.Errorf("chart '%s' is not in the cache. Use 'kubectl frisbee charts add %s'",
+			dependency, dependency)
+	}
+
+	if err := entry.Verify(); err != nil {
+		return "", errors.Wrapf(err, "use 'kubectl frisbee charts update %s'", dependency)
+	}
+
+	return entry.Path(), nil
+}
+
+func fileDigest(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(dst, data, 0o600)
+}
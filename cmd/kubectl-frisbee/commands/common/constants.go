@@ -29,8 +29,9 @@ const (
 )
 
 const (
-	FrisbeeRepo  = "https://carv-ics-forth.github.io/frisbee/charts"
-	JetstackRepo = "https://charts.jetstack.io"
+	FrisbeeRepo   = "https://carv-ics-forth.github.io/frisbee/charts"
+	JetstackRepo  = "https://charts.jetstack.io"
+	ChaosMeshRepo = "https://charts.chaos-mesh.org"
 )
 
 const (
@@ -47,3 +48,11 @@ var BackoffPodCreation = wait.Backoff{
 	Jitter:   0.1,
 	Steps:    60,
 }
+
+const (
+	// TestServiceAccount is the identity given to external tools that operate on a test.
+	TestServiceAccount = "frisbee-test"
+
+	// TestServiceAccountRole is the role bound to the TestServiceAccount, within the test namespace.
+	TestServiceAccountRole = "frisbee-test"
+)
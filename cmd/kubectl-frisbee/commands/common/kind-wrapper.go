@@ -0,0 +1,54 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"os"
+	"strings"
+
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
+	"github.com/carv-ics-forth/frisbee/pkg/process"
+	"github.com/kubeshop/testkube/pkg/ui"
+)
+
+func Kind(command ...string) ([]byte, error) {
+	ui.Debug(env.Default.Kind(), strings.Join(command, " "))
+
+	return process.Execute(env.Default.Kind(), command...)
+}
+
+func LoggedKind(command ...string) ([]byte, error) {
+	ui.Debug(env.Default.Kind(), strings.Join(command, " "))
+
+	return process.LoggedExecuteInDir("", os.Stdout, env.Default.Kind(), command...)
+}
+
+// KindClusterExists returns true if a kind cluster with the given name is running.
+func KindClusterExists(name string) (bool, error) {
+	out, err := Kind("get", "clusters")
+	if err != nil {
+		return false, err
+	}
+
+	for _, cluster := range strings.Fields(string(out)) {
+		if cluster == name {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
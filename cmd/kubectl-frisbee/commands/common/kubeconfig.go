@@ -0,0 +1,192 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
+	"github.com/pkg/errors"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/yaml"
+)
+
+const AlreadyExists = `Error from server (AlreadyExists)`
+
+func ErrAlreadyExists(out []byte) bool {
+	return strings.Contains(string(out), AlreadyExists)
+}
+
+// CreateTestServiceAccount creates (if it does not exist) a service account whose permissions are
+// limited to the namespace of the test.
+func CreateTestServiceAccount(testName string) error {
+	{ // Identity
+		command := []string{"create", "serviceaccount", TestServiceAccount}
+
+		out, err := Kubectl(testName, command...)
+		if err != nil && !ErrAlreadyExists(out) {
+			return errors.Wrapf(err, "cannot create service account")
+		}
+	}
+
+	{ // Permissions
+		if err := applyTestServiceAccountRole(testName); err != nil {
+			return errors.Wrapf(err, "cannot create role")
+		}
+
+		// the reference of a binding cannot be changed. Recreate the binding, in case it refers to the role of a
+		// previous version.
+		if _, err := Kubectl(testName, "delete", "rolebinding", TestServiceAccount, "--ignore-not-found"); err != nil {
+			return errors.Wrapf(err, "cannot delete role binding")
+		}
+
+		command := []string{
+			"create", "rolebinding", TestServiceAccount,
+			"--role=" + TestServiceAccountRole,
+			"--serviceaccount=" + testName + ":" + TestServiceAccount,
+		}
+
+		out, err := Kubectl(testName, command...)
+		if err != nil && !ErrAlreadyExists(out) {
+			return errors.Wrapf(err, "cannot create role binding")
+		}
+	}
+
+	return nil
+}
+
+// NewTestServiceAccountRole returns the permissions of the service account of the test. External tools can inspect
+// the Pods, Services and Frisbee resources of the test, read logs, exec into Pods and forward ports. They cannot
+// read Secrets (e.g, the keys of Triggers, the credentials of Grafana), nor modify the test.
+func NewTestServiceAccountRole(testName string) *rbacv1.Role {
+	read := []string{"get", "list", "watch"}
+
+	var role rbacv1.Role
+
+	role.SetGroupVersionKind(rbacv1.SchemeGroupVersion.WithKind("Role"))
+	role.SetName(TestServiceAccountRole)
+	role.SetNamespace(testName)
+
+	role.Rules = []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{""},
+			Resources: []string{"pods", "pods/log", "services", "endpoints", "events"},
+			Verbs:     read,
+		},
+		{
+			APIGroups: []string{""},
+			Resources: []string{"pods/exec", "pods/portforward"},
+			Verbs:     []string{"get", "create"},
+		},
+		{
+			APIGroups: []string{v1alpha1.GroupVersion.Group},
+			Resources: []string{"scenarios", "services", "clusters", "chaos", "cascades", "calls", "templates", "virtualobjects"},
+			Verbs:     read,
+		},
+	}
+
+	return &role
+}
+
+func applyTestServiceAccountRole(testName string) error {
+	manifest, err := yaml.Marshal(NewTestServiceAccountRole(testName))
+	if err != nil {
+		return errors.Wrapf(err, "cannot encode role")
+	}
+
+	f, err := os.CreateTemp("", TestServiceAccountRole+"-*.yaml")
+	if err != nil {
+		return errors.Wrapf(err, "cannot create role file")
+	}
+
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(manifest); err != nil {
+		f.Close()
+
+		return errors.Wrapf(err, "cannot store role file")
+	}
+
+	if err := f.Close(); err != nil {
+		return errors.Wrapf(err, "cannot store role file")
+	}
+
+	_, err = Kubectl(testName, "apply", "-f", f.Name())
+
+	return err
+}
+
+// CreateTestToken requests a short-lived token for the service account of the test.
+func CreateTestToken(testName string, duration string) (string, error) {
+	command := []string{"create", "token", TestServiceAccount, "--duration=" + duration}
+
+	out, err := Kubectl(testName, command...)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot create token")
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// GenerateTestKubeconfig returns a kubeconfig whose single context is scoped to the namespace of the test.
+// The cluster information is inherited by the kubeconfig of the caller.
+func GenerateTestKubeconfig(testName string, token string) ([]byte, error) {
+	restConfig := env.Default.KubeConfig
+	if restConfig == nil {
+		return nil, errors.Errorf("kubernetes api is not configured")
+	}
+
+	caData := restConfig.CAData
+	if len(caData) == 0 && restConfig.CAFile != "" {
+		data, err := os.ReadFile(restConfig.CAFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot read certificate authority '%s'", restConfig.CAFile)
+		}
+
+		caData = data
+	}
+
+	contextName := fmt.Sprintf("frisbee-%s", testName)
+
+	kubeconfig := clientcmdapi.NewConfig()
+
+	kubeconfig.Clusters[contextName] = &clientcmdapi.Cluster{
+		Server:                   restConfig.Host,
+		CertificateAuthorityData: caData,
+		InsecureSkipTLSVerify:    restConfig.Insecure,
+		TLSServerName:            restConfig.ServerName,
+	}
+
+	kubeconfig.AuthInfos[contextName] = &clientcmdapi.AuthInfo{
+		Token: token,
+	}
+
+	kubeconfig.Contexts[contextName] = &clientcmdapi.Context{
+		Cluster:   contextName,
+		AuthInfo:  contextName,
+		Namespace: testName,
+	}
+
+	kubeconfig.CurrentContext = contextName
+
+	return clientcmd.Write(*kubeconfig)
+}
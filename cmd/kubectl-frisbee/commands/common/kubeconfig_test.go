@@ -0,0 +1,73 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_test
+
+import (
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
+)
+
+func TestNewTestServiceAccountRole(t *testing.T) {
+	role := common.NewTestServiceAccountRole("my-test")
+
+	if role.GetNamespace() != "my-test" {
+		t.Errorf("namespace = %s, want my-test", role.GetNamespace())
+	}
+
+	allowed := map[string]map[string]bool{}
+
+	for _, rule := range role.Rules {
+		for _, resource := range rule.Resources {
+			if resource == "*" || resource == "secrets" {
+				t.Errorf("rule %v grants access to '%s'", rule, resource)
+			}
+
+			for _, verb := range rule.Verbs {
+				if verb == "*" {
+					t.Errorf("rule %v grants all verbs", rule)
+				}
+
+				if allowed[resource] == nil {
+					allowed[resource] = map[string]bool{}
+				}
+
+				allowed[resource][verb] = true
+			}
+		}
+	}
+
+	for _, want := range []struct{ resource, verb string }{
+		{"pods", "get"},
+		{"pods/log", "get"},
+		{"services", "list"},
+		{"pods/exec", "create"},
+		{"pods/portforward", "create"},
+	} {
+		if !allowed[want.resource][want.verb] {
+			t.Errorf("role does not allow '%s' on '%s'", want.verb, want.resource)
+		}
+	}
+
+	for _, resource := range []string{"pods", "scenarios"} {
+		for _, verb := range []string{"create", "update", "patch", "delete"} {
+			if allowed[resource][verb] {
+				t.Errorf("role allows '%s' on '%s'", verb, resource)
+			}
+		}
+	}
+}
@@ -17,8 +17,10 @@ limitations under the License.
 package common
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
 	"strings"
@@ -159,6 +161,48 @@ func LoggedKubectl(testName string, command ...string) ([]byte, error) {
 	return process.LoggedExecuteInDir("", os.Stdout, env.Default.Kubectl(), kubectlArgs...)
 }
 
+// KubectlStream runs the command in the background, and streams its standard output. The stream must be consumed
+// before calling the returned function, which waits for the command to exit and reports its standard error.
+func KubectlStream(ctx context.Context, testName string, command ...string) (io.Reader, func() error, error) {
+	var kubectlArgs []string
+
+	if env.Default.KubeConfigPath != "" {
+		kubectlArgs = append(kubectlArgs, "--kubeconfig", env.Default.KubeConfigPath)
+	}
+
+	if testName != "" {
+		kubectlArgs = append(kubectlArgs, "--namespace", testName)
+	}
+
+	kubectlArgs = append(kubectlArgs, command...)
+
+	ui.Debug(env.Default.Kubectl(), strings.Join(kubectlArgs, " "))
+
+	cmd := exec.New().CommandContext(ctx, env.Default.Kubectl(), kubectlArgs...)
+
+	stderr := new(bytes.Buffer)
+	cmd.SetStderr(stderr)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "cannot open stream")
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, nil, errors.Wrapf(err, "could not start process")
+	}
+
+	wait := func() error {
+		if err := cmd.Wait(); err != nil {
+			return errors.Wrapf(err, "process error: %s", strings.TrimSpace(stderr.String()))
+		}
+
+		return nil
+	}
+
+	return stdout, wait, nil
+}
+
 func setOutput(command []string) []string {
 	outputType := OutputType(env.Default.OutputType)
 	if outputType == "table" ||
@@ -262,33 +306,6 @@ func GetTemplateResources(testName string) error {
 	return err
 }
 
-func WaitForCondition(ctx context.Context, testName string, condition v1alpha1.ConditionType, timeout string) error {
-	command := []string{
-		"wait", "scenario", "--all=true",
-		"--for=condition=" + condition.String(),
-		"--timeout=" + timeout,
-	}
-
-	return wait.ExponentialBackoffWithContext(ctx, BackoffPodCreation, func(ctx context.Context) (done bool, err error) {
-		out, err := Kubectl(testName, command...)
-
-		switch {
-		case ErrNamespaceNotFound(out):
-			return true, nil
-		case len(out) == 0, ErrNoMatchingResources(out): // resource initialization
-			// ui.Info("Waiting for pods to become ready ...", string(out))
-			return false, nil
-		case err != nil: // abort
-			return false, err
-		default: // ok
-			ui.Info("Condition successful")
-
-			// Output printing is not required as it is printed by the os.Stdout
-			return true, nil
-		}
-	})
-}
-
 /*
 ******************************************************************
 
@@ -303,6 +320,7 @@ const (
 	IOChaos      = "iochaos.chaos-mesh.org"
 	KernelChaos  = "kernelchaos.chaos-mesh.org"
 	TimeChaos    = "timechaos.chaos-mesh.org"
+	StressChaos  = "stresschaos.chaos-mesh.org"
 )
 
 var ChaosResourceInspectionFields = strings.Join([]string{
@@ -323,7 +341,7 @@ func GetChaosResources(testName string) error {
 		"-l", v1alpha1.LabelScenario,
 	}
 
-	command = append(command, strings.Join([]string{NetworkChaos, PodChaos, IOChaos, KernelChaos, TimeChaos}, ","))
+	command = append(command, strings.Join([]string{NetworkChaos, PodChaos, IOChaos, KernelChaos, TimeChaos, StressChaos}, ","))
 
 	command = setOutput(command)
 
@@ -601,7 +619,7 @@ func DeleteNamespaces(selector string, testNames ...string) error {
 	}
 
 	if selector != "" {
-		command = append(command, "-l", ManagedNamespace)
+		command = append(command, "-l", selector)
 	} else {
 		command = append(command, testNames...)
 	}
@@ -616,8 +634,21 @@ func DeleteNamespaces(selector string, testNames ...string) error {
 
 const (
 	K8SRemoveFinalizer = `--patch=[{"op":"remove","path":"/metadata/finalizers"}]`
+
+	K8SAbortScenario = `--patch={"spec":{"abort":true}}`
 )
 
+// AbortTest requests from the controller to gracefully stop the scenario.
+func AbortTest(testName string, scenarioName string) error {
+	command := []string{"patch", Scenarios, scenarioName, "--type", "merge", K8SAbortScenario}
+
+	if _, err := Kubectl(testName, command...); err != nil {
+		return errors.Wrapf(err, "cannot abort scenario '%s'", scenarioName)
+	}
+
+	return nil
+}
+
 // ForceDelete iterates the Frisbee CRDs and remove its finalizers.
 func ForceDelete(testName string) error {
 	// CRDS without finalizers:
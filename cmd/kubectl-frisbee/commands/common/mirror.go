@@ -0,0 +1,227 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/carv-ics-forth/frisbee/pkg/images"
+	"github.com/pkg/errors"
+)
+
+/*******************************************************************
+
+			Mirroring of Images for Air-gapped Clusters
+
+*******************************************************************/
+
+// literalImage matches the literal image references of YAML files (e.g, image: "redis:6").
+var literalImage = regexp.MustCompile(`^(\s*-?\s*image:\s*)(["']?)([^"'\s{}#]+)(["']?)(\s*(?:#.*)?)$`)
+
+// templatedImage matches the image references that are resolved at runtime (e.g, image: {{.inputs.parameters.image}}).
+var templatedImage = regexp.MustCompile(`^\s*-?\s*image:.*\{\{`)
+
+// MirrorResult summarizes the mirroring of a set of files.
+type MirrorResult struct {
+	// Images maps the original images to the images in the mirror registry.
+	Images map[string]string `json:"images"`
+
+	// Templated lists the locations (file:line) of image references that cannot be rewritten, because they are
+	// resolved at runtime. These images must be redirected by the image rewrite rules of the operator.
+	Templated []string `json:"templated,omitempty"`
+}
+
+func (in *MirrorResult) Table() (header []string, output [][]string) {
+	header = []string{"Image", "Mirror"}
+
+	sources := make([]string, 0, len(in.Images))
+	for source := range in.Images {
+		sources = append(sources, source)
+	}
+
+	sort.Strings(sources)
+
+	for _, source := range sources {
+		output = append(output, []string{source, in.Images[source]})
+	}
+
+	return header, output
+}
+
+// PreloadList returns the images to pre-load in the mirror registry, one "<source> <mirror>" pair per line.
+func (in *MirrorResult) PreloadList() string {
+	_, rows := in.Table()
+
+	var list strings.Builder
+
+	for _, row := range rows {
+		list.WriteString(row[0] + " " + row[1] + "\n")
+	}
+
+	return list.String()
+}
+
+// MirrorFiles rewrites the image references of a scenario, a chart directory, or a chart archive (.tgz) to the
+// mirror registry, and writes the results under dst. Files that are not YAML are copied as they are.
+// Archives are extracted into dst. Because chart archives carry the chart directory, the chart is placed in
+// dst/<chart>.
+func MirrorFiles(src string, dst string, registry string, result *MirrorResult) error {
+	if result.Images == nil {
+		result.Images = make(map[string]string)
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return errors.Wrapf(err, "cannot access '%s'", src)
+	}
+
+	switch {
+	case info.IsDir():
+		return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+
+			rel, err := filepath.Rel(src, path)
+			if err != nil {
+				return err
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return errors.Wrapf(err, "cannot read '%s'", path)
+			}
+
+			return mirrorFile(path, filepath.Join(dst, rel), data, registry, result)
+		})
+
+	case IsChartArchive(src):
+		return mirrorArchive(src, dst, registry, result)
+
+	default:
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return errors.Wrapf(err, "cannot read '%s'", src)
+		}
+
+		return mirrorFile(src, dst, data, registry, result)
+	}
+}
+
+// IsChartArchive returns true if the path refers to a packaged chart.
+func IsChartArchive(path string) bool {
+	return strings.HasSuffix(path, ".tgz") || strings.HasSuffix(path, ".tar.gz")
+}
+
+func mirrorArchive(src string, dst string, registry string, result *MirrorResult) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return errors.Wrapf(err, "cannot open '%s'", src)
+	}
+
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return errors.Wrapf(err, "cannot decompress '%s'", src)
+	}
+
+	defer gz.Close()
+
+	archive := tar.NewReader(gz)
+
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return errors.Wrapf(err, "cannot read '%s'", src)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// reject entries that escape the destination directory.
+		name := filepath.Clean(header.Name)
+		if filepath.IsAbs(name) || strings.HasPrefix(name, "..") {
+			return errors.Errorf("illegal path '%s' in '%s'", header.Name, src)
+		}
+
+		data, err := io.ReadAll(archive)
+		if err != nil {
+			return errors.Wrapf(err, "cannot read '%s' from '%s'", header.Name, src)
+		}
+
+		if err := mirrorFile(src+":"+name, filepath.Join(dst, name), data, registry, result); err != nil {
+			return err
+		}
+	}
+}
+
+func mirrorFile(src string, dst string, data []byte, registry string, result *MirrorResult) error {
+	if ext := filepath.Ext(dst); ext == ".yaml" || ext == ".yml" {
+		data = mirrorYAML(src, data, registry, result)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return errors.Wrapf(err, "cannot create directory for '%s'", dst)
+	}
+
+	return os.WriteFile(dst, data, 0o644) //nolint:gosec
+}
+
+func mirrorYAML(src string, data []byte, registry string, result *MirrorResult) []byte {
+	lines := strings.Split(string(data), "\n")
+
+	for i, line := range lines {
+		if templatedImage.MatchString(line) {
+			result.Templated = append(result.Templated, fmt.Sprintf("%s:%d", src, i+1))
+
+			continue
+		}
+
+		match := literalImage.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		source := match[3]
+		mirror := images.Mirror(source, registry)
+
+		if strings.HasPrefix(source, strings.TrimSuffix(registry, "/")+"/") {
+			// already mirrored.
+			continue
+		}
+
+		result.Images[source] = mirror
+		lines[i] = match[1] + match[2] + mirror + match[4] + match[5]
+	}
+
+	return []byte(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/carv-ics-forth/frisbee/pkg/home"
+	"github.com/pkg/errors"
+)
+
+/*******************************************************************
+
+			Provenance of Scenarios
+
+*******************************************************************/
+
+/*
+	Scenarios (and packaged charts) can be signed with cosign, using a key pair:
+
+		cosign generate-key-pair
+		cosign sign-blob --key cosign.key --output-signature scenario.yaml.sig scenario.yaml
+
+	The public keys that are trusted to sign scenarios are placed in the TrustedKeys directory, or are given
+	explicitly with --key. Once at least one key is trusted, the submission of unsigned scenarios is refused.
+
+	Only key-based signatures are supported. Keyless signatures (Fulcio/Rekor) require network access to the
+	transparency log, and are not verified.
+*/
+
+// TrustedKeys is the directory with the public keys (PEM) that are trusted to sign scenarios.
+var TrustedKeys = home.ConfigPath("keys")
+
+// SignatureExt is the extension of the detached signature of a file (e.g, scenario.yaml.sig).
+const SignatureExt = ".sig"
+
+// LoadTrustedKeys returns the public keys found in the TrustedKeys directory, and in the given files.
+func LoadTrustedKeys(keyFiles ...string) ([]crypto.PublicKey, error) {
+	entries, err := os.ReadDir(TrustedKeys)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrapf(err, "cannot read trusted keys from '%s'", TrustedKeys)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".pub" {
+			keyFiles = append(keyFiles, filepath.Join(TrustedKeys, entry.Name()))
+		}
+	}
+
+	keys := make([]crypto.PublicKey, 0, len(keyFiles))
+
+	for _, keyFile := range keyFiles {
+		key, err := loadPublicKey(keyFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot load key '%s'", keyFile)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func loadPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.Errorf("not a PEM file")
+	}
+
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// VerifyFile checks that the detached signature of the file is produced by one of the trusted keys.
+// The signature is expected next to the file, with the SignatureExt suffix.
+// Both raw cosign signatures and cosign bundles are accepted.
+func VerifyFile(path string, keys []crypto.PublicKey) error {
+	if len(keys) == 0 {
+		return errors.Errorf("no trusted keys")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "cannot read '%s'", path)
+	}
+
+	signature, err := readSignature(path + SignatureExt)
+	if err != nil {
+		return errors.Wrapf(err, "cannot read signature of '%s'", path)
+	}
+
+	for _, key := range keys {
+		if verifySignature(key, data, signature) {
+			return nil
+		}
+	}
+
+	return errors.Errorf("signature of '%s' does not match any trusted key", path)
+}
+
+func readSignature(path string) ([]byte, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded := strings.TrimSpace(string(raw))
+
+	// cosign bundles carry the signature in a JSON document.
+	if strings.HasPrefix(encoded, "{") {
+		var bundle struct {
+			Base64Signature string `json:"base64Signature"`
+		}
+
+		if err := json.Unmarshal(raw, &bundle); err != nil {
+			return nil, errors.Wrapf(err, "malformed bundle")
+		}
+
+		encoded = bundle.Base64Signature
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Wrapf(err, "malformed signature")
+	}
+
+	return signature, nil
+}
+
+func verifySignature(key crypto.PublicKey, data, signature []byte) bool {
+	digest := sha256.Sum256(data)
+
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(k, digest[:], signature)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, data, signature)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2022-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"os"
+	"os/user"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// SubmitterLabel records who submitted the test, on the namespace of the test.
+const SubmitterLabel = "frisbee.dev/submitted-by"
+
+// invalidLabelChars are the characters that are not allowed in the value of a label.
+var invalidLabelChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Submitter returns the name of the local user, as a label value. FRISBEE_SUBMITTER takes precedence, for
+// environments where the local user is shared (e.g, CI runners).
+func Submitter() string {
+	name := os.Getenv("FRISBEE_SUBMITTER")
+
+	if name == "" {
+		if current, err := user.Current(); err == nil {
+			name = current.Username
+		}
+	}
+
+	return SubmitterLabelValue(name)
+}
+
+// SubmitterLabelValue converts a user name (e.g, an email, or DOMAIN\user) to a valid label value.
+func SubmitterLabelValue(name string) string {
+	value := invalidLabelChars.ReplaceAllString(name, "-")
+
+	if len(value) > validation.LabelValueMaxLength {
+		value = value[:validation.LabelValueMaxLength]
+	}
+
+	// label values must begin and end with an alphanumeric character.
+	return strings.Trim(value, "._-")
+}
+
+// TestNamespaceLabels returns the labels of a new test namespace: the test is managed by Frisbee, and it is
+// attributed to the submitter.
+func TestNamespaceLabels() []string {
+	labels := []string{ManagedNamespace}
+
+	if submitter := Submitter(); submitter != "" {
+		labels = append(labels, SubmitterLabel+"="+submitter)
+	}
+
+	return labels
+}
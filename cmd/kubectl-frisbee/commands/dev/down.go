@@ -0,0 +1,50 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dev
+
+import (
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewDownCmd() *cobra.Command {
+	var name string
+
+	cmd := &cobra.Command{
+		Use:               "down",
+		Short:             "Delete the local kind cluster",
+		ValidArgsFunction: common.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			exists, err := common.KindClusterExists(name)
+			ui.ExitOnError("Listing kind clusters", err)
+
+			if !exists {
+				ui.Failf("kind cluster '%s' does not exist.", name)
+			}
+
+			_, err = common.Kind("delete", "cluster", "--name", name)
+			ui.ExitOnError("Deleting kind cluster", err)
+
+			ui.Success("Cluster deleted:", name)
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", DefaultClusterName, "the name of the kind cluster.")
+
+	return cmd
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dev
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/install"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/scaffold"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// DefaultClusterName is the name of the kind cluster used for development.
+	DefaultClusterName = "frisbee"
+
+	// SystemChartInRepo provides the telemetry stack of the sample scenario.
+	SystemChartInRepo = "frisbee/system"
+
+	// IngressManifest deploys an ingress-nginx controller that is tailored for kind.
+	IngressManifest = "https://raw.githubusercontent.com/kubernetes/ingress-nginx/main/deploy/static/provider/kind/deploy.yaml"
+)
+
+// kindConfig exposes the ingress of the cluster on the ports 80 and 443 of the host.
+const kindConfig = `kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+nodes:
+  - role: control-plane
+    kubeadmConfigPatches:
+      - |
+        kind: InitConfiguration
+        nodeRegistration:
+          kubeletExtraArgs:
+            node-labels: "ingress-ready=true"
+    extraPortMappings:
+      - containerPort: 80
+        hostPort: 80
+        protocol: TCP
+      - containerPort: 443
+        hostPort: 443
+        protocol: TCP
+`
+
+// laptopValues tailor the platform for a single-node cluster on a laptop.
+// Dynamic volumes are disabled, and Chaos-Mesh talks to the containerd of the kind node.
+var laptopValues = []string{
+	"global.domainName=localhost",
+	"global.ingressClass=nginx",
+	"openebs.enabled=false",
+	"chaos-mesh.controllerManager.replicaCount=1",
+	"chaos-mesh.chaosDaemon.runtime=containerd",
+	"chaos-mesh.chaosDaemon.socketPath=/run/containerd/containerd.sock",
+}
+
+type UpOptions struct {
+	common.FrisbeeInstallOptions
+
+	// Name is the name of the kind cluster.
+	Name string
+
+	// Chart is the chart of the Frisbee platform.
+	Chart string
+
+	// SystemChart is the chart that provides the telemetry stack of the sample scenario.
+	SystemChart string
+
+	// Sample is the scaffold of the sample scenario.
+	Sample string
+
+	// SampleTest is the name of the test that runs the sample scenario.
+	SampleTest string
+
+	// Retention is how long Prometheus keeps the metrics of the sample scenario.
+	Retention string
+
+	// NoIngress skips the installation of the ingress controller.
+	NoIngress bool
+
+	// NoSample skips the submission of the sample scenario.
+	NoSample bool
+}
+
+func UpCmdFlags(cmd *cobra.Command, options *UpOptions) {
+	cmd.Flags().StringVar(&options.Name, "name", DefaultClusterName, "the name of the kind cluster.")
+	cmd.Flags().StringVar(&options.Chart, "chart", install.FrisbeeChartInRepo, "chart of the Frisbee platform.")
+	cmd.Flags().StringVar(&options.SystemChart, "system-chart", SystemChartInRepo, "chart of the telemetry stack.")
+	cmd.Flags().StringVar(&options.Sample, "sample", "client-server", "the scaffold of the sample scenario.")
+	cmd.Flags().StringVar(&options.SampleTest, "sample-test", "sample", "the name of the test that runs the sample scenario.")
+	cmd.Flags().StringVar(&options.Retention, "retention", "2h", "how long Prometheus keeps the metrics of the sample scenario.")
+	cmd.Flags().BoolVar(&options.NoIngress, "no-ingress", false, "don't install the ingress controller.")
+	cmd.Flags().BoolVar(&options.NoSample, "no-sample", false, "don't submit the sample scenario.")
+
+	common.PopulateInstallFlags(cmd, &options.FrisbeeInstallOptions)
+}
+
+func NewUpCmd() *cobra.Command {
+	var options UpOptions
+
+	cmd := &cobra.Command{
+		Use:   "up",
+		Short: "Create a local kind cluster with Frisbee, Chaos-Mesh, ingress, and a sample scenario",
+		Long: `Create a single-node kind cluster that is tailored for laptops.
+The platform runs without persistent volumes, and Prometheus keeps the metrics for a short period.`,
+		Example: `# Create the cluster and run the sample scenario:
+  kubectl frisbee dev up
+# Use the charts of a local checkout:
+  kubectl frisbee dev up --chart ./charts/platform --system-chart ./charts/system
+`,
+		ValidArgsFunction: common.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			/*---------------------------------------------------*
+			 * Create Kind Cluster
+			 *---------------------------------------------------*/
+			err := createCluster(options.Name)
+			ui.ExitOnError("Creating kind cluster", err)
+
+			/*---------------------------------------------------*
+			 * Install Ingress
+			 *---------------------------------------------------*/
+			if !options.NoIngress {
+				err := installIngress()
+				ui.ExitOnError("Installing ingress", err)
+				ui.Success("Ingress installed:", "ingress-nginx")
+			}
+
+			/*---------------------------------------------------*
+			 * Install Frisbee Platform
+			 *---------------------------------------------------*/
+			command := []string{
+				"upgrade", "--install", "--wait",
+				"--namespace", common.FrisbeeNamespace, "--create-namespace",
+			}
+
+			for _, value := range laptopValues {
+				command = append(command, "--set", value)
+			}
+
+			command = append(command, common.FrisbeeInstallation, options.Chart)
+
+			common.InstallFrisbeeOnK8s(command, &options.FrisbeeInstallOptions)
+			ui.Success("Frisbee installed:", options.Chart)
+
+			/*---------------------------------------------------*
+			 * Submit Sample Scenario
+			 *---------------------------------------------------*/
+			if !options.NoSample {
+				err := submitSample(&options)
+				ui.ExitOnError("Submitting sample scenario", err)
+				ui.Success("Sample scenario submitted:", options.SampleTest)
+
+				env.Default.Hint("To inspect the sample scenario use:",
+					"kubectl frisbee inspect test ", options.SampleTest)
+			}
+
+			env.Default.Hint("To delete the cluster use:", "kubectl frisbee dev down --name ", options.Name)
+		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			ui.NL()
+			ui.Success(" Happy Testing! 🚀")
+			ui.NL()
+		},
+	}
+
+	UpCmdFlags(cmd, &options)
+
+	return cmd
+}
+
+func createCluster(name string) error {
+	exists, err := common.KindClusterExists(name)
+	if err != nil {
+		return errors.Wrapf(err, "cannot list kind clusters")
+	}
+
+	if exists {
+		ui.Warn("Found existing kind cluster. Reuse it:", name)
+
+		_, err := common.Kubectl(common.ClusterScope, "config", "use-context", "kind-"+name)
+
+		return errors.Wrapf(err, "cannot switch to cluster '%s'", name)
+	}
+
+	configFile, err := os.CreateTemp("", "kind-config-*.yaml")
+	if err != nil {
+		return errors.Wrapf(err, "cannot create kind config")
+	}
+
+	defer os.Remove(configFile.Name())
+
+	if _, err := configFile.WriteString(kindConfig); err != nil {
+		return errors.Wrapf(err, "cannot write kind config")
+	}
+
+	if err := configFile.Close(); err != nil {
+		return errors.Wrapf(err, "cannot close kind config")
+	}
+
+	ui.Info("Creating kind cluster...")
+
+	command := []string{"create", "cluster", "--name", name, "--config", configFile.Name(), "--wait", "5m"}
+
+	if env.Default.Debug {
+		_, err = common.LoggedKind(command...)
+	} else {
+		_, err = common.Kind(command...)
+	}
+
+	return err
+}
+
+func installIngress() error {
+	ui.Info("Installing ingress controller...")
+
+	if _, err := common.Kubectl(common.ClusterScope, "apply", "-f", IngressManifest); err != nil {
+		return errors.Wrapf(err, "cannot apply '%s'", IngressManifest)
+	}
+
+	_, err := common.Kubectl("ingress-nginx", "wait", "pod",
+		"--for=condition=ready",
+		"--selector=app.kubernetes.io/component=controller",
+		"--timeout=5m",
+	)
+
+	return errors.Wrapf(err, "ingress controller is not ready")
+}
+
+func submitSample(options *UpOptions) error {
+	index, err := scaffold.LoadIndex()
+	if err != nil {
+		return err
+	}
+
+	selected, err := index.Get(options.Sample)
+	if err != nil {
+		return err
+	}
+
+	scenario, err := selected.Render(scaffold.Parameters{Name: options.SampleTest})
+	if err != nil {
+		return err
+	}
+
+	out, err := common.Kubectl(common.ClusterScope, "get", "namespace", options.SampleTest)
+	if err == nil {
+		ui.Warn("Found existing sample test. Skip submission:", options.SampleTest)
+
+		return nil
+	}
+
+	if !common.ErrNotFound(out) {
+		return errors.Wrapf(err, "cannot query namespace '%s'", options.SampleTest)
+	}
+
+	if err := common.CreateNamespace(options.SampleTest, common.TestNamespaceLabels()...); err != nil {
+		return err
+	}
+
+	// The telemetry stack keeps the metrics only for a short period, to save the disk of the laptop.
+	if _, err := common.Helm(options.SampleTest,
+		"upgrade", "--install", "system", options.SystemChart,
+		"--set", "telemetry.prometheus.retention="+options.Retention,
+	); err != nil {
+		return errors.Wrapf(err, "cannot install '%s'", options.SystemChart)
+	}
+
+	for _, dependency := range selected.Charts {
+		chart, err := common.ResolveChart(dependency)
+		if err != nil {
+			return errors.Wrapf(err, "cannot resolve dependency '%s'", dependency)
+		}
+
+		if _, err := common.Helm(options.SampleTest, "upgrade", "--install", filepath.Base(dependency), chart); err != nil {
+			return errors.Wrapf(err, "cannot install dependency '%s'", dependency)
+		}
+	}
+
+	scenarioFile := filepath.Join(os.TempDir(), options.SampleTest+".yml")
+
+	if err := os.WriteFile(scenarioFile, scenario, 0o600); err != nil {
+		return errors.Wrapf(err, "cannot write scenario")
+	}
+
+	defer os.Remove(scenarioFile)
+
+	return common.RunTest(options.SampleTest, scenarioFile, common.ValidationNone)
+}
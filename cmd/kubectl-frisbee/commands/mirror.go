@@ -0,0 +1,119 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+type MirrorCmdOptions struct {
+	// Output is the directory where the rewritten files are stored.
+	Output string
+
+	// Images is the file where the list of images to pre-load is stored.
+	Images string
+}
+
+func MirrorCmdCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch {
+	case len(args) == 0:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+
+	default:
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+}
+
+func NewMirrorCmd() *cobra.Command {
+	var options MirrorCmdOptions
+
+	cmd := &cobra.Command{
+		Use:   "mirror <registry> <scenario|chart>...",
+		Short: "Rewrite the images of scenarios and charts to a private registry",
+		Long: `Mirror copies scenarios and charts into the output directory, with their images redirected to a private
+registry, and lists the images that must be pre-loaded in the registry. Charts can be directories, archives,
+or short names of the local cache.
+
+Images that are resolved at runtime (e.g, from template inputs) cannot be rewritten. For these images,
+configure the image rewrite rules of the operator (operator.imageRewrites).`,
+		Example: `# Mirror a scenario and its charts:
+  kubectl frisbee mirror registry.local:5000 scenario.yaml ycsb ./charts/mydb -o offline
+# Pre-load the images with crane:
+  while read src dst; do crane copy $src $dst; done < offline/images.txt
+`,
+		ValidArgsFunction: MirrorCmdCompletion,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			env.Logo()
+			ui.SetVerbose(env.Default.Debug)
+		},
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				ui.Failf("Pass the registry and at least one scenario or chart.")
+			}
+
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			registry, sources := args[0], args[1:]
+
+			var result common.MirrorResult
+
+			for _, source := range sources {
+				path, err := common.ResolveChart(source)
+				ui.ExitOnError("Resolving "+source, err)
+
+				dst := filepath.Join(options.Output, filepath.Base(source))
+				if common.IsChartArchive(path) {
+					// archives carry the chart directory.
+					dst = options.Output
+				}
+
+				err = common.MirrorFiles(path, dst, registry, &result)
+				ui.ExitOnError("Mirroring "+source, err)
+			}
+
+			imagesFile := options.Images
+			if imagesFile == "" {
+				imagesFile = filepath.Join(options.Output, "images.txt")
+			}
+
+			err := os.WriteFile(imagesFile, []byte(result.PreloadList()), 0o644) //nolint:gosec
+			ui.ExitOnError("Saving images list", err)
+
+			err = common.RenderList(&result, os.Stdout)
+			ui.PrintOnError("Rendering list", err)
+
+			if len(result.Templated) > 0 {
+				ui.Warn("Images resolved at runtime. Use the image rewrite rules of the operator:", result.Templated...)
+			}
+
+			ui.Success("Mirrored into:", options.Output)
+			ui.Success("Images to pre-load:", imagesFile)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Output, "output", "o", "mirror", "directory for the rewritten scenarios and charts.")
+	cmd.Flags().StringVar(&options.Images, "images", "", "file for the list of images to pre-load (default <output>/images.txt).")
+
+	return cmd
+}
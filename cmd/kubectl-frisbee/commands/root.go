@@ -42,17 +42,31 @@ func NewRootCmd() *cobra.Command {
 		// Platform Installation
 		NewInstallCmd(),
 		NewUninstallCmd(),
+		NewDevCmd(),
+		NewSelftestCmd(),
 
 		// Test Management
 		NewValidateCmd(),
 		NewSubmitCmd(),
 		NewGetCmd(),
 		NewDeleteCmd(),
+		NewAbortCmd(),
 		NewInspectCmd(),
+		NewConfigCmd(),
+		NewConvertCmd(),
 
 		// Analysis Tools
 		NewSaveCmd(),
 		NewReportCmd(),
+
+		// Scaffolding
+		NewNewCmd(),
+
+		// Charts Management
+		NewChartsCmd(),
+
+		// Air-gapped Clusters
+		NewMirrorCmd(),
 	)
 
 	return cmd
@@ -43,6 +43,7 @@ func NewSaveCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(tests.NewSaveTestsCmd())
+	cmd.AddCommand(tests.NewSaveTimelineCmd())
 
 	return cmd
 }
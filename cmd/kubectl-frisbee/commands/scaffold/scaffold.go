@@ -0,0 +1,134 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package scaffold generates runnable scenarios from a curated index of scaffolds.
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+//go:embed scaffolds
+var scaffolds embed.FS
+
+const indexFile = "scaffolds/index.yaml"
+
+// Scaffold is an entry of the index.
+type Scaffold struct {
+	// Name is the identifier of the scaffold.
+	Name string `json:"name"`
+
+	// Description is a short summary of the scenario.
+	Description string `json:"description"`
+
+	// File is the path of the scaffold, relative to the index.
+	File string `json:"file"`
+
+	// Charts are the system charts that the scenario depends on.
+	Charts []string `json:"charts,omitempty"`
+}
+
+// Index is the curated list of scaffolds.
+type Index struct {
+	Scaffolds []Scaffold `json:"scaffolds"`
+}
+
+func (in *Index) Table() (header []string, output [][]string) {
+	header = []string{"Name", "Description", "Charts"}
+
+	for _, scaffold := range in.Scaffolds {
+		charts := "-"
+		if len(scaffold.Charts) > 0 {
+			charts = fmt.Sprint(scaffold.Charts)
+		}
+
+		output = append(output, []string{scaffold.Name, scaffold.Description, charts})
+	}
+
+	return header, output
+}
+
+// LoadIndex returns the curated index of scaffolds.
+func LoadIndex() (*Index, error) {
+	data, err := scaffolds.ReadFile(indexFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read index")
+	}
+
+	var index Index
+
+	if err := yaml.Unmarshal(data, &index); err != nil {
+		return nil, errors.Wrapf(err, "cannot decode index")
+	}
+
+	return &index, nil
+}
+
+// Get returns the scaffold with the given name.
+func (in *Index) Get(name string) (*Scaffold, error) {
+	for i, scaffold := range in.Scaffolds {
+		if scaffold.Name == name {
+			return &in.Scaffolds[i], nil
+		}
+	}
+
+	return nil, errors.Errorf("scaffold '%s' does not exist", name)
+}
+
+// Names returns the names of all the scaffolds.
+func (in *Index) Names() []string {
+	names := make([]string, 0, len(in.Scaffolds))
+
+	for _, scaffold := range in.Scaffolds {
+		names = append(names, scaffold.Name)
+	}
+
+	return names
+}
+
+// Parameters are the values used for rendering a scaffold.
+type Parameters struct {
+	// Name is the name of the scenario, and the prefix of its templates.
+	Name string
+}
+
+// Render generates the scenario from the scaffold. Scaffolds use [[ ]] as delimiters,
+// so that the Frisbee macros ({{ }}) are passed as they are to the scenario.
+func (s *Scaffold) Render(params Parameters) ([]byte, error) {
+	data, err := scaffolds.ReadFile("scaffolds/" + s.File)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read scaffold '%s'", s.Name)
+	}
+
+	tmpl, err := template.New(s.Name).Delims("[[", "]]").Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot parse scaffold '%s'", s.Name)
+	}
+
+	var out bytes.Buffer
+
+	if err := tmpl.Execute(&out, params); err != nil {
+		return nil, errors.Wrapf(err, "cannot render scaffold '%s'", s.Name)
+	}
+
+	return out.Bytes(), nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/scaffold"
+	"sigs.k8s.io/yaml"
+)
+
+func TestRenderScaffolds(t *testing.T) {
+	index, err := scaffold.LoadIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(index.Scaffolds) == 0 {
+		t.Fatal("empty index")
+	}
+
+	for _, entry := range index.Scaffolds {
+		entry := entry
+
+		t.Run(entry.Name, func(t *testing.T) {
+			out, err := entry.Render(scaffold.Parameters{Name: "my-test"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var found bool
+
+			for _, doc := range bytes.Split(out, []byte("\n---\n")) {
+				var scenario v1alpha1.Scenario
+
+				if err := yaml.Unmarshal(doc, &scenario); err != nil {
+					t.Fatalf("invalid document: %v", err)
+				}
+
+				if scenario.Kind == "Scenario" {
+					found = true
+
+					if scenario.GetName() != "my-test" {
+						t.Fatalf("expected scenario 'my-test', got '%s'", scenario.GetName())
+					}
+
+					if len(scenario.Spec.Actions) == 0 {
+						t.Fatal("scenario has no actions")
+					}
+				}
+			}
+
+			if !found {
+				t.Fatal("scaffold does not contain a scenario")
+			}
+
+			if strings.Contains(string(out), "[[") {
+				t.Fatal("scaffold is not fully rendered")
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2022-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/dev"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/selftest"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/tests"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+type SelftestCmdOptions struct {
+	// SystemChart is the chart that provides the system templates of the smoke scenario.
+	SystemChart string
+
+	// Timeout is how long to wait for the smoke scenario to complete.
+	Timeout time.Duration
+
+	// Keep retains the test namespace after a successful run.
+	Keep bool
+}
+
+func SelftestCmdFlags(cmd *cobra.Command, options *SelftestCmdOptions) {
+	cmd.Flags().StringVar(&options.SystemChart, "system-chart", dev.SystemChartInRepo, "chart of the telemetry stack and the chaos templates.")
+	cmd.Flags().DurationVarP(&options.Timeout, "timeout", "t", 5*time.Minute, "wait for the smoke scenario to complete.")
+	cmd.Flags().BoolVar(&options.Keep, "keep", false, "keep the test after a successful run. Failed runs are always kept for inspection.")
+}
+
+func NewSelftestCmd() *cobra.Command {
+	var options SelftestCmdOptions
+
+	cmd := &cobra.Command{
+		Use:   "selftest [Name]",
+		Short: "Validate the installation by running a built-in smoke scenario",
+		Long: `Run a smoke scenario that exercises the creation of services and clusters, the telemetry stack,
+the injection of a network fault, and an assertion. The scenario completes in about 2 minutes.
+Exits with 0 on success, 1 on failure, and 2 on assertion error.`,
+		Example: `# Validate the installation:
+  kubectl frisbee selftest
+# Use the system chart of a local checkout, and keep the test for inspection:
+  kubectl frisbee selftest --system-chart ./charts/system --keep
+`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: common.NoArgs,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			env.Logo()
+			ui.SetVerbose(env.Default.Debug)
+
+			if !common.CRDsExist(common.Scenarios) {
+				ui.Failf("Frisbee is not installed on the kubernetes cluster.")
+			}
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			testName := fmt.Sprintf("selftest-%d", rand.Intn(1000))
+			if len(args) == 1 {
+				testName = args[0]
+			}
+
+			/*---------------------------------------------------
+			 * Render the smoke scenario
+			 *---------------------------------------------------*/
+			scenario, err := selftest.Smoke(testName)
+			ui.ExitOnError("Rendering smoke scenario", err)
+
+			testFile := filepath.Join(os.TempDir(), testName+".yml")
+
+			err = os.WriteFile(testFile, scenario, 0o600)
+			ui.ExitOnError("Writing smoke scenario", err)
+
+			err = common.RunTest(testName, testFile, common.ValidationClient)
+			ui.ExitOnError("Validating smoke scenario", err)
+
+			/*---------------------------------------------------
+			 * Prepare the test environment
+			 *---------------------------------------------------*/
+			// unlike regular tests, the smoke scenario injects a fault. Thus, Chaos Mesh is mandatory.
+			err = common.VerifyClusterChaosMesh()
+			ui.ExitOnError("Verifying Chaos Mesh", err)
+
+			err = common.CreateNamespace(testName, common.TestNamespaceLabels()...)
+			ui.ExitOnError("Creating managed namespace", err)
+			ui.Success("Namespace Created:", testName)
+
+			_, err = common.Helm(testName, "upgrade", "--install", "system", options.SystemChart)
+			ui.ExitOnError("Installing system chart: "+options.SystemChart, err)
+			ui.Success("Dependencies Installed:", options.SystemChart)
+
+			/*---------------------------------------------------
+			 * Run the smoke scenario
+			 *---------------------------------------------------*/
+			err = common.RunTest(testName, testFile, common.ValidationNone)
+			os.Remove(testFile)
+			ui.ExitOnError("Starting smoke scenario", err)
+			ui.Success("Scenario submitted.")
+
+			code, err := tests.WatchTest(cmd.Context(), testName, options.Timeout)
+			if err != nil || code != tests.ExitSuccess {
+				env.Default.Hint("To inspect the execution:", "kubectl frisbee inspect test ", testName)
+				ui.ExitOnError("Watching smoke scenario", err)
+				ui.Warn("Frisbee installation is not healthy. Smoke scenario failed:", testName)
+
+				os.Exit(code)
+			}
+
+			ui.Success("Frisbee installation is healthy.")
+
+			if options.Keep {
+				env.Default.Hint("To inspect the execution:", "kubectl frisbee inspect test ", testName)
+
+				return
+			}
+
+			err = common.DeleteNamespaces("", testName)
+			ui.ExitOnError("Deleting smoke test", err)
+		},
+	}
+
+	SelftestCmdFlags(cmd, &options)
+
+	return cmd
+}
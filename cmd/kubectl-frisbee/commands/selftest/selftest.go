@@ -0,0 +1,46 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package selftest provides the built-in scenario that validates an installation of Frisbee.
+package selftest
+
+import (
+	"bytes"
+	_ "embed"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+//go:embed smoke.yml
+var smoke string
+
+// Smoke renders the smoke scenario, and its templates, under the given name. The scenario uses the system
+// templates for telemetry and chaos, and thus it expects the system chart to be installed in the test namespace.
+func Smoke(name string) ([]byte, error) {
+	tmpl, err := template.New("smoke").Delims("[[", "]]").Option("missingkey=error").Parse(smoke)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot parse smoke scenario")
+	}
+
+	var out bytes.Buffer
+
+	if err := tmpl.Execute(&out, struct{ Name string }{Name: name}); err != nil {
+		return nil, errors.Wrapf(err, "cannot render smoke scenario")
+	}
+
+	return out.Bytes(), nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selftest_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/selftest"
+	"sigs.k8s.io/yaml"
+)
+
+func TestSmoke(t *testing.T) {
+	out, err := selftest.Smoke("selftest-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(out), "[[") {
+		t.Fatal("smoke scenario is not fully rendered")
+	}
+
+	templates := make(map[string]bool)
+
+	var scenario *v1alpha1.Scenario
+
+	for _, doc := range bytes.Split(out, []byte("\n---\n")) {
+		var meta struct {
+			Kind     string `json:"kind"`
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+		}
+
+		if err := yaml.Unmarshal(doc, &meta); err != nil {
+			t.Fatalf("invalid document: %v", err)
+		}
+
+		switch meta.Kind {
+		case "Template":
+			var template v1alpha1.Template
+
+			if err := yaml.UnmarshalStrict(doc, &template); err != nil {
+				t.Fatalf("invalid template: %v", err)
+			}
+
+			templates[template.GetName()] = true
+
+		case "Scenario":
+			scenario = &v1alpha1.Scenario{}
+
+			if err := yaml.UnmarshalStrict(doc, scenario); err != nil {
+				t.Fatalf("invalid scenario: %v", err)
+			}
+		}
+	}
+
+	if scenario == nil || scenario.GetName() != "selftest-1" {
+		t.Fatal("smoke scenario is missing")
+	}
+
+	// the smoke scenario must exercise services, clusters, chaos, and assertions.
+	exercised := make(map[v1alpha1.ActionType]bool)
+	asserted := false
+
+	for _, action := range scenario.Spec.Actions {
+		exercised[action.ActionType] = true
+
+		if !action.Assert.IsZero() {
+			asserted = true
+		}
+
+		switch {
+		case action.Service != nil && !strings.HasPrefix(action.Service.TemplateRef, "frisbee.system."):
+			if !templates[action.Service.TemplateRef] {
+				t.Errorf("action '%s' refers to missing template '%s'", action.Name, action.Service.TemplateRef)
+			}
+
+		case action.Cluster != nil && !templates[action.Cluster.TemplateRef]:
+			t.Errorf("action '%s' refers to missing template '%s'", action.Name, action.Cluster.TemplateRef)
+		}
+	}
+
+	for _, actionType := range []v1alpha1.ActionType{
+		v1alpha1.ActionService, v1alpha1.ActionCluster, v1alpha1.ActionChaos, v1alpha1.ActionDelete,
+	} {
+		if !exercised[actionType] {
+			t.Errorf("smoke scenario does not exercise '%s'", actionType)
+		}
+	}
+
+	if !asserted {
+		t.Error("smoke scenario has no assertion")
+	}
+}
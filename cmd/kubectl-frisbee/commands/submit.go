@@ -43,6 +43,7 @@ func NewSubmitCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(tests.NewSubmitTestCmd())
+	cmd.AddCommand(tests.NewSubmitSuiteCmd())
 
 	return cmd
 }
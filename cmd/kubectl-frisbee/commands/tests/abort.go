@@ -0,0 +1,72 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+func AbortTestCmdCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return common.CompleteScenarios(cmd, args, toComplete)
+	}
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func NewAbortTestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "test <testName>",
+		Aliases:           []string{"tests", "t"},
+		Short:             "Abort Test",
+		Long:              "Abort the test, run its teardown actions, and remove the remaining jobs.",
+		ValidArgsFunction: AbortTestCmdCompletion,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				ui.Failf("Pass Test name to abort.")
+			}
+
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			testName := args[0]
+
+			scenario, err := env.Default.GetFrisbeeClient().GetScenario(cmd.Context(), testName)
+			ui.ExitOnError("Getting test information", err)
+
+			switch {
+			case scenario == nil:
+				ui.Failf("test '%s' was not found", testName)
+			case scenario.Status.Phase.Is(v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed, v1alpha1.PhaseAborted):
+				ui.Failf("test '%s' is already completed. Phase: '%s'", testName, scenario.Status.Phase)
+			}
+
+			ui.Info("Aborting test: ", testName)
+
+			err = common.AbortTest(testName, scenario.GetName())
+			ui.ExitOnError("Abort "+testName, err)
+
+			env.Default.Hint("To monitor the teardown use:", "kubectl frisbee inspect tests "+testName)
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2022-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/pkg/errors"
+)
+
+// BulkStatus is the outcome of an operation on a single test.
+type BulkStatus string
+
+const (
+	BulkDone    BulkStatus = "Done"
+	BulkSkipped BulkStatus = "Skipped"
+	BulkFailed  BulkStatus = "Failed"
+)
+
+// BulkResult is the outcome of an operation on a single test, within a bulk operation.
+type BulkResult struct {
+	Test     string
+	Status   BulkStatus
+	Message  string
+	Duration time.Duration
+}
+
+// BulkOperation operates on a single test. Returning ErrSkipped marks the test as skipped.
+type BulkOperation func(ctx context.Context, test string) error
+
+// ErrSkipped indicates that the operation did not apply to the test.
+var ErrSkipped = errors.New("skipped")
+
+// RunBulk runs the operation on the tests, with up to the given number of workers. The progress is reported as
+// every test completes, because operations such as foreground deletions block for long.
+func RunBulk(ctx context.Context, verb string, tests []string, workers int, operation BulkOperation) []BulkResult {
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]BulkResult, len(tests))
+	pending := make(chan int)
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		completed int
+	)
+
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range pending {
+				start := time.Now()
+				err := operation(ctx, tests[i])
+
+				result := BulkResult{Test: tests[i], Status: BulkDone, Duration: time.Since(start)}
+
+				switch {
+				case errors.Is(err, ErrSkipped):
+					result.Status = BulkSkipped
+					result.Message = strings.TrimSuffix(err.Error(), ": "+ErrSkipped.Error())
+				case err != nil:
+					result.Status = BulkFailed
+					result.Message = err.Error()
+				}
+
+				results[i] = result
+
+				mu.Lock()
+				completed++
+				progress := fmt.Sprintf("[%d/%d]", completed, len(tests))
+
+				switch result.Status {
+				case BulkDone:
+					ui.Success(progress+" "+verb+":", result.Test, result.Duration.Round(time.Second).String())
+				case BulkSkipped:
+					ui.Warn(progress+" Skipped:", result.Test, result.Message)
+				case BulkFailed:
+					ui.Warn(progress+" Failed:", result.Test, result.Message)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range tests {
+		pending <- i
+	}
+
+	close(pending)
+	wg.Wait()
+
+	return results
+}
+
+// PrintBulkSummary prints the number of tests per outcome, along with the skipped and failed tests.
+// It returns false if any operation has failed.
+func PrintBulkSummary(verb string, results []BulkResult) bool {
+	var done, skipped, failed []string
+
+	for _, result := range results {
+		switch result.Status {
+		case BulkDone:
+			done = append(done, result.Test)
+		case BulkSkipped:
+			skipped = append(skipped, fmt.Sprintf("%s (%s)", result.Test, result.Message))
+		case BulkFailed:
+			failed = append(failed, fmt.Sprintf("%s (%s)", result.Test, result.Message))
+		}
+	}
+
+	ui.NL()
+	ui.Info(fmt.Sprintf("%s: %d, Skipped: %d, Failed: %d", verb, len(done), len(skipped), len(failed)))
+
+	if len(skipped) > 0 {
+		ui.Warn("Skipped:", skipped...)
+	}
+
+	if len(failed) > 0 {
+		ui.Warn("Failed:", failed...)
+	}
+
+	return len(failed) == 0
+}
+
+// Confirm asks the user to confirm an operation. A non-interactive input cannot confirm, and the operation must
+// be confirmed in advance (e.g, with --yes).
+func Confirm(in *os.File, out io.Writer, question string) (bool, error) {
+	info, err := in.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false, errors.New("confirmation requires an interactive terminal")
+	}
+
+	scanner := bufio.NewScanner(in)
+
+	for {
+		fmt.Fprintf(out, "%s [y/N]: ", question)
+
+		if !scanner.Scan() {
+			return false, errors.New("no answer")
+		}
+
+		switch strings.ToLower(strings.TrimSpace(scanner.Text())) {
+		case "y", "yes":
+			return true, nil
+		case "", "n", "no":
+			return false, nil
+		}
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"os"
+
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+func ConfigTestCmdCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return common.CompleteScenarios(cmd, args, toComplete)
+	}
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+type ConfigTestCmdOptions struct {
+	Duration string
+	File     string
+}
+
+func ConfigTestCmdFlags(cmd *cobra.Command, options *ConfigTestCmdOptions) {
+	cmd.Flags().StringVar(&options.Duration, "duration", common.TestTimeout, "validity of the generated credentials.")
+	cmd.Flags().StringVarP(&options.File, "file", "f", "", "write the kubeconfig to file, instead of the standard output.")
+}
+
+func NewConfigTestCmd() *cobra.Command {
+	var options ConfigTestCmdOptions
+
+	cmd := &cobra.Command{
+		Use:     "test <testName>",
+		Aliases: []string{"tests", "t"},
+		Short:   "Generate a kubeconfig scoped to the test",
+		Long: `Generate a kubeconfig whose credentials are limited to the namespace of the test.
+The credentials belong to a dedicated service account, and can be given to external tools (e.g, load generators, debuggers).`,
+		Example: `# Print the kubeconfig:
+  kubectl frisbee config test my-test
+# Use the kubeconfig with an external tool:
+  kubectl frisbee config test my-test -f my-test.kubeconfig
+  KUBECONFIG=my-test.kubeconfig k9s
+`,
+		ValidArgsFunction: ConfigTestCmdCompletion,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				ui.Failf("Pass Test name.")
+			}
+
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			testName := args[0]
+
+			scenario, err := env.Default.GetFrisbeeClient().GetScenario(cmd.Context(), testName)
+			ui.ExitOnError("Getting test information", err)
+
+			if scenario == nil {
+				ui.Failf("test '%s' was not found", testName)
+			}
+
+			err = common.CreateTestServiceAccount(testName)
+			ui.ExitOnError("Creating service account", err)
+
+			token, err := common.CreateTestToken(testName, options.Duration)
+			ui.ExitOnError("Creating token", err)
+
+			kubeconfig, err := common.GenerateTestKubeconfig(testName, token)
+			ui.ExitOnError("Generating kubeconfig", err)
+
+			if options.File == "" {
+				_, err := os.Stdout.Write(kubeconfig)
+				ui.ExitOnError("Printing kubeconfig", err)
+
+				return
+			}
+
+			err = os.WriteFile(options.File, kubeconfig, 0o600)
+			ui.ExitOnError("Writing kubeconfig", err)
+
+			ui.Success("Kubeconfig written to:", options.File)
+
+			env.Default.Hint("To use the kubeconfig:", "export KUBECONFIG="+options.File)
+		},
+	}
+
+	ConfigTestCmdFlags(cmd, &options)
+
+	return cmd
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2022-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"bufio"
+	"io"
+	"os"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/export/argo"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+type ConvertArgoCmdOptions struct {
+	// Output is the file where the workflow is written. Defaults to the standard output.
+	Output string
+
+	// ServiceAccount is the account of the workflow.
+	ServiceAccount string
+}
+
+func ConvertArgoCmdFlags(cmd *cobra.Command, options *ConvertArgoCmdOptions) {
+	cmd.Flags().StringVarP(&options.Output, "output", "o", "", "the file to write the workflow into (default stdout).")
+	cmd.Flags().StringVar(&options.ServiceAccount, "service-account", "", "the service account of the workflow. It must be allowed to manage the Frisbee objects.")
+}
+
+func NewConvertArgoCmd() *cobra.Command {
+	var options ConvertArgoCmdOptions
+
+	cmd := &cobra.Command{
+		Use:   "argo <Scenario>",
+		Short: "Convert a scenario into an Argo Workflow",
+		Long: `Convert the actions of a scenario into an Argo Workflow that creates the same Frisbee objects, in the same order.
+The objects are still run by the controllers of Frisbee. Features that depend on the scenario controller
+(e.g, vars, teardown, Seed actions) are not supported.`,
+		Example: `# Print the workflow of the scenario:
+  kubectl frisbee convert argo scenario.yml
+# Submit the workflow to Argo:
+  kubectl frisbee convert argo scenario.yml | argo submit -
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				ui.Failf("Pass Scenario File")
+			}
+
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			scenario, err := readScenario(args[0])
+			ui.ExitOnError("Reading scenario", err)
+
+			workflow, err := argo.Export(scenario, argo.Options{ServiceAccountName: options.ServiceAccount})
+			ui.ExitOnError("Converting scenario", err)
+
+			out, err := argo.Marshal(workflow)
+			ui.ExitOnError("Marshaling workflow", err)
+
+			if options.Output == "" {
+				_, err := os.Stdout.Write(out)
+				ui.ExitOnError("Writing workflow", err)
+
+				return
+			}
+
+			ui.ExitOnError("Writing workflow to "+options.Output, os.WriteFile(options.Output, out, 0o600))
+			ui.Success("Workflow generated:", options.Output)
+		},
+	}
+
+	ConvertArgoCmdFlags(cmd, &options)
+
+	return cmd
+}
+
+// readScenario returns the first Scenario of the file. Other objects, such as Templates, are skipped.
+func readScenario(path string) (*v1alpha1.Scenario, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot open '%s'", path)
+	}
+
+	defer file.Close()
+
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(file))
+
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.Errorf("no scenario in '%s'", path)
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot read '%s'", path)
+		}
+
+		var typeMeta metav1.TypeMeta
+
+		if err := yaml.Unmarshal(doc, &typeMeta); err != nil {
+			return nil, errors.Wrapf(err, "cannot decode '%s'", path)
+		}
+
+		if typeMeta.GroupVersionKind() != v1alpha1.GroupVersion.WithKind("Scenario") {
+			continue
+		}
+
+		var scenario v1alpha1.Scenario
+
+		if err := yaml.UnmarshalStrict(doc, &scenario); err != nil {
+			return nil, errors.Wrapf(err, "cannot decode scenario")
+		}
+
+		return &scenario, nil
+	}
+}
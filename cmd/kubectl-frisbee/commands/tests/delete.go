@@ -17,10 +17,15 @@ limitations under the License.
 package tests
 
 import (
-	"strings"
+	"context"
+	"fmt"
+	"os"
 
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
 	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -29,15 +34,22 @@ func DeleteTestCmdCompletion(cmd *cobra.Command, args []string, toComplete strin
 }
 
 type DeleteTestCmdOptions struct {
-	DeleteAll, Force bool
-	Selectors        []string
+	DeleteAll, Force, DryRun bool
+
+	Yes     bool
+	Workers int
+
+	Filters TestFilterOptions
 }
 
 func DeleteTestCmdFlags(cmd *cobra.Command, options *DeleteTestCmdOptions) {
 	cmd.Flags().BoolVar(&options.DeleteAll, "all", false, "Delete all tests")
-	cmd.Flags().StringSliceVarP(&options.Selectors, "label", "l", nil, "label key value pair: --label key1=value1")
+	TestFilterFlags(cmd, &options.Filters)
 
 	cmd.Flags().BoolVar(&options.Force, "force", false, "Force delete a stalled test")
+	cmd.Flags().BoolVar(&options.DryRun, "dry-run", false, "Show the tests that would be deleted, without deleting them")
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", false, "Delete multiple tests without asking for confirmation")
+	cmd.Flags().IntVar(&options.Workers, "workers", 4, "Number of tests that are deleted in parallel")
 }
 
 func NewDeleteTestsCmd() *cobra.Command {
@@ -48,9 +60,24 @@ func NewDeleteTestsCmd() *cobra.Command {
 		Aliases:           []string{"tests", "t"},
 		Short:             "Delete Test",
 		ValidArgsFunction: DeleteTestCmdCompletion,
+		Example: `# Delete the failed tests:
+  kubectl frisbee delete tests --phase Failed
+# Show my tests that are older than a day, without deleting them:
+  kubectl frisbee delete tests --submitter me --older-than 24h --dry-run
+# Delete all tests, 8 at a time, without asking for confirmation:
+  kubectl frisbee delete tests --all --workers 8 --yes
+`,
 		Args: func(cmd *cobra.Command, args []string) error {
-			if len(args) == 0 && !options.DeleteAll {
-				ui.Failf("Pass Test name, --all flag to delete all or labels to delete by labels.")
+			if len(args) == 0 && !options.DeleteAll && !options.Filters.IsSet() {
+				ui.Failf("Pass Test name, --all flag to delete all or filters (e.g, labels) to delete by filters.")
+			}
+
+			if len(args) > 0 && options.Filters.IsSet() {
+				ui.Failf("Filters are not applicable to named tests.")
+			}
+
+			if options.DeleteAll && options.Filters.IsSet() {
+				ui.Failf("Choose only one of --all or filters.")
 			}
 
 			if options.DeleteAll && options.Force {
@@ -61,6 +88,14 @@ func NewDeleteTestsCmd() *cobra.Command {
 				ui.Failf("To prevent intended deletions, --force is applicable at one test at a time.")
 			}
 
+			if options.DryRun && options.Force {
+				ui.Failf("Choose only one of --dry-run or --force.")
+			}
+
+			if options.Workers < 1 {
+				ui.Failf("--workers must be positive.")
+			}
+
 			return nil
 		},
 		Run: func(cmd *cobra.Command, args []string) {
@@ -72,33 +107,89 @@ func NewDeleteTestsCmd() *cobra.Command {
 				err := common.ForceDelete(testName)
 				ui.ExitOnError("Force Delete "+testName, err)
 
-			case options.DeleteAll:
-				ui.Info("Deleting all tests with label: ", common.ManagedNamespace)
+			default:
+				DeleteTests(cmd.Context(), args, &options)
+			}
+		},
+	}
 
-				err := common.DeleteNamespaces(common.ManagedNamespace)
-				ui.ExitOnError("Delete all tests", err)
+	DeleteTestCmdFlags(cmd, &options)
 
-			case len(args) > 0:
-				ui.Info("Deleting tests: ", args...)
+	return cmd
+}
 
-				err := common.DeleteNamespaces("", args...)
-				ui.ExitOnError("Delete tests", err)
+// DeleteTests deletes the named tests, or the tests that match the filters (or --all). The deletion of multiple
+// tests must be confirmed, and it runs in parallel. Tests that do not exist, or are already being deleted,
+// are skipped.
+func DeleteTests(ctx context.Context, testNames []string, options *DeleteTestCmdOptions) {
+	// all the tests are listed, so that the named tests can be checked as well.
+	tests, err := ListTests(ctx, &TestFilterOptions{})
+	ui.ExitOnError("Listing tests", err)
 
-			case len(options.Selectors) != 0:
-				options.Selectors = append(options.Selectors, common.ManagedNamespace)
-				selector := strings.Join(options.Selectors, ",")
+	phases := make(map[string]v1alpha1.Phase, len(tests.Items))
 
-				ui.Info("Deleting all tests with labels: ", common.ManagedNamespace)
+	for _, test := range tests.Items {
+		phases[test.GetNamespace()] = test.Status.Phase
+	}
 
-				err := common.DeleteNamespaces(selector)
-				ui.ExitOnError("Deleting tests by labels: "+selector, err)
-			default:
-				cmd.Help()
-			}
-		},
+	// resolve the filters (or --all) to the names of the tests.
+	if len(testNames) == 0 {
+		selected, err := ListTests(ctx, &options.Filters)
+		ui.ExitOnError("Selecting tests", err)
+
+		for _, test := range selected.Items {
+			testNames = append(testNames, test.GetNamespace())
+		}
 	}
 
-	DeleteTestCmdFlags(cmd, &options)
+	if len(testNames) == 0 {
+		ui.Warn("No tests match the filters.")
 
-	return cmd
+		return
+	}
+
+	if options.DryRun {
+		ui.Info("Tests to be deleted (dry run): ", testNames...)
+
+		return
+	}
+
+	if len(testNames) > 1 && !options.Yes {
+		ui.Info("Tests to be deleted: ", testNames...)
+
+		confirmed, err := Confirm(os.Stdin, os.Stdout, fmt.Sprintf("Delete %d tests?", len(testNames)))
+		if err != nil {
+			ui.Failf("Cannot confirm the deletion: %s. Use --yes to delete without confirmation.", err)
+		}
+
+		if !confirmed {
+			ui.Warn("Deletion canceled.")
+
+			return
+		}
+	}
+
+	results := RunBulk(ctx, "Deleted", testNames, options.Workers, func(ctx context.Context, testName string) error {
+		phase, exists := phases[testName]
+
+		switch {
+		case !exists:
+			return errors.Wrap(ErrSkipped, "not found")
+		case phase == "Terminating":
+			return errors.Wrap(ErrSkipped, "already terminating")
+		}
+
+		if err := common.UninstallNamespacedChaosMesh(testName); err != nil {
+			return errors.Wrapf(err, "cannot uninstall Chaos Mesh")
+		}
+
+		// the deletion is in the foreground, and it blocks until the resources of the test are removed.
+		return common.DeleteNamespaces("", testName)
+	})
+
+	if !PrintBulkSummary("Deleted", results) {
+		env.Default.Hint("To delete a stalled test:", "kubectl frisbee delete test --force <testName>")
+
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,113 @@
+/*
+Copyright 2022-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// TestFilterOptions select tests by their labels, phase, age, and submitter.
+type TestFilterOptions struct {
+	Selectors []string
+	Phases    []string
+	OlderThan time.Duration
+	Submitter string
+}
+
+func TestFilterFlags(cmd *cobra.Command, options *TestFilterOptions) {
+	cmd.Flags().StringSliceVarP(&options.Selectors, "label", "l", nil, "label key value pair: --label key1=value1")
+	cmd.Flags().StringSliceVar(&options.Phases, "phase", nil,
+		"select tests in the given phases (e.g, Running,Failed)")
+	cmd.Flags().DurationVar(&options.OlderThan, "older-than", 0,
+		"select tests that were submitted before the given duration (e.g, 24h)")
+	cmd.Flags().StringVar(&options.Submitter, "submitter", "",
+		"select tests that were submitted by the given user. Use 'me' for the current user.")
+}
+
+// IsSet returns true if any filter is given.
+func (options *TestFilterOptions) IsSet() bool {
+	return len(options.Selectors) > 0 || len(options.Phases) > 0 || options.OlderThan > 0 || options.Submitter != ""
+}
+
+// Selector returns the label selector for the namespaces of the selected tests.
+func (options *TestFilterOptions) Selector() string {
+	selectors := append([]string{common.ManagedNamespace}, options.Selectors...)
+
+	switch options.Submitter {
+	case "":
+	case "me":
+		selectors = append(selectors, common.SubmitterLabel+"="+common.Submitter())
+	default:
+		selectors = append(selectors, common.SubmitterLabel+"="+common.SubmitterLabelValue(options.Submitter))
+	}
+
+	return strings.Join(selectors, ",")
+}
+
+// Match returns true if the scenario is in one of the selected phases, and it is older than the selected age.
+// The labels are matched by the Selector.
+func (options *TestFilterOptions) Match(scenario *v1alpha1.Scenario, now time.Time) bool {
+	if len(options.Phases) > 0 {
+		matched := false
+
+		for _, phase := range options.Phases {
+			if strings.EqualFold(phase, string(scenario.Status.Phase)) {
+				matched = true
+
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	if options.OlderThan > 0 && now.Sub(scenario.GetCreationTimestamp().Time) < options.OlderThan {
+		return false
+	}
+
+	return true
+}
+
+// ListTests returns the scenarios of the selected tests.
+func ListTests(ctx context.Context, options *TestFilterOptions) (v1alpha1.ScenarioList, error) {
+	all, err := env.Default.GetFrisbeeClient().ListScenarios(ctx, options.Selector())
+	if err != nil {
+		return v1alpha1.ScenarioList{}, errors.Wrapf(err, "cannot list tests")
+	}
+
+	now := time.Now()
+
+	var selected v1alpha1.ScenarioList
+
+	for i := range all.Items {
+		if options.Match(&all.Items[i], now) {
+			selected.Items = append(selected.Items, all.Items[i])
+		}
+	}
+
+	return selected, nil
+}
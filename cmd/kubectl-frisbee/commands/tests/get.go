@@ -20,17 +20,23 @@ import (
 	"os"
 
 	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
-	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
 	"github.com/kubeshop/testkube/pkg/ui"
 	"github.com/spf13/cobra"
 )
 
 func NewGetTestsCmd() *cobra.Command {
+	var options TestFilterOptions
+
 	cmd := &cobra.Command{
-		Use:               "test <testName>",
-		Aliases:           []string{"tests", "t"},
-		Short:             "Get all available tests",
-		Long:              `Getting all available tests from given namespace - if no namespace given "frisbee" namespace is used`,
+		Use:     "test <testName>",
+		Aliases: []string{"tests", "t"},
+		Short:   "Get all available tests",
+		Long:    `Getting all available tests from given namespace - if no namespace given "frisbee" namespace is used`,
+		Example: `# Get the failed tests:
+  kubectl frisbee get tests --phase Failed
+# Get my tests that were submitted more than a day ago:
+  kubectl frisbee get tests --submitter me --older-than 24h
+`,
 		ValidArgsFunction: common.NoArgs,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
@@ -41,7 +47,7 @@ func NewGetTestsCmd() *cobra.Command {
 		},
 
 		Run: func(cmd *cobra.Command, args []string) {
-			tests, err := env.Default.GetFrisbeeClient().ListScenarios(cmd.Context(), common.ManagedNamespace)
+			tests, err := ListTests(cmd.Context(), &options)
 			ui.PrintOnError("Getting all tests ", err)
 
 			err = common.RenderList(&tests, os.Stdout)
@@ -49,5 +55,7 @@ func NewGetTestsCmd() *cobra.Command {
 		},
 	}
 
+	TestFilterFlags(cmd, &options)
+
 	return cmd
 }
@@ -0,0 +1,58 @@
+/*
+Copyright 2022-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"io"
+	"os"
+
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/wizard"
+	"github.com/pkg/errors"
+)
+
+// WithAnswers prompts for the parameters that the test file declares, and writes a copy of the test file in which
+// the scenario uses the answers. It returns the path to the copy, which must be removed by the caller.
+func WithAnswers(testFile string, in io.Reader, out io.Writer) (string, error) {
+	file, err := os.Open(testFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot open '%s'", testFile)
+	}
+
+	defer file.Close()
+
+	w, err := wizard.Load(file)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot load '%s'", testFile)
+	}
+
+	if err := w.Ask(in, out); err != nil {
+		return "", err
+	}
+
+	answered, err := os.CreateTemp("", "frisbee-*.yml")
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot create copy of '%s'", testFile)
+	}
+
+	defer answered.Close()
+
+	if err := w.Write(answered); err != nil {
+		return "", errors.Wrapf(err, "cannot write copy of '%s'", testFile)
+	}
+
+	return answered.Name(), nil
+}
@@ -0,0 +1,306 @@
+/*
+Copyright 2022-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	embed "github.com/carv-ics-forth/frisbee"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
+	frisbeeclient "github.com/carv-ics-forth/frisbee/pkg/client"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// StdinManifest is the name that reads the manifests from the standard input.
+const StdinManifest = "-"
+
+// Manifest is a document of a manifest file.
+type Manifest struct {
+	// Source is the file of the document.
+	Source string
+
+	// Line is the line where the document starts.
+	Line int
+
+	// Data is the content of the document.
+	Data []byte
+}
+
+// String returns the position of the document, as file:line.
+func (m Manifest) String() string {
+	return fmt.Sprintf("%s:%d", m.Source, m.Line)
+}
+
+// Object decodes the document into an unstructured object.
+func (m Manifest) Object() (*unstructured.Unstructured, error) {
+	var obj unstructured.Unstructured
+
+	if err := yaml.Unmarshal(m.Data, &obj.Object); err != nil {
+		return nil, errors.Wrapf(err, "%s", m)
+	}
+
+	return &obj, nil
+}
+
+// ExpandManifests replaces the directories with the .yaml and .yml files they contain, in lexical order.
+// Directories are not traversed recursively. The standard input is kept as is.
+func ExpandManifests(paths []string) ([]string, error) {
+	var files []string
+
+	for _, path := range paths {
+		if path == StdinManifest {
+			files = append(files, path)
+
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot access '%s'", path)
+		}
+
+		if !info.IsDir() {
+			files = append(files, path)
+
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot read directory '%s'", path)
+		}
+
+		var found []string
+
+		for _, entry := range entries {
+			if ext := filepath.Ext(entry.Name()); !entry.IsDir() && (ext == ".yaml" || ext == ".yml") {
+				found = append(found, filepath.Join(path, entry.Name()))
+			}
+		}
+
+		if len(found) == 0 {
+			return nil, errors.Errorf("no manifests in directory '%s'", path)
+		}
+
+		sort.Strings(found)
+
+		files = append(files, found...)
+	}
+
+	return files, nil
+}
+
+// ReadManifests splits the files into their documents, and verifies that every document is an object.
+// The errors of all the documents are reported together, along with the position of every document.
+func ReadManifests(files []string, stdin io.Reader) ([]Manifest, error) {
+	var (
+		manifests []Manifest
+		errs      []error
+	)
+
+	for _, file := range files {
+		var (
+			source = file
+			data   []byte
+			err    error
+		)
+
+		if file == StdinManifest {
+			source = "<stdin>"
+			data, err = io.ReadAll(stdin)
+		} else {
+			data, err = os.ReadFile(file)
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot read '%s'", source)
+		}
+
+		docs, err := splitManifests(source, data)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, doc := range docs {
+			object, err := checkManifest(doc.Data)
+			if err != nil {
+				errs = append(errs, errors.Wrapf(err, "%s", doc))
+
+				continue
+			}
+
+			if object {
+				manifests = append(manifests, doc)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, utilerrors.NewAggregate(errs)
+	}
+
+	if len(manifests) == 0 {
+		return nil, errors.Errorf("no manifests in %s", strings.Join(files, ", "))
+	}
+
+	return manifests, nil
+}
+
+// ValidateManifestSchemas validates the documents against the schemas of their CRDs, as installed in the cluster,
+// or as bundled with the CLI. The errors of all the documents are reported together, along with the position of
+// every document.
+func ValidateManifestSchemas(ctx context.Context, manifests []Manifest) error {
+	bundled, err := frisbeeclient.LoadCRDs(embed.CRDs, embed.CRDsDir)
+	if err != nil {
+		return errors.Wrapf(err, "cannot load bundled CRDs")
+	}
+
+	var errs []error
+
+	for _, manifest := range manifests {
+		obj, err := manifest.Object()
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		objects := []*unstructured.Unstructured{obj}
+
+		if err := env.Default.GetFrisbeeClient().ValidateSchemas(ctx, objects, bundled); err != nil {
+			errs = append(errs, errors.Wrapf(err, "%s", manifest))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// WriteManifests writes the documents into a single file, and returns the path to the file.
+// The file must be removed by the caller.
+func WriteManifests(manifests []Manifest) (string, error) {
+	merged, err := os.CreateTemp("", "frisbee-*.yml")
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot create manifest file")
+	}
+
+	defer merged.Close()
+
+	for _, manifest := range manifests {
+		if _, err := fmt.Fprintf(merged, "---\n# Source: %s\n%s", manifest, manifest.Data); err != nil {
+			return "", errors.Wrapf(err, "cannot write manifest file")
+		}
+	}
+
+	return merged.Name(), nil
+}
+
+// splitManifests splits the data into documents, using the streaming reader of the API machinery. A separator is
+// a line that starts with '---', and therefore '---' within strings or block scalars does not split a document.
+func splitManifests(file string, data []byte) ([]Manifest, error) {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+
+	var (
+		manifests []Manifest
+		offset    int
+	)
+
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return manifests, nil
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot read '%s'", file)
+		}
+
+		// the documents are returned in order, so the position of every document is found after the previous one.
+		// The reader terminates the last line of the file, even if the file does not.
+		at := bytes.Index(data[offset:], bytes.TrimSuffix(doc, []byte("\n")))
+		if at < 0 {
+			return nil, errors.Errorf("cannot locate document after line %d of '%s'", bytes.Count(data[:offset], []byte("\n"))+1, file)
+		}
+
+		start := offset + at
+		offset = start + len(doc) - 1
+
+		manifests = append(manifests, Manifest{
+			Source: file,
+			Line:   bytes.Count(data[:start], []byte("\n")) + 1 + leadingBlankLines(doc),
+			Data:   doc,
+		})
+	}
+}
+
+// leadingBlankLines counts the empty lines before the content of the document.
+func leadingBlankLines(doc []byte) int {
+	lines := 0
+
+	for _, line := range bytes.Split(doc, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) != 0 {
+			break
+		}
+
+		lines++
+	}
+
+	return lines
+}
+
+// checkManifest verifies that the document is an object with a kind and an apiVersion. It returns false for
+// documents without content (e.g, only comments), which kubectl skips as well.
+func checkManifest(doc []byte) (bool, error) {
+	var obj map[string]interface{}
+
+	if err := yaml.Unmarshal(doc, &obj); err != nil {
+		return false, err
+	}
+
+	if len(obj) == 0 {
+		return false, nil
+	}
+
+	var typeMeta metav1.TypeMeta
+
+	if err := yaml.Unmarshal(doc, &typeMeta); err != nil {
+		return false, err
+	}
+
+	switch {
+	case typeMeta.APIVersion == "":
+		return false, errors.New("missing apiVersion")
+	case typeMeta.Kind == "":
+		return false, errors.New("missing kind")
+	}
+
+	return true, nil
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"os"
+	"strings"
+
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/scaffold"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+type NewScenarioCmdOptions struct {
+	// From is the name of the scaffold.
+	From string
+
+	// Output is the file where the scenario is written. Defaults to <name>.yml.
+	Output string
+
+	// List prints the available scaffolds.
+	List bool
+
+	// Force overwrites the output file, if it exists.
+	Force bool
+}
+
+func NewScenarioCmdFlags(cmd *cobra.Command, options *NewScenarioCmdOptions) {
+	cmd.Flags().StringVar(&options.From, "from", "client-server", "the scaffold of the scenario.")
+	cmd.Flags().StringVarP(&options.Output, "output", "o", "", "the file to write the scenario into (default <name>.yml).")
+	cmd.Flags().BoolVar(&options.List, "list", false, "list the available scaffolds.")
+	cmd.Flags().BoolVar(&options.Force, "force", false, "overwrite the output file, if it exists.")
+
+	_ = cmd.RegisterFlagCompletionFunc("from", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		index, err := scaffold.LoadIndex()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		return index.Names(), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+func NewNewScenarioCmd() *cobra.Command {
+	var options NewScenarioCmdOptions
+
+	cmd := &cobra.Command{
+		Use:     "scenario <name>",
+		Aliases: []string{"scenarios", "s"},
+		Short:   "Generate a runnable scenario from a curated scaffold",
+		Example: `# List the available scaffolds:
+  kubectl frisbee new scenario --list
+# Generate a scenario with a cluster of clients and a network partition:
+  kubectl frisbee new scenario my-test --from cluster-chaos
+`,
+		ValidArgsFunction: common.NoArgs,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if options.List {
+				return nil
+			}
+
+			if len(args) != 1 {
+				ui.Failf("Pass the name of the scenario.")
+			}
+
+			if strings.ContainsAny(args[0], "/. ") {
+				ui.Failf("Invalid name for scenario: %s. Use a DNS-1123 compliant name.", args[0])
+			}
+
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			index, err := scaffold.LoadIndex()
+			ui.ExitOnError("Loading scaffolds", err)
+
+			if options.List {
+				err := common.RenderList(index, os.Stdout)
+				ui.PrintOnError("Rendering list", err)
+
+				return
+			}
+
+			name := args[0]
+
+			output := options.Output
+			if output == "" {
+				output = name + ".yml"
+			}
+
+			if _, err := os.Stat(output); err == nil && !options.Force {
+				ui.Failf("file '%s' already exists. Use --force to overwrite it.", output)
+			}
+
+			selected, err := index.Get(options.From)
+			ui.ExitOnError("Selecting scaffold", err)
+
+			scenario, err := selected.Render(scaffold.Parameters{Name: name})
+			ui.ExitOnError("Generating scenario", err)
+
+			ui.ExitOnError("Writing scenario to "+output, os.WriteFile(output, scenario, 0o600))
+			ui.Success("Scenario generated:", output)
+
+			for _, chart := range selected.Charts {
+				env.Default.Hint("The scenario depends on charts. To add them to the cache use:",
+					"kubectl frisbee charts add ", chart)
+			}
+
+			env.Default.Hint("To run the scenario use:",
+				"kubectl frisbee submit test "+name+"- "+output+" "+strings.Join(selected.Charts, " "))
+		},
+	}
+
+	NewScenarioCmdFlags(cmd, &options)
+
+	return cmd
+}
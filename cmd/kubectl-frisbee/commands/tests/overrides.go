@@ -0,0 +1,140 @@
+/*
+Copyright 2022-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/images"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// ParseImageOverrides parses the --image-override flags.
+func ParseImageOverrides(rules []string) (images.Overrides, error) {
+	overrides := make(images.Overrides, len(rules))
+
+	for _, rule := range rules {
+		repository, tag, err := images.ParseOverride(rule)
+		if err != nil {
+			return nil, err
+		}
+
+		overrides[repository] = tag
+	}
+
+	return overrides, nil
+}
+
+// WithImageOverrides writes a copy of the test file, in which the scenario overrides the given images.
+// The overrides take precedence over the imageOverrides of the scenario. It returns the path to the copy,
+// which must be removed by the caller.
+func WithImageOverrides(testFile string, overrides images.Overrides) (string, error) {
+	file, err := os.Open(testFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot open '%s'", testFile)
+	}
+
+	defer file.Close()
+
+	var (
+		out      bytes.Buffer
+		scenario bool
+	)
+
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(file))
+
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return "", errors.Wrapf(err, "cannot read '%s'", testFile)
+		}
+
+		var typeMeta metav1.TypeMeta
+
+		if err := yaml.Unmarshal(doc, &typeMeta); err != nil {
+			return "", errors.Wrapf(err, "cannot decode '%s'", testFile)
+		}
+
+		if typeMeta.GroupVersionKind() == v1alpha1.GroupVersion.WithKind("Scenario") {
+			scenario = true
+
+			if doc, err = overrideScenarioImages(doc, overrides); err != nil {
+				return "", err
+			}
+		}
+
+		out.WriteString("---\n")
+		out.Write(bytes.TrimPrefix(doc, []byte("---\n")))
+	}
+
+	if !scenario {
+		return "", errors.Errorf("no scenario in '%s'", testFile)
+	}
+
+	overridden, err := os.CreateTemp("", "frisbee-*.yml")
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot create copy of '%s'", testFile)
+	}
+
+	defer overridden.Close()
+
+	if _, err := overridden.Write(out.Bytes()); err != nil {
+		return "", errors.Wrapf(err, "cannot write copy of '%s'", testFile)
+	}
+
+	return overridden.Name(), nil
+}
+
+// overrideScenarioImages adds the overrides to the spec of the scenario. The document is decoded as a generic
+// object, so that fields unknown to this version of the client are preserved.
+func overrideScenarioImages(doc []byte, overrides images.Overrides) ([]byte, error) {
+	var obj map[string]interface{}
+
+	if err := yaml.Unmarshal(doc, &obj); err != nil {
+		return nil, errors.Wrapf(err, "cannot decode scenario")
+	}
+
+	spec, _ := obj["spec"].(map[string]interface{})
+	if spec == nil {
+		spec = make(map[string]interface{})
+		obj["spec"] = spec
+	}
+
+	merged, _ := spec["imageOverrides"].(map[string]interface{})
+	if merged == nil {
+		merged = make(map[string]interface{})
+	}
+
+	for repository, tag := range overrides {
+		merged[repository] = tag
+	}
+
+	spec["imageOverrides"] = merged
+
+	return yaml.Marshal(obj)
+}
@@ -18,11 +18,13 @@ package tests
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
@@ -31,6 +33,7 @@ import (
 	"github.com/carv-ics-forth/frisbee/pkg/grafana"
 	"github.com/carv-ics-forth/frisbee/pkg/home"
 	"github.com/carv-ics-forth/frisbee/pkg/process"
+	"github.com/carv-ics-forth/frisbee/pkg/report"
 	"github.com/gosimple/slug"
 	"github.com/hashicorp/go-multierror"
 	"github.com/kubeshop/testkube/pkg/ui"
@@ -40,7 +43,7 @@ import (
 )
 
 const (
-	User = "'':''" // Not really needed since we have no authentication in Grafana.
+	User = "'':''" // Used when Grafana is unauthenticated.
 )
 
 var DefaultDashboards = []string{"summary", "singleton"}
@@ -74,11 +77,20 @@ type ReportTestCmdOptions struct {
 	// Data downloads data from Grafana
 	Data bool
 
+	// HTML generates a self-contained HTML report with the snapshots and the data of the panels.
+	HTML bool
+
 	// Force starts the reporting regardless of the status of the Scenario (data may be inconsistent).
 	Force bool
 
 	// Wait blocks until the Scenario is in terminal phase.
 	Wait bool
+
+	// GrafanaAPIKey authenticates the requests to Grafana with an API key or service account token.
+	GrafanaAPIKey string
+
+	// GrafanaBasicAuth authenticates the requests to Grafana with 'username:password'.
+	GrafanaBasicAuth string
 }
 
 func ReportTestCmdFlags(cmd *cobra.Command, options *ReportTestCmdOptions) {
@@ -101,11 +113,54 @@ func ReportTestCmdFlags(cmd *cobra.Command, options *ReportTestCmdOptions) {
 	// Data
 	cmd.Flags().BoolVar(&options.Data, "data", false, "download grafana data as csv (experimental)")
 
+	// HTML
+	cmd.Flags().BoolVar(&options.HTML, "html", false,
+		"Generate a self-contained HTML report. Snapshots require the Grafana Image Renderer, but not NodeJS.")
+
 	// Force
 	cmd.Flags().BoolVar(&options.Force, "force", false, "Force reporting test data despite test phase.")
 
 	// Wait
 	cmd.Flags().BoolVar(&options.Wait, "wait", false, "Block waiting for scenario to be Success.")
+
+	// Grafana Credentials
+	cmd.Flags().StringVar(&options.GrafanaAPIKey, "grafana-api-key", "", "API key or service account token for Grafana.")
+
+	cmd.Flags().StringVar(&options.GrafanaBasicAuth, "grafana-basic-auth", "", "Credentials for Grafana, as 'username:password'.")
+}
+
+// GrafanaCredentials returns the credentials for Grafana. The flags take precedence over the Secret
+// referenced by the scenario. If neither is set, Grafana is assumed to be unauthenticated.
+func GrafanaCredentials(ctx context.Context, options *ReportTestCmdOptions, scenario *v1alpha1.Scenario) ([]grafana.Option, error) {
+	switch {
+	case options.GrafanaAPIKey != "":
+		return []grafana.Option{grafana.WithAPIKey(options.GrafanaAPIKey)}, nil
+
+	case options.GrafanaBasicAuth != "":
+		username, password, ok := strings.Cut(options.GrafanaBasicAuth, ":")
+		if !ok {
+			return nil, errors.Errorf("expected 'username:password' for --grafana-basic-auth")
+		}
+
+		return []grafana.Option{grafana.WithBasicAuth(username, password)}, nil
+	}
+
+	secretName, exists := scenario.GetAnnotations()[v1alpha1.AnnotationGrafanaCredentials]
+	if !exists {
+		return nil, nil
+	}
+
+	secret, err := env.Default.GetFrisbeeClient().GetSecret(ctx, scenario.GetNamespace(), secretName)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := grafana.WithSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return []grafana.Option{credentials}, nil
 }
 
 func NewReportTestCmd() *cobra.Command {
@@ -125,8 +180,12 @@ func NewReportTestCmd() *cobra.Command {
 				ui.Failf("--wait and --force cannot be used together")
 			}
 
-			if !(options.PDF || options.Data || options.AggregatedPDF) {
-				ui.Failf("at least one of [--pdf|--aggregated-pdf|--data] flags must be enabled")
+			if options.GrafanaAPIKey != "" && options.GrafanaBasicAuth != "" {
+				ui.Failf("--grafana-api-key and --grafana-basic-auth cannot be used together")
+			}
+
+			if !(options.PDF || options.Data || options.AggregatedPDF || options.HTML) {
+				ui.Failf("at least one of [--pdf|--aggregated-pdf|--data|--html] flags must be enabled")
 			}
 
 			return nil
@@ -134,8 +193,10 @@ func NewReportTestCmd() *cobra.Command {
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
 			env.Logo()
 
-			if env.Default.NodeJS() == "" || env.Default.NPM() == "" {
-				ui.Fail(errors.Errorf("report is disabled. It requires NodeJS and NPM to be installed in your system"))
+			// only the PDFs are generated by NodeJS.
+			if (options.PDF || options.AggregatedPDF) && (env.Default.NodeJS() == "" || env.Default.NPM() == "") {
+				ui.Fail(errors.Errorf("PDF report is disabled. It requires NodeJS and NPM to be installed in your system. " +
+					"Use --html instead"))
 			}
 		},
 		Run: func(cmd *cobra.Command, args []string) {
@@ -151,7 +212,7 @@ func NewReportTestCmd() *cobra.Command {
 			if options.Wait {
 				ui.Info("Waiting for scenario actions to be completed...")
 
-				err = common.WaitForCondition(cmd.Context(), testName, v1alpha1.ConditionAllJobsAreCompleted, common.TestTimeout)
+				err = WaitForCondition(cmd.Context(), testName, v1alpha1.ConditionAllJobsAreCompleted, common.TestTimeout)
 				ui.ExitOnError("abnormal termination. err:", err)
 
 				// get the new status
@@ -164,7 +225,7 @@ func NewReportTestCmd() *cobra.Command {
 				ui.Failf("test '%s' was not found", testName)
 			case scenario.Status.GrafanaEndpoint == "":
 				ui.Failf("Telemetry is not enabled for this test. ")
-			case !scenario.Status.Phase.Is(v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed):
+			case !scenario.Status.Phase.Is(v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed, v1alpha1.PhaseAborted):
 				// Abort getting data from a non-completed test, unless --force is used
 				if !options.Force {
 					ui.Failf("Unsafe operation. The test is not completed yet. Use --force")
@@ -175,9 +236,17 @@ func NewReportTestCmd() *cobra.Command {
 			fromTS, toTS := FindTimeline(scenario)
 
 			/*-- Connect to Grafana --*/
-			grafanaClient, err := grafana.New(cmd.Context(), grafana.WithHTTP(scenario.Status.GrafanaEndpoint))
+			credentials, err := GrafanaCredentials(cmd.Context(), &options, scenario)
+			ui.ExitOnError("Getting Grafana credentials", err)
+
+			grafanaClient, err := grafana.New(cmd.Context(), append(credentials, grafana.WithHTTP(scenario.Status.GrafanaEndpoint))...)
 			ui.ExitOnError("unable to connect to Grafana: err", err)
 
+			auth := grafanaClient.Authorization()
+			if auth == "" {
+				auth = User
+			}
+
 			/*---------------------------------------------------*
 			 * Fix dependencies for PDF Generations
 			 *---------------------------------------------------*/
@@ -189,6 +258,23 @@ func NewReportTestCmd() *cobra.Command {
 				common.LoadPDFExporter(options.RepositoryCache)
 			}
 
+			/*---------------------------------------------------*
+			 * Save the context of the violated assertions
+			 *---------------------------------------------------*/
+			err = SaveAssertionFailures(scenario, dstDir)
+			ui.ExitOnError("Saving assertion failures to: "+dstDir, err)
+
+			err = SaveRunMetadata(scenario, dstDir)
+			ui.ExitOnError("Saving run metadata to: "+dstDir, err)
+
+			if ref := scenario.Status.PayloadsRef; ref != "" {
+				payloads, err := env.Default.GetFrisbeeClient().GetPayloads(cmd.Context(), scenario.GetNamespace(), ref)
+				ui.ExitOnError("Getting offloaded payloads", err)
+
+				err = SavePayloads(payloads, dstDir)
+				ui.ExitOnError("Saving offloaded payloads to: "+dstDir, err)
+			}
+
 			/*---------------------------------------------------*
 			 * Perform Reporting Activities
 			 *---------------------------------------------------*/
@@ -218,7 +304,7 @@ func NewReportTestCmd() *cobra.Command {
 				if options.PDF {
 					grafanaEndpoint := grafana.BuildURL(scenario.Status.GrafanaEndpoint, dashboardUID, fromTS, toTS, "&kiosk")
 
-					err = SavePDFs(cmd.Context(), common.FastPDFExporter, grafanaClient, grafanaEndpoint, dashboardDir, dashboardUID)
+					err = SavePDFs(cmd.Context(), common.FastPDFExporter, grafanaClient, auth, grafanaEndpoint, dashboardDir, dashboardUID)
 					ui.ExitOnError("Saving PDF to: "+dashboardDir+" for "+dashboardUID, err)
 				}
 
@@ -230,10 +316,20 @@ func NewReportTestCmd() *cobra.Command {
 
 					aggregatedFile := filepath.Join(dashboardDir, "__aggregated__.pdf")
 
-					err = SavePDF(common.LongPDFExporter, uri, aggregatedFile)
+					err = SavePDF(common.LongPDFExporter, uri, auth, aggregatedFile)
 					ui.ExitOnError("Saving Aggregated PDF to: "+dashboardDir, err)
 				}
 			}
+
+			/*---------------------------------------------------*
+			 * Generate HTML report
+			 *---------------------------------------------------*/
+			if options.HTML {
+				htmlFile := filepath.Join(dstDir, "report.html")
+
+				err = SaveHTML(cmd.Context(), grafanaClient, scenario, testName, options.Dashboards, htmlFile)
+				ui.ExitOnError("Saving HTML report to: "+htmlFile, err)
+			}
 		},
 	}
 
@@ -243,7 +339,8 @@ func NewReportTestCmd() *cobra.Command {
 }
 
 // SavePDF extracts the pdf from Grafana and stores it to the destination.
-func SavePDF(exporter common.PDFExporter, dashboardURI string, dstFile string) error {
+// The auth is either an API key or 'username:password', as returned by the Grafana client.
+func SavePDF(exporter common.PDFExporter, dashboardURI string, auth string, dstFile string) error {
 	// 	Validate the URI. This is because if the URI is wrong, the
 	// nodejs will block forever.
 	if _, err := url.ParseRequestURI(dashboardURI); err != nil {
@@ -253,7 +350,7 @@ func SavePDF(exporter common.PDFExporter, dashboardURI string, dstFile string) e
 	command := []string{
 		string(exporter),
 		dashboardURI,
-		User,
+		auth,
 		dstFile,
 	}
 
@@ -267,7 +364,7 @@ func SavePDF(exporter common.PDFExporter, dashboardURI string, dstFile string) e
 	return err
 }
 
-func SavePDFs(ctx context.Context, exporter common.PDFExporter, grafanaClient *grafana.Client, dashboardURI, destDir, dashboardUID string) error {
+func SavePDFs(ctx context.Context, exporter common.PDFExporter, grafanaClient *grafana.Client, auth, dashboardURI, destDir, dashboardUID string) error {
 	/*---------------------------------------------------*
 	 * Query Grafana for Available Panels.
 	 *---------------------------------------------------*/
@@ -287,7 +384,7 @@ func SavePDFs(ctx context.Context, exporter common.PDFExporter, grafanaClient *g
 		panelURI := fmt.Sprintf("%s&viewPanel=%d", dashboardURI, panel.ID)
 		file := filepath.Join(destDir, slug.Make(panel.Title)+".pdf")
 
-		if err := SavePDF(exporter, panelURI, file); err != nil {
+		if err := SavePDF(exporter, panelURI, auth, file); err != nil {
 			merr = multierror.Append(merr,
 				errors.Wrapf(err, "cannot save PDF for panel '%d (%s)'", panel.ID, panel.Title),
 			)
@@ -301,6 +398,92 @@ func SavePDFs(ctx context.Context, exporter common.PDFExporter, grafanaClient *g
 	return nil
 }
 
+// HTML snapshot dimensions, in pixels.
+const (
+	SnapshotWidth  = 1000
+	SnapshotHeight = 500
+)
+
+// SaveHTML renders the selected dashboards into a self-contained HTML report. Panels that cannot be rendered
+// (e.g, because the Grafana Image Renderer is missing) are reported with their data only.
+func SaveHTML(ctx context.Context, grafanaClient *grafana.Client, scenario *v1alpha1.Scenario, testName string, dashboards []string, dstFile string) error {
+	fromTS, toTS := FindTimeline(scenario)
+
+	htmlReport := report.Report{
+		Test:      testName,
+		Scenario:  scenario.GetName(),
+		Phase:     scenario.Status.Phase.String(),
+		From:      time.UnixMilli(fromTS),
+		To:        time.UnixMilli(toTS),
+		Generated: time.Now(),
+	}
+
+	var renderErrors int
+
+	for _, dashboardUID := range dashboards {
+		url := grafana.NewURL(scenario.Status.GrafanaEndpoint).
+			WithDashboard(dashboardUID).
+			WithFromTS(htmlReport.From).
+			WithToTS(htmlReport.To)
+
+		panels, err := grafanaClient.ListPanels(ctx, dashboardUID)
+		if err != nil {
+			return err
+		}
+
+		data, err := grafanaClient.QueryPanels(ctx, url)
+		if err != nil {
+			return err
+		}
+
+		tables := make(map[uint][]grafana.Frame, len(data))
+		for _, panel := range data {
+			tables[panel.ID] = panel.Frames
+		}
+
+		dashboard := report.Dashboard{UID: dashboardUID}
+
+		for _, panel := range panels {
+			if panel.Type == "row" {
+				continue
+			}
+
+			entry := report.Panel{Title: panel.Title, Tables: tables[panel.ID]}
+
+			snapshot, err := grafanaClient.RenderPanel(ctx, url.WithPanel(panel.ID), SnapshotWidth, SnapshotHeight)
+			if err != nil {
+				entry.SnapshotError = err.Error()
+				renderErrors++
+			} else {
+				entry.Snapshot = snapshot
+			}
+
+			dashboard.Panels = append(dashboard.Panels, entry)
+		}
+
+		htmlReport.Dashboards = append(htmlReport.Dashboards, dashboard)
+	}
+
+	if renderErrors > 0 {
+		ui.Warn(fmt.Sprintf("%d panels could not be rendered. Is the Grafana Image Renderer enabled?", renderErrors))
+	}
+
+	file, err := os.Create(dstFile)
+	if err != nil {
+		return errors.Wrapf(err, "cannot create '%s'", dstFile)
+	}
+
+	defer file.Close()
+
+	if err := report.WriteHTML(file, &htmlReport); err != nil {
+		return err
+	}
+
+	ui.Success("Saved html", dstFile)
+
+	return nil
+}
+
 func SaveData(ctx context.Context, grafanaClient *grafana.Client, url *grafana.URL, destDir string) error {
 	/*---------------------------------------------------*
 	 * Download CSV data from each panel
@@ -312,6 +495,67 @@ func SaveData(ctx context.Context, grafanaClient *grafana.Client, url *grafana.U
 	return nil
 }
 
+// SaveAssertionFailures stores the context of the violated assertions into the destination directory, as
+// assertions.json. If no assertion is violated, no file is created.
+func SaveAssertionFailures(scenario *v1alpha1.Scenario, destDir string) error {
+	failures := make(map[string]*v1alpha1.AssertionFailure)
+
+	for _, action := range scenario.Status.Actions {
+		if action.AssertionFailure != nil {
+			failures[action.Name] = action.AssertionFailure
+		}
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(failures, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "cannot encode assertion failures")
+	}
+
+	if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
+		return errors.Wrapf(err, "cannot create '%s'", destDir)
+	}
+
+	return os.WriteFile(filepath.Join(destDir, "assertions.json"), data, 0o600)
+}
+
+// SaveRunMetadata stores the metadata of the run into the destination directory, as run.json. The file links the
+// report to the CI job, commit, and user that submitted the scenario. If the run is not yet resolved, no file is created.
+func SaveRunMetadata(scenario *v1alpha1.Scenario, destDir string) error {
+	if scenario.Status.Run == nil {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(scenario.Status.Run, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "cannot encode run metadata")
+	}
+
+	if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
+		return errors.Wrapf(err, "cannot create '%s'", destDir)
+	}
+
+	return os.WriteFile(filepath.Join(destDir, "run.json"), data, 0o600)
+}
+
+// SavePayloads stores the payloads that have been offloaded from the status of the scenario into the destination
+// directory, as payloads.json. The keys of the payloads match the placeholders found in the status.
+func SavePayloads(payloads map[string]string, destDir string) error {
+	data, err := json.MarshalIndent(payloads, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "cannot encode payloads")
+	}
+
+	if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
+		return errors.Wrapf(err, "cannot create '%s'", destDir)
+	}
+
+	return os.WriteFile(filepath.Join(destDir, "payloads.json"), data, 0o600)
+}
+
 // FindTimeline parses the scenario to find timeline that make sense (formatted into time.UnixMilli).
 // ---------------------------------------------------
 //	For the starting time we adhere to these rules:
@@ -321,7 +565,8 @@ func SaveData(ctx context.Context, grafanaClient *grafana.Client, url *grafana.U
 //	For the ending time we adhere to these rules:
 //	 1. If the scenario is successful, we return the ConditionAllJobsAreCompleted time.
 //	 2. If the scenario has failed, we return the Failure time.
-//	 3. Otherwise, we report time.Now().
+//	 3. If the scenario is aborted, we return the Abort time.
+//	 4. Otherwise, we report time.Now().
 //
 // ---------------------------------------------------
 func FindTimeline(scenario *v1alpha1.Scenario) (from int64, to int64) {
@@ -352,6 +597,13 @@ func FindTimeline(scenario *v1alpha1.Scenario) (from int64, to int64) {
 		}
 	}
 
+	if scenario.Status.Phase == v1alpha1.PhaseAborted {
+		aborted := meta.FindStatusCondition(scenario.Status.Conditions, v1alpha1.ConditionAborted.String())
+		if aborted != nil {
+			return from, aborted.LastTransitionTime.Time.Add(GraceMonitoringPeriod).UnixMilli()
+		}
+	}
+
 	// return a few second in the future to compensate for tardy events
 	return from, time.Now().Add(GraceMonitoringPeriod).UnixMilli()
 }
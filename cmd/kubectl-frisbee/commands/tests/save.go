@@ -17,10 +17,16 @@ limitations under the License.
 package tests
 
 import (
+	"context"
+	"strings"
+
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
 	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
+	"github.com/carv-ics-forth/frisbee/pkg/archive"
+	"github.com/dustin/go-humanize"
 	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -45,32 +51,65 @@ const (
 type TestSaveOptions struct {
 	Datasource string
 	Force      bool
+
+	// Output is the destination of the data. It is either a local path, or a location in an object storage.
+	Output string
+
+	S3 archive.Config
 }
 
 func PopulateSaveTestFlags(cmd *cobra.Command, options *TestSaveOptions) {
 	cmd.Flags().BoolVar(&options.Force, "force", false, "Force save test data despite test phase.")
 
 	cmd.Flags().StringVar(&options.Datasource, "datasource", TestdataSource, "The location to copy data from.")
+
+	cmd.Flags().StringVarP(&options.Output, "output", "o", "",
+		"the destination of the data, instead of the positional argument. "+
+			"With "+archive.S3Scheme+"bucket/prefix, the data are streamed as tarballs into the object storage.")
+
+	cmd.Flags().StringVar(&options.S3.Endpoint, "s3-endpoint", "",
+		"endpoint of the object storage (e.g, http://minio:9000). Defaults to $AWS_ENDPOINT_URL, or to AWS S3.")
+	cmd.Flags().StringVar(&options.S3.Region, "s3-region", "", "region of the object storage. Defaults to $AWS_REGION.")
+	cmd.Flags().StringVar(&options.S3.AccessKey, "s3-access-key", "", "access key of the object storage. Defaults to $AWS_ACCESS_KEY_ID.")
+	cmd.Flags().StringVar(&options.S3.SecretKey, "s3-secret-key", "", "secret key of the object storage. Defaults to $AWS_SECRET_ACCESS_KEY.")
 }
 
 func NewSaveTestsCmd() *cobra.Command {
 	var options TestSaveOptions
 
 	cmd := &cobra.Command{
-		Use:               "test <testName> <destination>",
-		Aliases:           []string{"tests", "t"},
-		Short:             "Store locally data generated throughout the test execution",
-		Long:              `Getting all available tests from given namespace - if no namespace given "frisbee" namespace is used`,
+		Use:     "test <testName> [<destination> | --output <destination>]",
+		Aliases: []string{"tests", "t"},
+		Short:   "Store locally data generated throughout the test execution",
+		Long:    `Getting all available tests from given namespace - if no namespace given "frisbee" namespace is used`,
+		Example: `# Copy the data into a local directory:
+  kubectl frisbee save test my-test ./data
+# Stream the data into a MinIO bucket:
+  kubectl frisbee save test my-test --output s3://artifacts/my-test --s3-endpoint http://minio:9000
+`,
 		ValidArgsFunction: SaveTestCmdCompletion,
 		Args: func(cmd *cobra.Command, args []string) error {
-			if len(args) != 2 {
+			switch {
+			case len(args) == 2 && options.Output == "":
+			case len(args) == 1 && options.Output != "":
+			default:
 				ui.Failf("Pass Test name and destination to store the data.")
 			}
 
+			if options.Output != "" && archive.IsTarget(options.Output) {
+				if _, err := archive.ParseTarget(options.Output); err != nil {
+					ui.Failf("Invalid --output: %s", err)
+				}
+			}
+
 			return nil
 		},
 		Run: func(cmd *cobra.Command, args []string) {
-			testName, destination := args[0], args[1]
+			testName, destination := args[0], options.Output
+
+			if destination == "" {
+				destination = args[1]
+			}
 
 			scenario, err := env.Default.GetFrisbeeClient().GetScenario(cmd.Context(), testName)
 			ui.ExitOnError("Getting test information", err)
@@ -80,13 +119,28 @@ func NewSaveTestsCmd() *cobra.Command {
 				ui.Failf("test '%s' was not found", testName)
 			case scenario.Spec.TestData == nil && options.Datasource == TestdataSource:
 				ui.Failf("TestData is not enabled for this test. Either enable Scenario.Spec.TestData or use --datasource.")
-			case !scenario.Status.Phase.Is(v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed):
+			case !scenario.Status.Phase.Is(v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed, v1alpha1.PhaseAborted):
 				// Abort getting data from a non-completed test, unless --force is used
 				if !options.Force {
 					ui.Failf("Unsafe operation. The test is not completed yet. Use --force")
 				}
 			}
 
+			if archive.IsTarget(destination) {
+				target, _ := archive.ParseTarget(destination)
+
+				uploader, err := archive.NewS3(archive.ConfigFromEnv(options.S3))
+				ui.ExitOnError("Connecting to object storage", err)
+
+				err = streamToObjectStorage(cmd.Context(), uploader, testName, options.Datasource, target, "testdata.tar")
+				ui.ExitOnError("Saving test data to: "+target.String(), err)
+
+				err = streamToObjectStorage(cmd.Context(), uploader, testName, PrometheusSource, target, "prometheus.tar")
+				ui.ExitOnError("Saving Prometheus data to: "+target.String(), err)
+
+				return
+			}
+
 			_, err = common.Kubectl(testName, "cp", options.Datasource, destination)
 			ui.ExitOnError("Saving test data to: "+destination, err)
 
@@ -102,3 +156,40 @@ func NewSaveTestsCmd() *cobra.Command {
 
 	return cmd
 }
+
+// streamToObjectStorage archives the directory of the Pod, given as pod:path, and streams the tarball into the
+// object storage. The data are never stored locally, and thus large artifacts do not need local disk space.
+func streamToObjectStorage(ctx context.Context, uploader *archive.S3, testName, source string, target archive.Target, name string) error {
+	pod, dir, ok := strings.Cut(source, ":")
+	if !ok {
+		return errors.Errorf("expected format is 'pod:path', got '%s'", source)
+	}
+
+	// stop the archival, if the upload fails.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, wait, err := common.KubectlStream(ctx, testName, "exec", pod, "--", "tar", "cf", "-", "-C", dir, ".")
+	if err != nil {
+		return errors.Wrapf(err, "cannot archive '%s'", source)
+	}
+
+	key := target.Key(name)
+
+	size, err := uploader.Upload(ctx, target.Bucket, key, stream)
+	if err != nil {
+		cancel()
+		_ = wait()
+
+		return errors.Wrapf(err, "cannot upload '%s'", key)
+	}
+
+	// an archival that fails midway yields a truncated tarball. The upload is complete, but it must not be trusted.
+	if err := wait(); err != nil {
+		return errors.Wrapf(err, "archive of '%s' is incomplete, and '%s' must be discarded", source, key)
+	}
+
+	ui.Success("Uploaded:", archive.S3Scheme+target.Bucket+"/"+key, humanize.Bytes(uint64(size)))
+
+	return nil
+}
@@ -19,6 +19,7 @@ package tests
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -26,7 +27,9 @@ import (
 	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
 	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
 	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -51,6 +54,19 @@ type SubmitTestCmdOptions struct {
 	Timeout                                   string
 
 	Logs []string
+
+	Keys []string
+
+	ChaosScope  string
+	ChaosValues []string
+
+	ImageOverrides []string
+
+	Interactive bool
+
+	Filenames []string
+
+	KeepOnFailure bool
 }
 
 func SubmitTestCmdFlags(cmd *cobra.Command, options *SubmitTestCmdOptions) {
@@ -58,19 +74,44 @@ func SubmitTestCmdFlags(cmd *cobra.Command, options *SubmitTestCmdOptions) {
 	// cmd.Flags().StringVar(&options.MemoryQuota, "memory", "", "set quotas for the total Memory (e.g, 100Mi) that can be used by all Pods running in the test.")
 	cmd.Flags().StringSliceVarP(&options.Logs, "logs", "l", nil, "show logs output from executor pod (all|SUT|SYS|pod)")
 
-	cmd.Flags().BoolVarP(&options.Watch, "watch", "w", false, "watch status")
+	cmd.Flags().BoolVarP(&options.Watch, "watch", "w", false,
+		"block until the test completes, while streaming its progress. "+
+			"Exits with 0 on success, 1 on failure, and 2 on assertion error.")
 
 	cmd.Flags().BoolVar(&options.ExpectSuccess, "expect-success", false, "wait for the scenario to complete successfully.")
 	cmd.Flags().BoolVar(&options.ExpectFailure, "expect-failure", false, "wait for the scenario to fail ungracefully.")
 	cmd.Flags().BoolVar(&options.ExpectError, "expect-error", false, "wait for the scenario to abort due to an assertion error.")
-	cmd.Flags().StringVarP(&options.Timeout, "timeout", "t", "1m", "wait for the scenario to complete or to fail.")
+	cmd.Flags().StringVarP(&options.Timeout, "timeout", "t", "1m", "wait for the scenario to complete or to fail. "+
+		"With --watch, there is no timeout unless it is explicitly set.")
+
+	cmd.Flags().StringSliceVar(&options.Keys, "key", nil,
+		"public key trusted to sign the scenario, in addition to the keys in "+common.TrustedKeys)
+
+	cmd.Flags().StringVar(&options.ChaosScope, "chaos-scope", string(common.ChaosScopeCluster),
+		"Chaos Mesh that injects the faults of the test. "+
+			"'cluster' verifies the cluster-wide instance, and 'namespace' installs a dedicated instance in the test namespace.")
+	cmd.Flags().StringSliceVar(&options.ChaosValues, "chaos-set", nil,
+		"values of the namespace-scoped Chaos Mesh (e.g, chaosDaemon.runtime=containerd)")
+
+	cmd.Flags().StringArrayVar(&options.ImageOverrides, "image-override", nil,
+		"replace the tag of the matching images in every Pod of the test (e.g, icsforth/ycsb=candidate). Can be repeated.")
+
+	cmd.Flags().BoolVarP(&options.Interactive, "interactive", "i", false,
+		"prompt for the vars of the scenario and the parameters of its templates, before submitting the test.")
+
+	cmd.Flags().StringArrayVarP(&options.Filenames, "filename", "f", nil,
+		"file or directory with the manifests of the test, or '-' for the standard input. Can be repeated. "+
+			"If it is set, all the positional arguments after the test name are dependencies.")
+
+	cmd.Flags().BoolVar(&options.KeepOnFailure, "keep-on-failure", false,
+		"keep the namespace of a partially submitted test for inspection. By default, it is deleted.")
 }
 
 func NewSubmitTestCmd() *cobra.Command {
 	var options SubmitTestCmdOptions
 
 	cmd := &cobra.Command{
-		Use:     "test <Name> <Scenario> <Dependencies...> ",
+		Use:     "test <Name> [<Scenario> | -f <Manifests>...] <Dependencies...> ",
 		Aliases: []string{"t"},
 		Short:   "Submit a new test",
 		Long:    `Submit starts new test based on Test Custom Resource name, returns results to console`,
@@ -78,16 +119,40 @@ func NewSubmitTestCmd() *cobra.Command {
   kubectl frisbee submit test my-wf.yaml
 # Submit and wait for completion:
   kubectl frisbee submit test --wait my-wf.yaml
-# Submit and watch until completion:
+# Submit and watch until completion (exit code 0: success, 1: failure, 2: assertion error):
   kubectl frisbee submit test --watch my-wf.yaml
 # Submit and tail logs until completion:
   kubectl frisbee submit test --log my-wf.yaml
+# Submit a scenario signed with cosign (expects my-wf.yaml.sig):
+  kubectl frisbee submit test --key cosign.pub my-wf.yaml
+# Submit with a Chaos Mesh that is confined to the test namespace:
+  kubectl frisbee submit test --chaos-scope namespace my-wf.yaml
+# Submit against a freshly built image, without editing the templates:
+  kubectl frisbee submit test --image-override icsforth/ycsb=candidate my-wf.yaml
+# Prompt for the parameters of the scenario (e.g, when following a runbook):
+  kubectl frisbee submit test --interactive my-wf.yaml
+# Submit the manifests of multiple files and directories:
+  kubectl frisbee submit test -f templates/ -f my-wf.yaml
+# Submit a generated scenario from the standard input:
+  ./generate.sh | kubectl frisbee submit test -f -
+# Keep the namespace of a test that cannot be submitted, for debugging:
+  kubectl frisbee submit test --keep-on-failure my-wf.yaml
 `,
 		ValidArgsFunction: SubmitTestCmdCompletion,
 
 		Args: func(cmd *cobra.Command, args []string) error {
-			if len(args) < 2 {
-				ui.Failf("Pass Test Name and Test File Path")
+			if len(options.Filenames) == 0 {
+				if len(args) < 2 {
+					ui.Failf("Pass Test Name and Test File Path")
+				}
+
+				testFileExt := filepath.Ext(args[1])
+				if testFileExt != ".yaml" && testFileExt != ".yml" {
+					ui.Failf("Invalid format for test file: %s \n%s", args[1],
+						"Allowed formats are: .yaml or .yml")
+				}
+			} else if len(args) < 1 {
+				ui.Failf("Pass Test Name")
 			}
 
 			if strings.Contains(args[0], "/") {
@@ -95,27 +160,122 @@ func NewSubmitTestCmd() *cobra.Command {
 					"Allowed formats are: 1) example (fixed name) and 2) example- (auto-generated)")
 			}
 
-			testFileExt := filepath.Ext(args[1])
-			if testFileExt != ".yaml" && testFileExt != ".yml" {
-				ui.Failf("Invalid format for test file: %s \n%s", args[1],
-					"Allowed formats are: .yaml or .yml")
+			for _, filename := range options.Filenames {
+				if filename == StdinManifest && options.Interactive {
+					ui.Failf("--interactive cannot prompt for answers, while the manifests are read from the standard input")
+				}
 			}
 
 			if options.ExpectSuccess && options.ExpectFailure && options.ExpectError {
 				ui.Failf("Use one of --expect-success or --expect-failure or --expect-error.")
 			}
 
+			if _, err := ParseImageOverrides(options.ImageOverrides); err != nil {
+				ui.Failf("Invalid --image-override: %s", err)
+			}
+
+			switch common.ChaosScope(options.ChaosScope) {
+			case common.ChaosScopeCluster:
+				if len(options.ChaosValues) > 0 {
+					ui.Failf("--chaos-set is applicable only to --chaos-scope=%s", common.ChaosScopeNamespace)
+				}
+			case common.ChaosScopeNamespace:
+			default:
+				ui.Failf("Invalid chaos scope: %s. Allowed scopes are: %s, %s", options.ChaosScope,
+					common.ChaosScopeCluster, common.ChaosScopeNamespace)
+			}
+
 			return nil
 		},
 
 		Run: func(cmd *cobra.Command, args []string) {
-			testName, testFile := args[0], args[1]
+			testName, testFiles, dependencies := args[0], options.Filenames, args[1:]
+
+			if len(testFiles) == 0 {
+				testFiles, dependencies = args[1:2], args[2:]
+			}
 
 			// Generate test name, if needed
 			if strings.HasSuffix(testName, "-") {
 				testName = fmt.Sprintf("%s%d", testName, rand.Intn(1000))
 			}
 
+			/*---------------------------------------------------
+			 * Verify the provenance of the scenario
+			 *---------------------------------------------------*/
+			files, err := ExpandManifests(testFiles)
+			ui.ExitOnError("Reading manifests", err)
+
+			VerifyProvenance(files, dependencies, options.Keys)
+
+			/*---------------------------------------------------
+			 * Parse the manifests of the scenario
+			 *---------------------------------------------------*/
+			manifests, err := ReadManifests(files, os.Stdin)
+
+			var invalid utilerrors.Aggregate
+			if errors.As(err, &invalid) {
+				for _, docErr := range invalid.Errors() {
+					ui.Warn("Invalid manifest:", docErr.Error())
+				}
+			}
+			ui.ExitOnError("Parsing manifests", err)
+
+			// a single file is submitted as is. Otherwise, the documents are merged into a copy.
+			source, testFile := files[0], files[0]
+
+			if len(files) > 1 || source == StdinManifest {
+				testFile, err = WriteManifests(manifests)
+				ui.ExitOnError("Merging manifests", err)
+			}
+
+			ui.Success("Manifests Parsed:", fmt.Sprintf("%d documents", len(manifests)))
+
+			/*---------------------------------------------------
+			 * Validate the manifests against the schemas
+			 *---------------------------------------------------*/
+			// Typos fail locally with the path of the field, instead of failing after the namespace is created.
+			err = ValidateManifestSchemas(cmd.Context(), manifests)
+			if errors.As(err, &invalid) {
+				for _, docErr := range invalid.Errors() {
+					ui.Warn("Invalid manifest:", docErr.Error())
+				}
+			}
+			ui.ExitOnError("Validating manifests against the schemas", err)
+			ui.Success("Schemas Validated:", testFiles...)
+
+			/*---------------------------------------------------
+			 * Prompt for the parameters of the scenario
+			 *---------------------------------------------------*/
+			// the signature covers the original file. The answers are applied to a copy.
+			if options.Interactive {
+				answered, err := WithAnswers(testFile, os.Stdin, os.Stdout)
+				if testFile != source {
+					os.Remove(testFile)
+				}
+				ui.ExitOnError("Prompting for parameters of testfile: "+testFile, err)
+				ui.Success("Parameters Answered:", testFile)
+
+				testFile = answered
+			}
+
+			/*---------------------------------------------------
+			 * Override the images of the scenario
+			 *---------------------------------------------------*/
+			// the signature covers the original file. The overrides are applied to a copy.
+			if len(options.ImageOverrides) > 0 {
+				overrides, _ := ParseImageOverrides(options.ImageOverrides)
+
+				overridden, err := WithImageOverrides(testFile, overrides)
+				if testFile != source {
+					os.Remove(testFile)
+				}
+				ui.ExitOnError("Overriding images of testfile: "+source, err)
+				ui.Success("Images Overridden:", overrides.String())
+
+				testFile = overridden
+			}
+
 			/*---------------------------------------------------
 			 * Client-side validation of the spec
 			 *---------------------------------------------------*/
@@ -123,7 +283,7 @@ func NewSubmitTestCmd() *cobra.Command {
 			// This allows us to filter-out some poorly written scenarios before interacting with the server.
 			// More complex validation is performed on the server side (using admission webhooks) during
 			// the actual submission.
-			err := common.RunTest(testName, testFile, common.ValidationClient)
+			err = common.RunTest(testName, testFile, common.ValidationClient)
 			ui.ExitOnError("Validating testfile: "+testFile, err)
 			ui.Success("Scenario Validated:", testFile)
 
@@ -138,9 +298,12 @@ func NewSubmitTestCmd() *cobra.Command {
 				ui.Failf("test '%s' already exists", testName)
 			}
 
+			// Either all the resources of the test are created, or the namespace is deleted.
+			submission := Submission{TestName: testName, KeepOnFailure: options.KeepOnFailure}
+
 			// ensure isolated namespace
-			err = common.CreateNamespace(testName, common.ManagedNamespace)
-			ui.ExitOnError("Creating managed namespace", err)
+			err = submission.CreateNamespace(common.TestNamespaceLabels()...)
+			submission.ExitOnError(cmd.Context(), "Creating managed namespace", err)
 
 			/*
 				if options.CPUQuota != "" || options.MemoryQuota != "" {
@@ -150,18 +313,39 @@ func NewSubmitTestCmd() *cobra.Command {
 			*/
 			ui.Success("Namespace Created:", testName)
 
+			/*---------------------------------------------------
+			 * Ensure the Chaos Mesh of the test
+			 *---------------------------------------------------*/
+			if common.ChaosScope(options.ChaosScope) == common.ChaosScopeNamespace {
+				err := common.InstallNamespacedChaosMesh(testName, options.ChaosValues)
+				submission.ExitOnError(cmd.Context(), "Installing namespace-scoped Chaos Mesh", err)
+
+				ui.Success("Chaos Mesh Installed:", testName)
+			} else {
+				// tests without faults do not need Chaos Mesh. Thus, a missing instance is not fatal.
+				if err := common.VerifyClusterChaosMesh(); err != nil {
+					ui.Warn("Faults cannot be injected:", err.Error())
+				} else {
+					ui.Success("Chaos Mesh Verified:", string(common.ChaosScopeCluster))
+				}
+			}
+
 			/*---------------------------------------------------
 			 * Install Helm Dependencies, if any
 			 *---------------------------------------------------*/
 			{
-				dependentCharts := args[2:]
+				dependentCharts := dependencies
 				for _, dependency := range dependentCharts {
-					_, err := common.Helm(testName,
+					// Short names refer to charts of the local cache.
+					chart, err := common.ResolveChart(dependency)
+					submission.ExitOnError(cmd.Context(), "Resolving Dependency: "+dependency, err)
+
+					_, err = common.Helm(testName,
 						"upgrade", "--install",
-						filepath.Base(dependency), dependency,
+						filepath.Base(dependency), chart,
 						"--create-namespace",
 					)
-					ui.ExitOnError("Installing Dependency: "+dependency, err)
+					submission.ExitOnError(cmd.Context(), "Installing Dependency: "+dependency, err)
 				}
 
 				ui.Success("Dependencies Installed:", dependentCharts...)
@@ -170,10 +354,18 @@ func NewSubmitTestCmd() *cobra.Command {
 			/*---------------------------------------------------
 			 * Submit Scenario
 			 *---------------------------------------------------*/
-			err = common.RunTest(testName, testFile, common.ValidationNone)
-			ui.ExitOnError("Starting test-case execution ", err)
+			err = submission.Apply(cmd.Context(), testFile)
+			if testFile != source {
+				os.Remove(testFile)
+			}
+			submission.ExitOnError(cmd.Context(), "Starting test-case execution ", err)
 			ui.Success("Scenario submitted.")
 
+			// Watch without a deadline, unless one is explicitly requested.
+			if options.Watch && !cmd.Flags().Changed("timeout") {
+				options.Timeout = ""
+			}
+
 			// Control test output
 			ControlOutput(cmd.Context(), testName, &options)
 		},
@@ -184,12 +376,51 @@ func NewSubmitTestCmd() *cobra.Command {
 	return cmd
 }
 
+// VerifyProvenance refuses scenarios that are not signed by a trusted key. The check is enabled once at least one
+// key is trusted. Dependencies that are local chart archives must be signed as well. Cached charts are protected by
+// the digest of the cache, whereas other dependencies cannot be verified.
+func VerifyProvenance(testFiles []string, dependencies []string, keyFiles []string) {
+	keys, err := common.LoadTrustedKeys(keyFiles...)
+	ui.ExitOnError("Loading trusted keys", err)
+
+	if len(keys) == 0 {
+		return
+	}
+
+	for _, testFile := range testFiles {
+		if testFile == StdinManifest {
+			ui.Failf("Manifests from the standard input cannot be verified. Sign them, and pass them as files.")
+		}
+
+		err = common.VerifyFile(testFile, keys)
+		ui.ExitOnError("Verifying signature of "+testFile, err)
+	}
+
+	for _, dependency := range dependencies {
+		info, err := os.Stat(dependency)
+
+		switch {
+		case err == nil && info.Mode().IsRegular():
+			err = common.VerifyFile(dependency, keys)
+			ui.ExitOnError("Verifying signature of "+dependency, err)
+
+		case err == nil && info.IsDir():
+			ui.Failf("Chart directory '%s' cannot be verified. Package it with 'helm package' and sign the archive.", dependency)
+
+		case strings.Contains(dependency, "/"):
+			ui.Warn("Remote chart cannot be verified:", dependency)
+		}
+	}
+
+	ui.Success("Signatures Verified:", testFiles...)
+}
+
 func ControlOutput(ctx context.Context, testName string, options *SubmitTestCmdOptions) {
 	switch {
 	case options.ExpectSuccess:
 		ui.Info("Expecting the test to complete successfully within ", options.Timeout)
 
-		err := common.WaitForCondition(ctx, testName, v1alpha1.ConditionAllJobsAreCompleted, options.Timeout)
+		err := WaitForCondition(ctx, testName, v1alpha1.ConditionAllJobsAreCompleted, options.Timeout)
 
 		env.Default.Hint("To inspect the execution:", "kubectl frisbee inspect test ", testName)
 		ui.ExitOnError("waiting for test to complete successfully", err)
@@ -197,7 +428,7 @@ func ControlOutput(ctx context.Context, testName string, options *SubmitTestCmdO
 	case options.ExpectFailure:
 		ui.Info("Expecting the test to fail within ", options.Timeout)
 
-		err := common.WaitForCondition(ctx, testName, v1alpha1.ConditionJobUnexpectedTermination, options.Timeout)
+		err := WaitForCondition(ctx, testName, v1alpha1.ConditionJobUnexpectedTermination, options.Timeout)
 
 		env.Default.Hint("To inspect the execution:", "kubectl frisbee inspect test ", testName)
 		ui.ExitOnError("waiting for test to fail", err)
@@ -205,7 +436,7 @@ func ControlOutput(ctx context.Context, testName string, options *SubmitTestCmdO
 	case options.ExpectError:
 		ui.Info("Expecting the test to raise an assertion error within ", options.Timeout)
 
-		err := common.WaitForCondition(ctx, testName, v1alpha1.ConditionAssertionError, options.Timeout)
+		err := WaitForCondition(ctx, testName, v1alpha1.ConditionAssertionError, options.Timeout)
 
 		env.Default.Hint("To inspect the execution:", "kubectl frisbee inspect test ", testName)
 		ui.ExitOnError("waiting for test to raise an assertion error", err)
@@ -213,8 +444,7 @@ func ControlOutput(ctx context.Context, testName string, options *SubmitTestCmdO
 	case options.Watch:
 		ui.Info("Watching for changes in the test status.")
 
-		err := common.GetFrisbeeResources(testName, true)
-		ui.ExitOnError("Watching for changes in the test status error", err)
+		watchAndExit(ctx, testName, options.Timeout)
 
 	case options.Logs != nil:
 		ui.Warn("Streaming Logs from:", options.Logs...)
@@ -0,0 +1,329 @@
+/*
+Copyright 2022-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
+	"github.com/carv-ics-forth/frisbee/pkg/images"
+	"github.com/carv-ics-forth/frisbee/pkg/suite"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+type SubmitSuiteCmdOptions struct {
+	Concurrency int
+	FailFast    bool
+	Timeout     time.Duration
+	JUnit       string
+
+	Keys []string
+
+	ImageOverrides []string
+
+	KeepOnFailure bool
+}
+
+func SubmitSuiteCmdFlags(cmd *cobra.Command, options *SubmitSuiteCmdOptions) {
+	cmd.Flags().IntVarP(&options.Concurrency, "concurrency", "c", 2, "maximum number of tests that run in parallel.")
+
+	cmd.Flags().BoolVar(&options.FailFast, "fail-fast", false,
+		"stop the suite at the first test that does not pass. Running tests are aborted, and pending tests are skipped.")
+
+	cmd.Flags().DurationVarP(&options.Timeout, "timeout", "t", 0, "deadline for each test of the suite. Zero means no deadline.")
+
+	cmd.Flags().StringVar(&options.JUnit, "junit", "", "write the results of the suite to the given file, in the JUnit XML format.")
+
+	cmd.Flags().StringSliceVar(&options.Keys, "key", nil,
+		"public key trusted to sign the scenarios, in addition to the keys in "+common.TrustedKeys)
+
+	cmd.Flags().StringArrayVar(&options.ImageOverrides, "image-override", nil,
+		"replace the tag of the matching images in every Pod of the suite (e.g, icsforth/ycsb=candidate). Can be repeated.")
+
+	cmd.Flags().BoolVar(&options.KeepOnFailure, "keep-on-failure", false,
+		"keep the namespaces of partially submitted tests for inspection. By default, they are deleted.")
+}
+
+func NewSubmitSuiteCmd() *cobra.Command {
+	var options SubmitSuiteCmdOptions
+
+	cmd := &cobra.Command{
+		Use:     "suite <Name> <Directory> <Dependencies...>",
+		Aliases: []string{"s"},
+		Short:   "Submit a directory of scenarios as a test suite",
+		Long: `Submit every scenario (.yaml, .yml) of the directory as a separate test, named <Name>-<file name>.
+The tests run in parallel, up to the concurrency limit, and the dependencies are installed in the namespace of each test.
+Exits with 0 if every test passes, and 1 otherwise.`,
+		Example: `# Submit a suite with up to 4 tests in parallel:
+  kubectl frisbee submit suite nightly ./my-suite -c 4
+# Submit a suite, stop at the first failure, and export the results for CI:
+  kubectl frisbee submit suite nightly- ./my-suite --fail-fast --junit report.xml
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				ui.Failf("Pass Suite Name and Suite Directory")
+			}
+
+			if strings.Contains(args[0], "/") {
+				ui.Failf("Invalid format for suite name: %s. \n%s", args[0],
+					"Allowed formats are: 1) example (fixed name) and 2) example- (auto-generated)")
+			}
+
+			if options.Concurrency < 1 {
+				ui.Failf("--concurrency must be at least 1")
+			}
+
+			if _, err := ParseImageOverrides(options.ImageOverrides); err != nil {
+				ui.Failf("Invalid --image-override: %s", err)
+			}
+
+			return nil
+		},
+
+		Run: func(cmd *cobra.Command, args []string) {
+			suiteName, suiteDir, dependencies := args[0], args[1], args[2:]
+
+			overrides, _ := ParseImageOverrides(options.ImageOverrides)
+
+			// Generate suite name, if needed
+			if strings.HasSuffix(suiteName, "-") {
+				suiteName = fmt.Sprintf("%s%d", suiteName, rand.Intn(1000))
+			}
+
+			cases, err := suite.Discover(suiteDir, suiteName+"-")
+			ui.ExitOnError("Discovering scenarios of suite: "+suiteDir, err)
+
+			/*---------------------------------------------------
+			 * Verify and validate every scenario before running any
+			 *---------------------------------------------------*/
+			for _, c := range cases {
+				VerifyProvenance([]string{c.File}, dependencies, options.Keys)
+
+				manifests, err := ReadManifests([]string{c.File}, nil)
+				ui.ExitOnError("Parsing testfile: "+c.File, err)
+
+				err = ValidateManifestSchemas(cmd.Context(), manifests)
+				ui.ExitOnError("Validating testfile against the schemas: "+c.File, err)
+
+				err = common.RunTest(c.Name, c.File, common.ValidationClient)
+				ui.ExitOnError("Validating testfile: "+c.File, err)
+			}
+
+			ui.Success("Scenarios Validated:", suiteDir)
+
+			// tests without faults do not need Chaos Mesh. Thus, a missing instance is not fatal.
+			if err := common.VerifyClusterChaosMesh(); err != nil {
+				ui.Warn("Faults cannot be injected:", err.Error())
+			} else {
+				ui.Success("Chaos Mesh Verified:", string(common.ChaosScopeCluster))
+			}
+
+			/*---------------------------------------------------
+			 * Run the suite
+			 *---------------------------------------------------*/
+			ui.Info(fmt.Sprintf("Running suite '%s' with %d tests (concurrency: %d)", suiteName, len(cases), options.Concurrency))
+
+			report := suite.Run(cmd.Context(), suiteName, cases,
+				suite.Options{Concurrency: options.Concurrency, FailFast: options.FailFast},
+				func(ctx context.Context, c suite.Case) (suite.Status, error) {
+					return runSuiteCase(ctx, c, dependencies, overrides, options.Timeout, options.KeepOnFailure)
+				},
+			)
+
+			/*---------------------------------------------------
+			 * Report the results
+			 *---------------------------------------------------*/
+			PrintSuiteReport(report)
+
+			if options.JUnit != "" {
+				err := SaveJUnitReport(report, options.JUnit)
+				ui.ExitOnError("Saving JUnit report", err)
+
+				ui.Success("JUnit report saved:", options.JUnit)
+			}
+
+			env.Default.Hint("To inspect the execution of a test:", "kubectl frisbee inspect test <testName>")
+
+			if !report.Passed() {
+				os.Exit(ExitFailure)
+			}
+		},
+	}
+
+	SubmitSuiteCmdFlags(cmd, &options)
+
+	return cmd
+}
+
+// runSuiteCase submits the scenario of a test case into a dedicated namespace, and watches it to completion.
+// If the suite is stopped in the meantime, the test is aborted. Tests that cannot be submitted are rolled back.
+func runSuiteCase(ctx context.Context, c suite.Case, dependencies []string, overrides images.Overrides, timeout time.Duration, keepOnFailure bool) (suite.Status, error) {
+	/*---------------------------------------------------
+	 * Ensure environment isolation
+	 *---------------------------------------------------*/
+	scenario, err := env.Default.GetFrisbeeClient().GetScenario(ctx, c.Name)
+	if err != nil {
+		return suite.StatusError, errors.Wrapf(err, "cannot look for conflicts")
+	}
+
+	if scenario != nil {
+		return suite.StatusError, errors.Errorf("test '%s' already exists", c.Name)
+	}
+
+	submission := Submission{TestName: c.Name, KeepOnFailure: keepOnFailure}
+
+	if err := submission.CreateNamespace(common.TestNamespaceLabels()...); err != nil {
+		submission.Rollback()
+
+		return suite.StatusError, err
+	}
+
+	for _, dependency := range dependencies {
+		// Short names refer to charts of the local cache.
+		chart, err := common.ResolveChart(dependency)
+		if err != nil {
+			submission.Rollback()
+
+			return suite.StatusError, errors.Wrapf(err, "cannot resolve dependency '%s'", dependency)
+		}
+
+		if _, err := common.Helm(c.Name, "upgrade", "--install", filepath.Base(dependency), chart, "--create-namespace"); err != nil {
+			submission.Rollback()
+
+			return suite.StatusError, errors.Wrapf(err, "cannot install dependency '%s'", dependency)
+		}
+	}
+
+	/*---------------------------------------------------
+	 * Submit and watch the scenario
+	 *---------------------------------------------------*/
+	testFile := c.File
+
+	if len(overrides) > 0 {
+		overridden, err := WithImageOverrides(c.File, overrides)
+		if err != nil {
+			submission.Rollback()
+
+			return suite.StatusError, err
+		}
+
+		defer os.Remove(overridden)
+
+		testFile = overridden
+	}
+
+	if err := submission.Apply(ctx, testFile); err != nil {
+		submission.Rollback()
+
+		return suite.StatusError, errors.Wrapf(err, "cannot submit scenario")
+	}
+
+	ui.Info("Test submitted:", c.Name)
+
+	code, err := watchTest(ctx, c.Name, timeout, nil)
+
+	switch {
+	case err != nil && ctx.Err() != nil:
+		// the suite has been stopped, either by fail-fast or by the user.
+		abortSuiteCase(c.Name)
+
+		return suite.StatusSkipped, errors.New("the suite was stopped")
+
+	case err != nil:
+		abortSuiteCase(c.Name)
+
+		ui.Warn("Test failed:", c.Name)
+
+		return suite.StatusFailed, err
+
+	case code == ExitAssertionError:
+		ui.Warn("Test stopped by an assertion error:", c.Name)
+
+		return suite.StatusFailed, errors.New("the test was stopped by an assertion error")
+
+	case code != ExitSuccess:
+		ui.Warn("Test failed:", c.Name)
+
+		return suite.StatusFailed, errors.New("the test has failed")
+
+	default:
+		ui.Success("Test completed successfully:", c.Name)
+
+		return suite.StatusPassed, nil
+	}
+}
+
+// abortSuiteCase aborts a test that is still running. Tests that cannot be aborted are left for inspection.
+func abortSuiteCase(testName string) {
+	scenario, err := env.Default.GetFrisbeeClient().GetScenario(context.Background(), testName)
+	if err != nil || scenario == nil {
+		return
+	}
+
+	if err := common.AbortTest(testName, scenario.GetName()); err != nil {
+		ui.Warn("Cannot abort test:", testName, err.Error())
+	}
+}
+
+// PrintSuiteReport prints the outcome of every test, and the totals of the suite.
+func PrintSuiteReport(report *suite.Report) {
+	ui.NL()
+	ui.Info("Suite:", report.Name)
+
+	for _, result := range report.Results {
+		line := fmt.Sprintf("  %s: %s (%s)", result.Name, result.Status, result.Duration.Round(time.Second))
+
+		switch {
+		case result.Status == suite.StatusPassed:
+			ui.Success(line)
+		case result.Message != "":
+			ui.Warn(line, result.Message)
+		default:
+			ui.Warn(line)
+		}
+	}
+
+	ui.Info(fmt.Sprintf("Total: %d, Passed: %d, Failed: %d, Errors: %d, Skipped: %d (%s)",
+		len(report.Results),
+		report.Count(suite.StatusPassed),
+		report.Count(suite.StatusFailed),
+		report.Count(suite.StatusError),
+		report.Count(suite.StatusSkipped),
+		report.Duration.Round(time.Second),
+	))
+}
+
+// SaveJUnitReport stores the report of the suite in the JUnit XML format.
+func SaveJUnitReport(report *suite.Report, dstFile string) error {
+	file, err := os.Create(dstFile)
+	if err != nil {
+		return errors.Wrapf(err, "cannot create '%s'", dstFile)
+	}
+
+	defer file.Close()
+
+	return report.WriteJUnit(file)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"os"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
+	"github.com/carv-ics-forth/frisbee/pkg/timeline"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+func SaveTimelineCmdCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch {
+	case len(args) == 0:
+		return common.CompleteScenarios(cmd, args, toComplete)
+
+	case len(args) == 1:
+		return nil, cobra.ShellCompDirectiveDefault
+
+	default:
+		return common.CompleteFlags(cmd, args, toComplete)
+	}
+}
+
+func NewSaveTimelineCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "timeline <testName> <destination>",
+		Short:             "Export the timeline of the test as a Chrome trace (viewable in Perfetto)",
+		Long:              `Converts the actions and the events of the test into the Chrome Trace Event format. Open the file in https://ui.perfetto.dev`,
+		ValidArgsFunction: SaveTimelineCmdCompletion,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				ui.Failf("Pass Test name and destination to store the timeline.")
+			}
+
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			testName, destination := args[0], args[1]
+
+			scenario, err := env.Default.GetFrisbeeClient().GetScenario(cmd.Context(), testName)
+			ui.ExitOnError("Getting test information", err)
+
+			if scenario == nil {
+				ui.Failf("test '%s' was not found", testName)
+			}
+
+			events, err := env.Default.GetFrisbeeClient().ListEvents(cmd.Context(), testName)
+			ui.ExitOnError("Getting test events", err)
+
+			trace := timeline.FromScenario(scenario, events.Items, time.Now())
+
+			file, err := os.Create(destination)
+			ui.ExitOnError("Creating "+destination, err)
+
+			defer file.Close()
+
+			ui.ExitOnError("Saving timeline to: "+destination, trace.Write(file))
+
+			env.Default.Hint("To view the timeline, open it in", "https://ui.perfetto.dev")
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2022-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"os"
+
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/pkg/errors"
+)
+
+// Submission creates the resources of a test as a whole. If any step fails after the namespace of the test is
+// created, the namespace is deleted, so that a half-initialized test is not left behind. With KeepOnFailure,
+// the namespace is kept for inspection instead.
+type Submission struct {
+	TestName string
+
+	KeepOnFailure bool
+
+	// namespaced indicates that the namespace is created by this submission, and therefore it can be deleted.
+	namespaced bool
+
+	// apply creates the resources of a file in the namespace of the test. If nil, the file is applied with kubectl.
+	apply func(testName string, testFile string) error
+}
+
+// CreateNamespace creates the isolated namespace of the test. Once the namespace exists, it is owned by the
+// submission, even if it cannot be labeled.
+func (s *Submission) CreateNamespace(labels ...string) error {
+	if err := common.CreateNamespace(s.TestName); err != nil {
+		return err
+	}
+
+	s.namespaced = true
+
+	return common.LabelNamespace(s.TestName, labels...)
+}
+
+// Apply creates the documents of the file one by one, in order, and stops at the first document that fails,
+// or once the submission is interrupted. The error points to the failed resource and to its position in the file.
+func (s *Submission) Apply(ctx context.Context, testFile string) error {
+	manifests, err := ReadManifests([]string{testFile}, nil)
+	if err != nil {
+		return errors.Wrapf(err, "cannot read manifests")
+	}
+
+	apply := s.apply
+	if apply == nil {
+		apply = func(testName string, testFile string) error {
+			return common.RunTest(testName, testFile, common.ValidationNone)
+		}
+	}
+
+	for i, manifest := range manifests {
+		obj, err := manifest.Object()
+		if err != nil {
+			return err
+		}
+
+		resource := obj.GetKind() + "/" + obj.GetName()
+
+		if ctx.Err() != nil {
+			return errors.Wrapf(ctx.Err(), "interrupted before '%s'. %d of %d resources were created",
+				resource, i, len(manifests))
+		}
+
+		docFile, err := WriteManifests(manifests[i : i+1])
+		if err != nil {
+			return errors.Wrapf(err, "cannot prepare '%s'", resource)
+		}
+
+		err = apply(s.TestName, docFile)
+		os.Remove(docFile)
+
+		if err != nil {
+			return errors.Wrapf(err, "cannot create '%s' (%s). %d of %d resources were created",
+				resource, manifest, i, len(manifests))
+		}
+
+		ui.Debug("Resource created:", resource)
+	}
+
+	return nil
+}
+
+// Rollback deletes the namespace of the test, unless it must be kept for inspection.
+// Nothing is deleted if the namespace was not created by this submission.
+func (s *Submission) Rollback() {
+	if !s.namespaced {
+		return
+	}
+
+	if s.KeepOnFailure {
+		ui.Warn("Partially submitted test is kept for inspection:", s.TestName)
+		env.Default.Hint("To delete the test:", "kubectl frisbee delete test "+s.TestName)
+
+		return
+	}
+
+	ui.Warn("Rolling back partially submitted test:", s.TestName)
+
+	if err := common.DeleteNamespaces("", s.TestName); err != nil {
+		ui.Warn("Cannot roll back test:", s.TestName, err.Error())
+		env.Default.Hint("To delete a stalled test:", "kubectl frisbee delete test --force "+s.TestName)
+
+		return
+	}
+
+	s.namespaced = false
+}
+
+// ExitOnError rolls back the submission and exits, if there is an error or if the submission is interrupted
+// (e.g, on Ctrl-C).
+func (s *Submission) ExitOnError(ctx context.Context, title string, err error) {
+	if err == nil && ctx.Err() != nil {
+		err = errors.Wrapf(ctx.Err(), "interrupted")
+	}
+
+	if err != nil {
+		s.Rollback()
+	}
+
+	ui.ExitOnError(title, err)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+const testManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+apiVersion: frisbee.dev/v1alpha1
+kind: Template
+metadata:
+  name: second
+---
+apiVersion: frisbee.dev/v1alpha1
+kind: Scenario
+metadata:
+  name: third
+`
+
+func TestSubmission_Apply(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "test.yml")
+
+	if err := os.WriteFile(testFile, []byte(testManifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		interrupted bool
+		failOn      string
+		wantApplied []string
+		wantErr     []string
+	}{
+		{
+			name:        "all resources",
+			wantApplied: []string{"first", "second", "third"},
+		},
+		{
+			name:        "failed document",
+			failOn:      "second",
+			wantApplied: []string{"first", "second"},
+			wantErr:     []string{"cannot create 'Template/second'", testFile + ":6", "1 of 3 resources were created"},
+		},
+		{
+			name:        "interrupted",
+			interrupted: true,
+			wantErr:     []string{"interrupted before 'ConfigMap/first'", "0 of 3 resources were created"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var applied []string
+
+			submission := Submission{
+				TestName: "test",
+				apply: func(testName string, docFile string) error {
+					manifests, err := ReadManifests([]string{docFile}, nil)
+					if err != nil || len(manifests) != 1 {
+						t.Fatalf("expected a single document in '%s'. err: %v", docFile, err)
+					}
+
+					obj, err := manifests[0].Object()
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					applied = append(applied, obj.GetName())
+
+					if obj.GetName() == tt.failOn {
+						return errors.New("admission webhook denied the request")
+					}
+
+					return nil
+				},
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if tt.interrupted {
+				cancel()
+			}
+
+			err := submission.Apply(ctx, testFile)
+
+			if !reflect.DeepEqual(applied, tt.wantApplied) {
+				t.Errorf("applied = %v, want %v", applied, tt.wantApplied)
+			}
+
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("Apply() error = %v", err)
+				}
+
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("Apply() error = nil, want %v", tt.wantErr)
+			}
+
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Apply() error = %v, want %s", err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// Exit codes of 'submit test --watch', so that a single command suffices for CI pipelines.
+const (
+	// ExitSuccess means that the test has completed successfully.
+	ExitSuccess = 0
+
+	// ExitFailure means that the test has failed, has been aborted, or could not be watched.
+	ExitFailure = 1
+
+	// ExitAssertionError means that the test has been stopped by a violated assertion.
+	ExitAssertionError = 2
+)
+
+// ExitCode maps the status of a scenario to the exit code of the watch. It returns false if the scenario
+// is still running, or if it has triggered another scenario of the chain.
+func ExitCode(scenario *v1alpha1.Scenario) (code int, done bool) {
+	if scenario.Status.NextScenario != "" {
+		return ExitSuccess, false
+	}
+
+	switch scenario.Status.Phase {
+	case v1alpha1.PhaseSuccess:
+		return ExitSuccess, true
+
+	case v1alpha1.PhaseFailed:
+		if meta.IsStatusConditionTrue(scenario.Status.Conditions, v1alpha1.ConditionAssertionError.String()) {
+			return ExitAssertionError, true
+		}
+
+		return ExitFailure, true
+
+	case v1alpha1.PhaseAborted:
+		return ExitFailure, true
+
+	default:
+		return ExitSuccess, false
+	}
+}
+
+// progress reports the changes in the phase of the scenario and of its actions.
+type progress struct {
+	scenario string
+	phase    v1alpha1.Phase
+	actions  map[string]v1alpha1.Phase
+}
+
+func (p *progress) report(scenario *v1alpha1.Scenario) {
+	if scenario.GetName() != p.scenario {
+		p.scenario = scenario.GetName()
+		p.phase = v1alpha1.PhaseUninitialized
+		p.actions = make(map[string]v1alpha1.Phase)
+
+		ui.Info("Scenario:", scenario.GetName())
+	}
+
+	for _, action := range scenario.Status.Actions {
+		if last, seen := p.actions[action.Name]; seen && last == action.Phase {
+			continue
+		}
+
+		p.actions[action.Name] = action.Phase
+
+		if action.Reason != "" {
+			ui.Info(fmt.Sprintf("  %s (%s):", action.Name, action.ActionType), action.Phase.String(), action.Reason)
+		} else {
+			ui.Info(fmt.Sprintf("  %s (%s):", action.Name, action.ActionType), action.Phase.String())
+		}
+	}
+
+	if scenario.Status.Phase != p.phase {
+		p.phase = scenario.Status.Phase
+
+		ui.Info(fmt.Sprintf("Phase: %s (%d%%)", scenario.Status.Phase, scenario.Status.PercentComplete),
+			scenario.Status.Reason)
+	}
+}
+
+// WatchTest blocks until the test reaches a terminal phase, while streaming the progress of the scenario and of its
+// actions. If the timeout is non-zero, the watch fails once the timeout expires. It returns the exit code that
+// corresponds to the outcome of the test.
+func WatchTest(ctx context.Context, testName string, timeout time.Duration) (int, error) {
+	var p progress
+
+	return watchTest(ctx, testName, timeout, p.report)
+}
+
+// watchTest follows the status updates of the test until it reaches a terminal phase. The report, if any, is invoked
+// on every update.
+func watchTest(ctx context.Context, testName string, timeout time.Duration, report func(*v1alpha1.Scenario)) (int, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	updates, err := env.Default.GetFrisbeeClient().WatchTest(ctx, testName)
+	if err != nil {
+		return ExitFailure, errors.Wrapf(err, "cannot watch test '%s'", testName)
+	}
+
+	for scenario := range updates {
+		if report != nil {
+			report(scenario)
+		}
+
+		if code, done := ExitCode(scenario); done {
+			return code, nil
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ExitFailure, errors.Wrapf(ctx.Err(), "test '%s' did not complete", testName)
+	}
+
+	return ExitFailure, errors.Errorf("test '%s' was deleted", testName)
+}
+
+// WaitForCondition blocks until the condition of the test is true. It fails if the test is completed without
+// the condition, or if the timeout expires. An empty timeout waits without a deadline.
+func WaitForCondition(ctx context.Context, testName string, condition v1alpha1.ConditionType, timeout string) error {
+	if timeout != "" {
+		duration, err := time.ParseDuration(timeout)
+		if err != nil {
+			return errors.Wrapf(err, "invalid timeout")
+		}
+
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, duration)
+		defer cancel()
+	}
+
+	updates, err := env.Default.GetFrisbeeClient().WatchTest(ctx, testName)
+	if err != nil {
+		return errors.Wrapf(err, "cannot watch test '%s'", testName)
+	}
+
+	for scenario := range updates {
+		if meta.IsStatusConditionTrue(scenario.Status.Conditions, condition.String()) {
+			ui.Info("Condition successful")
+
+			return nil
+		}
+
+		if _, done := ExitCode(scenario); done {
+			return errors.Errorf("test '%s' is completed (%s) without condition '%s'",
+				testName, scenario.Status.Phase, condition)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return errors.Wrapf(ctx.Err(), "condition '%s' of test '%s' is not met", condition, testName)
+	}
+
+	return errors.Errorf("test '%s' was deleted", testName)
+}
+
+// watchAndExit watches the test and terminates the process with the exit code of the test.
+func watchAndExit(ctx context.Context, testName string, timeout string) {
+	var duration time.Duration
+
+	if timeout != "" {
+		parsed, err := time.ParseDuration(timeout)
+		ui.ExitOnError("Parsing timeout", err)
+
+		duration = parsed
+	}
+
+	code, err := WatchTest(ctx, testName, duration)
+
+	env.Default.Hint("To inspect the execution:", "kubectl frisbee inspect test ", testName)
+	ui.ExitOnError("Watching test", err)
+
+	switch code {
+	case ExitSuccess:
+		ui.Success("Test completed successfully:", testName)
+	case ExitAssertionError:
+		ui.Warn("Test stopped by an assertion error:", testName)
+	default:
+		ui.Warn("Test failed:", testName)
+	}
+
+	os.Exit(code)
+}
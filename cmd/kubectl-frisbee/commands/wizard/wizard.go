@@ -0,0 +1,375 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package wizard prompts for the parameters that a test file declares, and fills the answers into the scenario.
+//
+// The declared parameters are:
+//   - the vars of the scenario, and the vars of the templates in the file. The answers become vars of the scenario.
+//   - the parameters of the templates in the file, for every Service or Cluster action that uses them.
+//     The answers become inputs of the action.
+package wizard
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/carv-ics-forth/frisbee/pkg/structure"
+	"github.com/pkg/errors"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	kindScenario = "Scenario"
+	kindTemplate = "Template"
+)
+
+// Parameter is a value that the test file declares, along with its default.
+type Parameter struct {
+	// Name identifies the parameter, e.g, vars.target or server.duration (action.parameter).
+	Name string
+
+	// Source explains where the parameter is declared.
+	Source string
+
+	// Default is the value that is used if the answer is empty.
+	Default interface{}
+
+	// set writes the answer into the scenario.
+	set func(value interface{})
+}
+
+// Wizard holds the documents of a test file.
+type Wizard struct {
+	docs []map[string]interface{}
+
+	scenario  map[string]interface{}
+	templates map[string]map[string]interface{}
+}
+
+// Load reads the documents of a test file. The file must contain a scenario.
+func Load(r io.Reader) (*Wizard, error) {
+	w := &Wizard{templates: make(map[string]map[string]interface{})}
+
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+
+	for {
+		raw, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot read document")
+		}
+
+		var doc map[string]interface{}
+
+		if err := yaml.Unmarshal(raw, &doc); err != nil {
+			return nil, errors.Wrapf(err, "cannot decode document")
+		}
+
+		// skip empty documents (e.g, comments).
+		if doc == nil {
+			continue
+		}
+
+		switch doc["kind"] {
+		case kindScenario:
+			if w.scenario != nil {
+				return nil, errors.Errorf("multiple scenarios are not supported")
+			}
+
+			w.scenario = doc
+		case kindTemplate:
+			w.templates[str(field(doc, "metadata", "name"))] = doc
+		}
+
+		w.docs = append(w.docs, doc)
+	}
+
+	if w.scenario == nil {
+		return nil, errors.Errorf("no scenario found")
+	}
+
+	return w, nil
+}
+
+// Parameters returns the declared parameters of the test, in the order they are prompted.
+func (w *Wizard) Parameters() []Parameter {
+	var params []Parameter
+
+	/*---------------------------------------------------*
+	 * Vars of the scenario, and of the templates
+	 *---------------------------------------------------*/
+	scenarioVars, _ := field(w.scenario, "spec", "vars").(map[string]interface{})
+
+	setVar := func(name string) func(interface{}) {
+		return func(value interface{}) {
+			spec := child(w.scenario, "spec")
+			child(spec, "vars")[name] = fmt.Sprint(value)
+		}
+	}
+
+	for _, name := range structure.SortedMapKeys(scenarioVars) {
+		params = append(params, Parameter{
+			Name:    "vars." + name,
+			Source:  "scenario var",
+			Default: str(scenarioVars[name]),
+			set:     setVar(name),
+		})
+	}
+
+	declared := make(map[string]bool, len(scenarioVars))
+	for name := range scenarioVars {
+		declared[name] = true
+	}
+
+	for _, templateName := range structure.SortedMapKeys(w.templates) {
+		templateVars, _ := field(w.templates[templateName], "spec", "inputs", "vars").(map[string]interface{})
+
+		for _, name := range structure.SortedMapKeys(templateVars) {
+			if declared[name] {
+				continue
+			}
+
+			declared[name] = true
+
+			params = append(params, Parameter{
+				Name:    "vars." + name,
+				Source:  fmt.Sprintf("var of template '%s'", templateName),
+				Default: str(templateVars[name]),
+				set:     setVar(name),
+			})
+		}
+	}
+
+	/*---------------------------------------------------*
+	 * Parameters of the templates, per action
+	 *---------------------------------------------------*/
+	actions, _ := field(w.scenario, "spec", "actions").([]interface{})
+
+	for _, a := range actions {
+		action, _ := a.(map[string]interface{})
+
+		var spec map[string]interface{}
+
+		switch action["action"] {
+		case "Service":
+			spec, _ = action["service"].(map[string]interface{})
+		case "Cluster":
+			spec, _ = action["cluster"].(map[string]interface{})
+		}
+
+		if spec == nil {
+			continue
+		}
+
+		templateName := str(spec["templateRef"])
+
+		template, local := w.templates[templateName]
+		if !local {
+			continue
+		}
+
+		// with multiple input sets, every instance has its own parameters. There is no single answer for them.
+		inputs, _ := spec["inputs"].([]interface{})
+		if len(inputs) > 1 {
+			continue
+		}
+
+		var current map[string]interface{}
+		if len(inputs) == 1 {
+			current, _ = inputs[0].(map[string]interface{})
+		}
+
+		templateParams, _ := field(template, "spec", "inputs", "parameters").(map[string]interface{})
+
+		for _, name := range structure.SortedMapKeys(templateParams) {
+			def := templateParams[name]
+			if value, exists := current[name]; exists {
+				def = value
+			}
+
+			spec, name := spec, name
+
+			params = append(params, Parameter{
+				Name:    fmt.Sprintf("%s.%s", str(action["name"]), name),
+				Source:  fmt.Sprintf("parameter of template '%s'", templateName),
+				Default: def,
+				set: func(value interface{}) {
+					inputs, _ := spec["inputs"].([]interface{})
+					if len(inputs) == 0 {
+						inputs = []interface{}{map[string]interface{}{}}
+						spec["inputs"] = inputs
+					}
+
+					set, _ := inputs[0].(map[string]interface{})
+					if set == nil {
+						set = make(map[string]interface{})
+						inputs[0] = set
+					}
+
+					set[name] = value
+				},
+			})
+		}
+	}
+
+	return params
+}
+
+// Ask prompts for every declared parameter, and fills the answers into the scenario. An empty answer keeps the
+// default. Answers that do not match the type of the default are rejected, and the parameter is prompted again.
+func (w *Wizard) Ask(in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+
+	for _, param := range w.Parameters() {
+		for {
+			fmt.Fprintf(out, "%s (%s) [%s]: ", param.Name, param.Source, format(param.Default))
+
+			if !scanner.Scan() {
+				if err := scanner.Err(); err != nil {
+					return errors.Wrapf(err, "cannot read answer")
+				}
+
+				return errors.Errorf("no answer for '%s'", param.Name)
+			}
+
+			value, err := Parse(scanner.Text(), param.Default)
+			if err != nil {
+				fmt.Fprintf(out, "  invalid value: %s\n", err)
+
+				continue
+			}
+
+			param.set(value)
+
+			break
+		}
+	}
+
+	return nil
+}
+
+// Parse converts the answer to the type of the default. An empty answer returns the default.
+func Parse(answer string, def interface{}) (interface{}, error) {
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return def, nil
+	}
+
+	switch def.(type) {
+	case string, nil:
+		return answer, nil
+
+	case float64:
+		value, err := strconv.ParseFloat(answer, 64)
+		if err != nil {
+			return nil, errors.Errorf("expected a number, got '%s'", answer)
+		}
+
+		return value, nil
+
+	case bool:
+		value, err := strconv.ParseBool(answer)
+		if err != nil {
+			return nil, errors.Errorf("expected true or false, got '%s'", answer)
+		}
+
+		return value, nil
+
+	default:
+		// lists and objects are given in JSON.
+		var value interface{}
+
+		if err := json.Unmarshal([]byte(answer), &value); err != nil {
+			return nil, errors.Errorf("expected JSON, got '%s'", answer)
+		}
+
+		return value, nil
+	}
+}
+
+// Write writes the documents of the test file, with the answers filled into the scenario.
+func (w *Wizard) Write(out io.Writer) error {
+	var buf bytes.Buffer
+
+	for _, doc := range w.docs {
+		encoded, err := yaml.Marshal(doc)
+		if err != nil {
+			return errors.Wrapf(err, "cannot encode document")
+		}
+
+		buf.WriteString("---\n")
+		buf.Write(encoded)
+	}
+
+	_, err := out.Write(buf.Bytes())
+
+	return err
+}
+
+// field returns the value at the given path of the document, or nil if the path does not exist.
+func field(doc map[string]interface{}, path ...string) interface{} {
+	var current interface{} = doc
+
+	for _, key := range path {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		current = m[key]
+	}
+
+	return current
+}
+
+// child returns the object under the key, and creates it if it does not exist.
+func child(doc map[string]interface{}, key string) map[string]interface{} {
+	obj, ok := doc[key].(map[string]interface{})
+	if !ok {
+		obj = make(map[string]interface{})
+		doc[key] = obj
+	}
+
+	return obj
+}
+
+func str(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+
+	return fmt.Sprint(v)
+}
+
+func format(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		encoded, _ := json.Marshal(v)
+
+		return string(encoded)
+	default:
+		return str(v)
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wizard_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/wizard"
+)
+
+const testFile = `
+apiVersion: frisbee.dev/v1alpha1
+kind: Template
+metadata:
+  name: iperf.server
+spec:
+  inputs:
+    parameters:
+      duration: "60"
+      parallel: 2
+      verbose: false
+    vars:
+      region: eu
+  service:
+    containers:
+      - name: main
+        image: iperf
+---
+apiVersion: frisbee.dev/v1alpha1
+kind: Scenario
+metadata:
+  name: gameday
+spec:
+  vars:
+    target: db
+  actions:
+    - action: Service
+      name: server
+      service:
+        templateRef: iperf.server
+        inputs:
+          - duration: "120"
+    - action: Cluster
+      name: clients
+      cluster:
+        templateRef: iperf.server
+        inputs:
+          - parallel: 1
+          - parallel: 2
+    - action: Delete
+      name: cleanup
+      depends: { running: [ server ] }
+      delete:
+        jobs: [ server ]
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: extra
+`
+
+func TestParameters(t *testing.T) {
+	w, err := wizard.Load(strings.NewReader(testFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"vars.target":     "db",
+		"vars.region":     "eu",
+		"server.duration": "120",
+		"server.parallel": float64(2),
+		"server.verbose":  false,
+	}
+
+	params := w.Parameters()
+	if len(params) != len(expected) {
+		t.Fatalf("expected %d parameters, got %v", len(expected), params)
+	}
+
+	for _, param := range params {
+		def, ok := expected[param.Name]
+		if !ok {
+			t.Fatalf("unexpected parameter '%s'", param.Name)
+		}
+
+		if def != param.Default {
+			t.Fatalf("parameter '%s': expected default '%v', got '%v'", param.Name, def, param.Default)
+		}
+	}
+}
+
+func TestAsk(t *testing.T) {
+	w, err := wizard.Load(strings.NewReader(testFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// vars.target, vars.region, server.duration, server.parallel (invalid, then valid), server.verbose
+	answers := "cache\n\n300\nmany\n4\n\n"
+
+	var prompts bytes.Buffer
+
+	if err := w.Ask(strings.NewReader(answers), &prompts); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(prompts.String(), "invalid value") {
+		t.Fatal("invalid answer was accepted")
+	}
+
+	var out bytes.Buffer
+
+	if err := w.Write(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	rendered := out.String()
+
+	if !strings.Contains(rendered, "kind: ConfigMap") {
+		t.Fatal("unrelated documents must be preserved")
+	}
+
+	// the answers become the defaults of the written test.
+	written, err := wizard.Load(strings.NewReader(rendered))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"vars.target":     "cache",
+		"vars.region":     "eu",
+		"server.duration": "300",
+		"server.parallel": float64(4),
+		"server.verbose":  false,
+	}
+
+	for _, param := range written.Parameters() {
+		if expected[param.Name] != param.Default {
+			t.Fatalf("parameter '%s': expected '%v', got '%v'", param.Name, expected[param.Name], param.Default)
+		}
+	}
+
+	if !strings.Contains(rendered, "parallel: 1") {
+		t.Fatal("multiple input sets must be left unchanged")
+	}
+}
+
+func TestAskEOF(t *testing.T) {
+	w, err := wizard.Load(strings.NewReader(testFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Ask(strings.NewReader("cache\n"), &bytes.Buffer{}); err == nil {
+		t.Fatal("expected error on missing answers")
+	}
+}
+
+func TestLoadWithoutScenario(t *testing.T) {
+	if _, err := wizard.Load(strings.NewReader("kind: Template\nmetadata:\n  name: x\n")); err == nil {
+		t.Fatal("expected error for file without scenario")
+	}
+}
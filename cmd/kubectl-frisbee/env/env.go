@@ -20,6 +20,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	frisbeev1alpha1 "github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	frisbeeclient "github.com/carv-ics-forth/frisbee/pkg/client"
@@ -32,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 
 	// "k8s.io/cli-runtime/pkg/genericclioptions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -45,6 +47,7 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(frisbeev1alpha1.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 }
 
 const (
@@ -60,6 +63,7 @@ type Path struct {
 	helmPath    string
 	nodejsPath  string
 	npmPath     string
+	kindPath    string
 }
 
 // EnvironmentSettings describes all the environment settings.
@@ -85,13 +89,20 @@ type EnvironmentSettings struct {
 	// GoTemplate (if selected by OutputType type)
 	GoTemplate string
 
+	// RequestTimeout bounds every request to the Kubernetes API. Zero means no bound.
+	RequestTimeout time.Duration
+
 	// cached objects
 	client *frisbeeclient.APIClient
 }
 
 func New() *EnvironmentSettings {
+	// The kubeconfig may be missing on the first run (e.g, before "dev up" creates the cluster).
+	// In that case, the commands that need the cluster fail when they access it.
 	kubeconfig, err := config.GetConfig()
-	ui.ExitOnError("Failed to get config", err)
+	if err != nil {
+		ui.Debug("Failed to get config", err.Error())
+	}
 
 	env := &EnvironmentSettings{
 		Path:           Path{}, // will be set by LookupBinaries
@@ -103,7 +114,9 @@ func New() *EnvironmentSettings {
 		Hints:      envBoolOr("FRISBEE_HINTS", false),
 		OutputType: envOr("FRISBEE_OUTPUT_TYPE", defaultOutputType),
 		GoTemplate: "",
-		client:     nil,
+		// Large manifests and busy clusters may need more time than the default.
+		RequestTimeout: envDurationOr("FRISBEE_REQUEST_TIMEOUT", frisbeeclient.DefaultRequestTimeout),
+		client:         nil,
 	}
 
 	/*
@@ -124,6 +137,8 @@ func (env *EnvironmentSettings) AddFlags(cmd *cobra.Command) {
 	// and add new ones
 	pfs.BoolVarP(&env.Debug, "debug", "d", env.Debug, "enable verbose output")
 	pfs.BoolVar(&env.Hints, "hints", env.Hints, "enable hints in the output")
+	pfs.DurationVar(&env.RequestTimeout, "request-timeout", env.RequestTimeout,
+		"maximum time for every request to the Kubernetes API. Zero means no timeout.")
 }
 
 func envOr(name, def string) string {
@@ -172,6 +187,15 @@ func envIntOr(name string, def int) int {
 	return ret
 }
 
+func envDurationOr(name string, def time.Duration) time.Duration {
+	ret, err := time.ParseDuration(envOr(name, def.String()))
+	if err != nil {
+		return def
+	}
+
+	return ret
+}
+
 func envCSV(name string) (ls []string) {
 	trimmed := strings.Trim(os.Getenv(name), ", ")
 	if trimmed != "" {
@@ -195,11 +219,16 @@ func (env *EnvironmentSettings) GetFrisbeeClient() *frisbeeclient.APIClient {
 		return env.client
 	}
 
-	// create generic client
-	genericClient, err := client.New(env.KubeConfig, client.Options{Scheme: scheme})
+	if env.KubeConfig == nil {
+		ui.Failf("Kubernetes API is not configured. Set the KUBECONFIG or use 'kubectl frisbee dev up'.")
+	}
+
+	// create generic client. The watches are used for streaming the status of the tests.
+	genericClient, err := client.NewWithWatch(env.KubeConfig, client.Options{Scheme: scheme})
 	ui.ExitOnError("Setting up generic client", err)
 
 	c := frisbeeclient.NewDirectAPIClient(genericClient)
+	c.TestManagementClient = c.WithTimeout(env.RequestTimeout)
 	env.client = &c
 
 	return env.client
@@ -238,6 +267,15 @@ func (p *Path) NodeJS() string {
 	return p.nodejsPath
 }
 
+// Kind returns path to the kind binary.
+func (p *Path) Kind() string {
+	if p.kindPath == "" {
+		ui.Fail(errors.Errorf("command requires 'kind' to be installed in your system"))
+	}
+
+	return p.kindPath
+}
+
 // NPM returns path to the node binary.
 func (p *Path) NPM() string {
 	if p.npmPath == "" {
@@ -42,5 +42,11 @@ func Logo() {
 	fmt.Fprint(ui.Writer, ui.Blue(logo()))
 	fmt.Fprintln(ui.Writer)
 
+	if Default.KubeConfig == nil {
+		ui.Warn("Kubernetes API:", "not configured")
+
+		return
+	}
+
 	ui.Success("Kubernetes API:", Default.KubeConfig.Host)
 }
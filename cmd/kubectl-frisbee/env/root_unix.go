@@ -49,4 +49,9 @@ func (env *EnvironmentSettings) LookupBinaries() {
 	}
 
 	env.npmPath = npmPath
+
+	// kind is only required for the local development clusters.
+	kindPath, _ := exec.New().LookPath("kind")
+
+	env.kindPath = kindPath
 }
@@ -17,12 +17,21 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands"
 	"github.com/kubeshop/testkube/pkg/ui"
 )
 
 func main() {
-	if err := commands.NewRootCmd().Execute(); err != nil {
+	// Ctrl-C cancels the context of the command, so that the command can clean up before exiting.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := commands.NewRootCmd().ExecuteContext(ctx); err != nil {
 		ui.Fail(err)
 	}
 }
@@ -17,22 +17,37 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
+	"time"
 
 	frisbeev1alpha1 "github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/carv-ics-forth/frisbee/controllers/call"
 	"github.com/carv-ics-forth/frisbee/controllers/cascade"
 	"github.com/carv-ics-forth/frisbee/controllers/chaos"
 	"github.com/carv-ics-forth/frisbee/controllers/cluster"
+	"github.com/carv-ics-forth/frisbee/controllers/frisbeeconfig"
 	"github.com/carv-ics-forth/frisbee/controllers/scenario"
 	"github.com/carv-ics-forth/frisbee/controllers/service"
 	"github.com/carv-ics-forth/frisbee/controllers/template"
+	"github.com/carv-ics-forth/frisbee/pkg/chaosschema"
+	"github.com/carv-ics-forth/frisbee/pkg/clock"
+	"github.com/carv-ics-forth/frisbee/pkg/logging"
+	"github.com/carv-ics-forth/frisbee/pkg/policy"
+	"github.com/carv-ics-forth/frisbee/pkg/tracing"
 	"github.com/pkg/errors"
+	uberzap "go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -54,9 +69,25 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(frisbeev1alpha1.AddToScheme(scheme))
+
+	// the CRDs of Chaos-Mesh are read for validating the raw faults.
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
+// cacheOptions restricts the informers of high-churn types to the objects created by Frisbee.
+// Without it, every Pod in the cluster is cached and every Pod update is dispatched to the watchers.
+func cacheOptions() cache.Options {
+	createdByFrisbee, err := labels.NewRequirement(frisbeev1alpha1.LabelCreatedBy, selection.Exists, nil)
+	utilruntime.Must(err)
+
+	return cache.Options{
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.Pod{}: {Label: labels.NewSelector().Add(*createdByFrisbee)},
+		},
+	}
+}
+
 func main() {
 	var (
 		// admission webhooks
@@ -69,6 +100,16 @@ func main() {
 
 		enableChaos bool
 
+		// directory with the admission policies
+		policiesDir string
+
+		// client-side throttling of the requests to the API server
+		kubeAPIQPS   float64
+		kubeAPIBurst int
+
+		// export of the reconciliation traces
+		tracingOpts tracing.Options
+
 		// logger
 		verbose int
 	)
@@ -77,6 +118,8 @@ func main() {
 
 	flag.BoolVar(&enableChaos, "enable-chaos", true, "Enable Chaos controllers.")
 
+	flag.StringVar(&policiesDir, "policies", "", "Points to the directory with the admission policies. If empty, policies are not enforced.")
+
 	// flag.StringVar(&namespace, "namespace", "default", "Restricts the manager's cache to watch objects in this namespace ")
 
 	// If set to "0" the metrics serving is disabled (otherwise, :8080).
@@ -84,6 +127,21 @@ func main() {
 
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 
+	flag.Float64Var(&kubeAPIQPS, "kube-api-qps", 0,
+		"Maximum sustained QPS from the controllers to the API server. "+
+			"If zero, the controller-runtime default (20) is used. A negative value disables client-side throttling.")
+
+	flag.IntVar(&kubeAPIBurst, "kube-api-burst", 0,
+		"Maximum burst of requests from the controllers to the API server. "+
+			"If zero, the controller-runtime default (30) is used.")
+
+	flag.StringVar(&tracingOpts.Endpoint, "otlp-endpoint", "",
+		"Address (host:port) of the OTLP/gRPC collector for the reconciliation traces. If empty, tracing is disabled.")
+
+	flag.BoolVar(&tracingOpts.Insecure, "otlp-insecure", false, "Disable transport security towards the OTLP collector.")
+
+	flag.Float64Var(&tracingOpts.SampleRatio, "otlp-sample-ratio", 1, "Fraction of the reconciliation traces that are exported.")
+
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -92,17 +150,48 @@ func main() {
 
 	opts := zap.Options{
 		Development: true,
-		Level:       zapcore.Level(verbose),
 		TimeEncoder: zapcore.EpochNanosTimeEncoder,
 	}
 
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
+	// The level is shared by all the loggers, so that the FrisbeeConfig can change it at runtime.
+	// The --zap-log-level takes precedence over the --verbosity.
+	logging.SetDefaultLevel(zapcore.Level(verbose))
+
+	if lvl, ok := opts.Level.(uberzap.AtomicLevel); ok {
+		logging.SetDefaultLevel(lvl.Level())
+	}
+
+	debugOpts := opts
+	debugOpts.Level = zapcore.DebugLevel
+
+	opts.Level = logging.Level()
+
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	logging.SetDebugLogger(zap.New(zap.UseFlagOptions(&debugOpts)).WithName("debug"))
+
+	restConfig := ctrl.GetConfigOrDie()
+
+	if kubeAPIQPS != 0 {
+		restConfig.QPS = float32(kubeAPIQPS)
+	}
+
+	if kubeAPIBurst < 0 {
+		setupLog.Error(errors.Errorf("invalid burst '%d'", kubeAPIBurst), "burst must be non-negative")
+		os.Exit(1)
+	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	if kubeAPIBurst != 0 {
+		restConfig.Burst = kubeAPIBurst
+	}
+
+	setupLog.Info("API client throttling", "qps", restConfig.QPS, "burst", restConfig.Burst)
+
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme: scheme,
+		Cache:  cacheOptions(),
 		WebhookServer: webhook.NewServer(webhook.Options{
 			// Port:    o.Port,
 			Host:    "0.0.0.0",
@@ -121,51 +210,77 @@ func main() {
 
 	// Add controllers
 	{
-		if err := template.NewController(mgr, setupLog); err != nil {
+		clk := clock.RealClock{}
+
+		if err := frisbeeconfig.NewController(mgr, setupLog, clk); err != nil {
+			utilruntime.HandleError(errors.Wrapf(err, "cannot create FrisbeeConfig controller"))
+
+			os.Exit(1)
+		}
+
+		if err := template.NewController(mgr, setupLog, clk); err != nil {
 			utilruntime.HandleError(errors.Wrapf(err, "cannot create Templates controller"))
 
 			os.Exit(1)
 		}
 
-		if err := service.NewController(mgr, setupLog); err != nil {
+		if err := service.NewController(mgr, setupLog, clk); err != nil {
 			utilruntime.HandleError(errors.Wrapf(err, "cannot create Service controller"))
 
 			os.Exit(1)
 		}
 
-		if err := cluster.NewController(mgr, setupLog); err != nil {
+		if err := cluster.NewController(mgr, setupLog, clk); err != nil {
 			utilruntime.HandleError(errors.Wrapf(err, "cannot create Cluster controller"))
 
 			os.Exit(1)
 		}
 
 		if enableChaos {
-			if err := chaos.NewController(mgr, setupLog); err != nil {
+			if err := chaos.NewController(mgr, setupLog, clk); err != nil {
 				utilruntime.HandleError(errors.Wrapf(err, "cannot create Chaos controller"))
 
 				os.Exit(1)
 			}
 		}
 
-		if err := cascade.NewController(mgr, setupLog); err != nil {
+		if err := cascade.NewController(mgr, setupLog, clk); err != nil {
 			utilruntime.HandleError(errors.Wrapf(err, "cannot create Cascade controller"))
 
 			os.Exit(1)
 		}
 
-		if err := call.NewController(mgr, setupLog); err != nil {
+		if err := call.NewController(mgr, setupLog, clk); err != nil {
 			utilruntime.HandleError(errors.Wrapf(err, "cannot create Call controller"))
 
 			os.Exit(1)
 		}
 
-		if err := scenario.NewController(mgr, setupLog); err != nil {
+		if err := scenario.NewController(mgr, setupLog, clk); err != nil {
 			utilruntime.HandleError(errors.Wrapf(err, "cannot create Scenario controller"))
 
 			os.Exit(1)
 		}
 	}
 
+	// Add admission policies
+	if policiesDir != "" {
+		engine, err := policy.Load(policiesDir)
+		if err != nil {
+			setupLog.Error(err, "cannot load policies", "dir", policiesDir)
+			os.Exit(1)
+		}
+
+		frisbeev1alpha1.SetPolicyValidator(engine)
+
+		setupLog.Info("Admission policies loaded", "dir", policiesDir, "policies", engine.Len())
+	}
+
+	// Validate the raw faults against the schemas of the installed Chaos-Mesh
+	if enableChaos {
+		frisbeev1alpha1.SetChaosSchemaValidator(chaosschema.New(mgr.GetAPIReader(), mgr.GetRESTMapper()))
+	}
+
 	{
 		if err = (&frisbeev1alpha1.Template{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "cannot create webhook", "webhook", "Template")
@@ -235,9 +350,27 @@ func main() {
 
 	ctx := ctrl.SetupSignalHandler()
 
+	shutdownTracing, err := tracing.Setup(ctx, tracingOpts)
+	if err != nil {
+		setupLog.Error(err, "cannot set up tracing", "endpoint", tracingOpts.Endpoint)
+		os.Exit(1)
+	}
+
+	if tracingOpts.Endpoint != "" {
+		setupLog.Info("Tracing enabled", "endpoint", tracingOpts.Endpoint, "ratio", tracingOpts.SampleRatio)
+	}
+
 	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 
 		os.Exit(1)
 	}
+
+	// the signal context is already cancelled. Give the exporter some time to flush the pending spans.
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := shutdownTracing(flushCtx); err != nil {
+		setupLog.Error(err, "cannot flush traces")
+	}
 }
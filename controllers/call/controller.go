@@ -18,21 +18,19 @@ package call
 
 import (
 	"context"
-	"fmt"
 	"reflect"
 	"time"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/controllers/common/jobgroup"
 	"github.com/carv-ics-forth/frisbee/controllers/common/watchers"
-	"github.com/carv-ics-forth/frisbee/pkg/expressions"
+	"github.com/carv-ics-forth/frisbee/pkg/clock"
 	"github.com/carv-ics-forth/frisbee/pkg/kubexec"
 	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
-	"github.com/carv-ics-forth/frisbee/pkg/scheduler"
+	"github.com/carv-ics-forth/frisbee/pkg/tracing"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -55,6 +53,7 @@ import (
 type Controller struct {
 	ctrl.Manager
 	logr.Logger
+	clock.Clock
 
 	view *lifecycle.Classifier
 
@@ -65,6 +64,9 @@ type Controller struct {
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current view of the cluster closer to the desired view.
 func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracing.StartReconcile(ctx, "Call", req.NamespacedName)
+	defer span.End()
+
 	/*
 		1: Load CR by name and extract the Desired State
 		------------------------------------------------------------------
@@ -119,7 +121,7 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		------------------------------------------------------------------
 	*/
 
-	if call.Spec.Suspend != nil && *call.Spec.Suspend {
+	if group(&call).IsSuspended() {
 		// If this object is suspended, we don't want to run any jobs, so we'll stop now.
 		// This is useful if something's broken with the job we're running, and we want to
 		// pause runs to investigate the cluster, without deleting the object.
@@ -128,8 +130,6 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return common.Stop(r, req)
 	}
 
-	log := r.Logger.WithValues("object", client.ObjectKeyFromObject(&call))
-
 	switch call.Status.Phase {
 	case v1alpha1.PhaseUninitialized:
 		if err := r.Initialize(ctx, &call); err != nil {
@@ -139,61 +139,9 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return lifecycle.Pending(ctx, r, &call, "ready to start creating jobs.")
 
 	case v1alpha1.PhasePending:
-		//	If all jobs are scheduled but are not in the Running phase, they may be in the Pending phase.
-		//	In both cases, we have nothing else to do but waiting for the next reconciliation cycle.
-		if r.view.Count() >= len(call.Status.QueuedJobs) {
-			r.Logger.Info("All jobs have been scheduled. Nothing else to do. ")
-			return common.Stop(r, req)
-		}
-
-		// Check if the conditions are right to spawn a new job.
-		hasJob, nextTick, err := scheduler.Schedule(log, &call, scheduler.Parameters{
-			State:            *r.view,
-			LastScheduleTime: call.Status.LastScheduleTime,
-			ScheduleSpec:     call.Spec.Schedule,
-			ExpectedTimeline: call.Status.ExpectedTimeline,
-			JobName:          call.GetName(),
-			ScheduledJobs:    call.Status.ScheduledJobs,
+		return jobgroup.ScheduleNext(ctx, r, req, r.view, group(&call), func(ctx context.Context, jobIndex int) error {
+			return r.runJob(ctx, &call, jobIndex)
 		})
-		if err != nil {
-			return lifecycle.Failed(ctx, r, &call, errors.Wrapf(err, "scheduling error"))
-		}
-
-		if !hasJob {
-			// nothing to schedule
-			if nextTick.IsZero() {
-				return common.Stop(r, req)
-			}
-
-			// sleep until next tick
-			return common.RequeueAfter(r, req, time.Until(nextTick))
-		}
-
-		// Fetch the next job from the queuing list, and submit it to Kubernetes.
-		nextJobIndex := call.Status.ScheduledJobs + 1
-
-		if nextJobIndex >= len(call.Status.QueuedJobs) {
-			r.Logger.Error(errors.New("Ignore job as it is out of range compared to QueuedJobs"),
-				"BadScheduling",
-				"nextJobIndex", nextJobIndex,
-				"queueJobs", len(call.Status.QueuedJobs),
-				"viewedJobs", r.view.Count(),
-				"jobList", r.view.ListAll(),
-			)
-
-			return common.Stop(r, req)
-		}
-
-		if err := r.runJob(ctx, &call, nextJobIndex); err != nil {
-			return lifecycle.Failed(ctx, r, &call, errors.Wrapf(err, "cannot create job"))
-		}
-
-		// Update the scheduling information
-		call.Status.ScheduledJobs = nextJobIndex
-		call.Status.LastScheduleTime = metav1.Time{Time: time.Now()}
-
-		return lifecycle.Pending(ctx, r, &call, fmt.Sprintf("Scheduled jobs: '%d/%d'",
-			call.Status.ScheduledJobs+1, len(call.Spec.Services)))
 
 	case v1alpha1.PhaseRunning:
 		// Nothing to do. Just wait for something to happen.
@@ -229,23 +177,9 @@ func (r *Controller) Initialize(ctx context.Context, call *v1alpha1.Call) error
 	}
 
 	call.Status.QueuedJobs = jobList
-	call.Status.ScheduledJobs = -1
-
-	// Metrics-driven execution requires to set alerts on Grafana.
-	if until := call.Spec.SuspendWhen; until != nil && until.HasMetricsExpr() {
-		if err := expressions.SetAlert(ctx, call, until.Metrics); err != nil {
-			return errors.Wrapf(err, "spec.suspendWhen")
-		}
-	}
-
-	if schedule := call.Spec.Schedule; schedule != nil && schedule.Event.HasMetricsExpr() {
-		if err := expressions.SetAlert(ctx, call, schedule.Event.Metrics); err != nil {
-			return errors.Wrapf(err, "spec.schedule")
-		}
-	}
 
-	if _, err := lifecycle.Pending(ctx, r, call, "submitting job requests"); err != nil {
-		return errors.Wrapf(err, "status update")
+	if err := jobgroup.Initialize(ctx, r, group(call)); err != nil {
+		return errors.Wrapf(err, "initialize group")
 	}
 
 	return nil
@@ -269,56 +203,11 @@ func (r *Controller) PopulateView(ctx context.Context, req types.NamespacedName)
 }
 
 func (r *Controller) HasSucceed(ctx context.Context, call *v1alpha1.Call) error {
-	r.Logger.Info("CleanOnSuccess",
-		"obj", client.ObjectKeyFromObject(call).String(),
-		"sucessfulJobs", r.view.ListSuccessfulJobs(),
-	)
-
-	/*
-		Remove cr children once the call is successfully complete.
-		We should not remove the call descriptor itself, as we need to maintain its
-		status for higher-entities like the Scenario.
-	*/
-	for _, job := range r.view.GetSuccessfulJobs() {
-		common.Delete(ctx, r, job)
-	}
-
-	return nil
+	return jobgroup.HasSucceed(ctx, r, r.view, group(call))
 }
 
 func (r *Controller) HasFailed(ctx context.Context, call *v1alpha1.Call) error {
-	r.Logger.Info("!! JobError",
-		"obj", client.ObjectKeyFromObject(call).String(),
-		"reason", call.Status.Reason,
-		"message", call.Status.Message,
-	)
-
-	// Remove the non-failed components. Leave the failed jobs and system jobs for postmortem analysis.
-	for _, job := range r.view.GetPendingJobs() {
-		common.Delete(ctx, r, job)
-	}
-
-	for _, job := range r.view.GetRunningJobs() {
-		common.Delete(ctx, r, job)
-	}
-
-	// Block from creating further jobs
-	suspend := true
-	call.Spec.Suspend = &suspend
-
-	r.Logger.Info("Suspended",
-		"obj", client.ObjectKeyFromObject(call),
-		"reason", call.Status.Reason,
-		"message", call.Status.Message,
-	)
-
-	if call.GetDeletionTimestamp().IsZero() {
-		r.GetEventRecorderFor(call.GetName()).Event(call, corev1.EventTypeNormal,
-			"Suspended", call.Status.Lifecycle.Message)
-	}
-
-	// Update is needed since we modify the spec.suspend
-	return common.Update(ctx, r, call)
+	return jobgroup.HasFailed(ctx, r, r.view, group(call))
 }
 
 /*
@@ -353,10 +242,11 @@ func (r *Controller) Finalize(obj client.Object) error {
 	deleted, etc.
 */
 
-func NewController(mgr ctrl.Manager, logger logr.Logger) error {
+func NewController(mgr ctrl.Manager, logger logr.Logger, clk clock.Clock) error {
 	reconciler := &Controller{
 		Manager:  mgr,
 		Logger:   logger.WithName("call"),
+		Clock:    clk,
 		view:     &lifecycle.Classifier{},
 		executor: kubexec.NewExecutor(mgr.GetConfig()),
 	}
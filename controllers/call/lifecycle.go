@@ -17,92 +17,28 @@ limitations under the License.
 package call
 
 import (
-	"fmt"
-
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
-	"github.com/carv-ics-forth/frisbee/pkg/expressions"
-	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
-	"k8s.io/apimachinery/pkg/api/meta"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"github.com/carv-ics-forth/frisbee/controllers/common/jobgroup"
 )
 
-// updateLifecycle returns the update lifecycle of the cluster.
-func (r *Controller) updateLifecycle(call *v1alpha1.Call) bool {
-	// Step 1. Skip any CR which are already completed, or uninitialized.
-	if call.Status.Lifecycle.Phase.Is(v1alpha1.PhaseUninitialized, v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed) {
-		return false
-	}
-
-	/*---------------------------------------------------
-	 * Non-Suspended execution
-	 *---------------------------------------------------*/
-	if call.Spec.SuspendWhen.IsZero() {
-		totalJobs := len(call.Status.QueuedJobs)
-
-		return lifecycle.GroupedJobs(totalJobs, r.view, &call.Status.Lifecycle, call.Spec.Tolerate)
-	}
-
-	/*---------------------------------------------------
-	 * Suspended execution
-	 *---------------------------------------------------*/
-	if meta.IsStatusConditionTrue(call.Status.Conditions, v1alpha1.ConditionAllJobsAreScheduled.String()) {
-		// The Until condition is already handled, and we are in the Running Phase.
-		// From now on, the lifecycle depends on the progress of the already scheduled jobs.
-		totalJobs := call.Status.ScheduledJobs + 1
-
-		return lifecycle.GroupedJobs(totalJobs, r.view, &call.Status.Lifecycle, call.Spec.Tolerate)
-	}
-
-	eval := expressions.Condition{Expr: call.Spec.SuspendWhen}
-	if eval.IsTrue(r.view, call) {
-		call.Status.Lifecycle.Phase = v1alpha1.PhaseRunning
-		call.Status.Lifecycle.Reason = "UntilCondition"
-		call.Status.Lifecycle.Message = eval.Info
-
-		meta.SetStatusCondition(&call.Status.Lifecycle.Conditions, metav1.Condition{
-			Type:    v1alpha1.ConditionAllJobsAreScheduled.String(),
-			Status:  metav1.ConditionTrue,
-			Reason:  "UntilCondition",
-			Message: eval.Info,
-		})
-
-		// prevent the parent from spawning new jobs.
-		suspend := true
-		call.Spec.Suspend = &suspend
-
-		return true
+// group returns the job-group view of the call.
+func group(call *v1alpha1.Call) jobgroup.Group {
+	return jobgroup.Group{
+		Object:           call,
+		Lifecycle:        &call.Status.Lifecycle,
+		Suspend:          &call.Spec.Suspend,
+		SuspendWhen:      call.Spec.SuspendWhen,
+		Schedule:         call.Spec.Schedule,
+		Tolerate:         call.Spec.Tolerate,
+		MaxInstances:     len(call.Spec.Services),
+		QueuedJobs:       len(call.Status.QueuedJobs),
+		ScheduledJobs:    &call.Status.ScheduledJobs,
+		LastScheduleTime: &call.Status.LastScheduleTime,
+		ExpectedTimeline: call.Status.ExpectedTimeline,
 	}
+}
 
-	// Event used in conjunction with "Until", instance act as a maximum bound.
-	// If the maximum instances are reached before the Until conditions, we assume that
-	// the experiment never converges, and it fails.
-	maxJobs := len(call.Spec.Services)
-
-	if maxJobs > 0 && (call.Status.ScheduledJobs > maxJobs) {
-		msg := fmt.Sprintf(`Resource [%s] has reached Max instances [%d] before Until conditions are met.
-			Abort the experiment as it too flaky to accept. You can retry without defining instances.`,
-			call.GetName(), maxJobs)
-
-		call.Status.Lifecycle.Phase = v1alpha1.PhaseFailed
-		call.Status.Lifecycle.Reason = "MaxInstancesReached"
-		call.Status.Lifecycle.Message = msg
-
-		meta.SetStatusCondition(&call.Status.Lifecycle.Conditions, metav1.Condition{
-			Type:    v1alpha1.ConditionJobUnexpectedTermination.String(),
-			Status:  metav1.ConditionTrue,
-			Reason:  "MaxInstancesReached",
-			Message: msg,
-		})
-
-		return true
-	}
-
-	// A side effect of "Until" is that queued jobs will be reused,
-	// until the conditions are met. In that sense, they resemble mostly a pool of jobs
-	// rather than e queue.
-	call.Status.Lifecycle.Phase = v1alpha1.PhasePending
-	call.Status.Lifecycle.Reason = "SpawnUntilEvent"
-	call.Status.Lifecycle.Message = "Assertion is not yet satisfied."
-
-	return true
+// updateLifecycle returns the update lifecycle of the call.
+func (r *Controller) updateLifecycle(call *v1alpha1.Call) bool {
+	return jobgroup.UpdateLifecycle(r.view, group(call))
 }
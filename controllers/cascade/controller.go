@@ -18,20 +18,18 @@ package cascade
 
 import (
 	"context"
-	"fmt"
 	"reflect"
 	"time"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/controllers/common/jobgroup"
 	"github.com/carv-ics-forth/frisbee/controllers/common/watchers"
-	"github.com/carv-ics-forth/frisbee/pkg/expressions"
+	"github.com/carv-ics-forth/frisbee/pkg/clock"
 	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
-	"github.com/carv-ics-forth/frisbee/pkg/scheduler"
+	"github.com/carv-ics-forth/frisbee/pkg/tracing"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -45,6 +43,7 @@ import (
 type Controller struct {
 	ctrl.Manager
 	logr.Logger
+	clock.Clock
 
 	view *lifecycle.Classifier
 }
@@ -52,6 +51,9 @@ type Controller struct {
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current view of the cascade closer to the desired view.
 func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracing.StartReconcile(ctx, "Cascade", req.NamespacedName)
+	defer span.End()
+
 	/*
 		1: Load CR by name and extract the Desired State
 		------------------------------------------------------------------
@@ -106,7 +108,7 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		------------------------------------------------------------------
 	*/
 
-	if cascade.Spec.Suspend != nil && *cascade.Spec.Suspend {
+	if group(&cascade).IsSuspended() {
 		// If this object is suspended, we don't want to run any jobs, so we'll stop now.
 		// This is useful if something's broken with the job we're running, and we want to
 		// pause runs to investigate the cluster, without deleting the object.
@@ -115,8 +117,6 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return common.Stop(r, req)
 	}
 
-	log := r.Logger.WithValues("object", client.ObjectKeyFromObject(&cascade))
-
 	switch cascade.Status.Phase {
 	case v1alpha1.PhaseUninitialized:
 		if err := r.Initialize(ctx, &cascade); err != nil {
@@ -126,62 +126,9 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return lifecycle.Pending(ctx, r, &cascade, "ready to start creating jobs.")
 
 	case v1alpha1.PhasePending:
-		//	If all jobs are scheduled but are not in the Running phase, they may be in the Pending phase.
-		//	In both cases, we have nothing else to do but waiting for the next reconciliation cycle.
-		if r.view.Count() >= len(cascade.Status.QueuedJobs) {
-			r.Logger.Info("All jobs have been scheduled. Nothing else to do. ")
-
-			return common.Stop(r, req)
-		}
-
-		// Check if the conditions are right to spawn a new job.
-		hasJob, nextTick, err := scheduler.Schedule(log, &cascade, scheduler.Parameters{
-			State:            *r.view,
-			ScheduleSpec:     cascade.Spec.Schedule,
-			LastScheduleTime: cascade.Status.LastScheduleTime,
-			ExpectedTimeline: cascade.Status.ExpectedTimeline,
-			JobName:          cascade.GetName(),
-			ScheduledJobs:    cascade.Status.ScheduledJobs,
+		return jobgroup.ScheduleNext(ctx, r, req, r.view, group(&cascade), func(ctx context.Context, jobIndex int) error {
+			return r.runJob(ctx, &cascade, jobIndex)
 		})
-		if err != nil {
-			return lifecycle.Failed(ctx, r, &cascade, errors.Wrapf(err, "scheduling error"))
-		}
-
-		if !hasJob {
-			// nothing to schedule
-			if nextTick.IsZero() {
-				return common.Stop(r, req)
-			}
-
-			// sleep until next tick
-			return common.RequeueAfter(r, req, time.Until(nextTick))
-		}
-
-		// Fetch the next job from the queuing list, and submit it to Kubernetes.
-		nextJobIndex := cascade.Status.ScheduledJobs + 1
-
-		if nextJobIndex >= len(cascade.Status.QueuedJobs) {
-			r.Logger.Error(errors.New("Ignore job as it is out of range compared to QueuedJobs"),
-				"BadScheduling",
-				"nextJobIndex", nextJobIndex,
-				"queueJobs", len(cascade.Status.QueuedJobs),
-				"viewedJobs", r.view.Count(),
-				"jobList", r.view.ListAll(),
-			)
-
-			return common.Stop(r, req)
-		}
-
-		if err := r.runJob(ctx, &cascade, nextJobIndex); err != nil {
-			return lifecycle.Failed(ctx, r, &cascade, errors.Wrapf(err, "cannot create job"))
-		}
-
-		// Update the scheduling information
-		cascade.Status.ScheduledJobs = nextJobIndex
-		cascade.Status.LastScheduleTime = metav1.Time{Time: time.Now()}
-
-		return lifecycle.Pending(ctx, r, &cascade, fmt.Sprintf("Scheduled jobs: '%d/%d'",
-			cascade.Status.ScheduledJobs+1, cascade.Spec.MaxInstances))
 
 	case v1alpha1.PhaseRunning:
 		// Nothing to do. Just wait for something to happen.
@@ -217,23 +164,9 @@ func (r *Controller) Initialize(ctx context.Context, cascade *v1alpha1.Cascade)
 	}
 
 	cascade.Status.QueuedJobs = jobList
-	cascade.Status.ScheduledJobs = -1
 
-	// Metrics-driven execution requires to set alerts on Grafana.
-	if until := cascade.Spec.SuspendWhen; until != nil && until.HasMetricsExpr() {
-		if err := expressions.SetAlert(ctx, cascade, until.Metrics); err != nil {
-			return errors.Wrapf(err, "spec.suspendWhen")
-		}
-	}
-
-	if schedule := cascade.Spec.Schedule; schedule != nil && schedule.Event.HasMetricsExpr() {
-		if err := expressions.SetAlert(ctx, cascade, schedule.Event.Metrics); err != nil {
-			return errors.Wrapf(err, "spec.schedule")
-		}
-	}
-
-	if _, err := lifecycle.Pending(ctx, r, cascade, "submitting job requests"); err != nil {
-		return errors.Wrapf(err, "status update")
+	if err := jobgroup.Initialize(ctx, r, group(cascade)); err != nil {
+		return errors.Wrapf(err, "initialize group")
 	}
 
 	return nil
@@ -257,56 +190,11 @@ func (r *Controller) PopulateView(ctx context.Context, req types.NamespacedName)
 }
 
 func (r *Controller) HasSucceed(ctx context.Context, cascade *v1alpha1.Cascade) error {
-	r.Logger.Info("CleanOnSuccess",
-		"obj", client.ObjectKeyFromObject(cascade).String(),
-		"successfulJobs", r.view.ListSuccessfulJobs(),
-	)
-
-	/*
-		Remove cr children once the cr is successfully complete.
-		We should not remove the cr descriptor itself, as we need to maintain its
-		status for higher-entities like the Scenario.
-	*/
-	for _, job := range r.view.GetSuccessfulJobs() {
-		common.Delete(ctx, r, job)
-	}
-
-	return nil
+	return jobgroup.HasSucceed(ctx, r, r.view, group(cascade))
 }
 
 func (r *Controller) HasFailed(ctx context.Context, cascade *v1alpha1.Cascade) error {
-	r.Logger.Info("!! JobError",
-		"obj", client.ObjectKeyFromObject(cascade).String(),
-		"reason ", cascade.Status.Reason,
-		"message", cascade.Status.Message,
-	)
-
-	// Remove the non-failed components. Leave the failed jobs and system jobs for postmortem analysis.
-	for _, job := range r.view.GetPendingJobs() {
-		common.Delete(ctx, r, job)
-	}
-
-	for _, job := range r.view.GetRunningJobs() {
-		common.Delete(ctx, r, job)
-	}
-
-	// Block from creating further jobs
-	suspend := true
-	cascade.Spec.Suspend = &suspend
-
-	r.Logger.Info("Suspended",
-		"obj", client.ObjectKeyFromObject(cascade),
-		"reason", cascade.Status.Reason,
-		"message", cascade.Status.Message,
-	)
-
-	if cascade.GetDeletionTimestamp().IsZero() {
-		r.GetEventRecorderFor(cascade.GetName()).Event(cascade, corev1.EventTypeNormal,
-			"Suspended", cascade.Status.Lifecycle.Message)
-	}
-
-	// Update is needed since we modify the spec.suspend
-	return common.Update(ctx, r, cascade)
+	return jobgroup.HasFailed(ctx, r, r.view, group(cascade))
 }
 
 /*
@@ -337,10 +225,11 @@ func (r *Controller) Finalize(obj client.Object) error {
 	deleted, etc.
 */
 
-func NewController(mgr ctrl.Manager, logger logr.Logger) error {
+func NewController(mgr ctrl.Manager, logger logr.Logger, clk clock.Clock) error {
 	controller := &Controller{
 		Manager: mgr,
 		Logger:  logger.WithName("cascade"),
+		Clock:   clk,
 		view:    &lifecycle.Classifier{},
 	}
 
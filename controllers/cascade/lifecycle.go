@@ -17,87 +17,27 @@ limitations under the License.
 package cascade
 
 import (
-	"fmt"
-
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
-	"github.com/carv-ics-forth/frisbee/pkg/expressions"
-	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
-	"k8s.io/apimachinery/pkg/api/meta"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"github.com/carv-ics-forth/frisbee/controllers/common/jobgroup"
 )
 
-// updateLifecycle returns the update lifecycle of the cascade.
-func (r *Controller) updateLifecycle(cr *v1alpha1.Cascade) bool {
-	// Step 1. Skip any CR which are already completed, or uninitialized.
-	if cr.Status.Phase.Is(v1alpha1.PhaseUninitialized, v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed) {
-		return false
-	}
-
-	// Step 2. Check if "SuspendWhen" conditions are met.
-	if !cr.Spec.SuspendWhen.IsZero() {
-		if meta.IsStatusConditionTrue(cr.Status.Conditions, v1alpha1.ConditionAllJobsAreScheduled.String()) {
-			// The Until condition is already handled, and we are in the Running Phase.
-			// From now on, the lifecycle depends on the progress of the already scheduled jobs.
-			totalJobs := cr.Status.ScheduledJobs + 1
-			return lifecycle.GroupedJobs(totalJobs, r.view, &cr.Status.Lifecycle, nil)
-		}
-
-		eval := expressions.Condition{Expr: cr.Spec.SuspendWhen}
-		if eval.IsTrue(r.view, cr) {
-			cr.Status.Lifecycle.Phase = v1alpha1.PhaseRunning
-			cr.Status.Lifecycle.Reason = "UntilCondition"
-			cr.Status.Lifecycle.Message = eval.Info
-
-			meta.SetStatusCondition(&cr.Status.Lifecycle.Conditions, metav1.Condition{
-				Type:    v1alpha1.ConditionAllJobsAreScheduled.String(),
-				Status:  metav1.ConditionTrue,
-				Reason:  "UntilCondition",
-				Message: eval.Info,
-			})
-
-			// prevent the parent from spawning new jobs.
-			suspend := true
-			cr.Spec.Suspend = &suspend
-
-			return true
-		}
-
-		// Event used in conjunction with "Until", instance act as a maximum bound.
-		// If the maximum instances are reached before the Until conditions, we assume that
-		// the experiment never converges, and it fails.
-		maxJobs := cr.Spec.MaxInstances
-
-		if maxJobs > 0 && (cr.Status.ScheduledJobs > maxJobs) {
-			msg := fmt.Sprintf(`Resource [%s] has reached Max instances [%d] before Until conditions are met.
-			Abort the experiment as it too flaky to accept. You can retry without defining instances.`,
-				cr.GetName(), maxJobs)
-
-			cr.Status.Lifecycle.Phase = v1alpha1.PhaseFailed
-			cr.Status.Lifecycle.Reason = "MaxInstancesReached"
-			cr.Status.Lifecycle.Message = msg
-
-			meta.SetStatusCondition(&cr.Status.Lifecycle.Conditions, metav1.Condition{
-				Type:    v1alpha1.ConditionJobUnexpectedTermination.String(),
-				Status:  metav1.ConditionTrue,
-				Reason:  "MaxInstancesReached",
-				Message: msg,
-			})
-
-			return true
-		}
-
-		// A side effect of "Until" is that queued jobs will be reused,
-		// until the conditions are met. In that sense, they resemble mostly a pool of jobs
-		// rather than e queue.
-		cr.Status.Lifecycle.Phase = v1alpha1.PhasePending
-		cr.Status.Lifecycle.Reason = "SpawnUntilEvent"
-		cr.Status.Lifecycle.Message = "Assertion is not yet satisfied."
-
-		return true
+// group returns the job-group view of the cascade.
+func group(cr *v1alpha1.Cascade) jobgroup.Group {
+	return jobgroup.Group{
+		Object:           cr,
+		Lifecycle:        &cr.Status.Lifecycle,
+		Suspend:          &cr.Spec.Suspend,
+		SuspendWhen:      cr.Spec.SuspendWhen,
+		Schedule:         cr.Spec.Schedule,
+		MaxInstances:     cr.Spec.MaxInstances,
+		QueuedJobs:       len(cr.Status.QueuedJobs),
+		ScheduledJobs:    &cr.Status.ScheduledJobs,
+		LastScheduleTime: &cr.Status.LastScheduleTime,
+		ExpectedTimeline: cr.Status.ExpectedTimeline,
 	}
+}
 
-	// Step 4. Check if scheduling goes as expected.
-	totalJobs := len(cr.Status.QueuedJobs)
-
-	return lifecycle.GroupedJobs(totalJobs, r.view, &cr.Status.Lifecycle, nil)
+// updateLifecycle returns the update lifecycle of the cascade.
+func (r *Controller) updateLifecycle(cr *v1alpha1.Cascade) bool {
+	return jobgroup.UpdateLifecycle(r.view, group(cr))
 }
@@ -0,0 +1,187 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// CleanupGracePeriod is the time given to Chaos-Mesh for recovering a fault after its expiry. If the recovery
+	// is not reported by then, the fault is considered to have left residue in its targets.
+	CleanupGracePeriod = time.Minute
+
+	// CleanupProbeTimeout bounds the execution of the cleanup probe in a single target.
+	CleanupProbeTimeout = 30 * time.Second
+)
+
+// recordNotInjected is the phase of a Chaos-Mesh record, once the fault has been removed from the target.
+const recordNotInjected = "Not Injected"
+
+// cleanupVerified returns true if the FaultResidue condition has already been evaluated.
+func cleanupVerified(chaos *v1alpha1.Chaos) bool {
+	return meta.FindStatusCondition(chaos.Status.Conditions, v1alpha1.ConditionFaultResidue.String()) != nil
+}
+
+// verifyCleanup checks that the recovered faults have left no residue in their targets, first through the records
+// of Chaos-Mesh, and then through the cleanup probe, if any. The outcome is recorded as the FaultResidue condition,
+// and residue is announced by a warning event. Residue does not fail the chaos, as the fault itself has been
+// completed.
+func (r *Controller) verifyCleanup(ctx context.Context, chaos *v1alpha1.Chaos, faults []client.Object) {
+	reason, residue := v1alpha1.ReasonNoResidue, []string(nil)
+
+	for _, obj := range faults {
+		fault, ok := obj.(*GenericFault)
+		if !ok {
+			continue
+		}
+
+		residue = append(residue, unrecoveredRecords(fault)...)
+	}
+
+	if len(residue) > 0 {
+		reason = v1alpha1.ReasonUnrecoveredRecords
+	} else if chaos.Spec.CleanupProbe != nil {
+		residue = r.runCleanupProbe(ctx, chaos)
+
+		if len(residue) > 0 {
+			reason = v1alpha1.ReasonCleanupProbeFailed
+		}
+	}
+
+	r.setFaultResidue(chaos, reason, residue)
+}
+
+// reportRecoveryTimeout reports the faults whose recovery has not been reported within the grace period.
+func (r *Controller) reportRecoveryTimeout(chaos *v1alpha1.Chaos, faults []client.Object) {
+	residue := []string{fmt.Sprintf("recovery is not reported %s after the expiry of the fault", CleanupGracePeriod)}
+
+	for _, obj := range faults {
+		if fault, ok := obj.(*GenericFault); ok {
+			residue = append(residue, unrecoveredRecords(fault)...)
+		}
+	}
+
+	r.setFaultResidue(chaos, v1alpha1.ReasonRecoveryTimeout, residue)
+}
+
+// setFaultResidue records the outcome of the verification, and announces the residue, if any.
+func (r *Controller) setFaultResidue(chaos *v1alpha1.Chaos, reason string, residue []string) {
+	if len(residue) == 0 {
+		meta.SetStatusCondition(&chaos.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.ConditionFaultResidue.String(),
+			Status:  metav1.ConditionFalse,
+			Reason:  reason,
+			Message: "the fault has been removed from all targets",
+		})
+
+		return
+	}
+
+	msg := strings.Join(residue, "; ")
+
+	meta.SetStatusCondition(&chaos.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.ConditionFaultResidue.String(),
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: msg,
+	})
+
+	r.GetEventRecorderFor(chaos.GetName()).Event(chaos, corev1.EventTypeWarning, reason, msg)
+
+	r.Logger.Info("!! FaultResidue",
+		"obj", client.ObjectKeyFromObject(chaos).String(),
+		"reason", reason,
+		"residue", msg,
+	)
+}
+
+// unrecoveredRecords returns the targets of the fault that Chaos-Mesh has not reported as recovered.
+// Pod faults (e.g, pod-kill) have nothing to recover, and they are skipped.
+func unrecoveredRecords(fault *GenericFault) []string {
+	if fault.GroupVersionKind() == PodChaosGVK {
+		return nil
+	}
+
+	records, _, _ := unstructured.NestedSlice(fault.Object, "status", "experiment", "containerRecords")
+
+	var unrecovered []string
+
+	for _, raw := range records {
+		record, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		id, _, _ := unstructured.NestedString(record, "id")
+		phase, _, _ := unstructured.NestedString(record, "phase")
+		injected, _, _ := unstructured.NestedInt64(record, "injectedCount")
+		recovered, _, _ := unstructured.NestedInt64(record, "recoveredCount")
+
+		if phase != recordNotInjected || recovered < injected {
+			unrecovered = append(unrecovered, fmt.Sprintf("%s/%s: %s (injected:%d, recovered:%d)",
+				fault.GetKind(), id, phase, injected, recovered))
+		}
+	}
+
+	return unrecovered
+}
+
+// runCleanupProbe runs the cleanup probe in the targets of the fault, and returns the targets where it has failed.
+// Targets that no longer exist cannot have residue, and they are skipped.
+func (r *Controller) runCleanupProbe(ctx context.Context, chaos *v1alpha1.Chaos) []string {
+	probe := chaos.Spec.CleanupProbe
+
+	var fault GenericFault
+
+	if err := getRawManifest(chaos, &fault); err != nil {
+		return []string{fmt.Sprintf("cannot get manifest: %v", err)}
+	}
+
+	targets, err := r.listTargets(ctx, chaos, &fault)
+	if err != nil {
+		return []string{fmt.Sprintf("cannot find targets: %v", err)}
+	}
+
+	var failed []string
+
+	for _, pod := range targets {
+		if pod.GetDeletionTimestamp() != nil || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+
+		probeCtx, cancel := context.WithTimeout(ctx, CleanupProbeTimeout)
+		res, err := r.executor.Exec(probeCtx, client.ObjectKeyFromObject(&pod), probe.Container, probe.Command, false)
+		cancel()
+
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v %s", pod.GetName(), err, strings.TrimSpace(res.Stdout+res.Stderr)))
+		}
+	}
+
+	return failed
+}
@@ -24,10 +24,14 @@ import (
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/carv-ics-forth/frisbee/controllers/common"
 	"github.com/carv-ics-forth/frisbee/controllers/common/watchers"
+	"github.com/carv-ics-forth/frisbee/pkg/clock"
 	"github.com/carv-ics-forth/frisbee/pkg/grafana"
+	"github.com/carv-ics-forth/frisbee/pkg/kubexec"
 	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	"github.com/carv-ics-forth/frisbee/pkg/tracing"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -42,17 +46,31 @@ import (
 // +kubebuilder:rbac:groups=chaos-mesh.org,resources=*/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=chaos-mesh.org,resources=*/finalizers,verbs=update
 
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch
+
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get
+
+// ExpiryRetryInterval is the interval for re-evaluating a fault whose duration has expired, but whose recovery
+// is not yet reported by Chaos-Mesh.
+const ExpiryRetryInterval = 5 * time.Second
+
 // Controller reconciles a Reference object.
 type Controller struct {
 	ctrl.Manager
 	logr.Logger
+	clock.Clock
 
 	view *lifecycle.Classifier
+
+	executor kubexec.Executor
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracing.StartReconcile(ctx, "Chaos", req.NamespacedName)
+	defer span.End()
+
 	/*
 		1: Load CR by name and extract the Desired State
 		------------------------------------------------------------------
@@ -115,20 +133,53 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 		// Build the job in kubernetes
 		if err := r.runJob(ctx, &chaos); err != nil {
+			if errors.Is(err, errDisruptionBudget) {
+				return common.RequeueAfter(r, req, DisruptionBudgetRetryInterval)
+			}
+
 			return lifecycle.Failed(ctx, r, &chaos, errors.Wrapf(err, "chaos injection has failed"))
 		}
 
 		// Update the scheduling information
-		chaos.Status.LastScheduleTime = &metav1.Time{Time: time.Now()}
+		chaos.Status.LastScheduleTime = &metav1.Time{Time: r.Now()}
 
 		return lifecycle.Pending(ctx, r, &chaos, "injecting fault")
 
 	case v1alpha1.PhaseRunning:
-		// Nothing to do. Just wait for something to happen.
+		// Faults without duration are recovered by Chaos-Mesh. Just wait for something to happen.
+		if chaos.Status.ExpiresAt == nil {
+			return common.Stop(r, req)
+		}
 
-		return common.Stop(r, req)
+		// Come back when the fault is expected to expire.
+		if remaining := r.Until(chaos.Status.ExpiresAt.Time); remaining > 0 {
+			return common.RequeueAfter(r, req, remaining)
+		}
+
+		// The fault has expired, but the recovery is not yet reported. Once the grace period is over, the fault
+		// is reported as residue. Keep polling, as the recovery may still happen.
+		if !cleanupVerified(&chaos) && r.Since(chaos.Status.ExpiresAt.Time) > CleanupGracePeriod {
+			r.reportRecoveryTimeout(&chaos, r.view.GetRunningJobs())
+
+			if err := common.UpdateStatus(ctx, r, &chaos); err != nil {
+				return common.RequeueAfter(r, req, time.Second)
+			}
+		}
+
+		return common.RequeueAfter(r, req, ExpiryRetryInterval)
 
 	case v1alpha1.PhaseSuccess:
+		// Verify the recovery before the faults are deleted, along with their records. A late recovery is
+		// verified as well.
+		if cond := meta.FindStatusCondition(chaos.Status.Conditions, v1alpha1.ConditionFaultResidue.String()); cond == nil ||
+			cond.Reason == v1alpha1.ReasonRecoveryTimeout {
+			r.verifyCleanup(ctx, &chaos, r.view.GetSuccessfulJobs())
+
+			if err := common.UpdateStatus(ctx, r, &chaos); err != nil {
+				return common.RequeueAfter(r, req, time.Second)
+			}
+		}
+
 		r.HasSucceed(ctx, &chaos)
 
 		return common.Stop(r, req)
@@ -213,6 +264,19 @@ func (r *Controller) PopulateView(ctx context.Context, req types.NamespacedName)
 		}
 	}
 
+	var stressChaosList GenericFaultList
+
+	stressChaosList.SetGroupVersionKind(StressChaosGVK)
+	{
+		if err := common.ListChildren(ctx, r.GetClient(), &stressChaosList, req); err != nil {
+			return errors.Wrapf(err, "cannot list children for '%s'", req)
+		}
+
+		for i, job := range stressChaosList.Items {
+			r.view.ClassifyExternal(job.GetName(), &stressChaosList.Items[i], convertChaosLifecycle)
+		}
+	}
+
 	return nil
 }
 
@@ -271,11 +335,13 @@ func (r *Controller) Finalize(obj client.Object) error {
 	deleted, etc.
 */
 
-func NewController(mgr ctrl.Manager, logger logr.Logger) error {
+func NewController(mgr ctrl.Manager, logger logr.Logger, clk clock.Clock) error {
 	controller := &Controller{
-		Manager: mgr,
-		Logger:  logger.WithName("chaos"),
-		view:    &lifecycle.Classifier{},
+		Manager:  mgr,
+		Logger:   logger.WithName("chaos"),
+		Clock:    clk,
+		view:     &lifecycle.Classifier{},
+		executor: kubexec.NewExecutor(mgr.GetConfig()),
 	}
 
 	gvk := v1alpha1.GroupVersion.WithKind("Chaos")
@@ -287,6 +353,7 @@ func NewController(mgr ctrl.Manager, logger logr.Logger) error {
 		ioChaos     GenericFault
 		kernelChaos GenericFault
 		timeChaos   GenericFault
+		stressChaos GenericFault
 	)
 
 	networkChaos.SetGroupVersionKind(NetworkChaosGVK)
@@ -295,6 +362,7 @@ func NewController(mgr ctrl.Manager, logger logr.Logger) error {
 	ioChaos.SetGroupVersionKind(IOChaosGVK)
 	kernelChaos.SetGroupVersionKind(KernelChaosGVK)
 	timeChaos.SetGroupVersionKind(TimeChaosGVK)
+	stressChaos.SetGroupVersionKind(StressChaosGVK)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.Chaos{}).
@@ -304,6 +372,7 @@ func NewController(mgr ctrl.Manager, logger logr.Logger) error {
 		// Owns(&blockChaos, builder.WithPredicates(controller.Watchers())).
 		Owns(&ioChaos, watchers.WatchWithRangeAnnotations(controller, gvk, grafana.TagChaos)).
 		Owns(&kernelChaos, watchers.WatchWithPointAnnotation(controller, gvk, grafana.TagChaos)).
-		Owns(&timeChaos, watchers.WatchWithPointAnnotation(controller, gvk, grafana.TagChaos)).
+		Owns(&timeChaos, watchers.WatchWithRangeAnnotations(controller, gvk, grafana.TagChaos, grafana.TagClockSkew)).
+		Owns(&stressChaos, watchers.WatchWithRangeAnnotations(controller, gvk, grafana.TagChaos)).
 		Complete(controller)
 }
@@ -0,0 +1,189 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DisruptionBudgetRetryInterval is the interval for re-evaluating a fault that is blocked by a disruption budget.
+const DisruptionBudgetRetryInterval = 5 * time.Second
+
+// errDisruptionBudget indicates that the injection is deferred in order to respect a disruption budget.
+var errDisruptionBudget = errors.New("disruption budget is exhausted")
+
+// checkDisruptionBudgets applies the disruption policy of the chaos to the fault.
+func (r *Controller) checkDisruptionBudgets(ctx context.Context, chaos *v1alpha1.Chaos, fault *GenericFault) error {
+	if chaos.Spec.DisruptionPolicy == "" {
+		return nil
+	}
+
+	violations, err := r.budgetViolations(ctx, chaos, fault)
+	if err != nil {
+		return errors.Wrapf(err, "cannot evaluate disruption budgets")
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	msg := strings.Join(violations, ", ")
+
+	switch chaos.Spec.DisruptionPolicy {
+	case v1alpha1.DisruptionPolicyRespect:
+		r.GetEventRecorderFor(chaos.GetName()).Event(chaos, corev1.EventTypeNormal, "DisruptionBudget",
+			"Injection is deferred by "+msg)
+
+		return errDisruptionBudget
+
+	case v1alpha1.DisruptionPolicyViolate:
+		r.GetEventRecorderFor(chaos.GetName()).Event(chaos, corev1.EventTypeWarning, "DisruptionBudgetViolated", msg)
+
+		return nil
+	}
+
+	return errors.Errorf("unknown disruption policy '%s'", chaos.Spec.DisruptionPolicy)
+}
+
+// budgetViolations returns the disruption budgets that would be violated by the injection of the fault.
+func (r *Controller) budgetViolations(ctx context.Context, chaos *v1alpha1.Chaos, fault *GenericFault) ([]string, error) {
+	targets, err := r.listTargets(ctx, chaos, fault)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot find targets")
+	}
+
+	if len(targets) == 0 {
+		return nil, nil
+	}
+
+	// The number of targets that will be disrupted.
+	disrupted := len(targets)
+
+	switch mode, _, _ := unstructured.NestedString(fault.Object, "spec", "mode"); mode {
+	case "one":
+		disrupted = 1
+	case "fixed":
+		value, _, _ := unstructured.NestedString(fault.Object, "spec", "value")
+		if fixed, err := strconv.Atoi(value); err == nil && fixed < disrupted {
+			disrupted = fixed
+		}
+	}
+
+	var budgets policyv1.PodDisruptionBudgetList
+
+	if err := r.GetClient().List(ctx, &budgets, client.InNamespace(chaos.GetNamespace())); err != nil {
+		return nil, errors.Wrapf(err, "cannot list disruption budgets")
+	}
+
+	var violations []string
+
+	for _, pdb := range budgets.Items {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid selector in disruption budget '%s'", pdb.GetName())
+		}
+
+		covered := 0
+
+		for _, pod := range targets {
+			if pod.GetNamespace() == pdb.GetNamespace() && selector.Matches(labels.Set(pod.GetLabels())) {
+				covered++
+			}
+		}
+
+		if covered > disrupted {
+			covered = disrupted
+		}
+
+		if covered > int(pdb.Status.DisruptionsAllowed) {
+			violations = append(violations, fmt.Sprintf("%s (disrupted:%d, allowed:%d)",
+				pdb.GetName(), covered, pdb.Status.DisruptionsAllowed))
+		}
+	}
+
+	return violations, nil
+}
+
+// listTargets returns the pods that match the selector of the fault.
+func (r *Controller) listTargets(ctx context.Context, chaos *v1alpha1.Chaos, fault *GenericFault) ([]corev1.Pod, error) {
+	var targets []corev1.Pod
+
+	// Case 1: pods are explicitly given as {namespace: [names]}
+	podsByNamespace, _, _ := unstructured.NestedMap(fault.Object, "spec", "selector", "pods")
+
+	for namespace, names := range podsByNamespace {
+		podNames, ok := names.([]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, name := range podNames {
+			var pod corev1.Pod
+
+			key := client.ObjectKey{Namespace: namespace, Name: fmt.Sprint(name)}
+
+			if err := r.GetClient().Get(ctx, key, &pod); err != nil {
+				if client.IgnoreNotFound(err) == nil {
+					continue
+				}
+
+				return nil, errors.Wrapf(err, "cannot get pod '%s'", key)
+			}
+
+			targets = append(targets, pod)
+		}
+	}
+
+	// Case 2: pods are selected by labels
+	labelSelectors, found, _ := unstructured.NestedStringMap(fault.Object, "spec", "selector", "labelSelectors")
+	if !found {
+		return targets, nil
+	}
+
+	namespaces, _, _ := unstructured.NestedStringSlice(fault.Object, "spec", "selector", "namespaces")
+	if len(namespaces) == 0 {
+		namespaces = []string{chaos.GetNamespace()}
+	}
+
+	for _, namespace := range namespaces {
+		var pods corev1.PodList
+
+		if err := r.GetClient().List(ctx, &pods,
+			client.InNamespace(namespace),
+			client.MatchingLabels(labelSelectors),
+		); err != nil {
+			return nil, errors.Wrapf(err, "cannot list pods")
+		}
+
+		targets = append(targets, pods.Items...)
+	}
+
+	return targets, nil
+}
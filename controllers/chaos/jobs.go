@@ -18,10 +18,13 @@ package chaos
 
 import (
 	"context"
+	"time"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/carv-ics-forth/frisbee/controllers/common"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 )
 
@@ -32,6 +35,15 @@ func (r *Controller) runJob(ctx context.Context, chaos *v1alpha1.Chaos) error {
 		return errors.Wrapf(err, "cannot get manifest for chaos '%s'", chaos.GetName())
 	}
 
+	duration, err := faultDuration(&fault)
+	if err != nil {
+		return errors.Wrapf(err, "invalid duration for chaos '%s'", chaos.GetName())
+	}
+
+	if err := r.checkDisruptionBudgets(ctx, chaos, &fault); err != nil {
+		return err
+	}
+
 	fault.SetLabels(labels.Merge(fault.GetLabels(), chaos.GetLabels()))
 	fault.SetAnnotations(labels.Merge(fault.GetAnnotations(), chaos.GetAnnotations()))
 
@@ -39,5 +51,26 @@ func (r *Controller) runJob(ctx context.Context, chaos *v1alpha1.Chaos) error {
 		return errors.Wrapf(err, "failed to inject chaos type '%s'", chaos.Kind)
 	}
 
+	// Rather than waiting for the fault to complete, record when it expires and requeue the request at that time.
+	if duration > 0 {
+		chaos.Status.ExpiresAt = &metav1.Time{Time: r.Now().Add(duration)}
+	}
+
 	return nil
 }
+
+// faultDuration returns the duration of the fault, as defined in the Chaos-Mesh manifest.
+// If the fault has no duration, it returns zero.
+func faultDuration(fault *GenericFault) (time.Duration, error) {
+	raw, found, err := unstructured.NestedString(fault.Object, "spec", "duration")
+	if err != nil || !found {
+		return 0, err
+	}
+
+	duration, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, errors.Wrapf(err, "cannot parse duration '%s'", raw)
+	}
+
+	return duration, nil
+}
@@ -27,6 +27,39 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// Reasons for the lifecycle of the chaos experiment, as translated from the conditions of Chaos-Mesh.
+const (
+	// ReasonInteroperability indicates that the status of the chaos experiment cannot be parsed.
+	ReasonInteroperability = "Interoperability"
+
+	// ReasonChaosStarted indicates that the chaos experiment has just started, and it has no conditions yet.
+	ReasonChaosStarted = "ChaosStarted"
+
+	// ReasonUnsupportedAction indicates that the chaos experiment is in a state that is not supported (e.g, paused).
+	ReasonUnsupportedAction = "UnsupportedAction"
+
+	// ReasonChaosRestarting indicates that the chaos experiment starts from a clean slate.
+	ReasonChaosRestarting = "ChaosReStarting"
+
+	// ReasonChaosSelectingTargets indicates that the chaos experiment selects the target pods.
+	ReasonChaosSelectingTargets = "ChaosSelectingTargets"
+
+	// ReasonChaosInjecting indicates that the faults are being injected into the targets.
+	ReasonChaosInjecting = "ChaosInjecting"
+
+	// ReasonChaosRunning indicates that the faults have been injected into all targets.
+	ReasonChaosRunning = "ChaosRunning"
+
+	// ReasonChaosTearingDown indicates that the faults are being removed from the targets.
+	ReasonChaosTearingDown = "ChaosTearingDown"
+
+	// ReasonChaosFinished indicates that the faults have been removed from all targets.
+	ReasonChaosFinished = "ChaosFinished"
+
+	// ReasonTargetNotFound indicates that the chaos experiment has stopped without selecting any target.
+	ReasonTargetNotFound = "TargetNotFound"
+)
+
 func (r *Controller) updateLifecycle(chaos *v1alpha1.Chaos) bool {
 	// Skip any CR which are already completed, or uninitialized.
 	if chaos.Status.Phase.Is(v1alpha1.PhaseUninitialized, v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed) {
@@ -129,7 +162,7 @@ func convertChaosLifecycle(obj client.Object) v1alpha1.Lifecycle {
 	if err := mapstructure.Decode(obj.(*GenericFault).Object["status"], &parsed); err != nil {
 		return v1alpha1.Lifecycle{
 			Phase:   v1alpha1.PhaseFailed,
-			Reason:  "Interoperability",
+			Reason:  ReasonInteroperability,
 			Message: "cannot parse chaos message",
 		}
 	}
@@ -137,7 +170,7 @@ func convertChaosLifecycle(obj client.Object) v1alpha1.Lifecycle {
 	if parsed.Conditions == nil {
 		return v1alpha1.Lifecycle{
 			Phase:   v1alpha1.PhasePending,
-			Reason:  "ChaosStarted",
+			Reason:  ReasonChaosStarted,
 			Message: "Chaos experiment has just started.",
 		}
 	}
@@ -158,7 +191,7 @@ func convertChaosLifecycle(obj client.Object) v1alpha1.Lifecycle {
 			expression: paused.True(),
 			lifecycle: v1alpha1.Lifecycle{
 				Phase:   v1alpha1.PhaseFailed,
-				Reason:  "UnsupportedAction",
+				Reason:  ReasonUnsupportedAction,
 				Message: "chaos pausing is not yet supported",
 			},
 		},
@@ -167,7 +200,7 @@ func convertChaosLifecycle(obj client.Object) v1alpha1.Lifecycle {
 			expression: selected.False() && allInjected.False() && allRecovered.False(),
 			lifecycle: v1alpha1.Lifecycle{
 				Phase:   v1alpha1.PhasePending,
-				Reason:  "ChaosReStarting",
+				Reason:  ReasonChaosRestarting,
 				Message: "Re-starting Chaos from clean slate",
 			},
 		},
@@ -176,7 +209,7 @@ func convertChaosLifecycle(obj client.Object) v1alpha1.Lifecycle {
 			expression: phase.Run() && selected.False() && allInjected.True() && allRecovered.True(),
 			lifecycle: v1alpha1.Lifecycle{
 				Phase:   v1alpha1.PhasePending,
-				Reason:  "ChaosSelectingTargets",
+				Reason:  ReasonChaosSelectingTargets,
 				Message: "Selecting the target pods",
 			},
 		},
@@ -185,7 +218,7 @@ func convertChaosLifecycle(obj client.Object) v1alpha1.Lifecycle {
 			expression: phase.Run() && selected.True() && allInjected.False() && allRecovered.False(),
 			lifecycle: v1alpha1.Lifecycle{
 				Phase:   v1alpha1.PhasePending,
-				Reason:  "ChaosInjecting",
+				Reason:  ReasonChaosInjecting,
 				Message: "Chaos experiment is in the process of fault injection.",
 			},
 		},
@@ -196,7 +229,7 @@ func convertChaosLifecycle(obj client.Object) v1alpha1.Lifecycle {
 			expression: phase.Run() && selected.True() && allInjected.False() && allRecovered.True(),
 			lifecycle: v1alpha1.Lifecycle{
 				Phase:   v1alpha1.PhasePending,
-				Reason:  "ChaosInjecting",
+				Reason:  ReasonChaosInjecting,
 				Message: "Chaos experiment is in the process of fault injection.",
 			},
 		},
@@ -205,7 +238,7 @@ func convertChaosLifecycle(obj client.Object) v1alpha1.Lifecycle {
 			expression: phase.Run() && selected.True() && allInjected.True() && allRecovered.False(),
 			lifecycle: v1alpha1.Lifecycle{
 				Phase:   v1alpha1.PhaseRunning,
-				Reason:  "ChaosRunning",
+				Reason:  ReasonChaosRunning,
 				Message: "The faults have been successfully injected into all target pods",
 			},
 		},
@@ -214,7 +247,7 @@ func convertChaosLifecycle(obj client.Object) v1alpha1.Lifecycle {
 			expression: phase.Stop() && selected.True() && allInjected.True() && allRecovered.False(),
 			lifecycle: v1alpha1.Lifecycle{
 				Phase:   v1alpha1.PhaseRunning,
-				Reason:  "ChaosTearingDown",
+				Reason:  ReasonChaosTearingDown,
 				Message: "removing all the injected faults",
 			},
 		},
@@ -223,7 +256,7 @@ func convertChaosLifecycle(obj client.Object) v1alpha1.Lifecycle {
 			expression: phase.Stop() && selected.True() && allInjected.False() && allRecovered.False(),
 			lifecycle: v1alpha1.Lifecycle{
 				Phase:   v1alpha1.PhaseRunning,
-				Reason:  "ChaosTearingDown",
+				Reason:  ReasonChaosTearingDown,
 				Message: "all faults are removed",
 			},
 		},
@@ -232,7 +265,7 @@ func convertChaosLifecycle(obj client.Object) v1alpha1.Lifecycle {
 			expression: phase.Stop() && selected.True() && allInjected.False() && allRecovered.True(),
 			lifecycle: v1alpha1.Lifecycle{
 				Phase:   v1alpha1.PhaseSuccess,
-				Reason:  "ChaosFinished",
+				Reason:  ReasonChaosFinished,
 				Message: "all faults are recovered",
 			},
 		},
@@ -241,7 +274,7 @@ func convertChaosLifecycle(obj client.Object) v1alpha1.Lifecycle {
 			expression: phase.Stop() && selected.False() && allInjected.True() && allRecovered.True(),
 			lifecycle: v1alpha1.Lifecycle{
 				Phase:   v1alpha1.PhaseFailed,
-				Reason:  "TargetNotFound",
+				Reason:  ReasonTargetNotFound,
 				Message: fmt.Sprintf("%v", parsed),
 			},
 		},
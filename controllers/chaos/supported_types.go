@@ -88,6 +88,12 @@ var (
 		Version: "v1alpha1",
 		Kind:    "TimeChaos",
 	}
+
+	StressChaosGVK = schema.GroupVersionKind{
+		Group:   "chaos-mesh.org",
+		Version: "v1alpha1",
+		Kind:    "StressChaos",
+	}
 )
 
 func getRawManifest(chaos *v1alpha1.Chaos, f *GenericFault) error {
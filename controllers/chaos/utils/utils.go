@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	templateutils "github.com/carv-ics-forth/frisbee/controllers/template/utils"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/json"
@@ -43,15 +44,14 @@ func GetChaosSpecList(ctx context.Context, cli client.Client, parent metav1.Obje
 	/*
 		Get Chaos Templates
 	*/
-	var template v1alpha1.Template
-
 	key := client.ObjectKey{
-		Namespace: parent.GetNamespace(),
+		Namespace: v1alpha1.GetScenarioNamespace(parent),
 		Name:      fromTemplate.TemplateRef,
 	}
 
-	if err := cli.Get(ctx, key, &template); err != nil {
-		return []v1alpha1.ChaosSpec{}, errors.Wrapf(err, "cannot find template '%s'", key.String())
+	template, err := templateutils.GetTemplate(ctx, cli, key)
+	if err != nil {
+		return []v1alpha1.ChaosSpec{}, errors.Wrapf(err, "cannot get template")
 	}
 
 	/*
@@ -73,6 +73,15 @@ func GetChaosSpecList(ctx context.Context, cli client.Client, parent metav1.Obje
 	template.Spec.Inputs.Scenario = v1alpha1.GetScenarioLabel(parent)
 	template.Spec.Inputs.Namespace = parent.GetNamespace()
 
+	if err := templateutils.SetScenarioVars(ctx, cli, parent, &template.Spec); err != nil {
+		return nil, errors.Wrapf(err, "cannot set vars of '%s'", fromTemplate.TemplateRef)
+	}
+
+	// fetch the values that are kept in external stores
+	if err := templateutils.ResolveExternalInputs(ctx, &template.Spec, &fromTemplate); err != nil {
+		return nil, errors.Wrapf(err, "cannot resolve external inputs of '%s'", fromTemplate.TemplateRef)
+	}
+
 	/*
 		Generate Chaos Specs using the expanded inputs
 	*/
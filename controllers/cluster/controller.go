@@ -18,22 +18,19 @@ package cluster
 
 import (
 	"context"
-	"fmt"
 	"reflect"
 	"time"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/controllers/common/jobgroup"
 	"github.com/carv-ics-forth/frisbee/controllers/common/watchers"
+	"github.com/carv-ics-forth/frisbee/pkg/clock"
 	"github.com/carv-ics-forth/frisbee/pkg/distributions"
-	"github.com/carv-ics-forth/frisbee/pkg/expressions"
 	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
-	"github.com/carv-ics-forth/frisbee/pkg/scheduler"
+	"github.com/carv-ics-forth/frisbee/pkg/tracing"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -42,17 +39,30 @@ import (
 // +kubebuilder:rbac:groups=frisbee.dev,resources=clusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=frisbee.dev,resources=clusters/finalizers,verbs=update
 
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+
 // Controller reconciles a Cluster object.
 type Controller struct {
 	ctrl.Manager
 	logr.Logger
+	clock.Clock
 
 	view *lifecycle.Classifier
+
+	// children are the services of the cluster, as listed by the view. Unlike the view, they include the services
+	// that are just created and are not yet initialized.
+	children []client.Object
+
+	retries retryView
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current view of the cluster closer to the desired view.
 func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracing.StartReconcile(ctx, "Cluster", req.NamespacedName)
+	defer span.End()
+
 	/*
 		1: Load CR by name and extract the Desired State
 		------------------------------------------------------------------
@@ -84,7 +94,7 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		2: Load CR's children and classify their current state (view)
 		------------------------------------------------------------------
 	*/
-	if err := r.PopulateView(ctx, req.NamespacedName); err != nil {
+	if err := r.PopulateView(ctx, &cluster); err != nil {
 		return lifecycle.Failed(ctx, r, &cluster, errors.Wrapf(err, "cannot populate view for '%s'", req))
 	}
 
@@ -107,7 +117,7 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		------------------------------------------------------------------
 	*/
 
-	if cluster.Spec.Suspend != nil && *cluster.Spec.Suspend {
+	if group(&cluster).IsSuspended() {
 		// If this object is suspended, we don't want to run any jobs, so we'll stop now.
 		// This is useful if something's broken with the job we're running, and we want to
 		// pause runs to investigate the cluster, without deleting the object.
@@ -116,7 +126,11 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return common.Stop(r, req)
 	}
 
-	log := r.Logger.WithValues("object", client.ObjectKeyFromObject(&cluster))
+	// Recreate the failed services, as long as the retry policy allows it.
+	nextRetry, err := r.retryFailedJobs(ctx, &cluster)
+	if err != nil {
+		return lifecycle.Failed(ctx, r, &cluster, errors.Wrapf(err, "retry error"))
+	}
 
 	switch cluster.Status.Phase {
 	case v1alpha1.PhaseUninitialized:
@@ -127,64 +141,40 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return lifecycle.Pending(ctx, r, &cluster, "ready to start creating jobs.")
 
 	case v1alpha1.PhasePending:
-		//	If all jobs are scheduled but are not in the Running phase, they may be in the Pending phase.
-		//	In both cases, we have nothing else to do but waiting for the next reconciliation cycle.
-		if r.view.Count() >= len(cluster.Status.QueuedJobs) {
-			r.Logger.Info("All jobs have been scheduled. Nothing else to do. ")
-
-			return common.Stop(r, req)
-		}
+		// Delay the creation of services, as long as the creation rate is above the limit.
+		if delay, changed := r.throttle(&cluster); delay > 0 {
+			if changed {
+				if err := common.UpdateStatus(ctx, r, &cluster); err != nil {
+					return common.RequeueAfter(r, req, time.Second)
+				}
+			}
 
-		// Check if the conditions are right to spawn a new job.
-		hasJob, nextTick, err := scheduler.Schedule(log, &cluster, scheduler.Parameters{
-			State:            *r.view,
-			ScheduleSpec:     cluster.Spec.Schedule,
-			LastScheduleTime: cluster.Status.LastScheduleTime,
-			ExpectedTimeline: cluster.Status.ExpectedTimeline,
-			JobName:          cluster.GetName(),
-			ScheduledJobs:    cluster.Status.ScheduledJobs,
-		})
-		if err != nil {
-			return lifecycle.Failed(ctx, r, &cluster, errors.Wrapf(err, "scheduling error"))
-		}
+			r.Logger.Info("Throttle creation", "obj", client.ObjectKeyFromObject(&cluster), "delay", delay)
 
-		if !hasJob {
-			// nothing to schedule
-			if nextTick.IsZero() {
-				return common.Stop(r, req)
+			// wake up for the next retry, unless the throttling is over earlier.
+			if nextRetry > 0 && nextRetry < delay {
+				delay = nextRetry
 			}
 
-			// sleep until next tick
-			return common.RequeueAfter(r, req, time.Until(nextTick))
+			return common.RequeueAfter(r, req, delay)
 		}
 
-		// Fetch the next job from the queuing list, and submit it to Kubernetes.
-		nextJobIndex := cluster.Status.ScheduledJobs + 1
-
-		if nextJobIndex >= len(cluster.Status.QueuedJobs) {
-			r.Logger.Error(errors.New("Ignore job as it is out of range compared to QueuedJobs"),
-				"BadScheduling",
-				"nextJobIndex", nextJobIndex,
-				"queueJobs", len(cluster.Status.QueuedJobs),
-				"viewedJobs", r.view.Count(),
-				"jobList", r.view.ListAll(),
-			)
-
-			return common.Stop(r, req)
-		}
+		result, err := jobgroup.ScheduleNext(ctx, r, req, r.view, group(&cluster), func(ctx context.Context, jobIndex int) error {
+			return r.runJob(ctx, &cluster, jobIndex)
+		})
 
-		if err := r.runJob(ctx, &cluster, nextJobIndex); err != nil {
-			return lifecycle.Failed(ctx, r, &cluster, errors.Wrapf(err, "cannot create job"))
+		// wake up for the next retry, unless the scheduling wakes up earlier.
+		if nextRetry > 0 && err == nil && (result.RequeueAfter == 0 || nextRetry < result.RequeueAfter) {
+			result.RequeueAfter = nextRetry
 		}
 
-		// Update the scheduling information
-		cluster.Status.ScheduledJobs = nextJobIndex
-		cluster.Status.LastScheduleTime = metav1.Time{Time: time.Now()}
-
-		return lifecycle.Pending(ctx, r, &cluster, fmt.Sprintf("Scheduled jobs: '%d/%d'",
-			cluster.Status.ScheduledJobs+1, cluster.Spec.MaxInstances))
+		return result, err
 
 	case v1alpha1.PhaseRunning:
+		if nextRetry > 0 {
+			return common.RequeueAfter(r, req, nextRetry)
+		}
+
 		// Nothing to do. Just wait for something to happen.
 		return common.Stop(r, req)
 
@@ -207,6 +197,23 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 }
 
 func (r *Controller) Initialize(ctx context.Context, cluster *v1alpha1.Cluster) error {
+	/*
+		with variants or traces, the instances of the cluster are given by the variants or the traces.
+	*/
+	templates, trace, err := r.getTemplates(ctx, cluster)
+	if err != nil {
+		return errors.Wrapf(err, "cannot get templates")
+	}
+
+	if trace != nil {
+		cluster.Status.ExpectedTimeline = trace.Timeline(cluster.GetCreationTimestamp())
+	}
+
+	// traces loaded from ConfigMaps are not known to the admission webhook.
+	if err := cluster.ValidateSize(); err != nil {
+		return errors.Wrapf(err, "size error")
+	}
+
 	/*
 		calculate any top-level distribution. this distribution will be respected during the construction of the jobs.
 	*/
@@ -215,49 +222,54 @@ func (r *Controller) Initialize(ctx context.Context, cluster *v1alpha1.Cluster)
 	}
 
 	/*
-		We construct a list of job specifications based on the CR's template.
-		This list is used by the execution step to create the actual job.
-		If the template is invalid, it should be captured at this stage.
+		We construct a list of jobs based on the CR's template.
+		This list is used by the execution step to construct the actual job.
 	*/
-	jobList, err := r.buildJobQueue(ctx, cluster)
-	if err != nil {
+	if err := r.buildJobQueue(ctx, cluster, templates); err != nil {
 		return errors.Wrapf(err, "building joblist")
 	}
 
-	cluster.Status.QueuedJobs = jobList
-	cluster.Status.ScheduledJobs = -1
+	/*
+		If the template is invalid, it should be captured at this stage.
+	*/
+	specs, err := r.constructDistinctJobs(ctx, cluster, templates)
+	if err != nil {
+		return errors.Wrapf(err, "constructing jobs")
+	}
 
-	// Metrics-driven execution requires to set alerts on Grafana.
-	if until := cluster.Spec.SuspendWhen; until != nil && until.HasMetricsExpr() {
-		if err := expressions.SetAlert(ctx, cluster, until.Metrics); err != nil {
-			return errors.Wrapf(err, "spec.suspendWhen")
-		}
+	if err := r.createDisruptionBudget(ctx, cluster); err != nil {
+		return errors.Wrapf(err, "spec.disruptionBudget")
 	}
 
-	if schedule := cluster.Spec.Schedule; schedule != nil && schedule.Event.HasMetricsExpr() {
-		if err := expressions.SetAlert(ctx, cluster, schedule.Event.Metrics); err != nil {
-			return errors.Wrapf(err, "spec.schedule")
-		}
+	if err := r.createGroupService(ctx, cluster, specs); err != nil {
+		return errors.Wrapf(err, "spec.groupService")
 	}
 
-	if _, err := lifecycle.Pending(ctx, r, cluster, "submitting job requests"); err != nil {
-		return errors.Wrapf(err, "status update")
+	if err := jobgroup.Initialize(ctx, r, group(cluster)); err != nil {
+		return errors.Wrapf(err, "initialize group")
 	}
 
 	return nil
 }
 
-func (r *Controller) PopulateView(ctx context.Context, req types.NamespacedName) error {
+func (r *Controller) PopulateView(ctx context.Context, cluster *v1alpha1.Cluster) error {
 	r.view.Reset()
 
+	req := client.ObjectKeyFromObject(cluster)
+
 	var serviceJobs v1alpha1.ServiceList
 	{
 		if err := common.ListChildren(ctx, r.GetClient(), &serviceJobs, req); err != nil {
 			return errors.Wrapf(err, "cannot list children for '%s'", req)
 		}
 
-		for i, job := range serviceJobs.Items {
-			r.view.Classify(job.GetName(), &serviceJobs.Items[i])
+		// the failed services that are covered by the retry policy are classified as pending.
+		r.classifyRetries(cluster, serviceJobs.Items)
+
+		r.children = r.children[:0]
+
+		for i := range serviceJobs.Items {
+			r.children = append(r.children, &serviceJobs.Items[i])
 		}
 	}
 
@@ -265,56 +277,11 @@ func (r *Controller) PopulateView(ctx context.Context, req types.NamespacedName)
 }
 
 func (r *Controller) HasSucceed(ctx context.Context, cluster *v1alpha1.Cluster) error {
-	r.Logger.Info("CleanOnSuccess",
-		"obj", client.ObjectKeyFromObject(cluster).String(),
-		"successfulJobs", r.view.ListSuccessfulJobs(),
-	)
-
-	/*
-		Remove cr children once the cr is successfully complete.
-		We should not remove the cr descriptor itself, as we need to maintain its
-		status for higher-entities like the Scenario.
-	*/
-	for _, job := range r.view.GetSuccessfulJobs() {
-		common.Delete(ctx, r, job)
-	}
-
-	return nil
+	return jobgroup.HasSucceed(ctx, r, r.view, group(cluster))
 }
 
 func (r *Controller) HasFailed(ctx context.Context, cluster *v1alpha1.Cluster) error {
-	r.Logger.Info("!! JobError",
-		"obj", client.ObjectKeyFromObject(cluster).String(),
-		"reason ", cluster.Status.Reason,
-		"message", cluster.Status.Message,
-	)
-
-	// Remove the non-failed components. Leave the failed jobs and system jobs for postmortem analysis.
-	for _, job := range r.view.GetPendingJobs() {
-		common.Delete(ctx, r, job)
-	}
-
-	for _, job := range r.view.GetRunningJobs() {
-		common.Delete(ctx, r, job)
-	}
-
-	// Block from creating further jobs
-	suspend := true
-	cluster.Spec.Suspend = &suspend
-
-	r.Logger.Info("Suspended",
-		"obj", client.ObjectKeyFromObject(cluster),
-		"reason", cluster.Status.Reason,
-		"message", cluster.Status.Message,
-	)
-
-	if cluster.GetDeletionTimestamp().IsZero() {
-		r.GetEventRecorderFor(cluster.GetName()).Event(cluster, corev1.EventTypeNormal,
-			"Suspended", cluster.Status.Lifecycle.Message)
-	}
-
-	// Update is needed since we modify the spec.suspend
-	return common.Update(ctx, r, cluster)
+	return jobgroup.HasFailed(ctx, r, r.view, group(cluster))
 }
 
 /*
@@ -349,10 +316,11 @@ func (r *Controller) Finalize(obj client.Object) error {
 	deleted, etc.
 */
 
-func NewController(mgr ctrl.Manager, logger logr.Logger) error {
+func NewController(mgr ctrl.Manager, logger logr.Logger, clk clock.Clock) error {
 	controller := &Controller{
 		Manager: mgr,
 		Logger:  logger.WithName("cluster"),
+		Clock:   clk,
 		view:    &lifecycle.Classifier{},
 	}
 
@@ -18,13 +18,20 @@ package cluster
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	clusterutils "github.com/carv-ics-forth/frisbee/controllers/cluster/utils"
 	"github.com/carv-ics-forth/frisbee/controllers/common"
 	serviceutils "github.com/carv-ics-forth/frisbee/controllers/service/utils"
+	templateutils "github.com/carv-ics-forth/frisbee/controllers/template/utils"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func (r *Controller) runJob(ctx context.Context, cluster *v1alpha1.Cluster, jobIndex int) error {
@@ -34,10 +41,12 @@ func (r *Controller) runJob(ctx context.Context, cluster *v1alpha1.Cluster, jobI
 	job.SetName(common.GenerateName(cluster, jobIndex))
 	v1alpha1.PropagateLabels(&job, cluster)
 
-	// modulo is needed to re-iterate the job list, required for the implementation of "Until".
-	jobSpec := cluster.Status.QueuedJobs[jobIndex%len(cluster.Status.QueuedJobs)]
+	jobSpec, err := r.constructJob(ctx, cluster, jobIndex)
+	if err != nil {
+		return errors.Wrapf(err, "cannot construct job '%d'", jobIndex)
+	}
 
-	jobSpec.DeepCopyInto(&job.Spec)
+	job.Spec = jobSpec
 
 	serviceutils.AttachTestDataVolume(&job, cluster.Spec.TestData, true)
 
@@ -50,18 +59,274 @@ func (r *Controller) runJob(ctx context.Context, cluster *v1alpha1.Cluster, jobI
 	return nil
 }
 
-// buildJobQueue creates a list of job templates that will be scheduled throughout execution.
-func (r *Controller) buildJobQueue(ctx context.Context, cluster *v1alpha1.Cluster) ([]v1alpha1.ServiceSpec, error) {
-	serviceSpecs, err := serviceutils.GetServiceSpecList(ctx, r.GetClient(), cluster, cluster.Spec.GenerateObjectFromTemplate)
+// buildJobQueue creates a list of jobs that will be scheduled throughout execution. The jobs point to the templates
+// that generate them, and are constructed on demand (see constructJob).
+func (r *Controller) buildJobQueue(ctx context.Context, cluster *v1alpha1.Cluster, templates []v1alpha1.GenerateObjectFromTemplate) error {
+	cluster.Status.QueuedJobs = clusterutils.MixVariants(templates)
+
+	hash, err := r.templateHash(ctx, cluster, templates)
 	if err != nil {
-		return nil, errors.Wrapf(err, "cannot get serviceSpecs")
+		return errors.Wrapf(err, "cannot hash templates")
 	}
 
-	clusterutils.SetPlacement(cluster, serviceSpecs)
-
-	clusterutils.SetResources(cluster, serviceSpecs)
+	cluster.Status.TemplateHash = hash
 
 	clusterutils.SetTimeline(cluster)
 
-	return serviceSpecs, nil
+	return nil
+}
+
+// constructJob generates the spec of the job found at the given index of the queue.
+func (r *Controller) constructJob(ctx context.Context, cluster *v1alpha1.Cluster, jobIndex int) (v1alpha1.ServiceSpec, error) {
+	templates, _, err := r.getTemplates(ctx, cluster)
+	if err != nil {
+		return v1alpha1.ServiceSpec{}, errors.Wrapf(err, "cannot get templates")
+	}
+
+	// the job must be generated as it would have been generated during the initialization.
+	hash, err := r.templateHash(ctx, cluster, templates)
+	if err != nil {
+		return v1alpha1.ServiceSpec{}, errors.Wrapf(err, "cannot hash templates")
+	}
+
+	if hash != cluster.Status.TemplateHash {
+		return v1alpha1.ServiceSpec{}, errors.Errorf("templates have changed since the initialization of the cluster")
+	}
+
+	// modulo is needed to re-iterate the job list, required for the implementation of "Until".
+	total := len(cluster.Status.QueuedJobs)
+	position := jobIndex % total
+	queued := cluster.Status.QueuedJobs[position]
+
+	if queued.Variant >= len(templates) {
+		return v1alpha1.ServiceSpec{}, errors.Errorf("variant '%d' is out of range", queued.Variant)
+	}
+
+	spec, err := serviceutils.GetServiceSpec(ctx, r.GetClient(), cluster, templates[queued.Variant].Select(queued.Inputs))
+	if err != nil {
+		return v1alpha1.ServiceSpec{}, errors.Wrapf(err, "variant '%d'", queued.Variant)
+	}
+
+	clusterutils.SetPlacement(cluster, &spec)
+
+	clusterutils.SetTopology(cluster, position, &spec)
+
+	clusterutils.SetResources(cluster, position, total, &spec)
+
+	clusterutils.SetLifetime(cluster, position, total, &spec)
+
+	return spec, nil
+}
+
+// constructDistinctJobs generates the spec of every distinct combination of template and inputs in the queue.
+// Jobs that share the combination differ only in their placement and resources.
+func (r *Controller) constructDistinctJobs(ctx context.Context, cluster *v1alpha1.Cluster, templates []v1alpha1.GenerateObjectFromTemplate) ([]v1alpha1.ServiceSpec, error) {
+	var specs []v1alpha1.ServiceSpec
+
+	constructed := make(map[v1alpha1.QueuedJob]bool)
+
+	for _, queued := range cluster.Status.QueuedJobs {
+		if constructed[queued] {
+			continue
+		}
+
+		constructed[queued] = true
+
+		spec, err := serviceutils.GetServiceSpec(ctx, r.GetClient(), cluster, templates[queued.Variant].Select(queued.Inputs))
+		if err != nil {
+			return nil, errors.Wrapf(err, "variant '%d'", queued.Variant)
+		}
+
+		specs = append(specs, spec)
+	}
+
+	return specs, nil
+}
+
+// getTemplates returns the templates that generate the jobs, one per variant. Without variants, the jobs are
+// generated by the template of the cluster. The instances of the cluster are set to the instances of the variants,
+// or to the records of the trace. The trace, if any, is returned as well.
+func (r *Controller) getTemplates(ctx context.Context, cluster *v1alpha1.Cluster) ([]v1alpha1.GenerateObjectFromTemplate, v1alpha1.Trace, error) {
+	/*
+		with traces, the instances of the cluster, and their inputs, are given by the records of the trace.
+	*/
+	var trace v1alpha1.Trace
+
+	if schedule := cluster.Spec.Schedule; schedule != nil && schedule.Trace != nil {
+		loaded, err := r.loadTrace(ctx, cluster)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "spec.schedule.trace")
+		}
+
+		trace = loaded
+
+		cluster.Spec.MaxInstances = len(trace)
+
+		if trace.HasInputs() {
+			cluster.Spec.Inputs = trace.Inputs()
+		}
+	}
+
+	if len(cluster.Spec.Variants) == 0 {
+		return []v1alpha1.GenerateObjectFromTemplate{cluster.Spec.GenerateObjectFromTemplate}, trace, nil
+	}
+
+	/*
+		with variants, the instances of the cluster may be given by the instances of the variants.
+	*/
+	templates, err := cluster.Spec.VariantTemplates()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "invalid variants")
+	}
+
+	cluster.Spec.MaxInstances = 0
+
+	for _, template := range templates {
+		cluster.Spec.MaxInstances += template.MaxInstances
+	}
+
+	return templates, trace, nil
+}
+
+// templateHash identifies the given templates, along with the versions of the referenced Template objects.
+func (r *Controller) templateHash(ctx context.Context, cluster *v1alpha1.Cluster, templates []v1alpha1.GenerateObjectFromTemplate) (string, error) {
+	versions := make(map[string]string)
+
+	for _, template := range templates {
+		key := client.ObjectKey{Namespace: v1alpha1.GetScenarioNamespace(cluster), Name: template.TemplateRef}
+
+		resolved, err := templateutils.GetTemplate(ctx, r.GetClient(), key)
+		if err != nil {
+			return "", errors.Wrapf(err, "cannot get template")
+		}
+
+		versions[template.TemplateRef] = resolved.GetResourceVersion()
+	}
+
+	encoded, err := json.Marshal(struct {
+		Templates []v1alpha1.GenerateObjectFromTemplate `json:"templates"`
+		Versions  map[string]string                     `json:"versions"`
+	}{
+		Templates: templates,
+		Versions:  versions,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot encode templates")
+	}
+
+	sum := sha256.Sum256(encoded)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// loadTrace parses the trace of the cluster, either from the spec, or from the referenced ConfigMap.
+func (r *Controller) loadTrace(ctx context.Context, cluster *v1alpha1.Cluster) (v1alpha1.Trace, error) {
+	spec := cluster.Spec.Schedule.Trace
+
+	data := spec.Data
+
+	if ref := spec.ConfigMapRef; ref != nil {
+		var configMap corev1.ConfigMap
+
+		key := client.ObjectKey{Namespace: v1alpha1.GetScenarioNamespace(cluster), Name: ref.Name}
+
+		if err := r.GetClient().Get(ctx, key, &configMap); err != nil {
+			return nil, errors.Wrapf(err, "configmap '%s' is missing", key)
+		}
+
+		value, exists := configMap.Data[ref.Key]
+		if !exists {
+			return nil, errors.Errorf("configmap '%s' has no key '%s'", key, ref.Key)
+		}
+
+		data = value
+	}
+
+	trace, err := v1alpha1.ParseTrace(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid trace")
+	}
+
+	return trace, nil
+}
+
+// createDisruptionBudget protects the Services of the cluster from voluntary disruptions.
+func (r *Controller) createDisruptionBudget(ctx context.Context, cluster *v1alpha1.Cluster) error {
+	budget := cluster.Spec.DisruptionBudget
+	if budget == nil {
+		return nil
+	}
+
+	var pdb policyv1.PodDisruptionBudget
+
+	pdb.SetName(cluster.GetName())
+	v1alpha1.PropagateLabels(&pdb, cluster)
+
+	pdb.Spec.MinAvailable = budget.MinAvailable
+	pdb.Spec.MaxUnavailable = budget.MaxUnavailable
+
+	// Services inherit the action of the cluster (see placement).
+	pdb.Spec.Selector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{v1alpha1.LabelAction: cluster.GetName()},
+	}
+
+	if err := common.Create(ctx, r, cluster, &pdb); err != nil {
+		return errors.Wrapf(err, "cannot create disruption budget")
+	}
+
+	return nil
+}
+
+// createGroupService exposes all the Services of the cluster under a single name.
+// The given specs must cover every distinct combination of template and inputs in the queue.
+func (r *Controller) createGroupService(ctx context.Context, cluster *v1alpha1.Cluster, specs []v1alpha1.ServiceSpec) error {
+	if !cluster.Spec.GroupService || len(specs) == 0 {
+		return nil
+	}
+
+	// The Services of a cluster expose the same ports, unless they are generated by different variants.
+	// In that case, the group service exposes the ports of all the variants.
+	var allPorts []corev1.ServicePort
+
+	exposed := make(map[corev1.ServicePort]bool)
+
+	for _, spec := range specs {
+		for _, container := range spec.Containers {
+			for _, port := range container.Ports {
+				servicePort := corev1.ServicePort{
+					Name: port.Name,
+					Port: port.ContainerPort,
+				}
+
+				if !exposed[servicePort] {
+					exposed[servicePort] = true
+
+					allPorts = append(allPorts, servicePort)
+				}
+			}
+		}
+	}
+
+	var k8sService corev1.Service
+
+	k8sService.SetName(cluster.GetName())
+	v1alpha1.PropagateLabels(&k8sService, cluster)
+
+	k8sService.Spec.Ports = allPorts
+
+	// Without ports, a headless service resolves to the addresses of all the pods (DNS round-robin).
+	if len(allPorts) == 0 {
+		k8sService.Spec.ClusterIP = corev1.ClusterIPNone
+	}
+
+	// Services inherit the action of the cluster (see placement).
+	k8sService.Spec.Selector = map[string]string{
+		v1alpha1.LabelAction: cluster.GetName(),
+	}
+
+	if err := common.Create(ctx, r, cluster, &k8sService); err != nil {
+		return errors.Wrapf(err, "cannot create group service")
+	}
+
+	return nil
 }
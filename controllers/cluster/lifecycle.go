@@ -18,12 +18,14 @@ package cluster
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
-	"github.com/carv-ics-forth/frisbee/pkg/expressions"
-	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	"github.com/carv-ics-forth/frisbee/controllers/common/jobgroup"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // updateLifecycle returns the update lifecycle of the cluster.
@@ -33,72 +35,73 @@ func (r *Controller) updateLifecycle(cr *v1alpha1.Cluster) bool {
 		return false
 	}
 
-	// Step 3. Check if "SuspendWhen" conditions are met.
-	if !cr.Spec.SuspendWhen.IsZero() {
-		if meta.IsStatusConditionTrue(cr.Status.Conditions, v1alpha1.ConditionAllJobsAreScheduled.String()) {
-			// The Until condition is already handled, and we are in the Running Phase.
-			// From now on, the lifecycle depends on the progress of the already scheduled jobs.
-			totalJobs := cr.Status.ScheduledJobs + 1
+	// Step 2. Summarize the jobs per failure domain, and check whether failures are confined to the tolerated zones.
+	perZone := r.view.PerDomain(serviceZone)
+	zonesChanged := !reflect.DeepEqual(perZone, cr.Status.PerZone)
+	cr.Status.PerZone = perZone
 
-			return lifecycle.GroupedJobs(totalJobs, r.view, &cr.Status.Lifecycle, cr.Spec.Tolerate)
-		}
-
-		eval := expressions.Condition{Expr: cr.Spec.SuspendWhen}
-		if eval.IsTrue(r.view, cr) {
-			cr.Status.Lifecycle.Phase = v1alpha1.PhaseRunning
-			cr.Status.Lifecycle.Reason = "UntilCondition"
-			cr.Status.Lifecycle.Message = eval.Info
-
-			meta.SetStatusCondition(&cr.Status.Lifecycle.Conditions, metav1.Condition{
-				Type:    v1alpha1.ConditionAllJobsAreScheduled.String(),
-				Status:  metav1.ConditionTrue,
-				Reason:  "UntilCondition",
-				Message: eval.Info,
-			})
-
-			// prevent the parent from spawning new jobs.
-			suspend := true
-			cr.Spec.Suspend = &suspend
+	if tolerate := cr.Spec.Tolerate; tolerate != nil && tolerate.FailedZones > 0 {
+		var failedZones []string
 
-			return true
+		for zone, status := range perZone {
+			if status.FailedJobs > 0 {
+				failedZones = append(failedZones, zone)
+			}
 		}
 
-		// Event used in conjunction with "Until", instance act as a maximum bound.
-		// If the maximum instances are reached before the Until conditions, we assume that
-		// the experiment never converges, and it fails.
-		maxJobs := cr.Spec.MaxInstances
+		if len(failedZones) > tolerate.FailedZones {
+			sort.Strings(failedZones)
 
-		if maxJobs > 0 && (cr.Status.ScheduledJobs > maxJobs) {
-			msg := fmt.Sprintf(`Resource [%s] has reached Max instances [%d] before Until conditions are met.
-			Abort the experiment as it too flaky to accept. You can retry without defining instances.`,
-				cr.GetName(), maxJobs)
+			msg := fmt.Sprintf("tolerate: %d zones. failed zones: %d (%v)",
+				tolerate.FailedZones, len(failedZones), failedZones)
 
 			cr.Status.Lifecycle.Phase = v1alpha1.PhaseFailed
-			cr.Status.Lifecycle.Reason = "MaxInstancesReached"
+			cr.Status.Lifecycle.Reason = v1alpha1.ReasonTooManyFailedZones
 			cr.Status.Lifecycle.Message = msg
 
 			meta.SetStatusCondition(&cr.Status.Lifecycle.Conditions, metav1.Condition{
 				Type:    v1alpha1.ConditionJobUnexpectedTermination.String(),
 				Status:  metav1.ConditionTrue,
-				Reason:  "MaxInstancesReached",
+				Reason:  v1alpha1.ReasonTooManyFailedZones,
 				Message: msg,
 			})
 
 			return true
 		}
+	}
 
-		// A side effect of "Until" is that queued jobs will be reused,
-		// until the conditions are met. In that sense, they resemble mostly a pool of jobs
-		// rather than e queue.
-		cr.Status.Lifecycle.Phase = v1alpha1.PhasePending
-		cr.Status.Lifecycle.Reason = "SpawnUntilEvent"
-		cr.Status.Lifecycle.Message = "Assertion is not yet satisfied."
+	// Step 3. Calculate the progress metrics.
+	progressChanged := r.updateProgress(cr)
 
-		return true
+	// Step 4. Calculate the creation rate.
+	rateChanged := r.updateCreationRate(cr)
+
+	return jobgroup.UpdateLifecycle(r.view, group(cr)) || zonesChanged || progressChanged || rateChanged
+}
+
+// serviceZone returns the failure domain of a service.
+func serviceZone(job client.Object) string {
+	if service, ok := job.(*v1alpha1.Service); ok && service.Status.Zone != "" {
+		return service.Status.Zone
 	}
 
-	// Step 4. Check if scheduling goes as expected.
-	totalJobs := len(cr.Status.QueuedJobs)
+	return v1alpha1.UnknownZone
+}
 
-	return lifecycle.GroupedJobs(totalJobs, r.view, &cr.Status.Lifecycle, cr.Spec.Tolerate)
+// group returns the job-group view of the cluster.
+func group(cr *v1alpha1.Cluster) jobgroup.Group {
+	return jobgroup.Group{
+		Object:            cr,
+		Lifecycle:         &cr.Status.Lifecycle,
+		Suspend:           &cr.Spec.Suspend,
+		SuspendWhen:       cr.Spec.SuspendWhen,
+		Schedule:          cr.Spec.Schedule,
+		Tolerate:          cr.Spec.Tolerate,
+		MaxConcurrentJobs: cr.Spec.MaxConcurrentJobs,
+		MaxInstances:      cr.Spec.MaxInstances,
+		QueuedJobs:        len(cr.Status.QueuedJobs),
+		ScheduledJobs:     &cr.Status.ScheduledJobs,
+		LastScheduleTime:  &cr.Status.LastScheduleTime,
+		ExpectedTimeline:  cr.Status.ExpectedTimeline,
+	}
 }
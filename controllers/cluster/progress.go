@@ -0,0 +1,109 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/scheduler"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// updateProgress calculates the progress metrics of the cluster. It returns true if the status has been changed.
+func (r *Controller) updateProgress(cr *v1alpha1.Cluster) bool {
+	totalJobs := len(cr.Status.QueuedJobs)
+
+	// With "Until", the queued jobs are a pool. The total is known once the condition is met.
+	if !cr.Spec.SuspendWhen.IsZero() && meta.IsStatusConditionTrue(cr.Status.Conditions, v1alpha1.ConditionAllJobsAreScheduled.String()) {
+		totalJobs = cr.Status.ScheduledJobs + 1
+	}
+
+	if totalJobs == 0 {
+		return false
+	}
+
+	scheduledJobs := cr.Status.ScheduledJobs + 1
+
+	// percentage of completed jobs
+	percent := (r.view.NumSuccessfulJobs() + r.view.NumFailedJobs()) * 100 / totalJobs
+	if percent > 100 {
+		percent = 100
+	}
+
+	// scheduled jobs per minute, until the last scheduled job. The metrics depend only on the scheduled jobs, and
+	// not on the current time. Otherwise, every status update would trigger another reconciliation.
+	var rate float64
+
+	lastSchedule := cr.Status.LastScheduleTime.Time
+
+	if elapsed := lastSchedule.Sub(cr.GetCreationTimestamp().Time).Minutes(); !lastSchedule.IsZero() && elapsed > 0 && scheduledJobs > 0 {
+		rate = float64(scheduledJobs) / elapsed
+	}
+
+	// expected time for scheduling the last job
+	var eta time.Time
+
+	remainingJobs := totalJobs - scheduledJobs
+
+	switch {
+	case remainingJobs <= 0:
+		eta = cr.Status.LastScheduleTime.Time
+
+	default:
+		expected, err := scheduler.ExpectedCompletion(cr, scheduler.Parameters{
+			ScheduleSpec:     cr.Spec.Schedule,
+			LastScheduleTime: cr.Status.LastScheduleTime,
+			ExpectedTimeline: cr.Status.ExpectedTimeline,
+			QueuedJobs:       len(cr.Status.QueuedJobs),
+		}, remainingJobs)
+		if err != nil {
+			r.Logger.Info("Cannot estimate completion time", "obj", cr.GetName(), "err", err)
+		}
+
+		eta = expected
+
+		if eta.IsZero() && rate > 0 {
+			eta = lastSchedule.Add(time.Duration(float64(remainingJobs) / rate * float64(time.Minute)))
+		}
+	}
+
+	changed := false
+
+	if cr.Status.PercentComplete != percent {
+		cr.Status.PercentComplete = percent
+		changed = true
+	}
+
+	if formatted := fmt.Sprintf("%.2f", rate); cr.Status.SchedulingRate != formatted {
+		cr.Status.SchedulingRate = formatted
+		changed = true
+	}
+
+	if !eta.IsZero() {
+		expected := metav1.NewTime(eta.Truncate(time.Second))
+
+		if cr.Status.ExpectedCompletionTime == nil || !cr.Status.ExpectedCompletionTime.Equal(&expected) {
+			cr.Status.ExpectedCompletionTime = &expected
+			changed = true
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/clock"
+	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+// TestUpdateLifecycle_Stable checks that reconciliations without changes in the jobs do not update the status.
+// Otherwise, every status update would trigger another reconciliation.
+func TestUpdateLifecycle_Stable(t *testing.T) {
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	fakeClock := clocktesting.NewFakePassiveClock(created.Add(2 * time.Minute))
+
+	r := &Controller{
+		Logger: logr.Discard(),
+		Clock:  clock.FromPassive(fakeClock),
+		view:   &lifecycle.Classifier{},
+	}
+
+	var cluster v1alpha1.Cluster
+
+	cluster.SetName("clients")
+	cluster.SetCreationTimestamp(metav1.NewTime(created))
+	cluster.Status.Lifecycle.Phase = v1alpha1.PhaseRunning
+	cluster.Status.QueuedJobs = make([]v1alpha1.QueuedJob, 4)
+	cluster.Status.ScheduledJobs = 1
+	cluster.Status.LastScheduleTime = metav1.NewTime(created.Add(time.Minute))
+
+	for _, name := range []string{"clients-1", "clients-2"} {
+		var job v1alpha1.Service
+
+		job.SetName(name)
+		v1alpha1.SetComponentLabel(&job.ObjectMeta, v1alpha1.ComponentSUT)
+		job.SetCreationTimestamp(metav1.NewTime(created))
+		job.Status.Lifecycle.Phase = v1alpha1.PhaseRunning
+
+		r.view.Classify(name, &job)
+	}
+
+	r.updateLifecycle(&cluster)
+
+	// two jobs within a minute. The remaining two are expected within another minute.
+	if got, want := cluster.Status.SchedulingRate, "2.00"; got != want {
+		t.Errorf("SchedulingRate = %s, want %s", got, want)
+	}
+
+	if got, want := cluster.Status.ExpectedCompletionTime, created.Add(2*time.Minute); got == nil || !got.Time.Equal(want) {
+		t.Errorf("ExpectedCompletionTime = %v, want %v", got, want)
+	}
+
+	for i := 0; i < 3; i++ {
+		fakeClock.SetTime(fakeClock.Now().Add(17 * time.Second))
+
+		if r.updateLifecycle(&cluster) {
+			t.Fatalf("reconciliation %d at %s changed the status without changes in the jobs", i, fakeClock.Now())
+		}
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	clusterutils "github.com/carv-ics-forth/frisbee/controllers/cluster/utils"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// retryView keeps the services that are handled by the retry policy, as found by PopulateView.
+type retryView struct {
+	// failed are the failed services that must be deleted, before being recreated.
+	failed []*v1alpha1.Service
+
+	// deleted are the services that are deleted, and wait to be recreated.
+	deleted []string
+}
+
+func (in *retryView) reset() {
+	in.failed = in.failed[:0]
+	in.deleted = in.deleted[:0]
+}
+
+// retryingLifecycle hides the failure of the services that are to be recreated.
+func retryingLifecycle(client.Object) v1alpha1.Lifecycle {
+	return v1alpha1.Lifecycle{Phase: v1alpha1.PhasePending}
+}
+
+// classifyRetries accounts the services that are handled by the retry policy as pending, so that their failures
+// do not count against the cluster. The deleted services are accounted via placeholders, until they are recreated.
+func (r *Controller) classifyRetries(cluster *v1alpha1.Cluster, services []v1alpha1.Service) {
+	r.retries.reset()
+
+	listed := make(map[string]bool, len(services))
+
+	for i := range services {
+		job := &services[i]
+		listed[job.GetName()] = true
+
+		if !clusterutils.IsRetrying(cluster, job) {
+			r.view.Classify(job.GetName(), job)
+
+			continue
+		}
+
+		r.view.ClassifyExternal(job.GetName(), job, retryingLifecycle)
+
+		if job.GetDeletionTimestamp() == nil {
+			r.retries.failed = append(r.retries.failed, job)
+		}
+	}
+
+	for name, retry := range cluster.Status.Retries {
+		if retry.RecreateAfter == nil || listed[name] {
+			continue
+		}
+
+		var placeholder v1alpha1.Service
+
+		placeholder.SetName(name)
+		placeholder.SetNamespace(cluster.GetNamespace())
+
+		r.view.ClassifyExternal(name, &placeholder, retryingLifecycle)
+
+		r.retries.deleted = append(r.retries.deleted, name)
+	}
+}
+
+// retryFailedJobs deletes the failed services that are covered by the retry policy, and recreates them once their
+// backoff expires. It returns the delay until the next recreation, if any.
+func (r *Controller) retryFailedJobs(ctx context.Context, cluster *v1alpha1.Cluster) (time.Duration, error) {
+	policy := cluster.Spec.RetryPolicy
+
+	if policy == nil || !cluster.Status.Phase.Is(v1alpha1.PhasePending, v1alpha1.PhaseRunning) {
+		return 0, nil
+	}
+
+	now := r.Now()
+
+	/*
+		1. Journal the retries of the newly failed services, and then delete them.
+		------------------------------------------------------------------
+		Services that are already journaled (e.g, due to a conflict in the previous cycle),
+		are deleted without consuming another retry.
+	*/
+	if len(r.retries.failed) > 0 {
+		if cluster.Status.Retries == nil {
+			cluster.Status.Retries = make(map[string]v1alpha1.JobRetry)
+		}
+
+		for _, job := range r.retries.failed {
+			retry := cluster.Status.Retries[job.GetName()]
+
+			if retry.RecreateAfter != nil {
+				continue
+			}
+
+			retry.Count++
+			delay := policy.Delay(retry.Count)
+			recreateAfter := metav1.NewTime(now.Add(delay))
+			retry.RecreateAfter = &recreateAfter
+
+			cluster.Status.Retries[job.GetName()] = retry
+
+			r.GetEventRecorderFor(cluster.GetName()).Event(cluster, corev1.EventTypeWarning, "Retry",
+				fmt.Sprintf("service '%s' failed (%s). Retry %d/%d in %s",
+					job.GetName(), job.Status.Reason, retry.Count, policy.MaxRetries, delay))
+		}
+
+		if err := common.UpdateStatus(ctx, r, cluster); err != nil {
+			// due to the multiple updates, it is possible for this function to be in conflict.
+			return time.Second, nil
+		}
+
+		for _, job := range r.retries.failed {
+			common.Delete(ctx, r, job)
+		}
+	}
+
+	/*
+		2. Recreate the deleted services, once their backoff is expired.
+		------------------------------------------------------------------
+	*/
+	var nextRetry time.Duration
+
+	recreated := false
+
+	for _, name := range r.retries.deleted {
+		retry := cluster.Status.Retries[name]
+
+		if wait := retry.RecreateAfter.Sub(now); wait > 0 {
+			if nextRetry == 0 || wait < nextRetry {
+				nextRetry = wait
+			}
+
+			continue
+		}
+
+		jobIndex, err := clusterutils.JobIndex(cluster, name)
+		if err != nil {
+			return 0, errors.Wrapf(err, "cannot recreate job")
+		}
+
+		if err := r.runJob(ctx, cluster, jobIndex); err != nil {
+			return 0, errors.Wrapf(err, "cannot recreate job '%s'", name)
+		}
+
+		retry.RecreateAfter = nil
+		cluster.Status.Retries[name] = retry
+
+		recreated = true
+	}
+
+	if recreated {
+		if err := common.UpdateStatus(ctx, r, cluster); err != nil {
+			return time.Second, nil
+		}
+	}
+
+	return nextRetry, nil
+}
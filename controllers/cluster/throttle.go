@@ -0,0 +1,86 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	clusterutils "github.com/carv-ics-forth/frisbee/controllers/cluster/utils"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// minThrottleDelay bounds the requeue of a throttled cluster, because the creation timestamps have a granularity
+// of a second.
+const minThrottleDelay = time.Second
+
+// updateCreationRate reports the number of services created within the last minute.
+// It returns true if the status has been changed.
+func (r *Controller) updateCreationRate(cr *v1alpha1.Cluster) bool {
+	count, _ := clusterutils.RecentCreations(r.children, r.Now())
+
+	if cr.Status.CreationsLastMinute == count {
+		return false
+	}
+
+	cr.Status.CreationsLastMinute = count
+
+	return true
+}
+
+// throttle returns how long to wait before creating the next service, if the creation rate has reached the
+// limit of the cluster. The backpressure is recorded in the conditions of the cluster. It also returns true if
+// the conditions have been changed.
+func (r *Controller) throttle(cr *v1alpha1.Cluster) (time.Duration, bool) {
+	limit := cr.Spec.Placement.GetMaxCreationsPerMinute()
+
+	count, release := clusterutils.RecentCreations(r.children, r.Now())
+
+	// nothing is throttled if there is no limit, or if there are no further jobs to create.
+	if limit == 0 || count < limit || r.view.Count() >= len(cr.Status.QueuedJobs) {
+		if !meta.IsStatusConditionTrue(cr.Status.Conditions, v1alpha1.ConditionCreationThrottled.String()) {
+			return 0, false
+		}
+
+		meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.ConditionCreationThrottled.String(),
+			Status:  metav1.ConditionFalse,
+			Reason:  v1alpha1.ReasonWithinCreationRate,
+			Message: fmt.Sprintf("created services within the last minute: %d", count),
+		})
+
+		return 0, true
+	}
+
+	changed := !meta.IsStatusConditionTrue(cr.Status.Conditions, v1alpha1.ConditionCreationThrottled.String())
+
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.ConditionCreationThrottled.String(),
+		Status:  metav1.ConditionTrue,
+		Reason:  v1alpha1.ReasonCreationRateExceeded,
+		Message: fmt.Sprintf("created services within the last minute: %d. limit: %d", count, limit),
+	})
+
+	delay := r.Until(release)
+	if delay < minThrottleDelay {
+		delay = minThrottleDelay
+	}
+
+	return delay, changed
+}
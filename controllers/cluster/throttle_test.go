@@ -0,0 +1,103 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/clock"
+	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeManager serves the client of the controller.
+type fakeManager struct {
+	ctrl.Manager
+
+	cli client.Client
+}
+
+func (m *fakeManager) GetClient() client.Client { return m.cli }
+
+// TestThrottle_Burst checks that the services that are just created, and are not yet initialized, count against
+// the creation rate.
+func TestThrottle_Burst(t *testing.T) {
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var cluster v1alpha1.Cluster
+
+	cluster.SetNamespace("default")
+	cluster.SetName("clients")
+	cluster.Spec.Placement = &v1alpha1.PlacementSpec{MaxCreationsPerMinute: 3}
+	cluster.Status.Lifecycle.Phase = v1alpha1.PhasePending
+	cluster.Status.QueuedJobs = make([]v1alpha1.QueuedJob, 10)
+
+	// a burst of creations within a few seconds. None of the services has been initialized yet.
+	var objs []client.Object
+
+	for i := 0; i < 3; i++ {
+		var job v1alpha1.Service
+
+		job.SetNamespace("default")
+		job.SetName(fmt.Sprintf("clients-%d", i+1))
+		job.SetCreationTimestamp(metav1.NewTime(now.Add(time.Duration(i-3) * time.Second)))
+		v1alpha1.SetCreatedByLabel(&job, &cluster)
+		v1alpha1.SetComponentLabel(&job.ObjectMeta, v1alpha1.ComponentSUT)
+
+		objs = append(objs, &job)
+	}
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+
+	r := &Controller{
+		Manager: &fakeManager{cli: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()},
+		Logger:  logr.Discard(),
+		Clock:   clock.FromPassive(clocktesting.NewFakePassiveClock(now)),
+		view:    &lifecycle.Classifier{},
+	}
+
+	if err := r.PopulateView(context.Background(), &cluster); err != nil {
+		t.Fatalf("PopulateView() error = %v", err)
+	}
+
+	if r.updateCreationRate(&cluster); cluster.Status.CreationsLastMinute != 3 {
+		t.Errorf("CreationsLastMinute = %d, want 3", cluster.Status.CreationsLastMinute)
+	}
+
+	// the earliest service leaves the window a minute after its creation.
+	delay, changed := r.throttle(&cluster)
+	if want := time.Minute - 3*time.Second; delay != want {
+		t.Errorf("throttle() delay = %s, want %s", delay, want)
+	}
+
+	if !changed || !meta.IsStatusConditionTrue(cluster.Status.Conditions, v1alpha1.ConditionCreationThrottled.String()) {
+		t.Errorf("throttle() did not set the %s condition", v1alpha1.ConditionCreationThrottled)
+	}
+}
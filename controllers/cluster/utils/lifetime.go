@@ -0,0 +1,41 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"hash/fnv"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/distributions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SetLifetime assigns to the service the lifetime that corresponds to its position in the queue. The lifetimes
+// are seeded by the name of the cluster, so that repeated runs of the same scenario terminate the same services
+// at the same time.
+func SetLifetime(cluster *v1alpha1.Cluster, position int, total int, service *v1alpha1.ServiceSpec) {
+	if cluster.Spec.Lifetime == nil {
+		return
+	}
+
+	seed := fnv.New64a()
+	_, _ = seed.Write([]byte(cluster.GetName()))
+
+	lifetimes := distributions.GenerateLifetimes(total, cluster.Spec.Lifetime, int64(seed.Sum64()))
+
+	service.Lifetime = &metav1.Duration{Duration: lifetimes[position]}
+}
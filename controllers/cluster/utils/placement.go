@@ -22,7 +22,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func SetPlacement(cluster *v1alpha1.Cluster, services []v1alpha1.ServiceSpec) {
+// SetPlacement applies the affinity rules of the cluster to the service.
+func SetPlacement(cluster *v1alpha1.Cluster, service *v1alpha1.ServiceSpec) {
 	if cluster.Spec.Placement == nil {
 		return
 	}
@@ -100,9 +101,22 @@ func SetPlacement(cluster *v1alpha1.Cluster, services []v1alpha1.ServiceSpec) {
 		}
 	}
 
-	// apply affinity rules to all specs
-	for i := 0; i < len(services); i++ {
-		// Apply the current rules.
-		services[i].Affinity = &affinity
+	service.Affinity = &affinity
+}
+
+// SetTopology pins the service to the node selector that corresponds to its position in the queue.
+func SetTopology(cluster *v1alpha1.Cluster, position int, service *v1alpha1.ServiceSpec) {
+	if cluster.Spec.Topology == nil || len(cluster.Spec.Topology.NodeSelectors) == 0 {
+		return
+	}
+
+	selectors := cluster.Spec.Topology.NodeSelectors
+
+	if service.NodeSelector == nil {
+		service.NodeSelector = make(map[string]string)
+	}
+
+	for key, value := range selectors[position%len(selectors)] {
+		service.NodeSelector[key] = value
 	}
 }
@@ -21,29 +21,33 @@ import (
 	"github.com/carv-ics-forth/frisbee/pkg/distributions"
 )
 
-func SetResources(cluster *v1alpha1.Cluster, services []v1alpha1.ServiceSpec) {
+// SetResources assigns to the service its share of the total resources, given its position in the queue
+// and the total number of services.
+func SetResources(cluster *v1alpha1.Cluster, position int, total int, service *v1alpha1.ServiceSpec) {
 	if cluster.Spec.Resources == nil {
 		return
 	}
 
 	var generator distributions.ProbabilitySlice
 
-	// Default distributions means loads the evaluated distribution from the status of the resource.
-	if cluster.Spec.Resources.DistributionSpec.Name == v1alpha1.DistributionDefault {
+	switch spec := cluster.Spec.Resources.DistributionSpec; {
+	case spec == nil:
+		// Without distribution, the resources are split by the weights (or evenly, if no weights are given).
+		generator = distributions.GenerateProbabilitySliceFromWeights(int64(total), cluster.Spec.Resources.Weights)
+	case spec.Name == v1alpha1.DistributionDefault:
+		// Default distributions means loads the evaluated distribution from the status of the resource.
 		generator = cluster.Status.DefaultDistribution
-	} else {
-		generator = distributions.GenerateProbabilitySliceFromSpec(int64(cluster.Spec.MaxInstances), cluster.Spec.Resources.DistributionSpec)
+	default:
+		generator = distributions.GenerateProbabilitySliceFromSpec(int64(total), spec)
 	}
 
 	resources := generator.ApplyToResources(cluster.Spec.Resources.TotalResources)
 
-	// apply the resource distribution to the Main container of each pod.
-	for i := range services {
-		for ci, c := range services[i].Containers {
-			if c.Name == v1alpha1.MainContainerName {
-				services[i].Containers[ci].Resources.Requests = resources[i]
-				services[i].Containers[ci].Resources.Limits = resources[i]
-			}
+	// apply the resource distribution to the Main container of the pod.
+	for ci, c := range service.Containers {
+		if c.Name == v1alpha1.MainContainerName {
+			service.Containers[ci].Resources.Requests = resources[position]
+			service.Containers[ci].Resources.Limits = resources[position]
 		}
 	}
 }
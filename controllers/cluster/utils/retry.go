@@ -0,0 +1,70 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/service"
+	"github.com/pkg/errors"
+)
+
+// ReasonEvicted is the reason of the Pods that are evicted by the kubelet.
+const ReasonEvicted = "Evicted"
+
+// FailureCondition returns the class of the failure of the service, as understood by the retry policy.
+func FailureCondition(job *v1alpha1.Service) v1alpha1.RetryCondition {
+	switch job.Status.Reason {
+	case ReasonEvicted, service.ReasonPodDeletion:
+		return v1alpha1.RetryOnEvicted
+	default:
+		return v1alpha1.RetryOnFailed
+	}
+}
+
+// IsRetrying returns true if the service has failed, but it is (or will be) recreated by the retry policy
+// of the cluster. Such services are not counted as failures.
+func IsRetrying(cluster *v1alpha1.Cluster, job *v1alpha1.Service) bool {
+	policy := cluster.Spec.RetryPolicy
+
+	if policy == nil || !job.Status.Phase.Is(v1alpha1.PhaseFailed) {
+		return false
+	}
+
+	retry := cluster.Status.Retries[job.GetName()]
+
+	// the retry has been already accounted, and the service is being deleted.
+	if retry.RecreateAfter != nil {
+		return true
+	}
+
+	return retry.Count < policy.MaxRetries && policy.Covers(FailureCondition(job))
+}
+
+// JobIndex returns the index of the job from which the service has been generated (see common.GenerateName).
+func JobIndex(cluster *v1alpha1.Cluster, name string) (int, error) {
+	suffix := strings.TrimPrefix(name, cluster.GetName()+"-")
+
+	index, err := strconv.Atoi(suffix)
+	if err != nil || suffix == name || index < 1 {
+		return 0, errors.Errorf("'%s' is not a job of cluster '%s'", name, cluster.GetName())
+	}
+
+	return index - 1, nil
+}
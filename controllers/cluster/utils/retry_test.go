@@ -0,0 +1,132 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	clusterutils "github.com/carv-ics-forth/frisbee/controllers/cluster/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func failedService(name string, reason string) *v1alpha1.Service {
+	var service v1alpha1.Service
+
+	service.SetName(name)
+	service.Status.Phase = v1alpha1.PhaseFailed
+	service.Status.Reason = reason
+
+	return &service
+}
+
+func TestIsRetrying(t *testing.T) {
+	recreateAfter := metav1.Now()
+
+	tests := []struct {
+		name    string
+		policy  *v1alpha1.RetryPolicySpec
+		retries map[string]v1alpha1.JobRetry
+		service *v1alpha1.Service
+		want    bool
+	}{
+		{
+			name:    "no policy",
+			service: failedService("clients-1", "Error"),
+			want:    false,
+		},
+		{
+			name:    "within budget",
+			policy:  &v1alpha1.RetryPolicySpec{MaxRetries: 2},
+			retries: map[string]v1alpha1.JobRetry{"clients-1": {Count: 1}},
+			service: failedService("clients-1", "Error"),
+			want:    true,
+		},
+		{
+			name:    "budget exhausted",
+			policy:  &v1alpha1.RetryPolicySpec{MaxRetries: 2},
+			retries: map[string]v1alpha1.JobRetry{"clients-1": {Count: 2}},
+			service: failedService("clients-1", "Error"),
+			want:    false,
+		},
+		{
+			name:    "deletion in progress",
+			policy:  &v1alpha1.RetryPolicySpec{MaxRetries: 1},
+			retries: map[string]v1alpha1.JobRetry{"clients-1": {Count: 1, RecreateAfter: &recreateAfter}},
+			service: failedService("clients-1", "Error"),
+			want:    true,
+		},
+		{
+			name:    "evicted only",
+			policy:  &v1alpha1.RetryPolicySpec{MaxRetries: 1, RetryOn: []v1alpha1.RetryCondition{v1alpha1.RetryOnEvicted}},
+			service: failedService("clients-1", "Error"),
+			want:    false,
+		},
+		{
+			name:    "evicted",
+			policy:  &v1alpha1.RetryPolicySpec{MaxRetries: 1, RetryOn: []v1alpha1.RetryCondition{v1alpha1.RetryOnEvicted}},
+			service: failedService("clients-1", clusterutils.ReasonEvicted),
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cluster v1alpha1.Cluster
+
+			cluster.SetName("clients")
+			cluster.Spec.RetryPolicy = tt.policy
+			cluster.Status.Retries = tt.retries
+
+			if got := clusterutils.IsRetrying(&cluster, tt.service); got != tt.want {
+				t.Errorf("IsRetrying() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJobIndex(t *testing.T) {
+	var cluster v1alpha1.Cluster
+
+	cluster.SetName("clients")
+
+	if got, err := clusterutils.JobIndex(&cluster, "clients-3"); err != nil || got != 2 {
+		t.Errorf("JobIndex(clients-3) = %d, %v, want 2", got, err)
+	}
+
+	for _, name := range []string{"clients", "clients-0", "servers-1", "clients-x"} {
+		if _, err := clusterutils.JobIndex(&cluster, name); err == nil {
+			t.Errorf("JobIndex(%s) error = nil, want error", name)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	policy := v1alpha1.RetryPolicySpec{Backoff: &metav1.Duration{Duration: time.Minute}}
+
+	for retry, want := range map[int]time.Duration{
+		1: time.Minute,
+		2: 2 * time.Minute,
+		3: 4 * time.Minute,
+		4: v1alpha1.MaxRetryBackoff,
+	} {
+		if got := policy.Delay(retry); got != want {
+			t.Errorf("Delay(%d) = %s, want %s", retry, got, want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CreationWindow is the window over which the creation rate of the services is measured.
+const CreationWindow = time.Minute
+
+// RecentCreations returns the number of jobs that have been created within the creation window, and the time at
+// which the earliest of them leaves the window. The time is zero if there are no such jobs.
+func RecentCreations(jobs []client.Object, now time.Time) (int, time.Time) {
+	var (
+		count    int
+		earliest time.Time
+	)
+
+	since := now.Add(-CreationWindow)
+
+	for _, job := range jobs {
+		created := job.GetCreationTimestamp().Time
+
+		if !created.After(since) {
+			continue
+		}
+
+		count++
+
+		if earliest.IsZero() || created.Before(earliest) {
+			earliest = created
+		}
+	}
+
+	if count == 0 {
+		return 0, time.Time{}
+	}
+
+	return count, earliest.Add(CreationWindow)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	clusterutils "github.com/carv-ics-forth/frisbee/controllers/cluster/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func createdService(created time.Time) *v1alpha1.Service {
+	var service v1alpha1.Service
+
+	service.SetCreationTimestamp(metav1.NewTime(created))
+
+	return &service
+}
+
+func TestRecentCreations(t *testing.T) {
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		created     []time.Duration
+		wantCount   int
+		wantRelease time.Time
+	}{
+		{
+			name:      "no jobs",
+			wantCount: 0,
+		},
+		{
+			name:      "outside of the window",
+			created:   []time.Duration{-2 * time.Minute, -time.Minute},
+			wantCount: 0,
+		},
+		{
+			name:        "within the window",
+			created:     []time.Duration{-90 * time.Second, -40 * time.Second, -10 * time.Second, 0},
+			wantCount:   3,
+			wantRelease: now.Add(20 * time.Second),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := make([]client.Object, 0, len(tt.created))
+
+			for _, offset := range tt.created {
+				jobs = append(jobs, createdService(now.Add(offset)))
+			}
+
+			count, release := clusterutils.RecentCreations(jobs, now)
+
+			if count != tt.wantCount {
+				t.Errorf("count = %d, want %d", count, tt.wantCount)
+			}
+
+			if !release.Equal(tt.wantRelease) {
+				t.Errorf("release = %s, want %s", release, tt.wantRelease)
+			}
+		})
+	}
+}
@@ -22,7 +22,19 @@ import (
 )
 
 func SetTimeline(cluster *v1alpha1.Cluster) {
-	if cluster.Spec.Schedule == nil || cluster.Spec.Schedule.Timeline == nil {
+	if cluster.Spec.Schedule == nil {
+		return
+	}
+
+	// Ramps are not evaluated from a distribution. The timeline only reflects the pace of the ramp in the status.
+	if ramp := cluster.Spec.Schedule.Ramp; ramp != nil {
+		cluster.Status.ExpectedTimeline = ramp.Timeline(cluster.GetCreationTimestamp(), cluster.Spec.MaxInstances)
+
+		return
+	}
+
+	// Traces are evaluated into a timeline once they are loaded (see Initialize).
+	if cluster.Spec.Schedule.Timeline == nil {
 		return
 	}
 
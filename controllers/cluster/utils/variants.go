@@ -0,0 +1,62 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+)
+
+// MixVariants interleaves the jobs of the variants, so that every prefix of the mixed queue respects (as much
+// as possible) the proportions of the variants. For example, 2 readers and 1 writer become [reader, writer, reader].
+// This way, the proportions hold throughout the scheduling, and not only once all the jobs are scheduled.
+// The jobs of a variant iterate the input sets of the variant.
+func MixVariants(variants []v1alpha1.GenerateObjectFromTemplate) []v1alpha1.QueuedJob {
+	total := 0
+	for _, variant := range variants {
+		total += variant.MaxInstances
+	}
+
+	mixed := make([]v1alpha1.QueuedJob, 0, total)
+	next := make([]int, len(variants))
+
+	for len(mixed) < total {
+		// pick the variant that is the furthest behind its proportion.
+		pick := -1
+
+		for i, variant := range variants {
+			if next[i] == variant.MaxInstances {
+				continue
+			}
+
+			// compare next[i]/instances[i] < next[pick]/instances[pick], without divisions.
+			if pick == -1 || next[i]*variants[pick].MaxInstances < next[pick]*variant.MaxInstances {
+				pick = i
+			}
+		}
+
+		job := v1alpha1.QueuedJob{Variant: pick}
+
+		if inputs := len(variants[pick].Inputs); inputs > 0 {
+			job.Inputs = next[pick] % inputs
+		}
+
+		mixed = append(mixed, job)
+		next[pick]++
+	}
+
+	return mixed
+}
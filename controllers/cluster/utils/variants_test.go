@@ -0,0 +1,116 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	clusterutils "github.com/carv-ics-forth/frisbee/controllers/cluster/utils"
+)
+
+func TestVariantTemplates(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      v1alpha1.ClusterSpec
+		instances []int
+		wantErr   bool
+	}{
+		{
+			name: "instances",
+			spec: v1alpha1.ClusterSpec{
+				Variants: []v1alpha1.ClusterVariant{{Instances: 2}, {Instances: 3}},
+			},
+			instances: []int{2, 3},
+		},
+		{
+			name: "weights",
+			spec: v1alpha1.ClusterSpec{
+				GenerateObjectFromTemplate: v1alpha1.GenerateObjectFromTemplate{MaxInstances: 10},
+				Variants:                   []v1alpha1.ClusterVariant{{Weight: 7}, {Weight: 3}},
+			},
+			instances: []int{7, 3},
+		},
+		{
+			name: "weights with remainders",
+			spec: v1alpha1.ClusterSpec{
+				GenerateObjectFromTemplate: v1alpha1.GenerateObjectFromTemplate{MaxInstances: 4},
+				Variants:                   []v1alpha1.ClusterVariant{{Weight: 1}, {Weight: 1}, {Weight: 1}},
+			},
+			instances: []int{2, 1, 1},
+		},
+		{
+			name: "mixed sizes",
+			spec: v1alpha1.ClusterSpec{
+				GenerateObjectFromTemplate: v1alpha1.GenerateObjectFromTemplate{MaxInstances: 4},
+				Variants:                   []v1alpha1.ClusterVariant{{Weight: 1}, {Instances: 1}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "weights without instances",
+			spec: v1alpha1.ClusterSpec{
+				Variants: []v1alpha1.ClusterVariant{{Weight: 1}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templates, err := tt.spec.VariantTemplates()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VariantTemplates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			instances := make([]int, len(templates))
+			for i, template := range templates {
+				instances[i] = template.MaxInstances
+			}
+
+			if !reflect.DeepEqual(instances, tt.instances) {
+				t.Errorf("expected instances '%v' but got '%v'", tt.instances, instances)
+			}
+		})
+	}
+}
+
+func TestMixVariants(t *testing.T) {
+	names := []string{"reader", "writer"}
+
+	mixed := clusterutils.MixVariants([]v1alpha1.GenerateObjectFromTemplate{
+		{TemplateRef: "reader", MaxInstances: 4},
+		{TemplateRef: "writer", MaxInstances: 2, Inputs: []v1alpha1.UserInputs{{}, {}}},
+	})
+
+	var got []string
+	for _, job := range mixed {
+		got = append(got, fmt.Sprintf("%s-%d", names[job.Variant], job.Inputs))
+	}
+
+	expected := []string{"reader-0", "writer-0", "reader-0", "reader-0", "writer-1", "reader-0"}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected '%v' but got '%v'", expected, got)
+	}
+}
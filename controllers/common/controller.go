@@ -22,10 +22,14 @@ import (
 	"time"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/clock"
+	"github.com/carv-ics-forth/frisbee/pkg/tracing"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
 	k8errors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -86,6 +90,9 @@ type Reconciler interface {
 
 	Logger
 
+	// Clock is the time source of the reconciler. Tests inject a fake clock to advance schedules deterministically.
+	clock.Clock
+
 	// Finalizer returns a list of finalizers associated with the controller.
 	Finalizer() string
 
@@ -99,7 +106,7 @@ type Reconciler interface {
 //
 // Bool indicate whether the caller should return immediately (true) or continue (false).
 // The reconciliation cycle is where the framework gives us back control after a watch has passed up an event.
-func Reconcile(parentCtx context.Context, r Reconciler, req ctrl.Request, obj client.Object, requeue *bool) (ctrl.Result, error) {
+func Reconcile(parentCtx context.Context, r Reconciler, req ctrl.Request, obj client.Object, requeue *bool) (result ctrl.Result, err error) {
 	/*-- make the calling controller to return --*/
 	*requeue = true
 
@@ -119,9 +126,17 @@ func Reconcile(parentCtx context.Context, r Reconciler, req ctrl.Request, obj cl
 
 		logger.Error(err, "Unable to find requested object")
 
+		trace.SpanFromContext(parentCtx).RecordError(err)
+
 		return RequeueWithError(r, req, err)
 	}
 
+	// Describe the reconciliation in terms of the scenario, and link it to the reconciliation that created the object.
+	trace.SpanFromContext(parentCtx).SetAttributes(tracing.Attributes(obj)...)
+
+	ctx, span := tracing.Start(parentCtx, "Lifecycle", obj)
+	defer func() { tracing.End(span, err) }()
+
 	/*---------------------------------------------------
 	 * Set Finalizers for CR
 	 *---------------------------------------------------*/
@@ -149,7 +164,7 @@ func Reconcile(parentCtx context.Context, r Reconciler, req ctrl.Request, obj cl
 				return true, nil
 			}
 
-			if err := wait.ExponentialBackoffWithContext(parentCtx, DefaultBackoffForK8sEndpoint, retryCond); err != nil {
+			if err := wait.ExponentialBackoffWithContext(ctx, DefaultBackoffForK8sEndpoint, retryCond); err != nil {
 				logger.Error(err, "Abort retrying to add finalizer. Requeue the request")
 
 				return RequeueWithError(r, req, err)
@@ -194,7 +209,7 @@ func Reconcile(parentCtx context.Context, r Reconciler, req ctrl.Request, obj cl
 					return true, nil
 				}
 
-				if err := wait.ExponentialBackoffWithContext(parentCtx, DefaultBackoffForK8sEndpoint, retryCond); err != nil {
+				if err := wait.ExponentialBackoffWithContext(ctx, DefaultBackoffForK8sEndpoint, retryCond); err != nil {
 					logger.Error(err, "Abort retrying to remove finalizer. Requeue the request")
 
 					return RequeueWithError(r, req, err)
@@ -232,6 +247,22 @@ func UpdateStatus(ctx context.Context, reconciler Reconciler, obj client.Object)
 
 	statusAwre, ok := obj.(v1alpha1.ReconcileStatusAware)
 	if ok {
+		// record the generation that the status refers to.
+		lf := statusAwre.GetReconcileStatus()
+		lf.Observe(obj.GetGeneration())
+		statusAwre.SetReconcileStatus(lf)
+
+		// move the large payloads out of the status, and refuse statuses that are still too large.
+		if offloadable, ok := obj.(v1alpha1.OffloadableStatus); ok {
+			if err := offloadPayloads(ctx, reconciler, offloadable); err != nil {
+				return errors.Wrapf(err, "cannot offload payloads")
+			}
+		}
+
+		if err := validateSize(obj); err != nil {
+			return errors.Wrapf(err, "status is too large")
+		}
+
 		logger.Info("OO UpdtStatus",
 			"phase", statusAwre.GetReconcileStatus().Phase,
 			"version", obj.GetResourceVersion(),
@@ -254,7 +285,7 @@ func UpdateStatus(ctx context.Context, reconciler Reconciler, obj client.Object)
 // Create ignores existing objects.
 // if the next reconciliation cycle happens faster than the API update, it is possible to
 // reschedule the creation of a Job. To avoid that, get if the Job is already submitted.
-func Create(ctx context.Context, reconciler Reconciler, parent, child client.Object) error {
+func Create(ctx context.Context, reconciler Reconciler, parent, child client.Object) (err error) {
 	if reconciler == nil || parent == nil || child == nil {
 		panic(errors.Errorf("empty parameters.  Reconciler:%t Parent:%t Child:%t",
 			reconciler == nil, parent == nil, child == nil))
@@ -263,6 +294,10 @@ func Create(ctx context.Context, reconciler Reconciler, parent, child client.Obj
 	// Create a searchable link between the parent and the children.
 	v1alpha1.SetCreatedByLabel(child, parent)
 
+	if err := v1alpha1.PropagateMetadata(child, parent); err != nil {
+		return errors.Wrapf(err, "cannot propagate metadata")
+	}
+
 	child.SetNamespace(parent.GetNamespace())
 
 	// SetControllerReference sets owner as a Controller OwnerReference on controlled.
@@ -274,6 +309,53 @@ func Create(ctx context.Context, reconciler Reconciler, parent, child client.Obj
 		return errors.Wrapf(err, "set controller reference")
 	}
 
+	// The reconciliation of the child will be linked to the creation.
+	ctx, span := tracing.Start(ctx, "Create", child)
+	defer func() { tracing.End(span, err) }()
+
+	tracing.Inject(ctx, child)
+
+	reconciler.Info("++ Create",
+		"kind", reflect.TypeOf(child),
+		"obj", client.ObjectKeyFromObject(child),
+	)
+
+	if err := reconciler.GetClient().Create(ctx, child); err != nil {
+		if k8errors.IsAlreadyExists(err) {
+			// already exists. nothing to do.
+			return nil
+		}
+
+		return errors.Wrapf(err, "creation error")
+	}
+
+	return nil
+}
+
+// CreateInNamespace creates the child in the given namespace, which must differ from the namespace of the parent.
+// Since owner references cannot cross namespaces, the child is linked to its parent only by labels, and it is
+// not garbage-collected along with the parent. Existing objects are ignored.
+func CreateInNamespace(ctx context.Context, reconciler Reconciler, parent, child client.Object, namespace string) (err error) {
+	if namespace == parent.GetNamespace() {
+		return Create(ctx, reconciler, parent, child)
+	}
+
+	v1alpha1.SetCreatedByLabel(child, parent)
+
+	if err := v1alpha1.PropagateMetadata(child, parent); err != nil {
+		return errors.Wrapf(err, "cannot propagate metadata")
+	}
+
+	child.SetNamespace(namespace)
+	child.SetLabels(labels.Merge(child.GetLabels(), map[string]string{
+		v1alpha1.LabelParentNamespace: parent.GetNamespace(),
+	}))
+
+	ctx, span := tracing.Start(ctx, "Create", child)
+	defer func() { tracing.End(span, err) }()
+
+	tracing.Inject(ctx, child)
+
 	reconciler.Info("++ Create",
 		"kind", reflect.TypeOf(child),
 		"obj", client.ObjectKeyFromObject(child),
@@ -291,6 +373,23 @@ func Create(ctx context.Context, reconciler Reconciler, parent, child client.Obj
 	return nil
 }
 
+// ListIsolatedChildren lists the children that are placed outside the namespace of their parent
+// (see CreateInNamespace).
+func ListIsolatedChildren(ctx context.Context, cli client.Client, childJobs client.ObjectList, req types.NamespacedName) error {
+	filters := []client.ListOption{
+		client.MatchingLabels{
+			v1alpha1.LabelCreatedBy:       req.Name,
+			v1alpha1.LabelParentNamespace: req.Namespace,
+		},
+	}
+
+	if err := cli.List(ctx, childJobs, filters...); err != nil {
+		return errors.Wrapf(err, "cannot list isolated children")
+	}
+
+	return nil
+}
+
 func ListChildren(ctx context.Context, cli client.Client, childJobs client.ObjectList, req types.NamespacedName) error {
 	filters := []client.ListOption{
 		client.InNamespace(req.Namespace),
@@ -307,6 +406,9 @@ func ListChildren(ctx context.Context, cli client.Client, childJobs client.Objec
 // Delete removes a Kubernetes object, ignoring the NotFound error. If any error exists,
 // it is recorded in the reconciler's logger.
 func Delete(ctx context.Context, reconciler Reconciler, obj client.Object) {
+	ctx, span := tracing.Start(ctx, "Delete", obj)
+	defer span.End()
+
 	reconciler.Info("-- Delete",
 		"kind", reflect.TypeOf(obj),
 		"obj", client.ObjectKeyFromObject(obj),
@@ -324,6 +426,8 @@ func Delete(ctx context.Context, reconciler Reconciler, obj client.Object) {
 	// Ignore
 	case err != nil:
 		reconciler.Error(err, "deletion error", "obj", client.ObjectKeyFromObject(obj))
+
+		span.RecordError(err)
 	default:
 		return
 	}
@@ -334,7 +438,11 @@ func Delete(ctx context.Context, reconciler Reconciler, obj client.Object) {
 func IsManagedByThisController(obj metav1.Object, controller schema.GroupVersionKind) bool {
 	owner := metav1.GetControllerOf(obj)
 	if owner == nil {
-		return false
+		// owner references cannot cross namespaces. The resources in the namespace of their action are
+		// managed by the scenario of the parent namespace.
+		_, isolated := obj.GetLabels()[v1alpha1.LabelParentNamespace]
+
+		return isolated && controller.GroupKind() == v1alpha1.GroupVersion.WithKind("Scenario").GroupKind()
 	}
 
 	if owner.APIVersion != controller.GroupVersion().String() ||
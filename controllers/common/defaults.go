@@ -29,12 +29,38 @@ const (
 	DefaultPrometheusName = "prometheus"
 )
 
+// Ingestion Section
+const (
+	// DefaultIngestionName is the name of the ingestion service. It is fixed because it is used within the
+	// provisioned Grafana datasource.
+	DefaultIngestionName = "influxdb"
+
+	DefaultIngestionPort = int64(8086)
+)
+
+// Tracing Section
+const (
+	// DefaultTracingName is the name of the tracing backend, regardless of the backend type.
+	DefaultTracingName = "tracing"
+
+	// DefaultTracingOTLPPort is where the tracing backend receives spans over OTLP (gRPC).
+	DefaultTracingOTLPPort = int64(4317)
+
+	// DefaultTempoQueryPort is where Tempo serves the queries of Grafana.
+	DefaultTempoQueryPort = int64(3200)
+
+	// DefaultJaegerQueryPort is where Jaeger serves the queries of Grafana.
+	DefaultJaegerQueryPort = int64(16686)
+)
+
 // Grafana Section
 const (
 	DefaultGrafanaServiceName = "grafana"
 
 	DefaultGrafanaDashboardsPath = "/etc/grafana/provisioning/dashboards"
 
+	DefaultGrafanaDatasourcesPath = "/etc/grafana/provisioning/datasources"
+
 	DefaultGrafanaPort = int64(3000)
 
 	DefaultAdvertisedAlertingServiceHost = "alerting-service"
@@ -30,7 +30,7 @@ func InternalEndpoint(name string, planName string, port int64) string {
 
 // ExternalEndpoint creates an endpoint for accessing the service outside the cluster.
 func ExternalEndpoint(name, planName string) string {
-	return fmt.Sprintf("%s-%s.%s", name, planName, configuration.Global.DomainName)
+	return fmt.Sprintf("%s-%s.%s", name, planName, configuration.Global().DomainName)
 }
 
 // GenerateName names the children of a given resource. The instances will be named as Master-1, Master-2, ...
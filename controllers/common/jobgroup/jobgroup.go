@@ -0,0 +1,159 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jobgroup gathers the logic that is shared among the controllers that manage a group of jobs
+// (e.g, Cluster, Call, Cascade). These controllers differ only in the kind of job they create. The construction
+// of the queue and the creation of the job are left to the controllers, whereas the scheduling of the next job,
+// the evaluation of the lifecycle, and the handling of the terminal phases are implemented here.
+package jobgroup
+
+import (
+	"context"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/pkg/expressions"
+	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Group binds the fields that are common among the resources that manage a group of jobs.
+// Pointers refer to the fields of the resource, so that any change is directly reflected on the resource.
+type Group struct {
+	// Object is the resource that owns the jobs.
+	Object client.Object
+
+	// Lifecycle points to the lifecycle of the resource.
+	Lifecycle *v1alpha1.Lifecycle
+
+	// Suspend points to the spec.suspend field of the resource.
+	Suspend **bool
+
+	// SuspendWhen is the condition that suspends the scheduling of new jobs.
+	SuspendWhen *v1alpha1.ConditionalExpr
+
+	// Schedule is the scheduling policy for the jobs.
+	Schedule *v1alpha1.TaskSchedulerSpec
+
+	// Tolerate defines the failures that are tolerated by the group. Nil if tolerance is not supported.
+	Tolerate *v1alpha1.TolerateSpec
+
+	// MaxConcurrentJobs bounds the jobs that are active (pending or running) simultaneously. Zero means no limit.
+	MaxConcurrentJobs int
+
+	// MaxInstances is the number of jobs that the group is expected to create. If SuspendWhen is set,
+	// MaxInstances acts as an upper bound for the jobs that are created before the condition is met.
+	MaxInstances int
+
+	// QueuedJobs is the number of jobs in the queue.
+	QueuedJobs int
+
+	// ScheduledJobs points to the index of the last scheduled job.
+	ScheduledJobs *int
+
+	// LastScheduleTime points to the time the last job was scheduled.
+	LastScheduleTime *metav1.Time
+
+	// ExpectedTimeline is the evaluation of the timeline distribution, if any.
+	ExpectedTimeline v1alpha1.Timeline
+}
+
+// IsSuspended returns true if the group must not create any new jobs.
+func (g Group) IsSuspended() bool {
+	return *g.Suspend != nil && **g.Suspend
+}
+
+// Initialize prepares the group for the scheduling of jobs. It must be called once the queue is constructed.
+func Initialize(ctx context.Context, r common.Reconciler, g Group) error {
+	*g.ScheduledJobs = -1
+
+	// Metrics-driven execution requires to set alerts on Grafana.
+	if until := g.SuspendWhen; until != nil && until.HasMetricsExpr() {
+		if err := expressions.SetAlert(ctx, g.Object, until.Metrics); err != nil {
+			return errors.Wrapf(err, "spec.suspendWhen")
+		}
+	}
+
+	if schedule := g.Schedule; schedule != nil && schedule.Event.HasMetricsExpr() {
+		if err := expressions.SetAlert(ctx, g.Object, schedule.Event.Metrics); err != nil {
+			return errors.Wrapf(err, "spec.schedule")
+		}
+	}
+
+	if _, err := lifecycle.Pending(ctx, r, g.Object, "submitting job requests"); err != nil {
+		return errors.Wrapf(err, "status update")
+	}
+
+	return nil
+}
+
+// HasSucceed removes the successful jobs of the group.
+func HasSucceed(ctx context.Context, r common.Reconciler, view *lifecycle.Classifier, g Group) error {
+	r.Info("CleanOnSuccess",
+		"obj", client.ObjectKeyFromObject(g.Object).String(),
+		"successfulJobs", view.ListSuccessfulJobs(),
+	)
+
+	/*
+		Remove cr children once the cr is successfully complete.
+		We should not remove the cr descriptor itself, as we need to maintain its
+		status for higher-entities like the Scenario.
+	*/
+	for _, job := range view.GetSuccessfulJobs() {
+		common.Delete(ctx, r, job)
+	}
+
+	return nil
+}
+
+// HasFailed removes the non-failed jobs of the group, and suspends the group from creating further jobs.
+func HasFailed(ctx context.Context, r common.Reconciler, view *lifecycle.Classifier, g Group) error {
+	r.Info("!! JobError",
+		"obj", client.ObjectKeyFromObject(g.Object).String(),
+		"reason", g.Lifecycle.Reason,
+		"message", g.Lifecycle.Message,
+	)
+
+	// Remove the non-failed components. Leave the failed jobs and system jobs for postmortem analysis.
+	for _, job := range view.GetPendingJobs() {
+		common.Delete(ctx, r, job)
+	}
+
+	for _, job := range view.GetRunningJobs() {
+		common.Delete(ctx, r, job)
+	}
+
+	// Block from creating further jobs
+	suspend := true
+	*g.Suspend = &suspend
+
+	r.Info("Suspended",
+		"obj", client.ObjectKeyFromObject(g.Object),
+		"reason", g.Lifecycle.Reason,
+		"message", g.Lifecycle.Message,
+	)
+
+	if g.Object.GetDeletionTimestamp().IsZero() {
+		r.GetEventRecorderFor(g.Object.GetName()).Event(g.Object, corev1.EventTypeNormal,
+			"Suspended", g.Lifecycle.Message)
+	}
+
+	// Update is needed since we modify the spec.suspend
+	return common.Update(ctx, r, g.Object)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobgroup
+
+import (
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/expressions"
+	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+)
+
+// UpdateLifecycle updates the lifecycle of the group, based on the progress of its jobs
+// and on the SuspendWhen conditions (see expressions.Until). It returns true if the lifecycle has changed.
+func UpdateLifecycle(view *lifecycle.Classifier, g Group) bool {
+	lf := g.Lifecycle
+
+	// Step 1. Skip any CR which are already completed, or uninitialized.
+	if lf.Phase.Is(v1alpha1.PhaseUninitialized, v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed) {
+		return false
+	}
+
+	// Step 2. Check if scheduling goes as expected.
+	if g.SuspendWhen.IsZero() {
+		return lifecycle.GroupedJobs(g.QueuedJobs, view, lf, g.Tolerate)
+	}
+
+	// Step 3. Check if "SuspendWhen" conditions are met.
+	until := expressions.Until{
+		Expr:          g.SuspendWhen,
+		Suspend:       g.Suspend,
+		ScheduledJobs: *g.ScheduledJobs,
+		MaxJobs:       g.MaxInstances,
+	}
+
+	if until.IsHandled(lf) {
+		// The Until condition is already handled, and we are in the Running Phase.
+		// From now on, the lifecycle depends on the progress of the already scheduled jobs.
+		totalJobs := *g.ScheduledJobs + 1
+
+		return lifecycle.GroupedJobs(totalJobs, view, lf, g.Tolerate)
+	}
+
+	return until.Evaluate(view, g.Object, lf)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobgroup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/pkg/expressions"
+	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	"github.com/carv-ics-forth/frisbee/pkg/scheduler"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RunJobFunc creates the job found at the given index of the queue.
+type RunJobFunc func(ctx context.Context, jobIndex int) error
+
+// ScheduleNext checks whether the conditions are right to spawn a new job, and if so, it creates the next job
+// of the queue using runJob.
+func ScheduleNext(ctx context.Context, r common.Reconciler, req ctrl.Request, view *lifecycle.Classifier, g Group,
+	runJob RunJobFunc,
+) (ctrl.Result, error) {
+	//	If all jobs are scheduled but are not in the Running phase, they may be in the Pending phase.
+	//	In both cases, we have nothing else to do but waiting for the next reconciliation cycle.
+	if view.Count() >= g.QueuedJobs {
+		r.Info("All jobs have been scheduled. Nothing else to do. ")
+
+		return stopOrPoll(r, req, g)
+	}
+
+	log := r.WithValues("object", client.ObjectKeyFromObject(g.Object))
+
+	// Keep the remaining jobs queued, until some of the active jobs are completed. The completion of a job
+	// triggers the next reconciliation cycle.
+	if active := activeJobs(view, g); g.MaxConcurrentJobs > 0 && active >= g.MaxConcurrentJobs {
+		log.Info("Concurrency limit is reached. Wait for jobs to complete.", "active", active, "limit", g.MaxConcurrentJobs)
+
+		return stopOrPoll(r, req, g)
+	}
+
+	// Check if the conditions are right to spawn a new job.
+	hasJob, nextTick, err := scheduler.Schedule(log, g.Object, scheduler.Parameters{
+		Now:              r.Now(),
+		State:            *view,
+		ScheduleSpec:     g.Schedule,
+		LastScheduleTime: *g.LastScheduleTime,
+		ExpectedTimeline: g.ExpectedTimeline,
+		JobName:          g.Object.GetName(),
+		ScheduledJobs:    *g.ScheduledJobs,
+		QueuedJobs:       g.QueuedJobs,
+	})
+	if err != nil {
+		return lifecycle.Failed(ctx, r, g.Object, errors.Wrapf(err, "scheduling error"))
+	}
+
+	if !hasJob {
+		// nothing to schedule
+		if nextTick.IsZero() {
+			return stopOrPoll(r, req, g)
+		}
+
+		// sleep until next tick
+		return common.RequeueAfter(r, req, r.Until(nextTick))
+	}
+
+	// Fetch the next job from the queuing list, and submit it to Kubernetes.
+	nextJobIndex := *g.ScheduledJobs + 1
+
+	if nextJobIndex >= g.QueuedJobs {
+		r.Error(errors.New("Ignore job as it is out of range compared to QueuedJobs"),
+			"BadScheduling",
+			"nextJobIndex", nextJobIndex,
+			"queueJobs", g.QueuedJobs,
+			"viewedJobs", view.Count(),
+			"jobList", view.ListAll(),
+		)
+
+		return common.Stop(r, req)
+	}
+
+	if err := runJob(ctx, nextJobIndex); err != nil {
+		return lifecycle.Failed(ctx, r, g.Object, errors.Wrapf(err, "cannot create job"))
+	}
+
+	// Update the scheduling information
+	*g.ScheduledJobs = nextJobIndex
+	*g.LastScheduleTime = metav1.Time{Time: r.Now()}
+
+	return lifecycle.Pending(ctx, r, g.Object, fmt.Sprintf("Scheduled jobs: '%d/%d'",
+		*g.ScheduledJobs+1, g.MaxInstances))
+}
+
+// activeJobs returns the number of jobs that are created but not yet completed. The count is based on the scheduling
+// record, because the classifier ignores the jobs that are just created and are still uninitialized.
+func activeJobs(view *lifecycle.Classifier, g Group) int {
+	return (*g.ScheduledJobs + 1) - view.NumSuccessfulJobs() - view.NumFailedJobs()
+}
+
+// stopOrPoll stops the reconciliation, unless the group depends on expressions that must be polled.
+func stopOrPoll(r common.Reconciler, req ctrl.Request, g Group) (ctrl.Result, error) {
+	if expressions.NeedsPolling(g.SuspendWhen) || (g.Schedule != nil && expressions.NeedsPolling(g.Schedule.Event)) {
+		return common.RequeueAfter(r, req, expressions.AlertmanagerPollInterval)
+	}
+
+	return common.Stop(r, req)
+}
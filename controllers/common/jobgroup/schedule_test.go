@@ -0,0 +1,130 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobgroup_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common/jobgroup"
+	"github.com/carv-ics-forth/frisbee/pkg/clock"
+	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// reconciler is a minimal reconciler, whose status updates are ignored.
+type reconciler struct {
+	logr.Logger
+	clock.Clock
+
+	cli client.Client
+}
+
+func (r *reconciler) GetClient() client.Client { return r.cli }
+
+func (r *reconciler) GetCache() cache.Cache { return nil }
+
+func (r *reconciler) GetEventRecorderFor(string) record.EventRecorder {
+	return record.NewFakeRecorder(100)
+}
+
+func (r *reconciler) Finalizer() string { return "" }
+
+func (r *reconciler) Finalize(client.Object) error { return nil }
+
+// TestScheduleNext_MaxConcurrentJobs checks that the concurrency limit holds while the created jobs are still
+// uninitialized, and thus invisible to the classifier.
+func TestScheduleNext_MaxConcurrentJobs(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+
+	r := &reconciler{
+		Logger: logr.Discard(),
+		Clock:  clock.RealClock{},
+		cli:    fake.NewClientBuilder().WithScheme(scheme).Build(),
+	}
+
+	var cluster v1alpha1.Cluster
+
+	cluster.SetNamespace("default")
+	cluster.SetName("clients")
+	cluster.Spec.MaxInstances = 10
+	cluster.Spec.MaxConcurrentJobs = 2
+	cluster.Status.QueuedJobs = make([]v1alpha1.QueuedJob, 10)
+	cluster.Status.ScheduledJobs = -1
+
+	g := jobgroup.Group{
+		Object:            &cluster,
+		Lifecycle:         &cluster.Status.Lifecycle,
+		Suspend:           &cluster.Spec.Suspend,
+		MaxConcurrentJobs: cluster.Spec.MaxConcurrentJobs,
+		MaxInstances:      cluster.Spec.MaxInstances,
+		QueuedJobs:        len(cluster.Status.QueuedJobs),
+		ScheduledJobs:     &cluster.Status.ScheduledJobs,
+		LastScheduleTime:  &cluster.Status.LastScheduleTime,
+	}
+
+	var created []int
+
+	runJob := func(_ context.Context, jobIndex int) error {
+		created = append(created, jobIndex)
+
+		return nil
+	}
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&cluster)}
+	view := &lifecycle.Classifier{}
+
+	// every status update triggers another reconciliation, while the created jobs are still uninitialized.
+	for i := 0; i < 5; i++ {
+		if _, err := jobgroup.ScheduleNext(context.Background(), r, req, view, g, runJob); err != nil {
+			t.Fatalf("ScheduleNext() error = %v", err)
+		}
+	}
+
+	if got, want := len(created), cluster.Spec.MaxConcurrentJobs; got != want {
+		t.Fatalf("created %d jobs while uninitialized, want %d", got, want)
+	}
+
+	// the completion of a job releases a slot for the next one.
+	var completed v1alpha1.Service
+
+	completed.SetName(fmt.Sprintf("clients-%d", created[0]+1))
+	v1alpha1.SetComponentLabel(&completed.ObjectMeta, v1alpha1.ComponentSUT)
+	completed.Status.Lifecycle.Phase = v1alpha1.PhaseSuccess
+
+	view.Classify(completed.GetName(), &completed)
+
+	for i := 0; i < 5; i++ {
+		if _, err := jobgroup.ScheduleNext(context.Background(), r, req, view, g, runJob); err != nil {
+			t.Fatalf("ScheduleNext() error = %v", err)
+		}
+	}
+
+	if got, want := len(created), cluster.Spec.MaxConcurrentJobs+1; got != want {
+		t.Fatalf("created %d jobs after a completion, want %d", got, want)
+	}
+}
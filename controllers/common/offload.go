@@ -0,0 +1,110 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	// MaxObjectSize bounds the encoded size of the objects whose status is updated, and of the ConfigMaps
+	// that hold the offloaded payloads. By default, etcd limits the size of a request to 1.5MiB.
+	MaxObjectSize = 1024 * 1024
+
+	// OffloadThreshold is the size above which the payloads of an OffloadableStatus are moved to a ConfigMap.
+	OffloadThreshold = 32 * 1024
+)
+
+// offloadPayloads moves the large payloads of the status to the ConfigMap of the object. The ConfigMap accumulates
+// the payloads of successive updates.
+func offloadPayloads(ctx context.Context, reconciler Reconciler, obj v1alpha1.OffloadableStatus) error {
+	payloads := obj.OffloadPayloads(OffloadThreshold)
+	if len(payloads) == 0 {
+		return nil
+	}
+
+	owner, ok := obj.(client.Object)
+	if !ok {
+		return errors.Errorf("object '%s' is not a client object", obj.GetName())
+	}
+
+	var configMap corev1.ConfigMap
+
+	key := client.ObjectKey{Namespace: obj.GetNamespace(), Name: v1alpha1.PayloadsConfigMapName(obj.GetName())}
+
+	err := reconciler.GetClient().Get(ctx, key, &configMap)
+
+	switch {
+	case k8errors.IsNotFound(err):
+		configMap.SetName(key.Name)
+		configMap.Data = payloads
+
+		if err := validateSize(&configMap); err != nil {
+			return errors.Wrapf(err, "payloads of '%s'", key)
+		}
+
+		if err := Create(ctx, reconciler, owner, &configMap); err != nil {
+			return errors.Wrapf(err, "cannot create configmap '%s'", key)
+		}
+
+	case err != nil:
+		return errors.Wrapf(err, "cannot get configmap '%s'", key)
+
+	default:
+		if configMap.Data == nil {
+			configMap.Data = make(map[string]string, len(payloads))
+		}
+
+		for payloadKey, payload := range payloads {
+			configMap.Data[payloadKey] = payload
+		}
+
+		if err := validateSize(&configMap); err != nil {
+			return errors.Wrapf(err, "payloads of '%s'", key)
+		}
+
+		if err := reconciler.GetClient().Update(ctx, &configMap); err != nil {
+			return errors.Wrapf(err, "cannot update configmap '%s'", key)
+		}
+	}
+
+	obj.SetPayloadsRef(key.Name)
+
+	return nil
+}
+
+// validateSize returns an error if the encoded object exceeds the MaxObjectSize.
+func validateSize(obj client.Object) error {
+	encoded, err := json.Marshal(obj)
+	if err != nil {
+		return errors.Wrapf(err, "cannot encode object")
+	}
+
+	if len(encoded) > MaxObjectSize {
+		return errors.Errorf("object '%s' has size '%d' bytes, which exceeds the limit of '%d' bytes",
+			client.ObjectKeyFromObject(obj), len(encoded), MaxObjectSize)
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/clock"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// reconciler is a minimal reconciler on top of a fake client.
+type reconciler struct {
+	logr.Logger
+	clock.Clock
+
+	cli client.Client
+}
+
+func (r *reconciler) GetClient() client.Client { return r.cli }
+
+func (r *reconciler) GetCache() cache.Cache { return nil }
+
+func (r *reconciler) GetEventRecorderFor(string) record.EventRecorder {
+	return record.NewFakeRecorder(100)
+}
+
+func (r *reconciler) Finalizer() string { return "" }
+
+func (r *reconciler) Finalize(client.Object) error { return nil }
+
+func newReconciler(objs ...client.Object) *reconciler {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+
+	return &reconciler{
+		Logger: logr.Discard(),
+		Clock:  clock.RealClock{},
+		cli: fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(objs...).
+			WithStatusSubresource(objs...).
+			Build(),
+	}
+}
+
+func TestOffloadPayloads(t *testing.T) {
+	ctx := context.Background()
+
+	var scenario v1alpha1.Scenario
+
+	scenario.SetNamespace("default")
+	scenario.SetName("scenario")
+
+	r := newReconciler(&scenario)
+
+	large := strings.Repeat("x", OffloadThreshold+1)
+
+	// the first offload creates the ConfigMap of the scenario.
+	scenario.Status.Message = large
+
+	if err := offloadPayloads(ctx, r, &scenario); err != nil {
+		t.Fatalf("offloadPayloads() error = %v", err)
+	}
+
+	if got, want := scenario.Status.PayloadsRef, "scenario-payloads"; got != want {
+		t.Errorf("PayloadsRef = %s, want %s", got, want)
+	}
+
+	if got, want := scenario.Status.Message, v1alpha1.OffloadedPayload("message", len(large)); got != want {
+		t.Errorf("Message = %s, want %s", got, want)
+	}
+
+	var configMap corev1.ConfigMap
+
+	key := client.ObjectKey{Namespace: "default", Name: "scenario-payloads"}
+
+	if err := r.cli.Get(ctx, key, &configMap); err != nil {
+		t.Fatalf("cannot get configmap: %v", err)
+	}
+
+	if owners := configMap.GetOwnerReferences(); len(owners) != 1 || owners[0].Name != "scenario" {
+		t.Errorf("owner references = %v, want the scenario", owners)
+	}
+
+	// the next offload merges the new payloads into the ConfigMap.
+	scenario.Status.Actions = []v1alpha1.ActionStatus{{Name: "load", Message: large}}
+
+	if err := offloadPayloads(ctx, r, &scenario); err != nil {
+		t.Fatalf("offloadPayloads() error = %v", err)
+	}
+
+	if err := r.cli.Get(ctx, key, &configMap); err != nil {
+		t.Fatalf("cannot get configmap: %v", err)
+	}
+
+	for _, payloadKey := range []string{"message", "load.message"} {
+		if got := configMap.Data[payloadKey]; got != large {
+			t.Errorf("payload '%s' has %d bytes, want %d", payloadKey, len(got), len(large))
+		}
+	}
+}
+
+func TestUpdateStatus_Size(t *testing.T) {
+	defer func(limit int) { MaxObjectSize = limit }(MaxObjectSize)
+
+	MaxObjectSize = 4 * OffloadThreshold
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		status  func(scenario *v1alpha1.Scenario)
+		wantErr string
+	}{
+		{
+			name: "offloaded",
+			status: func(scenario *v1alpha1.Scenario) {
+				scenario.Status.Message = strings.Repeat("x", 2*OffloadThreshold)
+			},
+		},
+		{
+			name: "payloads too large",
+			status: func(scenario *v1alpha1.Scenario) {
+				scenario.Status.Message = strings.Repeat("x", MaxObjectSize)
+			},
+			wantErr: "payloads of 'default/scenario-payloads'",
+		},
+		{
+			name: "status too large",
+			status: func(scenario *v1alpha1.Scenario) {
+				// many small entries, none of which exceeds the threshold.
+				for i := 0; i < MaxObjectSize/OffloadThreshold+1; i++ {
+					scenario.Status.TeardownJobs = append(scenario.Status.TeardownJobs, strings.Repeat("x", OffloadThreshold))
+				}
+			},
+			wantErr: "status is too large",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scenario v1alpha1.Scenario
+
+			scenario.SetNamespace("default")
+			scenario.SetName("scenario")
+
+			r := newReconciler(&scenario)
+
+			tt.status(&scenario)
+
+			err := UpdateStatus(ctx, r, &scenario)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("UpdateStatus() error = %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("UpdateStatus() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
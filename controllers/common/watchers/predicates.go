@@ -0,0 +1,84 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watchers
+
+import (
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// externalChanged reports whether an update on an external object (Pods, Faults, etc.) may affect the
+// lifecycle of its parent. Metadata-only updates, such as changes to labels, annotations or managed fields,
+// are filtered out, since the parent never consumes them.
+func externalChanged(prev, latest client.Object) bool {
+	if prev.GetGeneration() != latest.GetGeneration() {
+		return true
+	}
+
+	if !prev.GetDeletionTimestamp().Equal(latest.GetDeletionTimestamp()) {
+		return true
+	}
+
+	switch prevObj := prev.(type) {
+	case *corev1.Pod:
+		latestObj, ok := latest.(*corev1.Pod)
+		if !ok {
+			return true
+		}
+
+		return podChanged(prevObj, latestObj)
+
+	case runtime.Unstructured:
+		latestObj, ok := latest.(runtime.Unstructured)
+		if !ok {
+			return true
+		}
+
+		return !reflect.DeepEqual(prevObj.UnstructuredContent()["status"], latestObj.UnstructuredContent()["status"])
+
+	default:
+		// unknown type. be conservative and let the parent decide.
+		return true
+	}
+}
+
+// podChanged compares only the Pod fields that are used by the lifecycle classifiers.
+func podChanged(prev, latest *corev1.Pod) bool {
+	if prev.Status.Phase != latest.Status.Phase ||
+		prev.Status.Reason != latest.Status.Reason ||
+		prev.Status.Message != latest.Status.Message ||
+		prev.Spec.NodeName != latest.Spec.NodeName {
+		return true
+	}
+
+	return !reflect.DeepEqual(containerStates(prev.Status.InitContainerStatuses), containerStates(latest.Status.InitContainerStatuses)) ||
+		!reflect.DeepEqual(containerStates(prev.Status.ContainerStatuses), containerStates(latest.Status.ContainerStatuses))
+}
+
+// containerStates strips the volatile fields (e.g, readiness, restart counters) from the container statuses.
+func containerStates(statuses []corev1.ContainerStatus) []corev1.ContainerState {
+	states := make([]corev1.ContainerState, len(statuses))
+
+	for i, status := range statuses {
+		states[i] = status.State
+	}
+
+	return states
+}
@@ -101,6 +101,12 @@ func (w *simpleWatch) watchUpdate(reconciler common.Reconciler, gvk schema.Group
 
 		if !prevOK || !latestOK {
 			// this may happen for external objects like Pods, Faults, etc.
+			if !externalChanged(event.ObjectOld, event.ObjectNew) {
+				reconciler.Info("Ignore Update (External)", "obj", client.ObjectKeyFromObject(event.ObjectNew))
+
+				return false
+			}
+
 			reconciler.Info("** Enqueue (External)",
 				"Request", "Update",
 				"kind", reflect.TypeOf(event.ObjectNew),
@@ -19,7 +19,6 @@ package watchers
 import (
 	"fmt"
 	"reflect"
-	"time"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/carv-ics-forth/frisbee/controllers/common"
@@ -119,6 +118,12 @@ func (w *watchWithRangeAnnotator) watchUpdate(reconciler common.Reconciler, gvk
 
 		if !prevOK || !latestOK {
 			// this may happen for external objects like Pods, Faults, etc.
+			if !externalChanged(event.ObjectOld, event.ObjectNew) {
+				reconciler.Info("Ignore Update (External)", "obj", client.ObjectKeyFromObject(event.ObjectNew))
+
+				return false
+			}
+
 			reconciler.Info("** Enqueue (External)",
 				"Request", "Update",
 				"kind", reflect.TypeOf(event.ObjectNew),
@@ -162,7 +167,7 @@ func (w *watchWithRangeAnnotator) watchUpdate(reconciler common.Reconciler, gvk
 
 				// set failure-detection time
 				// Perhaps we can use the state transition time.
-				failureTime := time.Now()
+				failureTime := reconciler.Now()
 
 				// push annotation to grafana
 				grafana.AnnotatePointInTime(event.ObjectNew, failureTime, tags)
@@ -219,7 +224,7 @@ func (w *watchWithRangeAnnotator) watchDelete(reconciler common.Reconciler, gvk
 
 			// set time range
 			timeStart := event.Object.GetCreationTimestamp().Time
-			timeEnd := time.Now()
+			timeEnd := reconciler.Now()
 
 			if !event.Object.GetDeletionTimestamp().IsZero() {
 				timeEnd = event.Object.GetDeletionTimestamp().Time
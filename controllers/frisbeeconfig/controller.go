@@ -0,0 +1,181 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frisbeeconfig
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/pkg/clock"
+	"github.com/carv-ics-forth/frisbee/pkg/configuration"
+	"github.com/carv-ics-forth/frisbee/pkg/logging"
+	"github.com/carv-ics-forth/frisbee/pkg/tracing"
+	"github.com/go-logr/logr"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=frisbee.dev,resources=frisbeeconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=frisbee.dev,resources=frisbeeconfigs/status,verbs=get;update;patch
+
+// Controller applies the FrisbeeConfig to the running operator. Invalid configurations are rejected, and the
+// previous configuration remains in use.
+type Controller struct {
+	ctrl.Manager
+	logr.Logger
+	clock.Clock
+}
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracing.StartReconcile(ctx, "FrisbeeConfig", req.NamespacedName)
+	defer span.End()
+
+	/*
+		1: Load CR by name.
+		------------------------------------------------------------------
+	*/
+	var config v1alpha1.FrisbeeConfig
+
+	if err := r.GetClient().Get(ctx, req.NamespacedName, &config); err != nil {
+		if !k8errors.IsNotFound(err) {
+			r.Error(err, "obj retrieval")
+
+			return common.RequeueAfter(r, req, time.Second)
+		}
+
+		if req.Name != v1alpha1.FrisbeeConfigName {
+			return common.Stop(r, req)
+		}
+
+		// The configuration is deleted. Fall back to the legacy ConfigMap, if any.
+		sysconf, err := configuration.Get(ctx, r.GetClient(), r.Logger)
+		if err != nil {
+			r.Logger.Info("!! Keep the current configuration", "obj", req.NamespacedName, "reason", err.Error())
+
+			return common.Stop(r, req)
+		}
+
+		r.apply(sysconf)
+
+		return common.Stop(r, req)
+	}
+
+	if config.Status.ObservedGeneration == config.GetGeneration() {
+		return common.Stop(r, req)
+	}
+
+	/*
+		2: Apply the configuration, if it is valid.
+		------------------------------------------------------------------
+	*/
+	cond := metav1.Condition{
+		Type:    v1alpha1.ConditionConfigApplied.String(),
+		Status:  metav1.ConditionTrue,
+		Reason:  v1alpha1.ReasonConfigApplied,
+		Message: "The configuration is used by the operator",
+	}
+
+	sysconf := configuration.FromSpec(config.Spec)
+
+	switch {
+	case config.GetName() != v1alpha1.FrisbeeConfigName:
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = v1alpha1.ReasonConfigIgnored
+		cond.Message = fmt.Sprintf("The operator uses only the FrisbeeConfig named '%s'", v1alpha1.FrisbeeConfigName)
+
+	default:
+		if err := sysconf.Validate(); err != nil {
+			cond.Status = metav1.ConditionFalse
+			cond.Reason = v1alpha1.ReasonConfigInvalid
+			cond.Message = err.Error()
+
+			break
+		}
+
+		r.apply(sysconf)
+
+		r.Logger.Info("LoadGlobalConf", "config", config.GetName(), "parameters", sysconf)
+	}
+
+	/*
+		3: Update the CR status.
+		------------------------------------------------------------------
+	*/
+	meta.SetStatusCondition(&config.Status.Conditions, cond)
+	config.Status.ObservedGeneration = config.GetGeneration()
+
+	if err := r.GetClient().Status().Update(ctx, &config); err != nil {
+		return common.RequeueAfter(r, req, time.Second)
+	}
+
+	return common.Stop(r, req)
+}
+
+// apply replaces the configuration of the controllers, and the level of the operator. The configuration is
+// expected to be valid.
+func (r *Controller) apply(sysconf configuration.Configuration) {
+	configuration.SetGlobal(sysconf)
+
+	if err := logging.SetLevel(sysconf.LogLevel); err != nil {
+		r.Logger.Error(err, "cannot change the log level")
+	}
+}
+
+/*
+### Finalizers
+*/
+
+func (r *Controller) Finalizer() string {
+	return ""
+}
+
+func (r *Controller) Finalize(obj client.Object) error {
+	r.Logger.Info("XX Finalize",
+		"kind", reflect.TypeOf(obj),
+		"name", obj.GetName(),
+		"version", obj.GetResourceVersion(),
+	)
+
+	return nil
+}
+
+/*
+### Setup
+	Finally, we'll update our setup.
+*/
+
+func NewController(mgr ctrl.Manager, logger logr.Logger, clk clock.Clock) error {
+	var config v1alpha1.FrisbeeConfig
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&config).
+		Named("frisbeeconfig").
+		Complete(&Controller{
+			Manager: mgr,
+			Logger:  logger.WithName("frisbeeconfig"),
+			Clock:   clk,
+		})
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario
+
+import (
+	"context"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/pkg/expressions"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// IsAbortRequested returns true if the user has requested to abort a scenario that is not yet completed.
+func IsAbortRequested(scenario *v1alpha1.Scenario) bool {
+	if scenario.Spec.Abort == nil || !*scenario.Spec.Abort {
+		return false
+	}
+
+	return !scenario.Status.Phase.Is(v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed)
+}
+
+// Abort gracefully stops the scenario. The abort spans multiple reconciliation cycles:
+//  1. The scenario transitions to the Aborted phase, and the teardown actions are submitted.
+//  2. The controller waits for the teardown actions to complete.
+//  3. The remaining jobs are removed, and the scenario is suspended.
+func (r *Controller) Abort(ctx context.Context, req ctrl.Request, scenario *v1alpha1.Scenario) (ctrl.Result, error) {
+	/*---------------------------------------------------
+	 * Transition to Aborted and run the Teardown actions
+	 *---------------------------------------------------*/
+	if !scenario.Status.Phase.Is(v1alpha1.PhaseAborted) {
+		msg := "The scenario is aborted by the user"
+
+		scenario.Status.Lifecycle.Phase = v1alpha1.PhaseAborted
+		scenario.Status.Lifecycle.Reason = v1alpha1.ReasonAborted
+		scenario.Status.Lifecycle.Message = msg
+
+		meta.SetStatusCondition(&scenario.Status.Lifecycle.Conditions, metav1.Condition{
+			Type:    v1alpha1.ConditionAborted.String(),
+			Status:  metav1.ConditionTrue,
+			Reason:  v1alpha1.ReasonAborted,
+			Message: msg,
+		})
+
+		for _, action := range scenario.Spec.Teardown {
+			// Teardown is best-effort. A broken teardown action should not block the removal of the jobs.
+			if err := r.RunAction(ctx, scenario, action); err != nil {
+				r.GetEventRecorderFor(scenario.GetName()).Event(scenario, corev1.EventTypeWarning,
+					"TeardownError", err.Error())
+
+				continue
+			}
+
+			scenario.Status.TeardownJobs = append(scenario.Status.TeardownJobs, action.Name)
+		}
+
+		r.GetEventRecorderFor(scenario.GetName()).Event(scenario, corev1.EventTypeNormal, v1alpha1.ReasonAborted, msg)
+
+		if err := common.UpdateStatus(ctx, r, scenario); err != nil {
+			return common.RequeueAfter(r, req, time.Second)
+		}
+
+		return common.Stop(r, req)
+	}
+
+	// The abort is already handled.
+	if scenario.Spec.Suspend != nil && *scenario.Spec.Suspend {
+		return common.Stop(r, req)
+	}
+
+	/*---------------------------------------------------
+	 * Wait for the Teardown actions to complete
+	 *---------------------------------------------------*/
+	for _, jobName := range scenario.Status.TeardownJobs {
+		if !r.view.IsSuccessful(jobName) && !r.view.IsFailed(jobName) {
+			r.Logger.Info("Waiting for teardown", "obj", client.ObjectKeyFromObject(scenario), "job", jobName)
+
+			return common.Stop(r, req)
+		}
+	}
+
+	/*---------------------------------------------------
+	 * Remove the remaining jobs
+	 *---------------------------------------------------*/
+	r.Logger.Info("CleanOnAbort",
+		"obj", client.ObjectKeyFromObject(scenario).String(),
+		"teardownJobs", scenario.Status.TeardownJobs,
+	)
+
+	for _, job := range r.view.GetPendingJobs() {
+		expressions.UnsetAlert(ctx, job)
+		common.Delete(ctx, r, job)
+	}
+
+	for _, job := range r.view.GetRunningJobs() {
+		expressions.UnsetAlert(ctx, job)
+		common.Delete(ctx, r, job)
+	}
+
+	for _, job := range r.view.GetSuccessfulJobs() {
+		expressions.UnsetAlert(ctx, job)
+	}
+
+	r.RemoveNetworkProfile(ctx, scenario)
+
+	// Suspend the workflow from creating new job.
+	suspend := true
+	scenario.Spec.Suspend = &suspend
+
+	// Update is needed since we modify the spec.suspend
+	if err := common.Update(ctx, r, scenario); err != nil {
+		return common.RequeueAfter(r, req, time.Second)
+	}
+
+	return common.Stop(r, req)
+}
@@ -23,9 +23,9 @@ import (
 	"os"
 	"time"
 
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/carv-ics-forth/frisbee/controllers/common"
 	"github.com/carv-ics-forth/frisbee/pkg/expressions"
-	notifier "github.com/golanghelper/grafana-webhook"
 	"github.com/pkg/errors"
 )
 
@@ -42,11 +42,10 @@ func NewAlertingProxy(ctx context.Context, r *Controller) error {
 	 *---------------------------------------------------*/
 	webhook := http.DefaultServeMux
 
-	webhook.Handle("/", notifier.HandleWebhook(func(w http.ResponseWriter, b *notifier.Body) {
-		if err := expressions.DispatchAlert(ctx, r, b); err != nil {
-			r.Logger.Error(err, "Drop alert", "body", b)
-		}
-	}, 0))
+	webhook.Handle("/", expressions.NewAlertDispatcher(r).HandleWebhook(ctx))
+
+	// Register the callbacks of the Trigger actions.
+	webhook.Handle(v1alpha1.TriggerPath, r.triggers.HandleWebhook())
 
 	/*---------------------------------------------------*
 	 * Start the Alerting Proxy Server
@@ -0,0 +1,255 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	scenarioutils "github.com/carv-ics-forth/frisbee/controllers/scenario/utils"
+	serviceutils "github.com/carv-ics-forth/frisbee/controllers/service/utils"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+/*
+	The budget of the scenario is enforced by a ResourceQuota in the namespace of the scenario. However, the quota
+	rejects the Pods that exceed it, which would fail the respective Services. Instead, the requests of the actions
+	are estimated before the actions are scheduled, and the actions that do not fit into the remaining budget are
+	queued. A LimitRange assigns default requests to the containers that do not request resources, so that every
+	Pod is accounted in the budget.
+*/
+
+// BudgetRetryInterval is the interval for checking whether the queued actions fit into the budget.
+const BudgetRetryInterval = 10 * time.Second
+
+// budgetName is the name of the ResourceQuota and of the LimitRange of the scenario.
+func budgetName(scenario *v1alpha1.Scenario) string {
+	return scenario.GetName() + "-budget"
+}
+
+// CreateBudget creates the ResourceQuota and the LimitRange that enforce the budget of the scenario.
+func (r *Controller) CreateBudget(ctx context.Context, scenario *v1alpha1.Scenario) error {
+	budget := scenario.Spec.Resources
+
+	// the defaults must precede the quota, or the Pods without requests are rejected.
+	var limitRange corev1.LimitRange
+
+	limitRange.SetName(budgetName(scenario))
+	limitRange.Spec.Limits = []corev1.LimitRangeItem{{
+		Type:           corev1.LimitTypeContainer,
+		DefaultRequest: budget.GetDefaultRequests(),
+	}}
+
+	if err := common.Create(ctx, r, scenario, &limitRange); err != nil {
+		return errors.Wrapf(err, "cannot create limit range")
+	}
+
+	var quota corev1.ResourceQuota
+
+	quota.SetName(budgetName(scenario))
+	quota.Spec.Hard = scenarioutils.QuotaLimits(budget.Limits())
+
+	if err := common.Create(ctx, r, scenario, &quota); err != nil {
+		return errors.Wrapf(err, "cannot create resource quota")
+	}
+
+	return nil
+}
+
+// admitByBudget returns the actions that fit into the remaining budget, in the order of scheduling. Once an action
+// does not fit, it is queued along with the actions that follow it. An action that does not fit even into an
+// unused budget can never be scheduled, and it is returned as error. It also returns true if the conditions of
+// the scenario have been changed.
+func (r *Controller) admitByBudget(ctx context.Context, scenario *v1alpha1.Scenario, actions []v1alpha1.Action) ([]v1alpha1.Action, bool, error) {
+	budget := scenario.Spec.Resources
+	if budget == nil {
+		return actions, false, nil
+	}
+
+	limits := budget.Limits()
+
+	used, err := r.budgetUsage(ctx, scenario)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "cannot calculate the used budget")
+	}
+
+	for i, action := range actions {
+		requests, err := r.actionRequests(ctx, scenario, action)
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "cannot estimate the requests of action '%s'", action.Name)
+		}
+
+		if exceeded := scenarioutils.Exceeded(limits, nil, requests); len(exceeded) > 0 {
+			return nil, false, errors.Errorf("action '%s' requests '%s', which exceeds the budget '%s'", action.Name,
+				scenarioutils.FormatRequests(requests, exceeded), scenarioutils.FormatRequests(limits, exceeded))
+		}
+
+		if exceeded := scenarioutils.Exceeded(limits, used, requests); len(exceeded) > 0 {
+			changed := !meta.IsStatusConditionTrue(scenario.Status.Conditions, v1alpha1.ConditionBudgetExceeded.String())
+
+			meta.SetStatusCondition(&scenario.Status.Conditions, metav1.Condition{
+				Type:   v1alpha1.ConditionBudgetExceeded.String(),
+				Status: metav1.ConditionTrue,
+				Reason: v1alpha1.ReasonInsufficientBudget,
+				Message: fmt.Sprintf("action '%s' requests '%s'. used: '%s'. budget: '%s'", action.Name,
+					scenarioutils.FormatRequests(requests, exceeded),
+					scenarioutils.FormatRequests(used, exceeded),
+					scenarioutils.FormatRequests(limits, exceeded)),
+			})
+
+			return actions[:i], changed, nil
+		}
+
+		scenarioutils.AddRequests(used, requests)
+	}
+
+	if !meta.IsStatusConditionTrue(scenario.Status.Conditions, v1alpha1.ConditionBudgetExceeded.String()) {
+		return actions, false, nil
+	}
+
+	meta.SetStatusCondition(&scenario.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.ConditionBudgetExceeded.String(),
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.ReasonWithinBudget,
+		Message: fmt.Sprintf("used: '%s'", scenarioutils.FormatRequests(used, budgetNames(limits))),
+	})
+
+	return actions, true, nil
+}
+
+// budgetUsage returns the resources that are used by the scenario. The ResourceQuota reports only the Pods that
+// exist, and therefore the usage is complemented by the requests of the active jobs, whose Pods may not have been
+// created yet.
+func (r *Controller) budgetUsage(ctx context.Context, scenario *v1alpha1.Scenario) (corev1.ResourceList, error) {
+	reserved := corev1.ResourceList{}
+	defaults := scenario.Spec.Resources.GetDefaultRequests()
+
+	var activeJobs []client.Object
+
+	activeJobs = append(activeJobs, r.view.GetPendingJobs()...)
+	activeJobs = append(activeJobs, r.view.GetRunningJobs()...)
+
+	for _, job := range activeJobs {
+		switch job := job.(type) {
+		case *v1alpha1.Service:
+			scenarioutils.AddRequests(reserved, scenarioutils.ServiceRequests(&job.Spec, defaults))
+
+		case *v1alpha1.Cluster:
+			requests, err := r.clusterRequests(ctx, scenario, &job.Spec)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cluster '%s'", job.GetName())
+			}
+
+			scenarioutils.AddRequests(reserved, requests)
+		}
+	}
+
+	var quota corev1.ResourceQuota
+
+	key := client.ObjectKey{Namespace: scenario.GetNamespace(), Name: budgetName(scenario)}
+
+	if err := r.GetClient().Get(ctx, key, &quota); client.IgnoreNotFound(err) != nil {
+		return nil, errors.Wrapf(err, "cannot get resource quota")
+	}
+
+	// keep the largest of the two estimations.
+	for name, quantity := range scenarioutils.QuotaUsage(&quota) {
+		if current, ok := reserved[name]; !ok || quantity.Cmp(current) > 0 {
+			reserved[name] = quantity
+		}
+	}
+
+	return reserved, nil
+}
+
+// actionRequests estimates the resources requested by the action. Only Services and Clusters create Pods that are
+// accounted in the budget.
+func (r *Controller) actionRequests(ctx context.Context, scenario *v1alpha1.Scenario, action v1alpha1.Action) (corev1.ResourceList, error) {
+	switch action.ActionType {
+	case v1alpha1.ActionService:
+		job, err := r.service(ctx, scenario, action)
+		if err != nil {
+			return nil, err
+		}
+
+		return scenarioutils.ServiceRequests(&job.Spec, scenario.Spec.Resources.GetDefaultRequests()), nil
+
+	case v1alpha1.ActionCluster:
+		return r.clusterRequests(ctx, scenario, action.Cluster)
+
+	default:
+		return corev1.ResourceList{}, nil
+	}
+}
+
+// clusterRequests estimates the resources requested by all the services of the cluster.
+func (r *Controller) clusterRequests(ctx context.Context, scenario *v1alpha1.Scenario, cluster *v1alpha1.ClusterSpec) (corev1.ResourceList, error) {
+	templates := []v1alpha1.GenerateObjectFromTemplate{cluster.GenerateObjectFromTemplate}
+
+	if len(cluster.Variants) > 0 {
+		variants, err := cluster.VariantTemplates()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid variants")
+		}
+
+		templates = variants
+	}
+
+	defaults := scenario.Spec.Resources.GetDefaultRequests()
+	total := corev1.ResourceList{}
+
+	for _, template := range templates {
+		specs, err := serviceutils.GetServiceSpecList(ctx, r.GetClient(), scenario, template)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot retrieve job specs")
+		}
+
+		for i := range specs {
+			scenarioutils.AddRequests(total, scenarioutils.ServiceRequests(&specs[i], defaults))
+		}
+	}
+
+	// the distributed resources replace the requests of the templates.
+	if cluster.Resources != nil {
+		for name, quantity := range cluster.Resources.TotalResources {
+			total[name] = quantity
+		}
+	}
+
+	return total, nil
+}
+
+// budgetNames returns the names of the resources in the list.
+func budgetNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+
+	for name := range list {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+
+	return names
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	scenarioutils "github.com/carv-ics-forth/frisbee/controllers/scenario/utils"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SubmitNext creates the scenario that follows the completion of the given scenario.
+// The next scenario runs in the same namespace, and is named after the template and its position in the chain.
+// Therefore, repeated invocations will not create duplicate scenarios.
+func (r *Controller) SubmitNext(ctx context.Context, scenario *v1alpha1.Scenario, fromTemplate *v1alpha1.GenerateObjectFromTemplate) error {
+	if fromTemplate == nil || scenario.Status.NextScenario != "" {
+		return nil
+	}
+
+	// Loop protection
+	depth := v1alpha1.GetChainDepth(scenario) + 1
+
+	maxDepth := v1alpha1.DefaultMaxChainDepth
+	if scenario.Spec.Next.MaxDepth != nil {
+		maxDepth = *scenario.Spec.Next.MaxDepth
+	}
+
+	if depth > maxDepth {
+		r.GetEventRecorderFor(scenario.GetName()).Event(scenario, corev1.EventTypeWarning, "ChainLimit",
+			fmt.Sprintf("Skip '%s'. Chain has reached the maximum depth '%d'", fromTemplate.TemplateRef, maxDepth))
+
+		return nil
+	}
+
+	spec, err := scenarioutils.GetScenarioSpec(ctx, r.GetClient(), scenario, *fromTemplate)
+	if err != nil {
+		return errors.Wrapf(err, "cannot retrieve scenario spec")
+	}
+
+	var next v1alpha1.Scenario
+
+	// Metadata
+	next.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("Scenario"))
+	next.SetNamespace(scenario.GetNamespace())
+	next.SetName(fmt.Sprintf("%s-%d", fromTemplate.TemplateRef, depth))
+
+	v1alpha1.SetChainDepth(&next.ObjectMeta, depth)
+
+	// Spec
+	spec.DeepCopyInto(&next.Spec)
+
+	if err := common.Create(ctx, r, scenario, &next); err != nil {
+		return errors.Wrapf(err, "cannot submit scenario '%s'", next.GetName())
+	}
+
+	r.Logger.Info("Chain",
+		"obj", client.ObjectKeyFromObject(scenario),
+		"next", next.GetName(),
+		"depth", depth,
+	)
+
+	r.GetEventRecorderFor(scenario.GetName()).Event(scenario, corev1.EventTypeNormal, "Chained", next.GetName())
+
+	scenario.Status.NextScenario = next.GetName()
+
+	return common.UpdateStatus(ctx, r, scenario)
+}
@@ -26,9 +26,12 @@ import (
 	"github.com/carv-ics-forth/frisbee/controllers/common"
 	"github.com/carv-ics-forth/frisbee/controllers/common/watchers"
 	scenarioutils "github.com/carv-ics-forth/frisbee/controllers/scenario/utils"
+	"github.com/carv-ics-forth/frisbee/pkg/clock"
 	"github.com/carv-ics-forth/frisbee/pkg/configuration"
 	"github.com/carv-ics-forth/frisbee/pkg/expressions"
 	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	"github.com/carv-ics-forth/frisbee/pkg/logging"
+	"github.com/carv-ics-forth/frisbee/pkg/tracing"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -37,6 +40,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 )
 
 // +kubebuilder:rbac:groups=frisbee.dev,resources=scenarios,verbs=get;list;watch;create;update;patch;delete
@@ -51,19 +55,36 @@ import (
 // +kubebuilder:rbac:groups=core,resources=configmaps/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core,resources=configmaps/finalizers,verbs=update
 
+// +kubebuilder:rbac:groups=core,resources=limitranges,verbs=get;list;watch;create
+
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch;create;delete
+
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=nodes/status,verbs=get
 
+// +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create
+
+// +kubebuilder:rbac:groups=core,resources=resourcequotas,verbs=get;list;watch;create
+
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;delete
+
 type Controller struct {
 	ctrl.Manager
 	logr.Logger
+	clock.Clock
 
 	view *lifecycle.Classifier
 
 	alertingProxy string
+
+	triggers *scenarioutils.TriggerDispatcher
 }
 
 func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracing.StartReconcile(ctx, "Scenario", req.NamespacedName)
+	defer span.End()
+
 	/*
 		1: Load CR by name and extract the Desired State
 		------------------------------------------------------------------
@@ -99,6 +120,33 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return lifecycle.Failed(ctx, r, &scenario, errors.Wrapf(err, "cannot populate view for '%s'", req))
 	}
 
+	debug := r.debugLogger(&scenario)
+
+	debug.Info("View",
+		"jobs", r.view.ListAll(),
+		"scheduled", scenario.Status.ScheduledJobs,
+		"conditions", scenario.Status.Conditions,
+	)
+
+	/* Check the health of the telemetry stack, and repair any crashed component. Until the stack is healthy, the
+	actions are held back, since they may push annotations and alerts to Grafana. */
+	if scenario.Status.Phase.Is(v1alpha1.PhasePending, v1alpha1.PhaseRunning) && !IsAbortRequested(&scenario) {
+		healthy, err := r.CheckTelemetry(ctx, &scenario)
+		if err != nil {
+			return lifecycle.Failed(ctx, r, &scenario, errors.Wrapf(err, "telemetry error"))
+		}
+
+		if !healthy {
+			debug.Info("Hold back the actions until the telemetry is healthy")
+
+			if err := common.UpdateStatus(ctx, r, &scenario); err != nil {
+				return common.RequeueAfter(r, req, time.Second)
+			}
+
+			return common.RequeueAfter(r, req, TelemetryRetryInterval)
+		}
+	}
+
 	/* Check if all the SYS services are running. If they are terminated (Failed/Success), we have nothing else to do,
 	and we abort the experiment. If they are still being created (Uninitialized, Pending), we sleep and retry */
 	if abort, sysErr := r.view.SystemState(); sysErr != nil {
@@ -107,6 +155,8 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 
 		// Just stop, waiting for system services to come up.
+		debug.Info("Wait for the system services", "reason", sysErr.Error())
+
 		return common.Stop(r, req)
 	}
 
@@ -129,6 +179,12 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		------------------------------------------------------------------
 	*/
 
+	// If this object is aborted, we run the teardown actions and then remove the remaining jobs.
+	// The abort precedes the suspension, because it spans multiple reconciliation cycles.
+	if IsAbortRequested(&scenario) {
+		return r.Abort(ctx, req, &scenario)
+	}
+
 	// If this object is suspended, we don't want to run any jobs, so we'll stop now.
 	// This is useful if something's broken with the job we're running, and we want to
 	// pause runs to investigate the cluster, without deleting the object.
@@ -140,6 +196,20 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	// This label will be adopted by all children objects of this workflow.
 	v1alpha1.SetScenarioLabel(&scenario.ObjectMeta, scenario.GetName())
 
+	// Emulate the network conditions between the groups that are already running.
+	if scenario.Status.Phase.Is(v1alpha1.PhasePending, v1alpha1.PhaseRunning) {
+		applied, err := r.ApplyNetworkProfile(ctx, &scenario)
+		if err != nil {
+			return lifecycle.Failed(ctx, r, &scenario, errors.Wrapf(err, "network profile error"))
+		}
+
+		if applied {
+			if err := common.UpdateStatus(ctx, r, &scenario); err != nil {
+				return common.RequeueAfter(r, req, time.Second)
+			}
+		}
+	}
+
 	switch scenario.Status.Phase {
 	case v1alpha1.PhaseUninitialized:
 		if err := r.Initialize(ctx, &scenario); err != nil {
@@ -147,7 +217,7 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 
 		// We could use common.Stop() to simply wait, but we need update status because Initialize()
-		// sets the endpoints, and we want to maintain this information for connectToGrafana().
+		// sets the endpoints, and we want to maintain this information for CheckTelemetry().
 		return lifecycle.Pending(ctx, r, &scenario, "Initializing the testing environment")
 
 	case v1alpha1.PhasePending:
@@ -156,15 +226,42 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			return lifecycle.Failed(ctx, r, &scenario, errors.Wrapf(err, "scheduling error"))
 		}
 
+		debug.Info("NextJobs", "actions", actionNames(nextActionList), "nextRun", nextRun)
+
 		if len(nextActionList) == 0 {
 			if nextRun.IsZero() {
 				// nothing to do on this cycle. wait the next cycle trigger by watchers.
-				return common.Stop(r, req)
+				return r.stopOrPoll(req, &scenario)
 			}
 
-			return common.RequeueAfter(r, req, time.Until(nextRun))
+			// wake up earlier, if an action in progress times out before the next run.
+			if deadline := nextTimeout(&scenario); !deadline.IsZero() && deadline.Before(nextRun) {
+				nextRun = deadline
+			}
+
+			return common.RequeueAfter(r, req, r.Until(nextRun))
 		}
 
+		// Queue the actions that do not fit into the remaining budget.
+		admitted, changed, err := r.admitByBudget(ctx, &scenario, nextActionList)
+		if err != nil {
+			return lifecycle.Failed(ctx, r, &scenario, errors.Wrapf(err, "budget error"))
+		}
+
+		if len(admitted) == 0 {
+			if changed {
+				if err := common.UpdateStatus(ctx, r, &scenario); err != nil {
+					return common.RequeueAfter(r, req, time.Second)
+				}
+			}
+
+			debug.Info("Queue actions until the budget is released", "actions", actionNames(nextActionList))
+
+			return common.RequeueAfter(r, req, BudgetRetryInterval)
+		}
+
+		nextActionList = admitted
+
 		if err := r.RunActions(ctx, &scenario, nextActionList); err != nil {
 			return lifecycle.Failed(ctx, r, &scenario, errors.Wrapf(err, "actions failed"))
 		}
@@ -174,7 +271,7 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	case v1alpha1.PhaseRunning:
 		// Nothing to do. Just wait for something to happen.
-		return common.Stop(r, req)
+		return r.stopOrPoll(req, &scenario)
 
 	case v1alpha1.PhaseSuccess:
 		if err := r.HasSucceed(ctx, &scenario); err != nil {
@@ -189,26 +286,49 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 
 		return common.Stop(r, req)
+
+	case v1alpha1.PhaseAborted:
+		// Nothing to do. The abort is handled by Abort().
+		return common.Stop(r, req)
 	}
 
 	panic(errors.New("This should never happen"))
 }
 
 func (r *Controller) Initialize(ctx context.Context, scenario *v1alpha1.Scenario) error {
-	/* Clone system configuration, needed to retrieve telemetry, chaos, etc  */
+	/* Refresh system configuration, needed to retrieve telemetry, chaos, etc. The FrisbeeConfig is also applied
+	by its own controller, but the legacy ConfigMap is not watched. */
 	sysconf, err := configuration.Get(ctx, r.GetClient(), r.Logger)
-	if err != nil {
+	switch {
+	case err == nil:
+		configuration.SetGlobal(sysconf)
+	case configuration.Global().Validate() == nil:
+		// an invalid update does not replace a valid configuration.
+		r.Logger.Info("!! Keep the current configuration", "reason", err.Error())
+	default:
 		return errors.Wrapf(err, "cannot get system configuration")
 	}
 
-	/* FIXME: we set the configuration be global here. is there any better way ? */
-	configuration.SetGlobal(sysconf)
+	// resolve the run metadata before any child is created, so that the children are labeled with the run.
+	scenario.Status.Run = v1alpha1.ResolveRun(scenario)
+
+	// fail early if the propagated metadata cannot be evaluated.
+	if _, _, err := scenario.Spec.Propagation.Render(scenario); err != nil {
+		return errors.Wrapf(err, "propagation error")
+	}
 
 	// load the templates required by the scenario.
 	if errValidate := scenarioutils.LoadTemplates(ctx, r.GetClient(), scenario); errValidate != nil {
 		return errors.Wrapf(errValidate, "template error")
 	}
 
+	// Bound the resources of the scenario, before any Pod is created.
+	if scenario.Spec.Resources != nil {
+		if errBudget := r.CreateBudget(ctx, scenario); errBudget != nil {
+			return errors.Wrapf(errBudget, "budget error")
+		}
+	}
+
 	// Start Prometheus + Grafana
 	if errTelemetry := r.StartTelemetry(ctx, scenario); errTelemetry != nil {
 		return errors.Wrapf(errTelemetry, "telemetry error")
@@ -219,7 +339,7 @@ func (r *Controller) Initialize(ctx context.Context, scenario *v1alpha1.Scenario
 	meta.SetStatusCondition(&scenario.Status.Conditions, metav1.Condition{
 		Type:    v1alpha1.ConditionCRInitialized.String(),
 		Status:  metav1.ConditionTrue,
-		Reason:  "Initialized",
+		Reason:  v1alpha1.ReasonInitialized,
 		Message: "Start Scheduling Jobs",
 	})
 
@@ -235,7 +355,7 @@ func (r *Controller) PopulateView(ctx context.Context, req types.NamespacedName)
 
 	var serviceJobs v1alpha1.ServiceList
 	{
-		if err := common.ListChildren(ctx, r.GetClient(), &serviceJobs, req); err != nil {
+		if err := listChildren(ctx, r.GetClient(), &serviceJobs, req); err != nil {
 			return errors.Wrapf(err, "cannot list child services for '%s'", req)
 		}
 
@@ -246,7 +366,7 @@ func (r *Controller) PopulateView(ctx context.Context, req types.NamespacedName)
 
 	var clusterJobs v1alpha1.ClusterList
 	{
-		if err := common.ListChildren(ctx, r.GetClient(), &clusterJobs, req); err != nil {
+		if err := listChildren(ctx, r.GetClient(), &clusterJobs, req); err != nil {
 			return errors.Wrapf(err, "cannot list child clusters for '%s'", req)
 		}
 
@@ -257,7 +377,7 @@ func (r *Controller) PopulateView(ctx context.Context, req types.NamespacedName)
 
 	var chaosJobs v1alpha1.ChaosList
 	{
-		if err := common.ListChildren(ctx, r.GetClient(), &chaosJobs, req); err != nil {
+		if err := listChildren(ctx, r.GetClient(), &chaosJobs, req); err != nil {
 			return errors.Wrapf(err, "cannot list child chaos for '%s'", req)
 		}
 
@@ -268,7 +388,7 @@ func (r *Controller) PopulateView(ctx context.Context, req types.NamespacedName)
 
 	var cascadeJobs v1alpha1.CascadeList
 	{
-		if err := common.ListChildren(ctx, r.GetClient(), &cascadeJobs, req); err != nil {
+		if err := listChildren(ctx, r.GetClient(), &cascadeJobs, req); err != nil {
 			return errors.Wrapf(err, "cannot list child cascades for '%s'", req)
 		}
 
@@ -279,7 +399,7 @@ func (r *Controller) PopulateView(ctx context.Context, req types.NamespacedName)
 
 	var virtualJobs v1alpha1.VirtualObjectList
 	{
-		if err := common.ListChildren(ctx, r.GetClient(), &virtualJobs, req); err != nil {
+		if err := listChildren(ctx, r.GetClient(), &virtualJobs, req); err != nil {
 			return errors.Wrapf(err, "cannot list child virtualobjects for '%s'", req)
 		}
 
@@ -290,7 +410,7 @@ func (r *Controller) PopulateView(ctx context.Context, req types.NamespacedName)
 
 	var callJobs v1alpha1.CallList
 	{
-		if err := common.ListChildren(ctx, r.GetClient(), &callJobs, req); err != nil {
+		if err := listChildren(ctx, r.GetClient(), &callJobs, req); err != nil {
 			return errors.Wrapf(err, "cannot list child calls for '%s'", req)
 		}
 
@@ -319,10 +439,19 @@ func (r *Controller) HasSucceed(ctx context.Context, scenario *v1alpha1.Scenario
 		// common.Delete(ctx, r, job)
 	}
 
+	r.RemoveNetworkProfile(ctx, scenario)
+
 	if scenario.GetDeletionTimestamp().IsZero() {
 		r.GetEventRecorderFor(scenario.GetName()).Event(scenario, corev1.EventTypeNormal, "Completed", scenario.Status.Lifecycle.Message)
 	}
 
+	// Submit the next scenario in the chain, if any.
+	if next := scenario.Spec.Next; next != nil {
+		if err := r.SubmitNext(ctx, scenario, next.OnSuccess); err != nil {
+			return errors.Wrapf(err, "next.onSuccess")
+		}
+	}
+
 	return nil
 }
 
@@ -351,6 +480,15 @@ func (r *Controller) HasFailed(ctx context.Context, scenario *v1alpha1.Scenario)
 		// common.Delete(ctx, r, job) Keep it commented. It is useful to see which jobs are complete.
 	}
 
+	r.RemoveNetworkProfile(ctx, scenario)
+
+	// Submit the next scenario in the chain, if any.
+	if next := scenario.Spec.Next; next != nil {
+		if err := r.SubmitNext(ctx, scenario, next.OnFailure); err != nil {
+			return errors.Wrapf(err, "next.onFailure")
+		}
+	}
+
 	// Suspend the workflow from creating new job.
 	suspend := true
 	scenario.Spec.Suspend = &suspend
@@ -371,14 +509,6 @@ func (r *Controller) HasFailed(ctx context.Context, scenario *v1alpha1.Scenario)
 }
 
 func (r *Controller) RunActions(ctx context.Context, scenario *v1alpha1.Scenario, nextActionList []v1alpha1.Action) error {
-	if scenario.Status.GrafanaEndpoint == "" {
-		r.Logger.Info("Grafana endpoint is empty. Skip telemetry.", "scenario", scenario.GetName())
-	} else {
-		if err := r.connectToGrafana(ctx, scenario, r.alertingProxy); err != nil {
-			return errors.Wrapf(err, "connect to grafana")
-		}
-	}
-
 	for _, action := range nextActionList {
 		if action.Assert.HasMetricsExpr() {
 			// Assert belong to the top-level workflow. Not to the job
@@ -402,6 +532,13 @@ func (r *Controller) RunActions(ctx context.Context, scenario *v1alpha1.Scenario
 			So, we need to use the job name as a lock to prevent us from making the job twice.
 		*/
 		scenario.Status.ScheduledJobs = append(scenario.Status.ScheduledJobs, action.Name)
+
+		scenario.Status.Actions = append(scenario.Status.Actions, v1alpha1.ActionStatus{
+			Name:       action.Name,
+			ActionType: action.ActionType,
+			Phase:      v1alpha1.PhasePending,
+			StartTime:  &metav1.Time{Time: r.Now()},
+		})
 	}
 
 	return nil
@@ -425,6 +562,11 @@ func (r *Controller) Finalize(obj client.Object) error {
 	// Remove idle Grafana clients
 	r.StopTelemetry(obj.(*v1alpha1.Scenario))
 
+	// Remove the namespaces of the actions. They are not owned by the scenario, and are not garbage-collected.
+	if scenario := obj.(*v1alpha1.Scenario); scenario.Spec.IsMultiNamespace() {
+		return r.deleteActionNamespaces(context.Background(), scenario)
+	}
+
 	return nil
 }
 
@@ -437,12 +579,36 @@ func (r *Controller) Finalize(obj client.Object) error {
 	deleted, etc.
 */
 
-func NewController(mgr ctrl.Manager, logger logr.Logger) error {
+// debugLogger returns a logger that ignores the level of the operator, if the scenario is debugged (see
+// FrisbeeConfig.DebugScenarios). Otherwise, it returns a logger that discards the messages.
+func (r *Controller) debugLogger(scenario *v1alpha1.Scenario) logr.Logger {
+	key := client.ObjectKeyFromObject(scenario)
+
+	if !configuration.Global().IsDebugScenario(key) {
+		return logr.Discard()
+	}
+
+	return logging.DebugLogger().WithName("scenario").WithValues("obj", key)
+}
+
+func actionNames(actions []v1alpha1.Action) []string {
+	names := make([]string, 0, len(actions))
+
+	for _, action := range actions {
+		names = append(names, action.Name)
+	}
+
+	return names
+}
+
+func NewController(mgr ctrl.Manager, logger logr.Logger, clk clock.Clock) error {
 	// instantiate the controller
 	controller := &Controller{
-		Manager: mgr,
-		Logger:  logger.WithName("scenario"),
-		view:    &lifecycle.Classifier{},
+		Manager:  mgr,
+		Logger:   logger.WithName("scenario"),
+		Clock:    clk,
+		view:     &lifecycle.Classifier{},
+		triggers: scenarioutils.NewTriggerDispatcher(),
 	}
 
 	// initiate the alerting service
@@ -462,5 +628,10 @@ func NewController(mgr ctrl.Manager, logger logr.Logger) error {
 		Owns(&v1alpha1.Cascade{}, watchers.Watch(controller, gvk)).                    // Logs Cascade
 		Owns(&v1alpha1.VirtualObject{}, watchers.Watch(controller, gvk)).              // Logs VirtualObjects
 		Owns(&v1alpha1.Call{}, watchers.Watch(controller, gvk)).                       // Logs Calls
+		// Jobs in the namespaces of their actions.
+		Watches(&v1alpha1.Service{}, handler.EnqueueRequestsFromMapFunc(enqueueParentScenario),
+			watchers.WatchWithPointAnnotation(controller, gvk)).
+		Watches(&v1alpha1.Cluster{}, handler.EnqueueRequestsFromMapFunc(enqueueParentScenario),
+			watchers.Watch(controller, gvk)).
 		Complete(controller)
 }
@@ -0,0 +1,152 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+/*
+	Action hooks are HTTP endpoints, configured on the operator, that are invoked around every action of a scenario.
+	They allow organizations to plug policy checks, inventory systems, or custom notifications, without modifying
+	the controllers.
+
+	PreAction hooks are invoked synchronously, with the rendered job, before the job is created. A response other than
+	2xx denies the action, and the scenario fails with the body of the response as the reason. Unreachable hooks
+	deny the action as well.
+
+	PostAction hooks are invoked asynchronously, with the result of the action, when the action is completed. Their
+	response is ignored. Because the result is delivered before the status is persisted, a hook may receive the same
+	result more than once.
+*/
+
+const (
+	// PreActionHookEnv is the environment variable with the endpoint of the PreAction hook.
+	PreActionHookEnv = "FRISBEE_PRE_ACTION_HOOK"
+
+	// PostActionHookEnv is the environment variable with the endpoint of the PostAction hook.
+	PostActionHookEnv = "FRISBEE_POST_ACTION_HOOK"
+)
+
+// ActionHookTimeout bounds the time spent for calling a single hook.
+const ActionHookTimeout = 10 * time.Second
+
+type ActionHookEvent string
+
+const (
+	PreAction  ActionHookEvent = "PreAction"
+	PostAction ActionHookEvent = "PostAction"
+)
+
+// ActionHookRequest is the payload sent to the action hooks.
+type ActionHookRequest struct {
+	Event     ActionHookEvent `json:"event"`
+	Scenario  string          `json:"scenario"`
+	Namespace string          `json:"namespace"`
+
+	// Action is the action as defined in the scenario.
+	Action v1alpha1.Action `json:"action"`
+
+	// Object is the rendered job of the action. It is set only for PreAction hooks, and only for actions that create
+	// a job.
+	Object client.Object `json:"object,omitempty"`
+
+	// Result is the outcome of the action. It is set only for PostAction hooks.
+	Result *v1alpha1.ActionStatus `json:"result,omitempty"`
+}
+
+// callPreActionHook asks the PreAction hook whether the action is allowed to run.
+func (r *Controller) callPreActionHook(ctx context.Context, scenario *v1alpha1.Scenario, action v1alpha1.Action, job client.Object) error {
+	endpoint := os.Getenv(PreActionHookEnv)
+	if endpoint == "" {
+		return nil
+	}
+
+	return callActionHook(ctx, endpoint, ActionHookRequest{
+		Event:     PreAction,
+		Scenario:  scenario.GetName(),
+		Namespace: scenario.GetNamespace(),
+		Action:    action,
+		Object:    job,
+	})
+}
+
+// callPostActionHook notifies the PostAction hook about the result of the action, without blocking the caller.
+func (r *Controller) callPostActionHook(scenario *v1alpha1.Scenario, result v1alpha1.ActionStatus) {
+	endpoint := os.Getenv(PostActionHookEnv)
+	if endpoint == "" {
+		return
+	}
+
+	request := ActionHookRequest{
+		Event:     PostAction,
+		Scenario:  scenario.GetName(),
+		Namespace: scenario.GetNamespace(),
+		Action:    *getActionOrDie(scenario, result.Name).DeepCopy(),
+		Result:    &result,
+	}
+
+	go func() {
+		if err := callActionHook(context.Background(), endpoint, request); err != nil {
+			r.Logger.Error(err, "PostAction hook has failed", "scenario", request.Scenario, "action", result.Name)
+		}
+	}()
+}
+
+func callActionHook(ctx context.Context, endpoint string, request ActionHookRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, ActionHookTimeout)
+	defer cancel()
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrapf(err, "cannot marshal request")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return errors.Wrapf(err, "cannot create request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "%s hook '%s' is unreachable", request.Event, req.URL.Host)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return errors.Errorf("%s hook denied action '%s' with status '%s': %s",
+			request.Event, request.Action.Name, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
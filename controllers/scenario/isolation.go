@@ -0,0 +1,115 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario
+
+import (
+	"context"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// createInActionNamespace creates the job of the action in the namespace where the action is placed, which is
+// either the dedicated namespace of the action, or one of the target namespaces of the scenario.
+func (r *Controller) createInActionNamespace(ctx context.Context, scenario *v1alpha1.Scenario, action v1alpha1.Action, job client.Object) error {
+	var namespace corev1.Namespace
+
+	namespace.SetName(scenario.JobNamespace(&action))
+
+	v1alpha1.SetScenarioLabel(&namespace.ObjectMeta, scenario.GetName())
+	metav1.SetMetaDataLabel(&namespace.ObjectMeta, v1alpha1.LabelParentNamespace, scenario.GetNamespace())
+
+	// target namespaces are shared by multiple actions.
+	if scenario.Spec.IsNamespaceIsolated() {
+		v1alpha1.SetActionLabel(&namespace.ObjectMeta, action.Name)
+	}
+
+	if err := r.GetClient().Create(ctx, &namespace); err != nil && !k8errors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "cannot create namespace '%s'", namespace.GetName())
+	}
+
+	return common.CreateInNamespace(ctx, r, scenario, job, namespace.GetName())
+}
+
+// deleteActionNamespaces removes the namespaces of the actions and the target namespaces, along with their resources.
+func (r *Controller) deleteActionNamespaces(ctx context.Context, scenario *v1alpha1.Scenario) error {
+	var namespaces corev1.NamespaceList
+
+	if err := r.GetClient().List(ctx, &namespaces, client.MatchingLabels{
+		v1alpha1.LabelScenario:        scenario.GetName(),
+		v1alpha1.LabelParentNamespace: scenario.GetNamespace(),
+	}); err != nil {
+		return errors.Wrapf(err, "cannot list the namespaces of the actions")
+	}
+
+	for i := range namespaces.Items {
+		common.Delete(ctx, r, &namespaces.Items[i])
+	}
+
+	return nil
+}
+
+// listChildren lists the children of the scenario, both in the namespace of the scenario and in the
+// namespaces of the actions.
+func listChildren(ctx context.Context, cli client.Client, childJobs client.ObjectList, req types.NamespacedName) error {
+	if err := common.ListChildren(ctx, cli, childJobs, req); err != nil {
+		return err
+	}
+
+	isolated, ok := childJobs.DeepCopyObject().(client.ObjectList)
+	if !ok {
+		return errors.Errorf("'%T' is not a list", childJobs)
+	}
+
+	if err := common.ListIsolatedChildren(ctx, cli, isolated, req); err != nil {
+		return err
+	}
+
+	isolatedItems, err := meta.ExtractList(isolated)
+	if err != nil || len(isolatedItems) == 0 {
+		return err
+	}
+
+	items, err := meta.ExtractList(childJobs)
+	if err != nil {
+		return err
+	}
+
+	return meta.SetList(childJobs, append(items, isolatedItems...))
+}
+
+// enqueueParentScenario maps the jobs in the namespaces of the actions to their scenario.
+// They have no owner references, and therefore they are not enqueued by Owns().
+func enqueueParentScenario(_ context.Context, obj client.Object) []reconcile.Request {
+	namespace, isolated := obj.GetLabels()[v1alpha1.LabelParentNamespace]
+	if !isolated || metav1.GetControllerOf(obj) != nil {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{
+		Namespace: namespace,
+		Name:      obj.GetLabels()[v1alpha1.LabelCreatedBy],
+	}}}
+}
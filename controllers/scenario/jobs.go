@@ -23,6 +23,7 @@ import (
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	chaosutils "github.com/carv-ics-forth/frisbee/controllers/chaos/utils"
 	"github.com/carv-ics-forth/frisbee/controllers/common"
+	scenarioutils "github.com/carv-ics-forth/frisbee/controllers/scenario/utils"
 	serviceutils "github.com/carv-ics-forth/frisbee/controllers/service/utils"
 	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
 	"github.com/pkg/errors"
@@ -37,12 +38,12 @@ func (r *Controller) RunAction(ctx context.Context, scenario *v1alpha1.Scenario,
 			return errors.Wrapf(err, "preparation of action '%s' has failed", action.Name)
 		}
 
-		return common.Create(ctx, r, scenario, job)
+		return r.create(ctx, scenario, action, job)
 
 	case v1alpha1.ActionCluster:
 		job := r.cluster(scenario, action)
 
-		return common.Create(ctx, r, scenario, job)
+		return r.create(ctx, scenario, action, job)
 
 	case v1alpha1.ActionChaos:
 		job, err := r.chaos(ctx, scenario, action)
@@ -50,19 +51,109 @@ func (r *Controller) RunAction(ctx context.Context, scenario *v1alpha1.Scenario,
 			return errors.Wrapf(err, "preparation of action '%s' has failed", action.Name)
 		}
 
-		return common.Create(ctx, r, scenario, job)
+		return r.create(ctx, scenario, action, job)
 
 	case v1alpha1.ActionCascade:
 		job := r.cascade(scenario, action)
 
-		return common.Create(ctx, r, scenario, job)
+		return r.create(ctx, scenario, action, job)
 
 	case v1alpha1.ActionCall:
 		job := r.call(scenario, action)
 
-		return common.Create(ctx, r, scenario, job)
+		return r.create(ctx, scenario, action, job)
+
+	case v1alpha1.ActionSeed:
+		job, err := r.seed(scenario, action)
+		if err != nil {
+			return errors.Wrapf(err, "preparation of action '%s' has failed", action.Name)
+		}
+
+		return r.create(ctx, scenario, action, job)
+
+	case v1alpha1.ActionAssertSQL:
+		job, err := r.assertSQL(ctx, scenario, action)
+		if err != nil {
+			return errors.Wrapf(err, "preparation of action '%s' has failed", action.Name)
+		}
+
+		return r.create(ctx, scenario, action, job)
+
+	case v1alpha1.ActionConsistency:
+		job, err := r.consistency(ctx, scenario, action)
+		if err != nil {
+			return errors.Wrapf(err, "preparation of action '%s' has failed", action.Name)
+		}
+
+		return r.create(ctx, scenario, action, job)
+
+	case v1alpha1.ActionClockSkew:
+		job := r.clockSkew(scenario, action)
+
+		return r.create(ctx, scenario, action, job)
+
+	case v1alpha1.ActionDiskFault:
+		job, err := r.diskFault(ctx, scenario, action)
+		if err != nil {
+			return errors.Wrapf(err, "preparation of action '%s' has failed", action.Name)
+		}
+
+		return r.create(ctx, scenario, action, job)
+
+	case v1alpha1.ActionTLSFault:
+		if err := r.tlsFault(ctx, scenario, action); err != nil {
+			return errors.Wrapf(err, "preparation of action '%s' has failed", action.Name)
+		}
+
+		return nil
+
+	case v1alpha1.ActionRegistryOutage:
+		if err := r.registryOutage(ctx, scenario, action); err != nil {
+			return errors.Wrapf(err, "preparation of action '%s' has failed", action.Name)
+		}
+
+		return nil
+
+	case v1alpha1.ActionControlPlaneFault:
+		job, err := r.controlPlaneFault(ctx, scenario, action)
+		if err != nil {
+			return errors.Wrapf(err, "preparation of action '%s' has failed", action.Name)
+		}
+
+		return r.create(ctx, scenario, action, job)
+
+	case v1alpha1.ActionRawChaos:
+		job := r.rawChaos(scenario, action)
+
+		return r.create(ctx, scenario, action, job)
+
+	case v1alpha1.ActionTrigger:
+		if err := r.callPreActionHook(ctx, scenario, action, nil); err != nil {
+			return err
+		}
+
+		if err := r.trigger(ctx, scenario, action); err != nil {
+			return errors.Wrapf(err, "preparation of action '%s' has failed", action.Name)
+		}
+
+		return nil
+
+	case v1alpha1.ActionSnapshot, v1alpha1.ActionRestore:
+		if err := r.callPreActionHook(ctx, scenario, action, nil); err != nil {
+			return err
+		}
+
+		if err := r.snapshot(ctx, scenario, action); err != nil {
+			return errors.Wrapf(err, "%s action '%s' has failed", action.ActionType, action.Name)
+		}
+
+		return nil
 
 	case v1alpha1.ActionDelete:
+		if err := r.callPreActionHook(ctx, scenario, action, nil); err != nil {
+			return err
+		}
+
 		if err := r.delete(ctx, scenario, action); err != nil {
 			return errors.Errorf("delete action '%s' has failed", action.Name)
 		}
@@ -75,6 +166,19 @@ func (r *Controller) RunAction(ctx context.Context, scenario *v1alpha1.Scenario,
 	}
 }
 
+// create submits the job of the action, once the PreAction hook allows it.
+func (r *Controller) create(ctx context.Context, scenario *v1alpha1.Scenario, action v1alpha1.Action, job client.Object) error {
+	if err := r.callPreActionHook(ctx, scenario, action, job); err != nil {
+		return err
+	}
+
+	if scenario.JobNamespace(&action) != scenario.GetNamespace() {
+		return r.createInActionNamespace(ctx, scenario, action, job)
+	}
+
+	return common.Create(ctx, r, scenario, job)
+}
+
 func (r *Controller) service(ctx context.Context, scenario *v1alpha1.Scenario, action v1alpha1.Action) (*v1alpha1.Service, error) {
 	// get the job template
 	spec, err := serviceutils.GetServiceSpec(ctx, r.GetClient(), scenario, *action.Service)
@@ -155,6 +259,25 @@ func (r *Controller) chaos(ctx context.Context, scenario *v1alpha1.Scenario, act
 	return &job, nil
 }
 
+// rawChaos wraps the inline manifest into a Chaos, which tracks the fault as any other fault.
+func (r *Controller) rawChaos(scenario *v1alpha1.Scenario, action v1alpha1.Action) *v1alpha1.Chaos {
+	var job v1alpha1.Chaos
+
+	// Metadata
+	job.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("Chaos"))
+	job.SetNamespace(scenario.GetNamespace())
+	job.SetName(action.Name)
+
+	v1alpha1.SetScenarioLabel(&job.ObjectMeta, scenario.GetName())
+	v1alpha1.SetActionLabel(&job.ObjectMeta, action.Name)
+	v1alpha1.SetComponentLabel(&job.ObjectMeta, v1alpha1.ComponentSUT)
+
+	// Spec
+	action.RawChaos.DeepCopyInto(&job.Spec)
+
+	return &job
+}
+
 func (r *Controller) cascade(scenario *v1alpha1.Scenario, action v1alpha1.Action) *v1alpha1.Cascade {
 	var job v1alpha1.Cascade
 
@@ -191,6 +314,258 @@ func (r *Controller) call(scenario *v1alpha1.Scenario, action v1alpha1.Action) *
 	return &job
 }
 
+// clockSkew runs the clock skew as a Cascade, whose every fault injects a single step of the skew profile.
+func (r *Controller) clockSkew(scenario *v1alpha1.Scenario, action v1alpha1.Action) *v1alpha1.Cascade {
+	var job v1alpha1.Cascade
+
+	// Metadata
+	job.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("Cascade"))
+	job.SetNamespace(scenario.GetNamespace())
+	job.SetName(action.Name)
+
+	v1alpha1.SetScenarioLabel(&job.ObjectMeta, scenario.GetName())
+	v1alpha1.SetActionLabel(&job.ObjectMeta, action.Name)
+	v1alpha1.SetComponentLabel(&job.ObjectMeta, v1alpha1.ComponentSUT)
+
+	// Spec
+	scenarioutils.ClockSkewCascadeSpec(action).DeepCopyInto(&job.Spec)
+
+	return &job
+}
+
+// seed runs the seeding as a Cluster, whose every service seeds a single chunk.
+// diskFault selects the targets of the fault when the action runs, and translates the fault into a Chaos (for the
+// IOChaos presets), or into a Cluster of helpers (for filling the volumes).
+func (r *Controller) diskFault(ctx context.Context, scenario *v1alpha1.Scenario, action v1alpha1.Action) (client.Object, error) {
+	fault := action.DiskFault
+
+	targets, err := scenarioutils.SelectServices(ctx, r.GetClient(), scenario.GetNamespace(), &fault.Selector)
+	if err != nil {
+		return nil, errors.Wrapf(err, "selector error")
+	}
+
+	if fault.Fill != nil {
+		spec, err := scenarioutils.DiskFillClusterSpec(ctx, r.GetClient(), action, targets)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fill spec")
+		}
+
+		var job v1alpha1.Cluster
+
+		// Metadata
+		job.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("Cluster"))
+		job.SetNamespace(scenario.GetNamespace())
+		job.SetName(action.Name)
+
+		v1alpha1.SetScenarioLabel(&job.ObjectMeta, scenario.GetName())
+		v1alpha1.SetActionLabel(&job.ObjectMeta, action.Name)
+		v1alpha1.SetComponentLabel(&job.ObjectMeta, v1alpha1.ComponentSUT)
+
+		// Spec
+		spec.DeepCopyInto(&job.Spec)
+
+		return &job, nil
+	}
+
+	fromTemplate, err := scenarioutils.DiskFaultChaosSpec(action, targets)
+	if err != nil {
+		return nil, errors.Wrapf(err, "chaos spec")
+	}
+
+	spec, err := chaosutils.GetChaosSpec(ctx, r.GetClient(), scenario, *fromTemplate)
+	if err != nil {
+		return nil, errors.Wrapf(err, "chaos spec")
+	}
+
+	var job v1alpha1.Chaos
+
+	// Metadata
+	job.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("Chaos"))
+	job.SetNamespace(scenario.GetNamespace())
+	job.SetName(action.Name)
+
+	v1alpha1.SetScenarioLabel(&job.ObjectMeta, scenario.GetName())
+	v1alpha1.SetActionLabel(&job.ObjectMeta, action.Name)
+	v1alpha1.SetComponentLabel(&job.ObjectMeta, v1alpha1.ComponentSUT)
+
+	// Spec
+	spec.DeepCopyInto(&job.Spec)
+
+	return &job, nil
+}
+
+// controlPlaneFault pauses the control-plane components with a Cluster of helpers, or delays the API servers with
+// a Chaos. The pods of the control plane are read directly from the API server, as they are not cached.
+func (r *Controller) controlPlaneFault(ctx context.Context, scenario *v1alpha1.Scenario, action v1alpha1.Action) (client.Object, error) {
+	// the flag is checked when the scenario starts, but the operator may have been reconfigured since.
+	if err := scenarioutils.CheckControlPlaneFaultsAllowed(); err != nil {
+		return nil, err
+	}
+
+	fault := action.ControlPlaneFault
+
+	if fault.Pause != nil {
+		spec, err := scenarioutils.ControlPlanePauseClusterSpec(ctx, r.GetAPIReader(), action)
+		if err != nil {
+			return nil, errors.Wrapf(err, "pause spec")
+		}
+
+		var job v1alpha1.Cluster
+
+		// Metadata
+		job.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("Cluster"))
+		job.SetNamespace(scenario.GetNamespace())
+		job.SetName(action.Name)
+
+		v1alpha1.SetScenarioLabel(&job.ObjectMeta, scenario.GetName())
+		v1alpha1.SetActionLabel(&job.ObjectMeta, action.Name)
+		v1alpha1.SetComponentLabel(&job.ObjectMeta, v1alpha1.ComponentSUT)
+
+		// Spec
+		spec.DeepCopyInto(&job.Spec)
+
+		return &job, nil
+	}
+
+	if _, err := scenarioutils.ControlPlaneNodes(ctx, r.GetAPIReader(), v1alpha1.ControlPlaneAPIServer); err != nil {
+		return nil, errors.Wrapf(err, "latency spec")
+	}
+
+	spec, err := chaosutils.GetChaosSpec(ctx, r.GetClient(), scenario, scenarioutils.APIServerLatencyChaosSpec(action))
+	if err != nil {
+		return nil, errors.Wrapf(err, "chaos spec")
+	}
+
+	var job v1alpha1.Chaos
+
+	// Metadata
+	job.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("Chaos"))
+	job.SetNamespace(scenario.GetNamespace())
+	job.SetName(action.Name)
+
+	v1alpha1.SetScenarioLabel(&job.ObjectMeta, scenario.GetName())
+	v1alpha1.SetActionLabel(&job.ObjectMeta, action.Name)
+	v1alpha1.SetComponentLabel(&job.ObjectMeta, v1alpha1.ComponentSUT)
+
+	// Spec
+	spec.DeepCopyInto(&job.Spec)
+
+	return &job, nil
+}
+
+func (r *Controller) seed(scenario *v1alpha1.Scenario, action v1alpha1.Action) (*v1alpha1.Cluster, error) {
+	spec, err := scenarioutils.SeedClusterSpec(scenario, action)
+	if err != nil {
+		return nil, errors.Wrapf(err, "seed spec")
+	}
+
+	var job v1alpha1.Cluster
+
+	// Metadata
+	job.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("Cluster"))
+	job.SetNamespace(scenario.GetNamespace())
+	job.SetName(action.Name)
+
+	v1alpha1.SetScenarioLabel(&job.ObjectMeta, scenario.GetName())
+	v1alpha1.SetActionLabel(&job.ObjectMeta, action.Name)
+	v1alpha1.SetComponentLabel(&job.ObjectMeta, v1alpha1.ComponentSUT)
+
+	// Spec
+	spec.DeepCopyInto(&job.Spec)
+
+	return &job, nil
+}
+
+// assertSQL runs the query of the assertion from a helper service, which fails if the assertion does not hold.
+func (r *Controller) assertSQL(ctx context.Context, scenario *v1alpha1.Scenario, action v1alpha1.Action) (*v1alpha1.Service, error) {
+	fromTemplate, err := scenarioutils.AssertSQLServiceSpec(scenario, action)
+	if err != nil {
+		return nil, errors.Wrapf(err, "assertion spec")
+	}
+
+	spec, err := serviceutils.GetServiceSpec(ctx, r.GetClient(), scenario, *fromTemplate)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot retrieve job spec")
+	}
+
+	var job v1alpha1.Service
+
+	// Metadata
+	job.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("Service"))
+	job.SetNamespace(scenario.GetNamespace())
+	job.SetName(action.Name)
+
+	v1alpha1.SetScenarioLabel(&job.ObjectMeta, scenario.GetName())
+	v1alpha1.SetActionLabel(&job.ObjectMeta, action.Name)
+	v1alpha1.SetComponentLabel(&job.ObjectMeta, v1alpha1.ComponentSUT)
+
+	// Spec
+	spec.DeepCopyInto(&job.Spec)
+
+	return &job, nil
+}
+
+// consistency runs the checker of the histories as a service, which fails if the histories are not consistent.
+func (r *Controller) consistency(ctx context.Context, scenario *v1alpha1.Scenario, action v1alpha1.Action) (*v1alpha1.Service, error) {
+	spec, err := serviceutils.GetServiceSpec(ctx, r.GetClient(), scenario, *scenarioutils.ConsistencyServiceSpec(action))
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot retrieve job spec")
+	}
+
+	var job v1alpha1.Service
+
+	// Metadata
+	job.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("Service"))
+	job.SetNamespace(scenario.GetNamespace())
+	job.SetName(action.Name)
+
+	v1alpha1.SetScenarioLabel(&job.ObjectMeta, scenario.GetName())
+	v1alpha1.SetActionLabel(&job.ObjectMeta, action.Name)
+	v1alpha1.SetComponentLabel(&job.ObjectMeta, v1alpha1.ComponentSUT)
+
+	// Spec
+	spec.DeepCopyInto(&job.Spec)
+
+	// The checker collects the histories of all the clients, and therefore it mounts the root of the volume.
+	serviceutils.AttachTestDataVolume(&job, scenario.Spec.TestData, false)
+
+	return &job, nil
+}
+
+// snapshot takes, or restores, the snapshots of the volumes. Since there is no dedicated controller, the action is
+// represented by a virtual object that completes once the snapshots are ready, or the claims are restored.
+func (r *Controller) snapshot(ctx context.Context, scenario *v1alpha1.Scenario, action v1alpha1.Action) error {
+	return lifecycle.CreateVirtualJob(ctx, r, scenario, action.Name, func(_ *v1alpha1.VirtualObject) error {
+		if action.ActionType == v1alpha1.ActionSnapshot {
+			return scenarioutils.TakeSnapshots(ctx, r, scenario, action.Snapshot)
+		}
+
+		return scenarioutils.RestoreSnapshots(ctx, r, scenario, action.Restore)
+	})
+}
+
+// tlsFault runs as a virtual job that lasts as long as the fault, so that its completion marks the restoration
+// of the original certificate.
+func (r *Controller) tlsFault(ctx context.Context, scenario *v1alpha1.Scenario, action v1alpha1.Action) error {
+	return lifecycle.CreateVirtualJob(ctx, r, scenario, action.Name, func(_ *v1alpha1.VirtualObject) error {
+		return scenarioutils.InjectTLSFault(ctx, r, scenario, action.TLSFault)
+	})
+}
+
+// registryOutage runs as a virtual job that lasts as long as the outage, and owns the helpers of the outage.
+func (r *Controller) registryOutage(ctx context.Context, scenario *v1alpha1.Scenario, action v1alpha1.Action) error {
+	return lifecycle.CreateVirtualJob(ctx, r, scenario, action.Name, func(vobj *v1alpha1.VirtualObject) error {
+		return scenarioutils.InjectRegistryOutage(ctx, r, scenario, vobj, action)
+	})
+}
+
+// trigger runs as a virtual job that completes once an external system releases the action with a signed callback.
+func (r *Controller) trigger(ctx context.Context, scenario *v1alpha1.Scenario, action v1alpha1.Action) error {
+	return lifecycle.CreateVirtualJob(ctx, r, scenario, action.Name, func(_ *v1alpha1.VirtualObject) error {
+		return scenarioutils.WaitForTrigger(ctx, r, r.triggers, scenario, action)
+	})
+}
+
 func (r *Controller) delete(ctx context.Context, scenario *v1alpha1.Scenario, action v1alpha1.Action) error {
 	r.Info("-> Delete", "obj", action.Name, "targets", action.Delete.Jobs)
 	defer r.Info("<- Delete", "obj", action.Name, "targets", action.Delete.Jobs)
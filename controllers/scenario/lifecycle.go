@@ -18,13 +18,16 @@ package scenario
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
 	"github.com/carv-ics-forth/frisbee/pkg/expressions"
 	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
 	"github.com/pkg/errors"
-	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // getActionOrDie returns the spec of the referenced action.
@@ -40,36 +43,201 @@ func getActionOrDie(t *v1alpha1.Scenario, actionName string) *v1alpha1.Action {
 }
 
 func (r *Controller) updateLifecycle(scenario *v1alpha1.Scenario) bool {
-	// Step 1. Skip any scenario which are already completed, or uninitialized.
-	if scenario.Status.Lifecycle.Phase.Is(v1alpha1.PhaseUninitialized, v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed) {
+	// Step 1. Skip any scenario which are already completed, aborted, or uninitialized.
+	if scenario.Status.Lifecycle.Phase.Is(v1alpha1.PhaseUninitialized, v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed,
+		v1alpha1.PhaseAborted) {
 		return false
 	}
 
 	for _, actionName := range scenario.Status.ScheduledJobs {
 		action := getActionOrDie(scenario, actionName)
 
-		if !action.Assert.IsZero() {
-			eval := expressions.Condition{Expr: action.Assert}
+		failure := expressions.Assert(r.view, scenario, &scenario.Status.Lifecycle, action.Assert,
+			fmt.Sprintf("action '%s'", action.Name), r.Now())
+		if failure != nil {
+			for i := range scenario.Status.Actions {
+				if scenario.Status.Actions[i].Name == action.Name {
+					scenario.Status.Actions[i].AssertionFailure = failure
+				}
+			}
 
-			if !eval.IsTrue(r.view, scenario) {
-				scenario.Status.Lifecycle.Phase = v1alpha1.PhaseFailed
-				scenario.Status.Lifecycle.Reason = "AssertError"
-				scenario.Status.Lifecycle.Message = fmt.Sprintf("action '%s' failed due to:'%s'", action.Name, eval.Info)
+			return true
+		}
+	}
 
-				meta.SetStatusCondition(&scenario.Status.Lifecycle.Conditions, metav1.Condition{
-					Type:    v1alpha1.ConditionAssertionError.String(),
-					Status:  metav1.ConditionTrue,
-					Reason:  "AssertError",
-					Message: fmt.Sprintf("action '%s' failed due to:'%s'", action.Name, eval.Info),
-				})
+	// Step 2. Fail the scenario if an action has exceeded its timeout.
+	if r.checkTimeouts(scenario) {
+		return true
+	}
 
-				return true
-			}
+	// Step 3. Calculate the progress of the scenario.
+	progressChanged := r.updateProgress(scenario)
+
+	// Step 4. Update the timeline of the actions.
+	actionsChanged := r.updateActions(scenario)
+
+	// Step 5. Check if scheduling goes as expected.
+	totalJobs := len(scenario.Spec.Actions)
+
+	return lifecycle.GroupedJobs(totalJobs, r.view, &scenario.Status.Lifecycle, nil) || progressChanged || actionsChanged
+}
+
+// updateProgress calculates the progress of the scenario as the average progress of its actions. Completed actions
+// count as 100%, Clusters count by their own progress, and the rest of the actions count as 0%.
+// It returns true if the status has been changed.
+func (r *Controller) updateProgress(scenario *v1alpha1.Scenario) bool {
+	if len(scenario.Spec.Actions) == 0 {
+		return false
+	}
+
+	total := 100 * (r.view.NumSuccessfulJobs() + r.view.NumFailedJobs())
+
+	for _, job := range append(r.view.GetPendingJobs(), r.view.GetRunningJobs()...) {
+		if cluster, ok := job.(*v1alpha1.Cluster); ok {
+			total += cluster.Status.PercentComplete
 		}
 	}
 
-	// Step 4. Check if scheduling goes as expected.
-	totalJobs := len(scenario.Spec.Actions)
+	percent := total / len(scenario.Spec.Actions)
+	if percent > 100 {
+		percent = 100
+	}
+
+	if scenario.Status.PercentComplete == percent {
+		return false
+	}
+
+	scenario.Status.PercentComplete = percent
+
+	return true
+}
+
+// updateActions updates the timeline of the scheduled actions, based on the state of the respective jobs.
+// It returns true if the status has been changed.
+func (r *Controller) updateActions(scenario *v1alpha1.Scenario) bool {
+	changed := false
+
+	for i := range scenario.Status.Actions {
+		action := &scenario.Status.Actions[i]
+
+		// the timeline of completed actions is final.
+		if action.EndTime != nil {
+			continue
+		}
+
+		job := r.getJob(action.Name)
+		if job == nil {
+			// the job is not yet created, or it is ignored by the view (e.g, uninitialized).
+			continue
+		}
+
+		status := job.(v1alpha1.ReconcileStatusAware).GetReconcileStatus()
+		jobsCreated := countCreatedJobs(job)
+		percentComplete := actionProgress(job, status.Phase)
+
+		if action.Phase == status.Phase && action.JobsCreated == jobsCreated &&
+			action.PercentComplete == percentComplete {
+			continue
+		}
+
+		action.Phase = status.Phase
+		action.JobsCreated = jobsCreated
+		action.PercentComplete = percentComplete
+
+		if status.Phase.Is(v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed) {
+			action.EndTime = &metav1.Time{Time: r.Now()}
+		}
+
+		if status.Phase.Is(v1alpha1.PhaseFailed) {
+			action.Reason = status.Reason
+			action.Message = status.Message
+		}
+
+		if action.EndTime != nil {
+			r.callPostActionHook(scenario, *action.DeepCopy())
+		}
+
+		changed = true
+	}
+
+	return changed
+}
+
+// getJob returns the job with the given name, regardless of its phase. If the job is not found, it returns nil.
+func (r *Controller) getJob(name string) client.Object {
+	for _, getJobs := range []func(...string) []client.Object{
+		r.view.GetPendingJobs,
+		r.view.GetRunningJobs,
+		r.view.GetSuccessfulJobs,
+		r.view.GetFailedJobs,
+	} {
+		if jobs := getJobs(name); len(jobs) > 0 {
+			return jobs[0]
+		}
+	}
+
+	return nil
+}
+
+// countCreatedJobs returns the number of jobs created by the job of an action.
+func countCreatedJobs(job client.Object) int {
+	switch v := job.(type) {
+	case *v1alpha1.Cluster:
+		return v.Status.ScheduledJobs + 1
+	case *v1alpha1.Cascade:
+		return v.Status.ScheduledJobs + 1
+	case *v1alpha1.Call:
+		return v.Status.ScheduledJobs + 1
+	case *v1alpha1.Service, *v1alpha1.Chaos:
+		return 1
+	default:
+		// virtual jobs (e.g, Delete) do not create anything.
+		return 0
+	}
+}
+
+// actionProgress returns the progress of the job of an action. Completed jobs count as 100%, Clusters (e.g, seeds)
+// count by their own progress, and the rest of the jobs count as 0%.
+func actionProgress(job client.Object, phase v1alpha1.Phase) int {
+	if phase.Is(v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed) {
+		return 100
+	}
+
+	if cluster, ok := job.(*v1alpha1.Cluster); ok {
+		return cluster.Status.PercentComplete
+	}
+
+	return 0
+}
+
+// stopOrPoll stops the reconciliation, unless the scheduled actions assert on expressions that must be polled,
+// or have timeouts that must be checked.
+func (r *Controller) stopOrPoll(req ctrl.Request, scenario *v1alpha1.Scenario) (ctrl.Result, error) {
+	delay := time.Duration(0)
+
+	for _, actionName := range scenario.Status.ScheduledJobs {
+		if expressions.NeedsPolling(getActionOrDie(scenario, actionName).Assert) {
+			delay = expressions.AlertmanagerPollInterval
+
+			break
+		}
+	}
+
+	if deadline := nextTimeout(scenario); !deadline.IsZero() {
+		// the expired deadlines are checked on the next cycle.
+		untilDeadline := r.Until(deadline)
+		if untilDeadline < time.Second {
+			untilDeadline = time.Second
+		}
+
+		if delay == 0 || untilDeadline < delay {
+			delay = untilDeadline
+		}
+	}
+
+	if delay > 0 {
+		return common.RequeueAfter(r, req, delay)
+	}
 
-	return lifecycle.GroupedJobs(totalJobs, r.view, &scenario.Status.Lifecycle, nil)
+	return common.Stop(r, req)
 }
@@ -0,0 +1,127 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario
+
+import (
+	"context"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/chaos"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ApplyNetworkProfile establishes the links whose ends are running. It returns true if new links are established.
+// Links are materialized as NetworkChaos that are owned by the scenario, so that they are not accounted as jobs.
+func (r *Controller) ApplyNetworkProfile(ctx context.Context, scenario *v1alpha1.Scenario) (bool, error) {
+	established := make(map[string]struct{}, len(scenario.Status.NetworkLinks))
+
+	for _, name := range scenario.Status.NetworkLinks {
+		established[name] = struct{}{}
+	}
+
+	var applied bool
+
+	for _, link := range scenario.Spec.NetworkProfile {
+		if _, exists := established[link.Name()]; exists {
+			continue
+		}
+
+		// Faults are injected only to existing pods. Therefore, we wait for both ends to be running.
+		if !r.view.IsRunning(link.From) || !r.view.IsRunning(link.To) {
+			continue
+		}
+
+		fault := newNetworkLink(scenario, link)
+
+		if err := common.Create(ctx, r, scenario, fault); err != nil {
+			return applied, errors.Wrapf(err, "cannot establish link '%s'", link.Name())
+		}
+
+		r.GetEventRecorderFor(scenario.GetName()).Event(scenario, corev1.EventTypeNormal, "NetworkLink", link.Name())
+
+		scenario.Status.NetworkLinks = append(scenario.Status.NetworkLinks, link.Name())
+		applied = true
+	}
+
+	return applied, nil
+}
+
+// RemoveNetworkProfile removes the established links.
+func (r *Controller) RemoveNetworkProfile(ctx context.Context, scenario *v1alpha1.Scenario) {
+	for _, name := range scenario.Status.NetworkLinks {
+		var fault chaos.GenericFault
+
+		fault.SetGroupVersionKind(chaos.NetworkChaosGVK)
+		fault.SetNamespace(scenario.GetNamespace())
+		fault.SetName(name)
+
+		common.Delete(ctx, r, &fault)
+	}
+
+	r.Logger.Info("RemoveNetworkProfile",
+		"obj", client.ObjectKeyFromObject(scenario),
+		"links", scenario.Status.NetworkLinks,
+	)
+}
+
+// newNetworkLink translates the link into a NetworkChaos that affects all the pods of the two ends.
+// Pods are selected by their action, as it is inherited by all the children of a Service or Cluster.
+func newNetworkLink(scenario *v1alpha1.Scenario, link v1alpha1.NetworkLinkSpec) *chaos.GenericFault {
+	selector := func(action string) map[string]interface{} {
+		return map[string]interface{}{
+			"namespaces":     []interface{}{scenario.GetNamespace()},
+			"labelSelectors": map[string]interface{}{v1alpha1.LabelAction: action},
+		}
+	}
+
+	spec := map[string]interface{}{
+		"action":    "netem",
+		"mode":      "all",
+		"selector":  selector(link.From),
+		"direction": string(link.Direction),
+		"target": map[string]interface{}{
+			"mode":     "all",
+			"selector": selector(link.To),
+		},
+	}
+
+	if link.Latency != "" {
+		delay := map[string]interface{}{"latency": link.Latency}
+
+		if link.Jitter != "" {
+			delay["jitter"] = link.Jitter
+		}
+
+		spec["delay"] = delay
+	}
+
+	if link.Loss != "" {
+		spec["loss"] = map[string]interface{}{"loss": link.Loss}
+	}
+
+	fault := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+
+	fault.SetGroupVersionKind(chaos.NetworkChaosGVK)
+	fault.SetName(link.Name())
+	v1alpha1.PropagateLabels(fault, scenario)
+
+	return fault
+}
@@ -36,7 +36,7 @@ import (
 func (r *Controller) NextJobs(scenario *v1alpha1.Scenario) (runNext []v1alpha1.Action, nextCycle time.Time, err error) {
 	timeOK := func(deps *v1alpha1.WaitSpec) bool {
 		if dur := deps.After; dur != nil {
-			cur := metav1.Now()
+			cur := metav1.NewTime(r.Now())
 			deadline := scenario.GetCreationTimestamp().Add(dur.Duration)
 
 			// the deadline has expired.
@@ -79,7 +79,7 @@ func (r *Controller) NextJobs(scenario *v1alpha1.Scenario) (runNext []v1alpha1.A
 				if r.view.IsSuccessful(dep) || r.view.IsFailed(dep) {
 					err := errors.Errorf("action '%s' has a Running dependency on completed job '%s'", action.Name, dep)
 
-					return nil, time.Now(), err
+					return nil, r.Now(), err
 				}
 			}
 
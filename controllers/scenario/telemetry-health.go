@@ -0,0 +1,245 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	scenarioutils "github.com/carv-ics-forth/frisbee/controllers/scenario/utils"
+	"github.com/carv-ics-forth/frisbee/pkg/expressions"
+	"github.com/carv-ics-forth/frisbee/pkg/grafana"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// TelemetryRetryInterval is the interval for re-checking an unhealthy telemetry stack.
+	TelemetryRetryInterval = 5 * time.Second
+
+	// TelemetryTimeout is the time after which an unreachable Grafana fails the scenario.
+	TelemetryTimeout = 5 * time.Minute
+
+	// MaxTelemetryRepairs is the number of times that crashed telemetry components are recreated,
+	// before failing the scenario.
+	MaxTelemetryRepairs = 3
+)
+
+// telemetryComponent is a system service that can be recreated if it crashes.
+type telemetryComponent struct {
+	name   string
+	deploy func(ctx context.Context) error
+}
+
+// telemetryComponents returns the system services that have been deployed for the scenario.
+func (r *Controller) telemetryComponents(scenario *v1alpha1.Scenario) []telemetryComponent {
+	var components []telemetryComponent
+
+	if scenario.Status.DataviewerEndpoint != "" {
+		components = append(components, telemetryComponent{
+			name: common.DefaultDataviewerName,
+			deploy: func(ctx context.Context) error {
+				return scenarioutils.DeployDataviewer(ctx, r, scenario)
+			},
+		})
+	}
+
+	if scenario.Status.PrometheusEndpoint != "" {
+		components = append(components, telemetryComponent{
+			name: common.DefaultPrometheusName,
+			deploy: func(ctx context.Context) error {
+				return scenarioutils.DeployPrometheus(ctx, r, scenario)
+			},
+		})
+	}
+
+	if scenario.Status.IngestionEndpoint != "" {
+		components = append(components, telemetryComponent{
+			name: common.DefaultIngestionName,
+			deploy: func(ctx context.Context) error {
+				return scenarioutils.DeployIngestion(ctx, r, scenario)
+			},
+		})
+	}
+
+	if scenario.Status.TracingEndpoint != "" {
+		components = append(components, telemetryComponent{
+			name: common.DefaultTracingName,
+			deploy: func(ctx context.Context) error {
+				return scenarioutils.DeployTracing(ctx, r, scenario)
+			},
+		})
+	}
+
+	if scenario.Status.GrafanaEndpoint != "" {
+		components = append(components, telemetryComponent{
+			name: common.DefaultGrafanaServiceName,
+			deploy: func(ctx context.Context) error {
+				agents, err := r.ListTelemetryAgents(ctx, scenario)
+				if err != nil {
+					return errors.Wrapf(err, "cannot list telemetry agents")
+				}
+
+				return scenarioutils.DeployGrafana(ctx, r, scenario, agents)
+			},
+		})
+	}
+
+	return components
+}
+
+// CheckTelemetry evaluates, without blocking, the health of the telemetry stack, and records it in the TelemetryReady
+// condition. A crashed component is removed in one cycle, and is recreated in a following one.
+// It returns an error if the stack cannot be repaired, or if Grafana remains unreachable for longer than TelemetryTimeout.
+func (r *Controller) CheckTelemetry(ctx context.Context, scenario *v1alpha1.Scenario) (bool, error) {
+	components := r.telemetryComponents(scenario)
+	if len(components) == 0 {
+		return true, nil
+	}
+
+	for _, component := range components {
+		var job v1alpha1.Service
+
+		key := client.ObjectKey{Namespace: scenario.GetNamespace(), Name: component.name}
+
+		if err := r.GetClient().Get(ctx, key, &job); err != nil {
+			if !k8errors.IsNotFound(err) {
+				return false, errors.Wrapf(err, "cannot get '%s'", component.name)
+			}
+
+			// the crashed component has been removed. Recreate it.
+			if err := component.deploy(ctx); err != nil {
+				return false, errors.Wrapf(err, "cannot recreate '%s'", component.name)
+			}
+
+			r.setTelemetryCondition(scenario, metav1.ConditionFalse, "Repairing",
+				fmt.Sprintf("'%s' is recreated", component.name))
+
+			return false, nil
+		}
+
+		if !job.GetDeletionTimestamp().IsZero() {
+			r.setTelemetryCondition(scenario, metav1.ConditionFalse, "Repairing",
+				fmt.Sprintf("'%s' is being removed", component.name))
+
+			return false, nil
+		}
+
+		switch job.Status.Phase {
+		case v1alpha1.PhaseRunning:
+			continue
+
+		case v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed:
+			if scenario.Status.TelemetryRepairs >= MaxTelemetryRepairs {
+				return false, errors.Errorf("'%s' has crashed, and the limit of %d repairs is reached",
+					component.name, MaxTelemetryRepairs)
+			}
+
+			scenario.Status.TelemetryRepairs++
+
+			r.GetEventRecorderFor(scenario.GetName()).Event(scenario, corev1.EventTypeWarning, "TelemetryRepair",
+				fmt.Sprintf("'%s' has crashed (%s). Recreating it.", component.name, job.Status.Message))
+
+			// a new Grafana requires a new connection, with a new alerting channel.
+			if component.name == common.DefaultGrafanaServiceName {
+				grafana.DeleteClientFor(scenario)
+			}
+
+			common.Delete(ctx, r, &job)
+
+			r.setTelemetryCondition(scenario, metav1.ConditionFalse, "Repairing",
+				fmt.Sprintf("'%s' has crashed", component.name))
+
+			return false, nil
+
+		default:
+			r.setTelemetryCondition(scenario, metav1.ConditionFalse, "TelemetryPending",
+				fmt.Sprintf("'%s' is not yet running", component.name))
+
+			return false, nil
+		}
+	}
+
+	if scenario.Status.GrafanaEndpoint != "" {
+		reconnected, err := r.connectToGrafana(ctx, scenario, r.alertingProxy)
+		if err != nil {
+			r.setTelemetryCondition(scenario, metav1.ConditionFalse, "GrafanaUnreachable", err.Error())
+
+			cond := meta.FindStatusCondition(scenario.Status.Conditions, v1alpha1.ConditionTelemetryReady.String())
+			if r.Since(cond.LastTransitionTime.Time) > TelemetryTimeout {
+				return false, errors.Wrapf(err, "grafana is unreachable for more than %s", TelemetryTimeout)
+			}
+
+			return false, nil
+		}
+
+		if reconnected {
+			r.restoreAlerts(ctx, scenario)
+		}
+	}
+
+	r.setTelemetryCondition(scenario, metav1.ConditionTrue, "Healthy", "The telemetry stack is running")
+
+	return true, nil
+}
+
+// restoreAlerts re-installs the alerts of the scheduled assertions, since a recreated Grafana has lost them.
+// Alerts that are still in Grafana (e.g, after a controller restart) are rejected, and the rejection is ignored.
+func (r *Controller) restoreAlerts(ctx context.Context, scenario *v1alpha1.Scenario) {
+	for _, actionName := range scenario.Status.ScheduledJobs {
+		action := getActionOrDie(scenario, actionName)
+
+		if !action.Assert.HasMetricsExpr() {
+			continue
+		}
+
+		if err := expressions.SetAlert(ctx, scenario, action.Assert.Metrics); err != nil {
+			r.Logger.Info("Cannot restore alert", "action", actionName, "err", err)
+		}
+	}
+}
+
+// setTelemetryCondition updates the TelemetryReady condition. The transition time is reset whenever the reason
+// changes, so that it marks the beginning of the current state.
+func (r *Controller) setTelemetryCondition(scenario *v1alpha1.Scenario, status metav1.ConditionStatus, reason, message string) {
+	conditionType := v1alpha1.ConditionTelemetryReady.String()
+
+	transition := metav1.NewTime(r.Now())
+
+	if cond := meta.FindStatusCondition(scenario.Status.Conditions, conditionType); cond != nil {
+		if cond.Status == status && cond.Reason == reason {
+			transition = cond.LastTransitionTime
+		}
+
+		meta.RemoveStatusCondition(&scenario.Status.Conditions, conditionType)
+	}
+
+	meta.SetStatusCondition(&scenario.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		LastTransitionTime: transition,
+		Reason:             reason,
+		Message:            message,
+	})
+}
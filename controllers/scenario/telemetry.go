@@ -28,6 +28,9 @@ import (
 	"github.com/carv-ics-forth/frisbee/pkg/grafana"
 	"github.com/carv-ics-forth/frisbee/pkg/structure"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // {{{ Internal types
@@ -46,11 +49,24 @@ func (r *Controller) StartTelemetry(ctx context.Context, scenario *v1alpha1.Scen
 		return errors.Wrapf(err, "importing dashboards")
 	}
 
-	if len(telemetryAgents) > 0 {
+	if len(telemetryAgents) > 0 || len(scenario.Spec.Datasources) > 0 ||
+		scenario.Spec.Ingestion != nil || scenario.Spec.Tracing != nil {
 		if err := scenarioutils.DeployPrometheus(ctx, r, scenario); err != nil {
 			return errors.Wrapf(err, "prometheus error")
 		}
 
+		if scenario.Spec.Ingestion != nil {
+			if err := scenarioutils.DeployIngestion(ctx, r, scenario); err != nil {
+				return errors.Wrapf(err, "ingestion error")
+			}
+		}
+
+		if scenario.Spec.Tracing != nil {
+			if err := scenarioutils.DeployTracing(ctx, r, scenario); err != nil {
+				return errors.Wrapf(err, "tracing error")
+			}
+		}
+
 		if err := scenarioutils.DeployGrafana(ctx, r, scenario, telemetryAgents); err != nil {
 			return errors.Wrapf(err, "grafana error")
 		}
@@ -103,20 +119,11 @@ func (r *Controller) ListTelemetryAgents(ctx context.Context, scenario *v1alpha1
 
 // connectToGrafana creates a dedicated link between the scenario controller and the Grafana service.
 // The link must be destroyed if the scenario is deleted, since any new instance will change the ip of Grafana.
-func (r *Controller) connectToGrafana(ctx context.Context, scenario *v1alpha1.Scenario, notificationEndpoint string) error {
-	// if a client exists, there is no need to create another one.
-	if grafana.HasClientFor(scenario) {
-		return nil
-	}
-
-	// otherwise, re-create a client.
-	// this condition captures both the cases:
-	// 1) this is the first time we create a client to the controller
-	// 2) the controller has been restarted and lost its state.
-
+// It returns true if a new link has been created.
+func (r *Controller) connectToGrafana(ctx context.Context, scenario *v1alpha1.Scenario, notificationEndpoint string) (bool, error) {
 	var endpoint string
 
-	if configuration.Global.DeveloperMode {
+	if configuration.Global().DeveloperMode {
 		/* If in developer mode, the operator runs outside the cluster, and will reach Grafana via the ingress */
 		endpoint = common.ExternalEndpoint(common.DefaultGrafanaServiceName, scenario.GetNamespace())
 	} else {
@@ -124,14 +131,71 @@ func (r *Controller) connectToGrafana(ctx context.Context, scenario *v1alpha1.Sc
 		endpoint = common.InternalEndpoint(common.DefaultGrafanaServiceName, scenario.GetNamespace(), common.DefaultGrafanaPort)
 	}
 
-	_, err := grafana.New(ctx,
+	// if a client to the same endpoint exists, there is no need to create another one.
+	if grafana.IsConnectedTo(scenario, endpoint) {
+		return false, nil
+	}
+
+	// otherwise, re-create a client. The new client replaces any existing one.
+	// this condition captures the cases:
+	// 1) this is the first time we create a client to the controller
+	// 2) the controller has been restarted and lost its state.
+	// 3) the client has been evicted from the pool, or the endpoint has changed.
+	options := []grafana.Option{
 		grafana.WithHTTP(endpoint),        // Connect to ...
 		grafana.WithRegisterFor(scenario), // Used by grafana.GetFrisbeeClient(), grafana.ClientExistsFor(), ...
 		grafana.WithLogger(r.Logger),      // Log info
 		grafana.WithNotifications(notificationEndpoint),
-	)
+		grafana.WithTags(runTags(scenario)...),      // Trace the annotations back to the run
+		grafana.WithBackoff(wait.Backoff{Steps: 1}), // Do not block the reconciliation
+	}
+
+	// authenticate against secured Grafana deployments.
+	if secretName, exists := scenario.GetAnnotations()[v1alpha1.AnnotationGrafanaCredentials]; exists {
+		credentials, err := r.grafanaCredentials(ctx, scenario.GetNamespace(), secretName)
+		if err != nil {
+			return false, err
+		}
+
+		options = append(options, credentials)
+	}
+
+	if _, err := grafana.New(ctx, options...); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// grafanaCredentials returns the credentials for Grafana, as stored in the given Secret.
+func (r *Controller) grafanaCredentials(ctx context.Context, namespace string, name string) (grafana.Option, error) {
+	var secret corev1.Secret
+
+	if err := r.GetClient().Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &secret); err != nil {
+		return nil, errors.Wrapf(err, "cannot get grafana credentials '%s'", name)
+	}
+
+	return grafana.WithSecret(&secret)
+}
+
+// runTags returns the Grafana tags that identify the run of the scenario.
+func runTags(scenario *v1alpha1.Scenario) []grafana.Tag {
+	run := scenario.Status.Run
+	if run == nil {
+		return nil
+	}
+
+	tags := []grafana.Tag{"run:" + run.ID}
+
+	if run.TriggeredBy != "" {
+		tags = append(tags, "triggeredBy:"+run.TriggeredBy)
+	}
+
+	if run.Source.GitCommit != "" {
+		tags = append(tags, "commit:"+run.Source.GitCommit)
+	}
 
-	return err
+	return tags
 }
 
 var startWebhookOnce sync.Once
@@ -0,0 +1,95 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// actionDeadline returns the time by which the action must be completed. It returns false if the action has no
+// timeout, or if it is already completed.
+func actionDeadline(scenario *v1alpha1.Scenario, status v1alpha1.ActionStatus) (time.Time, bool) {
+	if status.StartTime == nil || status.EndTime != nil {
+		return time.Time{}, false
+	}
+
+	action := getActionOrDie(scenario, status.Name)
+	if action.Timeout == nil {
+		return time.Time{}, false
+	}
+
+	return status.StartTime.Add(action.Timeout.Duration), true
+}
+
+// checkTimeouts fails the scenario if an action has not been completed within its timeout. The job of the action is
+// killed by HasFailed, along with the rest of the running jobs. It returns true if the scenario has failed.
+func (r *Controller) checkTimeouts(scenario *v1alpha1.Scenario) bool {
+	now := r.Now()
+
+	for i := range scenario.Status.Actions {
+		action := &scenario.Status.Actions[i]
+
+		deadline, ok := actionDeadline(scenario, *action)
+		if !ok || now.Before(deadline) {
+			continue
+		}
+
+		msg := fmt.Sprintf("action '%s' has not been completed within %s",
+			action.Name, getActionOrDie(scenario, action.Name).Timeout.Duration)
+
+		action.Phase = v1alpha1.PhaseFailed
+		action.Reason = v1alpha1.ReasonActionTimeout
+		action.Message = msg
+		action.EndTime = &metav1.Time{Time: now}
+
+		r.callPostActionHook(scenario, *action.DeepCopy())
+
+		scenario.Status.Lifecycle.Phase = v1alpha1.PhaseFailed
+		scenario.Status.Lifecycle.Reason = v1alpha1.ReasonActionTimeout
+		scenario.Status.Lifecycle.Message = msg
+
+		meta.SetStatusCondition(&scenario.Status.Lifecycle.Conditions, metav1.Condition{
+			Type:    v1alpha1.ConditionActionTimeout.String(),
+			Status:  metav1.ConditionTrue,
+			Reason:  v1alpha1.ReasonActionTimeout,
+			Message: msg,
+		})
+
+		return true
+	}
+
+	return false
+}
+
+// nextTimeout returns the earliest deadline of the actions in progress, or zero if there is none.
+func nextTimeout(scenario *v1alpha1.Scenario) time.Time {
+	var next time.Time
+
+	for _, action := range scenario.Status.Actions {
+		deadline, ok := actionDeadline(scenario, action)
+		if ok && (next.IsZero() || deadline.Before(next)) {
+			next = deadline
+		}
+	}
+
+	return next
+}
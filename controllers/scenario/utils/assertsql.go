@@ -0,0 +1,66 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"encoding/base64"
+	"strconv"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/configuration"
+	"github.com/pkg/errors"
+)
+
+// AssertSQLServiceSpec translates an SQL assertion into the helper service that runs the query.
+// The service fails if the assertion does not hold.
+func AssertSQLServiceSpec(scenario *v1alpha1.Scenario, action v1alpha1.Action) (*v1alpha1.GenerateObjectFromTemplate, error) {
+	assert := action.AssertSQL
+
+	var spec v1alpha1.GenerateObjectFromTemplate
+
+	switch assert.Driver {
+	case v1alpha1.DriverPostgres:
+		spec.TemplateRef = configuration.TemplateRef(configuration.AssertPostgresTemplate)
+	case v1alpha1.DriverMySQL:
+		spec.TemplateRef = configuration.TemplateRef(configuration.AssertMySQLTemplate)
+	default:
+		return nil, errors.Errorf("unknown driver '%s'", assert.Driver)
+	}
+
+	connection, err := assert.RenderConnection(scenario)
+	if err != nil {
+		return nil, errors.Wrapf(err, "connection error")
+	}
+
+	// the query is encoded, as its quotes would otherwise break the evaluation of the template.
+	inputs := v1alpha1.UserInputs{
+		"connection": v1alpha1.ParameterValue(connection),
+		"query":      v1alpha1.ParameterValue(base64.StdEncoding.EncodeToString([]byte(assert.Query))),
+		"operator":   v1alpha1.ParameterValue(assert.Operator.Symbol()),
+		"threshold":  v1alpha1.ParameterValue(strconv.FormatFloat(assert.Threshold, 'g', -1, 64)),
+	}
+
+	if ref := assert.PasswordRef; ref != nil {
+		inputs["passwordSecret"] = v1alpha1.ParameterValue(ref.Name)
+		inputs["passwordKey"] = v1alpha1.ParameterValue(ref.Key)
+	}
+
+	spec.MaxInstances = 1
+	spec.Inputs = []v1alpha1.UserInputs{inputs}
+
+	return &spec, nil
+}
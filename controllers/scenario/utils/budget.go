@@ -0,0 +1,139 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// budgetResources maps the resources of the budget to the resources of the ResourceQuota.
+var budgetResources = map[corev1.ResourceName]corev1.ResourceName{
+	corev1.ResourceCPU:    corev1.ResourceRequestsCPU,
+	corev1.ResourceMemory: corev1.ResourceRequestsMemory,
+}
+
+// ServiceRequests estimates the resources requested by the Pod of the service. Containers that do not request
+// a resource are assigned the default request, as the LimitRange of the scenario does.
+func ServiceRequests(spec *v1alpha1.ServiceSpec, defaults corev1.ResourceList) corev1.ResourceList {
+	containerRequests := func(container *corev1.Container) corev1.ResourceList {
+		requests := corev1.ResourceList{}
+
+		for name := range budgetResources {
+			if quantity, ok := container.Resources.Requests[name]; ok {
+				requests[name] = quantity
+			} else if quantity, ok := container.Resources.Limits[name]; ok {
+				// the requests default to the limits.
+				requests[name] = quantity
+			} else if quantity, ok := defaults[name]; ok {
+				requests[name] = quantity
+			}
+		}
+
+		return requests
+	}
+
+	total := corev1.ResourceList{}
+
+	for i := range spec.Containers {
+		AddRequests(total, containerRequests(&spec.Containers[i]))
+	}
+
+	// the init containers run before the main containers, and therefore only the largest one is accounted.
+	for i := range spec.InitContainers {
+		for name, quantity := range containerRequests(&spec.InitContainers[i]) {
+			if current, ok := total[name]; !ok || quantity.Cmp(current) > 0 {
+				total[name] = quantity
+			}
+		}
+	}
+
+	return total
+}
+
+// AddRequests adds the requests to the total.
+func AddRequests(total, requests corev1.ResourceList) {
+	for name, quantity := range requests {
+		if current, ok := total[name]; ok {
+			current.Add(quantity)
+			total[name] = current
+		} else {
+			total[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+// QuotaUsage converts the usage reported by the ResourceQuota of the scenario into the resources of the budget.
+func QuotaUsage(quota *corev1.ResourceQuota) corev1.ResourceList {
+	used := corev1.ResourceList{}
+
+	for name, quotaName := range budgetResources {
+		if quantity, ok := quota.Status.Used[quotaName]; ok {
+			used[name] = quantity
+		}
+	}
+
+	return used
+}
+
+// QuotaLimits converts the limits of the budget into the hard limits of a ResourceQuota.
+func QuotaLimits(limits corev1.ResourceList) corev1.ResourceList {
+	hard := corev1.ResourceList{}
+
+	for name, quantity := range limits {
+		if quotaName, ok := budgetResources[name]; ok {
+			hard[quotaName] = quantity
+		}
+	}
+
+	return hard
+}
+
+// Exceeded returns the resources whose limit is exceeded once the requests are added to the used resources.
+// The returned resources are sorted.
+func Exceeded(limits, used, requests corev1.ResourceList) []corev1.ResourceName {
+	var exceeded []corev1.ResourceName
+
+	for name, limit := range limits {
+		total := used[name].DeepCopy()
+		total.Add(requests[name])
+
+		if total.Cmp(limit) > 0 {
+			exceeded = append(exceeded, name)
+		}
+	}
+
+	sort.Slice(exceeded, func(i, j int) bool { return exceeded[i] < exceeded[j] })
+
+	return exceeded
+}
+
+// FormatRequests prints the given resources of the list, as cpu=1,memory=1Gi.
+func FormatRequests(list corev1.ResourceList, names []corev1.ResourceName) string {
+	fields := make([]string, 0, len(names))
+
+	for _, name := range names {
+		quantity := list[name]
+		fields = append(fields, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+
+	return strings.Join(fields, ",")
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	scenarioutils "github.com/carv-ics-forth/frisbee/controllers/scenario/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func resources(cpu, memory string) corev1.ResourceList {
+	list := corev1.ResourceList{}
+
+	if cpu != "" {
+		list[corev1.ResourceCPU] = resource.MustParse(cpu)
+	}
+
+	if memory != "" {
+		list[corev1.ResourceMemory] = resource.MustParse(memory)
+	}
+
+	return list
+}
+
+func TestServiceRequests(t *testing.T) {
+	defaults := resources("100m", "128Mi")
+
+	tests := []struct {
+		name string
+		spec v1alpha1.ServiceSpec
+		want corev1.ResourceList
+	}{
+		{
+			name: "defaults",
+			spec: v1alpha1.ServiceSpec{PodSpec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "main"}, {Name: "sidecar"}},
+			}},
+			want: resources("200m", "256Mi"),
+		},
+		{
+			name: "requests and limits",
+			spec: v1alpha1.ServiceSpec{PodSpec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "main", Resources: corev1.ResourceRequirements{Requests: resources("1", "")}},
+					{Name: "sidecar", Resources: corev1.ResourceRequirements{Limits: resources("", "1Gi")}},
+				},
+			}},
+			want: resources("1100m", "1152Mi"),
+		},
+		{
+			name: "init containers",
+			spec: v1alpha1.ServiceSpec{PodSpec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "init", Resources: corev1.ResourceRequirements{Requests: resources("2", "")}}},
+				Containers:     []corev1.Container{{Name: "main"}},
+			}},
+			want: resources("2", "128Mi"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scenarioutils.ServiceRequests(&tt.spec, defaults)
+
+			for name, want := range tt.want {
+				if quantity := got[name]; quantity.Cmp(want) != 0 {
+					t.Errorf("ServiceRequests() %s = %s, want %s", name, quantity.String(), want.String())
+				}
+			}
+		})
+	}
+}
+
+func TestExceeded(t *testing.T) {
+	limits := resources("4", "8Gi")
+
+	tests := []struct {
+		name     string
+		used     corev1.ResourceList
+		requests corev1.ResourceList
+		want     []corev1.ResourceName
+	}{
+		{name: "fits", used: resources("1", "1Gi"), requests: resources("3", "7Gi")},
+		{name: "cpu", used: resources("2", "1Gi"), requests: resources("3", "1Gi"), want: []corev1.ResourceName{corev1.ResourceCPU}},
+		{
+			name: "both", used: resources("1", "4Gi"), requests: resources("4", "5Gi"),
+			want: []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory},
+		},
+		{name: "unused", requests: resources("4", "8Gi")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scenarioutils.Exceeded(limits, tt.used, tt.requests); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Exceeded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"strings"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/configuration"
+)
+
+// ClockSkewCascadeSpec translates a clock skew into a Cascade, whose every fault injects a single step of the
+// profile. The faults are sequential, so that every step starts once the previous one is recovered.
+func ClockSkewCascadeSpec(action v1alpha1.Action) *v1alpha1.CascadeSpec {
+	skew := action.ClockSkew
+	steps := skew.Steps()
+
+	// the services may be expanded from macros into space-separated lists.
+	targets := strings.Join(strings.Fields(strings.Join(skew.Services, idListSeparator)), ", ")
+
+	var spec v1alpha1.CascadeSpec
+
+	spec.TemplateRef = configuration.TemplateRef(configuration.ClockSkewTemplate)
+	spec.MaxInstances = len(steps)
+	spec.Inputs = make([]v1alpha1.UserInputs, 0, len(steps))
+
+	for _, step := range steps {
+		spec.Inputs = append(spec.Inputs, v1alpha1.UserInputs{
+			"targets":  v1alpha1.ParameterValue(targets),
+			"offset":   v1alpha1.ParameterValue(step.Offset.String()),
+			"duration": v1alpha1.ParameterValue(step.Duration.String()),
+		})
+	}
+
+	sequential := true
+	spec.Schedule = &v1alpha1.TaskSchedulerSpec{Sequential: &sequential}
+
+	return &spec
+}
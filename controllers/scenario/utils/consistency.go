@@ -0,0 +1,43 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/configuration"
+)
+
+// ConsistencyServiceSpec translates a consistency check into the service that runs the checker.
+// The service fails if the histories are not consistent.
+func ConsistencyServiceSpec(action v1alpha1.Action) *v1alpha1.GenerateObjectFromTemplate {
+	consistency := action.Consistency
+
+	var spec v1alpha1.GenerateObjectFromTemplate
+
+	spec.TemplateRef = consistency.TemplateRef
+	if spec.TemplateRef == "" {
+		spec.TemplateRef = configuration.TemplateRef(configuration.ConsistencyElleTemplate)
+	}
+
+	spec.MaxInstances = 1
+	spec.Inputs = []v1alpha1.UserInputs{{
+		"histories": v1alpha1.ParameterValue(consistency.GetHistories()),
+		"model":     v1alpha1.ParameterValue(consistency.Model),
+	}}
+
+	return &spec
+}
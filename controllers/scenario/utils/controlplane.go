@@ -0,0 +1,151 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/configuration"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ControlPlaneFaultsEnv is the environment variable that allows the control-plane faults. Control-plane faults
+	// affect the whole cluster, and they are rejected unless it is set to true.
+	ControlPlaneFaultsEnv = "FRISBEE_ALLOW_CONTROL_PLANE_FAULTS"
+
+	// ControlPlaneNamespace is the namespace of the control-plane pods.
+	ControlPlaneNamespace = metav1.NamespaceSystem
+
+	// ControlPlaneComponentLabel is the label that identifies the control-plane pods (e.g, component=kube-apiserver).
+	ControlPlaneComponentLabel = "component"
+)
+
+// CheckControlPlaneFaultsAllowed returns an error, unless the operator allows the control-plane faults.
+func CheckControlPlaneFaultsAllowed() error {
+	allowed, _ := strconv.ParseBool(os.Getenv(ControlPlaneFaultsEnv))
+	if !allowed {
+		return errors.Errorf("control-plane faults are not allowed. Install the operator with "+
+			"operator.controlPlaneFaults.allowed=true (%s=true) to allow them", ControlPlaneFaultsEnv)
+	}
+
+	return nil
+}
+
+// ControlPlaneNodes returns the nodes that run the pods of the component. Because the control-plane pods are not
+// created by Frisbee, they are not in the cache, and they must be read through an uncached reader.
+func ControlPlaneNodes(ctx context.Context, reader client.Reader, component v1alpha1.ControlPlaneComponent) ([]string, error) {
+	var pods corev1.PodList
+
+	if err := reader.List(ctx, &pods,
+		client.InNamespace(ControlPlaneNamespace),
+		client.MatchingLabels{ControlPlaneComponentLabel: string(component)},
+	); err != nil {
+		return nil, errors.Wrapf(err, "cannot list pods of '%s'", component)
+	}
+
+	nodeSet := make(map[string]bool, len(pods.Items))
+
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != "" {
+			nodeSet[pod.Spec.NodeName] = true
+		}
+	}
+
+	if len(nodeSet) == 0 {
+		return nil, errors.Errorf("no pods of '%s' in '%s'. Managed control planes cannot be disrupted",
+			component, ControlPlaneNamespace)
+	}
+
+	nodes := make([]string, 0, len(nodeSet))
+	for node := range nodeSet {
+		nodes = append(nodes, node)
+	}
+
+	sort.Strings(nodes)
+
+	return nodes, nil
+}
+
+// APIServerLatencyChaosSpec translates the latency of the API servers into a NetworkChaos on their pods.
+func APIServerLatencyChaosSpec(action v1alpha1.Action) v1alpha1.GenerateObjectFromTemplate {
+	fault := action.ControlPlaneFault
+
+	return v1alpha1.GenerateObjectFromTemplate{
+		TemplateRef:  configuration.TemplateRef(configuration.APIServerLatencyTemplate),
+		MaxInstances: 1,
+		Inputs: []v1alpha1.UserInputs{{
+			"component": v1alpha1.ParameterValue(v1alpha1.ControlPlaneAPIServer),
+			"duration":  v1alpha1.ParameterValue(fault.Duration.Duration.String()),
+			"latency":   v1alpha1.ParameterValue(fault.APIServerLatency.Latency.Duration.String()),
+			"jitter":    v1alpha1.ParameterValue(fault.APIServerLatency.Jitter.Duration.String()),
+		}},
+	}
+}
+
+// ControlPlanePauseClusterSpec translates the pause of the components into a Cluster of helper services, one for
+// every control-plane node. Every helper shares the processes of its node (hostPID), pauses the processes of the
+// components that run on the node, and resumes them once the duration has passed.
+func ControlPlanePauseClusterSpec(ctx context.Context, reader client.Reader, action v1alpha1.Action) (*v1alpha1.ClusterSpec, error) {
+	fault := action.ControlPlaneFault
+
+	// the components of every node, in the order they are given.
+	processes := make(map[string][]string)
+
+	for _, component := range fault.Pause.Components {
+		nodes, err := ControlPlaneNodes(ctx, reader, component)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, node := range nodes {
+			processes[node] = append(processes[node], string(component))
+		}
+	}
+
+	nodes := make([]string, 0, len(processes))
+	for node := range processes {
+		nodes = append(nodes, node)
+	}
+
+	sort.Strings(nodes)
+
+	var spec v1alpha1.ClusterSpec
+
+	spec.TemplateRef = configuration.TemplateRef(configuration.ControlPlanePauseTemplate)
+
+	for _, node := range nodes {
+		spec.Inputs = append(spec.Inputs, v1alpha1.UserInputs{
+			"node":      v1alpha1.ParameterValue(node),
+			"processes": v1alpha1.ParameterValue(strings.Join(processes[node], " ")),
+			"seconds":   v1alpha1.ParameterValue(fmt.Sprint(int64(fault.Duration.Seconds()))),
+		})
+	}
+
+	spec.MaxInstances = len(spec.Inputs)
+
+	return &spec, nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/configuration"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DiskFaultChaosSpec translates a latency or errno fault into the respective IOChaos preset. If there are no targets,
+// the default targets of the preset are used, which is only meaningful for validating the preset.
+func DiskFaultChaosSpec(action v1alpha1.Action, targets SList) (*v1alpha1.GenerateObjectFromTemplate, error) {
+	fault := action.DiskFault
+
+	inputs := v1alpha1.UserInputs{
+		"duration": v1alpha1.ParameterValue(fault.Duration.Duration.String()),
+	}
+
+	if len(targets) > 0 {
+		inputs["targets"] = v1alpha1.ParameterValue(strings.Join(targets.GetNames(), ", "))
+	}
+
+	var spec v1alpha1.GenerateObjectFromTemplate
+
+	switch {
+	case fault.Latency != nil:
+		spec.TemplateRef = configuration.TemplateRef(configuration.IOLatencyTemplate)
+
+		inputs["volumePath"] = v1alpha1.ParameterValue(fault.Latency.VolumePath)
+		inputs["path"] = v1alpha1.ParameterValue(fault.Latency.GetPath())
+		inputs["percent"] = v1alpha1.ParameterValue(fmt.Sprint(fault.Latency.GetPercent()))
+		inputs["delay"] = v1alpha1.ParameterValue(fault.Latency.Delay.Duration.String())
+
+	case fault.Errno != nil:
+		spec.TemplateRef = configuration.TemplateRef(configuration.IOErrnoTemplate)
+
+		inputs["volumePath"] = v1alpha1.ParameterValue(fault.Errno.VolumePath)
+		inputs["path"] = v1alpha1.ParameterValue(fault.Errno.GetPath())
+		inputs["percent"] = v1alpha1.ParameterValue(fmt.Sprint(fault.Errno.GetPercent()))
+		inputs["errno"] = v1alpha1.ParameterValue(fmt.Sprint(fault.Errno.Errno))
+
+	default:
+		return nil, errors.Errorf("disk fault '%s' is not injected by IOChaos", action.Name)
+	}
+
+	spec.MaxInstances = 1
+	spec.Inputs = []v1alpha1.UserInputs{inputs}
+
+	return &spec, nil
+}
+
+// DiskFillClusterSpec translates a fill fault into a Cluster of helper services, one for every claim that is mounted
+// at the volume path of the targets. Every helper runs on the node of its target, in order to mount the claim
+// alongside the target, fills the claim, and frees it once the duration has passed.
+func DiskFillClusterSpec(ctx context.Context, cli client.Client, action v1alpha1.Action, targets SList) (*v1alpha1.ClusterSpec, error) {
+	fill := action.DiskFault.Fill
+
+	var spec v1alpha1.ClusterSpec
+
+	spec.TemplateRef = configuration.TemplateRef(configuration.DiskFillTemplate)
+
+	// services that share a claim (e.g, ReadWriteMany) are filled once.
+	filled := make(map[string]bool, len(targets))
+
+	for _, target := range targets {
+		// services run as pods of the same name.
+		var pod corev1.Pod
+
+		if err := cli.Get(ctx, client.ObjectKeyFromObject(target), &pod); err != nil {
+			return nil, errors.Wrapf(err, "cannot get pod of service '%s'", target.GetName())
+		}
+
+		if pod.Spec.NodeName == "" {
+			return nil, errors.Errorf("service '%s' is not scheduled", target.GetName())
+		}
+
+		claim, err := claimAtPath(&pod, fill.VolumePath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "service '%s' error", target.GetName())
+		}
+
+		if filled[claim] {
+			continue
+		}
+
+		filled[claim] = true
+
+		spec.Inputs = append(spec.Inputs, v1alpha1.UserInputs{
+			"node":    v1alpha1.ParameterValue(pod.Spec.NodeName),
+			"claim":   v1alpha1.ParameterValue(claim),
+			"percent": v1alpha1.ParameterValue(fmt.Sprint(fill.Percent)),
+			"seconds": v1alpha1.ParameterValue(fmt.Sprint(int64(action.DiskFault.Duration.Seconds()))),
+		})
+	}
+
+	spec.MaxInstances = len(spec.Inputs)
+
+	return &spec, nil
+}
+
+// claimAtPath returns the claim that is mounted at the given path, in any of the containers of the pod.
+func claimAtPath(pod *corev1.Pod, mountPath string) (string, error) {
+	for _, container := range pod.Spec.Containers {
+		for _, mount := range container.VolumeMounts {
+			if mount.MountPath != mountPath {
+				continue
+			}
+
+			for _, volume := range pod.Spec.Volumes {
+				if volume.Name != mount.Name {
+					continue
+				}
+
+				if volume.PersistentVolumeClaim == nil {
+					return "", errors.Errorf("volume '%s' at '%s' is not a persistentVolumeClaim", volume.Name, mountPath)
+				}
+
+				return volume.PersistentVolumeClaim.ClaimName, nil
+			}
+		}
+	}
+
+	return "", errors.Errorf("no volume is mounted at '%s'", mountPath)
+}
@@ -20,12 +20,18 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strings"
+	"unicode"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/pkg/dashboard"
+	"github.com/carv-ics-forth/frisbee/pkg/structure"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 )
 
 func InstallGrafanaDashboards(ctx context.Context, reconciler common.Reconciler, scenario *v1alpha1.Scenario, spec *v1alpha1.ServiceSpec, telemetryAgents []string) error {
@@ -45,6 +51,11 @@ func InstallGrafanaDashboards(ctx context.Context, reconciler common.Reconciler,
 				return errors.Wrapf(err, "configmap '%s' is missing", key)
 			}
 
+			// malformed dashboards do not break Grafana, but silently disappear from it.
+			if err := dashboard.ValidateFiles(dashboards.Data, Datasources(scenario)...); err != nil {
+				return errors.Wrapf(err, "configmap '%s' has invalid dashboards", key)
+			}
+
 			// avoid duplicates that may be caused when multiple agents share the same dashboard
 			if _, exists := imported[dashboards.GetName()]; exists {
 				continue
@@ -67,11 +78,10 @@ func InstallGrafanaDashboards(ctx context.Context, reconciler common.Reconciler,
 			})
 
 			// mount the Pod volume to the main Grafana container.
-			if len(spec.Containers) != 1 {
-				return errors.Errorf("Grafana expected a single '%s' but found '%d' containers",
-					v1alpha1.MainContainerName, len(spec.Containers))
+			mainContainer := grafanaContainer(spec)
+			if mainContainer == nil {
+				return errors.Errorf("Grafana expected a '%s' container", v1alpha1.MainContainerName)
 			}
-			mainContainer := &spec.Containers[0]
 
 			for file := range dashboards.Data {
 				mainContainer.VolumeMounts = append(mainContainer.VolumeMounts, corev1.VolumeMount{
@@ -90,3 +100,182 @@ func InstallGrafanaDashboards(ctx context.Context, reconciler common.Reconciler,
 
 	return nil
 }
+
+// Datasources returns the additional datasources of the scenario, in the form known to the dashboard validation.
+func Datasources(scenario *v1alpha1.Scenario) []dashboard.Datasource {
+	declared := scenarioDatasources(scenario)
+	datasources := make([]dashboard.Datasource, 0, len(declared))
+
+	for _, ds := range declared {
+		datasources = append(datasources, dashboard.Datasource{Name: ds.Name, Type: ds.Type, UID: ds.UID})
+	}
+
+	return datasources
+}
+
+// scenarioDatasources returns the datasources declared by the scenario, along with the datasources of the
+// optional telemetry services (e.g, ingestion, tracing).
+func scenarioDatasources(scenario *v1alpha1.Scenario) []v1alpha1.GrafanaDatasource {
+	datasources := scenario.Spec.Datasources
+
+	if ingestion := scenario.Spec.Ingestion; ingestion != nil {
+		datasources = append(datasources[:len(datasources):len(datasources)], v1alpha1.GrafanaDatasource{
+			Name:     v1alpha1.IngestionDatasourceName,
+			Type:     "influxdb",
+			URL:      fmt.Sprintf("http://%s:%d", common.DefaultIngestionName, common.DefaultIngestionPort),
+			Database: ingestion.GetDatabase(),
+		})
+	}
+
+	if tracing := scenario.Spec.Tracing; tracing != nil {
+		datasources = append(datasources[:len(datasources):len(datasources)], tracingDatasource(tracing))
+	}
+
+	return datasources
+}
+
+// provisionedDatasource is the Grafana provisioning format of a datasource.
+type provisionedDatasource struct {
+	Name           string                           `json:"name"`
+	Type           string                           `json:"type"`
+	UID            string                           `json:"uid,omitempty"`
+	Access         string                           `json:"access"`
+	URL            string                           `json:"url"`
+	Database       string                           `json:"database,omitempty"`
+	Editable       bool                             `json:"editable"`
+	JSONData       map[string]*apiextensionsv1.JSON `json:"jsonData,omitempty"`
+	SecureJSONData map[string]string                `json:"secureJsonData,omitempty"`
+}
+
+// grafanaContainer returns the main container of Grafana. Other containers (e.g, the image renderer) are sidecars.
+func grafanaContainer(spec *v1alpha1.ServiceSpec) *corev1.Container {
+	for i := range spec.Containers {
+		if spec.Containers[i].Name == v1alpha1.MainContainerName {
+			return &spec.Containers[i]
+		}
+	}
+
+	return nil
+}
+
+// InstallGrafanaDatasources renders the additional datasources of the scenario into a provisioning file, and mounts
+// it into Grafana. The secure fields are passed as environment variables, which Grafana expands when it loads the
+// provisioning file.
+func InstallGrafanaDatasources(ctx context.Context, reconciler common.Reconciler, scenario *v1alpha1.Scenario, spec *v1alpha1.ServiceSpec) error {
+	datasources := scenarioDatasources(scenario)
+	if len(datasources) == 0 {
+		return nil
+	}
+
+	mainContainer := grafanaContainer(spec)
+	if mainContainer == nil {
+		return errors.Errorf("Grafana expected a '%s' container", v1alpha1.MainContainerName)
+	}
+
+	provisioned := make([]provisionedDatasource, 0, len(datasources))
+
+	for i, ds := range datasources {
+		entry := provisionedDatasource{
+			Name:     ds.Name,
+			Type:     ds.Type,
+			UID:      ds.UID,
+			Access:   "proxy",
+			URL:      ds.URL,
+			Database: ds.Database,
+			Editable: true,
+			JSONData: ds.JSONData,
+		}
+
+		if len(ds.SecureJSONData) > 0 {
+			entry.SecureJSONData = make(map[string]string, len(ds.SecureJSONData))
+
+			for _, key := range structure.SortedMapKeys(ds.SecureJSONData) {
+				ref := ds.SecureJSONData[key]
+				envName := datasourceEnv(i, key)
+
+				mainContainer.Env = append(mainContainer.Env, corev1.EnvVar{
+					Name:      envName,
+					ValueFrom: &corev1.EnvVarSource{SecretKeyRef: ref.DeepCopy()},
+				})
+
+				entry.SecureJSONData[key] = fmt.Sprintf("$__env{%s}", envName)
+			}
+		}
+
+		provisioned = append(provisioned, entry)
+	}
+
+	body, err := yaml.Marshal(map[string]interface{}{
+		"apiVersion":  1,
+		"datasources": provisioned,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "cannot encode datasources")
+	}
+
+	var config corev1.ConfigMap
+
+	config.SetName(DatasourcesConfigMapName)
+	config.Data = map[string]string{datasourcesFile: string(body)}
+
+	if err := common.Create(ctx, reconciler, scenario, &config); err != nil {
+		return errors.Wrapf(err, "cannot create configmap '%s'", config.GetName())
+	}
+
+	volumeName := fmt.Sprintf("vol-%d", len(spec.Volumes))
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name: volumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: config.GetName()},
+			},
+		},
+	})
+
+	mainContainer.VolumeMounts = append(mainContainer.VolumeMounts, corev1.VolumeMount{
+		Name:      volumeName,
+		ReadOnly:  true,
+		MountPath: filepath.Join(common.DefaultGrafanaDatasourcesPath, datasourcesFile),
+		SubPath:   datasourcesFile,
+	})
+
+	reconciler.Info("LoadDatasources", "obj", client.ObjectKeyFromObject(&config), "datasources", len(provisioned))
+
+	return nil
+}
+
+// tracingDatasource returns the datasource that points to the query interface of the tracing backend.
+func tracingDatasource(tracing *v1alpha1.TracingSpec) v1alpha1.GrafanaDatasource {
+	ds := v1alpha1.GrafanaDatasource{Name: v1alpha1.TracingDatasourceName}
+
+	switch tracing.GetBackend() {
+	case v1alpha1.TracingJaeger:
+		ds.Type = "jaeger"
+		ds.URL = fmt.Sprintf("http://%s:%d", common.DefaultTracingName, common.DefaultJaegerQueryPort)
+	default:
+		ds.Type = "tempo"
+		ds.URL = fmt.Sprintf("http://%s:%d", common.DefaultTracingName, common.DefaultTempoQueryPort)
+	}
+
+	return ds
+}
+
+const (
+	// DatasourcesConfigMapName is the ConfigMap that holds the provisioning file of the additional datasources.
+	DatasourcesConfigMapName = "grafana-datasources"
+
+	datasourcesFile = "scenario.yml"
+)
+
+// datasourceEnv returns the environment variable that holds a secure field of a datasource.
+func datasourceEnv(index int, key string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+
+		return '_'
+	}, key)
+
+	return fmt.Sprintf("GF_DATASOURCE_%d_%s", index, sanitized)
+}
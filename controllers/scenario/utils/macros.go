@@ -27,6 +27,7 @@ import (
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/carv-ics-forth/frisbee/controllers/common"
 	"github.com/pkg/errors"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -46,6 +47,56 @@ func (in SList) ToString() string {
 	return strings.Join(in.GetNames(), idListSeparator)
 }
 
+// ToQualifiedString is like ToString, but the services outside the given namespace are qualified with their
+// namespace (<service>.<namespace>), so that they can be resolved by DNS.
+func (in SList) ToQualifiedString(namespace string) string {
+	if len(in) == 0 {
+		return ""
+	}
+
+	names := make([]string, len(in))
+
+	for i, service := range in {
+		names[i] = qualifiedName(service, namespace)
+	}
+
+	return strings.Join(names, idListSeparator)
+}
+
+func qualifiedName(obj client.Object, namespace string) string {
+	if obj.GetNamespace() == namespace {
+		return obj.GetName()
+	}
+
+	return obj.GetName() + "." + obj.GetNamespace()
+}
+
+// getCluster returns the cluster of the given action. Clusters that are placed outside the namespace of the
+// scenario (see Scenario.JobNamespace) are found by their labels.
+func getCluster(ctx context.Context, cli client.Client, key client.ObjectKey, cluster *v1alpha1.Cluster) error {
+	err := cli.Get(ctx, key, cluster)
+	if !k8errors.IsNotFound(err) {
+		return err
+	}
+
+	var placed v1alpha1.ClusterList
+
+	if err := cli.List(ctx, &placed, client.MatchingLabels{
+		v1alpha1.LabelAction:          key.Name,
+		v1alpha1.LabelParentNamespace: key.Namespace,
+	}); err != nil {
+		return errors.Wrapf(err, "cannot list placed clusters")
+	}
+
+	if len(placed.Items) == 0 {
+		return err
+	}
+
+	placed.Items[0].DeepCopyInto(cluster)
+
+	return nil
+}
+
 func (in SList) GetNames() []string {
 	if len(in) == 0 {
 		return nil
@@ -64,6 +115,32 @@ func isMacro(macro string) bool {
 	return strings.HasPrefix(macro, ".")
 }
 
+// groupServiceFilter is a macro filter that resolves to the group service of a cluster,
+// instead of the individual services (e.g, .cluster.masters.service).
+const groupServiceFilter = "service"
+
+// expandGroupService resolves macros that point to the group service of a cluster.
+// It returns false if the macro does not refer to a group service.
+func expandGroupService(ctx context.Context, cli client.Client, namespace string, macro string) (string, bool, error) {
+	fields := strings.Split(macro, ".")
+
+	if len(fields) != 4 || fields[1] != "cluster" || fields[3] != groupServiceFilter {
+		return "", false, nil
+	}
+
+	var cluster v1alpha1.Cluster
+
+	if err := getCluster(ctx, cli, client.ObjectKey{Namespace: namespace, Name: fields[2]}, &cluster); err != nil {
+		return "", true, errors.Wrapf(err, "macro %s yields no cluster", macro)
+	}
+
+	if !cluster.Spec.GroupService {
+		return "", true, errors.Errorf("macro %s refers to cluster without group service", macro)
+	}
+
+	return qualifiedName(&cluster, namespace), true, nil
+}
+
 func parseMacro(namespace string, selector *v1alpha1.ServiceSelector) error {
 	fields := strings.Split(*selector.Macro, ".")
 
@@ -98,6 +175,17 @@ func ExpandSliceInputs(ctx context.Context, cli client.Client, namespace string,
 	// extend macros
 	for i, value := range *inputs {
 		if isMacro(value) {
+			groupService, ok, err := expandGroupService(ctx, cli, namespace, value)
+			if err != nil {
+				return errors.Wrapf(err, "input [%d]", i)
+			}
+
+			if ok {
+				(*inputs)[i] = groupService
+
+				continue
+			}
+
 			val := value
 			ss := &v1alpha1.ServiceSelector{Macro: &val}
 
@@ -128,7 +216,7 @@ func ExpandSliceInputs(ctx context.Context, cli client.Client, namespace string,
 				return errors.Wrapf(err, "filter by mode")
 			}
 
-			(*inputs)[i] = filteredServices.ToString()
+			(*inputs)[i] = filteredServices.ToQualifiedString(namespace)
 		}
 	}
 
@@ -151,6 +239,16 @@ func ExpandMacros(ctx context.Context, cli client.Client, nm string, inputs *[]v
 			value := rawValue.String()
 
 			if isMacro(value) {
+				groupService, ok, err := expandGroupService(ctx, cli, nm, value)
+				if err != nil {
+					return errors.Wrapf(err, "input [%d]", i)
+				}
+
+				if ok {
+					(*inputs)[i][key] = v1alpha1.ParameterValue(groupService)
+
+					continue
+				}
 
 				val := value
 				ss := &v1alpha1.ServiceSelector{Macro: &val}
@@ -182,7 +280,7 @@ func ExpandMacros(ctx context.Context, cli client.Client, nm string, inputs *[]v
 					return errors.Wrapf(err, "filter by mode")
 				}
 
-				(*inputs)[i][key] = v1alpha1.ParameterValue(filteredServices.ToString())
+				(*inputs)[i][key] = v1alpha1.ParameterValue(filteredServices.ToQualifiedString(nm))
 			}
 		}
 	}
@@ -190,6 +288,34 @@ func ExpandMacros(ctx context.Context, cli client.Client, nm string, inputs *[]v
 	return nil
 }
 
+// SelectServices resolves a selector into the running services that it matches, filtered by its mode.
+// If the mode is not set, all the matching services are returned.
+func SelectServices(ctx context.Context, cli client.Client, namespace string, selector *v1alpha1.ServiceSelector) (SList, error) {
+	ss := selector.DeepCopy()
+
+	if ss.Macro != nil {
+		if err := parseMacro(namespace, ss); err != nil {
+			return nil, errors.Wrapf(err, "macro error")
+		}
+	}
+
+	services, err := selectServices(ctx, cli, &ss.Match)
+	if err != nil {
+		return nil, errors.Wrapf(err, "service selection error")
+	}
+
+	if len(services) == 0 {
+		return nil, errors.New("the selector yields no running services")
+	}
+
+	mode := ss.Mode
+	if mode == "" {
+		mode = v1alpha1.AllMode
+	}
+
+	return filterByMode(services, mode, ss.Value)
+}
+
 func selectServices(ctx context.Context, cli client.Client, ss *v1alpha1.MatchBy) (SList, error) {
 	if ss == nil {
 		return nil, nil
@@ -230,7 +356,7 @@ func selectServices(ctx context.Context, cli client.Client, ss *v1alpha1.MatchBy
 		{
 			var cluster v1alpha1.Cluster
 
-			if err := cli.Get(ctx, key, &cluster); err != nil {
+			if err := getCluster(ctx, cli, key, &cluster); err != nil {
 				return nil, errors.Wrapf(err, "cannot find cluster %s", key)
 			}
 
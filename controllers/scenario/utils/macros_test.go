@@ -0,0 +1,82 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	scenarioutils "github.com/carv-ics-forth/frisbee/controllers/scenario/utils"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSelectServices_TargetNamespace(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	tenant := v1alpha1.ActionNamespace("default", "tenant-a")
+
+	// the cluster is placed in the target namespace, and it is linked to the scenario only by labels.
+	var cluster v1alpha1.Cluster
+
+	cluster.SetNamespace(tenant)
+	cluster.SetName("servers")
+	cluster.SetLabels(map[string]string{
+		v1alpha1.LabelAction:          "servers",
+		v1alpha1.LabelParentNamespace: "default",
+	})
+
+	objects := []client.Object{&cluster}
+
+	for _, name := range []string{"servers-1", "servers-2"} {
+		var service v1alpha1.Service
+
+		service.SetNamespace(tenant)
+		service.SetName(name)
+		service.SetLabels(map[string]string{v1alpha1.LabelCreatedBy: "servers"})
+		service.Status.Lifecycle.Phase = v1alpha1.PhaseRunning
+
+		objects = append(objects, &service)
+	}
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+	macro := ".cluster.servers.all"
+
+	services, err := scenarioutils.SelectServices(context.Background(), cli, "default", &v1alpha1.ServiceSelector{Macro: &macro})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(services) != 2 {
+		t.Fatalf("expected 2 services but got %d", len(services))
+	}
+
+	expected := "servers-1." + tenant + " servers-2." + tenant
+	if got := services.ToQualifiedString("default"); got != expected {
+		t.Errorf("expected '%s' but got '%s'", expected, got)
+	}
+
+	if got := services.ToQualifiedString(tenant); got != "servers-1 servers-2" {
+		t.Errorf("expected unqualified names but got '%s'", got)
+	}
+}
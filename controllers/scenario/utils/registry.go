@@ -0,0 +1,248 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	chaosutils "github.com/carv-ics-forth/frisbee/controllers/chaos/utils"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	serviceutils "github.com/carv-ics-forth/frisbee/controllers/service/utils"
+	"github.com/carv-ics-forth/frisbee/pkg/configuration"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// registryPollInterval is the period for checking whether the outage is in place.
+	registryPollInterval = 2 * time.Second
+
+	// registryReadyTimeout bounds the time for putting the outage in place, before the services are restarted.
+	registryReadyTimeout = 2 * time.Minute
+)
+
+// InjectRegistryOutage makes the registries unreachable from the nodes of the selected services, restarts the
+// services, and waits for the outage to be over. The helpers of the outage belong to the virtual job, so that
+// they are removed along with it.
+func InjectRegistryOutage(ctx context.Context, reconciler common.Reconciler, scenario *v1alpha1.Scenario,
+	vobj *v1alpha1.VirtualObject, action v1alpha1.Action,
+) error {
+	outage := action.RegistryOutage
+	cli := reconciler.GetClient()
+
+	targets, err := SelectServices(ctx, cli, scenario.GetNamespace(), &outage.Selector)
+	if err != nil {
+		return errors.Wrapf(err, "selector error")
+	}
+
+	/*---------------------------------------------------
+	 * Run a helper on the network of every affected node
+	 *---------------------------------------------------*/
+	pods := make(map[string]*corev1.Pod, len(targets))
+	nodeSet := make(map[string]bool, len(targets))
+
+	for _, target := range targets {
+		var pod corev1.Pod
+
+		if err := cli.Get(ctx, client.ObjectKeyFromObject(target), &pod); err != nil {
+			return errors.Wrapf(err, "cannot get pod of service '%s'", target.GetName())
+		}
+
+		if pod.Spec.NodeName == "" {
+			return errors.Errorf("service '%s' is not scheduled", target.GetName())
+		}
+
+		pods[target.GetName()] = &pod
+		nodeSet[pod.Spec.NodeName] = true
+	}
+
+	nodes := make([]string, 0, len(nodeSet))
+	for node := range nodeSet {
+		nodes = append(nodes, node)
+	}
+
+	sort.Strings(nodes)
+
+	blockers, err := createRegistryBlockers(ctx, reconciler, scenario, vobj, action.Name, nodes)
+	if err != nil {
+		return errors.Wrapf(err, "cannot create blockers")
+	}
+
+	defer func() {
+		// the blockers must not outlive the outage, as they share the network of the nodes.
+		for _, blocker := range blockers {
+			if err := cli.Delete(context.Background(), blocker); client.IgnoreNotFound(err) != nil {
+				reconciler.Info("Cannot delete blocker", "obj", client.ObjectKeyFromObject(blocker), "err", err)
+			}
+		}
+	}()
+
+	/*---------------------------------------------------
+	 * Block the registries from the helpers
+	 *---------------------------------------------------*/
+	blockerNames := make([]string, 0, len(blockers))
+	for _, blocker := range blockers {
+		blockerNames = append(blockerNames, blocker.GetName())
+	}
+
+	chaos, err := createRegistryChaos(ctx, reconciler, scenario, vobj, action, blockerNames)
+	if err != nil {
+		return errors.Wrapf(err, "cannot create network chaos")
+	}
+
+	isBlocked := func(ctx context.Context) (done bool, err error) {
+		if err := cli.Get(ctx, client.ObjectKeyFromObject(chaos), chaos); err != nil {
+			return false, errors.Wrapf(err, "cannot get chaos")
+		}
+
+		switch chaos.Status.Phase {
+		case v1alpha1.PhaseRunning:
+			return true, nil
+		case v1alpha1.PhaseFailed:
+			return false, errors.Errorf("chaos has failed: %s", chaos.Status.Message)
+		default:
+			return false, nil
+		}
+	}
+
+	if err := wait.PollUntilContextTimeout(ctx, registryPollInterval, registryReadyTimeout, true, isBlocked); err != nil {
+		return errors.Wrapf(err, "the registries are not blocked")
+	}
+
+	/*---------------------------------------------------
+	 * Restart the services, and wait for the outage
+	 *---------------------------------------------------*/
+	for _, target := range targets {
+		patch := client.MergeFrom(target.DeepCopy())
+
+		annotations := target.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+
+		annotations[v1alpha1.AnnotationRestart] = string(pods[target.GetName()].GetUID())
+		target.SetAnnotations(annotations)
+
+		if err := cli.Patch(ctx, target, patch); err != nil {
+			return errors.Wrapf(err, "cannot restart service '%s'", target.GetName())
+		}
+	}
+
+	select {
+	case <-time.After(outage.Duration.Duration):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// createRegistryBlockers runs a helper with the network of the node (hostNetwork) on every node, for the network
+// chaos to be injected into.
+func createRegistryBlockers(ctx context.Context, reconciler common.Reconciler, scenario *v1alpha1.Scenario,
+	vobj *v1alpha1.VirtualObject, actionName string, nodes []string,
+) ([]*corev1.Pod, error) {
+	fromTemplate := v1alpha1.GenerateObjectFromTemplate{
+		TemplateRef:  configuration.TemplateRef(configuration.RegistryBlockerTemplate),
+		MaxInstances: len(nodes),
+		Inputs:       make([]v1alpha1.UserInputs, 0, len(nodes)),
+	}
+
+	for _, node := range nodes {
+		fromTemplate.Inputs = append(fromTemplate.Inputs, v1alpha1.UserInputs{
+			"node": v1alpha1.ParameterValue(node),
+		})
+	}
+
+	specs, err := serviceutils.GetServiceSpecList(ctx, reconciler.GetClient(), scenario, fromTemplate)
+	if err != nil {
+		return nil, errors.Wrapf(err, "blocker spec")
+	}
+
+	blockers := make([]*corev1.Pod, 0, len(specs))
+
+	for i, spec := range specs {
+		var pod corev1.Pod
+
+		pod.SetName(fmt.Sprintf("%s-blocker-%d", actionName, i))
+		v1alpha1.SetScenarioLabel(&pod.ObjectMeta, scenario.GetName())
+		v1alpha1.SetActionLabel(&pod.ObjectMeta, actionName)
+		v1alpha1.SetComponentLabel(&pod.ObjectMeta, v1alpha1.ComponentSys)
+
+		spec.PodSpec.DeepCopyInto(&pod.Spec)
+
+		if err := common.Create(ctx, reconciler, vobj, &pod); err != nil {
+			return blockers, errors.Wrapf(err, "cannot create blocker '%s'", pod.GetName())
+		}
+
+		blockers = append(blockers, &pod)
+	}
+
+	return blockers, nil
+}
+
+// createRegistryChaos partitions the blockers from the registries, for the duration of the outage.
+func createRegistryChaos(ctx context.Context, reconciler common.Reconciler, scenario *v1alpha1.Scenario,
+	vobj *v1alpha1.VirtualObject, action v1alpha1.Action, blockers []string,
+) (*v1alpha1.Chaos, error) {
+	outage := action.RegistryOutage
+
+	spec, err := chaosutils.GetChaosSpec(ctx, reconciler.GetClient(), scenario, RegistryOutageChaosSpec(outage, blockers))
+	if err != nil {
+		return nil, errors.Wrapf(err, "chaos spec")
+	}
+
+	var chaos v1alpha1.Chaos
+
+	chaos.SetName(action.Name + "-network")
+	v1alpha1.SetScenarioLabel(&chaos.ObjectMeta, scenario.GetName())
+	v1alpha1.SetActionLabel(&chaos.ObjectMeta, action.Name)
+	v1alpha1.SetComponentLabel(&chaos.ObjectMeta, v1alpha1.ComponentSUT)
+
+	spec.DeepCopyInto(&chaos.Spec)
+
+	if err := common.Create(ctx, reconciler, vobj, &chaos); err != nil {
+		return nil, errors.Wrapf(err, "cannot create chaos '%s'", chaos.GetName())
+	}
+
+	return &chaos, nil
+}
+
+// RegistryOutageChaosSpec translates the outage into the network chaos of the blockers. If there are no blockers,
+// the default targets of the template are used, which is only meaningful for validating the template.
+func RegistryOutageChaosSpec(outage *v1alpha1.RegistryOutageSpec, blockers []string) v1alpha1.GenerateObjectFromTemplate {
+	inputs := v1alpha1.UserInputs{
+		"registries": v1alpha1.ParameterValue(strings.Join(outage.Registries, ", ")),
+		"duration":   v1alpha1.ParameterValue(outage.Duration.Duration.String()),
+	}
+
+	if len(blockers) > 0 {
+		inputs["targets"] = v1alpha1.ParameterValue(strings.Join(blockers, ", "))
+	}
+
+	return v1alpha1.GenerateObjectFromTemplate{
+		TemplateRef:  configuration.TemplateRef(configuration.RegistryOutageTemplate),
+		MaxInstances: 1,
+		Inputs:       []v1alpha1.UserInputs{inputs},
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/configuration"
+	"github.com/pkg/errors"
+)
+
+// SeedClusterSpec translates a seed action into a Cluster, whose every service seeds a single chunk.
+// The Cluster tracks the progress of the seeding as the percentage of the completed chunks.
+func SeedClusterSpec(scenario *v1alpha1.Scenario, action v1alpha1.Action) (*v1alpha1.ClusterSpec, error) {
+	seed := action.Seed
+	chunks := seed.Chunks()
+
+	if len(chunks) == 0 {
+		return nil, errors.Errorf("nothing to seed")
+	}
+
+	var spec v1alpha1.ClusterSpec
+
+	spec.MaxInstances = len(chunks)
+	spec.Inputs = make([]v1alpha1.UserInputs, 0, len(chunks))
+
+	switch {
+	case seed.Files != nil:
+		path := seed.Files.Path
+		if path == "" {
+			path = action.Name
+		}
+
+		spec.TemplateRef = configuration.TemplateRef(configuration.SeedFilesTemplate)
+		spec.TestData = scenario.Spec.TestData
+
+		for _, chunk := range chunks {
+			spec.Inputs = append(spec.Inputs, v1alpha1.UserInputs{
+				"path":  v1alpha1.ParameterValue(path),
+				"file":  v1alpha1.ParameterValue(fmt.Sprintf("chunk-%d", chunk.Index)),
+				"bytes": v1alpha1.ParameterValue(fmt.Sprint(chunk.Count)),
+			})
+		}
+
+	case seed.Database != nil:
+		db := seed.Database
+
+		switch db.Driver {
+		case v1alpha1.DriverPostgres:
+			spec.TemplateRef = configuration.TemplateRef(configuration.SeedPostgresTemplate)
+		case v1alpha1.DriverMySQL:
+			spec.TemplateRef = configuration.TemplateRef(configuration.SeedMySQLTemplate)
+		default:
+			return nil, errors.Errorf("unknown driver '%s'", db.Driver)
+		}
+
+		connection, err := db.RenderConnection(scenario)
+		if err != nil {
+			return nil, errors.Wrapf(err, "connection error")
+		}
+
+		for _, chunk := range chunks {
+			inputs := v1alpha1.UserInputs{
+				"connection": v1alpha1.ParameterValue(connection),
+				"table":      v1alpha1.ParameterValue(db.GetTable()),
+				"offset":     v1alpha1.ParameterValue(fmt.Sprint(chunk.Offset)),
+				"rows":       v1alpha1.ParameterValue(fmt.Sprint(chunk.Count)),
+				"rowSize":    v1alpha1.ParameterValue(fmt.Sprint(db.GetRowSize())),
+			}
+
+			if ref := db.PasswordRef; ref != nil {
+				inputs["passwordSecret"] = v1alpha1.ParameterValue(ref.Name)
+				inputs["passwordKey"] = v1alpha1.ParameterValue(ref.Key)
+			}
+
+			spec.Inputs = append(spec.Inputs, inputs)
+		}
+
+	default:
+		return nil, errors.Errorf("either files or database must be set")
+	}
+
+	return &spec, nil
+}
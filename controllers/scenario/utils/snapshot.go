@@ -0,0 +1,228 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// VolumeSnapshotGVK is the CSI VolumeSnapshot. It is handled as unstructured, in order to avoid depending on the
+// external-snapshotter client.
+var VolumeSnapshotGVK = schema.GroupVersionKind{
+	Group:   "snapshot.storage.k8s.io",
+	Version: "v1",
+	Kind:    "VolumeSnapshot",
+}
+
+// snapshotPollInterval is the period for checking whether the snapshots are ready to use.
+const snapshotPollInterval = 5 * time.Second
+
+// TakeSnapshots creates a VolumeSnapshot for every claim of the spec, and waits for the snapshots to become ready
+// to use. The snapshots are deliberately not owned by the scenario, so that they survive across runs.
+func TakeSnapshots(ctx context.Context, reconciler common.Reconciler, scenario *v1alpha1.Scenario, spec *v1alpha1.SnapshotSpec) error {
+	cli := reconciler.GetClient()
+
+	snapshots := make([]*unstructured.Unstructured, 0, len(spec.Claims))
+
+	for _, claimName := range spec.Claims {
+		var claim corev1.PersistentVolumeClaim
+
+		if err := cli.Get(ctx, client.ObjectKey{Namespace: scenario.GetNamespace(), Name: claimName}, &claim); err != nil {
+			return errors.Wrapf(err, "cannot get claim '%s'", claimName)
+		}
+
+		claimSpec, err := json.Marshal(restorableClaimSpec(&claim))
+		if err != nil {
+			return errors.Wrapf(err, "cannot encode the spec of claim '%s'", claimName)
+		}
+
+		snapshot := &unstructured.Unstructured{}
+		snapshot.SetGroupVersionKind(VolumeSnapshotGVK)
+		snapshot.SetNamespace(scenario.GetNamespace())
+		snapshot.SetName(v1alpha1.SnapshotName(spec.Set, claimName))
+		snapshot.SetLabels(map[string]string{v1alpha1.LabelSnapshotSet: spec.Set})
+		snapshot.SetAnnotations(map[string]string{
+			v1alpha1.AnnotationSnapshotClaim:     claimName,
+			v1alpha1.AnnotationSnapshotClaimSpec: string(claimSpec),
+		})
+
+		if err := unstructured.SetNestedField(snapshot.Object, claimName, "spec", "source", "persistentVolumeClaimName"); err != nil {
+			return errors.Wrapf(err, "cannot set source")
+		}
+
+		if class := spec.VolumeSnapshotClassName; class != nil {
+			if err := unstructured.SetNestedField(snapshot.Object, *class, "spec", "volumeSnapshotClassName"); err != nil {
+				return errors.Wrapf(err, "cannot set class")
+			}
+		}
+
+		reconciler.Info("++ Create", "kind", "VolumeSnapshot", "obj", client.ObjectKeyFromObject(snapshot))
+
+		if err := cli.Create(ctx, snapshot); err != nil {
+			if k8errors.IsAlreadyExists(err) {
+				// reusing a stale snapshot would silently restore outdated data.
+				return errors.Errorf("snapshot '%s' already exists. Delete it, or use another set", snapshot.GetName())
+			}
+
+			return errors.Wrapf(err, "cannot create snapshot '%s'", snapshot.GetName())
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	/*---------------------------------------------------
+	 * Wait for the snapshots to become ready to use
+	 *---------------------------------------------------*/
+	var notReady string
+
+	isReady := func(ctx context.Context) (done bool, err error) {
+		for _, snapshot := range snapshots {
+			if err := cli.Get(ctx, client.ObjectKeyFromObject(snapshot), snapshot); err != nil {
+				return false, errors.Wrapf(err, "cannot get snapshot '%s'", snapshot.GetName())
+			}
+
+			if ready, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse"); !ready {
+				notReady = snapshot.GetName()
+
+				// errors may be transient, and the snapshotter retries them.
+				if msg, found, _ := unstructured.NestedString(snapshot.Object, "status", "error", "message"); found {
+					notReady += ": " + msg
+				}
+
+				return false, nil
+			}
+		}
+
+		return true, nil
+	}
+
+	if err := wait.PollUntilContextTimeout(ctx, snapshotPollInterval, spec.GetTimeout(), true, isReady); err != nil {
+		return errors.Wrapf(err, "snapshot '%s' is not ready to use", notReady)
+	}
+
+	return nil
+}
+
+// RestoreSnapshots recreates the claims of a snapshot set, with the snapshots as data sources. The restored claims
+// belong to the scenario. It does not wait for the claims to be bound, as they may be bound on the first consumer.
+func RestoreSnapshots(ctx context.Context, reconciler common.Reconciler, scenario *v1alpha1.Scenario, spec *v1alpha1.RestoreSpec) error {
+	cli := reconciler.GetClient()
+
+	var snapshots unstructured.UnstructuredList
+
+	snapshots.SetGroupVersionKind(VolumeSnapshotGVK.GroupVersion().WithKind("VolumeSnapshotList"))
+
+	if err := cli.List(ctx, &snapshots,
+		client.InNamespace(scenario.GetNamespace()),
+		client.MatchingLabels{v1alpha1.LabelSnapshotSet: spec.Set},
+	); err != nil {
+		return errors.Wrapf(err, "cannot list snapshot set '%s'", spec.Set)
+	}
+
+	snapshotOf := make(map[string]*unstructured.Unstructured, len(snapshots.Items))
+
+	for i := range snapshots.Items {
+		snapshot := &snapshots.Items[i]
+
+		snapshotOf[snapshot.GetAnnotations()[v1alpha1.AnnotationSnapshotClaim]] = snapshot
+	}
+
+	if len(snapshotOf) == 0 {
+		return errors.Errorf("snapshot set '%s' is empty", spec.Set)
+	}
+
+	claims := spec.Claims
+	if len(claims) == 0 {
+		for claimName := range snapshotOf {
+			claims = append(claims, claimName)
+		}
+
+		sort.Strings(claims)
+	}
+
+	apiGroup := VolumeSnapshotGVK.Group
+
+	for _, claimName := range claims {
+		snapshot, exists := snapshotOf[claimName]
+		if !exists {
+			return errors.Errorf("claim '%s' is not in snapshot set '%s'", claimName, spec.Set)
+		}
+
+		if ready, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse"); !ready {
+			return errors.Errorf("snapshot '%s' is not ready to use", snapshot.GetName())
+		}
+
+		var claim corev1.PersistentVolumeClaim
+
+		if err := json.Unmarshal([]byte(snapshot.GetAnnotations()[v1alpha1.AnnotationSnapshotClaimSpec]), &claim.Spec); err != nil {
+			return errors.Wrapf(err, "cannot decode the spec of claim '%s'", claimName)
+		}
+
+		if spec.StorageClassName != nil {
+			claim.Spec.StorageClassName = spec.StorageClassName
+		}
+
+		claim.Spec.DataSource = &corev1.TypedLocalObjectReference{
+			APIGroup: &apiGroup,
+			Kind:     VolumeSnapshotGVK.Kind,
+			Name:     snapshot.GetName(),
+		}
+
+		claim.SetNamespace(scenario.GetNamespace())
+		claim.SetName(claimName)
+
+		// an existing claim would be silently used in place of the restored one.
+		var existing corev1.PersistentVolumeClaim
+
+		switch err := cli.Get(ctx, client.ObjectKeyFromObject(&claim), &existing); {
+		case err == nil:
+			return errors.Errorf("claim '%s' already exists", claimName)
+		case !k8errors.IsNotFound(err):
+			return errors.Wrapf(err, "cannot get claim '%s'", claimName)
+		}
+
+		if err := common.Create(ctx, reconciler, scenario, &claim); err != nil {
+			return errors.Wrapf(err, "cannot restore claim '%s'", claimName)
+		}
+	}
+
+	return nil
+}
+
+// restorableClaimSpec keeps the part of the claim spec that is needed to recreate the claim. Fields that are bound
+// to the original volume (e.g, volumeName) are dropped.
+func restorableClaimSpec(claim *corev1.PersistentVolumeClaim) corev1.PersistentVolumeClaimSpec {
+	return corev1.PersistentVolumeClaimSpec{
+		AccessModes:      claim.Spec.AccessModes,
+		Resources:        claim.Spec.Resources,
+		StorageClassName: claim.Spec.StorageClassName,
+		VolumeMode:       claim.Spec.VolumeMode,
+	}
+}
@@ -52,7 +52,7 @@ func DeployDataviewer(ctx context.Context, reconciler common.Reconciler, scenari
 
 	{ // spec
 		spec, err := serviceutils.GetServiceSpec(ctx, reconciler.GetClient(), scenario, v1alpha1.GenerateObjectFromTemplate{
-			TemplateRef:  configuration.DataviewerTemplate,
+			TemplateRef:  configuration.TemplateRef(configuration.DataviewerTemplate),
 			MaxInstances: 1,
 			Inputs:       nil,
 		})
@@ -86,7 +86,7 @@ func DeployPrometheus(ctx context.Context, reconciler common.Reconciler, scenari
 
 	{ // spec
 		spec, err := serviceutils.GetServiceSpec(ctx, reconciler.GetClient(), scenario, v1alpha1.GenerateObjectFromTemplate{
-			TemplateRef:  configuration.PrometheusTemplate,
+			TemplateRef:  configuration.TemplateRef(configuration.PrometheusTemplate),
 			MaxInstances: 1,
 			Inputs:       nil,
 		})
@@ -111,6 +111,86 @@ func DeployPrometheus(ctx context.Context, reconciler common.Reconciler, scenari
 	return nil
 }
 
+// DeployIngestion deploys the service that receives the metrics pushed by the benchmark tools.
+func DeployIngestion(ctx context.Context, reconciler common.Reconciler, scenario *v1alpha1.Scenario) error {
+	ingestion := scenario.Spec.Ingestion
+
+	var job v1alpha1.Service
+
+	job.SetName(common.DefaultIngestionName)
+
+	// set labels
+	v1alpha1.SetScenarioLabel(&job.ObjectMeta, scenario.GetName())
+	v1alpha1.SetComponentLabel(&job.ObjectMeta, v1alpha1.ComponentSys)
+
+	{ // spec
+		spec, err := serviceutils.GetServiceSpec(ctx, reconciler.GetClient(), scenario, v1alpha1.GenerateObjectFromTemplate{
+			TemplateRef:  configuration.TemplateRef(configuration.IngestionTemplate),
+			MaxInstances: 1,
+			Inputs: []v1alpha1.UserInputs{{
+				"database": v1alpha1.ParameterValue(ingestion.GetDatabase()),
+				"graphite": v1alpha1.ParameterValue(ingestion.Protocol == v1alpha1.IngestionGraphite),
+			}},
+		})
+		if err != nil {
+			return errors.Wrapf(err, "cannot get spec")
+		}
+
+		spec.DeepCopyInto(&job.Spec)
+	}
+
+	if err := common.Create(ctx, reconciler, scenario, &job); err != nil {
+		return errors.Wrapf(err, "cannot create %s", job.GetName())
+	}
+
+	scenario.Status.IngestionEndpoint = common.ExternalEndpoint(common.DefaultIngestionName, scenario.GetNamespace())
+
+	return nil
+}
+
+// DeployTracing deploys the backend that receives the traces of the system under test.
+func DeployTracing(ctx context.Context, reconciler common.Reconciler, scenario *v1alpha1.Scenario) error {
+	var templateRef string
+
+	switch backend := scenario.Spec.Tracing.GetBackend(); backend {
+	case v1alpha1.TracingTempo:
+		templateRef = configuration.TemplateRef(configuration.TempoTemplate)
+	case v1alpha1.TracingJaeger:
+		templateRef = configuration.TemplateRef(configuration.JaegerTemplate)
+	default:
+		return errors.Errorf("unknown tracing backend '%s'", backend)
+	}
+
+	var job v1alpha1.Service
+
+	job.SetName(common.DefaultTracingName)
+
+	// set labels
+	v1alpha1.SetScenarioLabel(&job.ObjectMeta, scenario.GetName())
+	v1alpha1.SetComponentLabel(&job.ObjectMeta, v1alpha1.ComponentSys)
+
+	{ // spec
+		spec, err := serviceutils.GetServiceSpec(ctx, reconciler.GetClient(), scenario, v1alpha1.GenerateObjectFromTemplate{
+			TemplateRef:  templateRef,
+			MaxInstances: 1,
+			Inputs:       nil,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "cannot get spec")
+		}
+
+		spec.DeepCopyInto(&job.Spec)
+	}
+
+	if err := common.Create(ctx, reconciler, scenario, &job); err != nil {
+		return errors.Wrapf(err, "cannot create %s", job.GetName())
+	}
+
+	scenario.Status.TracingEndpoint = common.InternalEndpoint(common.DefaultTracingName, scenario.GetNamespace(), common.DefaultTracingOTLPPort)
+
+	return nil
+}
+
 func DeployGrafana(ctx context.Context, reconciler common.Reconciler, scenario *v1alpha1.Scenario, agentRefs []string) error {
 	var job v1alpha1.Service
 
@@ -121,7 +201,7 @@ func DeployGrafana(ctx context.Context, reconciler common.Reconciler, scenario *
 
 	{ // spec
 		spec, err := serviceutils.GetServiceSpec(ctx, reconciler.GetClient(), scenario, v1alpha1.GenerateObjectFromTemplate{
-			TemplateRef:  configuration.GrafanaTemplate,
+			TemplateRef:  configuration.TemplateRef(configuration.GrafanaTemplate),
 			MaxInstances: 1,
 			Inputs:       nil,
 		})
@@ -136,6 +216,10 @@ func DeployGrafana(ctx context.Context, reconciler common.Reconciler, scenario *
 		if err := InstallGrafanaDashboards(ctx, reconciler, scenario, &job.Spec, agentRefs); err != nil {
 			return errors.Wrapf(err, "import dashboards")
 		}
+
+		if err := InstallGrafanaDatasources(ctx, reconciler, scenario, &job.Spec); err != nil {
+			return errors.Wrapf(err, "import datasources")
+		}
 	}
 
 	if err := common.Create(ctx, reconciler, scenario, &job); err != nil {
@@ -22,8 +22,12 @@ import (
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	chaosutils "github.com/carv-ics-forth/frisbee/controllers/chaos/utils"
 	serviceutils "github.com/carv-ics-forth/frisbee/controllers/service/utils"
+	templateutils "github.com/carv-ics-forth/frisbee/controllers/template/utils"
+	"github.com/carv-ics-forth/frisbee/pkg/configuration"
 	"github.com/carv-ics-forth/frisbee/pkg/infrastructure"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/json"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -56,10 +60,29 @@ func LoadTemplates(ctx context.Context, cli client.Client, scenario *v1alpha1.Sc
 				return errors.Wrapf(err, "input error")
 			}
 
+			for i := range action.Cluster.Variants {
+				if err := ExpandMacros(ctx, cli, scenario.GetNamespace(), &action.Cluster.Variants[i].Inputs); err != nil {
+					return errors.Wrapf(err, "input error")
+				}
+			}
+
 			if _, err := serviceutils.GetServiceSpecList(ctx, cli, scenario, action.Cluster.GenerateObjectFromTemplate); err != nil {
 				return errors.Wrapf(err, "cluster '%s' error", action.Name)
 			}
 
+			if len(action.Cluster.Variants) > 0 {
+				variants, err := action.Cluster.VariantTemplates()
+				if err != nil {
+					return errors.Wrapf(err, "cluster '%s' error", action.Name)
+				}
+
+				for i, variant := range variants {
+					if _, err := serviceutils.GetServiceSpecList(ctx, cli, scenario, variant); err != nil {
+						return errors.Wrapf(err, "cluster '%s' variant '%d' error", action.Name, i)
+					}
+				}
+			}
+
 			// LoadTemplates Placement Policies
 			if action.Cluster.Placement != nil {
 				// ensure there are at least two physical nodes for placement to make sense
@@ -100,11 +123,188 @@ func LoadTemplates(ctx context.Context, cli client.Client, scenario *v1alpha1.Sc
 
 			// TODO: now that the templates are loaded, ensure that the referenced callables exist.
 
-		case v1alpha1.ActionDelete:
-			// calls and deletes do not involve templates.
-			return nil
+		case v1alpha1.ActionSeed:
+			// seeds are generated from the system templates.
+			spec, err := SeedClusterSpec(scenario, *action)
+			if err != nil {
+				return errors.Wrapf(err, "seed '%s' error", action.Name)
+			}
+
+			if _, err := serviceutils.GetServiceSpecList(ctx, cli, scenario, spec.GenerateObjectFromTemplate); err != nil {
+				return errors.Wrapf(err, "seed '%s' error", action.Name)
+			}
+
+		case v1alpha1.ActionAssertSQL:
+			// assertions are generated from the system templates.
+			fromTemplate, err := AssertSQLServiceSpec(scenario, *action)
+			if err != nil {
+				return errors.Wrapf(err, "assertSQL '%s' error", action.Name)
+			}
+
+			if _, err := serviceutils.GetServiceSpecList(ctx, cli, scenario, *fromTemplate); err != nil {
+				return errors.Wrapf(err, "assertSQL '%s' error", action.Name)
+			}
+
+		case v1alpha1.ActionConsistency:
+			// checkers are either built-in, or given by the user.
+			if _, err := serviceutils.GetServiceSpecList(ctx, cli, scenario, *ConsistencyServiceSpec(*action)); err != nil {
+				return errors.Wrapf(err, "consistency '%s' error", action.Name)
+			}
+
+		case v1alpha1.ActionClockSkew:
+			if err := ExpandSliceInputs(ctx, cli, scenario.GetNamespace(), &action.ClockSkew.Services); err != nil {
+				return errors.Wrapf(err, "input error")
+			}
+
+			// skews are generated from the system templates.
+			spec := ClockSkewCascadeSpec(*action)
+
+			if _, err := chaosutils.GetChaosSpecList(ctx, cli, scenario, spec.GenerateObjectFromTemplate); err != nil {
+				return errors.Wrapf(err, "clockSkew '%s' error", action.Name)
+			}
+
+		case v1alpha1.ActionDiskFault:
+			// the targets are selected when the action runs. Until then, only the system templates are validated.
+			if action.DiskFault.Fill != nil {
+				fromTemplate := v1alpha1.GenerateObjectFromTemplate{
+					TemplateRef:  configuration.TemplateRef(configuration.DiskFillTemplate),
+					MaxInstances: 1,
+				}
+
+				if _, err := serviceutils.GetServiceSpecList(ctx, cli, scenario, fromTemplate); err != nil {
+					return errors.Wrapf(err, "diskFault '%s' error", action.Name)
+				}
+
+				continue
+			}
+
+			fromTemplate, err := DiskFaultChaosSpec(*action, nil)
+			if err != nil {
+				return errors.Wrapf(err, "diskFault '%s' error", action.Name)
+			}
+
+			if _, err := chaosutils.GetChaosSpec(ctx, cli, scenario, *fromTemplate); err != nil {
+				return errors.Wrapf(err, "diskFault '%s' error", action.Name)
+			}
+
+		case v1alpha1.ActionRegistryOutage:
+			// the targets are selected when the action runs. Until then, only the system templates are validated.
+			blocker := v1alpha1.GenerateObjectFromTemplate{
+				TemplateRef:  configuration.TemplateRef(configuration.RegistryBlockerTemplate),
+				MaxInstances: 1,
+			}
+
+			if _, err := serviceutils.GetServiceSpecList(ctx, cli, scenario, blocker); err != nil {
+				return errors.Wrapf(err, "registryOutage '%s' error", action.Name)
+			}
+
+			if _, err := chaosutils.GetChaosSpec(ctx, cli, scenario, RegistryOutageChaosSpec(action.RegistryOutage, nil)); err != nil {
+				return errors.Wrapf(err, "registryOutage '%s' error", action.Name)
+			}
+
+		case v1alpha1.ActionControlPlaneFault:
+			// faults that affect the whole cluster are rejected before the scenario starts.
+			if err := CheckControlPlaneFaultsAllowed(); err != nil {
+				return errors.Wrapf(err, "controlPlaneFault '%s' error", action.Name)
+			}
+
+			// the control-plane nodes are discovered when the action runs. Until then, only the system templates
+			// are validated.
+			if action.ControlPlaneFault.Pause != nil {
+				fromTemplate := v1alpha1.GenerateObjectFromTemplate{
+					TemplateRef:  configuration.TemplateRef(configuration.ControlPlanePauseTemplate),
+					MaxInstances: 1,
+				}
+
+				if _, err := serviceutils.GetServiceSpecList(ctx, cli, scenario, fromTemplate); err != nil {
+					return errors.Wrapf(err, "controlPlaneFault '%s' error", action.Name)
+				}
+
+				continue
+			}
+
+			if _, err := chaosutils.GetChaosSpec(ctx, cli, scenario, APIServerLatencyChaosSpec(*action)); err != nil {
+				return errors.Wrapf(err, "controlPlaneFault '%s' error", action.Name)
+			}
+
+		case v1alpha1.ActionDelete, v1alpha1.ActionSnapshot, v1alpha1.ActionRestore, v1alpha1.ActionTLSFault,
+			v1alpha1.ActionRawChaos, v1alpha1.ActionTrigger:
+			// deletes, snapshots, certificate swaps, inline faults, and triggers do not involve templates.
+			continue
+		}
+	}
+
+	// LoadTemplates of Chained Scenarios
+	if next := scenario.Spec.Next; next != nil {
+		if next.OnSuccess != nil {
+			if _, err := GetScenarioSpec(ctx, cli, scenario, *next.OnSuccess); err != nil {
+				return errors.Wrapf(err, "next.onSuccess error")
+			}
+		}
+
+		if next.OnFailure != nil {
+			if _, err := GetScenarioSpec(ctx, cli, scenario, *next.OnFailure); err != nil {
+				return errors.Wrapf(err, "next.onFailure error")
+			}
 		}
 	}
 
 	return nil
 }
+
+// GetScenarioSpec generates a scenario spec from a template. It is used for chaining scenarios.
+func GetScenarioSpec(ctx context.Context, cli client.Client, parent metav1.Object, fromTemplate v1alpha1.GenerateObjectFromTemplate) (v1alpha1.ScenarioSpec, error) {
+	/*
+		Get Scenario Templates
+	*/
+	key := client.ObjectKey{
+		Namespace: v1alpha1.GetScenarioNamespace(parent),
+		Name:      fromTemplate.TemplateRef,
+	}
+
+	template, err := templateutils.GetTemplate(ctx, cli, key)
+	if err != nil {
+		return v1alpha1.ScenarioSpec{}, errors.Wrapf(err, "cannot get template")
+	}
+
+	if template.Spec.Scenario == nil {
+		return v1alpha1.ScenarioSpec{}, errors.Errorf("template '%s' does not define a scenario", key.String())
+	}
+
+	/*
+		Convert Scenario Template to JSON and expand inputs
+	*/
+	body, err := json.Marshal(template.Spec.Scenario)
+	if err != nil {
+		return v1alpha1.ScenarioSpec{}, errors.Errorf("cannot marshal scenario of '%s'", fromTemplate.TemplateRef)
+	}
+
+	// add extra fields in the template
+	if template.Spec.Inputs == nil {
+		var inputs v1alpha1.TemplateInputs
+		template.Spec.Inputs = &inputs
+	}
+
+	template.Spec.Inputs.Scenario = v1alpha1.GetScenarioLabel(parent)
+	template.Spec.Inputs.Namespace = parent.GetNamespace()
+
+	if err := templateutils.SetScenarioVars(ctx, cli, parent, &template.Spec); err != nil {
+		return v1alpha1.ScenarioSpec{}, errors.Wrapf(err, "cannot set vars of '%s'", fromTemplate.TemplateRef)
+	}
+
+	// fetch the values that are kept in external stores
+	if err := templateutils.ResolveExternalInputs(ctx, &template.Spec, &fromTemplate); err != nil {
+		return v1alpha1.ScenarioSpec{}, errors.Wrapf(err, "cannot resolve external inputs of '%s'", fromTemplate.TemplateRef)
+	}
+
+	/*
+		Generate Scenario Spec using the expanded inputs
+	*/
+	var spec v1alpha1.ScenarioSpec
+
+	if err := fromTemplate.Generate(&spec, 0, template.Spec, body); err != nil {
+		return v1alpha1.ScenarioSpec{}, errors.Wrapf(err, "evaluation of template '%s' has failed", fromTemplate.TemplateRef)
+	}
+
+	return spec, nil
+}
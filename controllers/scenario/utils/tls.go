@@ -0,0 +1,241 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// tlsRestoreTimeout bounds the restoration of the original certificate, which runs even if the fault is aborted.
+const tlsRestoreTimeout = 30 * time.Second
+
+// InjectTLSFault replaces the certificate of a TLS secret with a faulty one, waits for the duration of the fault,
+// and restores the original certificate. The original data are kept in a backup secret until they are restored.
+func InjectTLSFault(ctx context.Context, reconciler common.Reconciler, scenario *v1alpha1.Scenario, spec *v1alpha1.TLSFaultSpec) error {
+	cli := reconciler.GetClient()
+
+	var secret corev1.Secret
+
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: scenario.GetNamespace(), Name: spec.Secret}, &secret); err != nil {
+		return errors.Wrapf(err, "cannot get secret '%s'", spec.Secret)
+	}
+
+	original, err := parseCertificate(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return errors.Wrapf(err, "secret '%s' error", spec.Secret)
+	}
+
+	var issuer *tls.Certificate
+
+	if spec.IssuerSecret != "" {
+		var issuerSecret corev1.Secret
+
+		if err := cli.Get(ctx, client.ObjectKey{Namespace: scenario.GetNamespace(), Name: spec.IssuerSecret}, &issuerSecret); err != nil {
+			return errors.Wrapf(err, "cannot get issuer secret '%s'", spec.IssuerSecret)
+		}
+
+		pair, err := tls.X509KeyPair(issuerSecret.Data[corev1.TLSCertKey], issuerSecret.Data[corev1.TLSPrivateKeyKey])
+		if err != nil {
+			return errors.Wrapf(err, "issuer secret '%s' error", spec.IssuerSecret)
+		}
+
+		issuer = &pair
+	}
+
+	certPEM, keyPEM, err := faultyCertificate(original, issuer, spec.Fault)
+	if err != nil {
+		return errors.Wrapf(err, "cannot generate faulty certificate")
+	}
+
+	/*---------------------------------------------------
+	 * Back up the original data, and swap the certificate
+	 *---------------------------------------------------*/
+	backup := corev1.Secret{
+		Type: secret.Type,
+		Data: secret.Data,
+	}
+
+	backup.SetNamespace(secret.GetNamespace())
+	backup.SetName(v1alpha1.TLSBackupName(secret.GetName()))
+	v1alpha1.SetScenarioLabel(&backup.ObjectMeta, scenario.GetName())
+
+	// the backup is deliberately not owned by the scenario, as it must outlive an interrupted fault.
+	if err := cli.Create(ctx, &backup); err != nil {
+		if k8errors.IsAlreadyExists(err) {
+			return errors.Errorf("backup '%s' already exists. A previous fault was interrupted, and '%s' must be restored from it",
+				backup.GetName(), secret.GetName())
+		}
+
+		return errors.Wrapf(err, "cannot back up secret '%s'", secret.GetName())
+	}
+
+	faulty := secret.DeepCopy()
+	faulty.Data[corev1.TLSCertKey] = certPEM
+	faulty.Data[corev1.TLSPrivateKeyKey] = keyPEM
+
+	reconciler.Info("Inject TLS fault", "secret", client.ObjectKeyFromObject(&secret), "fault", spec.Fault)
+
+	if err := cli.Update(ctx, faulty); err != nil {
+		return errors.Wrapf(err, "cannot swap certificate of secret '%s'", secret.GetName())
+	}
+
+	/*---------------------------------------------------
+	 * Wait for the fault, and restore the original data
+	 *---------------------------------------------------*/
+	select {
+	case <-time.After(spec.Duration.Duration):
+	case <-ctx.Done():
+	}
+
+	// the fault may have been aborted, but the original certificate must be restored anyway.
+	restoreCtx, cancel := context.WithTimeout(context.Background(), tlsRestoreTimeout)
+	defer cancel()
+
+	if err := restoreTLSSecret(restoreCtx, cli, &backup, secret.GetName()); err != nil {
+		return errors.Wrapf(err, "cannot restore secret '%s' from backup '%s'", secret.GetName(), backup.GetName())
+	}
+
+	reconciler.Info("Restore TLS secret", "secret", client.ObjectKeyFromObject(&secret))
+
+	return ctx.Err()
+}
+
+// restoreTLSSecret copies the data of the backup into the secret, and removes the backup.
+func restoreTLSSecret(ctx context.Context, cli client.Client, backup *corev1.Secret, name string) error {
+	var secret corev1.Secret
+
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: backup.GetNamespace(), Name: name}, &secret); err != nil {
+		return errors.Wrapf(err, "cannot get secret")
+	}
+
+	secret.Data = backup.Data
+
+	if err := cli.Update(ctx, &secret); err != nil {
+		return errors.Wrapf(err, "cannot update secret")
+	}
+
+	if err := cli.Delete(ctx, backup); client.IgnoreNotFound(err) != nil {
+		return errors.Wrapf(err, "cannot delete backup")
+	}
+
+	return nil
+}
+
+// parseCertificate returns the leaf certificate of a PEM chain.
+func parseCertificate(chain []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(chain)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.Errorf("no PEM certificate in '%s'", corev1.TLSCertKey)
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// faultyCertificate derives a certificate with the given defect from the original certificate. The certificate is
+// signed by the issuer, if any, or it is self-signed.
+func faultyCertificate(original *x509.Certificate, issuer *tls.Certificate, fault v1alpha1.TLSFaultType) (certPEM, keyPEM []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "cannot generate key")
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "cannot generate serial number")
+	}
+
+	now := time.Now()
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      original.Subject,
+		DNSNames:     original.DNSNames,
+		IPAddresses:  original.IPAddresses,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  original.ExtKeyUsage,
+	}
+
+	switch fault {
+	case v1alpha1.TLSFaultExpired:
+		template.NotBefore = now.Add(-48 * time.Hour)
+		template.NotAfter = now.Add(-24 * time.Hour)
+
+	case v1alpha1.TLSFaultNotYetValid:
+		template.NotBefore = now.Add(24 * time.Hour)
+		template.NotAfter = now.Add(48 * time.Hour)
+
+	case v1alpha1.TLSFaultWrongHost:
+		template.Subject = pkix.Name{CommonName: "wrong-host.frisbee.invalid"}
+		template.DNSNames = []string{"wrong-host.frisbee.invalid"}
+		template.IPAddresses = nil
+
+	case v1alpha1.TLSFaultUntrusted:
+		// an unknown authority is emulated by a self-signed certificate.
+		issuer = nil
+
+	default:
+		return nil, nil, errors.Errorf("unknown fault '%s'", fault)
+	}
+
+	parent, signer := template, crypto.Signer(key)
+
+	if issuer != nil {
+		if parent, err = x509.ParseCertificate(issuer.Certificate[0]); err != nil {
+			return nil, nil, errors.Wrapf(err, "cannot parse issuer")
+		}
+
+		var ok bool
+
+		if signer, ok = issuer.PrivateKey.(crypto.Signer); !ok {
+			return nil, nil, errors.New("the key of the issuer cannot sign")
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "cannot sign certificate")
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "cannot encode key")
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+
+	return certPEM, keyPEM, nil
+}
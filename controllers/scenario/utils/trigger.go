@@ -0,0 +1,217 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxTriggerBody bounds the size of the callbacks, since they are read before the signature is verified.
+const maxTriggerBody = 64 * 1024
+
+var (
+	// ErrTriggerNotPending indicates a callback for an action that does not wait for one.
+	ErrTriggerNotPending = errors.New("no pending trigger")
+
+	// ErrTriggerSignature indicates a callback whose signature does not match the body.
+	ErrTriggerSignature = errors.New("invalid signature")
+)
+
+// pendingTrigger is an action that waits for a callback.
+type pendingTrigger struct {
+	key []byte
+
+	release chan v1alpha1.TriggerPayload
+}
+
+// TriggerDispatcher passes the verified callbacks of the webhook to the actions that wait for them.
+type TriggerDispatcher struct {
+	lock sync.Mutex
+
+	// pending holds the actions that wait for a callback, indexed by TriggerID.
+	pending map[string]*pendingTrigger
+}
+
+// NewTriggerDispatcher returns a dispatcher without pending actions.
+func NewTriggerDispatcher() *TriggerDispatcher {
+	return &TriggerDispatcher{pending: make(map[string]*pendingTrigger)}
+}
+
+// TriggerID identifies the action of a scenario, as given in the path of the callback.
+func TriggerID(namespace string, scenario string, action string) string {
+	return path.Join(namespace, scenario, action)
+}
+
+// SignTrigger returns the signature of the body, as expected in the v1alpha1.TriggerSignatureHeader.
+func SignTrigger(key []byte, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Arm registers the action as pending, and returns the channel that receives its callback.
+// The returned function unregisters the action.
+func (d *TriggerDispatcher) Arm(id string, key []byte) (<-chan v1alpha1.TriggerPayload, func()) {
+	trigger := &pendingTrigger{key: key, release: make(chan v1alpha1.TriggerPayload, 1)}
+
+	d.lock.Lock()
+	d.pending[id] = trigger
+	d.lock.Unlock()
+
+	return trigger.release, func() {
+		d.lock.Lock()
+		defer d.lock.Unlock()
+
+		if d.pending[id] == trigger {
+			delete(d.pending, id)
+		}
+	}
+}
+
+// Release verifies the callback, and passes it to the pending action. Every action is released at most once.
+func (d *TriggerDispatcher) Release(id string, body []byte, signature string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	trigger, ok := d.pending[id]
+	if !ok {
+		return errors.Wrapf(ErrTriggerNotPending, "trigger '%s'", id)
+	}
+
+	if !hmac.Equal([]byte(SignTrigger(trigger.key, body)), []byte(signature)) {
+		return errors.Wrapf(ErrTriggerSignature, "trigger '%s'", id)
+	}
+
+	var payload v1alpha1.TriggerPayload
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return errors.Wrapf(err, "invalid payload")
+	}
+
+	if payload.Action != path.Base(id) {
+		return errors.Errorf("payload is for action '%s', not for '%s'", payload.Action, path.Base(id))
+	}
+
+	trigger.release <- payload
+
+	delete(d.pending, id)
+
+	return nil
+}
+
+// HandleWebhook returns the HTTP handler of the callbacks, which is served under v1alpha1.TriggerPath.
+func (d *TriggerDispatcher) HandleWebhook() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, v1alpha1.TriggerPath)
+
+		if parts := strings.Split(id, "/"); len(parts) != 3 {
+			http.Error(w, "expected "+v1alpha1.TriggerPath+"<namespace>/<scenario>/<action>", http.StatusNotFound)
+
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxTriggerBody))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+
+			return
+		}
+
+		err = d.Release(id, body, r.Header.Get(v1alpha1.TriggerSignatureHeader))
+
+		switch {
+		case err == nil:
+			w.WriteHeader(http.StatusAccepted)
+		case errors.Is(err, ErrTriggerNotPending):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, ErrTriggerSignature):
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		default:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	})
+}
+
+// WaitForTrigger blocks the action until it is released by a callback, or until the timeout of the trigger expires.
+// A rejected callback fails the action.
+func WaitForTrigger(ctx context.Context, reconciler common.Reconciler, dispatcher *TriggerDispatcher,
+	scenario *v1alpha1.Scenario, action v1alpha1.Action,
+) error {
+	trigger := action.Trigger
+
+	var secret corev1.Secret
+
+	if err := reconciler.GetClient().Get(ctx, client.ObjectKey{Namespace: scenario.GetNamespace(), Name: trigger.Secret}, &secret); err != nil {
+		return errors.Wrapf(err, "cannot get secret '%s'", trigger.Secret)
+	}
+
+	key := secret.Data[trigger.GetKey()]
+	if len(key) == 0 {
+		return errors.Errorf("secret '%s' has no key '%s'", trigger.Secret, trigger.GetKey())
+	}
+
+	id := TriggerID(scenario.GetNamespace(), scenario.GetName(), action.Name)
+
+	release, disarm := dispatcher.Arm(id, key)
+	defer disarm()
+
+	reconciler.Info("Wait for trigger", "path", v1alpha1.TriggerPath+id, "timeout", trigger.GetTimeout())
+
+	timer := time.NewTimer(trigger.GetTimeout())
+	defer timer.Stop()
+
+	select {
+	case payload := <-release:
+		reconciler.GetEventRecorderFor(scenario.GetName()).Event(scenario, corev1.EventTypeNormal,
+			"Triggered", action.Name+": "+payload.Message)
+
+		if payload.Reject {
+			return errors.Errorf("trigger '%s' was rejected: %s", action.Name, payload.Message)
+		}
+
+		return nil
+
+	case <-timer.C:
+		return errors.Errorf("trigger '%s' was not received within %s", action.Name, trigger.GetTimeout())
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	scenarioutils "github.com/carv-ics-forth/frisbee/controllers/scenario/utils"
+)
+
+func TestTriggerDispatcher_HandleWebhook(t *testing.T) {
+	key := []byte("secret")
+	id := scenarioutils.TriggerID("default", "gated", "approve")
+
+	post := func(dispatcher *scenarioutils.TriggerDispatcher, path string, body string, signature string) int {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		req.Header.Set(v1alpha1.TriggerSignatureHeader, signature)
+
+		rec := httptest.NewRecorder()
+		dispatcher.HandleWebhook().ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	body := `{"action":"approve","message":"ci passed"}`
+
+	tests := []struct {
+		name      string
+		path      string
+		body      string
+		signature string
+		wantCode  int
+		released  bool
+	}{
+		{
+			name:      "valid",
+			path:      v1alpha1.TriggerPath + id,
+			body:      body,
+			signature: scenarioutils.SignTrigger(key, []byte(body)),
+			wantCode:  http.StatusAccepted,
+			released:  true,
+		},
+		{
+			name:      "wrong key",
+			path:      v1alpha1.TriggerPath + id,
+			body:      body,
+			signature: scenarioutils.SignTrigger([]byte("other"), []byte(body)),
+			wantCode:  http.StatusUnauthorized,
+		},
+		{
+			name:      "not pending",
+			path:      v1alpha1.TriggerPath + scenarioutils.TriggerID("default", "gated", "other"),
+			body:      body,
+			signature: scenarioutils.SignTrigger(key, []byte(body)),
+			wantCode:  http.StatusNotFound,
+		},
+		{
+			name:      "replayed against another action",
+			path:      v1alpha1.TriggerPath + id,
+			body:      `{"action":"other"}`,
+			signature: scenarioutils.SignTrigger(key, []byte(`{"action":"other"}`)),
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:     "malformed path",
+			path:     v1alpha1.TriggerPath + "default/gated",
+			body:     body,
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dispatcher := scenarioutils.NewTriggerDispatcher()
+
+			release, disarm := dispatcher.Arm(id, key)
+			defer disarm()
+
+			if code := post(dispatcher, tt.path, tt.body, tt.signature); code != tt.wantCode {
+				t.Fatalf("code = %d, want %d", code, tt.wantCode)
+			}
+
+			select {
+			case payload := <-release:
+				if !tt.released {
+					t.Fatalf("unexpected release %v", payload)
+				}
+
+				if payload.Message != "ci passed" {
+					t.Errorf("message = %q", payload.Message)
+				}
+			default:
+				if tt.released {
+					t.Fatal("trigger was not released")
+				}
+			}
+		})
+	}
+}
+
+func TestTriggerDispatcher_ReleaseOnce(t *testing.T) {
+	key := []byte("secret")
+	id := scenarioutils.TriggerID("default", "gated", "approve")
+	body := []byte(`{"action":"approve"}`)
+
+	dispatcher := scenarioutils.NewTriggerDispatcher()
+
+	_, disarm := dispatcher.Arm(id, key)
+	defer disarm()
+
+	if err := dispatcher.Release(id, body, scenarioutils.SignTrigger(key, body)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dispatcher.Release(id, body, scenarioutils.SignTrigger(key, body)); err == nil {
+		t.Fatal("expected the second callback to be rejected")
+	}
+}
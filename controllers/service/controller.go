@@ -18,13 +18,16 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"time"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/carv-ics-forth/frisbee/controllers/common"
 	"github.com/carv-ics-forth/frisbee/controllers/common/watchers"
+	"github.com/carv-ics-forth/frisbee/pkg/clock"
 	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	"github.com/carv-ics-forth/frisbee/pkg/tracing"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -38,10 +41,14 @@ import (
 // +kubebuilder:rbac:groups=frisbee.dev,resources=services/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=frisbee.dev,resources=services/finalizers,verbs=update
 
+// +kubebuilder:rbac:groups=frisbee.dev,resources=scenarios,verbs=get;list;watch
+
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=pods/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=pods/exec,verbs=get;list;watch;create;update;patch;delete
 
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services/status,verbs=get;list;watch
 
@@ -60,6 +67,7 @@ import (
 type Controller struct {
 	ctrl.Manager
 	logr.Logger
+	clock.Clock
 
 	view *lifecycle.Classifier
 }
@@ -67,6 +75,9 @@ type Controller struct {
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracing.StartReconcile(ctx, "Service", req.NamespacedName)
+	defer span.End()
+
 	/*
 		1: Load CR by name and extract the Desired State
 		------------------------------------------------------------------
@@ -94,6 +105,16 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		)
 	}()
 
+	/*
+		1b: Replace the pod, if a restart is requested (e.g, by a registry outage).
+		------------------------------------------------------------------
+		The restart must precede the view, since the deleted pod would otherwise fail the service.
+	*/
+	if podUID, requested := service.GetAnnotations()[v1alpha1.AnnotationRestart]; requested &&
+		service.Status.Phase.Is(v1alpha1.PhasePending, v1alpha1.PhaseRunning) {
+		return r.restart(ctx, &service, types.UID(podUID))
+	}
+
 	/*
 		2: Load CR's children and classify their current state (view)
 		------------------------------------------------------------------
@@ -108,7 +129,9 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		The Update serves as "journaling" for the upcoming operations,
 		and as a roadblock for stall (queued) requests.
 	*/
-	if r.updateLifecycle(&service) {
+	placed := r.updatePlacement(ctx, &service)
+
+	if r.updateLifecycle(&service) || placed {
 		if err := common.UpdateStatus(ctx, r, &service); err != nil {
 			// due to the multiple updates, it is possible for this function to
 			// be in conflict. We fix this issue by re-queueing the request.
@@ -135,11 +158,22 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 
 		// Update the scheduling information
-		service.Status.LastScheduleTime = &metav1.Time{Time: time.Now()}
+		service.Status.LastScheduleTime = &metav1.Time{Time: r.Now()}
 
 		return lifecycle.Pending(ctx, r, &service, "Submit pod create request")
 
 	case v1alpha1.PhasePending, v1alpha1.PhaseRunning:
+		// Terminate the service once its lifetime expires.
+		if lifetime := service.Spec.Lifetime; lifetime != nil && service.Status.LastScheduleTime != nil {
+			deadline := service.Status.LastScheduleTime.Add(lifetime.Duration)
+
+			if !r.Now().Before(deadline) {
+				return lifecycle.Success(ctx, r, &service, fmt.Sprintf("lifetime of %s has expired", lifetime.Duration))
+			}
+
+			return common.RequeueAfter(r, req, r.Until(deadline))
+		}
+
 		// Nothing to do. We are not waiting for Pod to begin.
 		return common.Stop(r, req)
 
@@ -183,6 +217,17 @@ func (r *Controller) HasSucceed(ctx context.Context, cr *v1alpha1.Service) {
 	for _, job := range r.view.GetSuccessfulJobs() {
 		common.Delete(ctx, r, job)
 	}
+
+	// A service whose lifetime has expired is successful, even though its Pod may be still running.
+	if cr.Spec.Lifetime != nil {
+		for _, job := range r.view.GetPendingJobs() {
+			common.Delete(ctx, r, job)
+		}
+
+		for _, job := range r.view.GetRunningJobs() {
+			common.Delete(ctx, r, job)
+		}
+	}
 }
 
 func (r *Controller) HasFailed(ctx context.Context, cr *v1alpha1.Service) {
@@ -229,10 +274,11 @@ func (r *Controller) Finalize(obj client.Object) error {
 	deleted, etc.
 */
 
-func NewController(mgr ctrl.Manager, logger logr.Logger) error {
+func NewController(mgr ctrl.Manager, logger logr.Logger, clk clock.Clock) error {
 	reconciler := &Controller{
 		Manager: mgr,
 		Logger:  logger.WithName("service"),
+		Clock:   clk,
 		view:    &lifecycle.Classifier{},
 	}
 
@@ -57,24 +57,6 @@ func (r *Controller) runJob(ctx context.Context, service *v1alpha1.Service) erro
 func setDefaultValues(service *v1alpha1.Service) {
 	// Set the restart policy
 	service.Spec.RestartPolicy = corev1.RestartPolicyNever
-
-	// Set the pre/post execution hooks
-	/*
-		for i := 0; i < len(service.Spec.Containers); i++ {
-			// Use this for the telemetry sidecar to be able to enter the cgroup of the main container
-
-				cr.Spec.Containers[i].Lifecycle = &corev1.Lifecycle{
-					PostStart: &corev1.Handler{
-						Exec: &corev1.ExecAction{
-							Command: []string{
-								// "/bin/sh", "-c", "|", "cut -d ' ' -f 4 /proc/self/stats > /dev/shm/app",
-							},
-						},
-					},
-					PreStop: nil,
-				}
-		}
-	*/
 }
 
 func decoratePod(ctx context.Context, controller *Controller, service *v1alpha1.Service) error {
@@ -97,13 +79,50 @@ func decoratePod(ctx context.Context, controller *Controller, service *v1alpha1.
 		}
 	}
 
+	// point the containers of the template to the tracing backend, before the telemetry sidecars are added.
+	if err := serviceutils.AddTracing(ctx, controller.GetClient(), service); err != nil {
+		return errors.Wrapf(err, "failed to add tracing")
+	}
+
 	if err := serviceutils.AddTelemetrySidecar(ctx, controller.GetClient(), service); err != nil {
 		return errors.Wrapf(err, "failed to add telemetry")
 	}
 
+	// set the pre/post execution hooks, after the telemetry sidecars are added.
+	if err := serviceutils.AddLifecycleHooks(service); err != nil {
+		return errors.Wrapf(err, "failed to add lifecycle hooks")
+	}
+
 	if err := serviceutils.AddIngress(ctx, controller, service); err != nil {
 		return errors.Wrapf(err, "failed to add ingress")
 	}
 
+	if err := serviceutils.AddMeshInjection(service); err != nil {
+		return errors.Wrapf(err, "failed to add mesh injection")
+	}
+
+	if err := serviceutils.AddScenarioDNS(ctx, controller.GetClient(), service); err != nil {
+		return errors.Wrapf(err, "failed to add scenario dns")
+	}
+
+	// harden the pod, after the sidecars are added.
+	if err := serviceutils.ApplyPodSecurity(service); err != nil {
+		return errors.Wrapf(err, "pod security violation")
+	}
+
+	if err := serviceutils.AddImagePullSecrets(ctx, controller.GetClient(), service); err != nil {
+		return errors.Wrapf(err, "failed to add image pull secrets")
+	}
+
+	// override the tags before the rewrites, so that the overridden images are redirected as well.
+	if err := serviceutils.OverrideImages(ctx, controller.GetClient(), service); err != nil {
+		return errors.Wrapf(err, "failed to override images")
+	}
+
+	// rewrite the images last, so that images of the injected sidecars are redirected as well.
+	if err := serviceutils.RewriteImages(service); err != nil {
+		return errors.Wrapf(err, "failed to rewrite images")
+	}
+
 	return nil
 }
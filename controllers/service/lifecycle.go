@@ -25,6 +25,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// Reasons for the lifecycle of the service, that are not given by the Pod.
+const (
+	// ReasonEmptyCreationTime indicates that the Pod has no creation timestamp.
+	ReasonEmptyCreationTime = "EmptyCreationTime"
+
+	// ReasonPodDeletion indicates that the Pod is being deleted before completion.
+	ReasonPodDeletion = "PodDeletion"
+
+	// ReasonContainerError indicates that the Pod has failed without a reason, usually due to invalid
+	// container parameters.
+	ReasonContainerError = "ContainerError"
+)
+
 // updateLifecycle returns the update lifecycle of the cluster.
 func (r *Controller) updateLifecycle(service *v1alpha1.Service) bool {
 	// Skip any CR which are already completed, or uninitialized.
@@ -45,7 +58,7 @@ func convertPodLifecycle(obj client.Object) v1alpha1.Lifecycle {
 	if pod.CreationTimestamp.IsZero() {
 		return v1alpha1.Lifecycle{
 			Phase:   v1alpha1.PhaseFailed,
-			Reason:  "EmptyCreationTime",
+			Reason:  ReasonEmptyCreationTime,
 			Message: fmt.Sprintf("Something is wrong with Pod '%s'.", pod.GetLabels()),
 		}
 	}
@@ -54,7 +67,7 @@ func convertPodLifecycle(obj client.Object) v1alpha1.Lifecycle {
 	if !pod.GetDeletionTimestamp().IsZero() && !(pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed) {
 		return v1alpha1.Lifecycle{
 			Phase:   v1alpha1.PhaseFailed,
-			Reason:  "PodDeletion",
+			Reason:  ReasonPodDeletion,
 			Message: fmt.Sprintf("Pod '%s' is probably being deleted", pod.GetLabels()),
 		}
 	}
@@ -144,7 +157,7 @@ func convertPodLifecycle(obj client.Object) v1alpha1.Lifecycle {
 		// A usual source for empty reason is invalid container parameters
 		reason := pod.Status.Reason
 		if reason == "" {
-			reason = "ContainerError"
+			reason = ReasonContainerError
 		}
 
 		message := pod.Status.Message
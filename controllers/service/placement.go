@@ -0,0 +1,59 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// updatePlacement records the node and the zone of the service's Pod, once the Pod is scheduled.
+// It returns true if the status has been changed.
+func (r *Controller) updatePlacement(ctx context.Context, service *v1alpha1.Service) bool {
+	if service.Status.NodeName != "" {
+		return false
+	}
+
+	jobs := r.view.GetPendingJobs()
+	jobs = append(jobs, r.view.GetRunningJobs()...)
+	jobs = append(jobs, r.view.GetSuccessfulJobs()...)
+	jobs = append(jobs, r.view.GetFailedJobs()...)
+
+	for _, job := range jobs {
+		pod, ok := job.(*corev1.Pod)
+		if !ok || pod.Spec.NodeName == "" {
+			continue
+		}
+
+		service.Status.NodeName = pod.Spec.NodeName
+
+		var node corev1.Node
+
+		if err := r.GetClient().Get(ctx, client.ObjectKey{Name: pod.Spec.NodeName}, &node); err != nil {
+			r.Logger.Info("Cannot get node. Zone is unknown.", "node", pod.Spec.NodeName, "err", err)
+		} else {
+			service.Status.Zone = node.GetLabels()[corev1.LabelTopologyZone]
+		}
+
+		return true
+	}
+
+	return false
+}
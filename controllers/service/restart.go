@@ -0,0 +1,88 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// restart replaces the pod of the service, in three steps that span multiple reconciliation cycles:
+// the pod is deleted, the replacement is created once the pod is gone, and the request is cleared once the
+// replacement exists. The phase of the service is kept as is, until the view of the replacement takes over.
+func (r *Controller) restart(ctx context.Context, service *v1alpha1.Service, podUID types.UID) (ctrl.Result, error) {
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(service)}
+
+	var pod corev1.Pod
+
+	switch err := r.GetClient().Get(ctx, req.NamespacedName, &pod); {
+	case err == nil && pod.GetUID() == podUID:
+		// the pod to be replaced is still here.
+		if pod.GetDeletionTimestamp().IsZero() {
+			r.Logger.Info("Restart", "obj", req.NamespacedName, "pod", podUID)
+
+			if err := r.GetClient().Delete(ctx, &pod); client.IgnoreNotFound(err) != nil {
+				return lifecycle.Failed(ctx, r, service, errors.Wrapf(err, "cannot delete pod for restart"))
+			}
+		}
+
+		return common.RequeueAfter(r, req, time.Second)
+
+	case err == nil:
+		// the replacement is here. Clear the request, and let the view track the replacement.
+		patch := client.MergeFrom(service.DeepCopy())
+
+		annotations := service.GetAnnotations()
+		delete(annotations, v1alpha1.AnnotationRestart)
+		service.SetAnnotations(annotations)
+
+		if err := r.GetClient().Patch(ctx, service, patch); err != nil {
+			return common.RequeueWithError(r, req, errors.Wrapf(err, "cannot clear restart request"))
+		}
+
+		return common.Stop(r, req)
+
+	case k8errors.IsNotFound(err):
+		// run the replacement on the same node, and pull the images again.
+		if service.Status.NodeName != "" {
+			service.Spec.NodeName = service.Status.NodeName
+		}
+
+		for i := range service.Spec.Containers {
+			service.Spec.Containers[i].ImagePullPolicy = corev1.PullAlways
+		}
+
+		if err := r.runJob(ctx, service); err != nil {
+			return lifecycle.Failed(ctx, r, service, errors.Wrapf(err, "cannot create pod for restart"))
+		}
+
+		return common.RequeueAfter(r, req, time.Second)
+
+	default:
+		return common.RequeueWithError(r, req, errors.Wrapf(err, "cannot get pod for restart"))
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/json"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// RenderCacheSize is the maximum number of rendered templates in the cache. When the cache is full,
+// caching a new rendering evicts the least recently used one.
+var RenderCacheSize = 1024
+
+// rendering is an entry of the render cache.
+type rendering struct {
+	hash     string
+	template client.ObjectKey
+	specs    []v1alpha1.ServiceSpec
+}
+
+var (
+	renderLocker sync.Mutex
+	renderings   = map[string]*list.Element{}
+
+	// renderVersions keeps the resource version of the templates with cached renderings.
+	renderVersions = map[client.ObjectKey]string{}
+
+	// renderRecency orders the renderings from the most to the least recently used.
+	renderRecency = list.New()
+
+	renderRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "frisbee_render_cache_requests_total",
+		Help: "Number of lookups in the cache of rendered templates, by result.",
+	}, []string{"result"})
+)
+
+func init() {
+	// the metrics are exposed by the metrics endpoint of the controller manager.
+	metrics.Registry.MustRegister(renderRequests)
+}
+
+// renderHash identifies the rendering of a template with the given inputs. The template inputs must already
+// include the scope information (scenario, namespace, vars). The external inputs must not be resolved yet, so that
+// the hash is computed over the references instead of the secret values.
+func renderHash(key client.ObjectKey, inputs *v1alpha1.TemplateInputs, fromTemplate v1alpha1.GenerateObjectFromTemplate) (string, error) {
+	encoded, err := json.Marshal(struct {
+		Template     string                              `json:"template"`
+		Inputs       *v1alpha1.TemplateInputs            `json:"inputs"`
+		FromTemplate v1alpha1.GenerateObjectFromTemplate `json:"fromTemplate"`
+	}{
+		Template:     key.String(),
+		Inputs:       inputs,
+		FromTemplate: fromTemplate,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot encode inputs")
+	}
+
+	sum := sha256.Sum256(encoded)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// getRendering returns a copy of the cached rendering. If the template has changed since the rendering,
+// all the renderings of the template are evicted. Templates without resource version are never cached.
+func getRendering(template client.ObjectKey, version string, hash string) ([]v1alpha1.ServiceSpec, bool) {
+	if version == "" {
+		return nil, false
+	}
+
+	renderLocker.Lock()
+	defer renderLocker.Unlock()
+
+	if cached, exists := renderVersions[template]; exists && cached != version {
+		evictRenderings(template)
+	}
+
+	elem, exists := renderings[hash]
+	if !exists {
+		renderRequests.WithLabelValues("miss").Inc()
+
+		return nil, false
+	}
+
+	renderRequests.WithLabelValues("hit").Inc()
+
+	renderRecency.MoveToFront(elem)
+
+	return copySpecs(elem.Value.(*rendering).specs), true
+}
+
+// putRendering caches a copy of the rendering.
+func putRendering(template client.ObjectKey, version string, hash string, specs []v1alpha1.ServiceSpec) {
+	if version == "" {
+		return
+	}
+
+	renderLocker.Lock()
+	defer renderLocker.Unlock()
+
+	if cached, exists := renderVersions[template]; exists && cached != version {
+		evictRenderings(template)
+	}
+
+	if _, exists := renderings[hash]; exists {
+		return
+	}
+
+	for len(renderings) >= RenderCacheSize && renderRecency.Len() > 0 {
+		evictRendering(renderRecency.Back())
+	}
+
+	renderVersions[template] = version
+
+	renderings[hash] = renderRecency.PushFront(&rendering{
+		hash:     hash,
+		template: template,
+		specs:    copySpecs(specs),
+	})
+}
+
+// evictRenderings removes all the renderings of the template. It must be called with the lock held.
+func evictRenderings(template client.ObjectKey) {
+	for elem := renderRecency.Back(); elem != nil; {
+		prev := elem.Prev()
+
+		if elem.Value.(*rendering).template == template {
+			evictRendering(elem)
+		}
+
+		elem = prev
+	}
+
+	delete(renderVersions, template)
+}
+
+// evictRendering removes the rendering from the cache. It must be called with the lock held.
+func evictRendering(elem *list.Element) {
+	entry := elem.Value.(*rendering)
+
+	renderRecency.Remove(elem)
+	delete(renderings, entry.hash)
+}
+
+func copySpecs(specs []v1alpha1.ServiceSpec) []v1alpha1.ServiceSpec {
+	out := make([]v1alpha1.ServiceSpec, len(specs))
+
+	for i := range specs {
+		specs[i].DeepCopyInto(&out[i])
+	}
+
+	return out
+}
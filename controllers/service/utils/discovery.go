@@ -36,7 +36,7 @@ func AddIngress(ctx context.Context, controller common.Reconciler, service *v1al
 
 	var ingress netv1.Ingress
 
-	ingressClassName := configuration.Global.IngressClassName
+	ingressClassName := configuration.Global().IngressClassName
 
 	ingress.SetName(service.GetName())
 	v1alpha1.PropagateLabels(&ingress, service)
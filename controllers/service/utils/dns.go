@@ -0,0 +1,98 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AddScenarioDNS injects the host aliases and the dns configuration of the scenario into the service.
+func AddScenarioDNS(ctx context.Context, cli client.Client, service *v1alpha1.Service) error {
+	if !v1alpha1.HasScenarioLabel(service) {
+		return nil
+	}
+
+	var scenario v1alpha1.Scenario
+
+	key := v1alpha1.GetScenarioKey(service)
+
+	if err := cli.Get(ctx, key, &scenario); err != nil {
+		return errors.Wrapf(err, "cannot get scenario '%s'", key)
+	}
+
+	for _, alias := range scenario.Spec.HostAliases {
+		ip := alias.IP
+
+		if alias.Service != "" {
+			resolved, err := resolveServiceIP(ctx, cli, service.GetNamespace(), alias.Service)
+			if err != nil {
+				return errors.Wrapf(err, "cannot resolve alias '%v'", alias.Hostnames)
+			}
+
+			ip = resolved
+		}
+
+		service.Spec.HostAliases = append(service.Spec.HostAliases, corev1.HostAlias{
+			IP:        ip,
+			Hostnames: alias.Hostnames,
+		})
+	}
+
+	if dnsConfig := scenario.Spec.DNSConfig; dnsConfig != nil {
+		if service.Spec.DNSConfig == nil {
+			service.Spec.DNSConfig = &corev1.PodDNSConfig{}
+		}
+
+		service.Spec.DNSConfig.Nameservers = append(service.Spec.DNSConfig.Nameservers, dnsConfig.Nameservers...)
+		service.Spec.DNSConfig.Searches = append(service.Spec.DNSConfig.Searches, dnsConfig.Searches...)
+		service.Spec.DNSConfig.Options = append(service.Spec.DNSConfig.Options, dnsConfig.Options...)
+	}
+
+	return nil
+}
+
+// resolveServiceIP returns the virtual IP of the service, or the IP of its pod if the service is headless.
+func resolveServiceIP(ctx context.Context, cli client.Client, namespace string, name string) (string, error) {
+	key := client.ObjectKey{Namespace: namespace, Name: name}
+
+	var k8sService corev1.Service
+
+	if err := cli.Get(ctx, key, &k8sService); err != nil {
+		return "", errors.Wrapf(err, "cannot get service '%s'", key)
+	}
+
+	if k8sService.Spec.ClusterIP != "" && k8sService.Spec.ClusterIP != corev1.ClusterIPNone {
+		return k8sService.Spec.ClusterIP, nil
+	}
+
+	var pod corev1.Pod
+
+	if err := cli.Get(ctx, key, &pod); err != nil {
+		return "", errors.Wrapf(err, "cannot get pod '%s'", key)
+	}
+
+	if pod.Status.PodIP == "" {
+		return "", errors.Errorf("pod '%s' has no IP", key)
+	}
+
+	return pod.Status.PodIP, nil
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	templateutils "github.com/carv-ics-forth/frisbee/controllers/template/utils"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/json"
@@ -43,27 +44,16 @@ func GetServiceSpecList(ctx context.Context, cli client.Client, parent metav1.Ob
 	/*
 		Get Service Templates
 	*/
-	var template v1alpha1.Template
-
 	key := client.ObjectKey{
-		Namespace: parent.GetNamespace(),
+		Namespace: v1alpha1.GetScenarioNamespace(parent),
 		Name:      fromTemplate.TemplateRef,
 	}
 
-	if err := cli.Get(ctx, key, &template); err != nil {
-		return []v1alpha1.ServiceSpec{}, errors.Wrapf(err, "cannot find template '%s'", key.String())
-	}
-
-	/*
-		Convert Service Template to JSON and expand inputs
-	*/
-	body, err := json.Marshal(template.Spec.Service)
+	template, err := templateutils.GetTemplate(ctx, cli, key)
 	if err != nil {
-		return nil, errors.Errorf("cannot marshal service of '%s'", fromTemplate.TemplateRef)
+		return []v1alpha1.ServiceSpec{}, errors.Wrapf(err, "cannot get template")
 	}
 
-	specs := make([]v1alpha1.ServiceSpec, 0, fromTemplate.MaxInstances)
-
 	// add extra fields in the template
 	if template.Spec.Inputs == nil {
 		var inputs v1alpha1.TemplateInputs
@@ -72,6 +62,39 @@ func GetServiceSpecList(ctx context.Context, cli client.Client, parent metav1.Ob
 
 	template.Spec.Inputs.Scenario = v1alpha1.GetScenarioLabel(parent)
 	template.Spec.Inputs.Namespace = parent.GetNamespace()
+	template.Spec.Inputs.RunID = v1alpha1.GetRunID(parent)
+
+	if err := templateutils.SetScenarioVars(ctx, cli, parent, &template.Spec); err != nil {
+		return nil, errors.Wrapf(err, "cannot set vars of '%s'", fromTemplate.TemplateRef)
+	}
+
+	/*
+		Reuse the specs of a previous rendering, unless the template has changed since. The lookup precedes the
+		resolution of the external inputs, so that cached renderings do not reach the external stores.
+	*/
+	hash, err := renderHash(key, template.Spec.Inputs, fromTemplate)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot hash inputs of '%s'", fromTemplate.TemplateRef)
+	}
+
+	if specs, exists := getRendering(key, template.GetResourceVersion(), hash); exists {
+		return specs, nil
+	}
+
+	// fetch the values that are kept in external stores
+	if err := templateutils.ResolveExternalInputs(ctx, &template.Spec, &fromTemplate); err != nil {
+		return nil, errors.Wrapf(err, "cannot resolve external inputs of '%s'", fromTemplate.TemplateRef)
+	}
+
+	/*
+		Convert Service Template to JSON and expand inputs
+	*/
+	body, err := json.Marshal(template.Spec.Service)
+	if err != nil {
+		return nil, errors.Errorf("cannot marshal service of '%s'", fromTemplate.TemplateRef)
+	}
+
+	specs := make([]v1alpha1.ServiceSpec, 0, fromTemplate.MaxInstances)
 
 	/*
 		Generate Service Specs using the expanded inputs
@@ -90,5 +113,7 @@ func GetServiceSpecList(ctx context.Context, cli client.Client, parent metav1.Ob
 		return nil, errors.Wrapf(err, "cannot get specs")
 	}
 
+	putRendering(key, template.GetResourceVersion(), hash, specs)
+
 	return specs, nil
 }
@@ -0,0 +1,167 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	serviceutils "github.com/carv-ics-forth/frisbee/controllers/service/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetServiceSpecList_Cache(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	var template v1alpha1.Template
+
+	template.SetNamespace("default")
+	template.SetName("server")
+	template.Spec.Inputs = &v1alpha1.TemplateInputs{
+		Parameters: v1alpha1.Parameters{"image": v1alpha1.ParameterValue("redis:6")},
+	}
+	template.Spec.EmbedSpecs = &v1alpha1.EmbedSpecs{
+		Service: &v1alpha1.ServiceSpec{
+			PodSpec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "main", Image: "{{.inputs.parameters.image}}"}},
+			},
+		},
+	}
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&template).Build()
+
+	var scenario v1alpha1.Scenario
+
+	scenario.SetNamespace("default")
+	scenario.SetName("scenario")
+	scenario.SetLabels(map[string]string{v1alpha1.LabelScenario: "scenario"})
+
+	fromTemplate := v1alpha1.GenerateObjectFromTemplate{TemplateRef: "server", MaxInstances: 2}
+
+	image := func() string {
+		specs, err := serviceutils.GetServiceSpecList(context.Background(), cli, &scenario, fromTemplate)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if len(specs) != 2 {
+			t.Fatalf("expected 2 specs but got %d", len(specs))
+		}
+
+		// the cached rendering must not be affected by changes to the returned specs.
+		defer func() { specs[0].Containers[0].Image = "changed" }()
+
+		return specs[0].Containers[0].Image
+	}
+
+	if got := image(); got != "redis:6" {
+		t.Errorf("expected image 'redis:6' but got '%s'", got)
+	}
+
+	if got := image(); got != "redis:6" {
+		t.Errorf("expected cached image 'redis:6' but got '%s'", got)
+	}
+
+	// changing the template invalidates the cached renderings.
+	if err := cli.Get(context.Background(), client.ObjectKeyFromObject(&template), &template); err != nil {
+		t.Fatal(err)
+	}
+
+	template.Spec.Inputs.Parameters["image"] = v1alpha1.ParameterValue("redis:7")
+
+	if err := cli.Update(context.Background(), &template); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := image(); got != "redis:7" {
+		t.Errorf("expected image 'redis:7' after update but got '%s'", got)
+	}
+}
+
+// TestGetServiceSpecList_CacheExternalInputs checks that cached renderings do not fetch the external inputs.
+func TestGetServiceSpecList_CacheExternalInputs(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		_, _ = w.Write([]byte(`{"data": {"password": "secret"}}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "token")
+
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	var template v1alpha1.Template
+
+	template.SetNamespace("default")
+	template.SetName("database")
+	template.Spec.Inputs = &v1alpha1.TemplateInputs{
+		Parameters: v1alpha1.Parameters{"password": v1alpha1.ParameterValue("vault://kv/db#password")},
+	}
+	template.Spec.EmbedSpecs = &v1alpha1.EmbedSpecs{
+		Service: &v1alpha1.ServiceSpec{
+			PodSpec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:  "main",
+					Image: "postgres",
+					Env:   []corev1.EnvVar{{Name: "POSTGRES_PASSWORD", Value: "{{.inputs.parameters.password}}"}},
+				}},
+			},
+		},
+	}
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&template).Build()
+
+	var scenario v1alpha1.Scenario
+
+	scenario.SetNamespace("default")
+	scenario.SetName("scenario")
+	scenario.SetLabels(map[string]string{v1alpha1.LabelScenario: "scenario"})
+
+	fromTemplate := v1alpha1.GenerateObjectFromTemplate{TemplateRef: "database", MaxInstances: 1}
+
+	for i := 0; i < 3; i++ {
+		specs, err := serviceutils.GetServiceSpecList(context.Background(), cli, &scenario, fromTemplate)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if got := specs[0].Containers[0].Env[0].Value; got != "secret" {
+			t.Fatalf("expected password 'secret' but got '%s'", got)
+		}
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("expected 1 request to vault but got %d", got)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// mainPIDScript finds the process of the main container, i.e, the oldest process with the same mount namespace
+// as the hook, and publishes its PID to the handshake volume. The file is renamed atomically, so that the agents
+// never read a partial PID.
+var mainPIDScript = fmt.Sprintf(`self=$(readlink /proc/self/ns/mnt)
+for pid in $(ls /proc | grep -E '^[0-9]+$' | sort -n); do
+  if [ "$(readlink /proc/${pid}/ns/mnt 2>/dev/null)" = "${self}" ]; then
+    echo ${pid} > %[1]s.tmp && mv %[1]s.tmp %[1]s
+    break
+  fi
+done`, filepath.Join(v1alpha1.TelemetryHandshakePath, v1alpha1.TelemetryMainPIDFile))
+
+// addTelemetryHandshake implements the agent contract (see v1alpha1.TelemetryHandshakePath).
+func addTelemetryHandshake(service *v1alpha1.Service) {
+	hasMain := findContainer(service.Spec.Containers, v1alpha1.MainContainerName) != nil
+
+	// Expose the Pod information, and the resources of the main container.
+	podInfo := []corev1.DownwardAPIVolumeFile{
+		{Path: "labels", FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.labels"}},
+		{Path: "annotations", FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.annotations"}},
+	}
+
+	if hasMain {
+		podInfo = append(podInfo,
+			corev1.DownwardAPIVolumeFile{Path: "cpu_limit", ResourceFieldRef: &corev1.ResourceFieldSelector{
+				ContainerName: v1alpha1.MainContainerName,
+				Resource:      "limits.cpu",
+				Divisor:       resource.MustParse("1m"),
+			}},
+			corev1.DownwardAPIVolumeFile{Path: "mem_limit", ResourceFieldRef: &corev1.ResourceFieldSelector{
+				ContainerName: v1alpha1.MainContainerName,
+				Resource:      "limits.memory",
+			}},
+		)
+	}
+
+	// Mount the handshake volumes to all the containers.
+	service.Spec.Volumes = append(service.Spec.Volumes,
+		corev1.Volume{
+			Name: v1alpha1.TelemetryHandshakeVolume,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory},
+			},
+		},
+		corev1.Volume{
+			Name: v1alpha1.TelemetryHandshakeVolume + "-podinfo",
+			VolumeSource: corev1.VolumeSource{
+				DownwardAPI: &corev1.DownwardAPIVolumeSource{Items: podInfo},
+			},
+		},
+	)
+
+	for i := range service.Spec.Containers {
+		service.Spec.Containers[i].VolumeMounts = append(service.Spec.Containers[i].VolumeMounts,
+			corev1.VolumeMount{
+				Name:      v1alpha1.TelemetryHandshakeVolume,
+				MountPath: v1alpha1.TelemetryHandshakePath,
+			},
+			corev1.VolumeMount{
+				Name:      v1alpha1.TelemetryHandshakeVolume + "-podinfo",
+				MountPath: filepath.Join(v1alpha1.TelemetryHandshakePath, v1alpha1.TelemetryPodInfoDir),
+				ReadOnly:  true,
+			},
+		)
+	}
+
+	// The PID is meaningful to the agents only if the process namespace is shared.
+	if !hasMain || service.Spec.ShareProcessNamespace == nil || !*service.Spec.ShareProcessNamespace {
+		return
+	}
+
+	// Publish the PID of the main container. User-defined postStart hooks run after the handshake.
+	if service.Spec.Hooks == nil {
+		service.Spec.Hooks = map[string]v1alpha1.ContainerHooks{}
+	}
+
+	hooks := service.Spec.Hooks[v1alpha1.MainContainerName]
+
+	if len(hooks.PostStart) == 0 {
+		hooks.PostStart = []string{"/bin/sh", "-c", mainPIDScript}
+	} else {
+		hooks.PostStart = append([]string{"/bin/sh", "-c", mainPIDScript + "\n" + `exec "$@"`, "handshake"}, hooks.PostStart...)
+	}
+
+	service.Spec.Hooks[v1alpha1.MainContainerName] = hooks
+}
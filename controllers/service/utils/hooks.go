@@ -0,0 +1,69 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// AddLifecycleHooks translates the hooks of the service into lifecycle handlers of the respective containers.
+func AddLifecycleHooks(service *v1alpha1.Service) error {
+	for containerName, hooks := range service.Spec.Hooks {
+		container := findContainer(service.Spec.Containers, containerName)
+		if container == nil {
+			return errors.Errorf("hooks refer to non-existing container '%s'", containerName)
+		}
+
+		if container.Lifecycle == nil {
+			container.Lifecycle = &corev1.Lifecycle{}
+		}
+
+		if len(hooks.PostStart) > 0 {
+			if container.Lifecycle.PostStart != nil {
+				return errors.Errorf("container '%s' already defines a postStart handler", containerName)
+			}
+
+			container.Lifecycle.PostStart = &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{Command: hooks.PostStart},
+			}
+		}
+
+		if len(hooks.PreStop) > 0 {
+			if container.Lifecycle.PreStop != nil {
+				return errors.Errorf("container '%s' already defines a preStop handler", containerName)
+			}
+
+			container.Lifecycle.PreStop = &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{Command: hooks.PreStop},
+			}
+		}
+	}
+
+	return nil
+}
+
+func findContainer(containers []corev1.Container, name string) *corev1.Container {
+	for i := range containers {
+		if containers[i].Name == name {
+			return &containers[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/images"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ImageRewritesEnv is the environment variable with the image rewrite rules of the operator.
+// The rules redirect the images of the services to private registries, enabling offline clusters.
+// See images.ParseRewriteMap for the format.
+const ImageRewritesEnv = "FRISBEE_IMAGE_REWRITES"
+
+// ImagePullSecretsEnv is the environment variable with the comma-separated names of the imagePullSecrets that are
+// attached to every pod. The secrets must exist in the namespace of each scenario.
+const ImagePullSecretsEnv = "FRISBEE_IMAGE_PULL_SECRETS"
+
+// RewriteImages replaces the images of the service containers according to the image rewrite rules.
+func RewriteImages(service *v1alpha1.Service) error {
+	rules := os.Getenv(ImageRewritesEnv)
+	if rules == "" {
+		return nil
+	}
+
+	rewrites, err := images.ParseRewriteMap(rules)
+	if err != nil {
+		return errors.Wrapf(err, "invalid %s", ImageRewritesEnv)
+	}
+
+	for i := range service.Spec.InitContainers {
+		service.Spec.InitContainers[i].Image = rewrites.Rewrite(service.Spec.InitContainers[i].Image)
+	}
+
+	for i := range service.Spec.Containers {
+		service.Spec.Containers[i].Image = rewrites.Rewrite(service.Spec.Containers[i].Image)
+	}
+
+	return nil
+}
+
+// OverrideImages replaces the tags of the service images according to the image overrides of the parent scenario.
+func OverrideImages(ctx context.Context, cli client.Client, service *v1alpha1.Service) error {
+	if !v1alpha1.HasScenarioLabel(service) {
+		return nil
+	}
+
+	var scenario v1alpha1.Scenario
+
+	key := v1alpha1.GetScenarioKey(service)
+
+	if err := cli.Get(ctx, key, &scenario); err != nil {
+		return errors.Wrapf(err, "cannot get scenario '%s'", key)
+	}
+
+	overrides := images.Overrides(scenario.Spec.ImageOverrides)
+
+	for i := range service.Spec.InitContainers {
+		service.Spec.InitContainers[i].Image = overrides.Override(service.Spec.InitContainers[i].Image)
+	}
+
+	for i := range service.Spec.Containers {
+		service.Spec.Containers[i].Image = overrides.Override(service.Spec.Containers[i].Image)
+	}
+
+	return nil
+}
+
+// AddImagePullSecrets attaches the imagePullSecrets of the operator and of the parent scenario to the service.
+func AddImagePullSecrets(ctx context.Context, cli client.Client, service *v1alpha1.Service) error {
+	var secrets []corev1.LocalObjectReference
+
+	for _, name := range strings.Split(os.Getenv(ImagePullSecretsEnv), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			secrets = append(secrets, corev1.LocalObjectReference{Name: name})
+		}
+	}
+
+	if v1alpha1.HasScenarioLabel(service) {
+		var scenario v1alpha1.Scenario
+
+		key := v1alpha1.GetScenarioKey(service)
+
+		if err := cli.Get(ctx, key, &scenario); err != nil {
+			return errors.Wrapf(err, "cannot get scenario '%s'", key)
+		}
+
+		secrets = append(secrets, scenario.Spec.ImagePullSecrets...)
+	}
+
+	// avoid duplicates with the secrets defined in the template.
+	for _, secret := range secrets {
+		exists := false
+
+		for _, current := range service.Spec.ImagePullSecrets {
+			if current.Name == secret.Name {
+				exists = true
+
+				break
+			}
+		}
+
+		if !exists {
+			service.Spec.ImagePullSecrets = append(service.Spec.ImagePullSecrets, secret)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// IstioInjection is the label that enables the Istio sidecar injection.
+	IstioInjection = "sidecar.istio.io/inject"
+
+	// LinkerdInjection is the annotation that enables the Linkerd proxy injection.
+	LinkerdInjection = "linkerd.io/inject"
+)
+
+// AddMeshInjection marks the service for injection by the mesh control plane.
+func AddMeshInjection(service *v1alpha1.Service) error {
+	switch service.Spec.Decorators.Mesh {
+	case "":
+		return nil
+	case v1alpha1.MeshIstio:
+		metav1.SetMetaDataLabel(&service.ObjectMeta, IstioInjection, "true")
+	case v1alpha1.MeshLinkerd:
+		metav1.SetMetaDataAnnotation(&service.ObjectMeta, LinkerdInjection, "enabled")
+	default:
+		return errors.Errorf("unknown mesh '%s'", service.Spec.Decorators.Mesh)
+	}
+
+	return nil
+}
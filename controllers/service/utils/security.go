@@ -0,0 +1,145 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"os"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+// PodSecurityEnv is the environment variable with the default pod security profile of the operator.
+// If it is empty, the securityContexts are left as defined in the templates.
+const PodSecurityEnv = "FRISBEE_POD_SECURITY"
+
+// ApplyPodSecurity hardens the securityContexts of the service according to its pod security profile.
+// Under the Restricted profile, missing settings are filled with values that comply with the restricted Pod
+// Security Standard, whereas settings that violate the standard are reported as errors.
+func ApplyPodSecurity(service *v1alpha1.Service) error {
+	profile := service.Spec.Decorators.PodSecurity
+	if profile == "" {
+		profile = v1alpha1.PodSecurityProfile(os.Getenv(PodSecurityEnv))
+	}
+
+	switch profile {
+	case "", v1alpha1.PodSecurityPrivileged:
+		return nil
+	case v1alpha1.PodSecurityRestricted:
+	default:
+		return errors.Errorf("unknown pod security profile '%s'", profile)
+	}
+
+	// pod-level settings
+	if service.Spec.SecurityContext == nil {
+		service.Spec.SecurityContext = &corev1.PodSecurityContext{}
+	}
+
+	podSecurity := service.Spec.SecurityContext
+
+	if podSecurity.RunAsNonRoot == nil {
+		podSecurity.RunAsNonRoot = pointer.Bool(true)
+	}
+
+	if podSecurity.SeccompProfile == nil {
+		podSecurity.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	}
+
+	if !*podSecurity.RunAsNonRoot || (podSecurity.RunAsUser != nil && *podSecurity.RunAsUser == 0) {
+		return errors.Errorf("pod runs as root. Use decorators.podSecurity: %s to opt out", v1alpha1.PodSecurityPrivileged)
+	}
+
+	if podSecurity.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+		return errors.Errorf("pod uses unconfined seccomp. Use decorators.podSecurity: %s to opt out", v1alpha1.PodSecurityPrivileged)
+	}
+
+	if service.Spec.HostNetwork || service.Spec.HostPID || service.Spec.HostIPC {
+		return errors.Errorf("pod uses host namespaces. Use decorators.podSecurity: %s to opt out", v1alpha1.PodSecurityPrivileged)
+	}
+
+	for _, volume := range service.Spec.Volumes {
+		if volume.HostPath != nil {
+			return errors.Errorf("volume '%s' is a hostPath. Use decorators.podSecurity: %s to opt out",
+				volume.Name, v1alpha1.PodSecurityPrivileged)
+		}
+	}
+
+	// container-level settings
+	for i := range service.Spec.InitContainers {
+		if err := restrictContainer(&service.Spec.InitContainers[i]); err != nil {
+			return err
+		}
+	}
+
+	for i := range service.Spec.Containers {
+		if err := restrictContainer(&service.Spec.Containers[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func restrictContainer(container *corev1.Container) error {
+	if container.SecurityContext == nil {
+		container.SecurityContext = &corev1.SecurityContext{}
+	}
+
+	security := container.SecurityContext
+
+	if security.Privileged != nil && *security.Privileged {
+		return errors.Errorf("container '%s' is privileged. Use decorators.podSecurity: %s to opt out",
+			container.Name, v1alpha1.PodSecurityPrivileged)
+	}
+
+	if security.RunAsNonRoot != nil && !*security.RunAsNonRoot || security.RunAsUser != nil && *security.RunAsUser == 0 {
+		return errors.Errorf("container '%s' runs as root. Use decorators.podSecurity: %s to opt out",
+			container.Name, v1alpha1.PodSecurityPrivileged)
+	}
+
+	if security.AllowPrivilegeEscalation == nil {
+		security.AllowPrivilegeEscalation = pointer.Bool(false)
+	}
+
+	if *security.AllowPrivilegeEscalation {
+		return errors.Errorf("container '%s' allows privilege escalation. Use decorators.podSecurity: %s to opt out",
+			container.Name, v1alpha1.PodSecurityPrivileged)
+	}
+
+	if security.Capabilities == nil {
+		security.Capabilities = &corev1.Capabilities{}
+	}
+
+	// the restricted standard allows only NET_BIND_SERVICE to be added.
+	for _, capability := range security.Capabilities.Add {
+		if capability != "NET_BIND_SERVICE" {
+			return errors.Errorf("container '%s' adds capability '%s'. Use decorators.podSecurity: %s to opt out",
+				container.Name, capability, v1alpha1.PodSecurityPrivileged)
+		}
+	}
+
+	security.Capabilities.Drop = []corev1.Capability{"ALL"}
+
+	if security.SeccompProfile != nil && security.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+		return errors.Errorf("container '%s' uses unconfined seccomp. Use decorators.podSecurity: %s to opt out",
+			container.Name, v1alpha1.PodSecurityPrivileged)
+	}
+
+	return nil
+}
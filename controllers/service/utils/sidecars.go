@@ -29,12 +29,6 @@ func AddTelemetrySidecar(ctx context.Context, cli client.Client, service *v1alph
 		return nil
 	}
 
-	if len(service.Spec.Decorators.Telemetry) > 0 {
-		//  The sidecar makes use of the shareProcessNamespace option to access the host cgroup metrics.
-		share := true
-		service.Spec.ShareProcessNamespace = &share
-	}
-
 	// import telemetry agents
 	// import dashboards for monitoring agents to the service
 	for _, monRef := range service.Spec.Decorators.Telemetry {
@@ -51,8 +45,30 @@ func AddTelemetrySidecar(ctx context.Context, cli client.Client, service *v1alph
 		}
 
 		service.Spec.Containers = append(service.Spec.Containers, monSpec.Containers[0])
+
+		if hooks, exists := monSpec.Hooks[monSpec.Containers[0].Name]; exists {
+			if service.Spec.Hooks == nil {
+				service.Spec.Hooks = map[string]v1alpha1.ContainerHooks{}
+			}
+
+			service.Spec.Hooks[monSpec.Containers[0].Name] = hooks
+		}
+
 		service.Spec.Volumes = append(service.Spec.Volumes, monSpec.Volumes...)
-		service.Spec.Volumes = append(service.Spec.Volumes, monSpec.Volumes...)
+
+		// a privileged agent makes the whole pod privileged.
+		if monSpec.Decorators.PodSecurity == v1alpha1.PodSecurityPrivileged {
+			service.Spec.Decorators.PodSecurity = v1alpha1.PodSecurityPrivileged
+		}
+
+		// an agent that enters the namespaces of the main container shares the process namespace of the pod.
+		if share := monSpec.ShareProcessNamespace; share != nil && *share {
+			service.Spec.ShareProcessNamespace = share
+		}
+	}
+
+	if len(service.Spec.Decorators.Telemetry) > 0 {
+		addTelemetryHandshake(service)
 	}
 
 	return nil
@@ -0,0 +1,100 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	serviceutils "github.com/carv-ics-forth/frisbee/controllers/service/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAddTelemetrySidecar_ProcessNamespace(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	agent := func(name string, share bool) *v1alpha1.Template {
+		var template v1alpha1.Template
+
+		template.SetNamespace("default")
+		template.SetName(name)
+		template.Spec.EmbedSpecs = &v1alpha1.EmbedSpecs{
+			Service: &v1alpha1.ServiceSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: name, Image: "agent"}},
+				},
+			},
+		}
+
+		if share {
+			template.Spec.Service.ShareProcessNamespace = &share
+		}
+
+		return &template
+	}
+
+	var scenario v1alpha1.Scenario
+
+	scenario.SetNamespace("default")
+	scenario.SetName("scenario")
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(&scenario, agent("metrics", false), agent("cgroups", true)).
+		Build()
+
+	tests := []struct {
+		name      string
+		agents    []string
+		wantShare bool
+	}{
+		{name: "not shared", agents: []string{"metrics"}},
+		{name: "opt-in", agents: []string{"metrics", "cgroups"}, wantShare: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var service v1alpha1.Service
+
+			service.SetNamespace("default")
+			service.SetName("server")
+			service.SetLabels(map[string]string{v1alpha1.LabelScenario: "scenario"})
+			service.Spec.Containers = []corev1.Container{{Name: v1alpha1.MainContainerName, Image: "redis"}}
+			service.Spec.Decorators.Telemetry = tt.agents
+
+			if err := serviceutils.AddTelemetrySidecar(context.Background(), cli, &service); err != nil {
+				t.Fatalf("AddTelemetrySidecar() error = %v", err)
+			}
+
+			share := service.Spec.ShareProcessNamespace != nil && *service.Spec.ShareProcessNamespace
+			if share != tt.wantShare {
+				t.Errorf("ShareProcessNamespace = %v, want %v", share, tt.wantShare)
+			}
+
+			// the PID of the main container is published only if it is visible to the agents.
+			_, hasHandshake := service.Spec.Hooks[v1alpha1.MainContainerName]
+			if hasHandshake != tt.wantShare {
+				t.Errorf("main postStart handshake = %v, want %v", hasHandshake, tt.wantShare)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AddTracing points the containers of the service to the OTLP endpoint of the tracing backend of the scenario,
+// using the standard OpenTelemetry environment variables. Variables that are set by the template are retained.
+func AddTracing(ctx context.Context, cli client.Client, service *v1alpha1.Service) error {
+	if !v1alpha1.HasScenarioLabel(service) || v1alpha1.IsSYSComponent(service) {
+		return nil
+	}
+
+	var scenario v1alpha1.Scenario
+
+	key := v1alpha1.GetScenarioKey(service)
+
+	if err := cli.Get(ctx, key, &scenario); err != nil {
+		return errors.Wrapf(err, "cannot get scenario '%s'", key)
+	}
+
+	if tracing := scenario.Spec.Tracing; tracing == nil || tracing.DisableInjection {
+		return nil
+	}
+
+	attributes := []string{"frisbee.scenario=" + scenario.GetName()}
+
+	if runID := v1alpha1.GetRunID(service); runID != "" {
+		attributes = append(attributes, "frisbee.run="+runID)
+	}
+
+	env := []corev1.EnvVar{
+		{
+			Name: "OTEL_EXPORTER_OTLP_ENDPOINT",
+			Value: fmt.Sprintf("http://%s",
+				common.InternalEndpoint(common.DefaultTracingName, key.Namespace, common.DefaultTracingOTLPPort)),
+		},
+		{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "grpc"},
+		{Name: "OTEL_SERVICE_NAME", Value: service.GetName()},
+		{Name: "OTEL_RESOURCE_ATTRIBUTES", Value: strings.Join(attributes, ",")},
+	}
+
+	for i := range service.Spec.Containers {
+		container := &service.Spec.Containers[i]
+
+		for _, envVar := range env {
+			if !hasEnv(container, envVar.Name) {
+				container.Env = append(container.Env, envVar)
+			}
+		}
+	}
+
+	return nil
+}
+
+func hasEnv(container *corev1.Container, name string) bool {
+	for _, envVar := range container.Env {
+		if envVar.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
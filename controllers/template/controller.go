@@ -23,6 +23,8 @@ import (
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/pkg/clock"
+	"github.com/carv-ics-forth/frisbee/pkg/tracing"
 	"github.com/go-logr/logr"
 	k8errors "k8s.io/apimachinery/pkg/api/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -37,11 +39,15 @@ import (
 type Controller struct {
 	ctrl.Manager
 	logr.Logger
+	clock.Clock
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracing.StartReconcile(ctx, "Template", req.NamespacedName)
+	defer span.End()
+
 	/*
 		1: Load CR by name.
 		------------------------------------------------------------------
@@ -140,7 +146,7 @@ func (r *Controller) Finalize(obj client.Object) error {
 	deleted, etc.
 */
 
-func NewController(mgr ctrl.Manager, logger logr.Logger) error {
+func NewController(mgr ctrl.Manager, logger logr.Logger, clk clock.Clock) error {
 	var template v1alpha1.Template
 
 	return ctrl.NewControllerManagedBy(mgr).
@@ -149,5 +155,6 @@ func NewController(mgr ctrl.Manager, logger logr.Logger) error {
 		Complete(&Controller{
 			Manager: mgr,
 			Logger:  logger.WithName("template"),
+			Clock:   clk,
 		})
 }
@@ -0,0 +1,174 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"strings"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MaxExtendsDepth bounds the length of an inheritance chain.
+const MaxExtendsDepth = 10
+
+// GetTemplate returns the template, after merging into it the specs of the templates it extends.
+// The resource version of the returned template reflects the versions of all the templates in the chain,
+// so that it changes whenever any of them changes.
+func GetTemplate(ctx context.Context, cli client.Client, key client.ObjectKey) (v1alpha1.Template, error) {
+	var template v1alpha1.Template
+
+	if err := cli.Get(ctx, key, &template); err != nil {
+		return template, errors.Wrapf(err, "cannot find template '%s'", key.String())
+	}
+
+	versions := []string{template.GetResourceVersion()}
+
+	visited := map[string]struct{}{template.GetName(): {}}
+
+	for parentRef := template.Spec.Extends; parentRef != ""; {
+		if _, exists := visited[parentRef]; exists {
+			return template, errors.Errorf("template '%s' has circular inheritance via '%s'", key.String(), parentRef)
+		}
+
+		if len(visited) > MaxExtendsDepth {
+			return template, errors.Errorf("template '%s' exceeds the maximum inheritance depth '%d'",
+				key.String(), MaxExtendsDepth)
+		}
+
+		visited[parentRef] = struct{}{}
+
+		var parent v1alpha1.Template
+
+		parentKey := client.ObjectKey{Namespace: key.Namespace, Name: parentRef}
+
+		if err := cli.Get(ctx, parentKey, &parent); err != nil {
+			return template, errors.Wrapf(err, "cannot find parent template '%s'", parentKey.String())
+		}
+
+		merged, err := Overlay(parent.Spec, template.Spec)
+		if err != nil {
+			return template, errors.Wrapf(err, "cannot extend template '%s'", parentKey.String())
+		}
+
+		template.Spec = merged
+		parentRef = parent.Spec.Extends
+
+		versions = append(versions, parent.GetResourceVersion())
+	}
+
+	template.Spec.Extends = ""
+	template.SetResourceVersion(strings.Join(versions, "/"))
+
+	return template, nil
+}
+
+// Overlay merges the overlay spec into the base spec. Parameters of the overlay replace the parameters of the base,
+// and the embedded specs are merged using strategic merge (e.g, containers are merged by name).
+func Overlay(base, overlay v1alpha1.TemplateSpec) (v1alpha1.TemplateSpec, error) {
+	var merged v1alpha1.TemplateSpec
+
+	base.DeepCopyInto(&merged)
+
+	if merged.EmbedSpecs == nil {
+		merged.EmbedSpecs = &v1alpha1.EmbedSpecs{}
+	}
+
+	// Inputs
+	if overlay.Inputs != nil {
+		if merged.Inputs == nil {
+			merged.Inputs = &v1alpha1.TemplateInputs{}
+		}
+
+		if merged.Inputs.Parameters == nil && len(overlay.Inputs.Parameters) > 0 {
+			merged.Inputs.Parameters = make(v1alpha1.Parameters, len(overlay.Inputs.Parameters))
+		}
+
+		for key, value := range overlay.Inputs.Parameters {
+			merged.Inputs.Parameters[key] = value.DeepCopy()
+		}
+	}
+
+	if overlay.EmbedSpecs == nil {
+		return merged, nil
+	}
+
+	// Service
+	if overlay.Service != nil {
+		var service v1alpha1.ServiceSpec
+
+		if err := strategicMerge(merged.Service, overlay.Service, &service); err != nil {
+			return merged, errors.Wrapf(err, "service")
+		}
+
+		merged.Service = &service
+	}
+
+	// Chaos
+	if overlay.Chaos != nil {
+		var chaos v1alpha1.ChaosSpec
+
+		if err := strategicMerge(merged.Chaos, overlay.Chaos, &chaos); err != nil {
+			return merged, errors.Wrapf(err, "chaos")
+		}
+
+		merged.Chaos = &chaos
+	}
+
+	// Scenario
+	if overlay.Scenario != nil {
+		var scenario v1alpha1.ScenarioSpec
+
+		if err := strategicMerge(merged.Scenario, overlay.Scenario, &scenario); err != nil {
+			return merged, errors.Wrapf(err, "scenario")
+		}
+
+		merged.Scenario = &scenario
+	}
+
+	return merged, nil
+}
+
+// strategicMerge applies the overlay as a strategic merge patch on the base, and decodes the result into out.
+// The type of out provides the patch strategies (e.g, patchMergeKey).
+func strategicMerge(base, overlay, out interface{}) error {
+	baseJSON, err := json.Marshal(base)
+	if err != nil {
+		return errors.Wrapf(err, "cannot marshal base")
+	}
+
+	// a missing base is equivalent to an empty one.
+	if string(baseJSON) == "null" {
+		baseJSON = []byte("{}")
+	}
+
+	overlayJSON, err := json.Marshal(overlay)
+	if err != nil {
+		return errors.Wrapf(err, "cannot marshal overlay")
+	}
+
+	mergedJSON, err := strategicpatch.StrategicMergePatch(baseJSON, overlayJSON, out)
+	if err != nil {
+		return errors.Wrapf(err, "merge error")
+	}
+
+	return json.Unmarshal(mergedJSON, out)
+}
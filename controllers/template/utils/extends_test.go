@@ -0,0 +1,115 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	templateutils "github.com/carv-ics-forth/frisbee/controllers/template/utils"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestOverlay(t *testing.T) {
+	base := v1alpha1.TemplateSpec{
+		Inputs: &v1alpha1.TemplateInputs{
+			Parameters: v1alpha1.Parameters{
+				"role":  v1alpha1.ParameterValue("server"),
+				"image": v1alpha1.ParameterValue("redis:6"),
+			},
+		},
+		EmbedSpecs: &v1alpha1.EmbedSpecs{
+			Service: &v1alpha1.ServiceSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "main", Image: "redis:6", Command: []string{"redis-server"}},
+						{Name: "sidecar", Image: "busybox"},
+					},
+				},
+			},
+		},
+	}
+
+	overlay := v1alpha1.TemplateSpec{
+		Extends: "base",
+		Inputs: &v1alpha1.TemplateInputs{
+			Parameters: v1alpha1.Parameters{
+				"role": v1alpha1.ParameterValue("client"),
+			},
+		},
+		EmbedSpecs: &v1alpha1.EmbedSpecs{
+			Service: &v1alpha1.ServiceSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "main", Command: []string{"redis-benchmark"}},
+					},
+				},
+			},
+		},
+	}
+
+	merged, err := templateutils.Overlay(base, overlay)
+	if err != nil {
+		t.Fatalf("Overlay() error = %v", err)
+	}
+
+	if got := string(merged.Inputs.Parameters["role"].Raw); got != `"client"` {
+		t.Errorf("role = %s, want overridden value", got)
+	}
+
+	if got := string(merged.Inputs.Parameters["image"].Raw); got != `"redis:6"` {
+		t.Errorf("image = %s, want inherited value", got)
+	}
+
+	containers := merged.Service.Containers
+	if len(containers) != 2 {
+		t.Fatalf("containers = %d, want 2", len(containers))
+	}
+
+	for _, container := range containers {
+		if container.Name != "main" {
+			continue
+		}
+
+		if container.Image != "redis:6" {
+			t.Errorf("main.image = %s, want inherited value", container.Image)
+		}
+
+		if len(container.Command) != 1 || container.Command[0] != "redis-benchmark" {
+			t.Errorf("main.command = %v, want overridden value", container.Command)
+		}
+	}
+}
+
+func TestValidateExtendsOnly(t *testing.T) {
+	template := v1alpha1.Template{
+		Spec: v1alpha1.TemplateSpec{
+			Extends: "base",
+			Inputs: &v1alpha1.TemplateInputs{
+				Parameters: v1alpha1.Parameters{
+					"role": v1alpha1.ParameterValue("client"),
+				},
+			},
+		},
+	}
+
+	template.SetName("overlay")
+
+	if _, err := template.ValidateCreate(); err != nil {
+		t.Errorf("ValidateCreate() error = %v", err)
+	}
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/awsv4"
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+/*
+	External inputs are parameter values that are not written in the scenario, but are fetched from an external
+	store when the template is rendered. The credentials for the stores are configured on the operator through
+	the standard environment variables of each store.
+
+	Syntax:
+		vault://<path>#<field>	-- HashiCorp Vault (KV v1 or v2). Uses VAULT_ADDR, VAULT_TOKEN, VAULT_NAMESPACE.
+		ssm://<name>			-- AWS SSM Parameter Store. Uses AWS_REGION, AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY,
+								   AWS_SESSION_TOKEN, and optionally AWS_ENDPOINT_URL_SSM.
+*/
+
+const (
+	VaultInputPrefix = "vault://"
+	SSMInputPrefix   = "ssm://"
+)
+
+// ExternalInputTimeout bounds the time spent for fetching a single external value.
+const ExternalInputTimeout = 10 * time.Second
+
+// IsExternalInput returns true if the value must be fetched from an external store.
+func IsExternalInput(value string) bool {
+	return strings.HasPrefix(value, VaultInputPrefix) || strings.HasPrefix(value, SSMInputPrefix)
+}
+
+// ResolveExternalInputs replaces the external references found in the default parameters of the template and in
+// the user inputs with the values fetched from the respective store. The user inputs are copied before replacement,
+// so that the resolved values never find their way back into the parent object.
+func ResolveExternalInputs(ctx context.Context, tSpec *v1alpha1.TemplateSpec, fromTemplate *v1alpha1.GenerateObjectFromTemplate) error {
+	if tSpec.Inputs != nil && tSpec.Inputs.Parameters != nil {
+		resolved := make(v1alpha1.Parameters, len(tSpec.Inputs.Parameters))
+
+		for key, value := range tSpec.Inputs.Parameters {
+			newValue, err := resolveExternalValue(ctx, value)
+			if err != nil {
+				return errors.Wrapf(err, "cannot resolve parameter '%s'", key)
+			}
+
+			resolved[key] = newValue
+		}
+
+		tSpec.Inputs.Parameters = resolved
+	}
+
+	if len(fromTemplate.Inputs) > 0 {
+		inputs := make([]v1alpha1.UserInputs, len(fromTemplate.Inputs))
+
+		for i, userInputs := range fromTemplate.Inputs {
+			inputs[i] = make(v1alpha1.UserInputs, len(userInputs))
+
+			for key, value := range userInputs {
+				newValue, err := resolveExternalValue(ctx, value)
+				if err != nil {
+					return errors.Wrapf(err, "cannot resolve input '%s'", key)
+				}
+
+				inputs[i][key] = newValue
+			}
+		}
+
+		fromTemplate.Inputs = inputs
+	}
+
+	return nil
+}
+
+func resolveExternalValue(ctx context.Context, value *apiextensionsv1.JSON) (*apiextensionsv1.JSON, error) {
+	if value == nil {
+		return value, nil
+	}
+
+	var ref string
+
+	// only string values can carry external references.
+	if err := json.Unmarshal(value.Raw, &ref); err != nil || !IsExternalInput(ref) {
+		return value, nil //nolint:nilerr
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ExternalInputTimeout)
+	defer cancel()
+
+	var (
+		fetched string
+		err     error
+	)
+
+	switch {
+	case strings.HasPrefix(ref, VaultInputPrefix):
+		fetched, err = fetchFromVault(ctx, strings.TrimPrefix(ref, VaultInputPrefix))
+	case strings.HasPrefix(ref, SSMInputPrefix):
+		fetched, err = fetchFromSSM(ctx, strings.TrimPrefix(ref, SSMInputPrefix))
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot fetch '%s'", ref)
+	}
+
+	raw, err := json.Marshal(fetched)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot marshal value of '%s'", ref)
+	}
+
+	return &apiextensionsv1.JSON{Raw: raw}, nil
+}
+
+/*
+	HashiCorp Vault
+*/
+
+func fetchFromVault(ctx context.Context, ref string) (string, error) {
+	path, field, found := strings.Cut(ref, "#")
+	if !found || path == "" || field == "" {
+		return "", errors.Errorf("expected format is '%s<path>#<field>'", VaultInputPrefix)
+	}
+
+	addr := os.Getenv("VAULT_ADDR")
+	token := os.Getenv("VAULT_TOKEN")
+
+	if addr == "" || token == "" {
+		return "", errors.Errorf("vault is not configured. Set VAULT_ADDR and VAULT_TOKEN on the operator")
+	}
+
+	url := strings.TrimSuffix(addr, "/") + "/v1/" + strings.TrimPrefix(path, "/")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot create request")
+	}
+
+	req.Header.Set("X-Vault-Token", token)
+
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	body, err := doRequest(req)
+	if err != nil {
+		return "", err
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", errors.Wrapf(err, "cannot decode vault response")
+	}
+
+	data := secret.Data
+
+	// KV v2 engines nest the actual secret under data.data.
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, isMeta := data["metadata"]; isMeta {
+			data = nested
+		}
+	}
+
+	value, exists := data[field]
+	if !exists {
+		return "", errors.Errorf("field '%s' does not exist in '%s'", field, path)
+	}
+
+	return stringify(value), nil
+}
+
+/*
+	AWS SSM Parameter Store
+*/
+
+func fetchFromSSM(ctx context.Context, name string) (string, error) {
+	if name == "" {
+		return "", errors.Errorf("expected format is '%s<name>'", SSMInputPrefix)
+	}
+
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+
+	accessKey := os.Getenv("AWS_ACCESS_KEY_ID")
+	secretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+
+	if region == "" || accessKey == "" || secretKey == "" {
+		return "", errors.Errorf("ssm is not configured. Set AWS_REGION, AWS_ACCESS_KEY_ID, and AWS_SECRET_ACCESS_KEY on the operator")
+	}
+
+	endpoint := os.Getenv("AWS_ENDPOINT_URL_SSM")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://ssm.%s.amazonaws.com", region)
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"Name":           name,
+		"WithDecryption": true,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot marshal request")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/", bytes.NewReader(payload))
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot create request")
+	}
+
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "AmazonSSM.GetParameter")
+
+	awsv4.Sign(req, payload, awsv4.Credentials{
+		AccessKey:    accessKey,
+		SecretKey:    secretKey,
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	}, region, "ssm", time.Now())
+
+	body, err := doRequest(req)
+	if err != nil {
+		return "", err
+	}
+
+	var response struct {
+		Parameter struct {
+			Value string `json:"Value"`
+		} `json:"Parameter"`
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", errors.Wrapf(err, "cannot decode ssm response")
+	}
+
+	return response.Parameter.Value, nil
+}
+
+func doRequest(req *http.Request) ([]byte, error) {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "request to '%s' failed", req.URL.Host)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read response")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status '%s' from '%s'", resp.Status, req.URL.Host)
+	}
+
+	return body, nil
+}
+
+func stringify(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+
+	return fmt.Sprint(value)
+}
@@ -16,6 +16,18 @@ limitations under the License.
 
 package utils_test
 
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/template/utils"
+)
+
 /*
 func TestGenerateSpecFromScheme(t *testing.T) {
 	type args struct {
@@ -88,3 +100,225 @@ EOF
 
 
 */
+
+// resolve resolves a single parameter of a template, and returns its value.
+func resolve(t *testing.T, value interface{}) (interface{}, error) {
+	t.Helper()
+
+	tSpec := v1alpha1.TemplateSpec{
+		Inputs: &v1alpha1.TemplateInputs{
+			Parameters: v1alpha1.Parameters{"key": v1alpha1.ParameterValue(value)},
+		},
+	}
+
+	if err := utils.ResolveExternalInputs(context.Background(), &tSpec, &v1alpha1.GenerateObjectFromTemplate{}); err != nil {
+		return nil, err
+	}
+
+	var resolved interface{}
+
+	if err := json.Unmarshal(tSpec.Inputs.Parameters["key"].Raw, &resolved); err != nil {
+		t.Fatalf("cannot decode resolved value: %v", err)
+	}
+
+	return resolved, nil
+}
+
+func TestResolveExternalInputs_Vault(t *testing.T) {
+	secrets := map[string]string{
+		// KV v1 engines return the secret as the data.
+		"/v1/kv/db": `{"data": {"password": "v1-secret", "port": 5432}}`,
+		// KV v2 engines nest the secret under data.data, along with the metadata.
+		"/v1/secret/data/db": `{"data": {"data": {"password": "v2-secret"}, "metadata": {"version": 3}}}`,
+		// a KV v1 secret may have a field named data. Without metadata, it is not unwrapped.
+		"/v1/kv/nested": `{"data": {"data": {"password": "inner"}, "password": "outer"}}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" || r.Header.Get("X-Vault-Namespace") != "team" {
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		secret, exists := secrets[r.URL.Path]
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(secret))
+	}))
+	defer server.Close()
+
+	t.Setenv("VAULT_ADDR", server.URL+"/")
+	t.Setenv("VAULT_TOKEN", "token")
+	t.Setenv("VAULT_NAMESPACE", "team")
+
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    interface{}
+		wantErr string
+	}{
+		{name: "kv v1", value: "vault://kv/db#password", want: "v1-secret"},
+		{name: "kv v2", value: "vault://secret/data/db#password", want: "v2-secret"},
+		{name: "not unwrapped", value: "vault://kv/nested#password", want: "outer"},
+		{name: "non-string field", value: "vault://kv/db#port", want: "5432"},
+		{name: "plain string", value: "password", want: "password"},
+		{name: "non-string value", value: 42, want: float64(42)},
+		{name: "missing field", value: "vault://kv/db#user", wantErr: "field 'user' does not exist"},
+		{name: "missing path", value: "vault://kv/missing#password", wantErr: "404"},
+		{name: "missing separator", value: "vault://kv/db", wantErr: "expected format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolve(t, tt.value)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ResolveExternalInputs() error = %v, want %s", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ResolveExternalInputs() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("ResolveExternalInputs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("wrong token", func(t *testing.T) {
+		t.Setenv("VAULT_TOKEN", "other")
+
+		if _, err := resolve(t, "vault://kv/db#password"); err == nil || !strings.Contains(err.Error(), "403") {
+			t.Fatalf("ResolveExternalInputs() error = %v, want 403", err)
+		}
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		t.Setenv("VAULT_ADDR", "")
+
+		if _, err := resolve(t, "vault://kv/db#password"); err == nil || !strings.Contains(err.Error(), "not configured") {
+			t.Fatalf("ResolveExternalInputs() error = %v, want not configured", err)
+		}
+	})
+}
+
+func TestResolveExternalInputs_SSM(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") ||
+			!strings.Contains(auth, "/eu-west-1/ssm/aws4_request") ||
+			!strings.Contains(auth, "x-amz-security-token") ||
+			r.Header.Get("X-Amz-Security-Token") != "session" ||
+			r.Header.Get("X-Amz-Target") != "AmazonSSM.GetParameter" ||
+			r.Header.Get("Content-Type") != "application/x-amz-json-1.1" {
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		var request struct {
+			Name           string
+			WithDecryption bool
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || !request.WithDecryption {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		if request.Name != "/bench/password" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type": "ParameterNotFound"}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"Parameter": {"Name": "/bench/password", "Value": "ssm-secret"}}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY")
+	t.Setenv("AWS_SESSION_TOKEN", "session")
+	t.Setenv("AWS_ENDPOINT_URL_SSM", server.URL)
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr string
+	}{
+		{name: "parameter", value: "ssm:///bench/password", want: "ssm-secret"},
+		{name: "missing parameter", value: "ssm:///bench/user", wantErr: "400"},
+		{name: "missing name", value: "ssm://", wantErr: "expected format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolve(t, tt.value)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ResolveExternalInputs() error = %v, want %s", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ResolveExternalInputs() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("ResolveExternalInputs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("not configured", func(t *testing.T) {
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+
+		if _, err := resolve(t, "ssm:///bench/password"); err == nil || !strings.Contains(err.Error(), "not configured") {
+			t.Fatalf("ResolveExternalInputs() error = %v, want not configured", err)
+		}
+	})
+}
+
+// TestResolveExternalInputs_UserInputs checks that the resolved values are not written back to the parent object.
+func TestResolveExternalInputs_UserInputs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {"password": "secret"}}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "token")
+
+	parentInputs := []v1alpha1.UserInputs{{"password": v1alpha1.ParameterValue("vault://kv/db#password")}}
+	fromTemplate := v1alpha1.GenerateObjectFromTemplate{Inputs: parentInputs}
+
+	if err := utils.ResolveExternalInputs(context.Background(), &v1alpha1.TemplateSpec{}, &fromTemplate); err != nil {
+		t.Fatalf("ResolveExternalInputs() error = %v", err)
+	}
+
+	if got, want := string(fromTemplate.Inputs[0]["password"].Raw), `"secret"`; got != want {
+		t.Errorf("resolved input = %s, want %s", got, want)
+	}
+
+	if got, want := string(parentInputs[0]["password"].Raw), `"vault://kv/db#password"`; got != want {
+		t.Errorf("parent input = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SetScenarioVars overrides the default vars of the template with the vars of the scenario
+// from which the template is called from.
+func SetScenarioVars(ctx context.Context, cli client.Client, parent metav1.Object, tSpec *v1alpha1.TemplateSpec) error {
+	scenario, isScenario := parent.(*v1alpha1.Scenario)
+	if !isScenario {
+		if !v1alpha1.HasScenarioLabel(parent) {
+			return nil
+		}
+
+		scenario = &v1alpha1.Scenario{}
+
+		key := v1alpha1.GetScenarioKey(parent)
+
+		if err := cli.Get(ctx, key, scenario); err != nil {
+			return errors.Wrapf(err, "cannot get scenario '%s'", key)
+		}
+	}
+
+	if len(scenario.Spec.Vars) == 0 {
+		return nil
+	}
+
+	vars := make(map[string]string, len(tSpec.Inputs.Vars)+len(scenario.Spec.Vars))
+
+	for name, value := range tSpec.Inputs.Vars {
+		vars[name] = value
+	}
+
+	for name, value := range scenario.Spec.Vars {
+		vars[name] = value
+	}
+
+	tSpec.Inputs.Vars = vars
+
+	return nil
+}
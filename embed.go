@@ -32,6 +32,15 @@ import (
 //go:embed hack
 var Hack embed.FS
 
+// CRDs are the CRDs of the platform. They are used for validating the manifests, if the CRDs of the cluster
+// cannot be read.
+//
+//go:embed charts/platform/crds
+var CRDs embed.FS
+
+// CRDsDir is the directory of the CRDs within the embedded filesystem.
+const CRDsDir = "charts/platform/crds"
+
 // UpdateLocalFiles duplicates the structure of embedded fs into the installation dir.
 func UpdateLocalFiles(embeddedFS embed.FS, installationDir string) error {
 	root := "."
@@ -4,7 +4,7 @@ const puppeteer = require('puppeteer');
 
 // URL to load should be passed as first parameter
 const url = process.argv[2];
-// Username and password (with colon separator) should be second parameter
+// Username and password (with colon separator), or an API key, should be second parameter
 const auth_string = process.argv[3];
 // Output file name should be third parameter
 const outfile = process.argv[4];
@@ -19,8 +19,10 @@ const width_px = 1632;
 // Instead, set e.g. double the size here (1632px), and call page.pdf() with format: 'Letter' and
 const scaleFactor = 2; // 400%
 
-// Generate authorization header for basic auth
-const auth_header = 'Basic ' + new Buffer.from(auth_string).toString('base64');
+// Generate authorization header for basic auth, or bearer token for API keys
+const auth_header = auth_string.includes(':') ?
+    'Basic ' + new Buffer.from(auth_string).toString('base64') :
+    'Bearer ' + auth_string;
 
 (async () => {
     try {
@@ -0,0 +1,113 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+/*
+	The artifacts of a test (testdata, Prometheus data) can be too large to be copied locally before they are
+	archived. Instead, they are streamed as tarballs straight from the Pods into an S3-compatible object storage
+	(AWS S3, MinIO). The size of the tarball is unknown in advance, and therefore it is uploaded in parts.
+*/
+
+// S3Scheme is the scheme of the targets in an S3-compatible object storage.
+const S3Scheme = "s3://"
+
+// DefaultRegion is used when neither the flags nor the environment set a region. MinIO ignores it.
+const DefaultRegion = "us-east-1"
+
+// Target is a location within a bucket.
+type Target struct {
+	Bucket string
+	Prefix string
+}
+
+// IsTarget returns true if the destination points to an object storage, rather than to a local path.
+func IsTarget(destination string) bool {
+	return strings.HasPrefix(destination, S3Scheme)
+}
+
+// ParseTarget parses destinations of the form s3://bucket/prefix. The prefix is optional.
+func ParseTarget(destination string) (Target, error) {
+	if !IsTarget(destination) {
+		return Target{}, errors.Errorf("expected format is '%sbucket/prefix'", S3Scheme)
+	}
+
+	u, err := url.Parse(destination)
+	if err != nil {
+		return Target{}, errors.Wrapf(err, "invalid target '%s'", destination)
+	}
+
+	if u.Host == "" {
+		return Target{}, errors.Errorf("missing bucket in '%s'", destination)
+	}
+
+	return Target{Bucket: u.Host, Prefix: strings.Trim(u.Path, "/")}, nil
+}
+
+// Key returns the key of the object with the given name, under the prefix of the target.
+func (t Target) Key(name string) string {
+	return path.Join(t.Prefix, name)
+}
+
+// String returns the target as s3://bucket/prefix.
+func (t Target) String() string {
+	return S3Scheme + path.Join(t.Bucket, t.Prefix)
+}
+
+// Config describes the access to the object storage.
+type Config struct {
+	// Endpoint is the URL of the object storage (e.g, http://minio:9000). If empty, the AWS S3 endpoint of the
+	// region is used.
+	Endpoint string
+
+	Region string
+
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+}
+
+// ConfigFromEnv reads the configuration from the standard AWS variables. Fields that are already set are kept.
+func ConfigFromEnv(config Config) Config {
+	setDefault := func(field *string, vars ...string) {
+		for _, v := range vars {
+			if *field == "" {
+				*field = os.Getenv(v)
+			}
+		}
+	}
+
+	setDefault(&config.Endpoint, "AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL")
+	setDefault(&config.Region, "AWS_REGION", "AWS_DEFAULT_REGION")
+	setDefault(&config.AccessKey, "AWS_ACCESS_KEY_ID")
+	setDefault(&config.SecretKey, "AWS_SECRET_ACCESS_KEY")
+	setDefault(&config.SessionToken, "AWS_SESSION_TOKEN")
+
+	if config.Region == "" {
+		config.Region = DefaultRegion
+	}
+
+	return config
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/pkg/awsv4"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MinPartSize is the minimum size of every part, except the last one, that S3 accepts.
+	MinPartSize = 5 << 20
+
+	// DefaultPartSize bounds the memory used for buffering the stream.
+	DefaultPartSize = 16 << 20
+
+	// maxParts is the maximum number of parts of an upload.
+	maxParts = 10000
+)
+
+// S3 uploads streams to an S3-compatible object storage. Objects are addressed in the path-style
+// (endpoint/bucket/key), which is supported by both AWS S3 and MinIO.
+type S3 struct {
+	config Config
+
+	// PartSize is the size of the parts in which a stream is uploaded.
+	PartSize int
+
+	Client *http.Client
+}
+
+// NewS3 returns an uploader for the object storage.
+func NewS3(config Config) (*S3, error) {
+	if config.AccessKey == "" || config.SecretKey == "" {
+		return nil, errors.New("missing credentials. Set the access key and the secret key, or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+
+	if config.Region == "" {
+		config.Region = DefaultRegion
+	}
+
+	if config.Endpoint == "" {
+		config.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", config.Region)
+	}
+
+	if _, err := url.Parse(config.Endpoint); err != nil {
+		return nil, errors.Wrapf(err, "invalid endpoint '%s'", config.Endpoint)
+	}
+
+	return &S3{
+		config:   config,
+		PartSize: DefaultPartSize,
+		Client:   http.DefaultClient,
+	}, nil
+}
+
+// Upload streams the data into the object, and returns the number of uploaded bytes. Streams that fit in a
+// single part are uploaded as a whole. Larger streams are uploaded in parts, and the upload is aborted on failure,
+// so that no parts are left behind.
+func (s *S3) Upload(ctx context.Context, bucket, key string, data io.Reader) (int64, error) {
+	if s.PartSize < MinPartSize {
+		return 0, errors.Errorf("part size '%d' is lower than the minimum '%d'", s.PartSize, MinPartSize)
+	}
+
+	buf := make([]byte, s.PartSize)
+
+	n, err := io.ReadFull(data, buf)
+
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return int64(n), s.putObject(ctx, bucket, key, buf[:n])
+	case err != nil:
+		return 0, errors.Wrapf(err, "cannot read stream")
+	}
+
+	uploadID, err := s.createMultipartUpload(ctx, bucket, key)
+	if err != nil {
+		return 0, err
+	}
+
+	total, err := s.uploadParts(ctx, bucket, key, uploadID, buf, data)
+	if err != nil {
+		// use a fresh context, since the failure may be due to the cancellation of the original.
+		if abortErr := s.abortMultipartUpload(context.Background(), bucket, key, uploadID); abortErr != nil {
+			return total, errors.Wrapf(err, "upload failed, and it cannot be aborted (%s)", abortErr)
+		}
+
+		return total, err
+	}
+
+	return total, nil
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []completedPart `xml:"Part"`
+}
+
+// uploadParts uploads the first part, which is already read into the buffer, and the rest of the stream.
+func (s *S3) uploadParts(ctx context.Context, bucket, key, uploadID string, buf []byte, data io.Reader) (int64, error) {
+	var (
+		parts []completedPart
+		total int64
+		n     = len(buf)
+	)
+
+	for partNumber := 1; n > 0; partNumber++ {
+		if partNumber > maxParts {
+			return total, errors.Errorf("stream exceeds %d parts of %d bytes", maxParts, s.PartSize)
+		}
+
+		etag, err := s.uploadPart(ctx, bucket, key, uploadID, partNumber, buf[:n])
+		if err != nil {
+			return total, err
+		}
+
+		parts = append(parts, completedPart{PartNumber: partNumber, ETag: etag})
+		total += int64(n)
+
+		// the last part may be smaller than the others. The end of the stream yields an empty part.
+		n, err = io.ReadFull(data, buf)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return total, errors.Wrapf(err, "cannot read stream")
+		}
+	}
+
+	return total, s.completeMultipartUpload(ctx, bucket, key, uploadID, parts)
+}
+
+func (s *S3) putObject(ctx context.Context, bucket, key string, payload []byte) error {
+	_, _, err := s.do(ctx, http.MethodPut, bucket, key, nil, payload)
+
+	return errors.Wrapf(err, "cannot put object '%s'", key)
+}
+
+func (s *S3) createMultipartUpload(ctx context.Context, bucket, key string) (string, error) {
+	body, _, err := s.do(ctx, http.MethodPost, bucket, key, url.Values{"uploads": {""}}, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot create multipart upload for '%s'", key)
+	}
+
+	var response struct {
+		UploadID string `xml:"UploadId"`
+	}
+
+	if err := xml.Unmarshal(body, &response); err != nil || response.UploadID == "" {
+		return "", errors.Errorf("invalid response to multipart upload for '%s'", key)
+	}
+
+	return response.UploadID, nil
+}
+
+func (s *S3) uploadPart(ctx context.Context, bucket, key, uploadID string, partNumber int, payload []byte) (string, error) {
+	query := url.Values{"partNumber": {strconv.Itoa(partNumber)}, "uploadId": {uploadID}}
+
+	_, header, err := s.do(ctx, http.MethodPut, bucket, key, query, payload)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot upload part '%d' of '%s'", partNumber, key)
+	}
+
+	return header.Get("ETag"), nil
+}
+
+func (s *S3) completeMultipartUpload(ctx context.Context, bucket, key, uploadID string, parts []completedPart) error {
+	payload, err := xml.Marshal(completeMultipartUpload{Parts: parts})
+	if err != nil {
+		return errors.Wrapf(err, "cannot marshal parts")
+	}
+
+	body, _, err := s.do(ctx, http.MethodPost, bucket, key, url.Values{"uploadId": {uploadID}}, payload)
+	if err != nil {
+		return errors.Wrapf(err, "cannot complete multipart upload for '%s'", key)
+	}
+
+	// the completion may fail after the response has started. In this case, the body is an error.
+	if bytes.Contains(body, []byte("<Error>")) {
+		return errors.Errorf("cannot complete multipart upload for '%s': %s", key, body)
+	}
+
+	return nil
+}
+
+func (s *S3) abortMultipartUpload(ctx context.Context, bucket, key, uploadID string) error {
+	_, _, err := s.do(ctx, http.MethodDelete, bucket, key, url.Values{"uploadId": {uploadID}}, nil)
+
+	return err
+}
+
+// do sends a signed request for the object, and returns the body and the headers of the response.
+func (s *S3) do(ctx context.Context, method, bucket, key string, query url.Values, payload []byte) ([]byte, http.Header, error) {
+	endpoint, _ := url.Parse(s.config.Endpoint)
+
+	objectPath := strings.TrimSuffix(endpoint.Path, "/") + "/" + bucket + "/" + key
+
+	endpoint.Path = objectPath
+	endpoint.RawPath = awsv4.EscapePath(objectPath)
+	endpoint.RawQuery = awsv4.CanonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), bytes.NewReader(payload))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "cannot create request")
+	}
+
+	s.sign(req, payload)
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "request to '%s' failed", req.URL.Host)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "cannot read response")
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, nil, errors.Errorf("unexpected status '%s' from '%s': %s", resp.Status, req.URL.Host, body)
+	}
+
+	return body, resp.Header, nil
+}
+
+// sign signs the request with AWS Signature Version 4. S3 also expects the hash of the payload in a header.
+func (s *S3) sign(req *http.Request, payload []byte) {
+	req.Header.Set("X-Amz-Content-Sha256", awsv4.PayloadHash(payload))
+
+	awsv4.Sign(req, payload, awsv4.Credentials{
+		AccessKey:    s.config.AccessKey,
+		SecretKey:    s.config.SecretKey,
+		SessionToken: s.config.SessionToken,
+	}, s.config.Region, "s3", time.Now())
+}
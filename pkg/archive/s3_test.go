@@ -0,0 +1,176 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeStorage keeps the objects and the parts of the uploads in memory.
+type fakeStorage struct {
+	lock sync.Mutex
+
+	objects map[string][]byte
+	parts   map[string][][]byte
+	aborted int
+
+	failPart int
+}
+
+func (f *fakeStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+		w.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	query := r.URL.Query()
+	key := r.URL.Path
+
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", key)
+
+	case r.Method == http.MethodPut && query.Has("partNumber"):
+		if query.Get("partNumber") == fmt.Sprint(f.failPart) {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		f.parts[key] = append(f.parts[key], body)
+		w.Header().Set("ETag", fmt.Sprintf("\"%d\"", len(f.parts[key])))
+
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		var complete completeMultipartUpload
+
+		if err := xml.Unmarshal(body, &complete); err != nil || len(complete.Parts) != len(f.parts[key]) {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		f.objects[key] = bytes.Join(f.parts[key], nil)
+
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		delete(f.parts, key)
+		f.aborted++
+
+	case r.Method == http.MethodPut:
+		f.objects[key] = body
+
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func TestUpload(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      int
+		failPart  int
+		wantParts int
+		wantErr   bool
+	}{
+		{name: "single", size: 1024},
+		{name: "exact", size: MinPartSize, wantParts: 1},
+		{name: "multipart", size: 2*MinPartSize + 1024, wantParts: 3},
+		{name: "failed", size: 2*MinPartSize + 1024, failPart: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &fakeStorage{objects: map[string][]byte{}, parts: map[string][][]byte{}, failPart: tt.failPart}
+
+			server := httptest.NewServer(storage)
+			defer server.Close()
+
+			s, err := NewS3(Config{Endpoint: server.URL, AccessKey: "access", SecretKey: "secret"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s.PartSize = MinPartSize
+
+			data := bytes.Repeat([]byte("x"), tt.size)
+
+			n, err := s.Upload(context.Background(), "bucket", "prefix/testdata.tar", bytes.NewReader(data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Upload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				if storage.aborted != 1 {
+					t.Errorf("failed upload is not aborted")
+				}
+
+				return
+			}
+
+			if n != int64(tt.size) {
+				t.Errorf("Upload() = %d, want %d", n, tt.size)
+			}
+
+			if got := storage.objects["/bucket/prefix/testdata.tar"]; !bytes.Equal(got, data) {
+				t.Errorf("stored object has %d bytes, want %d", len(got), len(data))
+			}
+
+			if got := len(storage.parts["/bucket/prefix/testdata.tar"]); got != tt.wantParts {
+				t.Errorf("uploaded %d parts, want %d", got, tt.wantParts)
+			}
+		})
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		destination string
+		want        Target
+		wantErr     bool
+	}{
+		{destination: "s3://bucket", want: Target{Bucket: "bucket"}},
+		{destination: "s3://bucket/runs/nightly/", want: Target{Bucket: "bucket", Prefix: "runs/nightly"}},
+		{destination: "s3:///prefix", wantErr: true},
+		{destination: "/tmp/data", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.destination, func(t *testing.T) {
+			got, err := ParseTarget(tt.destination)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTarget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseTarget() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package awsv4 signs HTTP requests with AWS Signature Version 4, for the few AWS-compatible APIs that Frisbee
+// talks to (S3, SSM) without pulling in the AWS SDK.
+package awsv4
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Credentials are the AWS credentials of the requests. The SessionToken is set only for temporary credentials.
+type Credentials struct {
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+}
+
+// Sign signs the request for the given region and service, at the given time. All the headers of the request are
+// signed, along with the host. The payload is the body of the request, and the query of the request must be in
+// the canonical form (see CanonicalQuery).
+func Sign(req *http.Request, payload []byte, creds Credentials, region, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	signedHeaders := []string{"host"}
+	for header := range req.Header {
+		signedHeaders = append(signedHeaders, strings.ToLower(header))
+	}
+
+	sort.Strings(signedHeaders)
+
+	var canonicalHeaders strings.Builder
+
+	for _, header := range signedHeaders {
+		value := req.Header.Get(header)
+		if header == "host" {
+			value = req.URL.Host
+		}
+
+		canonicalHeaders.WriteString(header + ":" + strings.TrimSpace(value) + "\n")
+	}
+
+	canonicalURI := req.URL.EscapedPath()
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		PayloadHash(payload),
+	}, "\n")
+
+	scope := strings.Join([]string{date, region, service, "aws4_request"}, "/")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKey, scope, strings.Join(signedHeaders, ";"), signature))
+}
+
+// PayloadHash returns the hash of the payload, as it is expected by the X-Amz-Content-Sha256 header of S3.
+func PayloadHash(payload []byte) string {
+	hash := sha256.Sum256(payload)
+
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+
+	return h.Sum(nil)
+}
+
+// CanonicalQuery encodes the query in the canonical form of the signature, which is also a valid query string.
+func CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	var pairs []string
+
+	for _, k := range keys {
+		for _, v := range query[k] {
+			pairs = append(pairs, escape(k, true)+"="+escape(v, true))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// EscapePath encodes every character of the path but the unreserved ones and the slashes, as required by the
+// signature.
+func EscapePath(path string) string {
+	return escape(path, false)
+}
+
+// escape encodes every character but the unreserved ones, as required by the signature.
+// Slashes are kept, unless the value is a query parameter.
+func escape(value string, encodeSlash bool) string {
+	var b strings.Builder
+
+	for _, c := range []byte(value) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/builder"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestScenarioBuilder(t *testing.T) {
+	tests := []struct {
+		name    string
+		build   func() *builder.ScenarioBuilder
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			build: func() *builder.ScenarioBuilder {
+				return builder.NewScenario("sweep").
+					AddServiceAction("server", "iperf.server").Scenario().
+					AddClusterAction("clients", "iperf.client", 2).
+					Inputs(map[string]interface{}{"server": "server"}, map[string]interface{}{"server": "server"}).
+					DependsOnRunning("server").Timeout(time.Minute).Scenario().
+					AddDeleteAction("teardown", "server").DependsOn("clients").Scenario()
+			},
+		},
+		{
+			name: "duplicate action",
+			build: func() *builder.ScenarioBuilder {
+				return builder.NewScenario("sweep").
+					AddServiceAction("server", "iperf.server").Scenario().
+					AddServiceAction("server", "iperf.server").Scenario()
+			},
+			wantErr: true,
+		},
+		{
+			name: "undeclared dependency",
+			build: func() *builder.ScenarioBuilder {
+				return builder.NewScenario("sweep").
+					AddClusterAction("clients", "iperf.client", 2).DependsOn("server").Scenario()
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty template",
+			build: func() *builder.ScenarioBuilder {
+				return builder.NewScenario("sweep").AddServiceAction("server", "").Scenario()
+			},
+			wantErr: true,
+		},
+		{
+			name: "inputs without template",
+			build: func() *builder.ScenarioBuilder {
+				return builder.NewScenario("sweep").
+					AddServiceAction("server", "iperf.server").Scenario().
+					AddDeleteAction("teardown", "server").Inputs(map[string]interface{}{"a": 1}).Scenario()
+			},
+			wantErr: true,
+		},
+		{
+			name: "multiple inputs of service",
+			build: func() *builder.ScenarioBuilder {
+				return builder.NewScenario("sweep").
+					AddServiceAction("server", "iperf.server").
+					Inputs(map[string]interface{}{"a": 1}, map[string]interface{}{"a": 2}).Scenario()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenario, err := tt.build().Build()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if scenario.GetName() != "sweep" || scenario.Kind != "Scenario" {
+				t.Errorf("Build() = %s/%s, want Scenario/sweep", scenario.Kind, scenario.GetName())
+			}
+		})
+	}
+}
+
+func TestScenarioBuilderActions(t *testing.T) {
+	scenario, err := builder.NewScenario("sweep").
+		AddServiceAction("server", "iperf.server").Scenario().
+		AddClusterAction("clients", "iperf.client", 3).MaxConcurrentJobs(1).DependsOnRunning("server").Scenario().
+		AddDeleteAction("teardown", "server").DependsOn("clients").Scenario().
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if got := len(scenario.Spec.Actions); got != 3 {
+		t.Fatalf("Build() actions = %d, want 3", got)
+	}
+
+	clients := scenario.Spec.Actions[1]
+
+	if clients.ActionType != v1alpha1.ActionCluster || clients.Cluster.MaxInstances != 3 || clients.Cluster.MaxConcurrentJobs != 1 {
+		t.Errorf("Build() cluster = %+v", clients.Cluster)
+	}
+
+	if clients.DependsOn == nil || len(clients.DependsOn.Running) != 1 || clients.DependsOn.Running[0] != "server" {
+		t.Errorf("Build() dependencies = %+v, want running of 'server'", clients.DependsOn)
+	}
+}
+
+func TestTemplateBuilder(t *testing.T) {
+	tests := []struct {
+		name    string
+		build   func() *builder.TemplateBuilder
+		wantErr bool
+	}{
+		{
+			name: "container",
+			build: func() *builder.TemplateBuilder {
+				return builder.NewTemplate("iperf.server").
+					Parameter("port", 5201).
+					Container(corev1.Container{Name: "main", Image: "networkstatic/iperf3"})
+			},
+		},
+		{
+			name: "scenario",
+			build: func() *builder.TemplateBuilder {
+				return builder.NewTemplate("iperf.pair").Scenario(
+					builder.NewScenario("pair").
+						AddServiceAction("server", "iperf.server").Scenario().
+						AddDeleteAction("teardown", "server").DependsOnRunning("server").Scenario())
+			},
+		},
+		{
+			name: "extends",
+			build: func() *builder.TemplateBuilder {
+				return builder.NewTemplate("iperf.custom").Extends("iperf.server").Parameter("port", 5202)
+			},
+		},
+		{
+			name:    "no spec",
+			build:   func() *builder.TemplateBuilder { return builder.NewTemplate("iperf.server") },
+			wantErr: true,
+		},
+		{
+			name: "multiple specs",
+			build: func() *builder.TemplateBuilder {
+				return builder.NewTemplate("iperf.server").
+					Container(corev1.Container{Name: "main", Image: "networkstatic/iperf3"}).
+					Chaos(v1alpha1.ChaosSpec{})
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.build().Build(); (err != nil) != tt.wantErr {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,357 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package builder composes Scenarios and Templates programmatically, for tools that generate experiments from code
+// (e.g, fuzzers, parameter sweeps), instead of assembling YAML strings.
+//
+//	scenario, err := builder.NewScenario("sweep").
+//		AddServiceAction("server", "iperf-server").Scenario().
+//		AddClusterAction("clients", "iperf-client", 100).MaxConcurrentJobs(10).DependsOnRunning("server").Scenario().
+//		AddDeleteAction("teardown", "server").DependsOn("clients").Scenario().
+//		Build()
+//
+// The errors of the builder are deferred to Build, which also validates the result as the admission webhook does.
+package builder
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// ScenarioBuilder composes a Scenario.
+type ScenarioBuilder struct {
+	scenario v1alpha1.Scenario
+
+	// err is the first error of the composition. It is returned by Build.
+	err error
+}
+
+// NewScenario starts the composition of a Scenario with the given name.
+func NewScenario(name string) *ScenarioBuilder {
+	var b ScenarioBuilder
+
+	b.scenario.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("Scenario"))
+	b.scenario.SetName(name)
+
+	return &b
+}
+
+// setErr keeps the first error of the composition.
+func (b *ScenarioBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Namespace sets the namespace of the Scenario.
+func (b *ScenarioBuilder) Namespace(namespace string) *ScenarioBuilder {
+	b.scenario.SetNamespace(namespace)
+
+	return b
+}
+
+// Label sets a label of the Scenario.
+func (b *ScenarioBuilder) Label(key, value string) *ScenarioBuilder {
+	metav1.SetMetaDataLabel(&b.scenario.ObjectMeta, key, value)
+
+	return b
+}
+
+// Spec modifies the fields of the Scenario that are not covered by the builder (e.g, TestData, Tracing).
+func (b *ScenarioBuilder) Spec(modify func(spec *v1alpha1.ScenarioSpec)) *ScenarioBuilder {
+	modify(&b.scenario.Spec)
+
+	return b
+}
+
+// AddServiceAction adds an action that creates a Service from the given template.
+func (b *ScenarioBuilder) AddServiceAction(name, templateRef string) *ActionBuilder {
+	return b.addTemplated(name, templateRef, v1alpha1.Action{
+		ActionType: v1alpha1.ActionService,
+		EmbedActions: &v1alpha1.EmbedActions{
+			Service: &v1alpha1.GenerateObjectFromTemplate{TemplateRef: templateRef},
+		},
+	})
+}
+
+// AddClusterAction adds an action that creates a Cluster with the given number of instances of the template.
+func (b *ScenarioBuilder) AddClusterAction(name, templateRef string, instances int) *ActionBuilder {
+	return b.addTemplated(name, templateRef, v1alpha1.Action{
+		ActionType: v1alpha1.ActionCluster,
+		EmbedActions: &v1alpha1.EmbedActions{
+			Cluster: &v1alpha1.ClusterSpec{
+				GenerateObjectFromTemplate: v1alpha1.GenerateObjectFromTemplate{
+					TemplateRef:  templateRef,
+					MaxInstances: instances,
+				},
+			},
+		},
+	})
+}
+
+// AddChaosAction adds an action that injects the fault of the given template.
+func (b *ScenarioBuilder) AddChaosAction(name, templateRef string) *ActionBuilder {
+	return b.addTemplated(name, templateRef, v1alpha1.Action{
+		ActionType: v1alpha1.ActionChaos,
+		EmbedActions: &v1alpha1.EmbedActions{
+			Chaos: &v1alpha1.GenerateObjectFromTemplate{TemplateRef: templateRef},
+		},
+	})
+}
+
+// AddCascadeAction adds an action that injects the given number of faults of the template.
+func (b *ScenarioBuilder) AddCascadeAction(name, templateRef string, instances int) *ActionBuilder {
+	return b.addTemplated(name, templateRef, v1alpha1.Action{
+		ActionType: v1alpha1.ActionCascade,
+		EmbedActions: &v1alpha1.EmbedActions{
+			Cascade: &v1alpha1.CascadeSpec{
+				GenerateObjectFromTemplate: v1alpha1.GenerateObjectFromTemplate{
+					TemplateRef:  templateRef,
+					MaxInstances: instances,
+				},
+			},
+		},
+	})
+}
+
+// AddDeleteAction adds an action that deletes the jobs of the given actions.
+func (b *ScenarioBuilder) AddDeleteAction(name string, jobs ...string) *ActionBuilder {
+	return b.AddAction(v1alpha1.Action{
+		ActionType:   v1alpha1.ActionDelete,
+		Name:         name,
+		EmbedActions: &v1alpha1.EmbedActions{Delete: &v1alpha1.DeleteSpec{Jobs: jobs}},
+	})
+}
+
+// AddAction adds an action that is composed by the caller. It is used for the types of actions that have no
+// dedicated method (e.g, Call, Seed, AssertSQL).
+func (b *ScenarioBuilder) AddAction(action v1alpha1.Action) *ActionBuilder {
+	b.scenario.Spec.Actions = append(b.scenario.Spec.Actions, action)
+
+	return &ActionBuilder{scenario: b, actions: &b.scenario.Spec.Actions, index: len(b.scenario.Spec.Actions) - 1}
+}
+
+// AddTeardownAction adds an action that runs when the scenario is aborted.
+func (b *ScenarioBuilder) AddTeardownAction(action v1alpha1.Action) *ActionBuilder {
+	b.scenario.Spec.Teardown = append(b.scenario.Spec.Teardown, action)
+
+	return &ActionBuilder{scenario: b, actions: &b.scenario.Spec.Teardown, index: len(b.scenario.Spec.Teardown) - 1}
+}
+
+func (b *ScenarioBuilder) addTemplated(name, templateRef string, action v1alpha1.Action) *ActionBuilder {
+	if templateRef == "" {
+		b.setErr(errors.Errorf("action '%s' has empty templateRef", name))
+	}
+
+	action.Name = name
+
+	return b.AddAction(action)
+}
+
+// Build returns the composed Scenario, or the first error of the composition. The Scenario is validated as it
+// would be by the admission webhook, but without the defaults of the webhook, which are set upon submission.
+func (b *ScenarioBuilder) Build() (*v1alpha1.Scenario, error) {
+	if b.err != nil {
+		return nil, errors.Wrapf(b.err, "scenario '%s'", b.scenario.GetName())
+	}
+
+	validated := b.scenario.DeepCopy()
+	validated.Default()
+
+	if _, err := validated.ValidateCreate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid scenario '%s'", b.scenario.GetName())
+	}
+
+	return b.scenario.DeepCopy(), nil
+}
+
+// YAML returns the composed Scenario as a manifest that can be submitted with 'kubectl frisbee submit test'.
+func (b *ScenarioBuilder) YAML() ([]byte, error) {
+	scenario, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(scenario)
+}
+
+// ActionBuilder composes an action of a Scenario.
+type ActionBuilder struct {
+	scenario *ScenarioBuilder
+
+	// actions is the list where the action is placed (i.e, actions or teardown). The action is referenced by index,
+	// because the list grows as more actions are added.
+	actions *[]v1alpha1.Action
+	index   int
+}
+
+func (a *ActionBuilder) action() *v1alpha1.Action {
+	return &(*a.actions)[a.index]
+}
+
+// Scenario returns the builder of the Scenario, in order to continue with the next action.
+func (a *ActionBuilder) Scenario() *ScenarioBuilder {
+	return a.scenario
+}
+
+// DependsOn starts the action once the given actions are successfully completed.
+func (a *ActionBuilder) DependsOn(actions ...string) *ActionBuilder {
+	deps := a.dependencies()
+	deps.Success = append(deps.Success, actions...)
+
+	return a
+}
+
+// DependsOnRunning starts the action once the given actions are running.
+func (a *ActionBuilder) DependsOnRunning(actions ...string) *ActionBuilder {
+	deps := a.dependencies()
+	deps.Running = append(deps.Running, actions...)
+
+	return a
+}
+
+// After starts the action once the given duration has passed since the beginning of the scenario.
+func (a *ActionBuilder) After(d time.Duration) *ActionBuilder {
+	a.dependencies().After = &metav1.Duration{Duration: d}
+
+	return a
+}
+
+func (a *ActionBuilder) dependencies() *v1alpha1.WaitSpec {
+	action := a.action()
+
+	if action.DependsOn == nil {
+		action.DependsOn = &v1alpha1.WaitSpec{}
+	}
+
+	return action.DependsOn
+}
+
+// Timeout fails the scenario if the action is not completed within the given duration.
+func (a *ActionBuilder) Timeout(d time.Duration) *ActionBuilder {
+	a.action().Timeout = &metav1.Duration{Duration: d}
+
+	return a
+}
+
+// Assert sets the conditions that must be maintained once the action is started.
+func (a *ActionBuilder) Assert(expr v1alpha1.ConditionalExpr) *ActionBuilder {
+	a.action().Assert = &expr
+
+	return a
+}
+
+// Inputs sets the parameters of the template. Services and Chaos take a single set of parameters, whereas the
+// instances of Clusters and Cascades take a set each. The values are encoded as JSON.
+func (a *ActionBuilder) Inputs(inputs ...map[string]interface{}) *ActionBuilder {
+	template := a.template()
+	if template == nil {
+		return a
+	}
+
+	action := a.action()
+
+	if len(inputs) > 1 && (action.ActionType == v1alpha1.ActionService || action.ActionType == v1alpha1.ActionChaos) {
+		a.scenario.setErr(errors.Errorf("action '%s' accepts a single set of inputs", action.Name))
+
+		return a
+	}
+
+	for _, values := range inputs {
+		encoded, err := userInputs(values)
+		if err != nil {
+			a.scenario.setErr(errors.Wrapf(err, "action '%s'", action.Name))
+
+			return a
+		}
+
+		template.Inputs = append(template.Inputs, encoded)
+	}
+
+	return a
+}
+
+// Schedule sets the interval between the creation of the jobs of a Cluster or a Cascade.
+func (a *ActionBuilder) Schedule(schedule v1alpha1.TaskSchedulerSpec) *ActionBuilder {
+	switch action := a.action(); action.ActionType {
+	case v1alpha1.ActionCluster:
+		action.Cluster.Schedule = &schedule
+	case v1alpha1.ActionCascade:
+		action.Cascade.Schedule = &schedule
+	default:
+		a.scenario.setErr(errors.Errorf("action '%s' of type '%s' cannot be scheduled", action.Name, action.ActionType))
+	}
+
+	return a
+}
+
+// MaxConcurrentJobs bounds the number of Services of a Cluster that run simultaneously.
+func (a *ActionBuilder) MaxConcurrentJobs(n int) *ActionBuilder {
+	if action := a.action(); action.ActionType == v1alpha1.ActionCluster {
+		action.Cluster.MaxConcurrentJobs = n
+	} else {
+		a.scenario.setErr(errors.Errorf("action '%s' of type '%s' has no concurrent jobs", action.Name, action.ActionType))
+	}
+
+	return a
+}
+
+// Modify changes the fields of the action that are not covered by the builder.
+func (a *ActionBuilder) Modify(modify func(action *v1alpha1.Action)) *ActionBuilder {
+	modify(a.action())
+
+	return a
+}
+
+// template returns the template of the action, or nil if the action is not generated from a template.
+func (a *ActionBuilder) template() *v1alpha1.GenerateObjectFromTemplate {
+	switch action := a.action(); action.ActionType {
+	case v1alpha1.ActionService:
+		return action.Service
+	case v1alpha1.ActionChaos:
+		return action.Chaos
+	case v1alpha1.ActionCluster:
+		return &action.Cluster.GenerateObjectFromTemplate
+	case v1alpha1.ActionCascade:
+		return &action.Cascade.GenerateObjectFromTemplate
+	default:
+		a.scenario.setErr(errors.Errorf("action '%s' of type '%s' has no template", action.Name, action.ActionType))
+
+		return nil
+	}
+}
+
+// userInputs encodes the values as the inputs of a template.
+func userInputs(values map[string]interface{}) (v1alpha1.UserInputs, error) {
+	inputs := make(v1alpha1.UserInputs, len(values))
+
+	for key, value := range values {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot encode input '%s'", key)
+		}
+
+		inputs[key] = &apiextensionsv1.JSON{Raw: raw}
+	}
+
+	return inputs, nil
+}
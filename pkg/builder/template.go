@@ -0,0 +1,176 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"encoding/json"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// TemplateBuilder composes a Template.
+type TemplateBuilder struct {
+	template v1alpha1.Template
+
+	// err is the first error of the composition. It is returned by Build.
+	err error
+}
+
+// NewTemplate starts the composition of a Template with the given name.
+func NewTemplate(name string) *TemplateBuilder {
+	var b TemplateBuilder
+
+	b.template.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("Template"))
+	b.template.SetName(name)
+
+	return &b
+}
+
+func (b *TemplateBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Namespace sets the namespace of the Template.
+func (b *TemplateBuilder) Namespace(namespace string) *TemplateBuilder {
+	b.template.SetNamespace(namespace)
+
+	return b
+}
+
+// Extends inherits the spec of the given template.
+func (b *TemplateBuilder) Extends(template string) *TemplateBuilder {
+	b.template.Spec.Extends = template
+
+	return b
+}
+
+// Parameter sets the default value of a parameter. The value is encoded as JSON.
+func (b *TemplateBuilder) Parameter(key string, value interface{}) *TemplateBuilder {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		b.setErr(errors.Wrapf(err, "cannot encode parameter '%s'", key))
+
+		return b
+	}
+
+	if b.template.Spec.Inputs == nil {
+		b.template.Spec.Inputs = &v1alpha1.TemplateInputs{}
+	}
+
+	if b.template.Spec.Inputs.Parameters == nil {
+		b.template.Spec.Inputs.Parameters = v1alpha1.Parameters{}
+	}
+
+	b.template.Spec.Inputs.Parameters[key] = &apiextensionsv1.JSON{Raw: raw}
+
+	return b
+}
+
+// Service sets the spec of the Services that are generated from the template.
+func (b *TemplateBuilder) Service(spec v1alpha1.ServiceSpec) *TemplateBuilder {
+	b.embedSpecs().Service = &spec
+
+	return b
+}
+
+// Container sets a Service of a single container, which is the common case for templates.
+func (b *TemplateBuilder) Container(container corev1.Container) *TemplateBuilder {
+	var spec v1alpha1.ServiceSpec
+
+	spec.Containers = []corev1.Container{container}
+
+	return b.Service(spec)
+}
+
+// Chaos sets the spec of the faults that are generated from the template.
+func (b *TemplateBuilder) Chaos(spec v1alpha1.ChaosSpec) *TemplateBuilder {
+	b.embedSpecs().Chaos = &spec
+
+	return b
+}
+
+// Scenario sets the spec of the scenarios that are generated from the template. The errors of the scenario
+// builder are returned by the Build of the template.
+func (b *TemplateBuilder) Scenario(scenario *ScenarioBuilder) *TemplateBuilder {
+	if scenario.err != nil {
+		b.setErr(errors.Wrapf(scenario.err, "scenario '%s'", scenario.scenario.GetName()))
+
+		return b
+	}
+
+	b.embedSpecs().Scenario = scenario.scenario.Spec.DeepCopy()
+
+	return b
+}
+
+func (b *TemplateBuilder) embedSpecs() *v1alpha1.EmbedSpecs {
+	if b.template.Spec.EmbedSpecs == nil {
+		b.template.Spec.EmbedSpecs = &v1alpha1.EmbedSpecs{}
+	}
+
+	return b.template.Spec.EmbedSpecs
+}
+
+// Build returns the composed Template, or the first error of the composition. A template embeds exactly one
+// spec, unless it extends another template.
+func (b *TemplateBuilder) Build() (*v1alpha1.Template, error) {
+	if b.err != nil {
+		return nil, errors.Wrapf(b.err, "template '%s'", b.template.GetName())
+	}
+
+	embedded := 0
+
+	if specs := b.template.Spec.EmbedSpecs; specs != nil {
+		for _, set := range []bool{specs.Service != nil, specs.Chaos != nil, specs.Scenario != nil} {
+			if set {
+				embedded++
+			}
+		}
+	}
+
+	switch {
+	case embedded > 1:
+		return nil, errors.Errorf("template '%s' embeds more than one spec", b.template.GetName())
+	case embedded == 0 && b.template.Spec.Extends == "":
+		return nil, errors.Errorf("template '%s' embeds no spec", b.template.GetName())
+	}
+
+	validated := b.template.DeepCopy()
+	validated.Default()
+
+	if _, err := validated.ValidateCreate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid template '%s'", b.template.GetName())
+	}
+
+	return b.template.DeepCopy(), nil
+}
+
+// YAML returns the composed Template as a manifest that can be installed in the cluster.
+func (b *TemplateBuilder) YAML() ([]byte, error) {
+	template, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(template)
+}
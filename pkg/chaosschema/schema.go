@@ -0,0 +1,196 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaosschema
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/pruning"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+/*
+	The schemas of the Chaos-Mesh faults are not known to Frisbee, as they depend on the version of Chaos-Mesh that is
+	installed in the cluster. Instead, they are fetched from the CRDs of the cluster, and the manifests are validated
+	in the same way as the API server does it, with one difference: unknown fields are rejected, instead of being
+	silently pruned. A misspelled field would otherwise result in a fault that differs from the intended one.
+
+	The compiled schemas are cached by the generation of their CRD. Upgrades of Chaos-Mesh are thus picked up without
+	restarting the operator.
+
+	Nothing in the validation is specific to Chaos-Mesh. The CLI uses ValidateAgainst for validating any custom
+	resource (e.g, Scenarios) before it is submitted.
+*/
+
+// FetchTimeout bounds the retrieval of a CRD from the API server.
+const FetchTimeout = 10 * time.Second
+
+type compiledSchema struct {
+	generation int64
+
+	validator  *validate.SchemaValidator
+	structural *structuralschema.Structural
+}
+
+// Validator validates the Chaos-Mesh manifests against the schemas of their CRDs.
+type Validator struct {
+	reader client.Reader
+	mapper meta.RESTMapper
+
+	lock    sync.Mutex
+	schemas map[string]compiledSchema
+}
+
+// New returns a validator that reads the CRDs through the given reader. Because the CRDs are cluster-wide objects
+// that are not watched by the operator, the reader should not be cached (e.g, mgr.GetAPIReader()).
+func New(reader client.Reader, mapper meta.RESTMapper) *Validator {
+	return &Validator{
+		reader:  reader,
+		mapper:  mapper,
+		schemas: make(map[string]compiledSchema),
+	}
+}
+
+// Validate checks the fault against the schema of the version of its CRD.
+func (v *Validator) Validate(fault *unstructured.Unstructured) error {
+	gvk := fault.GroupVersionKind()
+
+	mapping, err := v.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return errors.Wrapf(err, "'%s' is not installed", gvk)
+	}
+
+	crdName := mapping.Resource.Resource + "." + gvk.Group
+
+	ctx, cancel := context.WithTimeout(context.Background(), FetchTimeout)
+	defer cancel()
+
+	schema, err := v.getSchema(ctx, crdName, gvk.Version)
+	if err != nil {
+		return err
+	}
+
+	return schema.validate(fault)
+}
+
+// ValidateAgainst checks the object against the schema of the given CRD, for the version of the object.
+func ValidateAgainst(crd *apiextensionsv1.CustomResourceDefinition, object *unstructured.Unstructured) error {
+	schema, err := compile(crd, object.GroupVersionKind().Version)
+	if err != nil {
+		return errors.Wrapf(err, "CRD '%s'", crd.GetName())
+	}
+
+	return schema.validate(object)
+}
+
+// validate checks the object against the schema. Unknown fields are rejected, instead of being pruned.
+func (schema compiledSchema) validate(object *unstructured.Unstructured) error {
+	// validate a copy, as the pruning modifies the object.
+	obj := runtime.DeepCopyJSON(object.UnstructuredContent())
+
+	if errs := validation.ValidateCustomResource(nil, obj, schema.validator); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+
+	if unknown := pruning.PruneWithOptions(obj, schema.structural, true, structuralschema.UnknownFieldPathOptions{
+		TrackUnknownFieldPaths: true,
+	}); len(unknown) > 0 {
+		return errors.Errorf("unknown fields: %s", strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
+
+// getSchema returns the compiled schema of the given version of the CRD, and recompiles it if the CRD has changed.
+func (v *Validator) getSchema(ctx context.Context, crdName string, version string) (compiledSchema, error) {
+	var crd apiextensionsv1.CustomResourceDefinition
+
+	if err := v.reader.Get(ctx, client.ObjectKey{Name: crdName}, &crd); err != nil {
+		return compiledSchema{}, errors.Wrapf(err, "cannot get CRD '%s'", crdName)
+	}
+
+	key := crdName + "/" + version
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if cached, exists := v.schemas[key]; exists && cached.generation == crd.GetGeneration() {
+		return cached, nil
+	}
+
+	schema, err := compile(&crd, version)
+	if err != nil {
+		return compiledSchema{}, errors.Wrapf(err, "CRD '%s'", crdName)
+	}
+
+	v.schemas[key] = schema
+
+	return schema, nil
+}
+
+func compile(crd *apiextensionsv1.CustomResourceDefinition, version string) (compiledSchema, error) {
+	for _, crdVersion := range crd.Spec.Versions {
+		if crdVersion.Name != version {
+			continue
+		}
+
+		if !crdVersion.Served {
+			return compiledSchema{}, errors.Errorf("version '%s' is not served", version)
+		}
+
+		if crdVersion.Schema == nil || crdVersion.Schema.OpenAPIV3Schema == nil {
+			return compiledSchema{}, errors.Errorf("version '%s' has no schema", version)
+		}
+
+		var internal apiextensions.CustomResourceValidation
+
+		if err := apiextensionsv1.Convert_v1_CustomResourceValidation_To_apiextensions_CustomResourceValidation(
+			crdVersion.Schema, &internal, nil); err != nil {
+			return compiledSchema{}, errors.Wrapf(err, "cannot convert schema")
+		}
+
+		validator, _, err := validation.NewSchemaValidator(&internal)
+		if err != nil {
+			return compiledSchema{}, errors.Wrapf(err, "cannot build validator")
+		}
+
+		structural, err := structuralschema.NewStructural(internal.OpenAPIV3Schema)
+		if err != nil {
+			return compiledSchema{}, errors.Wrapf(err, "schema is not structural")
+		}
+
+		return compiledSchema{
+			generation: crd.GetGeneration(),
+			validator:  validator,
+			structural: structural,
+		}, nil
+	}
+
+	return compiledSchema{}, errors.Errorf("version '%s' is not defined", version)
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaosschema_test
+
+import (
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/chaosschema"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func networkChaosCRD() *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "networkchaos.chaos-mesh.org"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "chaos-mesh.org",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: "NetworkChaos", Plural: "networkchaos"},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:    "v1alpha1",
+				Served:  true,
+				Storage: true,
+				Schema: &apiextensionsv1.CustomResourceValidation{
+					OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]apiextensionsv1.JSONSchemaProps{
+							"apiVersion": {Type: "string"},
+							"kind":       {Type: "string"},
+							"metadata":   {Type: "object"},
+							"spec": {
+								Type:     "object",
+								Required: []string{"action"},
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"action": {
+										Type: "string",
+										Enum: []apiextensionsv1.JSON{{Raw: []byte(`"delay"`)}, {Raw: []byte(`"loss"`)}},
+									},
+									"duration": {Type: "string"},
+								},
+							},
+						},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func TestValidator_Validate(t *testing.T) {
+	scheme := runtime.NewScheme()
+
+	if err := apiextensionsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("cannot build scheme: %v", err)
+	}
+
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(networkChaosCRD()).Build()
+
+	gv := schema.GroupVersion{Group: "chaos-mesh.org", Version: "v1alpha1"}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{gv})
+	mapper.AddSpecific(gv.WithKind("NetworkChaos"), gv.WithResource("networkchaos"), gv.WithResource("networkchaos"), meta.RESTScopeNamespace)
+
+	validator := chaosschema.New(reader, mapper)
+
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			raw: `
+apiVersion: chaos-mesh.org/v1alpha1
+kind: NetworkChaos
+spec:
+  action: delay
+  duration: 1m
+`,
+			wantErr: false,
+		},
+		{
+			name: "invalid-enum",
+			raw: `
+apiVersion: chaos-mesh.org/v1alpha1
+kind: NetworkChaos
+spec:
+  action: corrupt
+`,
+			wantErr: true,
+		},
+		{
+			name: "missing-required",
+			raw: `
+apiVersion: chaos-mesh.org/v1alpha1
+kind: NetworkChaos
+spec:
+  duration: 1m
+`,
+			wantErr: true,
+		},
+		{
+			name: "unknown-field",
+			raw: `
+apiVersion: chaos-mesh.org/v1alpha1
+kind: NetworkChaos
+spec:
+  action: delay
+  durration: 1m
+`,
+			wantErr: true,
+		},
+		{
+			name: "not-installed",
+			raw: `
+apiVersion: chaos-mesh.org/v1alpha1
+kind: StressChaos
+spec:
+  mode: all
+`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fault, err := v1alpha1.ParseRawChaos(tt.raw)
+			if err != nil {
+				t.Fatalf("cannot parse manifest: %v", err)
+			}
+
+			if err := validator.Validate(fault); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
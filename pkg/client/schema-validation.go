@@ -0,0 +1,127 @@
+/*
+Copyright 2022-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"io/fs"
+	"strings"
+
+	"github.com/carv-ics-forth/frisbee/pkg/chaosschema"
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// LoadCRDs parses the CRDs of the given directory (e.g, the CRDs that are bundled with the CLI).
+func LoadCRDs(fsys fs.FS, dir string) (map[schema.GroupKind]*apiextensionsv1.CustomResourceDefinition, error) {
+	files, err := fs.Glob(fsys, dir+"/*.yaml")
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot list CRDs")
+	}
+
+	crds := make(map[schema.GroupKind]*apiextensionsv1.CustomResourceDefinition, len(files))
+
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot read CRD '%s'", file)
+		}
+
+		var crd apiextensionsv1.CustomResourceDefinition
+
+		if err := yaml.Unmarshal(data, &crd); err != nil {
+			return nil, errors.Wrapf(err, "cannot decode CRD '%s'", file)
+		}
+
+		crds[schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}] = &crd
+	}
+
+	return crds, nil
+}
+
+// ValidateSchemas validates the custom resources against the OpenAPI schemas of their CRDs, before anything is
+// created. The schemas of the installed CRDs take precedence. The bundled CRDs are used for the kinds whose CRDs are
+// not installed, or cannot be read. Resources of built-in kinds (e.g, ConfigMaps) are not validated.
+func (c TestManagementClient) ValidateSchemas(ctx context.Context, objects []*unstructured.Unstructured,
+	bundled map[schema.GroupKind]*apiextensionsv1.CustomResourceDefinition,
+) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	var errs []error
+
+	for _, obj := range objects {
+		gvk := obj.GroupVersionKind()
+
+		// the groups of custom resources must contain a dot. Groups without dots are built-in.
+		if !strings.Contains(gvk.Group, ".") {
+			continue
+		}
+
+		crd, err := c.getCRD(ctx, gvk)
+		if err != nil {
+			fallback, exists := bundled[gvk.GroupKind()]
+
+			switch {
+			case exists:
+				crd = fallback
+			case k8errors.IsForbidden(err):
+				// the schema cannot be read. The resource is validated by the API server.
+				continue
+			default:
+				errs = append(errs, errors.Wrapf(err, "%s/%s", gvk.Kind, obj.GetName()))
+
+				continue
+			}
+		}
+
+		if err := chaosschema.ValidateAgainst(crd, obj); err != nil {
+			errs = append(errs, errors.Wrapf(err, "%s/%s", gvk.Kind, obj.GetName()))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// getCRD returns the installed CRD of the given kind.
+func (c TestManagementClient) getCRD(ctx context.Context, gvk schema.GroupVersionKind) (*apiextensionsv1.CustomResourceDefinition, error) {
+	mapping, err := c.client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, errors.Errorf("kind '%s' is not installed", gvk)
+		}
+
+		return nil, errors.Wrapf(err, "cannot find kind '%s'", gvk)
+	}
+
+	var crd apiextensionsv1.CustomResourceDefinition
+
+	name := mapping.Resource.Resource + "." + gvk.Group
+
+	if err := c.client.Get(ctx, client.ObjectKey{Name: name}, &crd); err != nil {
+		return nil, errors.Wrapf(err, "cannot get CRD '%s'", name)
+	}
+
+	return &crd, nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	frisbeeclient "github.com/carv-ics-forth/frisbee/pkg/client"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+)
+
+func TestValidateSchemas_Bundled(t *testing.T) {
+	scheme := runtime.NewScheme()
+
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := apiextensionsv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	bundled, err := frisbeeclient.LoadCRDs(os.DirFS("../.."), "charts/platform/crds")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the CRDs are not installed in the fake cluster. Therefore, the bundled CRDs are used.
+	c := frisbeeclient.NewTestManagementClient(fake.NewClientBuilder().WithScheme(scheme).Build())
+
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			raw: `
+apiVersion: frisbee.dev/v1alpha1
+kind: Scenario
+metadata:
+  name: valid
+spec:
+  actions:
+    - action: Service
+      name: server
+      service:
+        templateRef: frisbee.apps.iperf2.server
+`,
+		},
+		{
+			name: "unknown field",
+			raw: `
+apiVersion: frisbee.dev/v1alpha1
+kind: Scenario
+metadata:
+  name: typo
+spec:
+  actions:
+    - action: Service
+      name: server
+      servce:
+        templateRef: frisbee.apps.iperf2.server
+`,
+			wantErr: "spec.actions[0].servce",
+		},
+		{
+			name: "invalid enum",
+			raw: `
+apiVersion: frisbee.dev/v1alpha1
+kind: Scenario
+metadata:
+  name: enum
+spec:
+  actions:
+    - action: Servce
+      name: server
+`,
+			wantErr: "spec.actions[0].action",
+		},
+		{
+			name: "built-in kind",
+			raw: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+unknown: field
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var obj unstructured.Unstructured
+
+			if err := yaml.Unmarshal([]byte(tt.raw), &obj.Object); err != nil {
+				t.Fatal(err)
+			}
+
+			err := c.ValidateSchemas(context.Background(), []*unstructured.Unstructured{&obj}, bundled)
+
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("error = %v, want mention of '%s'", err, tt.wantErr)
+			}
+		})
+	}
+}
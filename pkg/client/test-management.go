@@ -19,27 +19,57 @@ package client
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// DefaultRequestTimeout bounds every call of the client, unless the caller sets an earlier deadline.
+const DefaultRequestTimeout = time.Minute
+
 // NewTestManagementClient creates new Test client.
 func NewTestManagementClient(client client.Client) TestManagementClient {
 	return TestManagementClient{
-		client: client,
+		client:  client,
+		timeout: DefaultRequestTimeout,
 	}
 }
 
 type TestManagementClient struct {
 	client client.Client
+
+	// timeout bounds every call of the client. Zero means that the calls are bounded only by the caller's context.
+	timeout time.Duration
+}
+
+// WithTimeout returns a copy of the client whose calls are bounded by the given timeout. Zero removes the bound.
+func (c TestManagementClient) WithTimeout(timeout time.Duration) TestManagementClient {
+	c.timeout = timeout
+
+	return c
+}
+
+// withTimeout derives the context of a call. The call is cancelled either when the caller cancels it
+// (e.g, on Ctrl-C), or when the timeout expires.
+func (c TestManagementClient) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, c.timeout)
 }
 
 // GetScenario returns single scenario by id.
 func (c TestManagementClient) GetScenario(ctx context.Context, id string) (scenario *v1alpha1.Scenario, err error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	filters := &client.ListOptions{Namespace: id}
 
 	var scenarios v1alpha1.ScenarioList
@@ -48,18 +78,60 @@ func (c TestManagementClient) GetScenario(ctx context.Context, id string) (scena
 		return nil, errors.Wrapf(err, "cannot list resources")
 	}
 
-	switch numItems := len(scenarios.Items); numItems {
-	case 0:
+	if len(scenarios.Items) == 0 {
 		return nil, nil
-	case 1:
-		return &scenarios.Items[0], nil
-	default:
-		return nil, errors.Errorf("test '%s' has %d scenarios", id, numItems)
 	}
+
+	return latestScenario(scenarios.Items), nil
+}
+
+// latestScenario returns the most recently created scenario. A test may have multiple scenarios
+// when scenarios are chained. In this case, the latest scenario is the active link of the chain.
+func latestScenario(scenarios []v1alpha1.Scenario) *v1alpha1.Scenario {
+	latest := &scenarios[0]
+
+	for i := range scenarios {
+		if latest.CreationTimestamp.Before(&scenarios[i].CreationTimestamp) {
+			latest = &scenarios[i]
+		}
+	}
+
+	return latest
+}
+
+// GetPayloads returns the payloads that have been offloaded from the status of an object to the given ConfigMap.
+func (c TestManagementClient) GetPayloads(ctx context.Context, namespace string, name string) (map[string]string, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	var configMap corev1.ConfigMap
+
+	if err := c.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &configMap); err != nil {
+		return nil, errors.Wrapf(err, "cannot get payloads '%s'", name)
+	}
+
+	return configMap.Data, nil
+}
+
+// GetSecret returns the given Secret.
+func (c TestManagementClient) GetSecret(ctx context.Context, namespace string, name string) (*corev1.Secret, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	var secret corev1.Secret
+
+	if err := c.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &secret); err != nil {
+		return nil, errors.Wrapf(err, "cannot get secret '%s'", name)
+	}
+
+	return &secret, nil
 }
 
 // ListScenarios list all scenarios.
 func (c TestManagementClient) ListScenarios(ctx context.Context, selector string) (scenarios v1alpha1.ScenarioList, err error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	set, err := labels.ConvertSelectorToLabelsMap(selector)
 	if err != nil {
 		return scenarios, errors.Wrapf(err, "invalid selector")
@@ -108,24 +180,84 @@ func (c TestManagementClient) ListScenarios(ctx context.Context, selector string
 
 			scenarios.Items = append(scenarios.Items, dummy)
 
-		case 1:
+		default:
+			latest := latestScenario(localList.Items)
+
 			if !namespace.GetDeletionTimestamp().IsZero() { // Some rewrite for output to make more sense
-				localList.Items[0].Status.Phase = "Terminating"
+				latest.Status.Phase = "Terminating"
 			}
 
-			scenarios.Items = append(scenarios.Items, localList.Items[0])
-		default:
-			return v1alpha1.ScenarioList{}, errors.Errorf("test '%s' has %d scenarios", namespace.GetName(), numItems)
+			scenarios.Items = append(scenarios.Items, *latest)
 		}
 	}
 
 	return scenarios, nil
 }
 
-// ListVirtualObjects list all virtual objects.
+// ListTestNamespaces returns the namespace of the test, along with the namespaces where the scenario places its
+// jobs (e.g, target namespaces). These namespaces are labeled with the namespace of the test.
+func (c TestManagementClient) ListTestNamespaces(ctx context.Context, testName string) ([]string, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	var placed corev1.NamespaceList
+
+	if err := c.client.List(ctx, &placed, client.MatchingLabels{v1alpha1.LabelParentNamespace: testName}); err != nil {
+		return nil, errors.Wrapf(err, "cannot list the namespaces of test '%s'", testName)
+	}
+
+	namespaces := []string{testName}
+
+	for _, namespace := range placed.Items {
+		namespaces = append(namespaces, namespace.GetName())
+	}
+
+	return namespaces, nil
+}
+
+// listInTest lists the objects across the namespaces of the test. If no test is given, it lists the objects
+// across all namespaces.
+func (c TestManagementClient) listInTest(ctx context.Context, testName string, list client.ObjectList, filter client.ListOptions) error {
+	if testName == "" {
+		return c.client.List(ctx, list, &filter)
+	}
+
+	namespaces, err := c.ListTestNamespaces(ctx, testName)
+	if err != nil {
+		return err
+	}
+
+	var items []runtime.Object
+
+	for _, namespace := range namespaces {
+		partial, ok := list.DeepCopyObject().(client.ObjectList)
+		if !ok {
+			return errors.Errorf("'%T' is not a list", list)
+		}
+
+		filter.Namespace = namespace
+
+		if err := c.client.List(ctx, partial, &filter); err != nil {
+			return err
+		}
+
+		extracted, err := meta.ExtractList(partial)
+		if err != nil {
+			return errors.Wrapf(err, "cannot extract list")
+		}
+
+		items = append(items, extracted...)
+	}
+
+	return meta.SetList(list, items)
+}
+
+// ListVirtualObjects list all virtual objects, across the namespaces of the test.
 func (c TestManagementClient) ListVirtualObjects(ctx context.Context, namespace string, selectors ...string) (list v1alpha1.VirtualObjectList, err error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	var filter client.ListOptions
-	filter.Namespace = namespace
 
 	if selectors != nil {
 		set, err := labels.ConvertSelectorToLabelsMap(strings.Join(selectors, ","))
@@ -137,17 +269,19 @@ func (c TestManagementClient) ListVirtualObjects(ctx context.Context, namespace
 		filter.LabelSelector = labels.SelectorFromValidatedSet(set)
 	}
 
-	if err = c.client.List(ctx, &list, &filter); err != nil {
+	if err = c.listInTest(ctx, namespace, &list, filter); err != nil {
 		return v1alpha1.VirtualObjectList{}, errors.Wrapf(err, "cannot list resources")
 	}
 
 	return list, err
 }
 
-// ListServices list all services.
+// ListServices list all services, across the namespaces of the test.
 func (c TestManagementClient) ListServices(ctx context.Context, namespace string, selectors ...string) (list v1alpha1.ServiceList, err error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	var filter client.ListOptions
-	filter.Namespace = namespace
 
 	if selectors != nil {
 		set, err := labels.ConvertSelectorToLabelsMap(strings.Join(selectors, ","))
@@ -159,9 +293,21 @@ func (c TestManagementClient) ListServices(ctx context.Context, namespace string
 		filter.LabelSelector = labels.SelectorFromValidatedSet(set)
 	}
 
-	if err = c.client.List(ctx, &list, &filter); err != nil {
+	if err = c.listInTest(ctx, namespace, &list, filter); err != nil {
 		return v1alpha1.ServiceList{}, errors.Wrapf(err, "cannot list resources")
 	}
 
 	return list, err
 }
+
+// ListEvents list all the Kubernetes events of a test, across the namespaces of the test.
+func (c TestManagementClient) ListEvents(ctx context.Context, namespace string) (list corev1.EventList, err error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	if err = c.listInTest(ctx, namespace, &list, client.ListOptions{}); err != nil {
+		return corev1.EventList{}, errors.Wrapf(err, "cannot list resources")
+	}
+
+	return list, err
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	frisbeeclient "github.com/carv-ics-forth/frisbee/pkg/client"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newStalledClient returns a client whose requests block until they are cancelled.
+func newStalledClient(t *testing.T) frisbeeclient.TestManagementClient {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	stalled := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		List: func(ctx context.Context, _ client.WithWatch, _ client.ObjectList, _ ...client.ListOption) error {
+			<-ctx.Done()
+
+			return ctx.Err()
+		},
+	}).Build()
+
+	return frisbeeclient.NewTestManagementClient(stalled)
+}
+
+func TestTimeout(t *testing.T) {
+	c := newStalledClient(t).WithTimeout(10 * time.Millisecond)
+
+	_, err := c.GetScenario(context.Background(), "test")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetScenario() error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestCancellation(t *testing.T) {
+	// without a timeout, the call is bounded only by the caller.
+	c := newStalledClient(t).WithTimeout(0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err := c.GetScenario(ctx, "test")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetScenario() error = %v, want cancelled", err)
+	}
+}
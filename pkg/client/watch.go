@@ -0,0 +1,155 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrTestNotFound is returned when the watched test has no scenario.
+var ErrTestNotFound = errors.New("test not found")
+
+/*
+WatchTest streams the status of the test, as it is updated by the controller. The channel receives the current
+status of the test, and then every update of it. Like GetScenario, the status is the status of the latest scenario of
+the test, so that the stream follows the chained scenarios.
+
+Updates are not queued. If the receiver is slower than the updates, it receives only the latest status.
+
+The channel is closed once the context is cancelled, or once the scenarios of the test are deleted. The timeout of the
+client bounds only the initial listing of the scenarios, not the stream.
+
+The client must support watches (e.g, created by client.NewWithWatch).
+*/
+func (c TestManagementClient) WatchTest(ctx context.Context, id string) (<-chan *v1alpha1.Scenario, error) {
+	watcher, ok := c.client.(client.WithWatch)
+	if !ok {
+		return nil, errors.Errorf("the client does not support watches")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	informer := cache.NewSharedIndexInformer(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			var scenarios v1alpha1.ScenarioList
+
+			err := watcher.List(ctx, &scenarios, &client.ListOptions{Namespace: id, Raw: &options})
+
+			return &scenarios, err
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			var scenarios v1alpha1.ScenarioList
+
+			return watcher.Watch(ctx, &scenarios, &client.ListOptions{Namespace: id, Raw: &options})
+		},
+	}, &v1alpha1.Scenario{}, 0, cache.Indexers{})
+
+	// the handlers only signal the change. The status is read from the store, so that the updates are coalesced.
+	changed := make(chan struct{}, 1)
+
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { notify() },
+		UpdateFunc: func(interface{}, interface{}) { notify() },
+		DeleteFunc: func(interface{}) { notify() },
+	}); err != nil {
+		cancel()
+
+		return nil, errors.Wrapf(err, "cannot watch test '%s'", id)
+	}
+
+	go informer.Run(ctx.Done())
+
+	syncCtx, syncCancel := c.withTimeout(ctx)
+	defer syncCancel()
+
+	if !cache.WaitForCacheSync(syncCtx.Done(), informer.HasSynced) {
+		cancel()
+
+		return nil, errors.Wrapf(syncCtx.Err(), "cannot list scenarios of test '%s'", id)
+	}
+
+	latest := func() *v1alpha1.Scenario {
+		var scenarios []v1alpha1.Scenario
+
+		for _, obj := range informer.GetStore().List() {
+			if scenario, ok := obj.(*v1alpha1.Scenario); ok {
+				scenarios = append(scenarios, *scenario)
+			}
+		}
+
+		if len(scenarios) == 0 {
+			return nil
+		}
+
+		return latestScenario(scenarios).DeepCopy()
+	}
+
+	if latest() == nil {
+		cancel()
+
+		return nil, errors.Wrapf(ErrTestNotFound, "test '%s'", id)
+	}
+
+	updates := make(chan *v1alpha1.Scenario)
+
+	go func() {
+		defer cancel()
+		defer close(updates)
+
+		// the version of the last sent status. The uid distinguishes the scenarios of a chain.
+		var sent string
+
+		for {
+			scenario := latest()
+			if scenario == nil {
+				return
+			}
+
+			if version := string(scenario.GetUID()) + "/" + scenario.GetResourceVersion(); version != sent {
+				select {
+				case updates <- scenario:
+					sent = version
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-changed:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return updates, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	frisbeeclient "github.com/carv-ics-forth/frisbee/pkg/client"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// receive returns the next update of the watch, or nil if the watch is closed.
+func receive(t *testing.T, updates <-chan *v1alpha1.Scenario) *v1alpha1.Scenario {
+	t.Helper()
+
+	select {
+	case scenario := <-updates:
+		return scenario
+	case <-time.After(5 * time.Second):
+		t.Fatal("no update within 5s")
+
+		return nil
+	}
+}
+
+func TestWatchTest(t *testing.T) {
+	scheme := runtime.NewScheme()
+
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	var scenario v1alpha1.Scenario
+
+	scenario.SetNamespace("test")
+	scenario.SetName("scenario")
+	scenario.Status.Phase = v1alpha1.PhasePending
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&scenario).Build()
+	c := frisbeeclient.NewTestManagementClient(cli)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if _, err := c.WatchTest(ctx, "missing"); !errors.Is(err, frisbeeclient.ErrTestNotFound) {
+		t.Fatalf("WatchTest() error = %v, want not found", err)
+	}
+
+	updates, err := c.WatchTest(ctx, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the current status.
+	if got := receive(t, updates); got == nil || got.Status.Phase != v1alpha1.PhasePending {
+		t.Fatalf("initial update = %v, want phase %s", got, v1alpha1.PhasePending)
+	}
+
+	// an update of the status.
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(&scenario), &scenario); err != nil {
+		t.Fatal(err)
+	}
+
+	scenario.Status.Phase = v1alpha1.PhaseRunning
+
+	if err := cli.Update(ctx, &scenario); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := receive(t, updates); got == nil || got.Status.Phase != v1alpha1.PhaseRunning {
+		t.Fatalf("update = %v, want phase %s", got, v1alpha1.PhaseRunning)
+	}
+
+	// the deletion of the test closes the watch.
+	if err := cli.Delete(ctx, &scenario); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := receive(t, updates); got != nil {
+		t.Fatalf("update after deletion = %v, want closed watch", got)
+	}
+}
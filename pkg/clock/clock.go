@@ -0,0 +1,66 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clock abstracts the time source of the controllers. In production, the controllers follow the wall clock.
+// Tests and the simulator inject a fake clock, so that schedules, timeouts, and durations can be advanced
+// deterministically.
+package clock
+
+import (
+	"time"
+
+	utilclock "k8s.io/utils/clock"
+)
+
+// Clock tells the time to the controllers.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// Since returns the time elapsed since t.
+	Since(t time.Time) time.Duration
+
+	// Until returns the duration until t.
+	Until(t time.Time) time.Duration
+}
+
+// RealClock follows the wall clock.
+type RealClock struct{}
+
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+func (RealClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+func (RealClock) Until(t time.Time) time.Duration {
+	return time.Until(t)
+}
+
+// FromPassive adapts a Kubernetes clock, such as the FakeClock of k8s.io/utils/clock/testing, to a Clock.
+func FromPassive(c utilclock.PassiveClock) Clock {
+	return passive{PassiveClock: c}
+}
+
+type passive struct {
+	utilclock.PassiveClock
+}
+
+func (p passive) Until(t time.Time) time.Duration {
+	return t.Sub(p.Now())
+}
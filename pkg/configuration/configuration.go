@@ -18,16 +18,22 @@ package configuration
 
 import (
 	"context"
+	"strings"
+	"sync"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/logging"
 	"github.com/go-logr/logr"
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // Configuration is the programmatic equivalent of charts/platform/configuration.
+// It is loaded from the FrisbeeConfig of the operator, or from the legacy ConfigMap if there is no FrisbeeConfig.
 type Configuration struct {
 	DeveloperMode bool `json:"developerMode"`
 
@@ -38,6 +44,38 @@ type Configuration struct {
 	IngressClassName string `json:"ingressClassName"`
 
 	ControllerName string `json:"controllerName"`
+
+	// Templates replace the system templates. They are not supported by the legacy ConfigMap.
+	Templates map[string]string `json:"templates,omitempty"`
+
+	// LogLevel is the level of the operator. If empty, the level is given by the flags of the operator.
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// DebugScenarios are the scenarios (<namespace>/<name>) that are logged in detail.
+	DebugScenarios []string `json:"debugScenarios,omitempty"`
+}
+
+// legacyConfiguration is the schema of the legacy ConfigMap.
+type legacyConfiguration struct {
+	DeveloperMode    bool
+	Namespace        string
+	DomainName       string
+	IngressClassName string
+	ControllerName   string
+}
+
+// FromSpec returns the configuration that is defined by a FrisbeeConfig.
+func FromSpec(spec v1alpha1.FrisbeeConfigSpec) Configuration {
+	return Configuration{
+		DeveloperMode:    spec.DeveloperMode,
+		Namespace:        spec.Namespace,
+		DomainName:       spec.DomainName,
+		IngressClassName: spec.IngressClassName,
+		ControllerName:   spec.ControllerName,
+		Templates:        spec.Templates,
+		LogLevel:         spec.LogLevel,
+		DebugScenarios:   spec.DebugScenarios,
+	}
 }
 
 func (c Configuration) Validate() error {
@@ -53,9 +91,42 @@ func (c Configuration) Validate() error {
 
 	case c.ControllerName == "":
 		return errors.Errorf("Configuration.ControllerName is empty")
-	default:
-		return nil
 	}
+
+	for name, override := range c.Templates {
+		if !isSystemTemplate(name) {
+			return errors.Errorf("Configuration.Templates: '%s' is not a system template", name)
+		}
+
+		if override == "" {
+			return errors.Errorf("Configuration.Templates: '%s' is replaced by an empty name", name)
+		}
+	}
+
+	if c.LogLevel != "" {
+		if _, err := logging.ParseLevel(c.LogLevel); err != nil {
+			return errors.Wrapf(err, "Configuration.LogLevel")
+		}
+	}
+
+	for _, key := range c.DebugScenarios {
+		if namespace, name, found := strings.Cut(key, "/"); !found || namespace == "" || name == "" {
+			return errors.Errorf("Configuration.DebugScenarios: '%s' is not in the <namespace>/<name> format", key)
+		}
+	}
+
+	return nil
+}
+
+// IsDebugScenario returns true if the scenario is logged in detail.
+func (c Configuration) IsDebugScenario(key client.ObjectKey) bool {
+	for _, debugged := range c.DebugScenarios {
+		if debugged == key.String() {
+			return true
+		}
+	}
+
+	return false
 }
 
 func namesOfItems(list corev1.ConfigMapList) []string {
@@ -68,8 +139,37 @@ func namesOfItems(list corev1.ConfigMapList) []string {
 	return names
 }
 
-// Get returns the system configuration.
+// Get returns the system configuration. The FrisbeeConfig takes precedence over the legacy ConfigMap.
 func Get(ctx context.Context, cli client.Client, logger logr.Logger) (Configuration, error) {
+	var config v1alpha1.FrisbeeConfig
+
+	err := cli.Get(ctx, client.ObjectKey{Name: v1alpha1.FrisbeeConfigName}, &config)
+
+	switch {
+	case err == nil:
+		sysConf := FromSpec(config.Spec)
+
+		logger.Info("LoadGlobalConf",
+			"config", v1alpha1.FrisbeeConfigName,
+			"parameters", sysConf,
+		)
+
+		if err := sysConf.Validate(); err != nil {
+			return Configuration{}, err
+		}
+
+		return sysConf, nil
+
+	case k8errors.IsNotFound(err), meta.IsNoMatchError(err):
+		return getFromConfigMap(ctx, cli, logger)
+
+	default:
+		return Configuration{}, errors.Wrapf(err, "cannot get '%s'", v1alpha1.FrisbeeConfigName)
+	}
+}
+
+// getFromConfigMap returns the system configuration from the legacy ConfigMap.
+func getFromConfigMap(ctx context.Context, cli client.Client, logger logr.Logger) (Configuration, error) {
 	// 1. Discovery the configuration across the various namespaces.
 	var list corev1.ConfigMapList
 
@@ -90,7 +190,7 @@ func Get(ctx context.Context, cli client.Client, logger logr.Logger) (Configurat
 
 	config := list.Items[0]
 
-	var sysConf Configuration
+	var legacy legacyConfiguration
 
 	// 2. Parse the configuration
 	decoderConfig := &mapstructure.DecoderConfig{
@@ -101,7 +201,7 @@ func Get(ctx context.Context, cli client.Client, logger logr.Logger) (Configurat
 		WeaklyTypedInput:     true,
 		Squash:               false,
 		Metadata:             nil,
-		Result:               &sysConf,
+		Result:               &legacy,
 		TagName:              "",
 		IgnoreUntaggedFields: false,
 		MatchName:            nil,
@@ -116,6 +216,14 @@ func Get(ctx context.Context, cli client.Client, logger logr.Logger) (Configurat
 		return Configuration{}, errors.Wrapf(err, "decoding error")
 	}
 
+	sysConf := Configuration{
+		DeveloperMode:    legacy.DeveloperMode,
+		Namespace:        legacy.Namespace,
+		DomainName:       legacy.DomainName,
+		IngressClassName: legacy.IngressClassName,
+		ControllerName:   legacy.ControllerName,
+	}
+
 	logger.Info("LoadGlobalConf",
 		"config", PlatformConfigurationName,
 		"parameters", sysConf,
@@ -128,8 +236,34 @@ func Get(ctx context.Context, cli client.Client, logger logr.Logger) (Configurat
 	return sysConf, nil
 }
 
+var (
+	globalLock sync.RWMutex
+
+	global Configuration
+)
+
+// SetGlobal replaces the configuration that is used by the controllers.
 func SetGlobal(conf Configuration) {
-	Global = conf
+	globalLock.Lock()
+	defer globalLock.Unlock()
+
+	global = conf
 }
 
-var Global Configuration
+// Global returns the configuration that is used by the controllers. Because the configuration may change at
+// runtime, callers should not retain it.
+func Global() Configuration {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+
+	return global
+}
+
+// TemplateRef returns the template that replaces the given system template, or the system template itself.
+func TemplateRef(name string) string {
+	if override := Global().Templates[name]; override != "" {
+		return override
+	}
+
+	return name
+}
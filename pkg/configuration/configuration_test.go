@@ -0,0 +1,165 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configuration_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/configuration"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func legacyConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      configuration.PlatformConfigurationName,
+			Namespace: "frisbee",
+			Labels:    map[string]string{v1alpha1.ResourceDiscoveryLabel: configuration.PlatformConfigurationName},
+		},
+		Data: map[string]string{
+			"DeveloperMode":    "false",
+			"Namespace":        "frisbee",
+			"DomainName":       "legacy.local",
+			"IngressClassName": "nginx",
+			"ControllerName":   "frisbee-operator",
+		},
+	}
+}
+
+func frisbeeConfig(templates map[string]string) *v1alpha1.FrisbeeConfig {
+	return &v1alpha1.FrisbeeConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.FrisbeeConfigName},
+		Spec: v1alpha1.FrisbeeConfigSpec{
+			Namespace:        "frisbee",
+			DomainName:       "config.local",
+			IngressClassName: "traefik",
+			ControllerName:   "frisbee-operator",
+			Templates:        templates,
+		},
+	}
+}
+
+func TestGet(t *testing.T) {
+	scheme := runtime.NewScheme()
+
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("cannot build scheme: %v", err)
+	}
+
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("cannot build scheme: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		objects    []client.Object
+		wantDomain string
+		wantErr    bool
+	}{
+		{
+			name:       "legacy",
+			objects:    []client.Object{legacyConfigMap()},
+			wantDomain: "legacy.local",
+		},
+		{
+			name:       "config-precedes-legacy",
+			objects:    []client.Object{legacyConfigMap(), frisbeeConfig(nil)},
+			wantDomain: "config.local",
+		},
+		{
+			name: "unknown-template",
+			objects: []client.Object{frisbeeConfig(map[string]string{
+				"frisbee.system.telemetry.unknown": "my.template",
+			})},
+			wantErr: true,
+		},
+		{
+			name: "invalid-log-level",
+			objects: []client.Object{func() client.Object {
+				config := frisbeeConfig(nil)
+				config.Spec.LogLevel = "verbose"
+
+				return config
+			}()},
+			wantErr: true,
+		},
+		{
+			name: "invalid-debug-scenario",
+			objects: []client.Object{func() client.Object {
+				config := frisbeeConfig(nil)
+				config.Spec.DebugScenarios = []string{"no-namespace"}
+
+				return config
+			}()},
+			wantErr: true,
+		},
+		{
+			name:    "missing",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build()
+
+			got, err := configuration.Get(context.Background(), cli, logr.Discard())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err == nil && got.DomainName != tt.wantDomain {
+				t.Errorf("Get() domain = %s, want %s", got.DomainName, tt.wantDomain)
+			}
+		})
+	}
+}
+
+func TestTemplateRef(t *testing.T) {
+	configuration.SetGlobal(configuration.Configuration{
+		Templates: map[string]string{configuration.PrometheusTemplate: "my.prometheus"},
+	})
+
+	defer configuration.SetGlobal(configuration.Configuration{})
+
+	if got := configuration.TemplateRef(configuration.PrometheusTemplate); got != "my.prometheus" {
+		t.Errorf("TemplateRef() = %s, want my.prometheus", got)
+	}
+
+	if got := configuration.TemplateRef(configuration.GrafanaTemplate); got != configuration.GrafanaTemplate {
+		t.Errorf("TemplateRef() = %s, want %s", got, configuration.GrafanaTemplate)
+	}
+}
+
+func TestIsDebugScenario(t *testing.T) {
+	conf := configuration.Configuration{DebugScenarios: []string{"default/stuck"}}
+
+	if !conf.IsDebugScenario(client.ObjectKey{Namespace: "default", Name: "stuck"}) {
+		t.Errorf("IsDebugScenario(default/stuck) = false, want true")
+	}
+
+	if conf.IsDebugScenario(client.ObjectKey{Namespace: "other", Name: "stuck"}) {
+		t.Errorf("IsDebugScenario(other/stuck) = true, want false")
+	}
+}
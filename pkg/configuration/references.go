@@ -20,11 +20,65 @@ package configuration
 // and Go code of the controller.
 const (
 	// PlatformConfigurationName points to a configmap that maintain information about the installation.
+	// It is superseded by the FrisbeeConfig (v1alpha1.FrisbeeConfigName), and it is used only if the latter is missing.
 	PlatformConfigurationName = "system.controller.configuration"
 
 	PrometheusTemplate = "frisbee.system.telemetry.prometheus"
 
 	GrafanaTemplate = "frisbee.system.telemetry.grafana"
 
+	IngestionTemplate = "frisbee.system.telemetry.influxdb"
+
+	TempoTemplate = "frisbee.system.telemetry.tempo"
+
+	JaegerTemplate = "frisbee.system.telemetry.jaeger"
+
 	DataviewerTemplate = "frisbee.system.telemetry.dataviewer"
+
+	SeedFilesTemplate = "frisbee.system.seed.files"
+
+	SeedPostgresTemplate = "frisbee.system.seed.postgres"
+
+	SeedMySQLTemplate = "frisbee.system.seed.mysql"
+
+	AssertPostgresTemplate = "frisbee.system.assert.postgres"
+
+	AssertMySQLTemplate = "frisbee.system.assert.mysql"
+
+	ConsistencyElleTemplate = "frisbee.system.consistency.elle"
+
+	ClockSkewTemplate = "frisbee.system.chaos.time.skew"
+
+	IOLatencyTemplate = "frisbee.system.chaos.io.latency"
+
+	IOErrnoTemplate = "frisbee.system.chaos.io.errno"
+
+	DiskFillTemplate = "frisbee.system.chaos.disk.fill"
+
+	RegistryOutageTemplate = "frisbee.system.chaos.registry.outage"
+
+	RegistryBlockerTemplate = "frisbee.system.chaos.registry.blocker"
+
+	APIServerLatencyTemplate = "frisbee.system.chaos.controlplane.latency"
+
+	ControlPlanePauseTemplate = "frisbee.system.chaos.controlplane.pause"
 )
+
+// systemTemplates are the templates that can be replaced through the configuration.
+var systemTemplates = []string{
+	PrometheusTemplate, GrafanaTemplate, IngestionTemplate, TempoTemplate, JaegerTemplate, DataviewerTemplate,
+	SeedFilesTemplate, SeedPostgresTemplate, SeedMySQLTemplate,
+	AssertPostgresTemplate, AssertMySQLTemplate, ConsistencyElleTemplate,
+	ClockSkewTemplate, IOLatencyTemplate, IOErrnoTemplate, DiskFillTemplate,
+	RegistryOutageTemplate, RegistryBlockerTemplate, APIServerLatencyTemplate, ControlPlanePauseTemplate,
+}
+
+func isSystemTemplate(name string) bool {
+	for _, template := range systemTemplates {
+		if name == template {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dashboard validates the Grafana dashboards that accompany the telemetry agents, so that malformed
+// dashboards are rejected before they are mounted into Grafana.
+package dashboard
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+)
+
+// Datasource is a datasource provisioned to the Grafana of every scenario.
+type Datasource struct {
+	Name string
+	Type string
+	UID  string
+}
+
+// Datasources must match the datasources provisioned in charts/system/templates/telemetry/grafana.
+var Datasources = []Datasource{
+	{Name: "Prometheus", Type: "prometheus", UID: "PBFA97CFB590B2093"},
+}
+
+// builtin are the special datasources of Grafana, referred either by name or by uid.
+var builtin = map[string]struct{}{
+	"grafana":         {},
+	"-- Grafana --":   {},
+	"-- Mixed --":     {},
+	"-- Dashboard --": {},
+}
+
+// MaxUIDLength is the longest dashboard uid accepted by Grafana.
+const MaxUIDLength = 40
+
+// variableRef matches the forms of variables: $var, ${var}, ${var:format}, and [[var]].
+var variableRef = regexp.MustCompile(`^\$(\w+)$|^\$\{([^}:]+)(:[^}]*)?\}$|^\[\[([^\]:]+)(:[^\]]*)?\]\]$`)
+
+type target struct {
+	RefID      string          `json:"refId"`
+	Datasource json.RawMessage `json:"datasource"`
+}
+
+type panel struct {
+	ID         int             `json:"id"`
+	Title      string          `json:"title"`
+	Type       string          `json:"type"`
+	Datasource json.RawMessage `json:"datasource"`
+	Targets    []target        `json:"targets"`
+	Panels     []panel         `json:"panels"`
+}
+
+type variable struct {
+	Name       string          `json:"name"`
+	Datasource json.RawMessage `json:"datasource"`
+}
+
+type annotation struct {
+	Name       string          `json:"name"`
+	Datasource json.RawMessage `json:"datasource"`
+}
+
+type document struct {
+	Title  *string `json:"title"`
+	UID    string  `json:"uid"`
+	Inputs []struct {
+		Name string `json:"name"`
+	} `json:"__inputs"`
+	Panels []panel `json:"panels"`
+	Rows   []struct {
+		Panels []panel `json:"panels"`
+	} `json:"rows"`
+	Templating struct {
+		List []variable `json:"list"`
+	} `json:"templating"`
+	Annotations struct {
+		List []annotation `json:"list"`
+	} `json:"annotations"`
+}
+
+// validator checks the dashboards against a set of known datasources.
+type validator struct {
+	datasources []Datasource
+
+	// anyDatasource accepts references to unknown datasources, which may be declared later by the scenario.
+	anyDatasource bool
+}
+
+// Validate checks that the body is a well-formed dashboard, and that its panels, queries, variables, and
+// annotations refer to the provisioned datasources, to the given extra datasources, or to variables
+// declared by the dashboard.
+func Validate(body []byte, extra ...Datasource) error {
+	v := validator{datasources: append(append([]Datasource(nil), Datasources...), extra...)}
+
+	return v.validate(body)
+}
+
+// ValidateFiles validates the dashboards of a telemetry ConfigMap. Grafana loads only the json files,
+// so the other files are ignored. The errors are reported with the name of the offending file.
+func ValidateFiles(files map[string]string, extra ...Datasource) error {
+	v := validator{datasources: append(append([]Datasource(nil), Datasources...), extra...)}
+
+	return v.validateFiles(files)
+}
+
+// ValidateSchema validates the dashboards of a telemetry ConfigMap, as ValidateFiles, except that references
+// to unknown datasources are accepted. It is used when the datasources of the scenario are not yet known.
+func ValidateSchema(files map[string]string) error {
+	v := validator{anyDatasource: true}
+
+	return v.validateFiles(files)
+}
+
+func (v *validator) validateFiles(files map[string]string) error {
+	var merr *multierror.Error
+
+	for name, body := range files {
+		if filepath.Ext(name) != ".json" {
+			continue
+		}
+
+		if err := v.validate([]byte(body)); err != nil {
+			merr = multierror.Append(merr, errors.Wrapf(err, "dashboard '%s'", name))
+		}
+	}
+
+	return merr.ErrorOrNil()
+}
+
+func (v *validator) validate(body []byte) error {
+	var d document
+
+	if err := json.Unmarshal(body, &d); err != nil {
+		return errors.Wrapf(err, "malformed json")
+	}
+
+	if d.Title == nil || strings.TrimSpace(*d.Title) == "" {
+		return errors.New("missing title")
+	}
+
+	if len(d.UID) > MaxUIDLength {
+		return errors.Errorf("uid '%s' exceeds %d characters", d.UID, MaxUIDLength)
+	}
+
+	declared := make(map[string]struct{}, len(d.Inputs)+len(d.Templating.List))
+
+	for _, input := range d.Inputs {
+		declared[input.Name] = struct{}{}
+	}
+
+	for _, variable := range d.Templating.List {
+		declared[variable.Name] = struct{}{}
+	}
+
+	var merr *multierror.Error
+
+	for _, variable := range d.Templating.List {
+		if err := v.checkDatasource(variable.Datasource, declared); err != nil {
+			merr = multierror.Append(merr, errors.Wrapf(err, "variable '%s'", variable.Name))
+		}
+	}
+
+	for _, a := range d.Annotations.List {
+		if err := v.checkDatasource(a.Datasource, declared); err != nil {
+			merr = multierror.Append(merr, errors.Wrapf(err, "annotation '%s'", a.Name))
+		}
+	}
+
+	panels := d.Panels
+	for _, row := range d.Rows {
+		panels = append(panels, row.Panels...)
+	}
+
+	for _, p := range panels {
+		if err := v.checkPanel(p, declared); err != nil {
+			merr = multierror.Append(merr, err)
+		}
+	}
+
+	return merr.ErrorOrNil()
+}
+
+func (v *validator) checkPanel(p panel, declared map[string]struct{}) error {
+	if p.Type == "" {
+		return errors.Errorf("panel '%s' (id: %d) has no type", p.Title, p.ID)
+	}
+
+	if err := v.checkDatasource(p.Datasource, declared); err != nil {
+		return errors.Wrapf(err, "panel '%s' (id: %d)", p.Title, p.ID)
+	}
+
+	for _, t := range p.Targets {
+		if err := v.checkDatasource(t.Datasource, declared); err != nil {
+			return errors.Wrapf(err, "panel '%s' (id: %d), query '%s'", p.Title, p.ID, t.RefID)
+		}
+	}
+
+	// collapsed rows carry their own panels.
+	for _, nested := range p.Panels {
+		if err := v.checkPanel(nested, declared); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkDatasource accepts the default datasource (null), references by name (legacy), and references by
+// type and uid.
+func (v *validator) checkDatasource(raw json.RawMessage, declared map[string]struct{}) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil {
+		return v.checkRef(name, func(ds Datasource) bool { return ds.Name == name }, declared)
+	}
+
+	var ref struct {
+		Type string `json:"type"`
+		UID  string `json:"uid"`
+	}
+
+	if err := json.Unmarshal(raw, &ref); err != nil {
+		return errors.Errorf("invalid datasource '%s'", raw)
+	}
+
+	// without uid, Grafana picks the first datasource of the given type.
+	if ref.UID == "" {
+		return v.checkRef(ref.Type, func(ds Datasource) bool { return ds.Type == ref.Type }, declared)
+	}
+
+	return v.checkRef(ref.UID, func(ds Datasource) bool {
+		return ds.UID == ref.UID && (ref.Type == "" || ds.Type == ref.Type)
+	}, declared)
+}
+
+func (v *validator) checkRef(ref string, match func(Datasource) bool, declared map[string]struct{}) error {
+	if ref == "" {
+		return nil
+	}
+
+	if _, ok := builtin[ref]; ok {
+		return nil
+	}
+
+	if groups := variableRef.FindStringSubmatch(ref); groups != nil {
+		name := groups[1] + groups[2] + groups[4]
+
+		if _, ok := declared[name]; !ok {
+			return errors.Errorf("datasource refers to undeclared variable '%s'", name)
+		}
+
+		return nil
+	}
+
+	if v.anyDatasource {
+		return nil
+	}
+
+	for _, ds := range v.datasources {
+		if match(ds) {
+			return nil
+		}
+	}
+
+	return errors.Errorf("unknown datasource '%s'", ref)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dashboard_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/pkg/dashboard"
+)
+
+// TestValidate_Shipped ensures that the dashboards shipped with the charts and the examples are accepted.
+func TestValidate_Shipped(t *testing.T) {
+	var files []string
+
+	for _, pattern := range []string{"../../charts/*/dashboards/*.json", "../../examples/apps/*/dashboards/*.json"} {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		files = append(files, matches...)
+	}
+
+	if len(files) == 0 {
+		t.Fatal("no dashboards found")
+	}
+
+	for _, file := range files {
+		body, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := dashboard.Validate(body); err != nil {
+			t.Errorf("dashboard '%s': %v", file, err)
+		}
+	}
+}
+
+func TestValidate_Extra(t *testing.T) {
+	body := []byte(`{"title": "t", "panels": [{"id": 1, "type": "graph", "datasource": {"type": "influxdb", "uid": "influx"}}]}`)
+
+	if err := dashboard.Validate(body); err == nil {
+		t.Error("expected error for unknown datasource")
+	}
+
+	if err := dashboard.Validate(body, dashboard.Datasource{Name: "InfluxDB", Type: "influxdb", UID: "influx"}); err != nil {
+		t.Errorf("unexpected error for extra datasource: %v", err)
+	}
+
+	if err := dashboard.ValidateSchema(map[string]string{"test.json": string(body)}); err != nil {
+		t.Errorf("unexpected schema error: %v", err)
+	}
+}
+
+func TestValidateFiles(t *testing.T) {
+	testcases := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{
+			name: "Valid",
+			body: `{"title": "ok", "panels": [{"id": 1, "type": "graph", "datasource": "Prometheus",
+				"targets": [{"refId": "A", "datasource": {"type": "prometheus", "uid": "PBFA97CFB590B2093"}}]}]}`,
+			wantErr: false,
+		},
+		{
+			name:    "Malformed",
+			body:    `{"title": "broken", "panels": [`,
+			wantErr: true,
+		},
+		{
+			name:    "NoTitle",
+			body:    `{"panels": []}`,
+			wantErr: true,
+		},
+		{
+			name:    "NoPanelType",
+			body:    `{"title": "t", "panels": [{"id": 1}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "UnknownDatasource",
+			body:    `{"title": "t", "panels": [{"id": 1, "type": "graph", "datasource": "InfluxDB"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "UnknownUID",
+			body:    `{"title": "t", "panels": [{"id": 1, "type": "graph", "datasource": {"type": "prometheus", "uid": "abc"}}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "UndeclaredVariable",
+			body:    `{"title": "t", "panels": [{"id": 1, "type": "graph", "datasource": "${DS_PROMETHEUS}"}]}`,
+			wantErr: true,
+		},
+		{
+			name: "DeclaredVariable",
+			body: `{"title": "t", "__inputs": [{"name": "DS_PROMETHEUS"}],
+				"panels": [{"id": 1, "type": "graph", "datasource": "${DS_PROMETHEUS}"}]}`,
+			wantErr: false,
+		},
+		{
+			name: "NestedPanel",
+			body: `{"title": "t", "panels": [{"id": 1, "type": "row",
+				"panels": [{"id": 2, "type": "graph", "datasource": "Loki"}]}]}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := dashboard.ValidateFiles(map[string]string{"test.json": tc.body, "README": "not a dashboard"})
+
+			if (err != nil) != tc.wantErr {
+				t.Errorf("ValidateFiles() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
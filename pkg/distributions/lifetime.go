@@ -0,0 +1,63 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package distributions
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// GenerateLifetimes draws the lifetimes of the given number of samples. To keep the experiments reproducible,
+// the lifetimes are given by evenly spaced quantiles of the distribution, and are shuffled with the given seed.
+func GenerateLifetimes(samples int, spec *v1alpha1.LifetimeSpec, seed int64) []time.Duration {
+	if samples < 1 {
+		return nil
+	}
+
+	var quantile func(p float64) float64
+
+	switch spec.Distribution {
+	case v1alpha1.LifetimeUniform:
+		var lb float64
+		if spec.Min != nil {
+			lb = float64(spec.Min.Duration)
+		}
+
+		quantile = distuv.Uniform{Min: lb, Max: float64(spec.Duration.Duration)}.Quantile
+
+	case v1alpha1.LifetimeExponential:
+		quantile = distuv.Exponential{Rate: 1 / float64(spec.Duration.Duration)}.Quantile
+
+	default:
+		quantile = func(float64) float64 { return float64(spec.Duration.Duration) }
+	}
+
+	lifetimes := make([]time.Duration, samples)
+
+	for i := range lifetimes {
+		lifetimes[i] = time.Duration(quantile((float64(i) + 0.5) / float64(samples)))
+	}
+
+	rand.New(rand.NewSource(seed)).Shuffle(samples, func(i, j int) { //nolint:gosec
+		lifetimes[i], lifetimes[j] = lifetimes[j], lifetimes[i]
+	})
+
+	return lifetimes
+}
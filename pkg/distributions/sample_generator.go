@@ -73,6 +73,31 @@ func GenerateProbabilitySliceFromSpec(samples int64, spec *v1alpha1.Distribution
 	}
 }
 
+// GenerateProbabilitySliceFromWeights assigns to every sample a probability proportional to its weight.
+// The sample i is weighted by weights[i mod len(weights)]. Without weights, all samples have the same probability.
+func GenerateProbabilitySliceFromWeights(samples int64, weights []float64) ProbabilitySlice {
+	if len(weights) == 0 {
+		weights = []float64{1}
+	}
+
+	dist := make(ProbabilitySlice, samples)
+	for i := range dist {
+		dist[i] = weights[i%len(weights)]
+	}
+
+	sum := dist.sum()
+	if sum == 0 {
+		panic("divide by zero factor")
+	}
+
+	// unlike divide(), the probabilities are not rounded, so that the sum never exceeds the total.
+	for i := range dist {
+		dist[i] /= sum
+	}
+
+	return dist
+}
+
 func genProbabilityDensitySlice(samples int64, distgenerator Generator) ProbabilitySlice {
 	// discard the first 0 value
 	distgenerator.Next()
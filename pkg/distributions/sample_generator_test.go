@@ -207,6 +207,60 @@ func Test_ResourceDistribution(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "even",
+			dist: distributions.GenerateProbabilitySliceFromWeights(Nodes, nil),
+			args: args{total: total},
+			want: []corev1.ResourceList{
+				{
+					corev1.ResourceCPU:    resource.MustParse("8"),
+					corev1.ResourceMemory: resource.MustParse("8G"),
+				},
+				{
+					corev1.ResourceCPU:    resource.MustParse("8"),
+					corev1.ResourceMemory: resource.MustParse("8G"),
+				},
+				{
+					corev1.ResourceCPU:    resource.MustParse("8"),
+					corev1.ResourceMemory: resource.MustParse("8G"),
+				},
+				{
+					corev1.ResourceCPU:    resource.MustParse("8"),
+					corev1.ResourceMemory: resource.MustParse("8G"),
+				},
+				{
+					corev1.ResourceCPU:    resource.MustParse("8"),
+					corev1.ResourceMemory: resource.MustParse("8G"),
+				},
+			},
+		},
+		{
+			name: "weights",
+			dist: distributions.GenerateProbabilitySliceFromWeights(Nodes, []float64{2, 1, 1, 0.5, 0.5}),
+			args: args{total: total},
+			want: []corev1.ResourceList{
+				{
+					corev1.ResourceCPU:    resource.MustParse("16"),
+					corev1.ResourceMemory: resource.MustParse("16G"),
+				},
+				{
+					corev1.ResourceCPU:    resource.MustParse("8"),
+					corev1.ResourceMemory: resource.MustParse("8G"),
+				},
+				{
+					corev1.ResourceCPU:    resource.MustParse("8"),
+					corev1.ResourceMemory: resource.MustParse("8G"),
+				},
+				{
+					corev1.ResourceCPU:    resource.MustParse("4"),
+					corev1.ResourceMemory: resource.MustParse("4G"),
+				},
+				{
+					corev1.ResourceCPU:    resource.MustParse("4"),
+					corev1.ResourceMemory: resource.MustParse("4G"),
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -314,3 +368,56 @@ func Test_TimelineDistribution(t *testing.T) {
 		})
 	}
 }
+
+func Test_Lifetimes(t *testing.T) {
+	tests := []struct {
+		name string
+		spec v1alpha1.LifetimeSpec
+		min  time.Duration
+		max  time.Duration
+		mean time.Duration
+	}{
+		{
+			name: "fixed",
+			spec: v1alpha1.LifetimeSpec{Distribution: v1alpha1.LifetimeFixed, Duration: metav1.Duration{Duration: time.Minute}},
+			min:  time.Minute,
+			max:  time.Minute,
+			mean: time.Minute,
+		},
+		{
+			name: "uniform",
+			spec: v1alpha1.LifetimeSpec{
+				Distribution: v1alpha1.LifetimeUniform,
+				Duration:     metav1.Duration{Duration: 2 * time.Minute},
+				Min:          &metav1.Duration{Duration: time.Minute},
+			},
+			min:  time.Minute,
+			max:  2 * time.Minute,
+			mean: 90 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lifetimes := distributions.GenerateLifetimes(10, &tt.spec, 1)
+
+			if !reflect.DeepEqual(lifetimes, distributions.GenerateLifetimes(10, &tt.spec, 1)) {
+				t.Errorf("lifetimes are not reproducible")
+			}
+
+			var sum time.Duration
+
+			for _, lifetime := range lifetimes {
+				if lifetime < tt.min || lifetime > tt.max {
+					t.Errorf("lifetime '%s' is out of [%s, %s]", lifetime, tt.min, tt.max)
+				}
+
+				sum += lifetime
+			}
+
+			if mean := sum / time.Duration(len(lifetimes)); mean != tt.mean {
+				t.Errorf("expected mean '%s' but got '%s'", tt.mean, mean)
+			}
+		})
+	}
+}
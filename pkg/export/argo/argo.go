@@ -0,0 +1,494 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argo
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+/*
+	The export translates every action into the Frisbee object that implements it, and leaves the execution of the
+	object to the controllers of Frisbee. Argo only replaces the scenario controller: it creates the objects in the
+	order of the dependencies, and waits for them.
+
+	Every action yields up to three tasks:
+	  - <action>-after:   suspends the action until the time offset since the beginning of the workflow.
+	  - <action>:         creates the object, and succeeds once the object is running.
+	  - <action>-success: waits for the object to succeed.
+
+	Running dependencies are thus mapped to the first task, and Success dependencies to the second one.
+	Deleted objects are not waited for.
+
+	The templates of Services and Chaos are resolved by the scenario controller, which is not involved here.
+	They are therefore translated into single-instance Clusters and Cascades, whose controllers resolve the templates.
+	Actions that are implemented within the scenario controller (e.g, Seed, Snapshot) cannot be exported.
+*/
+
+const (
+	afterSuffix   = "-after"
+	successSuffix = "-success"
+)
+
+var (
+	runningCondition = fmt.Sprintf("status.phase in (%s,%s)", v1alpha1.PhaseRunning, v1alpha1.PhaseSuccess)
+	successCondition = fmt.Sprintf("status.phase == %s", v1alpha1.PhaseSuccess)
+	failureCondition = fmt.Sprintf("status.phase == %s", v1alpha1.PhaseFailed)
+)
+
+// Options customize the generated workflow.
+type Options struct {
+	// ServiceAccountName is the account that creates the Frisbee objects. It must be allowed to manage them.
+	ServiceAccountName string
+}
+
+// Export translates the scenario into an equivalent Argo Workflow. The scenario is validated as it would be by the
+// admission controller, without the checks that require the cluster.
+func Export(scenario *v1alpha1.Scenario, options Options) (*Workflow, error) {
+	if err := checkSupported(scenario); err != nil {
+		return nil, err
+	}
+
+	actions := make(map[string]*v1alpha1.Action, len(scenario.Spec.Actions))
+	deleted := sets.New[string]()
+
+	for i, action := range scenario.Spec.Actions {
+		actions[action.Name] = &scenario.Spec.Actions[i]
+
+		if action.ActionType == v1alpha1.ActionDelete {
+			deleted.Insert(action.Delete.Jobs...)
+		}
+	}
+
+	var (
+		main      = DAGTemplate{}
+		templates []Template
+	)
+
+	for _, action := range scenario.Spec.Actions {
+		var dependencies []string
+
+		if deps := action.DependsOn; deps != nil {
+			dependencies = append(dependencies, deps.Running...)
+
+			for _, dep := range deps.Success {
+				// Delete actions have no object to wait for. They succeed once the deletion is requested.
+				if actions[dep].ActionType == v1alpha1.ActionDelete {
+					dependencies = append(dependencies, dep)
+				} else {
+					dependencies = append(dependencies, dep+successSuffix)
+				}
+			}
+
+			if after := deps.After; after != nil {
+				templates = append(templates, Template{
+					Name:    action.Name + afterSuffix,
+					Suspend: &SuspendTemplate{Duration: after.Duration.String()},
+				})
+
+				main.Tasks = append(main.Tasks, DAGTask{
+					Name:     action.Name + afterSuffix,
+					Template: action.Name + afterSuffix,
+				})
+
+				dependencies = append(dependencies, action.Name+afterSuffix)
+			}
+		}
+
+		actionTemplates, err := actionTemplates(action, actions)
+		if err != nil {
+			return nil, errors.Wrapf(err, "action '%s'", action.Name)
+		}
+
+		templates = append(templates, actionTemplates...)
+
+		main.Tasks = append(main.Tasks, DAGTask{
+			Name:         action.Name,
+			Template:     action.Name,
+			Dependencies: dependencies,
+		})
+	}
+
+	// Add the waits for completion. Those that are referenced by other actions are always added.
+	// The rest are added so that the workflow completes after all the actions, like the scenario does.
+	for _, action := range scenario.Spec.Actions {
+		if action.ActionType == v1alpha1.ActionDelete {
+			continue
+		}
+
+		if deleted.Has(action.Name) && !isSuccessDependency(scenario, action.Name) {
+			continue
+		}
+
+		kind, err := exportedKind(action)
+		if err != nil {
+			return nil, err
+		}
+
+		manifest, err := referenceManifest(kind, action.Name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "action '%s'", action.Name)
+		}
+
+		templates = append(templates, Template{
+			Name: action.Name + successSuffix,
+			Resource: &ResourceTemplate{
+				Action:           "get",
+				Manifest:         manifest,
+				SuccessCondition: successCondition,
+				FailureCondition: failureCondition,
+			},
+		})
+
+		main.Tasks = append(main.Tasks, DAGTask{
+			Name:         action.Name + successSuffix,
+			Template:     action.Name + successSuffix,
+			Dependencies: []string{action.Name},
+		})
+	}
+
+	// The suffixes may clash with the names of the actions.
+	names := sets.New[string]()
+
+	for _, task := range main.Tasks {
+		if names.Has(task.Name) {
+			return nil, errors.Errorf("task '%s' is defined twice. Rename the action", task.Name)
+		}
+
+		names.Insert(task.Name)
+	}
+
+	var workflow Workflow
+
+	workflow.APIVersion = APIVersion
+	workflow.Kind = Kind
+	workflow.SetName(scenario.GetName())
+	workflow.SetNamespace(scenario.GetNamespace())
+
+	workflow.Spec.Entrypoint = scenario.GetName()
+	workflow.Spec.ServiceAccountName = options.ServiceAccountName
+	workflow.Spec.Templates = append([]Template{{Name: scenario.GetName(), DAG: &main}}, templates...)
+
+	return &workflow, nil
+}
+
+// checkSupported rejects the features that depend on the scenario controller.
+func checkSupported(scenario *v1alpha1.Scenario) error {
+	// Besides the actions, the fields of the spec (e.g, vars, teardown, telemetry) are handled by the scenario
+	// controller. They have no counterpart in the workflow.
+	rest := scenario.Spec.DeepCopy()
+	rest.Actions = nil
+
+	if !reflect.DeepEqual(*rest, v1alpha1.ScenarioSpec{}) {
+		return errors.New("only the actions of the scenario can be exported")
+	}
+
+	// validate a defaulted copy, as the admission controller does.
+	defaulted := scenario.DeepCopy()
+	defaulted.Default()
+
+	references, err := v1alpha1.BuildDependencyGraph(defaulted)
+	if err != nil {
+		return errors.Wrapf(err, "invalid scenario")
+	}
+
+	for i := range defaulted.Spec.Actions {
+		if err := v1alpha1.CheckAction(&defaulted.Spec.Actions[i], references); err != nil {
+			return errors.Wrapf(err, "action '%s' error", defaulted.Spec.Actions[i].Name)
+		}
+	}
+
+	for i := range scenario.Spec.Actions {
+		action := &scenario.Spec.Actions[i]
+
+		if action.Assert != nil {
+			return errors.Errorf("action '%s': assertions are not supported", action.Name)
+		}
+
+		if action.Timeout != nil {
+			return errors.Errorf("action '%s': timeouts are not supported", action.Name)
+		}
+
+		var inputs []v1alpha1.UserInputs
+
+		switch action.ActionType {
+		case v1alpha1.ActionService:
+			inputs = action.Service.Inputs
+		case v1alpha1.ActionCluster:
+			inputs = action.Cluster.Inputs
+
+			for _, variant := range action.Cluster.Variants {
+				inputs = append(inputs, variant.Inputs...)
+			}
+		case v1alpha1.ActionChaos:
+			inputs = action.Chaos.Inputs
+		case v1alpha1.ActionCascade:
+			inputs = action.Cascade.Inputs
+		case v1alpha1.ActionCall, v1alpha1.ActionRawChaos, v1alpha1.ActionDelete:
+		default:
+			return errors.Errorf("action '%s': %s actions are not supported", action.Name, action.ActionType)
+		}
+
+		// macros in the inputs are expanded by the scenario controller.
+		if hasMacros(inputs) {
+			return errors.Errorf("action '%s': macros in the inputs are not supported", action.Name)
+		}
+	}
+
+	return nil
+}
+
+func hasMacros(inputs []v1alpha1.UserInputs) bool {
+	for _, input := range inputs {
+		for _, value := range input {
+			if value == nil {
+				continue
+			}
+
+			var str string
+
+			if err := json.Unmarshal(value.Raw, &str); err == nil && strings.HasPrefix(str, ".") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isSuccessDependency(scenario *v1alpha1.Scenario, name string) bool {
+	for _, action := range scenario.Spec.Actions {
+		if action.DependsOn == nil {
+			continue
+		}
+
+		for _, dep := range action.DependsOn.Success {
+			if dep == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// exportedKind returns the kind of the object that implements the action.
+func exportedKind(action v1alpha1.Action) (string, error) {
+	switch action.ActionType {
+	case v1alpha1.ActionService, v1alpha1.ActionCluster:
+		return "Cluster", nil
+	case v1alpha1.ActionChaos, v1alpha1.ActionCascade:
+		return "Cascade", nil
+	case v1alpha1.ActionCall:
+		return "Call", nil
+	case v1alpha1.ActionRawChaos:
+		return "Chaos", nil
+	default:
+		return "", errors.Errorf("action '%s' of type %s has no object", action.Name, action.ActionType)
+	}
+}
+
+// actionTemplates returns the template that runs the action, followed by its helper templates.
+func actionTemplates(action v1alpha1.Action, actions map[string]*v1alpha1.Action) ([]Template, error) {
+	if action.ActionType == v1alpha1.ActionDelete {
+		return deleteTemplates(action, actions)
+	}
+
+	manifest, err := toManifest(actionJob(action))
+	if err != nil {
+		return nil, err
+	}
+
+	return []Template{{
+		Name: action.Name,
+		Resource: &ResourceTemplate{
+			Action:            "create",
+			SetOwnerReference: true,
+			Manifest:          manifest,
+			SuccessCondition:  runningCondition,
+			FailureCondition:  failureCondition,
+		},
+	}}, nil
+}
+
+// actionJob returns the object that implements the action. Unlike the scenario controller, the object is not labeled
+// with the scenario, as there is no Scenario object for the controllers to look up.
+func actionJob(action v1alpha1.Action) client.Object {
+	var objMeta metav1.ObjectMeta
+
+	objMeta.SetName(action.Name)
+
+	v1alpha1.SetActionLabel(&objMeta, action.Name)
+	v1alpha1.SetComponentLabel(&objMeta, v1alpha1.ComponentSUT)
+
+	switch action.ActionType {
+	case v1alpha1.ActionService:
+		var job v1alpha1.Cluster
+
+		job.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("Cluster"))
+		job.ObjectMeta = objMeta
+
+		action.Service.DeepCopyInto(&job.Spec.GenerateObjectFromTemplate)
+		job.Spec.MaxInstances = 1
+
+		// the group service resolves the name of the action to the single instance, as it does for a Service.
+		job.Spec.GroupService = true
+
+		return &job
+
+	case v1alpha1.ActionCluster:
+		var job v1alpha1.Cluster
+
+		job.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("Cluster"))
+		job.ObjectMeta = objMeta
+
+		action.Cluster.DeepCopyInto(&job.Spec)
+
+		return &job
+
+	case v1alpha1.ActionChaos:
+		var job v1alpha1.Cascade
+
+		job.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("Cascade"))
+		job.ObjectMeta = objMeta
+
+		action.Chaos.DeepCopyInto(&job.Spec.GenerateObjectFromTemplate)
+		job.Spec.MaxInstances = 1
+
+		return &job
+
+	case v1alpha1.ActionCascade:
+		var job v1alpha1.Cascade
+
+		job.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("Cascade"))
+		job.ObjectMeta = objMeta
+
+		action.Cascade.DeepCopyInto(&job.Spec)
+
+		return &job
+
+	case v1alpha1.ActionCall:
+		var job v1alpha1.Call
+
+		job.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("Call"))
+		job.ObjectMeta = objMeta
+
+		action.Call.DeepCopyInto(&job.Spec)
+
+		return &job
+
+	case v1alpha1.ActionRawChaos:
+		var job v1alpha1.Chaos
+
+		job.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("Chaos"))
+		job.ObjectMeta = objMeta
+
+		action.RawChaos.DeepCopyInto(&job.Spec)
+
+		return &job
+
+	default:
+		panic("should never happen")
+	}
+}
+
+// deleteTemplates returns a DAG that deletes the jobs of the action in parallel.
+func deleteTemplates(action v1alpha1.Action, actions map[string]*v1alpha1.Action) ([]Template, error) {
+	dag := DAGTemplate{}
+	templates := []Template{{Name: action.Name, DAG: &dag}}
+
+	for _, target := range action.Delete.Jobs {
+		ref, exists := actions[target]
+		if !exists {
+			return nil, errors.Errorf("cannot delete unknown action '%s'", target)
+		}
+
+		kind, err := exportedKind(*ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot delete action '%s'", target)
+		}
+
+		manifest, err := referenceManifest(kind, target)
+		if err != nil {
+			return nil, err
+		}
+
+		name := action.Name + "-" + target
+
+		templates = append(templates, Template{
+			Name: name,
+			Resource: &ResourceTemplate{
+				Action:   "delete",
+				Manifest: manifest,
+				Flags:    []string{"--ignore-not-found"},
+			},
+		})
+
+		dag.Tasks = append(dag.Tasks, DAGTask{Name: target, Template: name})
+	}
+
+	return templates, nil
+}
+
+// referenceManifest returns the manifest of an object that is referenced by name.
+func referenceManifest(kind string, name string) (string, error) {
+	var obj unstructured.Unstructured
+
+	obj.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind(kind))
+	obj.SetName(name)
+
+	return toManifest(&obj)
+}
+
+// Marshal returns the yaml of the workflow.
+func Marshal(workflow *Workflow) ([]byte, error) {
+	out, err := toManifest(workflow)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(out), nil
+}
+
+// toManifest returns the yaml of the object, without the fields that are set by the API server.
+func toManifest(obj interface{}) (string, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot convert object")
+	}
+
+	delete(content, "status")
+	unstructured.RemoveNestedField(content, "metadata", "creationTimestamp")
+
+	out, err := yaml.Marshal(content)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot marshal object")
+	}
+
+	return string(out), nil
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argo_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/export/argo"
+	"sigs.k8s.io/yaml"
+)
+
+const clientServer = `
+apiVersion: frisbee.dev/v1alpha1
+kind: Scenario
+metadata:
+  name: client-server
+spec:
+  actions:
+    - action: Service
+      name: server
+      service:
+        templateRef: iperf.server
+
+    - action: Cluster
+      name: clients
+      depends: { running: [ server ], after: "30s" }
+      cluster:
+        templateRef: iperf.client
+        instances: 2
+
+    - action: Delete
+      name: teardown
+      depends: { success: [ clients ] }
+      delete:
+        jobs: [ server ]
+`
+
+func loadScenario(t *testing.T, manifest string) *v1alpha1.Scenario {
+	t.Helper()
+
+	var scenario v1alpha1.Scenario
+
+	if err := yaml.UnmarshalStrict([]byte(manifest), &scenario); err != nil {
+		t.Fatalf("cannot parse scenario: %v", err)
+	}
+
+	return &scenario
+}
+
+func TestExport(t *testing.T) {
+	workflow, err := argo.Export(loadScenario(t, clientServer), argo.Options{})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	if workflow.Spec.Entrypoint != "client-server" || workflow.Spec.Templates[0].DAG == nil {
+		t.Fatalf("Export() entrypoint = %s, want the DAG of the scenario", workflow.Spec.Entrypoint)
+	}
+
+	got := make(map[string][]string)
+
+	for _, task := range workflow.Spec.Templates[0].DAG.Tasks {
+		got[task.Name] = task.Dependencies
+	}
+
+	want := map[string][]string{
+		"server":          nil,
+		"clients-after":   nil,
+		"clients":         {"server", "clients-after"},
+		"clients-success": {"clients"},
+		"teardown":        {"clients-success"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Export() tasks = %v, want %v", got, want)
+	}
+
+	templates := make(map[string]argo.Template)
+
+	for _, template := range workflow.Spec.Templates {
+		templates[template.Name] = template
+	}
+
+	// the service is created as a single-instance cluster.
+	server := templates["server"].Resource
+	if server == nil || !strings.Contains(server.Manifest, "kind: Cluster") ||
+		!strings.Contains(server.Manifest, "instances: 1") {
+		t.Errorf("Export() server = %v, want a single-instance Cluster", server)
+	}
+
+	if got := templates["clients-after"].Suspend; got == nil || got.Duration != "30s" {
+		t.Errorf("Export() clients-after = %v, want a suspension of 30s", got)
+	}
+
+	if got := templates["teardown-server"].Resource; got == nil || got.Action != "delete" {
+		t.Errorf("Export() teardown-server = %v, want the deletion of the server", got)
+	}
+}
+
+func TestExport_Unsupported(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+	}{
+		{
+			name: "vars",
+			manifest: `
+metadata:
+  name: vars
+spec:
+  vars: { size: "10" }
+  actions:
+    - action: Cluster
+      name: clients
+      cluster: { templateRef: iperf.client }
+`,
+		},
+		{
+			name: "macros",
+			manifest: `
+metadata:
+  name: macros
+spec:
+  actions:
+    - action: Cluster
+      name: clients
+      cluster:
+        templateRef: iperf.client
+        inputs:
+          - { server: .cluster.servers.one }
+`,
+		},
+		{
+			name: "seed",
+			manifest: `
+metadata:
+  name: seed
+spec:
+  actions:
+    - action: Seed
+      name: seed
+      seed: { files: { size: 1Gi }, parallelism: 1 }
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := argo.Export(loadScenario(t, tt.manifest), argo.Options{}); err == nil {
+				t.Errorf("Export() error = nil, want error")
+			}
+		})
+	}
+}
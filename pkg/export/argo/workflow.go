@@ -0,0 +1,102 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argo
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+/*
+	The types below are the subset of the Argo Workflow that the export uses. The types of Argo are not imported,
+	as to not bind Frisbee to a specific version of Argo. The fields are stable across all v3 releases of Argo.
+*/
+
+const (
+	// APIVersion is the API version of the Argo Workflows.
+	APIVersion = "argoproj.io/v1alpha1"
+
+	// Kind is the kind of the Argo Workflows.
+	Kind = "Workflow"
+)
+
+// Workflow is an Argo Workflow.
+type Workflow struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec WorkflowSpec `json:"spec"`
+}
+
+// WorkflowSpec is the specification of an Argo Workflow.
+type WorkflowSpec struct {
+	// Entrypoint is the template that is invoked when the workflow starts.
+	Entrypoint string `json:"entrypoint"`
+
+	// ServiceAccountName is the account under which the resources are created.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Templates are the templates of the workflow.
+	Templates []Template `json:"templates"`
+}
+
+// Template is a unit of work. Exactly one of its fields, besides the name, is set.
+type Template struct {
+	Name string `json:"name"`
+
+	DAG *DAGTemplate `json:"dag,omitempty"`
+
+	Resource *ResourceTemplate `json:"resource,omitempty"`
+
+	Suspend *SuspendTemplate `json:"suspend,omitempty"`
+}
+
+// DAGTemplate runs its tasks as soon as their dependencies are succeeded.
+type DAGTemplate struct {
+	Tasks []DAGTask `json:"tasks"`
+}
+
+// DAGTask is a node of a DAGTemplate.
+type DAGTask struct {
+	Name string `json:"name"`
+
+	Template string `json:"template"`
+
+	Dependencies []string `json:"dependencies,omitempty"`
+}
+
+// ResourceTemplate manipulates a Kubernetes resource, and optionally waits for a condition on it.
+type ResourceTemplate struct {
+	// Action is one of create, get, apply, delete, replace, patch.
+	Action string `json:"action"`
+
+	// SetOwnerReference binds the resource to the workflow, so that it is removed along with the workflow.
+	SetOwnerReference bool `json:"setOwnerReference,omitempty"`
+
+	Manifest string `json:"manifest"`
+
+	// SuccessCondition and FailureCondition are label selectors on the fields of the resource.
+	SuccessCondition string `json:"successCondition,omitempty"`
+
+	FailureCondition string `json:"failureCondition,omitempty"`
+
+	Flags []string `json:"flags,omitempty"`
+}
+
+// SuspendTemplate pauses the execution for the given duration.
+type SuspendTemplate struct {
+	Duration string `json:"duration,omitempty"`
+}
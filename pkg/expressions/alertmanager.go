@@ -0,0 +1,125 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expressions
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+/*
+	Alertmanager expressions refer to alerts whose rules are managed outside Frisbee. The controller polls the
+	Alertmanager that is set by ALERTMANAGER_URL on the operator, and treats firing alerts as violated conditions.
+	Silenced and inhibited alerts are ignored.
+*/
+
+const (
+	// AlertmanagerURLEnv points to the Alertmanager used for evaluating Alertmanager expressions.
+	AlertmanagerURLEnv = "ALERTMANAGER_URL"
+
+	// AlertmanagerPollInterval is the interval between two consecutive queries to the Alertmanager.
+	AlertmanagerPollInterval = 15 * time.Second
+
+	// AlertmanagerTimeout bounds the time spent for a single query to the Alertmanager.
+	AlertmanagerTimeout = 5 * time.Second
+)
+
+// IsAlertmanagerAlertFiring queries the Alertmanager for active alerts that match the expression.
+// It returns the number of firing alerts.
+func IsAlertmanagerAlertFiring(ctx context.Context, expr v1alpha1.ExprAlertmanager) (int, error) {
+	alertname, matchers, err := expr.Parse()
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid alertmanager expression")
+	}
+
+	addr := os.Getenv(AlertmanagerURLEnv)
+	if addr == "" {
+		return 0, errors.Errorf("alertmanager is not configured. Set %s on the operator", AlertmanagerURLEnv)
+	}
+
+	query := url.Values{}
+	query.Set("active", "true")
+	query.Set("silenced", "false")
+	query.Set("inhibited", "false")
+	query.Add("filter", fmt.Sprintf("alertname=%q", alertname))
+
+	for _, matcher := range matchers {
+		query.Add("filter", matcher)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, AlertmanagerTimeout)
+	defer cancel()
+
+	endpoint := strings.TrimSuffix(addr, "/") + "/api/v2/alerts?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, errors.Wrapf(err, "cannot create request")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, errors.Wrapf(err, "request to '%s' failed", req.URL.Host)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, errors.Wrapf(err, "cannot read response")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, errors.Errorf("unexpected status '%s' from '%s'", resp.Status, req.URL.Host)
+	}
+
+	var alerts []struct {
+		Status struct {
+			State string `json:"state"`
+		} `json:"status"`
+	}
+
+	if err := json.Unmarshal(body, &alerts); err != nil {
+		return 0, errors.Wrapf(err, "cannot decode alertmanager response")
+	}
+
+	firing := 0
+
+	for _, alert := range alerts {
+		if alert.Status.State == "active" {
+			firing++
+		}
+	}
+
+	return firing, nil
+}
+
+// NeedsPolling returns true if the evaluation of the expression depends on an external source that does not
+// notify the controller, and therefore the controller must periodically reevaluate the expression.
+func NeedsPolling(expr *v1alpha1.ConditionalExpr) bool {
+	return expr.HasAlertmanagerExpr()
+}
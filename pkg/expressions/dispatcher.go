@@ -0,0 +1,166 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expressions
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	notifier "github.com/golanghelper/grafana-webhook"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// AlertDispatchQPS is the sustained rate of alerts that the dispatcher accepts, across all scenarios.
+	AlertDispatchQPS = 10
+
+	// AlertDispatchBurst is the number of alerts that the dispatcher accepts above the sustained rate.
+	AlertDispatchBurst = 50
+
+	// AlertHistoryLimit is the number of alerts kept in the history of a scenario.
+	AlertHistoryLimit = 20
+)
+
+// ErrAlertRateLimited indicates that an alert is rejected, because the dispatcher has exceeded its rate.
+var ErrAlertRateLimited = errors.New("alert rate limit exceeded")
+
+// alertKey identifies an alert rule.
+type alertKey struct {
+	ruleID   int
+	ruleName string
+}
+
+// AlertDispatcher filters the Grafana notifications before passing them to DispatchAlert.
+// Notifications that repeat the last dispatched state of an alert are dropped, since flapping or re-sent alerts
+// would otherwise trigger needless patches and expression evaluations. Notifications above the rate are rejected.
+type AlertDispatcher struct {
+	reconciler common.Reconciler
+
+	limiter flowcontrol.RateLimiter
+
+	lock sync.Mutex
+
+	// states holds the last dispatched state of every alert.
+	states map[alertKey]notifier.State
+}
+
+// NewAlertDispatcher returns a dispatcher that patches the objects using the given reconciler.
+func NewAlertDispatcher(r common.Reconciler) *AlertDispatcher {
+	return &AlertDispatcher{
+		reconciler: r,
+		limiter:    flowcontrol.NewTokenBucketRateLimiter(AlertDispatchQPS, AlertDispatchBurst),
+		states:     make(map[alertKey]notifier.State),
+	}
+}
+
+// Dispatch deduplicates and rate-limits the alert, and dispatches it to the target object.
+func (d *AlertDispatcher) Dispatch(ctx context.Context, alertBody *notifier.Body) error {
+	if alertBody == nil {
+		return errors.Errorf("notifier body cannot be empty")
+	}
+
+	key := alertKey{ruleID: alertBody.RuleID, ruleName: alertBody.RuleName}
+
+	d.lock.Lock()
+	last, seen := d.states[key]
+	d.lock.Unlock()
+
+	if seen && last == alertBody.State {
+		d.reconciler.Info("Ignore duplicate alert", "alertName", alertBody.RuleName, "state", alertBody.State)
+
+		return nil
+	}
+
+	if !d.limiter.TryAccept() {
+		return ErrAlertRateLimited
+	}
+
+	if err := DispatchAlert(ctx, d.reconciler, alertBody); err != nil {
+		return err
+	}
+
+	d.lock.Lock()
+	d.states[key] = alertBody.State
+	d.lock.Unlock()
+
+	return nil
+}
+
+// HandleWebhook returns the HTTP handler of the Grafana notifications.
+func (d *AlertDispatcher) HandleWebhook(ctx context.Context) http.Handler {
+	return notifier.HandleWebhook(func(w http.ResponseWriter, b *notifier.Body) {
+		if err := d.Dispatch(ctx, b); err != nil {
+			d.reconciler.Error(err, "Drop alert", "body", b)
+
+			if errors.Is(err, ErrAlertRateLimited) {
+				w.WriteHeader(http.StatusTooManyRequests)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}
+	}, 0)
+}
+
+// recordAlert appends the alert to the history of the scenario that the target object belongs to.
+func recordAlert(ctx context.Context, r common.Reconciler, target *unstructured.Unstructured, alertBody *notifier.Body) error {
+	var scenarioName string
+
+	switch {
+	case target.GetKind() == "Scenario":
+		scenarioName = target.GetName()
+	case v1alpha1.HasScenarioLabel(target):
+		scenarioName = v1alpha1.GetScenarioLabel(target)
+	default:
+		// the object does not belong to a scenario.
+		return nil
+	}
+
+	record := v1alpha1.AlertRecord{
+		Name:   alertBody.RuleName,
+		State:  string(alertBody.State),
+		Target: target.GetKind() + "/" + target.GetName(),
+		Time:   metav1.NewTime(r.Now()),
+	}
+
+	key := client.ObjectKey{Namespace: v1alpha1.GetScenarioNamespace(target), Name: scenarioName}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var scenario v1alpha1.Scenario
+
+		if err := r.GetClient().Get(ctx, key, &scenario); err != nil {
+			return err
+		}
+
+		scenario.Status.Alerts = append(scenario.Status.Alerts, record)
+
+		if overflow := len(scenario.Status.Alerts) - AlertHistoryLimit; overflow > 0 {
+			scenario.Status.Alerts = scenario.Status.Alerts[overflow:]
+		}
+
+		return r.GetClient().Status().Update(ctx, &scenario)
+	})
+
+	return errors.Wrapf(client.IgnoreNotFound(err), "cannot record alert to scenario '%s'", key)
+}
@@ -17,21 +17,33 @@ limitations under the License.
 package expressions
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/grafana"
 	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	notifier "github.com/golanghelper/grafana-webhook"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type Condition struct {
 	Expr *v1alpha1.ConditionalExpr
 	Info string
+
+	// Values are the values observed during the last evaluation of the expression.
+	Values map[string]string
 }
 
-func (c Condition) IsTrue(state lifecycle.ClassifierReader, job metav1.Object) bool {
+func (c *Condition) IsTrue(state lifecycle.ClassifierReader, job metav1.Object) bool {
 	// Check for state expressions
 	if c.Expr.HasStateExpr() {
+		c.Values = stateValues(state)
+
 		pass, err := c.Expr.State.GoValuate(state)
 		if err != nil {
 			c.Info = fmt.Sprintf("Err: '%s'. DebugInfo: '%s'", err, state.ListAll())
@@ -49,14 +61,92 @@ func (c Condition) IsTrue(state lifecycle.ClassifierReader, job metav1.Object) b
 
 		c.Info = fmt.Sprintf("Alert '%s' is %s", c.Expr.Metrics, info)
 
+		if fired {
+			c.Values = metricsValues(c.Expr.Metrics, info)
+		}
+
 		// non-fired mean that the condition is still true.
 		// fired means that the condition is violated, and should return false
 		return !fired
 	}
 
+	if c.Expr.HasAlertmanagerExpr() {
+		firing, err := IsAlertmanagerAlertFiring(context.Background(), c.Expr.Alertmanager)
+		if err != nil {
+			c.Info = fmt.Sprintf("Err: '%s'", err)
+
+			return false
+		}
+
+		c.Info = fmt.Sprintf("Alertmanager alert '%s' has %d firing instances", c.Expr.Alertmanager, firing)
+		c.Values = map[string]string{"firing": fmt.Sprint(firing)}
+
+		// as with Grafana alerts, firing means that the condition is violated.
+		return firing == 0
+	}
+
 	return false
 }
 
 func (c Condition) GetInfo() string {
 	return c.Info
 }
+
+// GetValues returns a printable form of the observed values, sorted by key.
+func (c Condition) GetValues() string {
+	keys := make([]string, 0, len(c.Values))
+	for key := range c.Values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = fmt.Sprintf("%s=%s", key, c.Values[key])
+	}
+
+	return strings.Join(pairs, ", ")
+}
+
+// stateValues lists the names of the classified jobs, per phase.
+func stateValues(state lifecycle.ClassifierReader) map[string]string {
+	names := func(jobs []client.Object) string {
+		list := make([]string, len(jobs))
+		for i, job := range jobs {
+			list[i] = job.GetName()
+		}
+
+		sort.Strings(list)
+
+		return "[" + strings.Join(list, " ") + "]"
+	}
+
+	return map[string]string{
+		"pending": names(state.GetPendingJobs()),
+		"running": names(state.GetRunningJobs()),
+		"success": names(state.GetSuccessfulJobs()),
+		"failed":  names(state.GetFailedJobs()),
+	}
+}
+
+// metricsValues extracts the evaluated metric values from the details of a fired alert, along with the evaluator
+// and the threshold of the expression. Values that cannot be parsed are omitted.
+func metricsValues(expr v1alpha1.ExprMetrics, details string) map[string]string {
+	values := make(map[string]string)
+
+	if rule, err := grafana.ParseAlertExpr(expr); err == nil {
+		values["metric"] = rule.MetricName
+		values["evaluator"] = rule.Evaluator.Type
+		values["threshold"] = fmt.Sprint(rule.Evaluator.Params)
+	}
+
+	var body notifier.Body
+	if err := json.Unmarshal([]byte(details), &body); err == nil {
+		for _, match := range body.EvalMatches {
+			values[fmt.Sprintf("value{%v}", match["metric"])] = fmt.Sprint(match["value"])
+		}
+	}
+
+	return values
+}
@@ -146,7 +146,7 @@ func DispatchAlert(ctx context.Context, r common.Reconciler, alertBody *notifier
 			alertName:      alertBody.RuleName,
 			alertState:     string(alertBody.State),
 			alertDetails:   string(alertJSON),
-			alertTimestamp: time.Now().Format(time.RFC3339),
+			alertTimestamp: r.Now().Format(time.RFC3339),
 		}
 	default:
 		return errors.Errorf("state '%s' is not handled. Only [OK, Alerting] are supported", alertBody.State)
@@ -178,7 +178,11 @@ func DispatchAlert(ctx context.Context, r common.Reconciler, alertBody *notifier
 	obj.SetNamespace(targetEndpoint.Namespace)
 	obj.SetName(targetEndpoint.Name)
 
-	return r.GetClient().Patch(ctx, &obj, patch)
+	if err := r.GetClient().Patch(ctx, &obj, patch); err != nil {
+		return errors.Wrapf(err, "cannot patch '%s'", alertBody.RuleName)
+	}
+
+	return recordAlert(ctx, r, &obj, alertBody)
 }
 
 const notifyChannelError = "SOMETHING IS WRONG WITH THE ALERTING MECHANISMS"
@@ -0,0 +1,182 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expressions
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+/*
+	Conditional expressions are used with the following semantics, regardless of the resource that defines them.
+
+	Until (spec.suspendWhen on Cluster, Call, Cascade):
+		- While the expression is false, the group keeps spawning jobs. The phase is Pending (reason: SpawnUntilEvent).
+		- Once the expression is true, the phase becomes Running (reason: UntilCondition), the AllJobsAreScheduled
+		  condition is set, and spec.suspend is set to true. From then on, the lifecycle depends only on the jobs
+		  that are already scheduled.
+		- If maxJobs is positive and the scheduled jobs exceed it before the expression becomes true, the group
+		  never converges. The phase becomes Failed (reason: MaxInstancesReached), and the UnexpectedTermination
+		  condition is set.
+
+	Assert (spec.actions[].assert on Scenario):
+		- The expression must remain true for as long as the parent is active. Once it becomes false, the phase
+		  becomes Failed (reason: AssertError), and the AssertError condition is set. Suspend is not affected.
+
+	Tolerate (spec.tolerate on Cluster, Call) is not an expression, and is handled by lifecycle.GroupedJobs.
+
+	Alertmanager expressions do not notify the controller. Instead, the controller polls the Alertmanager every
+	AlertmanagerPollInterval, for as long as the expression is evaluated.
+*/
+
+const (
+	ReasonUntilCondition      = "UntilCondition"
+	ReasonSpawnUntilEvent     = "SpawnUntilEvent"
+	ReasonMaxInstancesReached = "MaxInstancesReached"
+	ReasonAssertError         = "AssertError"
+)
+
+// Until describes a SuspendWhen expression, and the state of the group that it controls.
+type Until struct {
+	// Expr is the SuspendWhen expression.
+	Expr *v1alpha1.ConditionalExpr
+
+	// Suspend points to the spec.suspend of the group. It is set to true once the expression is met.
+	Suspend **bool
+
+	// ScheduledJobs is the index of the last scheduled job.
+	ScheduledJobs int
+
+	// MaxJobs is the upper bound of jobs before the expression is met. Zero means unbounded.
+	MaxJobs int
+}
+
+// IsHandled returns true if the expression has been met in a previous evaluation.
+// In that case, the lifecycle depends only on the ScheduledJobs+1 jobs that are already scheduled.
+func (u Until) IsHandled(lf *v1alpha1.Lifecycle) bool {
+	return meta.IsStatusConditionTrue(lf.Conditions, v1alpha1.ConditionAllJobsAreScheduled.String())
+}
+
+// Evaluate applies the Until semantics on the lifecycle of the job. It always returns true,
+// as the lifecycle is set in every case.
+func (u Until) Evaluate(state lifecycle.ClassifierReader, job metav1.Object, lf *v1alpha1.Lifecycle) bool {
+	eval := Condition{Expr: u.Expr}
+	if eval.IsTrue(state, job) {
+		lf.Phase = v1alpha1.PhaseRunning
+		lf.Reason = ReasonUntilCondition
+		lf.Message = eval.Info
+
+		meta.SetStatusCondition(&lf.Conditions, metav1.Condition{
+			Type:    v1alpha1.ConditionAllJobsAreScheduled.String(),
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonUntilCondition,
+			Message: eval.Info,
+		})
+
+		// prevent the parent from spawning new jobs.
+		suspend := true
+		*u.Suspend = &suspend
+
+		return true
+	}
+
+	// Event used in conjunction with "Until", instance act as a maximum bound.
+	// If the maximum instances are reached before the Until conditions, we assume that
+	// the experiment never converges, and it fails.
+	if u.MaxJobs > 0 && (u.ScheduledJobs > u.MaxJobs) {
+		msg := fmt.Sprintf(`Resource [%s] has reached Max instances [%d] before Until conditions are met.
+			Abort the experiment as it too flaky to accept. You can retry without defining instances.`,
+			job.GetName(), u.MaxJobs)
+
+		lf.Phase = v1alpha1.PhaseFailed
+		lf.Reason = ReasonMaxInstancesReached
+		lf.Message = msg
+
+		meta.SetStatusCondition(&lf.Conditions, metav1.Condition{
+			Type:    v1alpha1.ConditionJobUnexpectedTermination.String(),
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonMaxInstancesReached,
+			Message: msg,
+		})
+
+		return true
+	}
+
+	// A side effect of "Until" is that queued jobs will be reused,
+	// until the conditions are met. In that sense, they resemble mostly a pool of jobs
+	// rather than e queue.
+	lf.Phase = v1alpha1.PhasePending
+	lf.Reason = ReasonSpawnUntilEvent
+	lf.Message = "Assertion is not yet satisfied."
+
+	return true
+}
+
+// Assert applies the Assert semantics on the lifecycle of the job. The subject names the entity that carries the
+// assertion (e.g, an action) and is used in the message. If the assertion is violated, it returns the values
+// observed during the evaluation. Otherwise, it returns nil.
+func Assert(state lifecycle.ClassifierReader, job metav1.Object, lf *v1alpha1.Lifecycle,
+	expr *v1alpha1.ConditionalExpr, subject string, now time.Time,
+) *v1alpha1.AssertionFailure {
+	if expr.IsZero() {
+		return nil
+	}
+
+	eval := Condition{Expr: expr}
+	if eval.IsTrue(state, job) {
+		return nil
+	}
+
+	msg := fmt.Sprintf("%s failed due to:'%s'", subject, eval.Info)
+	if len(eval.Values) > 0 {
+		msg = fmt.Sprintf("%s. Values: [%s]", msg, eval.GetValues())
+	}
+
+	lf.Phase = v1alpha1.PhaseFailed
+	lf.Reason = ReasonAssertError
+	lf.Message = msg
+
+	meta.SetStatusCondition(&lf.Conditions, metav1.Condition{
+		Type:    v1alpha1.ConditionAssertionError.String(),
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonAssertError,
+		Message: msg,
+	})
+
+	return &v1alpha1.AssertionFailure{
+		Expression: exprString(expr),
+		Values:     eval.Values,
+		Time:       metav1.NewTime(now),
+	}
+}
+
+// exprString returns the printable form of the defined expression.
+func exprString(expr *v1alpha1.ConditionalExpr) string {
+	switch {
+	case expr.HasStateExpr():
+		return string(expr.State)
+	case expr.HasMetricsExpr():
+		return string(expr.Metrics)
+	default:
+		return string(expr.Alertmanager)
+	}
+}
@@ -103,6 +103,10 @@ func (c *Client) AddAnnotation(annotationRequest sdk.CreateAnnotationRequest) (r
 		return 0
 	}
 
+	if len(c.tags) > 0 {
+		annotationRequest.Tags = append(annotationRequest.Tags[:len(annotationRequest.Tags):len(annotationRequest.Tags)], c.tags...)
+	}
+
 	/*---------------------------------------------------*
 	 * Set the retry logic
 	 *---------------------------------------------------*/
@@ -19,6 +19,8 @@ package grafana
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 
 	"github.com/carv-ics-forth/frisbee/controllers/common"
 	"github.com/go-logr/logr"
@@ -26,6 +28,7 @@ import (
 	"github.com/grafana-tools/sdk"
 	gapi "github.com/grafana/grafana-api-golang-client"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 )
@@ -40,6 +43,16 @@ type Options struct {
 	Logger logr.Logger
 
 	HTTPEndpoint *string
+
+	Backoff *wait.Backoff
+
+	Transport *http.Transport
+
+	Tags []Tag
+
+	APIKey *string
+
+	BasicAuth *url.Userinfo
 }
 
 type Option func(*Options)
@@ -72,12 +85,87 @@ func WithLogger(logger logr.Logger) Option {
 // WithHTTP will use HTTP for connection with Grafana.
 func WithHTTP(endpoint string) Option {
 	return func(args *Options) {
-		httpEndpoint := fmt.Sprintf("http://%s", endpoint)
+		httpEndpoint := httpURL(endpoint)
 
 		args.HTTPEndpoint = &httpEndpoint
 	}
 }
 
+func httpURL(endpoint string) string {
+	return fmt.Sprintf("http://%s", endpoint)
+}
+
+// WithBackoff overrides the retries for reaching Grafana. A single step makes the connection non-blocking.
+func WithBackoff(backoff wait.Backoff) Option {
+	return func(args *Options) {
+		args.Backoff = &backoff
+	}
+}
+
+// WithTags will add the given tags to every annotation pushed by the client.
+func WithTags(tags ...Tag) Option {
+	return func(args *Options) {
+		args.Tags = append(args.Tags, tags...)
+	}
+}
+
+// WithTransport will use the given transport for the requests to Grafana, instead of a clone of the default one.
+func WithTransport(transport *http.Transport) Option {
+	return func(args *Options) {
+		args.Transport = transport
+	}
+}
+
+// WithAPIKey will authenticate the requests to Grafana with the given API key or service account token.
+func WithAPIKey(key string) Option {
+	return func(args *Options) {
+		args.APIKey = &key
+	}
+}
+
+// WithBasicAuth will authenticate the requests to Grafana with the given username and password.
+func WithBasicAuth(username, password string) Option {
+	return func(args *Options) {
+		args.BasicAuth = url.UserPassword(username, password)
+	}
+}
+
+// SecretKeyAPIKey is the key of a Secret that holds the API key for Grafana.
+// Secrets for basic authentication use the keys of the kubernetes.io/basic-auth type.
+const SecretKeyAPIKey = "apiKey"
+
+// WithSecret will authenticate the requests to Grafana with the credentials of the given Secret.
+// The API key takes precedence over the basic authentication.
+func WithSecret(secret *corev1.Secret) (Option, error) {
+	if key, ok := secret.Data[SecretKeyAPIKey]; ok {
+		return WithAPIKey(string(key)), nil
+	}
+
+	username, hasUsername := secret.Data[corev1.BasicAuthUsernameKey]
+	password, hasPassword := secret.Data[corev1.BasicAuthPasswordKey]
+
+	if !hasUsername || !hasPassword {
+		return nil, errors.Errorf("secret '%s' has neither '%s' nor '%s'/'%s'", secret.GetName(),
+			SecretKeyAPIKey, corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey)
+	}
+
+	return WithBasicAuth(string(username), string(password)), nil
+}
+
+// authorization returns the credentials in the format of the sdk client: either an API key or 'username:password'.
+func (args *Options) authorization() string {
+	switch {
+	case args.APIKey != nil:
+		return *args.APIKey
+	case args.BasicAuth != nil:
+		password, _ := args.BasicAuth.Password()
+
+		return args.BasicAuth.Username() + ":" + password
+	default:
+		return ""
+	}
+}
+
 type Client struct {
 	logger logr.Logger
 
@@ -86,6 +174,28 @@ type Client struct {
 	GapiClient *gapi.Client
 
 	BaseURL string
+
+	// transport is dedicated to the client, and is released by Close.
+	transport *http.Transport
+
+	// tags are added to every annotation pushed by the client.
+	tags []Tag
+
+	// authorization holds the credentials of the client, as passed to the sdk client.
+	authorization string
+}
+
+// Authorization returns the credentials of the client, either as an API key or as 'username:password'.
+// It is empty if the client is unauthenticated.
+func (c *Client) Authorization() string {
+	return c.authorization
+}
+
+// Close releases the connections of the client. It is called when the client is evicted from the pool.
+func (c *Client) Close() {
+	if c.transport != nil {
+		c.transport.CloseIdleConnections()
+	}
 }
 
 func New(parentCtx context.Context, setters ...Option) (*Client, error) {
@@ -95,7 +205,7 @@ func New(parentCtx context.Context, setters ...Option) (*Client, error) {
 		setter(&args)
 	}
 
-	client := &Client{}
+	client := &Client{tags: args.Tags, authorization: args.authorization()}
 
 	if args.Logger == (logr.Logger{}) {
 		client.logger = defaultLogger
@@ -107,7 +217,11 @@ func New(parentCtx context.Context, setters ...Option) (*Client, error) {
 	if args.HTTPEndpoint != nil {
 		client.logger.Info("Connecting to Grafana ...", "endpoint", *args.HTTPEndpoint)
 
-		conn, err := sdk.NewClient(*args.HTTPEndpoint, "", sdk.DefaultHTTPClient)
+		httpClient, transport := newHTTPClient(args.Transport)
+
+		client.transport = transport
+
+		conn, err := sdk.NewClient(*args.HTTPEndpoint, client.authorization, httpClient)
 		if err != nil {
 			return nil, errors.Wrapf(err, "client error")
 		}
@@ -134,7 +248,12 @@ func New(parentCtx context.Context, setters ...Option) (*Client, error) {
 			return true, nil
 		}
 
-		if err := wait.ExponentialBackoffWithContext(parentCtx, common.DefaultBackoffForServiceEndpoint, retryCond); err != nil {
+		backoff := common.DefaultBackoffForServiceEndpoint
+		if args.Backoff != nil {
+			backoff = *args.Backoff
+		}
+
+		if err := wait.ExponentialBackoffWithContext(parentCtx, backoff, retryCond); err != nil {
 			return nil, errors.Wrapf(err, "endpoint is unreachable ('%s')", *args.HTTPEndpoint)
 		}
 
@@ -142,7 +261,12 @@ func New(parentCtx context.Context, setters ...Option) (*Client, error) {
 		client.BaseURL = *args.HTTPEndpoint
 
 		// Start Gapi client
-		gapiClient, err := gapi.New(*args.HTTPEndpoint, gapi.Config{})
+		gapiConfig := gapi.Config{Client: httpClient, BasicAuth: args.BasicAuth}
+		if args.APIKey != nil {
+			gapiConfig.APIKey = *args.APIKey
+		}
+
+		gapiClient, err := gapi.New(*args.HTTPEndpoint, gapiConfig)
 		if err != nil {
 			return nil, errors.Wrapf(err, "Failed to initialize gapi client")
 		}
@@ -17,8 +17,10 @@ limitations under the License.
 package grafana
 
 import (
+	"container/list"
 	"reflect"
 	"sync"
+	"time"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/pkg/errors"
@@ -28,8 +30,27 @@ import (
 )
 
 var (
-	clientsLocker sync.RWMutex
-	clients       = map[types.NamespacedName]*Client{}
+	// PoolSize is the maximum number of clients in the pool. When the pool is full, registering a new client
+	// evicts the least recently used one.
+	PoolSize = 256
+
+	// PoolTTL is the time after which an unused client is evicted from the pool.
+	PoolTTL = time.Hour
+)
+
+// pooledClient is an entry of the pool.
+type pooledClient struct {
+	key      types.NamespacedName
+	client   *Client
+	lastUsed time.Time
+}
+
+var (
+	clientsLocker sync.Mutex
+	clients       = map[types.NamespacedName]*list.Element{}
+
+	// recency orders the clients from the most to the least recently used.
+	recency = list.New()
 )
 
 func getScenarioFromLabels(obj metav1.Object) types.NamespacedName {
@@ -41,27 +62,83 @@ func getScenarioFromLabels(obj metav1.Object) types.NamespacedName {
 	// Namespaces: provides separation between test-cases
 	// Scenario: Is a flag that is propagated all over the test-cases.
 	return types.NamespacedName{
-		Namespace: obj.GetNamespace(),
+		Namespace: v1alpha1.GetScenarioNamespace(obj),
 		Name:      v1alpha1.GetScenarioLabel(obj),
 	}
 }
 
-// SetClientFor creates a new client for the given object.  It panics if it cannot parse the object's metadata,
-// or if another client is already registers.
+// lookup returns the client registered for the key, and marks it as recently used.
+// Expired clients are evicted. It must be called with the lock held.
+func lookup(key types.NamespacedName) *Client {
+	elem, exists := clients[key]
+	if !exists {
+		return nil
+	}
+
+	entry := elem.Value.(*pooledClient)
+
+	if time.Since(entry.lastUsed) > PoolTTL {
+		evict(elem, "ttl")
+
+		return nil
+	}
+
+	entry.lastUsed = time.Now()
+	recency.MoveToFront(elem)
+
+	return entry.client
+}
+
+// evict removes the entry from the pool, and closes its client. It must be called with the lock held.
+func evict(elem *list.Element, reason string) {
+	entry := elem.Value.(*pooledClient)
+
+	recency.Remove(elem)
+	delete(clients, entry.key)
+
+	entry.client.Close()
+
+	poolEvictions.WithLabelValues(reason).Inc()
+	poolClients.Set(float64(len(clients)))
+
+	entry.client.logger.Info("Evict Grafana client for", "obj", entry.key, "reason", reason)
+}
+
+// evictExpired removes the clients that have not been used within the PoolTTL, and then the least recently used
+// clients, until there is room for a new one. It must be called with the lock held.
+func evictExpired() {
+	for elem := recency.Back(); elem != nil; {
+		prev := elem.Prev()
+
+		if time.Since(elem.Value.(*pooledClient).lastUsed) > PoolTTL {
+			evict(elem, "ttl")
+		}
+
+		elem = prev
+	}
+
+	for len(clients) >= PoolSize && recency.Len() > 0 {
+		evict(recency.Back(), "lru")
+	}
+}
+
+// SetClientFor registers the client for the given object. It panics if it cannot parse the object's metadata.
+// If another client is already registered (e.g, because the endpoint has changed), the old client is closed
+// and replaced.
 func SetClientFor(obj metav1.Object, client *Client) {
 	key := getScenarioFromLabels(obj)
 
-	clientsLocker.RLock()
-	_, exists := clients[key]
-	clientsLocker.RUnlock()
+	clientsLocker.Lock()
+	defer clientsLocker.Unlock()
 
-	if exists {
-		panic(errors.Errorf("client is already registered for '%s'", key))
+	if elem, exists := clients[key]; exists {
+		evict(elem, "replaced")
 	}
 
-	clientsLocker.Lock()
-	clients[key] = client
-	clientsLocker.Unlock()
+	evictExpired()
+
+	clients[key] = recency.PushFront(&pooledClient{key: key, client: client, lastUsed: time.Now()})
+	poolClients.Set(float64(len(clients)))
 
 	client.logger.Info("Set Grafana client for", "obj", key)
 }
@@ -77,11 +154,11 @@ func GetClientFor(obj metav1.Object) *Client {
 
 	key := getScenarioFromLabels(obj)
 
-	clientsLocker.RLock()
-	defer clientsLocker.RUnlock()
+	clientsLocker.Lock()
+	defer clientsLocker.Unlock()
 
-	client, exists := clients[key]
-	if !exists || client == nil {
+	client := lookup(key)
+	if client == nil {
 		panic("nil grafana client was found for object: " + obj.GetName())
 	}
 
@@ -96,18 +173,30 @@ func HasClientFor(obj metav1.Object) bool {
 
 	key := getScenarioFromLabels(obj)
 
-	clientsLocker.RLock()
-	defer clientsLocker.RUnlock()
+	clientsLocker.Lock()
+	defer clientsLocker.Unlock()
+
+	return lookup(key) != nil
+}
 
-	client, exists := clients[key]
-	if !exists || client == nil {
+// IsConnectedTo returns whether the client registered for the given object points to the given endpoint.
+// The endpoint has the form expected by WithHTTP.
+func IsConnectedTo(obj metav1.Object, endpoint string) bool {
+	if !v1alpha1.HasScenarioLabel(obj) {
 		return false
 	}
 
-	return true
+	key := getScenarioFromLabels(obj)
+
+	clientsLocker.Lock()
+	defer clientsLocker.Unlock()
+
+	client := lookup(key)
+
+	return client != nil && client.BaseURL == httpURL(endpoint)
 }
 
-// DeleteClientFor removes the client registered for the given object.
+// DeleteClientFor removes and closes the client registered for the given object.
 func DeleteClientFor(obj metav1.Object) {
 	if !v1alpha1.HasScenarioLabel(obj) {
 		return
@@ -118,5 +207,7 @@ func DeleteClientFor(obj metav1.Object) {
 	clientsLocker.Lock()
 	defer clientsLocker.Unlock()
 
-	delete(clients, key)
+	if elem, exists := clients[key]; exists {
+		evict(elem, "deleted")
+	}
 }
@@ -0,0 +1,74 @@
+package grafana_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/grafana"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func scenarioObject(name string) *metav1.ObjectMeta {
+	obj := &metav1.ObjectMeta{Namespace: "pool", Name: name}
+	v1alpha1.SetScenarioLabel(obj, name)
+
+	return obj
+}
+
+func register(t *testing.T, obj metav1.Object) {
+	t.Helper()
+
+	if _, err := grafana.New(context.Background(), grafana.WithRegisterFor(obj)); err != nil {
+		t.Fatalf("cannot register client: %v", err)
+	}
+}
+
+func TestClientPool(t *testing.T) {
+	defaultSize, defaultTTL := grafana.PoolSize, grafana.PoolTTL
+	defer func() { grafana.PoolSize, grafana.PoolTTL = defaultSize, defaultTTL }()
+
+	a, b, c := scenarioObject("a"), scenarioObject("b"), scenarioObject("c")
+	defer func() {
+		for _, obj := range []metav1.Object{a, b, c} {
+			grafana.DeleteClientFor(obj)
+		}
+	}()
+
+	// the least recently used client is evicted when the pool is full.
+	grafana.PoolSize = 2
+
+	register(t, a)
+	register(t, b)
+
+	if !grafana.HasClientFor(a) {
+		t.Fatal("expected client for 'a'")
+	}
+
+	register(t, c)
+
+	if grafana.HasClientFor(b) {
+		t.Error("expected the least recently used client 'b' to be evicted")
+	}
+
+	if !grafana.HasClientFor(a) || !grafana.HasClientFor(c) {
+		t.Error("expected clients for 'a' and 'c'")
+	}
+
+	// registering a client for the same scenario replaces the old one.
+	register(t, a)
+
+	if !grafana.HasClientFor(a) {
+		t.Error("expected the client of 'a' to be replaced")
+	}
+
+	// unused clients expire.
+	grafana.PoolTTL = time.Nanosecond
+
+	time.Sleep(time.Millisecond)
+
+	if grafana.HasClientFor(a) || grafana.HasClientFor(c) {
+		t.Error("expected the clients to expire")
+	}
+}
@@ -0,0 +1,200 @@
+package grafana_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/pkg/grafana"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// connect creates a client to a stub Grafana, and returns the Authorization header of the health check.
+func connect(t *testing.T, setters ...grafana.Option) (*grafana.Client, string) {
+	t.Helper()
+
+	var header string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("Authorization")
+
+		_, _ = w.Write([]byte(`{"database": "ok"}`))
+	}))
+	defer server.Close()
+
+	setters = append(setters,
+		grafana.WithHTTP(strings.TrimPrefix(server.URL, "http://")),
+		grafana.WithBackoff(wait.Backoff{Steps: 1}),
+	)
+
+	client, err := grafana.New(context.Background(), setters...)
+	if err != nil {
+		t.Fatalf("cannot connect to grafana: %v", err)
+	}
+
+	return client, header
+}
+
+func TestAuthentication(t *testing.T) {
+	t.Run("unauthenticated", func(t *testing.T) {
+		client, header := connect(t)
+
+		if header != "" || client.Authorization() != "" {
+			t.Fatalf("unexpected credentials: header '%s', authorization '%s'", header, client.Authorization())
+		}
+	})
+
+	t.Run("api key", func(t *testing.T) {
+		client, header := connect(t, grafana.WithAPIKey("secret"))
+
+		if header != "Bearer secret" || client.Authorization() != "secret" {
+			t.Fatalf("unexpected credentials: header '%s', authorization '%s'", header, client.Authorization())
+		}
+	})
+
+	t.Run("basic auth", func(t *testing.T) {
+		client, header := connect(t, grafana.WithBasicAuth("admin", "pass:word"))
+
+		request := &http.Request{Header: http.Header{"Authorization": {header}}}
+
+		if user, password, ok := request.BasicAuth(); !ok || user != "admin" || password != "pass:word" {
+			t.Fatalf("unexpected header '%s'", header)
+		}
+
+		if client.Authorization() != "admin:pass:word" {
+			t.Fatalf("unexpected authorization '%s'", client.Authorization())
+		}
+	})
+}
+
+func TestWithSecret(t *testing.T) {
+	secret := func(data map[string]string) *corev1.Secret {
+		s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "grafana"}, Data: map[string][]byte{}}
+		for k, v := range data {
+			s.Data[k] = []byte(v)
+		}
+
+		return s
+	}
+
+	tests := []struct {
+		name    string
+		data    map[string]string
+		want    string
+		wantErr bool
+	}{
+		{name: "api key", data: map[string]string{grafana.SecretKeyAPIKey: "key", "username": "admin", "password": "pass"}, want: "key"},
+		{name: "basic auth", data: map[string]string{"username": "admin", "password": "pass"}, want: "admin:pass"},
+		{name: "missing password", data: map[string]string{"username": "admin"}, wantErr: true},
+		{name: "empty", data: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			option, err := grafana.WithSecret(secret(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WithSecret() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			client, err := grafana.New(context.Background(), option)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			if client.Authorization() != tt.want {
+				t.Fatalf("Authorization() = '%s', want '%s'", client.Authorization(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDownloadDataAuthentication(t *testing.T) {
+	var annotationsHeader string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/annotations":
+			annotationsHeader = r.Header.Get("Authorization")
+
+			_, _ = w.Write([]byte(`[]`))
+		case strings.HasPrefix(r.URL.Path, "/api/dashboards/uid/"):
+			_, _ = w.Write([]byte(`{"dashboard": {"uid": "summary", "panels": []}, "meta": {}}`))
+		default:
+			_, _ = w.Write([]byte(`{"database": "ok"}`))
+		}
+	}))
+	defer server.Close()
+
+	endpoint := strings.TrimPrefix(server.URL, "http://")
+
+	client, err := grafana.New(context.Background(),
+		grafana.WithHTTP(endpoint),
+		grafana.WithBackoff(wait.Backoff{Steps: 1}),
+		grafana.WithAPIKey("secret"),
+	)
+	if err != nil {
+		t.Fatalf("cannot connect to grafana: %v", err)
+	}
+
+	url := grafana.NewURL(endpoint).WithDashboard("summary").WithFromTS(time.Unix(0, 0)).WithToTS(time.Unix(60, 0))
+
+	if err := client.DownloadData(context.Background(), url, t.TempDir()); err != nil {
+		t.Fatalf("DownloadData() error = %v", err)
+	}
+
+	if annotationsHeader != "Bearer secret" {
+		t.Fatalf("unexpected header '%s' for annotations", annotationsHeader)
+	}
+}
+
+func TestRenderPanel(t *testing.T) {
+	contentType := "image/png"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/render/d-solo/summary/") {
+			if r.URL.Query().Get("panelId") != "7" {
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			w.Header().Set("Content-Type", contentType)
+			_, _ = w.Write([]byte("png"))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"database": "ok"}`))
+	}))
+	defer server.Close()
+
+	endpoint := strings.TrimPrefix(server.URL, "http://")
+
+	client, err := grafana.New(context.Background(), grafana.WithHTTP(endpoint), grafana.WithBackoff(wait.Backoff{Steps: 1}))
+	if err != nil {
+		t.Fatalf("cannot connect to grafana: %v", err)
+	}
+
+	url := grafana.NewURL(endpoint).WithDashboard("summary").WithPanel(7).WithFromTS(time.Unix(0, 0)).WithToTS(time.Unix(60, 0))
+
+	snapshot, err := client.RenderPanel(context.Background(), url, 100, 50)
+	if err != nil || string(snapshot) != "png" {
+		t.Fatalf("RenderPanel() = '%s', %v", snapshot, err)
+	}
+
+	// without a renderer, Grafana responds with its login/error page.
+	contentType = "text/html"
+
+	if _, err := client.RenderPanel(context.Background(), url, 100, 50); err == nil {
+		t.Fatal("expected error for missing renderer")
+	}
+}
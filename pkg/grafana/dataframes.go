@@ -21,9 +21,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
-	"github.com/go-logr/logr"
 	"github.com/gosimple/slug"
+	"github.com/grafana-tools/sdk"
 	"github.com/imroc/req/v3"
 	"github.com/pkg/errors"
 )
@@ -54,21 +55,12 @@ func (c *Client) DownloadData(ctx context.Context, url *URL, destDir string) err
 		return errors.Wrapf(err, "cannot retrieve dashboard %s", *url.DashboardUID)
 	}
 
-	dataRange := TimeRange{
-		From: url.FromTS.UTC(),
-		To:   url.ToTS.UTC(),
-		Raw: &RawTimeRange{
-			From: url.FromTS.UTC(),
-			To:   url.ToTS.UTC(),
-		},
-	}
-
 	/*---------------------------------------------------*
 	 * Download Annotations
 	 *---------------------------------------------------*/
 	annotationsFilepath := filepath.Join(destDir, "annotations.json")
 
-	if err := downloadAnnotations(c.logger, url, annotationsFilepath); err != nil {
+	if err := c.downloadAnnotations(url, annotationsFilepath); err != nil {
 		return errors.Wrapf(err, "failed to download annotations")
 	}
 
@@ -76,109 +68,142 @@ func (c *Client) DownloadData(ctx context.Context, url *URL, destDir string) err
 	 * Download DataFrames
 	 *---------------------------------------------------*/
 	for _, panel := range board.Panels {
-		var queries []interface{}
+		queries := c.panelQueries(panel)
 
-		// extract queries per panel type
-		switch {
-		case panel.GraphPanel != nil:
-			for _, target := range panel.GraphPanel.Targets {
-				queries = append(queries, target)
-			}
-		case panel.TablePanel != nil:
-			for _, target := range panel.TablePanel.Targets {
-				evaluateDashboardVariable(&target.Expr)
+		// submit queries
+		if len(queries) > 0 {
+			dataReq := newDataRequest(url, queries)
 
-				queries = append(queries, target)
-			}
-		case panel.SinglestatPanel != nil:
-			for _, target := range panel.SinglestatPanel.Targets {
-				evaluateDashboardVariable(&target.Expr)
+			dataFilepath := filepath.Join(destDir, slug.Make(panel.Title)+".json")
 
-				queries = append(queries, target)
+			if err := c.downloadDataFrame(url, dataReq, dataFilepath); err != nil {
+				return errors.Wrapf(err, "unable to download csv data")
 			}
-		case panel.StatPanel != nil:
-			for _, target := range panel.StatPanel.Targets {
-				evaluateDashboardVariable(&target.Expr)
+		}
+	}
 
-				queries = append(queries, target)
-			}
-		case panel.BarGaugePanel != nil:
-			for _, target := range panel.BarGaugePanel.Targets {
-				evaluateDashboardVariable(&target.Expr)
+	return nil
+}
 
-				queries = append(queries, target)
-			}
-		case panel.HeatmapPanel != nil:
-			for _, target := range panel.HeatmapPanel.Targets {
-				evaluateDashboardVariable(&target.Expr)
+// panelQueries returns the queries of the panel. Unsupported panels have no queries.
+func (c *Client) panelQueries(panel *sdk.Panel) []interface{} {
+	var queries []interface{}
 
-				queries = append(queries, target)
-			}
-		case panel.TimeseriesPanel != nil:
-			for _, target := range panel.TimeseriesPanel.Targets {
-				evaluateDashboardVariable(&target.Expr)
+	switch {
+	case panel.GraphPanel != nil:
+		for _, target := range panel.GraphPanel.Targets {
+			queries = append(queries, target)
+		}
+	case panel.TablePanel != nil:
+		for _, target := range panel.TablePanel.Targets {
+			evaluateDashboardVariable(&target.Expr)
 
-				queries = append(queries, target)
-			}
-		case panel.CustomPanel != nil:
-			c.logger.Info("CustomPanel is not supported. Skip it", "panelTitle", panel.Title)
+			queries = append(queries, target)
+		}
+	case panel.SinglestatPanel != nil:
+		for _, target := range panel.SinglestatPanel.Targets {
+			evaluateDashboardVariable(&target.Expr)
 
-			continue
-		case panel.TextPanel != nil:
-			c.logger.Info("TextPanel is not supported. Skip it", "panelTitle", panel.Title)
+			queries = append(queries, target)
+		}
+	case panel.StatPanel != nil:
+		for _, target := range panel.StatPanel.Targets {
+			evaluateDashboardVariable(&target.Expr)
 
-			continue
-		case panel.DashlistPanel != nil:
-			c.logger.Info("DashlistPanel is not supported. Skip it", "panelTitle", panel.Title)
+			queries = append(queries, target)
+		}
+	case panel.BarGaugePanel != nil:
+		for _, target := range panel.BarGaugePanel.Targets {
+			evaluateDashboardVariable(&target.Expr)
 
-			continue
-		case panel.PluginlistPanel != nil:
-			c.logger.Info("PluginlistPanel is not supported. Skip it", "panelTitle", panel.Title)
+			queries = append(queries, target)
+		}
+	case panel.HeatmapPanel != nil:
+		for _, target := range panel.HeatmapPanel.Targets {
+			evaluateDashboardVariable(&target.Expr)
 
-			continue
-		case panel.RowPanel != nil:
-			c.logger.Info("RowPanel is not supported. Skip it", "panelTitle", panel.Title)
+			queries = append(queries, target)
+		}
+	case panel.TimeseriesPanel != nil:
+		for _, target := range panel.TimeseriesPanel.Targets {
+			evaluateDashboardVariable(&target.Expr)
 
-			continue
-		case panel.AlertlistPanel != nil:
-			c.logger.Info("AlertlistPanel is not supported. Skip it", "panelTitle", panel.Title)
+			queries = append(queries, target)
+		}
+	case panel.CustomPanel != nil:
+		c.logger.Info("CustomPanel is not supported. Skip it", "panelTitle", panel.Title)
 
-			continue
-		default:
-			c.logger.V(5).Info("Unhandled panel type. skip it",
-				"panelTitle", panel.Title,
-			)
+		return nil
+	case panel.TextPanel != nil:
+		c.logger.Info("TextPanel is not supported. Skip it", "panelTitle", panel.Title)
 
-			continue
-		}
+		return nil
+	case panel.DashlistPanel != nil:
+		c.logger.Info("DashlistPanel is not supported. Skip it", "panelTitle", panel.Title)
 
-		// submit queries
-		if len(queries) > 0 {
-			dataReq := &DataRequest{
-				Queries: queries,
-				Range:   dataRange,
-				From:    fmt.Sprint(url.FromTS.UnixMilli()),
-				To:      fmt.Sprint(url.ToTS.UnixMilli()),
-			}
+		return nil
+	case panel.PluginlistPanel != nil:
+		c.logger.Info("PluginlistPanel is not supported. Skip it", "panelTitle", panel.Title)
 
-			dataFilepath := filepath.Join(destDir, slug.Make(panel.Title)+".json")
+		return nil
+	case panel.RowPanel != nil:
+		c.logger.Info("RowPanel is not supported. Skip it", "panelTitle", panel.Title)
 
-			if err := downloadDataFrame(c.logger, url, dataReq, dataFilepath); err != nil {
-				return errors.Wrapf(err, "unable to download csv data")
-			}
-		}
+		return nil
+	case panel.AlertlistPanel != nil:
+		c.logger.Info("AlertlistPanel is not supported. Skip it", "panelTitle", panel.Title)
+
+		return nil
+	default:
+		c.logger.V(5).Info("Unhandled panel type. skip it",
+			"panelTitle", panel.Title,
+		)
+
+		return nil
 	}
 
-	return nil
+	return queries
+}
+
+// newDataRequest asks for the results of the queries within the time range of the url.
+func newDataRequest(url *URL, queries []interface{}) *DataRequest {
+	return &DataRequest{
+		Queries: queries,
+		Range: TimeRange{
+			From: url.FromTS.UTC(),
+			To:   url.ToTS.UTC(),
+			Raw: &RawTimeRange{
+				From: url.FromTS.UTC(),
+				To:   url.ToTS.UTC(),
+			},
+		},
+		From: fmt.Sprint(url.FromTS.UnixMilli()),
+		To:   fmt.Sprint(url.ToTS.UnixMilli()),
+	}
 }
 
-func downloadAnnotations(logger logr.Logger, url *URL, dstFile string) error {
+// request returns a request that carries the credentials of the client.
+func (c *Client) request() *req.Request {
+	request := req.NewClient().R()
+
+	switch {
+	case c.authorization == "":
+	case strings.Contains(c.authorization, ":"):
+		username, password, _ := strings.Cut(c.authorization, ":")
+
+		request.SetBasicAuth(username, password)
+	default:
+		request.SetBearerAuthToken(c.authorization)
+	}
+
+	return request
+}
+
+func (c *Client) downloadAnnotations(url *URL, dstFile string) error {
 	/*---------------------------------------------------*
 	 * Fetch annotations from Grafana in JSON
 	 *---------------------------------------------------*/
-	client := req.NewClient()
-
-	resp, err := client.R().Get(url.AnnotationsQuery())
+	resp, err := c.request().Get(url.AnnotationsQuery())
 	if err != nil {
 		return errors.Wrapf(err, "GET has failed")
 	}
@@ -194,37 +219,45 @@ func downloadAnnotations(logger logr.Logger, url *URL, dstFile string) error {
 		return errors.Wrapf(err, "failed to write annotations to '%s'", dstFile)
 	}
 
-	logger.Info("Annotations saved.", "file", dstFile)
+	c.logger.Info("Annotations saved.", "file", dstFile)
 
 	return nil
 }
 
 // downloadDataFrame downloads raw data without transformations and field config applied.
-func downloadDataFrame(logger logr.Logger, url *URL, reqBody *DataRequest, dstFile string) error {
+func (c *Client) downloadDataFrame(url *URL, reqBody *DataRequest, dstFile string) error {
 	/*---------------------------------------------------*
 	 * Fetch data from Grafana in JSON format
 	 *---------------------------------------------------*/
-	client := req.NewClient()
-
-	resp, err := client.R().
-		SetBodyJsonMarshal(reqBody).
-		Post(url.DataSourceQuery())
+	data, err := c.queryDataFrame(url, reqBody)
 	if err != nil {
-		return errors.Wrapf(err, "POST has failed")
-	}
-
-	if !resp.IsSuccessState() {
-		return errors.Errorf("unsuccessful response: %s", resp)
+		return err
 	}
 
 	/*---------------------------------------------------*
 	 * Store JSON to file
 	 *---------------------------------------------------*/
-	if err := os.WriteFile(dstFile, resp.Bytes(), 0o600); err != nil {
+	if err := os.WriteFile(dstFile, data, 0o600); err != nil {
 		return errors.Wrapf(err, "failed to write data to '%s'", dstFile)
 	}
 
-	logger.Info("Data saved.", "file", dstFile)
+	c.logger.Info("Data saved.", "file", dstFile)
 
 	return nil
 }
+
+// queryDataFrame returns the raw response of Grafana to the data request.
+func (c *Client) queryDataFrame(url *URL, reqBody *DataRequest) ([]byte, error) {
+	resp, err := c.request().
+		SetBodyJsonMarshal(reqBody).
+		Post(url.DataSourceQuery())
+	if err != nil {
+		return nil, errors.Wrapf(err, "POST has failed")
+	}
+
+	if !resp.IsSuccessState() {
+		return nil, errors.Errorf("unsuccessful response: %s", resp)
+	}
+
+	return resp.Bytes(), nil
+}
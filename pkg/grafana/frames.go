@@ -0,0 +1,232 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grafana
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Frame is a table of results, as returned by a query of a panel. The first row of a frame is not a header.
+type Frame struct {
+	// Name identifies the query (or the series) that produced the frame.
+	Name string
+
+	// Fields are the names of the columns.
+	Fields []string
+
+	// Rows are the values, formatted as strings.
+	Rows [][]string
+}
+
+// WriteCSV writes the frame in CSV, with the fields as header.
+func (f *Frame) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(f.Fields); err != nil {
+		return errors.Wrapf(err, "cannot write header")
+	}
+
+	if err := writer.WriteAll(f.Rows); err != nil {
+		return errors.Wrapf(err, "cannot write rows")
+	}
+
+	return nil
+}
+
+// PanelData is the data behind a panel.
+type PanelData struct {
+	PanelRef
+
+	Frames []Frame
+}
+
+// QueryPanels returns the data of every panel in the dashboard of the url, within the time range of the url.
+// Panels without queries are omitted.
+func (c *Client) QueryPanels(ctx context.Context, url *URL) ([]PanelData, error) {
+	if c == nil {
+		panic("empty client was given")
+	}
+
+	board, _, err := c.Conn.GetDashboardByUID(ctx, *url.DashboardUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot retrieve dashboard %s", *url.DashboardUID)
+	}
+
+	var data []PanelData
+
+	for _, panel := range board.Panels {
+		queries := c.panelQueries(panel)
+		if len(queries) == 0 {
+			continue
+		}
+
+		resp, err := c.queryDataFrame(url, newDataRequest(url, queries))
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot query panel '%s'", panel.Title)
+		}
+
+		frames, err := ParseFrames(resp)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot parse data of panel '%s'", panel.Title)
+		}
+
+		data = append(data, PanelData{
+			PanelRef: PanelRef{Title: panel.Title, ID: panel.ID, Type: panel.Type},
+			Frames:   frames,
+		})
+	}
+
+	return data, nil
+}
+
+// dataResponse is the response of Grafana to a DataRequest.
+type dataResponse struct {
+	Results map[string]struct {
+		Error  string `json:"error"`
+		Frames []struct {
+			Schema struct {
+				Name   string `json:"name"`
+				RefID  string `json:"refId"`
+				Fields []struct {
+					Name   string            `json:"name"`
+					Type   string            `json:"type"`
+					Labels map[string]string `json:"labels"`
+					Config struct {
+						DisplayNameFromDS string `json:"displayNameFromDS"`
+					} `json:"config"`
+				} `json:"fields"`
+			} `json:"schema"`
+			Data struct {
+				Values [][]interface{} `json:"values"`
+			} `json:"data"`
+		} `json:"frames"`
+	} `json:"results"`
+}
+
+// ParseFrames converts the response of Grafana to a DataRequest into tables.
+// The values of time fields are formatted in RFC3339 (UTC).
+func ParseFrames(resp []byte) ([]Frame, error) {
+	var parsed dataResponse
+
+	if err := json.Unmarshal(resp, &parsed); err != nil {
+		return nil, errors.Wrapf(err, "invalid data response")
+	}
+
+	// the results are indexed by the query reference. Sort them for deterministic output.
+	refIDs := make([]string, 0, len(parsed.Results))
+	for refID := range parsed.Results {
+		refIDs = append(refIDs, refID)
+	}
+
+	sort.Strings(refIDs)
+
+	var frames []Frame
+
+	for _, refID := range refIDs {
+		result := parsed.Results[refID]
+
+		if result.Error != "" {
+			return nil, errors.Errorf("query '%s' has failed: %s", refID, result.Error)
+		}
+
+		for _, raw := range result.Frames {
+			frame := Frame{Name: raw.Schema.Name}
+			if frame.Name == "" {
+				frame.Name = refID
+			}
+
+			for _, field := range raw.Schema.Fields {
+				frame.Fields = append(frame.Fields, fieldName(field.Name, field.Config.DisplayNameFromDS, field.Labels))
+			}
+
+			values := raw.Data.Values
+
+			// the values are stored per column. Transpose them into rows.
+			rows := 0
+			if len(values) > 0 {
+				rows = len(values[0])
+			}
+
+			for row := 0; row < rows; row++ {
+				record := make([]string, len(values))
+
+				for col := range values {
+					if row < len(values[col]) {
+						isTime := col < len(raw.Schema.Fields) && raw.Schema.Fields[col].Type == "time"
+
+						record[col] = formatValue(values[col][row], isTime)
+					}
+				}
+
+				frame.Rows = append(frame.Rows, record)
+			}
+
+			frames = append(frames, frame)
+		}
+	}
+
+	return frames, nil
+}
+
+func fieldName(name string, displayName string, labels map[string]string) string {
+	if displayName != "" {
+		return displayName
+	}
+
+	if len(labels) == 0 {
+		return name
+	}
+
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", key, labels[key]))
+	}
+
+	return fmt.Sprintf("%s{%s}", name, strings.Join(pairs, ", "))
+}
+
+func formatValue(value interface{}, isTime bool) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		if isTime {
+			return time.UnixMilli(int64(v)).UTC().Format(time.RFC3339)
+		}
+
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,62 @@
+package grafana_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/pkg/grafana"
+)
+
+const dataResponse = `{
+  "results": {
+    "B": {"frames": [{"schema": {"name": "errors", "fields": [{"name": "Time", "type": "time"}, {"name": "Value", "type": "number"}]},
+                      "data": {"values": [[0], [null]]}}]},
+    "A": {"frames": [{"schema": {"refId": "A", "fields": [
+                        {"name": "Time", "type": "time"},
+                        {"name": "Value", "type": "number", "labels": {"pod": "server", "app": "iperf"}},
+                        {"name": "Value", "type": "number", "config": {"displayNameFromDS": "client"}}]},
+                      "data": {"values": [[1700000000000, 1700000015000], [1.5, 2], [3, 4.25]]}}]}
+  }
+}`
+
+func TestParseFrames(t *testing.T) {
+	frames, err := grafana.ParseFrames([]byte(dataResponse))
+	if err != nil {
+		t.Fatalf("ParseFrames() error = %v", err)
+	}
+
+	want := []grafana.Frame{
+		{
+			Name:   "A",
+			Fields: []string{"Time", `Value{app="iperf", pod="server"}`, "client"},
+			Rows: [][]string{
+				{"2023-11-14T22:13:20Z", "1.5", "3"},
+				{"2023-11-14T22:13:35Z", "2", "4.25"},
+			},
+		},
+		{
+			Name:   "errors",
+			Fields: []string{"Time", "Value"},
+			Rows:   [][]string{{"1970-01-01T00:00:00Z", ""}},
+		},
+	}
+
+	if !reflect.DeepEqual(frames, want) {
+		t.Fatalf("ParseFrames() = %v, want %v", frames, want)
+	}
+
+	var csv bytes.Buffer
+
+	if err := frames[1].WriteCSV(&csv); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+
+	if got, want := csv.String(), "Time,Value\n1970-01-01T00:00:00Z,\n"; got != want {
+		t.Fatalf("WriteCSV() = %q, want %q", got, want)
+	}
+
+	if _, err := grafana.ParseFrames([]byte(`{"results": {"A": {"error": "bad query"}}}`)); err == nil {
+		t.Fatal("expected error for failed query")
+	}
+}
@@ -25,6 +25,9 @@ import (
 type PanelRef struct {
 	Title string
 	ID    uint
+
+	// Type is the visualization of the panel (e.g, timeseries, stat, row).
+	Type string
 }
 
 // ListPanels returns a list of Panels ID with  a Grafana dashboard.
@@ -44,6 +47,7 @@ func (c *Client) ListPanels(ctx context.Context, dashboardUID string) ([]PanelRe
 		panels = append(panels, PanelRef{
 			Title: panel.Title,
 			ID:    panel.ID,
+			Type:  panel.Type,
 		})
 	}
 
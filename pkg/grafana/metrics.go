@@ -0,0 +1,61 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grafana
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	poolClients = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "frisbee_grafana_clients",
+		Help: "Number of Grafana clients in the pool.",
+	})
+
+	poolEvictions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "frisbee_grafana_client_evictions_total",
+		Help: "Number of Grafana clients removed from the pool, by reason.",
+	}, []string{"reason"})
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "frisbee_grafana_request_duration_seconds",
+		Help:    "Latency of the requests to Grafana, by method and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "code"})
+)
+
+func init() {
+	// the metrics are exposed by the metrics endpoint of the controller manager.
+	metrics.Registry.MustRegister(poolClients, poolEvictions, requestDuration)
+}
+
+// newHTTPClient returns an HTTP client with a dedicated transport, so that the connections of a client are
+// released when the client is closed, and with instrumentation for the request latencies.
+// If no transport is given, it clones the default one.
+func newHTTPClient(transport *http.Transport) (*http.Client, *http.Transport) {
+	if transport == nil {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	}
+
+	return &http.Client{
+		Transport: promhttp.InstrumentRoundTripperDuration(requestDuration, transport),
+	}, transport
+}
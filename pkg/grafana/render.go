@@ -0,0 +1,48 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grafana
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RenderPanel returns a PNG snapshot of the panel of the url, within the time range of the url.
+// Rendering is performed by Grafana, and therefore requires the Grafana Image Renderer.
+func (c *Client) RenderPanel(ctx context.Context, url *URL, width, height int) ([]byte, error) {
+	if c == nil {
+		panic("empty client was given")
+	}
+
+	resp, err := c.request().SetContext(ctx).Get(url.RenderQuery(width, height))
+	if err != nil {
+		return nil, errors.Wrapf(err, "GET has failed")
+	}
+
+	if !resp.IsSuccessState() {
+		return nil, errors.Errorf("unsuccessful response: %s", resp)
+	}
+
+	// without a renderer, Grafana responds with an html page.
+	if contentType := resp.GetContentType(); !strings.HasPrefix(contentType, "image/png") {
+		return nil, errors.Errorf("unexpected content type '%s'. Is the Grafana Image Renderer installed?", contentType)
+	}
+
+	return resp.Bytes(), nil
+}
@@ -45,6 +45,9 @@ const (
 	TagDeleted = "delete"
 	TagFailed  = "failed"
 	TagChaos   = "chaos"
+
+	// TagClockSkew marks the windows in which the clocks of services are skewed.
+	TagClockSkew = "clock-skew"
 )
 
 // Annotation provides a way to mark points on the graph with rich events.
@@ -78,3 +78,9 @@ func (url *URL) AnnotationsQuery() string {
 func BuildURL(grafanaEndpoint string, dashboard string, from int64, to int64, postfix string) string {
 	return fmt.Sprintf("http://%s/d/%s?orgId=1&from=%d&to=%d%s", grafanaEndpoint, dashboard, from, to, postfix)
 }
+
+// RenderQuery returns the URL for rendering the panel as PNG. It requires the Grafana Image Renderer.
+func (url *URL) RenderQuery(width, height int) string {
+	return fmt.Sprintf("http://%s/render/d-solo/%s/_?orgId=1&panelId=%d&from=%d&to=%d&width=%d&height=%d&tz=UTC",
+		url.Endpoint, *url.DashboardUID, *url.PanelID, url.FromTS.UnixMilli(), url.ToTS.UnixMilli(), width, height)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultRegistry is the registry of the image references that do not specify one (e.g, redis:6).
+const DefaultRegistry = "docker.io"
+
+// AnyRegistry is the key of a RewriteMap rule that applies to all registries without a dedicated rule.
+const AnyRegistry = "*"
+
+// Split breaks the image reference into the registry and the repository path (including tag or digest).
+// Short references are expanded to their canonical form (e.g, redis:6 to docker.io, library/redis:6).
+func Split(ref string) (registry string, path string) {
+	ref = strings.TrimSpace(ref)
+
+	first, rest, found := strings.Cut(ref, "/")
+	if !found {
+		return DefaultRegistry, "library/" + ref
+	}
+
+	// the first component is a registry only if it looks like a host.
+	if strings.ContainsAny(first, ".:") || first == "localhost" {
+		switch first {
+		case "index.docker.io", "registry-1.docker.io":
+			first = DefaultRegistry
+		}
+
+		return first, rest
+	}
+
+	return DefaultRegistry, ref
+}
+
+// Mirror returns the reference of the image in the mirror registry. The repository path is preserved.
+func Mirror(ref string, mirror string) string {
+	_, path := Split(ref)
+
+	return strings.TrimSuffix(mirror, "/") + "/" + path
+}
+
+// RewriteMap maps source registries to mirror registries.
+type RewriteMap map[string]string
+
+// ParseRewriteMap parses a comma-separated list of rules, in the form of <registry>=<mirror>.
+// The registry can be '*' for matching every registry without a dedicated rule.
+// For example: docker.io=registry.local:5000,quay.io=registry.local:5000/quay
+func ParseRewriteMap(rules string) (RewriteMap, error) {
+	rewrites := make(RewriteMap)
+
+	for _, rule := range strings.Split(rules, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		registry, mirror, found := strings.Cut(rule, "=")
+		if !found || strings.TrimSpace(registry) == "" || strings.TrimSpace(mirror) == "" {
+			return nil, errors.Errorf("invalid rule '%s'. expected format is <registry>=<mirror>", rule)
+		}
+
+		rewrites[strings.TrimSpace(registry)] = strings.TrimSpace(mirror)
+	}
+
+	return rewrites, nil
+}
+
+// Rewrite returns the reference of the image according to the rules of the map. Images without a matching rule,
+// and images that already belong to a mirror, are returned unmodified.
+func (m RewriteMap) Rewrite(ref string) string {
+	registry, _ := Split(ref)
+
+	for _, mirror := range m {
+		if strings.HasPrefix(ref, strings.TrimSuffix(mirror, "/")+"/") {
+			return ref
+		}
+	}
+
+	mirror, exists := m[registry]
+	if !exists {
+		mirror, exists = m[AnyRegistry]
+	}
+
+	if !exists {
+		return ref
+	}
+
+	return Mirror(ref, mirror)
+}
+
+// String returns the rules in the format accepted by ParseRewriteMap.
+func (m RewriteMap) String() string {
+	rules := make([]string, 0, len(m))
+
+	for registry, mirror := range m {
+		rules = append(rules, registry+"="+mirror)
+	}
+
+	sort.Strings(rules)
+
+	return strings.Join(rules, ",")
+}
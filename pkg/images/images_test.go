@@ -0,0 +1,103 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images_test
+
+import (
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/pkg/images"
+)
+
+func TestRewriteMap_Rewrite(t *testing.T) {
+	rewrites, err := images.ParseRewriteMap("docker.io=registry.local:5000, quay.io=registry.local:5000/quay")
+	if err != nil {
+		t.Fatalf("cannot parse rules: %v", err)
+	}
+
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{ref: "redis:6", want: "registry.local:5000/library/redis:6"},
+		{ref: "icsforth/ycsb:latest", want: "registry.local:5000/icsforth/ycsb:latest"},
+		{ref: "docker.io/bitnami/redis", want: "registry.local:5000/bitnami/redis"},
+		{ref: "index.docker.io/bitnami/redis", want: "registry.local:5000/bitnami/redis"},
+		{ref: "quay.io/prometheus/node-exporter:v1.5.0", want: "registry.local:5000/quay/prometheus/node-exporter:v1.5.0"},
+		{ref: "ghcr.io/org/app:1.0", want: "ghcr.io/org/app:1.0"},
+		{ref: "registry.local:5000/library/redis:6", want: "registry.local:5000/library/redis:6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			if got := rewrites.Rewrite(tt.ref); got != tt.want {
+				t.Errorf("Rewrite() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRewriteMap_Invalid(t *testing.T) {
+	if _, err := images.ParseRewriteMap("docker.io"); err == nil {
+		t.Error("expected error for rule without mirror")
+	}
+}
+
+func TestOverrides_Override(t *testing.T) {
+	overrides := images.Overrides{
+		"icsforth/ycsb":           "candidate",
+		"redis":                   "7.2",
+		"registry.local:5000/app": "sha256:abcd",
+		"ghcr.io/org/app":         "pr-42",
+	}
+
+	if err := overrides.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{ref: "icsforth/ycsb:latest", want: "icsforth/ycsb:candidate"},
+		{ref: "docker.io/icsforth/ycsb", want: "docker.io/icsforth/ycsb:candidate"},
+		{ref: "redis:6", want: "redis:7.2"},
+		{ref: "docker.io/library/redis@sha256:1234", want: "docker.io/library/redis:7.2"},
+		{ref: "registry.local:5000/app:1.0", want: "registry.local:5000/app@sha256:abcd"},
+		{ref: "ghcr.io/org/app:1.0", want: "ghcr.io/org/app:pr-42"},
+		{ref: "ghcr.io/other/app:1.0", want: "ghcr.io/other/app:1.0"},
+		{ref: "bitnami/redis:6", want: "bitnami/redis:6"},
+	}
+
+	for _, tt := range tests {
+		if got := overrides.Override(tt.ref); got != tt.want {
+			t.Errorf("Override(%s) = %s, want %s", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestParseOverride(t *testing.T) {
+	repository, tag, err := images.ParseOverride(" icsforth/ycsb = candidate ")
+	if err != nil || repository != "icsforth/ycsb" || tag != "candidate" {
+		t.Fatalf("ParseOverride() = %s, %s, %v", repository, tag, err)
+	}
+
+	for _, rule := range []string{"icsforth/ycsb", "=candidate", "icsforth/ycsb=", "icsforth/ycsb:1.0=candidate", "redis=other/redis"} {
+		if _, _, err := images.ParseOverride(rule); err == nil {
+			t.Errorf("expected error for '%s'", rule)
+		}
+	}
+}
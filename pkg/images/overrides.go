@@ -0,0 +1,108 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Overrides maps image repositories (e.g, icsforth/ycsb) to the tags that replace their original tags.
+// Overrides allow a scenario to run against a freshly built image, without editing the templates.
+type Overrides map[string]string
+
+// ParseOverride parses a rule in the form of <repository>=<tag>, e.g, icsforth/ycsb=candidate.
+// The tag may also be a digest (sha256:...).
+func ParseOverride(rule string) (repository string, tag string, err error) {
+	repository, tag, found := strings.Cut(strings.TrimSpace(rule), "=")
+
+	repository, tag = strings.TrimSpace(repository), strings.TrimSpace(tag)
+
+	if !found || repository == "" || tag == "" {
+		return "", "", errors.Errorf("invalid override '%s'. expected format is <repository>=<tag>", rule)
+	}
+
+	if strings.ContainsAny(tag, "/@") {
+		return "", "", errors.Errorf("invalid tag '%s' for repository '%s'", tag, repository)
+	}
+
+	if _, existing := splitTag(repository); existing != "" {
+		return "", "", errors.Errorf("repository '%s' must not have a tag", repository)
+	}
+
+	return repository, tag, nil
+}
+
+// Validate checks that every rule refers to a repository without a tag.
+func (o Overrides) Validate() error {
+	for repository, tag := range o {
+		if _, _, err := ParseOverride(repository + "=" + tag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Override returns the reference of the image with the tag of the matching rule. Repositories are matched in
+// their canonical form (e.g, redis matches docker.io/library/redis:6). Images without a rule are returned unmodified.
+func (o Overrides) Override(ref string) string {
+	if len(o) == 0 {
+		return ref
+	}
+
+	name, _ := splitTag(ref)
+	registry, path := Split(name)
+
+	for repository, tag := range o {
+		ruleRegistry, rulePath := Split(repository)
+
+		if ruleRegistry != registry || rulePath != path {
+			continue
+		}
+
+		if strings.HasPrefix(tag, "sha256:") {
+			return name + "@" + tag
+		}
+
+		return name + ":" + tag
+	}
+
+	return ref
+}
+
+// String returns the rules in the format accepted by ParseOverride, separated by commas.
+func (o Overrides) String() string {
+	return RewriteMap(o).String()
+}
+
+// splitTag breaks the image reference into the name and the tag (or digest), if any.
+func splitTag(ref string) (name string, tag string) {
+	ref = strings.TrimSpace(ref)
+
+	if name, digest, found := strings.Cut(ref, "@"); found {
+		return name, digest
+	}
+
+	// a colon after the last slash separates the tag. Otherwise, it belongs to the port of the registry.
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		return ref[:i], ref[i+1:]
+	}
+
+	return ref, ""
+}
@@ -51,20 +51,105 @@ var _ ClassifierReader = (*Classifier)(nil)
 
 // Classifier splits jobs into Pending, Running, Successful, and Failed.
 // To relief the garbage collector, we use an embeddable structure that we reset at every reconciliation cycle.
+// Reset keeps the allocated memory, so that a controller with thousands of children does not reallocate
+// its classification structures on every reconciliation.
 type Classifier struct {
-	pendingJobs    map[string]client.Object
-	runningJobs    map[string]client.Object
-	successfulJobs map[string]client.Object
-	failedJobs     map[string]client.Object
-	systemJobs     map[string]client.Object
+	pendingJobs    jobSet
+	runningJobs    jobSet
+	successfulJobs jobSet
+	failedJobs     jobSet
+	systemJobs     jobSet
 }
 
 func (in *Classifier) Reset() {
-	in.pendingJobs = make(map[string]client.Object)
-	in.runningJobs = make(map[string]client.Object)
-	in.successfulJobs = make(map[string]client.Object)
-	in.failedJobs = make(map[string]client.Object)
-	in.systemJobs = make(map[string]client.Object)
+	in.pendingJobs.reset()
+	in.runningJobs.reset()
+	in.successfulJobs.reset()
+	in.failedJobs.reset()
+	in.systemJobs.reset()
+}
+
+// jobSet is a set of named jobs whose memory is reused across resets.
+type jobSet struct {
+	jobs map[string]client.Object
+
+	// sorted caches the sorted names of the jobs. It is rebuilt only if the set has changed.
+	sorted sort.StringSlice
+	dirty  bool
+}
+
+func (s *jobSet) reset() {
+	if s.jobs == nil {
+		s.jobs = make(map[string]client.Object)
+	}
+
+	// the compiler optimizes this loop into a map clear, which retains the allocated buckets.
+	for name := range s.jobs {
+		delete(s.jobs, name)
+	}
+
+	s.sorted = s.sorted[:0]
+	s.dirty = false
+}
+
+func (s *jobSet) add(name string, obj client.Object) {
+	if s.jobs == nil {
+		s.reset()
+	}
+
+	s.jobs[name] = obj
+	s.dirty = true
+}
+
+func (s *jobSet) has(name string) bool {
+	_, ok := s.jobs[name]
+
+	return ok
+}
+
+// names returns the sorted names of the jobs. The slice is owned by the set, and remains valid until the next change.
+func (s *jobSet) names() []string {
+	if s.dirty {
+		s.sorted = s.sorted[:0]
+
+		for name := range s.jobs {
+			s.sorted = append(s.sorted, name)
+		}
+
+		// sorting through a pointer avoids boxing the slice header on every call.
+		sort.Sort(&s.sorted)
+
+		s.dirty = false
+	}
+
+	return s.sorted
+}
+
+// objects returns the jobs with the given names, or all the jobs (sorted by name) if no names are given.
+// The returned slice is owned by the caller.
+func (s *jobSet) objects(jobNames []string) []client.Object {
+	if len(jobNames) == 0 {
+		// if no job names are defined, return everything
+		sorted := s.names()
+		list := make([]client.Object, len(sorted))
+
+		for i, name := range sorted {
+			list[i] = s.jobs[name]
+		}
+
+		return list
+	}
+
+	// otherwise, iterate the list
+	list := make([]client.Object, 0, len(jobNames))
+
+	for _, name := range jobNames {
+		if job, exists := s.jobs[name]; exists {
+			list = append(list, job)
+		}
+	}
+
+	return list
 }
 
 type Convertor func(object client.Object) v1alpha1.Lifecycle
@@ -78,16 +163,16 @@ func (in *Classifier) ClassifyExternal(name string, obj client.Object, conv Conv
 		// Ignore uninitialized/unscheduled jobs
 
 	case v1alpha1.PhasePending:
-		in.pendingJobs[name] = obj
+		in.pendingJobs.add(name, obj)
 
 	case v1alpha1.PhaseSuccess:
-		in.successfulJobs[name] = obj
+		in.successfulJobs.add(name, obj)
 
 	case v1alpha1.PhaseFailed:
-		in.failedJobs[name] = obj
+		in.failedJobs.add(name, obj)
 
 	case v1alpha1.PhaseRunning:
-		in.runningJobs[name] = obj
+		in.runningJobs.add(name, obj)
 
 	default:
 		panic("unhandled lifecycle condition")
@@ -106,9 +191,9 @@ func (in *Classifier) Classify(name string, obj client.Object) {
 		// 3) If they are Running, they are returned by the SystemOK().
 		if v1alpha1.GetComponentLabel(obj) == v1alpha1.ComponentSys {
 			if status.Phase.Is(v1alpha1.PhaseFailed) {
-				in.failedJobs[name] = obj
+				in.failedJobs.add(name, obj)
 			} else {
-				in.systemJobs[name] = obj
+				in.systemJobs.add(name, obj)
 			}
 
 			return
@@ -120,16 +205,16 @@ func (in *Classifier) Classify(name string, obj client.Object) {
 			// Ignore uninitialized/unscheduled jobs
 
 		case v1alpha1.PhasePending:
-			in.pendingJobs[name] = obj
+			in.pendingJobs.add(name, obj)
 
 		case v1alpha1.PhaseSuccess:
-			in.successfulJobs[name] = obj
+			in.successfulJobs.add(name, obj)
 
 		case v1alpha1.PhaseFailed:
-			in.failedJobs[name] = obj
+			in.failedJobs.add(name, obj)
 
 		case v1alpha1.PhaseRunning:
-			in.runningJobs[name] = obj
+			in.runningJobs.add(name, obj)
 
 		default:
 			panic("unhandled lifecycle condition")
@@ -140,7 +225,9 @@ func (in *Classifier) Classify(name string, obj client.Object) {
 }
 
 func (in *Classifier) SystemState() (abort bool, err error) {
-	for _, job := range in.systemJobs {
+	for _, name := range in.systemJobs.names() {
+		job := in.systemJobs.jobs[name]
+
 		statusAware, ok := job.(v1alpha1.ReconcileStatusAware)
 		if !ok {
 			return true, errors.Errorf("job '%s' does not implement status interface", job.GetName())
@@ -165,16 +252,15 @@ func (in *Classifier) SystemState() (abort bool, err error) {
 }
 
 func (in *Classifier) Count() int {
-	return len(in.pendingJobs) +
-		len(in.runningJobs) +
-		len(in.successfulJobs) +
-		len(in.failedJobs)
+	return len(in.pendingJobs.jobs) +
+		len(in.runningJobs.jobs) +
+		len(in.successfulJobs.jobs) +
+		len(in.failedJobs.jobs)
 }
 
 func (in *Classifier) IsPending(job ...string) bool {
 	for _, name := range job {
-		_, ok := in.pendingJobs[name]
-		if !ok {
+		if !in.pendingJobs.has(name) {
 			return false
 		}
 	}
@@ -184,8 +270,7 @@ func (in *Classifier) IsPending(job ...string) bool {
 
 func (in *Classifier) IsRunning(job ...string) bool {
 	for _, name := range job {
-		_, ok := in.runningJobs[name]
-		if !ok {
+		if !in.runningJobs.has(name) {
 			return false
 		}
 	}
@@ -195,8 +280,7 @@ func (in *Classifier) IsRunning(job ...string) bool {
 
 func (in *Classifier) IsSuccessful(job ...string) bool {
 	for _, name := range job {
-		_, ok := in.successfulJobs[name]
-		if !ok {
+		if !in.successfulJobs.has(name) {
 			return false
 		}
 	}
@@ -206,8 +290,7 @@ func (in *Classifier) IsSuccessful(job ...string) bool {
 
 func (in *Classifier) IsFailed(job ...string) bool {
 	for _, name := range job {
-		_, ok := in.failedJobs[name]
-		if !ok {
+		if !in.failedJobs.has(name) {
 			return false
 		}
 	}
@@ -216,19 +299,19 @@ func (in *Classifier) IsFailed(job ...string) bool {
 }
 
 func (in *Classifier) NumPendingJobs() int {
-	return len(in.pendingJobs)
+	return len(in.pendingJobs.jobs)
 }
 
 func (in *Classifier) NumRunningJobs() int {
-	return len(in.runningJobs)
+	return len(in.runningJobs.jobs)
 }
 
 func (in *Classifier) NumSuccessfulJobs() int {
-	return len(in.successfulJobs)
+	return len(in.successfulJobs.jobs)
 }
 
 func (in Classifier) NumFailedJobs() int {
-	return len(in.failedJobs)
+	return len(in.failedJobs.jobs)
 }
 
 func (in *Classifier) NumAll() string {
@@ -241,52 +324,28 @@ func (in *Classifier) NumAll() string {
 	)
 }
 
+// ListPendingJobs returns the sorted names of the pending jobs.
+// The slice is owned by the classifier, and is valid until the next classification.
 func (in *Classifier) ListPendingJobs() []string {
-	list := make([]string, 0, len(in.pendingJobs))
-
-	for jobName := range in.pendingJobs {
-		list = append(list, jobName)
-	}
-
-	sort.Strings(list)
-
-	return list
+	return in.pendingJobs.names()
 }
 
+// ListRunningJobs returns the sorted names of the running jobs.
+// The slice is owned by the classifier, and is valid until the next classification.
 func (in *Classifier) ListRunningJobs() []string {
-	list := make([]string, 0, len(in.runningJobs))
-
-	for jobName := range in.runningJobs {
-		list = append(list, jobName)
-	}
-
-	sort.Strings(list)
-
-	return list
+	return in.runningJobs.names()
 }
 
+// ListSuccessfulJobs returns the sorted names of the successful jobs.
+// The slice is owned by the classifier, and is valid until the next classification.
 func (in *Classifier) ListSuccessfulJobs() []string {
-	list := make([]string, 0, len(in.successfulJobs))
-
-	for jobName := range in.successfulJobs {
-		list = append(list, jobName)
-	}
-
-	sort.Strings(list)
-
-	return list
+	return in.successfulJobs.names()
 }
 
+// ListFailedJobs returns the sorted names of the failed jobs.
+// The slice is owned by the classifier, and is valid until the next classification.
 func (in *Classifier) ListFailedJobs() []string {
-	list := make([]string, 0, len(in.failedJobs))
-
-	for jobName := range in.failedJobs {
-		list = append(list, jobName)
-	}
-
-	sort.Strings(list)
-
-	return list
+	return in.failedJobs.names()
 }
 
 func (in *Classifier) ListAll() string {
@@ -299,86 +358,40 @@ func (in *Classifier) ListAll() string {
 	)
 }
 
-func (in *Classifier) GetPendingJobs(jobNames ...string) []client.Object {
-	list := make([]client.Object, 0, len(in.pendingJobs))
+// PerDomain summarizes the classified jobs per failure domain, as given by the domainOf function.
+func (in *Classifier) PerDomain(domainOf func(job client.Object) string) map[string]v1alpha1.DomainStatus {
+	perDomain := make(map[string]v1alpha1.DomainStatus)
 
-	if len(jobNames) == 0 {
-		// if no job names are defined, return everything
-		for _, job := range in.pendingJobs {
-			list = append(list, job)
-		}
-	} else {
-		// otherwise, iterate the list
-		for _, job := range jobNames {
-			j, exists := in.pendingJobs[job]
-			if exists {
-				list = append(list, j)
-			}
+	count := func(jobs *jobSet, inc func(status *v1alpha1.DomainStatus)) {
+		for _, job := range jobs.jobs {
+			domain := domainOf(job)
+
+			status := perDomain[domain]
+			inc(&status)
+			perDomain[domain] = status
 		}
 	}
 
-	return list
-}
+	count(&in.pendingJobs, func(status *v1alpha1.DomainStatus) { status.PendingJobs++ })
+	count(&in.runningJobs, func(status *v1alpha1.DomainStatus) { status.RunningJobs++ })
+	count(&in.successfulJobs, func(status *v1alpha1.DomainStatus) { status.SuccessfulJobs++ })
+	count(&in.failedJobs, func(status *v1alpha1.DomainStatus) { status.FailedJobs++ })
 
-func (in *Classifier) GetRunningJobs(jobNames ...string) []client.Object {
-	list := make([]client.Object, 0, len(in.runningJobs))
+	return perDomain
+}
 
-	if len(jobNames) == 0 {
-		// if no job names are defined, return everything
-		for _, job := range in.runningJobs {
-			list = append(list, job)
-		}
-	} else {
-		// otherwise, iterate the list
-		for _, job := range jobNames {
-			j, exists := in.runningJobs[job]
-			if exists {
-				list = append(list, j)
-			}
-		}
-	}
+func (in *Classifier) GetPendingJobs(jobNames ...string) []client.Object {
+	return in.pendingJobs.objects(jobNames)
+}
 
-	return list
+func (in *Classifier) GetRunningJobs(jobNames ...string) []client.Object {
+	return in.runningJobs.objects(jobNames)
 }
 
 func (in *Classifier) GetSuccessfulJobs(jobNames ...string) []client.Object {
-	list := make([]client.Object, 0, len(in.successfulJobs))
-
-	if len(jobNames) == 0 {
-		// if no job names are defined, return everything
-		for _, job := range in.successfulJobs {
-			list = append(list, job)
-		}
-	} else {
-		// otherwise, iterate the list
-		for _, job := range jobNames {
-			j, exists := in.successfulJobs[job]
-			if exists {
-				list = append(list, j)
-			}
-		}
-	}
-
-	return list
+	return in.successfulJobs.objects(jobNames)
 }
 
 func (in *Classifier) GetFailedJobs(jobNames ...string) []client.Object {
-	list := make([]client.Object, 0, len(in.failedJobs))
-
-	if len(jobNames) == 0 {
-		// if no job names are defined, return everything
-		for _, job := range in.failedJobs {
-			list = append(list, job)
-		}
-	} else {
-		// otherwise, iterate the list
-		for _, job := range jobNames {
-			j, exists := in.failedJobs[job]
-			if exists {
-				list = append(list, j)
-			}
-		}
-	}
-
-	return list
+	return in.failedJobs.objects(jobNames)
 }
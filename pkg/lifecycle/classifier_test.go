@@ -0,0 +1,125 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newJobs returns services evenly spread across the phases.
+func newJobs(n int) []*v1alpha1.Service {
+	phases := []v1alpha1.Phase{
+		v1alpha1.PhasePending,
+		v1alpha1.PhaseRunning,
+		v1alpha1.PhaseSuccess,
+		v1alpha1.PhaseFailed,
+	}
+
+	jobs := make([]*v1alpha1.Service, n)
+
+	for i := range jobs {
+		jobs[i] = &v1alpha1.Service{ObjectMeta: metav1.ObjectMeta{
+			Name:   fmt.Sprintf("job-%d", i),
+			Labels: map[string]string{v1alpha1.LabelComponent: string(v1alpha1.ComponentSUT)},
+		}}
+		jobs[i].Status.Phase = phases[i%len(phases)]
+	}
+
+	return jobs
+}
+
+// reconcile emulates the use of the classifier in a reconciliation cycle.
+func reconcile(view *lifecycle.Classifier, jobs []*v1alpha1.Service) {
+	view.Reset()
+
+	for _, job := range jobs {
+		view.Classify(job.GetName(), job)
+	}
+
+	_ = view.ListPendingJobs()
+	_ = view.ListRunningJobs()
+	_ = view.ListSuccessfulJobs()
+	_ = view.ListFailedJobs()
+}
+
+func TestClassifier_Order(t *testing.T) {
+	var view lifecycle.Classifier
+
+	reconcile(&view, newJobs(20))
+
+	successful := view.GetSuccessfulJobs()
+	names := view.ListSuccessfulJobs()
+
+	if len(successful) != 5 || len(names) != 5 {
+		t.Fatalf("expected 5 successful jobs, but got %d objects and %d names", len(successful), len(names))
+	}
+
+	for i := range successful {
+		if successful[i].GetName() != names[i] {
+			t.Fatalf("expected '%s' at position %d, but got '%s'", names[i], i, successful[i].GetName())
+		}
+
+		if i > 0 && names[i-1] >= names[i] {
+			t.Fatalf("names are not sorted: %v", names)
+		}
+	}
+
+	// the classification of a new cycle must not leak into the previous one.
+	reconcile(&view, newJobs(4))
+
+	if view.Count() != 4 || !view.IsSuccessful("job-2") || view.IsSuccessful("job-6") {
+		t.Fatalf("stale jobs after reset: %s", view.ListAll())
+	}
+}
+
+// TestClassifier_Allocations guards the per-reconcile allocations. Once warmed up, the classifier reuses its memory.
+func TestClassifier_Allocations(t *testing.T) {
+	var view lifecycle.Classifier
+
+	jobs := newJobs(1000)
+
+	reconcile(&view, jobs)
+
+	allocs := testing.AllocsPerRun(10, func() {
+		reconcile(&view, jobs)
+	})
+
+	if allocs > 0 {
+		t.Fatalf("expected no allocations per reconcile, but got %.0f", allocs)
+	}
+}
+
+func BenchmarkClassifier(b *testing.B) {
+	for _, size := range []int{100, 1000, 10000} {
+		jobs := newJobs(size)
+
+		b.Run(fmt.Sprintf("jobs=%d", size), func(b *testing.B) {
+			var view lifecycle.Classifier
+
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				reconcile(&view, jobs)
+			}
+		})
+	}
+}
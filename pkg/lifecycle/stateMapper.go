@@ -21,8 +21,6 @@ import (
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/pkg/errors"
-	"github.com/r3labs/diff/v3"
-	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -193,17 +191,11 @@ func GroupedJobs(totalJobs int, state ClassifierReader, lf *v1alpha1.Lifecycle,
 	}
 
 	updatedLF, updatedCond := ret()
-	if updatedLF != nil && diff.Changed(lf, updatedLF) {
-		*lf = *updatedLF
-
-		if updatedCond != nil {
-			meta.SetStatusCondition(&lf.Conditions, *updatedCond)
-		}
-
-		return true
+	if updatedLF == nil {
+		return false
 	}
 
-	return false
+	return lf.Transition(*updatedLF, updatedCond)
 }
 
 func SingleJob(state ClassifierReader, lf *v1alpha1.Lifecycle) bool {
@@ -292,18 +284,13 @@ func SingleJob(state ClassifierReader, lf *v1alpha1.Lifecycle) bool {
 
 	for _, testcase := range testSequence {
 		if testcase.expression { // Check if any lifecycle condition is met
-			if diff.Changed(*lf, testcase.lifecycle) { // Update only if there is any change
-				*lf = testcase.lifecycle
-
-				if testcase.condition != (metav1.Condition{}) {
-					meta.SetStatusCondition(&lf.Conditions, testcase.condition)
-				}
-
-				return true
+			var cond *metav1.Condition
+			if testcase.condition != (metav1.Condition{}) {
+				cond = &testcase.condition
 			}
 
-			// do nothing
-			return false
+			// Update only if there is any change
+			return lf.Transition(testcase.lifecycle, cond)
 		}
 	}
 
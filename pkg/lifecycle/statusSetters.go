@@ -23,6 +23,7 @@ import (
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/carv-ics-forth/frisbee/controllers/common"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -44,8 +45,18 @@ func Success(ctx context.Context, reconciler common.Reconciler, obj client.Objec
 		Message: msg,
 	}
 
+	cond := &metav1.Condition{
+		Type:    v1alpha1.ConditionAllJobsAreCompleted.String(),
+		Status:  metav1.ConditionTrue,
+		Reason:  status.Reason,
+		Message: status.Message,
+	}
+
 	if statusAware, updateStatus := obj.(v1alpha1.ReconcileStatusAware); updateStatus {
-		statusAware.SetReconcileStatus(status)
+		// retain the existing conditions.
+		lf := statusAware.GetReconcileStatus()
+		lf.Transition(status, cond)
+		statusAware.SetReconcileStatus(lf)
 
 		reconciler.Info("SetLifecycle",
 			"obj", client.ObjectKeyFromObject(obj),
@@ -79,7 +90,10 @@ func Pending(ctx context.Context, reconciler common.Reconciler, obj client.Objec
 	}
 
 	if statusAware, updateStatus := obj.(v1alpha1.ReconcileStatusAware); updateStatus {
-		statusAware.SetReconcileStatus(status)
+		// retain the existing conditions.
+		lf := statusAware.GetReconcileStatus()
+		lf.Transition(status, nil)
+		statusAware.SetReconcileStatus(lf)
 
 		reconciler.Info("SetLifecycle",
 			"obj", client.ObjectKeyFromObject(obj),
@@ -112,8 +126,18 @@ func Failed(ctx context.Context, reconciler common.Reconciler, obj client.Object
 		Message: issue.Error(),
 	}
 
+	cond := &metav1.Condition{
+		Type:    v1alpha1.ConditionJobUnexpectedTermination.String(),
+		Status:  metav1.ConditionTrue,
+		Reason:  status.Reason,
+		Message: status.Message,
+	}
+
 	if statusAware, updateStatus := obj.(v1alpha1.ReconcileStatusAware); updateStatus {
-		statusAware.SetReconcileStatus(status)
+		// retain the existing conditions.
+		lf := statusAware.GetReconcileStatus()
+		lf.Transition(status, cond)
+		statusAware.SetReconcileStatus(lf)
 
 		reconciler.Info("SetLifecycle",
 			"obj", client.ObjectKeyFromObject(obj),
@@ -0,0 +1,116 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+/*
+	The loggers of the operator share an atomic level, so that the verbosity can be changed at runtime, through the
+	FrisbeeConfig, without restarting the operator. The debug logger bypasses the level, and is used for the
+	scenarios that are explicitly debugged.
+*/
+
+var (
+	lock sync.RWMutex
+
+	// level is the current level of the operator.
+	level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+
+	// defaultLevel is the level that is given by the flags of the operator.
+	defaultLevel = zapcore.InfoLevel
+
+	debugLogger = logr.Discard()
+)
+
+// Level returns the level that is shared by the loggers of the operator.
+func Level() zap.AtomicLevel {
+	return level
+}
+
+// SetDefaultLevel sets the level that is restored when the configuration defines no level.
+func SetDefaultLevel(lvl zapcore.Level) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	defaultLevel = lvl
+	level.SetLevel(lvl)
+}
+
+// SetLevel changes the level of the operator. An empty name restores the default level.
+func SetLevel(name string) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if name == "" {
+		level.SetLevel(defaultLevel)
+
+		return nil
+	}
+
+	lvl, err := ParseLevel(name)
+	if err != nil {
+		return err
+	}
+
+	level.SetLevel(lvl)
+
+	return nil
+}
+
+// ParseLevel accepts 'debug', 'info', 'error', or a positive integer for the verbosity of the logr.V() messages.
+func ParseLevel(name string) (zapcore.Level, error) {
+	switch name {
+	case "debug":
+		return zapcore.DebugLevel, nil
+	case "info":
+		return zapcore.InfoLevel, nil
+	case "error":
+		return zapcore.ErrorLevel, nil
+	}
+
+	verbosity, err := strconv.Atoi(name)
+	if err != nil || verbosity < 0 || verbosity > 127 {
+		return zapcore.InfoLevel, errors.Errorf("invalid log level '%s'. Use debug, info, error, or a verbosity in [0, 127]", name)
+	}
+
+	// logr.V(n) messages are logged at zap level -n.
+	return zapcore.Level(-verbosity), nil
+}
+
+// SetDebugLogger sets the logger of the debugged scenarios.
+func SetDebugLogger(logger logr.Logger) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	debugLogger = logger
+}
+
+// DebugLogger returns the logger of the debugged scenarios. Unlike the other loggers, it ignores the level.
+func DebugLogger() logr.Logger {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	return debugLogger
+}
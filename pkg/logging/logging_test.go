@@ -0,0 +1,73 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging_test
+
+import (
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/pkg/logging"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    zapcore.Level
+		wantErr bool
+	}{
+		{name: "debug", want: zapcore.DebugLevel},
+		{name: "error", want: zapcore.ErrorLevel},
+		{name: "0", want: zapcore.InfoLevel},
+		{name: "5", want: zapcore.Level(-5)},
+		{name: "-1", wantErr: true},
+		{name: "verbose", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := logging.ParseLevel(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLevel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err == nil && got != tt.want {
+				t.Errorf("ParseLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	logging.SetDefaultLevel(zapcore.InfoLevel)
+
+	if err := logging.SetLevel("debug"); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+
+	if got := logging.Level().Level(); got != zapcore.DebugLevel {
+		t.Errorf("Level() = %v, want debug", got)
+	}
+
+	// the empty level restores the level of the flags.
+	if err := logging.SetLevel(""); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+
+	if got := logging.Level().Level(); got != zapcore.InfoLevel {
+		t.Errorf("Level() = %v, want info", got)
+	}
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+/*
+	Policies are organization-wide rules that every object must satisfy in order to be admitted. They are written in
+	CEL (https://github.com/google/cel-spec), and are evaluated by the admission webhooks, with the submitted object
+	bound to the 'object' variable. A rule that evaluates to false, or that cannot be evaluated, denies the object.
+
+	Policies are loaded from the YAML files of a directory. Each file contains a list of policies. For example:
+
+		- name: allowed-images
+		  kinds: ["Service"]
+		  rule: "object.spec.containers.all(c, c.image.startsWith('icsforth/'))"
+		  message: "only images from the icsforth registry are allowed"
+
+		- name: max-chaos-actions
+		  kinds: ["Scenario"]
+		  rule: "size(object.spec.actions.filter(a, a.action == 'Chaos')) <= 3"
+		  message: "a scenario may inject at most 3 faults"
+
+		- name: required-team-label
+		  kinds: ["Scenario"]
+		  rule: "has(object.metadata.labels) && 'team' in object.metadata.labels"
+		  message: "scenarios must be labeled with their team"
+
+	Policies are evaluated when objects are created, and when they are updated (against the updated object), so that
+	admitted objects cannot be edited into violating ones.
+
+	Policies match objects by the kind of the admission request. Because the jobs of a scenario are created through
+	the admission webhooks as well, rules on Service and Chaos apply to the rendered templates of the scenario.
+	The specs embedded in Templates carry no kind, and are checked only when the respective jobs are created.
+*/
+
+// Policy is a rule that the admitted objects must satisfy.
+type Policy struct {
+	// Name identifies the policy in the denial messages.
+	Name string `json:"name"`
+
+	// Kinds limits the policy to the given kinds (e.g, Scenario, Service, Chaos). If empty, the policy applies to
+	// every kind.
+	Kinds []string `json:"kinds,omitempty"`
+
+	// Rule is a CEL expression that must evaluate to true for the object to be admitted.
+	Rule string `json:"rule"`
+
+	// Message explains the denial to the user.
+	Message string `json:"message,omitempty"`
+}
+
+type compiledPolicy struct {
+	Policy
+
+	program cel.Program
+}
+
+// Engine validates objects against a set of compiled policies.
+type Engine struct {
+	policies []compiledPolicy
+}
+
+// Load compiles the policies found in the YAML files of the given directory.
+func Load(dir string) (*Engine, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read policies from '%s'", dir)
+	}
+
+	var policies []Policy
+
+	for _, file := range files {
+		if file.IsDir() || !isYAML(file.Name()) {
+			continue
+		}
+
+		path := filepath.Join(dir, file.Name())
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot read '%s'", path)
+		}
+
+		var filePolicies []Policy
+
+		if err := yaml.Unmarshal(data, &filePolicies); err != nil {
+			return nil, errors.Wrapf(err, "cannot decode '%s'", path)
+		}
+
+		policies = append(policies, filePolicies...)
+	}
+
+	return New(policies)
+}
+
+// New compiles the given policies.
+func New(policies []Policy) (*Engine, error) {
+	env, err := cel.NewEnv(cel.Variable("object", cel.DynType))
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot create cel environment")
+	}
+
+	engine := &Engine{}
+	names := make(map[string]bool, len(policies))
+
+	for _, policy := range policies {
+		if policy.Name == "" {
+			return nil, errors.Errorf("policy with rule '%s' has no name", policy.Rule)
+		}
+
+		if names[policy.Name] {
+			return nil, errors.Errorf("policy '%s' is defined more than once", policy.Name)
+		}
+
+		names[policy.Name] = true
+
+		ast, issues := env.Compile(policy.Rule)
+		if issues != nil && issues.Err() != nil {
+			return nil, errors.Wrapf(issues.Err(), "cannot compile policy '%s'", policy.Name)
+		}
+
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot build policy '%s'", policy.Name)
+		}
+
+		engine.policies = append(engine.policies, compiledPolicy{Policy: policy, program: program})
+	}
+
+	sort.Slice(engine.policies, func(i, j int) bool {
+		return engine.policies[i].Name < engine.policies[j].Name
+	})
+
+	return engine, nil
+}
+
+// Len returns the number of policies in the engine.
+func (e *Engine) Len() int {
+	return len(e.policies)
+}
+
+// Validate evaluates the policies that apply to the kind of the object, and returns an error that lists all the
+// violated policies. Objects without kind are denied, since it is unknown which policies apply to them.
+func (e *Engine) Validate(obj runtime.Object) error {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		return errors.Errorf("cannot match policies to an object without kind")
+	}
+
+	unstructured, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return errors.Wrapf(err, "cannot convert %s to unstructured", kind)
+	}
+
+	var merr *multierror.Error
+
+	for _, policy := range e.policies {
+		if !policy.appliesTo(kind) {
+			continue
+		}
+
+		val, _, err := policy.program.Eval(map[string]interface{}{"object": unstructured})
+		if err != nil {
+			merr = multierror.Append(merr, errors.Errorf("policy '%s' cannot be evaluated: %s", policy.Name, err))
+
+			continue
+		}
+
+		allowed, ok := val.Value().(bool)
+		if !ok {
+			merr = multierror.Append(merr, errors.Errorf("policy '%s' returned '%v' instead of bool", policy.Name, val.Value()))
+
+			continue
+		}
+
+		if !allowed {
+			merr = multierror.Append(merr, errors.Errorf("policy '%s' denied %s: %s", policy.Name, kind, policy.Message))
+		}
+	}
+
+	return merr.ErrorOrNil()
+}
+
+func (p *compiledPolicy) appliesTo(kind string) bool {
+	if len(p.Kinds) == 0 {
+		return true
+	}
+
+	for _, k := range p.Kinds {
+		if strings.EqualFold(k, kind) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isYAML(name string) bool {
+	ext := filepath.Ext(name)
+
+	return ext == ".yaml" || ext == ".yml"
+}
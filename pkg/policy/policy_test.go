@@ -0,0 +1,144 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/policy"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestEngine_Validate(t *testing.T) {
+	engine, err := policy.New([]policy.Policy{
+		{
+			Name:    "required-team-label",
+			Kinds:   []string{"Scenario"},
+			Rule:    "has(object.metadata.labels) && 'team' in object.metadata.labels",
+			Message: "scenarios must be labeled with their team",
+		},
+		{
+			Name:    "max-chaos-actions",
+			Kinds:   []string{"Scenario"},
+			Rule:    "size(object.spec.actions.filter(a, a.action == 'Chaos')) <= 1",
+			Message: "a scenario may inject at most 1 fault",
+		},
+		{
+			Name:    "allowed-images",
+			Kinds:   []string{"Service"},
+			Rule:    "object.spec.containers.all(c, c.image.startsWith('icsforth/'))",
+			Message: "only images from the icsforth registry are allowed",
+		},
+	})
+	if err != nil {
+		t.Fatalf("cannot create engine: %v", err)
+	}
+
+	scenario := func(labels map[string]string, actions ...v1alpha1.ActionType) *v1alpha1.Scenario {
+		obj := &v1alpha1.Scenario{}
+		obj.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("Scenario"))
+		obj.SetName("test")
+		obj.SetLabels(labels)
+
+		for _, actionType := range actions {
+			obj.Spec.Actions = append(obj.Spec.Actions, v1alpha1.Action{ActionType: actionType})
+		}
+
+		return obj
+	}
+
+	tests := []struct {
+		name    string
+		obj     runtime.Object
+		wantErr bool
+	}{
+		{
+			name:    "allowed",
+			obj:     scenario(map[string]string{"team": "storage"}, v1alpha1.ActionService, v1alpha1.ActionChaos),
+			wantErr: false,
+		},
+		{
+			name:    "missing-label",
+			obj:     scenario(nil, v1alpha1.ActionService),
+			wantErr: true,
+		},
+		{
+			name:    "too-many-faults",
+			obj:     scenario(map[string]string{"team": "storage"}, v1alpha1.ActionChaos, v1alpha1.ActionChaos),
+			wantErr: true,
+		},
+		{
+			name:    "no-kind",
+			obj:     &v1alpha1.Scenario{ObjectMeta: metav1.ObjectMeta{Name: "embedded"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := engine.Validate(tt.obj); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNew_InvalidRule(t *testing.T) {
+	if _, err := policy.New([]policy.Policy{{Name: "broken", Rule: "object.spec.("}}); err == nil {
+		t.Error("expected compilation error")
+	}
+}
+
+func TestValidatePolicies_Update(t *testing.T) {
+	engine, err := policy.New([]policy.Policy{
+		{
+			Name:    "required-team-label",
+			Kinds:   []string{"Scenario"},
+			Rule:    "has(object.metadata.labels) && 'team' in object.metadata.labels",
+			Message: "scenarios must be labeled with their team",
+		},
+	})
+	if err != nil {
+		t.Fatalf("cannot create engine: %v", err)
+	}
+
+	v1alpha1.SetPolicyValidator(engine)
+	defer v1alpha1.SetPolicyValidator(nil)
+
+	// the objects of the admission requests may be decoded without kind.
+	old := &v1alpha1.Scenario{ObjectMeta: metav1.ObjectMeta{Name: "test", Labels: map[string]string{"team": "storage"}}}
+
+	if _, err := old.DeepCopy().ValidateUpdate(old); err != nil {
+		t.Errorf("ValidateUpdate() of compliant scenario: error = %v", err)
+	}
+
+	updated := old.DeepCopy()
+	updated.SetLabels(nil)
+
+	if _, err := updated.ValidateUpdate(old); err == nil {
+		t.Error("ValidateUpdate() of violating scenario: expected error")
+	}
+
+	now := metav1.Now()
+	updated.SetDeletionTimestamp(&now)
+
+	if _, err := updated.ValidateUpdate(old); err != nil {
+		t.Errorf("ValidateUpdate() of deleted scenario: error = %v", err)
+	}
+}
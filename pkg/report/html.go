@@ -0,0 +1,187 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package report renders the telemetry of a test into a self-contained HTML file. The snapshots of the
+// panels are embedded as PNG images, and the data of the panels as tables, so that the file can be shared
+// without access to Grafana.
+package report
+
+import (
+	"bytes"
+	"encoding/base64"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/pkg/grafana"
+	"github.com/pkg/errors"
+)
+
+// MaxTableRows limits the rows of each table that are shown in the report. The complete table is
+// still available as an embedded CSV.
+var MaxTableRows = 200
+
+// Report describes the content of the HTML report.
+type Report struct {
+	// Test is the name of the test.
+	Test string
+
+	// Scenario is the name of the scenario, and Phase its final phase.
+	Scenario string
+	Phase    string
+
+	// From and To is the time range of the report.
+	From time.Time
+	To   time.Time
+
+	// Generated is when the report was generated.
+	Generated time.Time
+
+	Dashboards []Dashboard
+}
+
+// Dashboard is a section of the report.
+type Dashboard struct {
+	UID string
+
+	Panels []Panel
+}
+
+// Panel is a Grafana panel, with its snapshot and its data.
+type Panel struct {
+	Title string
+
+	// Snapshot is the PNG image of the panel.
+	Snapshot []byte
+
+	// SnapshotError explains why the snapshot is missing.
+	SnapshotError string
+
+	Tables []grafana.Frame
+}
+
+var funcs = template.FuncMap{
+	// png embeds the image into the report. The data are base64-encoded, and thereby safe.
+	"png": func(data []byte) template.URL {
+		return template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(data))
+	},
+
+	// csv embeds the table into the report, for downloading.
+	"csv": func(frame grafana.Frame) (template.URL, error) {
+		var buf bytes.Buffer
+
+		if err := frame.WriteCSV(&buf); err != nil {
+			return "", err
+		}
+
+		return template.URL("data:text/csv;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())), nil
+	},
+
+	"head": func(rows [][]string) [][]string {
+		if len(rows) > MaxTableRows {
+			return rows[:MaxTableRows]
+		}
+
+		return rows
+	},
+
+	"omitted": func(rows [][]string) int {
+		if len(rows) > MaxTableRows {
+			return len(rows) - MaxTableRows
+		}
+
+		return 0
+	},
+
+	"timestamp": func(t time.Time) string {
+		return t.UTC().Format(time.RFC3339)
+	},
+}
+
+var page = template.Must(template.New("report").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Frisbee Report: {{ .Test }}</title>
+<style>
+  body { font-family: sans-serif; margin: 2em auto; max-width: 1100px; color: #222; }
+  header { border-bottom: 1px solid #ccc; margin-bottom: 1em; }
+  nav ul { columns: 2; }
+  section.panel { margin: 2em 0; }
+  img { max-width: 100%; border: 1px solid #ddd; }
+  table { border-collapse: collapse; font-size: 0.8em; margin: 0.5em 0; }
+  th, td { border: 1px solid #ddd; padding: 2px 6px; text-align: right; }
+  th { background: #f4f4f4; }
+  .missing { color: #a00; font-style: italic; }
+</style>
+</head>
+<body>
+<header>
+  <h1>{{ .Test }}</h1>
+  <p>
+    Scenario: <b>{{ .Scenario }}</b> ({{ .Phase }})<br>
+    Time range: {{ timestamp .From }} &ndash; {{ timestamp .To }}<br>
+    Generated: {{ timestamp .Generated }}
+  </p>
+  <nav>
+    <ul>
+    {{- range $d := .Dashboards }}
+      <li><a href="#{{ $d.UID }}">{{ $d.UID }}</a></li>
+    {{- end }}
+    </ul>
+  </nav>
+</header>
+{{- range $d := .Dashboards }}
+<article id="{{ $d.UID }}">
+  <h2>Dashboard: {{ $d.UID }}</h2>
+  {{- range $p := $d.Panels }}
+  <section class="panel">
+    <h3>{{ $p.Title }}</h3>
+    {{- if $p.Snapshot }}
+    <img src="{{ png $p.Snapshot }}" alt="{{ $p.Title }}">
+    {{- else if $p.SnapshotError }}
+    <p class="missing">No snapshot: {{ $p.SnapshotError }}</p>
+    {{- end }}
+    {{- range $t := $p.Tables }}
+    <details>
+      <summary>{{ $t.Name }} ({{ len $t.Rows }} rows) &ndash; <a href="{{ csv $t }}" download="{{ $t.Name }}.csv">csv</a></summary>
+      <table>
+        <tr>{{ range $t.Fields }}<th>{{ . }}</th>{{ end }}</tr>
+        {{- range head $t.Rows }}
+        <tr>{{ range . }}<td>{{ . }}</td>{{ end }}</tr>
+        {{- end }}
+      </table>
+      {{- with omitted $t.Rows }}
+      <p>{{ . }} more rows are available in the csv.</p>
+      {{- end }}
+    </details>
+    {{- end }}
+  </section>
+  {{- end }}
+</article>
+{{- end }}
+</body>
+</html>
+`))
+
+// WriteHTML renders the report into a self-contained HTML page.
+func WriteHTML(w io.Writer, report *Report) error {
+	if err := page.Execute(w, report); err != nil {
+		return errors.Wrapf(err, "cannot render html report")
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/pkg/grafana"
+	"github.com/carv-ics-forth/frisbee/pkg/report"
+)
+
+func TestWriteHTML(t *testing.T) {
+	defaultRows := report.MaxTableRows
+	defer func() { report.MaxTableRows = defaultRows }()
+
+	report.MaxTableRows = 1
+
+	r := &report.Report{
+		Test:     "demo-1",
+		Scenario: "scenario",
+		Phase:    "Success",
+		From:     time.Unix(0, 0),
+		To:       time.Unix(60, 0),
+		Dashboards: []report.Dashboard{{
+			UID: "summary",
+			Panels: []report.Panel{
+				{
+					Title:    "Throughput",
+					Snapshot: []byte("png"),
+					Tables: []grafana.Frame{{
+						Name:   "A",
+						Fields: []string{"Time", "<b>Value</b>"},
+						Rows:   [][]string{{"t0", "1"}, {"t1", "2"}},
+					}},
+				},
+				{Title: "Latency", SnapshotError: "no renderer"},
+			},
+		}},
+	}
+
+	var out bytes.Buffer
+
+	if err := report.WriteHTML(&out, r); err != nil {
+		t.Fatalf("WriteHTML() error = %v", err)
+	}
+
+	html := out.String()
+
+	for _, expected := range []string{
+		"<title>Frisbee Report: demo-1</title>",
+		`<img src="data:image/png;base64,cG5n" alt="Throughput">`,
+		"data:text/csv;base64,",
+		"&lt;b&gt;Value&lt;/b&gt;",
+		"<td>t0</td>",
+		"1 more rows are available in the csv.",
+		"No snapshot: no renderer",
+		"1970-01-01T00:01:00Z",
+	} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("report does not contain %q", expected)
+		}
+	}
+
+	if strings.Contains(html, "<td>t1</td>") {
+		t.Error("report should omit the rows beyond the limit")
+	}
+}
@@ -31,6 +31,9 @@ import (
 )
 
 type Parameters struct {
+	// Now is the current time, as seen by the controller's clock.
+	Now time.Time
+
 	// State is the real state of the system.
 	State lifecycle.Classifier
 
@@ -41,10 +44,10 @@ type Parameters struct {
 	ScheduleSpec *v1alpha1.TaskSchedulerSpec
 
 	//
-	// Parameters Used for Timeline mode
+	// Parameters Used for Timeline and Trace modes
 	//
 
-	// ExpectedTime is the evaluation of a timeline distribution defined in the ScheduleSpec.
+	// ExpectedTime is the evaluation of a timeline distribution, or the arrival times of a trace, defined in the ScheduleSpec.
 	ExpectedTimeline v1alpha1.Timeline
 
 	//
@@ -59,6 +62,13 @@ type Parameters struct {
 	// In this case, we use it in conjunction with JobName to find the children's name,
 	// and from that to extract the status of the child.
 	ScheduledJobs int `json:"scheduledJobs,omitempty"`
+
+	//
+	// Parameters Used for Ramp mode
+	//
+
+	// QueuedJobs is the total number of jobs, over which the ramp is spread.
+	QueuedJobs int
 }
 
 // Schedule calculate the next scheduled run, and whether we've got a run that we haven't processed yet  (or anything we missed).
@@ -105,6 +115,31 @@ func Schedule(log logr.Logger, obj client.Object, params Parameters) (goToNextJo
 		return !missed.IsZero(), fixedTick, err
 	}
 
+	// Ramp-based scheduling
+	if params.ScheduleSpec.Ramp != nil {
+		due := rampDue(obj, params)
+
+		if due.After(params.Now) {
+			return false, due, nil
+		}
+
+		return true, time.Time{}, nil
+	}
+
+	// Trace-based scheduling
+	if params.ScheduleSpec.Trace != nil {
+		due, exists := traceDue(params)
+		if !exists {
+			return false, time.Time{}, nil
+		}
+
+		if due.After(params.Now) {
+			return false, due, nil
+		}
+
+		return true, time.Time{}, nil
+	}
+
 	// Event-based scheduling
 	if !params.ScheduleSpec.Event.IsZero() {
 		eval := expressions.Condition{Expr: params.ScheduleSpec.Event}
@@ -154,6 +189,28 @@ func timelineWithDeadline(_ logr.Logger, obj client.Object, params Parameters) (
 	return lastMissed, next, nil
 }
 
+// rampDue returns the time at which the next job is due, according to the ramp.
+// Unlike the timeline, jobs that are due at the same time are scheduled back-to-back.
+func rampDue(obj client.Object, params Parameters) time.Time {
+	// ScheduledJobs points to the last scheduled job, starting from -1.
+	nextJob := params.ScheduledJobs + 2
+
+	return obj.GetCreationTimestamp().Add(params.ScheduleSpec.Ramp.At(nextJob, params.QueuedJobs))
+}
+
+// traceDue returns the time at which the next job is due, according to the trace.
+// Like the ramp, jobs that arrive at the same time are scheduled back-to-back.
+func traceDue(params Parameters) (time.Time, bool) {
+	// ScheduledJobs points to the last scheduled job, starting from -1.
+	nextJob := params.ScheduledJobs + 1
+
+	if nextJob < 0 || nextJob >= len(params.ExpectedTimeline) {
+		return time.Time{}, false
+	}
+
+	return params.ExpectedTimeline[nextJob].Time, true
+}
+
 // Timeline describes a job's duty cycle.
 type Timeline interface {
 	// Next returns the next activation time, later than the given time.
@@ -172,7 +229,7 @@ type Timeline interface {
 // Otherwise, we'll just return the missed runs (of which we'll just use the latest),
 // and the next run, so that we can know when it's time to reconcile again.
 func getNextScheduleTime(earliest time.Time, timeline Timeline, params Parameters) (lastMissed time.Time, next time.Time, err error) {
-	now := time.Now()
+	now := params.Now
 
 	var earliestTime time.Time
 
@@ -252,3 +309,43 @@ func honorDeadline(log logr.Logger, lastMissed time.Time, deadline *int64) bool
 	return tooLate
 }
 */
+
+// ExpectedCompletion returns the time at which the last of the remaining jobs is expected to be scheduled,
+// according to a time-based schedule (Cron or Timeline). For other kinds of schedules, it returns zero time.
+func ExpectedCompletion(obj client.Object, params Parameters, remainingJobs int) (time.Time, error) {
+	switch {
+	case params.ScheduleSpec == nil:
+		return time.Time{}, nil
+
+	case params.ScheduleSpec.Cron != nil:
+		timeline, err := cron.ParseStandard(*params.ScheduleSpec.Cron)
+		if err != nil {
+			return time.Time{}, errors.Wrapf(err, "unparseable timeline %q", *params.ScheduleSpec.Cron)
+		}
+
+		tick := params.LastScheduleTime.Time
+		if tick.IsZero() {
+			tick = obj.GetCreationTimestamp().Time
+		}
+
+		for i := 0; i < remainingJobs; i++ {
+			tick = timeline.Next(tick)
+		}
+
+		return tick, nil
+
+	case params.ScheduleSpec.Timeline != nil, params.ScheduleSpec.Trace != nil:
+		// the timeline is already evaluated into specific points in time.
+		if len(params.ExpectedTimeline) == 0 {
+			return time.Time{}, nil
+		}
+
+		return params.ExpectedTimeline[len(params.ExpectedTimeline)-1].Time, nil
+
+	case params.ScheduleSpec.Ramp != nil:
+		return obj.GetCreationTimestamp().Add(params.ScheduleSpec.Ramp.At(params.QueuedJobs, params.QueuedJobs)), nil
+
+	default:
+		return time.Time{}, nil
+	}
+}
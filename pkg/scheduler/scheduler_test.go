@@ -0,0 +1,186 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/scheduler"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestSchedule_Cron(t *testing.T) {
+	created := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clk := testingclock.NewFakeClock(created)
+
+	var obj v1alpha1.Cluster
+	obj.SetCreationTimestamp(metav1.NewTime(created))
+
+	cron := "*/5 * * * *"
+	params := scheduler.Parameters{ScheduleSpec: &v1alpha1.TaskSchedulerSpec{Cron: &cron}}
+
+	tests := []struct {
+		advance  time.Duration
+		wantJob  bool
+		wantNext time.Time
+		wantErr  bool
+	}{
+		{advance: time.Minute, wantJob: false, wantNext: created.Add(5 * time.Minute)},
+		{advance: 4 * time.Minute, wantJob: true, wantNext: created.Add(10 * time.Minute)},
+		{advance: 3 * time.Minute, wantJob: false, wantNext: created.Add(10 * time.Minute)},
+		// a controller that wakes up after a day has missed too many starts.
+		{advance: 24 * time.Hour, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		clk.Step(tt.advance)
+		params.Now = clk.Now()
+
+		hasJob, next, err := scheduler.Schedule(logr.Discard(), &obj, params)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("at %s: got error %v, want error %t", params.Now, err, tt.wantErr)
+		}
+
+		if tt.wantErr {
+			continue
+		}
+
+		if hasJob != tt.wantJob || !next.Equal(tt.wantNext) {
+			t.Errorf("at %s: got (%t, %s), want (%t, %s)", params.Now, hasJob, next, tt.wantJob, tt.wantNext)
+		}
+
+		if hasJob {
+			params.LastScheduleTime = metav1.NewTime(params.Now)
+		}
+	}
+}
+
+func TestSchedule_Ramp(t *testing.T) {
+	created := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	var obj v1alpha1.Cluster
+	obj.SetCreationTimestamp(metav1.NewTime(created))
+
+	// 7 jobs in waves of 1, 2, and 4 jobs, at 0, 5, and 10 minutes.
+	ramp := &v1alpha1.RampSpec{Profile: v1alpha1.RampExponential, Duration: metav1.Duration{Duration: 10 * time.Minute}}
+	params := scheduler.Parameters{
+		ScheduleSpec:  &v1alpha1.TaskSchedulerSpec{Ramp: ramp},
+		ScheduledJobs: -1,
+		QueuedJobs:    7,
+	}
+
+	tests := []struct {
+		now      time.Duration
+		wantJobs int
+		wantNext time.Duration
+	}{
+		{now: 0, wantJobs: 1, wantNext: 5 * time.Minute},
+		{now: 4 * time.Minute, wantJobs: 1, wantNext: 5 * time.Minute},
+		{now: 5 * time.Minute, wantJobs: 3, wantNext: 10 * time.Minute},
+		{now: 11 * time.Minute, wantJobs: 7},
+	}
+
+	for _, tt := range tests {
+		params.Now = created.Add(tt.now)
+
+		// schedule as many jobs as due, back-to-back.
+		for params.ScheduledJobs+1 < params.QueuedJobs {
+			hasJob, next, err := scheduler.Schedule(logr.Discard(), &obj, params)
+			if err != nil {
+				t.Fatalf("at %s: unexpected error %v", tt.now, err)
+			}
+
+			if !hasJob {
+				if !next.Equal(created.Add(tt.wantNext)) {
+					t.Errorf("at %s: got next %s, want %s", tt.now, next, created.Add(tt.wantNext))
+				}
+
+				break
+			}
+
+			params.ScheduledJobs++
+		}
+
+		if got := params.ScheduledJobs + 1; got != tt.wantJobs {
+			t.Errorf("at %s: got %d jobs, want %d", tt.now, got, tt.wantJobs)
+		}
+	}
+}
+
+func TestSchedule_Trace(t *testing.T) {
+	created := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	var obj v1alpha1.Cluster
+	obj.SetCreationTimestamp(metav1.NewTime(created))
+
+	// the records are unordered, and two of them arrive at the same time.
+	trace, err := v1alpha1.ParseTrace("timestamp,size\n1672531260,20\n1672531200,10\n1672531260,30\n")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if got := trace.Inputs()[0]["size"]; string(got.Raw) != `"10"` {
+		t.Errorf("got first input %s, want \"10\"", got.Raw)
+	}
+
+	params := scheduler.Parameters{
+		ScheduleSpec:     &v1alpha1.TaskSchedulerSpec{Trace: &v1alpha1.TraceSpec{}},
+		ExpectedTimeline: trace.Timeline(obj.GetCreationTimestamp()),
+		ScheduledJobs:    -1,
+		QueuedJobs:       len(trace),
+	}
+
+	tests := []struct {
+		now      time.Duration
+		wantJobs int
+		wantNext time.Duration
+	}{
+		{now: 0, wantJobs: 1, wantNext: time.Minute},
+		{now: 30 * time.Second, wantJobs: 1, wantNext: time.Minute},
+		{now: time.Minute, wantJobs: 3},
+	}
+
+	for _, tt := range tests {
+		params.Now = created.Add(tt.now)
+
+		// schedule as many jobs as due, back-to-back.
+		for params.ScheduledJobs+1 < params.QueuedJobs {
+			hasJob, next, err := scheduler.Schedule(logr.Discard(), &obj, params)
+			if err != nil {
+				t.Fatalf("at %s: unexpected error %v", tt.now, err)
+			}
+
+			if !hasJob {
+				if !next.Equal(created.Add(tt.wantNext)) {
+					t.Errorf("at %s: got next %s, want %s", tt.now, next, created.Add(tt.wantNext))
+				}
+
+				break
+			}
+
+			params.ScheduledJobs++
+		}
+
+		if got := params.ScheduledJobs + 1; got != tt.wantJobs {
+			t.Errorf("at %s: got %d jobs, want %d", tt.now, got, tt.wantJobs)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/controllers/chaos"
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+/*
+	The fake Chaos-Mesh injects a fault as soon as it is created, and recovers it when spec.duration expires.
+	Faults without a duration remain injected until they are deleted. The faults do not affect the targets.
+*/
+
+// InjectedAnnotation records the simulated time at which a fault was injected.
+const InjectedAnnotation = "simulator.frisbee.dev/injected-at"
+
+var faultKinds = []schema.GroupVersionKind{
+	chaos.NetworkChaosGVK,
+	chaos.PodChaosGVK,
+	chaos.IOChaosGVK,
+	chaos.KernelChaosGVK,
+	chaos.TimeChaosGVK,
+	chaos.StressChaosGVK,
+}
+
+// chaosCRDs returns schemaless CRDs for the faults supported by Frisbee.
+func chaosCRDs() []*apiextensionsv1.CustomResourceDefinition {
+	preserve := true
+
+	crds := make([]*apiextensionsv1.CustomResourceDefinition, 0, len(faultKinds))
+
+	for _, gvk := range faultKinds {
+		plural := strings.ToLower(gvk.Kind)
+
+		crds = append(crds, &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: plural + "." + gvk.Group},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: gvk.Group,
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural:   plural,
+					Singular: plural,
+					Kind:     gvk.Kind,
+					ListKind: gvk.Kind + "List",
+				},
+				Scope: apiextensionsv1.NamespaceScoped,
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+					Name:    gvk.Version,
+					Served:  true,
+					Storage: true,
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+							Type:                   "object",
+							XPreserveUnknownFields: &preserve,
+						},
+					},
+				}},
+			},
+		})
+	}
+
+	return crds
+}
+
+// runFaults moves the faults of the namespace through their lifecycle.
+func (s *Simulator) runFaults(ctx context.Context, namespace string) error {
+	now := s.clock.Now()
+
+	for _, gvk := range faultKinds {
+		var faults unstructured.UnstructuredList
+
+		faults.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+		if err := s.client.List(ctx, &faults, client.InNamespace(namespace)); err != nil {
+			return errors.Wrapf(err, "cannot list '%s'", gvk.Kind)
+		}
+
+		for i := range faults.Items {
+			fault := &faults.Items[i]
+
+			changed, err := stepFault(fault, now)
+			if err != nil {
+				return err
+			}
+
+			if !changed {
+				continue
+			}
+
+			if err := s.client.Update(ctx, fault); client.IgnoreNotFound(err) != nil {
+				return errors.Wrapf(err, "cannot update '%s'", fault.GetName())
+			}
+		}
+	}
+
+	return nil
+}
+
+// stepFault advances the status of the fault to the given time. It returns false if the status is not changed.
+func stepFault(fault *unstructured.Unstructured, now time.Time) (bool, error) {
+	annotations := fault.GetAnnotations()
+
+	injectedAt, injected := annotations[InjectedAnnotation]
+	if !injected {
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+
+		annotations[InjectedAnnotation] = now.Format(time.RFC3339Nano)
+		fault.SetAnnotations(annotations)
+
+		return true, setFaultStatus(fault, chaos.RunningPhase, true)
+	}
+
+	phase, _, _ := unstructured.NestedString(fault.Object, "status", "experiment", "desiredPhase")
+	if chaos.DesiredPhase(phase).Stop() {
+		return false, nil
+	}
+
+	duration, exists, err := unstructured.NestedString(fault.Object, "spec", "duration")
+	if err != nil || !exists {
+		return false, nil //nolint:nilerr
+	}
+
+	parsedDuration, err := time.ParseDuration(duration)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid duration for fault '%s'", fault.GetName())
+	}
+
+	start, err := time.Parse(time.RFC3339Nano, injectedAt)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid injection time for fault '%s'", fault.GetName())
+	}
+
+	if now.Before(start.Add(parsedDuration)) {
+		return false, nil
+	}
+
+	return true, setFaultStatus(fault, chaos.StoppedPhase, false)
+}
+
+func setFaultStatus(fault *unstructured.Unstructured, phase chaos.DesiredPhase, injected bool) error {
+	condition := func(conditionType chaos.ConditionType, value bool) interface{} {
+		status := "False"
+		if value {
+			status = "True"
+		}
+
+		return map[string]interface{}{"type": string(conditionType), "status": status}
+	}
+
+	status := map[string]interface{}{
+		"experiment": map[string]interface{}{"desiredPhase": string(phase)},
+		"conditions": []interface{}{
+			condition(chaos.ConditionSelected, true),
+			condition(chaos.ConditionAllInjected, injected),
+			condition(chaos.ConditionAllRecovered, !injected),
+			condition(chaos.ConditionPaused, false),
+		},
+	}
+
+	return unstructured.SetNestedField(fault.Object, status, "status")
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/pkg/grafana"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// grafanaStub accepts every request of the Grafana clients, so that the annotations and the alerts of the
+// controllers succeed without a real Grafana.
+type grafanaStub struct {
+	*httptest.Server
+}
+
+func newGrafanaStub() *grafanaStub {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/api/health":
+			_, _ = w.Write([]byte(`{"commit":"simulator","database":"ok","version":"9.4.7"}`))
+
+		case strings.HasPrefix(r.URL.Path, "/api/annotations"):
+			_, _ = w.Write([]byte(`{"id":1,"message":"ok"}`))
+
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+
+	return &grafanaStub{Server: httptest.NewServer(handler)}
+}
+
+// RegisterFor connects a Grafana client of the given scenario to the stub.
+func (g *grafanaStub) RegisterFor(ctx context.Context, scenario *v1alpha1.Scenario, logger logr.Logger) error {
+	// the clients are indexed by the scenario label, that is set by the controller after the creation.
+	key := metav1.ObjectMeta{Namespace: scenario.GetNamespace()}
+	v1alpha1.SetScenarioLabel(&key, scenario.GetName())
+
+	if grafana.HasClientFor(&key) {
+		return nil
+	}
+
+	// the client is registered with the endpoint that the controller expects, but its requests reach the stub.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+
+		return dialer.DialContext(ctx, network, g.Listener.Addr().String())
+	}
+
+	_, err := grafana.New(ctx,
+		grafana.WithHTTP(common.InternalEndpoint(common.DefaultGrafanaServiceName, scenario.GetNamespace(), common.DefaultGrafanaPort)),
+		grafana.WithTransport(transport),
+		grafana.WithRegisterFor(&key),
+		grafana.WithLogger(logger),
+	)
+
+	return err
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+/*
+	The envtest has neither a scheduler nor kubelets. The fake kubelet runs every Pod as soon as it is created,
+	and terminates it after the duration decided by the Behavior function.
+*/
+
+const (
+	// DurationAnnotation sets the simulated running time of a Pod (e.g, 30s). Pods without a duration run until
+	// they are deleted.
+	DurationAnnotation = "simulator.frisbee.dev/duration"
+
+	// ExitCodeAnnotation sets the exit code of the main container when the duration expires.
+	ExitCodeAnnotation = "simulator.frisbee.dev/exit-code"
+)
+
+// Behavior describes how a Pod runs in the simulation.
+type Behavior struct {
+	// Duration is the simulated running time. Zero means that the Pod runs until it is deleted.
+	Duration time.Duration
+
+	// ExitCode is the exit code of the main container.
+	ExitCode int32
+}
+
+// BehaviorFunc decides the behavior of a Pod.
+type BehaviorFunc func(pod *corev1.Pod) (Behavior, error)
+
+// BehaviorFromAnnotations decides the behavior of a Pod from the simulator annotations. The annotations can be set
+// on the templates via decorators.annotations.
+func BehaviorFromAnnotations(pod *corev1.Pod) (Behavior, error) {
+	var behavior Behavior
+
+	annotations := pod.GetAnnotations()
+
+	if value, exists := annotations[DurationAnnotation]; exists {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return behavior, errors.Wrapf(err, "invalid duration for pod '%s'", pod.GetName())
+		}
+
+		behavior.Duration = duration
+	}
+
+	if value, exists := annotations[ExitCodeAnnotation]; exists {
+		exitCode, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return behavior, errors.Wrapf(err, "invalid exit code for pod '%s'", pod.GetName())
+		}
+
+		behavior.ExitCode = int32(exitCode)
+	}
+
+	return behavior, nil
+}
+
+// runPods moves the Pods of the namespace through their lifecycle.
+func (s *Simulator) runPods(ctx context.Context, namespace string) error {
+	var pods corev1.PodList
+
+	if err := s.client.List(ctx, &pods, client.InNamespace(namespace)); err != nil {
+		return errors.Wrapf(err, "cannot list pods")
+	}
+
+	now := metav1.NewTime(s.clock.Now())
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+
+		// without a kubelet, nobody confirms the termination of the Pod.
+		if !pod.GetDeletionTimestamp().IsZero() {
+			if err := s.client.Delete(ctx, pod, client.GracePeriodSeconds(0)); client.IgnoreNotFound(err) != nil {
+				return errors.Wrapf(err, "cannot delete pod '%s'", pod.GetName())
+			}
+
+			continue
+		}
+
+		behavior, err := s.options.Behavior(pod)
+		if err != nil {
+			return err
+		}
+
+		if !stepPod(pod, behavior, now) {
+			continue
+		}
+
+		if err := s.client.Status().Update(ctx, pod); client.IgnoreNotFound(err) != nil {
+			return errors.Wrapf(err, "cannot update pod '%s'", pod.GetName())
+		}
+	}
+
+	return nil
+}
+
+// stepPod advances the status of the Pod to the given time. It returns false if the status is not changed.
+func stepPod(pod *corev1.Pod, behavior Behavior, now metav1.Time) bool {
+	switch pod.Status.Phase {
+	case "", corev1.PodPending:
+		pod.Status.Phase = corev1.PodRunning
+		pod.Status.StartTime = &now
+		pod.Status.ContainerStatuses = make([]corev1.ContainerStatus, 0, len(pod.Spec.Containers))
+
+		for _, container := range pod.Spec.Containers {
+			pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+				Name:    container.Name,
+				Image:   container.Image,
+				Ready:   true,
+				Started: pointer.Bool(true),
+				State:   corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: now}},
+			})
+		}
+
+		pod.Status.Conditions = []corev1.PodCondition{
+			{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: now},
+		}
+
+		return true
+
+	case corev1.PodRunning:
+		if behavior.Duration == 0 || pod.Status.StartTime == nil ||
+			now.Time.Before(pod.Status.StartTime.Add(behavior.Duration)) {
+			return false
+		}
+
+		pod.Status.Phase = corev1.PodSucceeded
+		if behavior.ExitCode != 0 {
+			pod.Status.Phase = corev1.PodFailed
+		}
+
+		for i, status := range pod.Status.ContainerStatuses {
+			var exitCode int32
+
+			if status.Name == v1alpha1.MainContainerName {
+				exitCode = behavior.ExitCode
+			}
+
+			pod.Status.ContainerStatuses[i].Ready = false
+			pod.Status.ContainerStatuses[i].State = corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{
+					ExitCode:   exitCode,
+					StartedAt:  *pod.Status.StartTime,
+					FinishedAt: now,
+				},
+			}
+		}
+
+		pod.Status.Conditions = []corev1.PodCondition{
+			{Type: corev1.PodReady, Status: corev1.ConditionFalse, LastTransitionTime: now},
+		}
+
+		return true
+
+	default:
+		return false
+	}
+}
@@ -0,0 +1,542 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package simulator runs the Frisbee controllers against an in-process API server (envtest), so that scenario authors
+can check the execution of a scenario (ordering of actions, schedules, until-conditions) in seconds, without
+a real cluster.
+
+The simulation is driven by a fake clock. Every tick advances the clock, and pokes the backends that the envtest
+lacks: a fake kubelet that runs the Pods, a fake Chaos-Mesh that injects the faults, and a fake Grafana that accepts
+the annotations. Metrics-based conditions are never fired, since there is no real telemetry.
+
+The envtest binaries (etcd, kube-apiserver) are located via KUBEBUILDER_ASSETS or Options.BinaryAssetsDirectory.
+*/
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/call"
+	"github.com/carv-ics-forth/frisbee/controllers/cascade"
+	"github.com/carv-ics-forth/frisbee/controllers/chaos"
+	"github.com/carv-ics-forth/frisbee/controllers/cluster"
+	"github.com/carv-ics-forth/frisbee/controllers/scenario"
+	"github.com/carv-ics-forth/frisbee/controllers/service"
+	"github.com/carv-ics-forth/frisbee/controllers/template"
+	"github.com/carv-ics-forth/frisbee/pkg/clock"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	testingclock "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+const (
+	// DefaultStep is the simulated time that elapses on every tick.
+	DefaultStep = time.Second
+
+	// DefaultSettle is the real time given to the controllers for reacting to a tick.
+	DefaultSettle = 50 * time.Millisecond
+
+	// DefaultTimeout is the maximum simulated duration of a scenario.
+	DefaultTimeout = time.Hour
+
+	// TickAnnotation is updated on every tick, in order to trigger the reconciliation of the active objects.
+	TickAnnotation = "simulator.frisbee.dev/tick"
+)
+
+type Options struct {
+	// CRDDirectoryPaths points to the Frisbee CRDs (e.g, charts/platform/crds).
+	CRDDirectoryPaths []string
+
+	// BinaryAssetsDirectory points to the envtest binaries. If empty, KUBEBUILDER_ASSETS is used.
+	BinaryAssetsDirectory string
+
+	// Step is the simulated time that elapses on every tick.
+	Step time.Duration
+
+	// Settle is the real time given to the controllers for reacting to a tick.
+	Settle time.Duration
+
+	// Timeout is the maximum simulated duration of a scenario.
+	Timeout time.Duration
+
+	// Behavior decides how long a Pod runs and how it exits. If nil, it is decided by the Pod annotations.
+	Behavior BehaviorFunc
+
+	Logger logr.Logger
+}
+
+// Event is a phase transition of an action, in simulated time.
+type Event struct {
+	// At is the simulated time since the submission of the scenario.
+	At time.Duration
+
+	// Wall is the real time since the submission of the scenario.
+	Wall time.Duration
+
+	// Action is the name of the action. It is empty for the transitions of the scenario.
+	Action string
+
+	// Phase is the new phase.
+	Phase v1alpha1.Phase
+}
+
+func (e Event) String() string {
+	if e.Action == "" {
+		return fmt.Sprintf("%s\tscenario\t%s", e.At, e.Phase)
+	}
+
+	return fmt.Sprintf("%s\t%s\t%s", e.At, e.Action, e.Phase)
+}
+
+// Result is the outcome of a simulation.
+type Result struct {
+	// Scenario is the final state of the scenario.
+	Scenario *v1alpha1.Scenario
+
+	// Duration is the simulated duration of the scenario.
+	Duration time.Duration
+
+	// Wall is the real duration of the scenario.
+	Wall time.Duration
+
+	// Timeline lists the phase transitions of the scenario and its actions, in the order they were observed.
+	Timeline []Event
+}
+
+// Simulator runs the Frisbee controllers in-process.
+type Simulator struct {
+	options Options
+
+	env    *envtest.Environment
+	client client.Client
+	clock  *testingclock.FakeClock
+
+	grafana *grafanaStub
+
+	cancel context.CancelFunc
+	done   chan error
+
+	ticks int
+
+	// requests counts the requests of the controllers to the API server.
+	requests int64
+}
+
+// New returns a simulator. Use Start to bring up the API server and the controllers.
+func New(options Options) (*Simulator, error) {
+	if len(options.CRDDirectoryPaths) == 0 {
+		return nil, errors.Errorf("the paths of the Frisbee CRDs are required")
+	}
+
+	if options.Step == 0 {
+		options.Step = DefaultStep
+	}
+
+	if options.Settle == 0 {
+		options.Settle = DefaultSettle
+	}
+
+	if options.Timeout == 0 {
+		options.Timeout = DefaultTimeout
+	}
+
+	if options.Behavior == nil {
+		options.Behavior = BehaviorFromAnnotations
+	}
+
+	if options.Logger == (logr.Logger{}) {
+		options.Logger = ctrl.Log.WithName("simulator")
+	}
+
+	return &Simulator{
+		options: options,
+		clock:   testingclock.NewFakeClock(time.Now()),
+	}, nil
+}
+
+// Start brings up the API server, installs the CRDs and the webhooks, and starts the controllers.
+func (s *Simulator) Start(ctx context.Context) error {
+	s.env = &envtest.Environment{
+		CRDDirectoryPaths:     s.options.CRDDirectoryPaths,
+		CRDs:                  chaosCRDs(),
+		ErrorIfCRDPathMissing: true,
+		BinaryAssetsDirectory: s.options.BinaryAssetsDirectory,
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			MutatingWebhooks:   mutatingWebhooks(),
+			ValidatingWebhooks: validatingWebhooks(),
+		},
+	}
+
+	cfg, err := s.env.Start()
+	if err != nil {
+		return errors.Wrapf(err, "cannot start envtest")
+	}
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+
+	s.client, err = client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return s.abort(errors.Wrapf(err, "cannot create client"))
+	}
+
+	s.grafana = newGrafanaStub()
+
+	webhookOptions := s.env.WebhookInstallOptions
+
+	// count the requests of the controllers, but not those of the simulated backends.
+	mgrConfig := rest.CopyConfig(cfg)
+	mgrConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &countingTransport{next: rt, counter: &s.requests}
+	})
+
+	mgr, err := ctrl.NewManager(mgrConfig, ctrl.Options{
+		Scheme:             scheme,
+		MetricsBindAddress: "0",
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Host:    webhookOptions.LocalServingHost,
+			Port:    webhookOptions.LocalServingPort,
+			CertDir: webhookOptions.LocalServingCertDir,
+		}),
+	})
+	if err != nil {
+		return s.abort(errors.Wrapf(err, "cannot create manager"))
+	}
+
+	if err := setupControllers(mgr, s.options.Logger, clock.FromPassive(s.clock)); err != nil {
+		return s.abort(err)
+	}
+
+	mgrCtx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.done = make(chan error, 1)
+
+	go func() {
+		s.done <- mgr.Start(mgrCtx)
+	}()
+
+	// wait for the webhooks, otherwise the objects are stored without defaults.
+	started := mgr.GetWebhookServer().StartedChecker()
+
+	if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, time.Minute, true,
+		func(context.Context) (bool, error) {
+			return started(nil) == nil, nil
+		}); err != nil {
+		return s.abort(errors.Wrapf(err, "webhook server is not ready"))
+	}
+
+	if !mgr.GetCache().WaitForCacheSync(mgrCtx) {
+		return s.abort(errors.Errorf("cannot sync cache"))
+	}
+
+	return s.installConfiguration(ctx)
+}
+
+// Stop terminates the controllers and the API server.
+func (s *Simulator) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+
+		if err := <-s.done; err != nil {
+			s.options.Logger.Error(err, "manager exited with error")
+		}
+	}
+
+	if s.grafana != nil {
+		s.grafana.Close()
+	}
+
+	return s.env.Stop()
+}
+
+func (s *Simulator) abort(err error) error {
+	if errStop := s.Stop(); errStop != nil {
+		s.options.Logger.Error(errStop, "cannot stop envtest")
+	}
+
+	return err
+}
+
+// Client returns a client to the API server of the simulator.
+func (s *Simulator) Client() client.Client {
+	return s.client
+}
+
+// Now returns the simulated time.
+func (s *Simulator) Now() time.Time {
+	return s.clock.Now()
+}
+
+func setupControllers(mgr ctrl.Manager, logger logr.Logger, clk clock.Clock) error {
+	if err := template.NewController(mgr, logger, clk); err != nil {
+		return errors.Wrapf(err, "cannot create Templates controller")
+	}
+
+	if err := service.NewController(mgr, logger, clk); err != nil {
+		return errors.Wrapf(err, "cannot create Service controller")
+	}
+
+	if err := cluster.NewController(mgr, logger, clk); err != nil {
+		return errors.Wrapf(err, "cannot create Cluster controller")
+	}
+
+	if err := chaos.NewController(mgr, logger, clk); err != nil {
+		return errors.Wrapf(err, "cannot create Chaos controller")
+	}
+
+	if err := cascade.NewController(mgr, logger, clk); err != nil {
+		return errors.Wrapf(err, "cannot create Cascade controller")
+	}
+
+	if err := call.NewController(mgr, logger, clk); err != nil {
+		return errors.Wrapf(err, "cannot create Call controller")
+	}
+
+	if err := scenario.NewController(mgr, logger, clk); err != nil {
+		return errors.Wrapf(err, "cannot create Scenario controller")
+	}
+
+	webhooks := []interface{ SetupWebhookWithManager(ctrl.Manager) error }{
+		&v1alpha1.Template{},
+		&v1alpha1.Service{},
+		&v1alpha1.Cluster{},
+		&v1alpha1.Chaos{},
+		&v1alpha1.Cascade{},
+		&v1alpha1.Scenario{},
+		&v1alpha1.Call{},
+	}
+
+	for _, hook := range webhooks {
+		if err := hook.SetupWebhookWithManager(mgr); err != nil {
+			return errors.Wrapf(err, "cannot create webhook for '%T'", hook)
+		}
+	}
+
+	return nil
+}
+
+// Run submits the objects (templates, configmaps, etc) and the scenario to the given namespace, and advances the
+// simulated time until the scenario is completed. Exactly one of the objects must be a Scenario.
+func (s *Simulator) Run(ctx context.Context, namespace string, objects ...client.Object) (*Result, error) {
+	var submitted *v1alpha1.Scenario
+
+	others := make([]client.Object, 0, len(objects))
+
+	for _, obj := range objects {
+		if candidate, ok := obj.(*v1alpha1.Scenario); ok {
+			if submitted != nil {
+				return nil, errors.Errorf("expected a single scenario but got '%s' and '%s'",
+					submitted.GetName(), candidate.GetName())
+			}
+
+			submitted = candidate
+
+			continue
+		}
+
+		others = append(others, obj)
+	}
+
+	if submitted == nil {
+		return nil, errors.Errorf("no scenario was given")
+	}
+
+	/*---------------------------------------------------*
+	 * Submit the objects
+	 *---------------------------------------------------*/
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+
+	if err := s.client.Create(ctx, &ns); err != nil {
+		return nil, errors.Wrapf(err, "cannot create namespace '%s'", namespace)
+	}
+
+	if err := s.installStubs(ctx, namespace, others); err != nil {
+		return nil, errors.Wrapf(err, "cannot install stubs")
+	}
+
+	for _, obj := range others {
+		obj.SetNamespace(namespace)
+
+		if err := s.client.Create(ctx, obj); err != nil {
+			return nil, errors.Wrapf(err, "cannot create '%s'", obj.GetName())
+		}
+	}
+
+	submitted.SetNamespace(namespace)
+
+	// the controller skips the connection to Grafana if a client is already registered for the scenario.
+	if err := s.grafana.RegisterFor(ctx, submitted, s.options.Logger); err != nil {
+		return nil, errors.Wrapf(err, "cannot register grafana stub")
+	}
+
+	if err := s.client.Create(ctx, submitted); err != nil {
+		return nil, errors.Wrapf(err, "cannot create scenario '%s'", submitted.GetName())
+	}
+
+	/*---------------------------------------------------*
+	 * Advance the time until the scenario is completed
+	 *---------------------------------------------------*/
+	start := s.clock.Now()
+	wallStart := time.Now()
+	key := client.ObjectKeyFromObject(submitted)
+	observer := newObserver()
+
+	for {
+		var current v1alpha1.Scenario
+
+		if err := s.client.Get(ctx, key, &current); err != nil {
+			return nil, errors.Wrapf(err, "cannot get scenario '%s'", key)
+		}
+
+		elapsed := s.clock.Since(start)
+		wall := time.Since(wallStart)
+		observer.Observe(elapsed, wall, &current)
+
+		result := &Result{
+			Scenario: &current,
+			Duration: elapsed,
+			Wall:     wall,
+			Timeline: observer.timeline,
+		}
+
+		if current.Status.Phase.Is(v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed, v1alpha1.PhaseAborted) {
+			return result, nil
+		}
+
+		if elapsed > s.options.Timeout {
+			return result, errors.Errorf("scenario '%s' did not complete within %s", key, s.options.Timeout)
+		}
+
+		if err := s.tick(ctx, namespace); err != nil {
+			return result, errors.Wrapf(err, "tick error")
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-time.After(s.options.Settle):
+		}
+	}
+}
+
+// tick advances the simulated time, and lets the backends react to it.
+func (s *Simulator) tick(ctx context.Context, namespace string) error {
+	// the creation timestamps are set by the API server. Never let the simulated time fall behind them.
+	if now := time.Now(); s.clock.Now().Before(now) {
+		s.clock.SetTime(now)
+	}
+
+	s.clock.Step(s.options.Step)
+	s.ticks++
+
+	if err := s.runPods(ctx, namespace); err != nil {
+		return errors.Wrapf(err, "kubelet error")
+	}
+
+	if err := s.runFaults(ctx, namespace); err != nil {
+		return errors.Wrapf(err, "chaos error")
+	}
+
+	return s.poke(ctx, namespace)
+}
+
+// poke updates the tick annotation of the active objects. The controllers requeue their requests in wall time,
+// so without a poke, a schedule in simulated time would be evaluated too late.
+func (s *Simulator) poke(ctx context.Context, namespace string) error {
+	patch := client.RawPatch(types.MergePatchType,
+		[]byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:"%d"}}}`, TickAnnotation, s.ticks)))
+
+	lists := []client.ObjectList{
+		&v1alpha1.ScenarioList{},
+		&v1alpha1.ClusterList{},
+		&v1alpha1.CascadeList{},
+		&v1alpha1.ServiceList{},
+		&v1alpha1.ChaosList{},
+		&v1alpha1.CallList{},
+	}
+
+	for _, list := range lists {
+		if err := s.client.List(ctx, list, client.InNamespace(namespace)); err != nil {
+			return errors.Wrapf(err, "cannot list '%T'", list)
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return errors.Wrapf(err, "cannot extract '%T'", list)
+		}
+
+		for _, item := range items {
+			obj := item.(client.Object)
+
+			status, ok := obj.(v1alpha1.ReconcileStatusAware)
+			if ok && status.GetReconcileStatus().Phase.Is(v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed, v1alpha1.PhaseAborted) {
+				continue
+			}
+
+			if err := s.client.Patch(ctx, obj, patch); client.IgnoreNotFound(err) != nil {
+				return errors.Wrapf(err, "cannot poke '%s'", obj.GetName())
+			}
+		}
+	}
+
+	return nil
+}
+
+// observer records the phase transitions of a scenario.
+type observer struct {
+	phases   map[string]v1alpha1.Phase
+	timeline []Event
+}
+
+func newObserver() *observer {
+	return &observer{phases: make(map[string]v1alpha1.Phase)}
+}
+
+func (o *observer) Observe(at time.Duration, wall time.Duration, current *v1alpha1.Scenario) {
+	o.record(at, wall, "", current.Status.Phase)
+
+	for _, action := range current.Status.Actions {
+		o.record(at, wall, action.Name, action.Phase)
+	}
+}
+
+func (o *observer) record(at time.Duration, wall time.Duration, action string, phase v1alpha1.Phase) {
+	if last, exists := o.phases[action]; exists && last == phase {
+		return
+	}
+
+	o.phases[action] = phase
+	o.timeline = append(o.timeline, Event{At: at, Wall: wall, Action: action, Phase: phase})
+}
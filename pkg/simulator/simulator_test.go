@@ -0,0 +1,199 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/chaos"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+func TestStepPod(t *testing.T) {
+	start := time.Now()
+
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: v1alpha1.MainContainerName},
+			{Name: "sidecar"},
+		}},
+	}
+
+	behavior := Behavior{Duration: time.Minute, ExitCode: 1}
+
+	if !stepPod(pod, behavior, metav1.NewTime(start)) || pod.Status.Phase != corev1.PodRunning {
+		t.Fatalf("expected the pod to run, but got '%s'", pod.Status.Phase)
+	}
+
+	if stepPod(pod, behavior, metav1.NewTime(start.Add(30*time.Second))) {
+		t.Fatalf("expected the pod to keep running before its duration expires")
+	}
+
+	if !stepPod(pod, behavior, metav1.NewTime(start.Add(time.Minute))) || pod.Status.Phase != corev1.PodFailed {
+		t.Fatalf("expected the pod to fail, but got '%s'", pod.Status.Phase)
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Terminated == nil {
+			t.Fatalf("container '%s' is not terminated", status.Name)
+		}
+
+		if status.Name == v1alpha1.MainContainerName && status.State.Terminated.ExitCode != 1 {
+			t.Errorf("expected exit code 1 for the main container, but got %d", status.State.Terminated.ExitCode)
+		}
+	}
+}
+
+func TestStepFault(t *testing.T) {
+	start := time.Now()
+
+	fault := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"duration": "2m"},
+	}}
+
+	phaseOf := func() string {
+		phase, _, _ := unstructured.NestedString(fault.Object, "status", "experiment", "desiredPhase")
+
+		return phase
+	}
+
+	if changed, err := stepFault(fault, start); err != nil || !changed || phaseOf() != string(chaos.RunningPhase) {
+		t.Fatalf("expected the fault to be injected, but got '%s' (err: %v)", phaseOf(), err)
+	}
+
+	if changed, err := stepFault(fault, start.Add(time.Minute)); err != nil || changed {
+		t.Fatalf("expected the fault to remain injected before its duration expires (err: %v)", err)
+	}
+
+	if changed, err := stepFault(fault, start.Add(2*time.Minute)); err != nil || !changed || phaseOf() != string(chaos.StoppedPhase) {
+		t.Fatalf("expected the fault to be recovered, but got '%s' (err: %v)", phaseOf(), err)
+	}
+}
+
+const sleepers = `
+apiVersion: frisbee.dev/v1alpha1
+kind: Template
+metadata:
+  name: sleeper
+spec:
+  service:
+    decorators:
+      annotations:
+        simulator.frisbee.dev/duration: 1m
+    containers:
+      - name: main
+        image: busybox
+---
+apiVersion: frisbee.dev/v1alpha1
+kind: Scenario
+metadata:
+  name: sleepers
+spec:
+  actions:
+    - action: Service
+      name: first
+      service:
+        templateRef: sleeper
+
+    - action: Service
+      name: second
+      depends: { success: [ first ] }
+      service:
+        templateRef: sleeper
+`
+
+// TestSimulator_Run requires the envtest binaries. Use setup-envtest and set KUBEBUILDER_ASSETS.
+func TestSimulator_Run(t *testing.T) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS is not set")
+	}
+
+	var (
+		template v1alpha1.Template
+		scenario v1alpha1.Scenario
+	)
+
+	docs := splitDocuments(sleepers)
+
+	if err := yaml.Unmarshal([]byte(docs[0]), &template); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := yaml.Unmarshal([]byte(docs[1]), &scenario); err != nil {
+		t.Fatal(err)
+	}
+
+	sim, err := New(Options{
+		CRDDirectoryPaths: []string{"../../charts/platform/crds"},
+		Step:              5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	if err := sim.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := sim.Stop(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	result, err := sim.Run(ctx, "sleepers", []client.Object{&template, &scenario}...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, event := range result.Timeline {
+		t.Log(event)
+	}
+
+	if result.Scenario.Status.Phase != v1alpha1.PhaseSuccess {
+		t.Fatalf("expected success, but got '%s': %s", result.Scenario.Status.Phase, result.Scenario.Status.Message)
+	}
+
+	// the second sleeper starts after the first is completed.
+	if result.Duration < 2*time.Minute {
+		t.Errorf("expected the actions to run sequentially, but the scenario took %s", result.Duration)
+	}
+}
+
+func splitDocuments(manifest string) []string {
+	var docs []string
+
+	for _, doc := range strings.Split(manifest, "\n---\n") {
+		if strings.TrimSpace(doc) != "" {
+			docs = append(docs, doc)
+		}
+	}
+
+	return docs
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"strings"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/configuration"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// PlatformNamespace hosts the configuration of the simulated platform.
+	PlatformNamespace = "frisbee"
+
+	// StubImage is the image of the containers that replace the missing templates.
+	StubImage = "simulator/stub"
+)
+
+// installConfiguration creates the platform configuration that is normally installed by the Helm chart.
+func (s *Simulator) installConfiguration(ctx context.Context) error {
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: PlatformNamespace}}
+
+	if err := s.client.Create(ctx, &ns); err != nil && !k8errors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "cannot create namespace '%s'", PlatformNamespace)
+	}
+
+	config := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      configuration.PlatformConfigurationName,
+			Namespace: PlatformNamespace,
+			Labels:    map[string]string{v1alpha1.ResourceDiscoveryLabel: configuration.PlatformConfigurationName},
+		},
+		Data: map[string]string{
+			"DeveloperMode":    "false",
+			"Namespace":        PlatformNamespace,
+			"DomainName":       "localhost",
+			"IngressClassName": "nginx",
+			"ControllerName":   "frisbee-operator",
+		},
+	}
+
+	if err := s.client.Create(ctx, &config); err != nil && !k8errors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "cannot create platform configuration")
+	}
+
+	return nil
+}
+
+// installStubs creates the system templates (e.g, Prometheus, Grafana) and the telemetry agents that are
+// referenced but not given, along with the dashboards of the agents.
+func (s *Simulator) installStubs(ctx context.Context, namespace string, objects []client.Object) error {
+	given := make(map[string]bool)
+	agents := make(map[string]bool)
+
+	for _, obj := range objects {
+		given[obj.GetName()] = true
+
+		if template, ok := obj.(*v1alpha1.Template); ok && template.Spec.Service != nil {
+			for _, agent := range template.Spec.Service.Decorators.Telemetry {
+				agents[agent] = true
+			}
+		}
+	}
+
+	var stubs []client.Object
+
+	for _, system := range []string{
+		configuration.PrometheusTemplate,
+		configuration.GrafanaTemplate,
+		configuration.IngestionTemplate,
+		configuration.TempoTemplate,
+		configuration.JaegerTemplate,
+		configuration.DataviewerTemplate,
+	} {
+		if !given[system] {
+			stubs = append(stubs, stubTemplate(system, v1alpha1.MainContainerName))
+		}
+	}
+
+	for agent := range agents {
+		if !given[agent] {
+			stubs = append(stubs, stubTemplate(agent, strings.ReplaceAll(agent, ".", "-")))
+		}
+
+		// Every telemetry agent must be accompanied by a configMap with the dashboards.
+		if dashboards := agent + ".config"; !given[dashboards] {
+			stubs = append(stubs, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: dashboards},
+				Data:       map[string]string{"dashboard.json": "{}"},
+			})
+		}
+	}
+
+	for _, obj := range stubs {
+		obj.SetNamespace(namespace)
+
+		if err := s.client.Create(ctx, obj); err != nil {
+			return errors.Wrapf(err, "cannot create stub '%s'", obj.GetName())
+		}
+	}
+
+	return nil
+}
+
+func stubTemplate(name string, containerName string) *v1alpha1.Template {
+	var spec v1alpha1.ServiceSpec
+
+	spec.Containers = []corev1.Container{{Name: containerName, Image: StubImage}}
+
+	template := &v1alpha1.Template{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	template.Spec.Service = &spec
+
+	return template
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// countingTransport counts the requests that go through it.
+type countingTransport struct {
+	next    http.RoundTripper
+	counter *int64
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt64(t.counter, 1)
+
+	return t.next.RoundTrip(req)
+}
+
+// APIRequests returns the number of requests that the controllers have issued to the API server.
+// The requests of the simulated backends (kubelet, chaos, ticks) are not included.
+func (s *Simulator) APIRequests() int64 {
+	return atomic.LoadInt64(&s.requests)
+}
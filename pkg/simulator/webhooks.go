@@ -0,0 +1,103 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"fmt"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// admittedResources maps the singular names used in the webhook paths to the resources of the Frisbee API.
+// It must follow the +kubebuilder:webhook markers of api/v1alpha1.
+var admittedResources = []struct {
+	singular, plural string
+}{
+	{"template", "templates"},
+	{"service", "services"},
+	{"cluster", "clusters"},
+	{"chaos", "chaos"},
+	{"cascade", "cascades"},
+	{"scenario", "scenarios"},
+	{"call", "calls"},
+}
+
+func webhookClientConfig(kind, singular string) admissionv1.WebhookClientConfig {
+	// the service is replaced by envtest with the url of the local webhook server.
+	path := fmt.Sprintf("/%s-frisbee-dev-v1alpha1-%s", kind, singular)
+
+	return admissionv1.WebhookClientConfig{
+		Service: &admissionv1.ServiceReference{Name: "webhook-service", Namespace: "default", Path: &path},
+	}
+}
+
+func webhookRule(plural string, operations ...admissionv1.OperationType) []admissionv1.RuleWithOperations {
+	return []admissionv1.RuleWithOperations{{
+		Operations: operations,
+		Rule: admissionv1.Rule{
+			APIGroups:   []string{v1alpha1.GroupVersion.Group},
+			APIVersions: []string{v1alpha1.GroupVersion.Version},
+			Resources:   []string{plural},
+		},
+	}}
+}
+
+func mutatingWebhooks() []*admissionv1.MutatingWebhookConfiguration {
+	failurePolicy := admissionv1.Fail
+	sideEffects := admissionv1.SideEffectClassNone
+
+	config := &admissionv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "frisbee-mutating-webhook"},
+	}
+
+	for _, res := range admittedResources {
+		config.Webhooks = append(config.Webhooks, admissionv1.MutatingWebhook{
+			Name:                    fmt.Sprintf("m%s.kb.io", res.singular),
+			ClientConfig:            webhookClientConfig("mutate", res.singular),
+			Rules:                   webhookRule(res.plural, admissionv1.Create, admissionv1.Update),
+			FailurePolicy:           &failurePolicy,
+			SideEffects:             &sideEffects,
+			AdmissionReviewVersions: []string{"v1", "v1alpha1"},
+		})
+	}
+
+	return []*admissionv1.MutatingWebhookConfiguration{config}
+}
+
+func validatingWebhooks() []*admissionv1.ValidatingWebhookConfiguration {
+	failurePolicy := admissionv1.Fail
+	sideEffects := admissionv1.SideEffectClassNone
+
+	config := &admissionv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "frisbee-validating-webhook"},
+	}
+
+	for _, res := range admittedResources {
+		config.Webhooks = append(config.Webhooks, admissionv1.ValidatingWebhook{
+			Name:                    fmt.Sprintf("v%s.kb.io", res.singular),
+			ClientConfig:            webhookClientConfig("validate", res.singular),
+			Rules:                   webhookRule(res.plural, admissionv1.Create),
+			FailurePolicy:           &failurePolicy,
+			SideEffects:             &sideEffects,
+			AdmissionReviewVersions: []string{"v1", "v1alpha1"},
+		})
+	}
+
+	return []*admissionv1.ValidatingWebhookConfiguration{config}
+}
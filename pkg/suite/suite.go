@@ -0,0 +1,224 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package suite runs a directory of scenarios as a test suite. The scenarios run concurrently, up to a limit,
+// and their outcomes are aggregated into a single report that can be exported in the JUnit XML format.
+package suite
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gosimple/slug"
+	"github.com/pkg/errors"
+)
+
+// Status is the outcome of a test case.
+type Status string
+
+const (
+	// StatusPassed means that the scenario has completed successfully.
+	StatusPassed = Status("Passed")
+
+	// StatusFailed means that the scenario has failed, or has been stopped by an assertion error.
+	StatusFailed = Status("Failed")
+
+	// StatusError means that the scenario could not be submitted or watched.
+	StatusError = Status("Error")
+
+	// StatusSkipped means that the scenario has not run to completion, because the suite was stopped.
+	StatusSkipped = Status("Skipped")
+)
+
+// Case is a scenario of the suite.
+type Case struct {
+	// Name is the name of the test, and thereby of its namespace.
+	Name string
+
+	// File is the path to the scenario.
+	File string
+}
+
+// Result is the outcome of a test case.
+type Result struct {
+	Case
+
+	Status Status
+
+	// Message explains why the test has not passed.
+	Message string
+
+	Duration time.Duration
+}
+
+// Report aggregates the results of the suite. The results are in the order of the cases.
+type Report struct {
+	Name string
+
+	Timestamp time.Time
+
+	Duration time.Duration
+
+	Results []Result
+}
+
+// Count returns the number of results with the given status.
+func (r *Report) Count(status Status) int {
+	count := 0
+
+	for _, result := range r.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Passed returns true if every test of the suite has passed.
+func (r *Report) Passed() bool {
+	return r.Count(StatusPassed) == len(r.Results)
+}
+
+// Discover returns a case for every scenario (.yaml, .yml) in the directory, in lexicographic order.
+// The name of each test is the prefix followed by the slug of the file name.
+func Discover(dir string, prefix string) ([]Case, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read suite '%s'", dir)
+	}
+
+	var cases []Case
+
+	names := make(map[string]string)
+
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		// namespaces do not allow underscores, which are preserved by the slug.
+		name := prefix + strings.ReplaceAll(slug.Make(strings.TrimSuffix(entry.Name(), ext)), "_", "-")
+
+		if other, exists := names[name]; exists {
+			return nil, errors.Errorf("scenarios '%s' and '%s' map to the same test '%s'", other, entry.Name(), name)
+		}
+
+		names[name] = entry.Name()
+
+		cases = append(cases, Case{Name: name, File: filepath.Join(dir, entry.Name())})
+	}
+
+	if len(cases) == 0 {
+		return nil, errors.Errorf("no scenarios found in '%s'", dir)
+	}
+
+	sort.Slice(cases, func(i, j int) bool { return cases[i].File < cases[j].File })
+
+	return cases, nil
+}
+
+// Runner runs a test case to completion and returns its outcome. The context is cancelled if the suite is stopped.
+type Runner func(ctx context.Context, c Case) (Status, error)
+
+// Options control the execution of the suite.
+type Options struct {
+	// Concurrency is the maximum number of tests that run in parallel. Values below 1 mean one.
+	Concurrency int
+
+	// FailFast stops the suite at the first test that does not pass.
+	// The tests that are still running are cancelled, and the pending ones are skipped.
+	FailFast bool
+}
+
+// Run runs the cases of the suite and returns the aggregated report.
+func Run(ctx context.Context, name string, cases []Case, options Options, runner Runner) *Report {
+	report := &Report{
+		Name:      name,
+		Timestamp: time.Now(),
+		Results:   make([]Result, len(cases)),
+	}
+
+	concurrency := options.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pending := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range pending {
+				report.Results[i] = runCase(ctx, cases[i], runner)
+
+				if options.FailFast && report.Results[i].Status != StatusPassed {
+					cancel()
+				}
+			}
+		}()
+	}
+
+	for i := range cases {
+		// the dispatch stops once the suite is stopped. The remaining cases are skipped.
+		if ctx.Err() != nil {
+			report.Results[i] = Result{Case: cases[i], Status: StatusSkipped, Message: "the suite was stopped"}
+
+			continue
+		}
+
+		select {
+		case pending <- i:
+		case <-ctx.Done():
+			report.Results[i] = Result{Case: cases[i], Status: StatusSkipped, Message: "the suite was stopped"}
+		}
+	}
+
+	close(pending)
+	wg.Wait()
+
+	report.Duration = time.Since(report.Timestamp)
+
+	return report
+}
+
+func runCase(ctx context.Context, c Case, runner Runner) Result {
+	start := time.Now()
+
+	status, err := runner(ctx, c)
+
+	result := Result{Case: c, Status: status, Duration: time.Since(start)}
+
+	if err != nil {
+		result.Message = err.Error()
+	}
+
+	return result
+}
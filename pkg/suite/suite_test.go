@@ -0,0 +1,216 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package suite_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/pkg/suite"
+	"github.com/pkg/errors"
+)
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{"b_test.yml", "a test.yaml", "notes.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, "charts.yaml"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	cases, err := suite.Discover(dir, "nightly-")
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+
+	want := []suite.Case{
+		{Name: "nightly-a-test", File: filepath.Join(dir, "a test.yaml")},
+		{Name: "nightly-b-test", File: filepath.Join(dir, "b_test.yml")},
+	}
+
+	if !reflect.DeepEqual(cases, want) {
+		t.Fatalf("Discover() = %v, want %v", cases, want)
+	}
+
+	// file names that map to the same test are ambiguous.
+	if err := os.WriteFile(filepath.Join(dir, "b-test.yaml"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := suite.Discover(dir, "nightly-"); err == nil {
+		t.Fatal("expected error for conflicting test names")
+	}
+
+	if _, err := suite.Discover(t.TempDir(), ""); err == nil {
+		t.Fatal("expected error for empty suite")
+	}
+}
+
+func cases(names ...string) []suite.Case {
+	list := make([]suite.Case, 0, len(names))
+
+	for _, name := range names {
+		list = append(list, suite.Case{Name: name, File: name + ".yml"})
+	}
+
+	return list
+}
+
+func statuses(report *suite.Report) []suite.Status {
+	list := make([]suite.Status, 0, len(report.Results))
+
+	for _, result := range report.Results {
+		list = append(list, result.Status)
+	}
+
+	return list
+}
+
+func TestRun(t *testing.T) {
+	outcome := map[string]suite.Status{
+		"a": suite.StatusPassed,
+		"b": suite.StatusFailed,
+		"c": suite.StatusPassed,
+	}
+
+	var running, peak int32
+
+	runner := func(ctx context.Context, c suite.Case) (suite.Status, error) {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+
+		for {
+			old := atomic.LoadInt32(&peak)
+			if current <= old || atomic.CompareAndSwapInt32(&peak, old, current) {
+				break
+			}
+		}
+
+		if outcome[c.Name] != suite.StatusPassed {
+			return outcome[c.Name], errors.Errorf("test '%s' failed", c.Name)
+		}
+
+		return outcome[c.Name], nil
+	}
+
+	report := suite.Run(context.Background(), "suite", cases("a", "b", "c"), suite.Options{Concurrency: 2}, runner)
+
+	if got, want := statuses(report), []suite.Status{suite.StatusPassed, suite.StatusFailed, suite.StatusPassed}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("statuses = %v, want %v", got, want)
+	}
+
+	if report.Results[1].Message != "test 'b' failed" {
+		t.Fatalf("unexpected message '%s'", report.Results[1].Message)
+	}
+
+	if report.Passed() {
+		t.Fatal("the suite should not pass")
+	}
+
+	if peak > 2 {
+		t.Fatalf("concurrency limit violated: %d tests in parallel", peak)
+	}
+}
+
+func TestRunFailFast(t *testing.T) {
+	runner := func(ctx context.Context, c suite.Case) (suite.Status, error) {
+		if c.Name == "b" {
+			return suite.StatusFailed, errors.New("failed")
+		}
+
+		return suite.StatusPassed, nil
+	}
+
+	report := suite.Run(context.Background(), "suite", cases("a", "b", "c", "d"), suite.Options{Concurrency: 1, FailFast: true}, runner)
+
+	want := []suite.Status{suite.StatusPassed, suite.StatusFailed, suite.StatusSkipped, suite.StatusSkipped}
+
+	if got := statuses(report); !reflect.DeepEqual(got, want) {
+		t.Fatalf("statuses = %v, want %v", got, want)
+	}
+
+	if report.Count(suite.StatusSkipped) != 2 {
+		t.Fatalf("Count(Skipped) = %d, want 2", report.Count(suite.StatusSkipped))
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	report := &suite.Report{
+		Name: "nightly",
+		Results: []suite.Result{
+			{Case: suite.Case{Name: "a", File: "a.yml"}, Status: suite.StatusPassed},
+			{Case: suite.Case{Name: "b", File: "b.yml"}, Status: suite.StatusFailed, Message: "assertion error"},
+			{Case: suite.Case{Name: "c", File: "c.yml"}, Status: suite.StatusError, Message: "cannot submit"},
+			{Case: suite.Case{Name: "d", File: "d.yml"}, Status: suite.StatusSkipped, Message: "the suite was stopped"},
+		},
+	}
+
+	var out bytes.Buffer
+
+	if err := report.WriteJUnit(&out); err != nil {
+		t.Fatalf("WriteJUnit() error = %v", err)
+	}
+
+	if !strings.HasPrefix(out.String(), xml.Header) {
+		t.Fatalf("missing xml header: %s", out.String())
+	}
+
+	var parsed struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Errors   int `xml:"errors,attr"`
+		Skipped  int `xml:"skipped,attr"`
+		Suites   []struct {
+			Cases []struct {
+				Name    string    `xml:"name,attr"`
+				Failure *struct{} `xml:"failure"`
+				Error   *struct{} `xml:"error"`
+				Skipped *struct{} `xml:"skipped"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+
+	if err := xml.Unmarshal(out.Bytes(), &parsed); err != nil {
+		t.Fatalf("invalid junit report: %v", err)
+	}
+
+	if parsed.Tests != 4 || parsed.Failures != 1 || parsed.Errors != 1 || parsed.Skipped != 1 {
+		t.Fatalf("unexpected totals: %+v", parsed)
+	}
+
+	if len(parsed.Suites) != 1 || len(parsed.Suites[0].Cases) != 4 {
+		t.Fatalf("unexpected suites: %+v", parsed.Suites)
+	}
+
+	testCases := parsed.Suites[0].Cases
+
+	if testCases[0].Failure != nil || testCases[1].Failure == nil || testCases[2].Error == nil || testCases[3].Skipped == nil {
+		t.Fatalf("unexpected test cases: %+v", testCases)
+	}
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package timeline converts the history of a scenario into the Chrome Trace Event format,
+// which can be loaded into Perfetto (https://ui.perfetto.dev) or chrome://tracing.
+//
+// The trace consists of:
+//   - one span for the scenario, and one span per scheduled action (from status.actions).
+//   - one span per child object, from its first event until the event that marks its completion.
+//   - one instant per Kubernetes event (e.g., creations, phase changes, chaos injections, alerts).
+package timeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Chrome Trace Event phases.
+const (
+	phaseComplete = "X"
+	phaseInstant  = "i"
+	phaseMetadata = "M"
+)
+
+const (
+	// scenarioPID groups all the tracks of the scenario.
+	scenarioPID = 1
+
+	// actionsTID is the track of the scenario and its actions. The rest of the tracks are allocated to the child objects.
+	actionsTID = 0
+)
+
+// Event is a single entry of the Chrome Trace Event format.
+type Event struct {
+	Name     string            `json:"name"`
+	Category string            `json:"cat,omitempty"`
+	Phase    string            `json:"ph"`
+	Scope    string            `json:"s,omitempty"`
+	TS       int64             `json:"ts"`
+	Duration int64             `json:"dur,omitempty"`
+	PID      int               `json:"pid"`
+	TID      int               `json:"tid"`
+	Args     map[string]string `json:"args,omitempty"`
+}
+
+// Trace is a document of the Chrome Trace Event format.
+type Trace struct {
+	TraceEvents     []Event `json:"traceEvents"`
+	DisplayTimeUnit string  `json:"displayTimeUnit"`
+}
+
+// Write encodes the trace as JSON.
+func (t *Trace) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return errors.Wrapf(enc.Encode(t), "cannot encode trace")
+}
+
+// FromScenario builds the trace of the scenario, given the events of its namespace.
+// Spans that have not finished yet are closed at the given time.
+func FromScenario(scenario *v1alpha1.Scenario, events []corev1.Event, now time.Time) *Trace {
+	trace := &Trace{DisplayTimeUnit: "ms"}
+
+	trace.add(Event{Name: "process_name", Phase: phaseMetadata, PID: scenarioPID, TID: actionsTID,
+		Args: map[string]string{"name": scenario.GetName()}})
+
+	trace.add(Event{Name: "thread_name", Phase: phaseMetadata, PID: scenarioPID, TID: actionsTID,
+		Args: map[string]string{"name": "Scenario"}})
+
+	/*---------------------------------------------------
+	 * Scenario and actions
+	 *---------------------------------------------------*/
+	scenarioEnd := now
+	if scenario.Status.Phase.Is(v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed, v1alpha1.PhaseAborted) {
+		scenarioEnd = lastTime(scenario.GetCreationTimestamp().Time, scenario.Status.Actions, events)
+	}
+
+	trace.span("Scenario", scenario.GetName(), actionsTID, scenario.GetCreationTimestamp().Time, scenarioEnd,
+		map[string]string{
+			"phase":   scenario.Status.Phase.String(),
+			"reason":  scenario.Status.Reason,
+			"message": scenario.Status.Message,
+		})
+
+	for _, action := range scenario.Status.Actions {
+		if action.StartTime == nil {
+			continue
+		}
+
+		end := now
+		if action.EndTime != nil {
+			end = action.EndTime.Time
+		}
+
+		args := map[string]string{
+			"phase":       action.Phase.String(),
+			"jobsCreated": fmt.Sprint(action.JobsCreated),
+			"progress":    fmt.Sprintf("%d%%", action.PercentComplete),
+		}
+
+		if action.Reason != "" {
+			args["reason"] = action.Reason
+			args["message"] = action.Message
+		}
+
+		if failure := action.AssertionFailure; failure != nil {
+			args["assert"] = failure.Expression
+
+			for key, value := range failure.Values {
+				args["assert."+key] = value
+			}
+		}
+
+		trace.span(string(action.ActionType), action.Name, actionsTID, action.StartTime.Time, end, args)
+	}
+
+	/*---------------------------------------------------
+	 * Objects and events
+	 *---------------------------------------------------*/
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(&events[i]).Before(eventTime(&events[j]))
+	})
+
+	type track struct {
+		tid        int
+		kind       string
+		start, end time.Time
+		completed  bool
+	}
+
+	tracks := make(map[string]*track)
+	order := make([]string, 0)
+
+	for i := range events {
+		event := &events[i]
+		obj := event.InvolvedObject
+		key := obj.Kind + "/" + obj.Name
+		ts := eventTime(event)
+
+		t, exists := tracks[key]
+		if !exists {
+			t = &track{tid: len(tracks) + 1, kind: obj.Kind, start: ts}
+			tracks[key] = t
+			order = append(order, key)
+
+			trace.add(Event{Name: "thread_name", Phase: phaseMetadata, PID: scenarioPID, TID: t.tid,
+				Args: map[string]string{"name": key}})
+		}
+
+		if !t.completed {
+			t.end = ts
+			t.completed = isCompletion(event.Reason)
+		}
+
+		trace.add(Event{
+			Name:     event.Reason,
+			Category: obj.Kind,
+			Phase:    phaseInstant,
+			Scope:    "t",
+			TS:       ts.UnixMicro(),
+			PID:      scenarioPID,
+			TID:      t.tid,
+			Args: map[string]string{
+				"type":    event.Type,
+				"message": event.Message,
+				"count":   fmt.Sprint(event.Count),
+			},
+		})
+	}
+
+	for _, key := range order {
+		t := tracks[key]
+
+		end := t.end
+		if !t.completed {
+			end = now
+		}
+
+		trace.span(t.kind, key, t.tid, t.start, end, nil)
+	}
+
+	return trace
+}
+
+func (t *Trace) add(event Event) {
+	t.TraceEvents = append(t.TraceEvents, event)
+}
+
+func (t *Trace) span(category, name string, tid int, start, end time.Time, args map[string]string) {
+	if end.Before(start) {
+		end = start
+	}
+
+	t.add(Event{
+		Name:     name,
+		Category: category,
+		Phase:    phaseComplete,
+		TS:       start.UnixMicro(),
+		Duration: end.Sub(start).Microseconds(),
+		PID:      scenarioPID,
+		TID:      tid,
+		Args:     args,
+	})
+}
+
+// isCompletion returns true if the event reason marks the end of an object's lifecycle.
+func isCompletion(reason string) bool {
+	switch reason {
+	case v1alpha1.PhaseSuccess.String(), v1alpha1.PhaseFailed.String(), v1alpha1.PhaseAborted.String(),
+		"Completed", "VExecSuccess", "VExecFailed":
+		return true
+	default:
+		return false
+	}
+}
+
+// eventTime returns the most accurate timestamp of the event.
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	default:
+		return event.GetCreationTimestamp().Time
+	}
+}
+
+// lastTime returns the latest known timestamp of a completed scenario.
+func lastTime(start time.Time, actions []v1alpha1.ActionStatus, events []corev1.Event) time.Time {
+	last := start
+
+	for _, action := range actions {
+		if action.EndTime != nil && action.EndTime.After(last) {
+			last = action.EndTime.Time
+		}
+	}
+
+	for i := range events {
+		if ts := eventTime(&events[i]); ts.After(last) {
+			last = ts
+		}
+	}
+
+	return last
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+/*
+	The controllers open a span for every reconciliation cycle, and a child span for every object they create or
+	delete. The spans of different controllers are stitched together through the AnnotationTraceParent of the
+	objects: the controller that creates an object records its trace context on the object, and the controller that
+	reconciles the object links its spans to that context. Hence, the lifecycle of a scenario can be followed from
+	the Scenario down to the Pods of the workload.
+
+	Unless an exporter is configured, the global tracer provider of OpenTelemetry is a no-op, and so are the spans.
+*/
+
+const (
+	// instrumentationName identifies the spans produced by the controllers.
+	instrumentationName = "github.com/carv-ics-forth/frisbee/controllers"
+
+	// ServiceName is the name under which the operator reports its traces.
+	ServiceName = "frisbee-operator"
+
+	// traceParentKey is the key of the W3C trace context.
+	traceParentKey = "traceparent"
+)
+
+const (
+	attributeKind      = attribute.Key("frisbee.kind")
+	attributeName      = attribute.Key("frisbee.name")
+	attributeNamespace = attribute.Key("frisbee.namespace")
+	attributeScenario  = attribute.Key("frisbee.scenario")
+	attributeAction    = attribute.Key("frisbee.action")
+)
+
+// Options configure the export of the traces.
+type Options struct {
+	// Endpoint is the address (host:port) of the OTLP/gRPC collector. If empty, tracing is disabled.
+	Endpoint string
+
+	// Insecure disables the transport security towards the collector.
+	Insecure bool
+
+	// SampleRatio is the fraction of the reconciliation traces that are sampled, within [0, 1].
+	SampleRatio float64
+}
+
+// Setup installs the global tracer provider, which exports the spans to the OTLP collector.
+// The returned function flushes the pending spans, and must be called before the operator exits.
+func Setup(ctx context.Context, opts Options) (shutdown func(context.Context) error, err error) {
+	if opts.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	if opts.SampleRatio < 0 || opts.SampleRatio > 1 {
+		return nil, errors.Errorf("sample ratio '%f' is not within [0, 1]", opts.SampleRatio)
+	}
+
+	clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.Endpoint)}
+
+	if opts.Insecure {
+		clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, clientOpts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot create exporter for '%s'", opts.Endpoint)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceNameKey.String(ServiceName),
+		)),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return provider.Shutdown, nil
+}
+
+// StartReconcile opens the span of a reconciliation cycle. The object is not yet loaded, and therefore the
+// span is identified only by the request.
+func StartReconcile(ctx context.Context, kind string, req types.NamespacedName) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, "Reconcile "+kind,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(
+			attributeKind.String(kind),
+			attributeNamespace.String(req.Namespace),
+			attributeName.String(req.Name),
+		),
+	)
+}
+
+// Start opens a span for an operation on the object. If the object was created within a traced reconciliation,
+// the span is linked to it.
+func Start(ctx context.Context, operation string, obj client.Object) (context.Context, trace.Span) {
+	opts := []trace.SpanStartOption{trace.WithAttributes(Attributes(obj)...)}
+
+	if parent := Extract(obj); parent.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: parent}))
+	}
+
+	return otel.Tracer(instrumentationName).Start(ctx, operation, opts...)
+}
+
+// End records the error, if any, and closes the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// Attributes describe the object in terms of the scenario it belongs to.
+func Attributes(obj client.Object) []attribute.KeyValue {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		kind = reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+	}
+
+	attrs := []attribute.KeyValue{
+		attributeKind.String(kind),
+		attributeNamespace.String(obj.GetNamespace()),
+		attributeName.String(obj.GetName()),
+	}
+
+	if scenario, ok := obj.GetLabels()[v1alpha1.LabelScenario]; ok {
+		attrs = append(attrs, attributeScenario.String(scenario))
+	}
+
+	if action, ok := obj.GetLabels()[v1alpha1.LabelAction]; ok {
+		attrs = append(attrs, attributeAction.String(action))
+	}
+
+	return attrs
+}
+
+// Inject records the trace context of the current span on the object. Nothing is recorded if there is no
+// active span (e.g, tracing is disabled).
+func Inject(ctx context.Context, obj metav1.Object) {
+	carrier := propagation.MapCarrier{}
+
+	propagation.TraceContext{}.Inject(ctx, carrier)
+
+	traceParent := carrier.Get(traceParentKey)
+	if traceParent == "" {
+		return
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	annotations[v1alpha1.AnnotationTraceParent] = traceParent
+
+	obj.SetAnnotations(annotations)
+}
+
+// Extract returns the trace context that is recorded on the object, if any.
+func Extract(obj metav1.Object) trace.SpanContext {
+	traceParent, ok := obj.GetAnnotations()[v1alpha1.AnnotationTraceParent]
+	if !ok {
+		return trace.SpanContext{}
+	}
+
+	carrier := propagation.MapCarrier{traceParentKey: traceParent}
+
+	return trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), carrier))
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestLinkAcrossControllers(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	// the scenario controller creates a service.
+	ctx, reconcile := tracing.StartReconcile(context.Background(), "Scenario", types.NamespacedName{Namespace: "ns", Name: "test"})
+
+	var service v1alpha1.Service
+
+	service.SetNamespace("ns")
+	service.SetName("server")
+	service.SetLabels(map[string]string{v1alpha1.LabelScenario: "test"})
+
+	createCtx, create := tracing.Start(ctx, "Create", &service)
+	tracing.Inject(createCtx, &service)
+	tracing.End(create, nil)
+
+	reconcile.End()
+
+	if _, ok := service.GetAnnotations()[v1alpha1.AnnotationTraceParent]; !ok {
+		t.Fatalf("trace context is not recorded on the object")
+	}
+
+	// the service controller reconciles the service.
+	_, lifecycle := tracing.Start(context.Background(), "Lifecycle", &service)
+	lifecycle.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+
+	created, reconciled := spans[0], spans[2]
+
+	if len(reconciled.Links()) != 1 || reconciled.Links()[0].SpanContext.SpanID() != created.SpanContext().SpanID() {
+		t.Errorf("reconciliation is not linked to the creation. links: %v", reconciled.Links())
+	}
+
+	found := false
+
+	for _, attr := range reconciled.Attributes() {
+		if string(attr.Key) == "frisbee.scenario" && attr.Value.AsString() == "test" {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("scenario attribute is missing. attributes: %v", reconciled.Attributes())
+	}
+}
+
+func TestInjectWithoutSpan(t *testing.T) {
+	var service v1alpha1.Service
+
+	tracing.Inject(context.Background(), &service)
+
+	if service.GetAnnotations() != nil {
+		t.Errorf("unexpected annotations %v", service.GetAnnotations())
+	}
+
+	if tracing.Extract(&service).IsValid() {
+		t.Errorf("unexpected trace context")
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package perf
+
+import (
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// reconcileMetric is exported by controller-runtime for every controller.
+const reconcileMetric = "controller_runtime_reconcile_total"
+
+// reconcileCounters returns the number of reconciliations per controller. The counters are process-wide,
+// so the measurements of a workload are the difference between two snapshots.
+func reconcileCounters() (map[string]float64, error) {
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot gather metrics")
+	}
+
+	counters := make(map[string]float64)
+
+	for _, family := range families {
+		if family.GetName() != reconcileMetric {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "controller" {
+					counters[label.GetValue()] += metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+
+	return counters, nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package perf_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/simulator"
+	"github.com/carv-ics-forth/frisbee/test/perf"
+)
+
+func TestParseWorkloads(t *testing.T) {
+	workloads, err := perf.ParseWorkloads("1x10, 5x20")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(workloads) != 2 || workloads[1].Clusters != 5 || workloads[1].Jobs != 20 {
+		t.Fatalf("unexpected workloads: %v", workloads)
+	}
+
+	for _, invalid := range []string{"", "10", "0x5", "1xa"} {
+		if _, err := perf.ParseWorkloads(invalid); err == nil {
+			t.Errorf("expected error for '%s'", invalid)
+		}
+	}
+}
+
+func TestNewPercentiles(t *testing.T) {
+	var samples []time.Duration
+
+	for i := 100; i > 0; i-- {
+		samples = append(samples, time.Duration(i)*time.Second)
+	}
+
+	got := perf.NewPercentiles(samples)
+
+	if got.P50 != 50 || got.P90 != 90 || got.P99 != 99 || got.Max != 100 {
+		t.Fatalf("unexpected percentiles: %+v", got)
+	}
+}
+
+// TestPerf runs the workloads of FRISBEE_PERF_WORKLOADS (e.g, "1x10,10x100"), and writes the reports to
+// FRISBEE_PERF_OUTPUT. It requires the envtest binaries. Use setup-envtest and set KUBEBUILDER_ASSETS.
+func TestPerf(t *testing.T) {
+	list := os.Getenv("FRISBEE_PERF_WORKLOADS")
+	if list == "" {
+		t.Skip("FRISBEE_PERF_WORKLOADS is not set")
+	}
+
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS is not set")
+	}
+
+	workloads, err := perf.ParseWorkloads(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sim, err := simulator.New(simulator.Options{
+		CRDDirectoryPaths: []string{"../../charts/platform/crds"},
+		Step:              5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	if err := sim.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := sim.Stop(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	reports := make([]*perf.Report, 0, len(workloads))
+
+	for _, workload := range workloads {
+		report, err := perf.Measure(ctx, sim, workload)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if report.Phase != v1alpha1.PhaseSuccess {
+			t.Errorf("workload '%s' completed with phase '%s'", workload, report.Phase)
+		}
+
+		reports = append(reports, report)
+	}
+
+	if err := perf.WriteTable(os.Stdout, reports); err != nil {
+		t.Fatal(err)
+	}
+
+	if output := os.Getenv("FRISBEE_PERF_OUTPUT"); output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer file.Close()
+
+		if err := perf.WriteJSON(file, reports); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package perf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/simulator"
+	"github.com/pkg/errors"
+)
+
+// Report summarizes the performance of the operator for a workload.
+type Report struct {
+	Workload Workload `json:"workload"`
+
+	// Phase is the final phase of the scenario.
+	Phase v1alpha1.Phase `json:"phase"`
+
+	// WallSeconds is the real duration of the scenario.
+	WallSeconds float64 `json:"wallSeconds"`
+
+	// SimulatedSeconds is the simulated duration of the scenario.
+	SimulatedSeconds float64 `json:"simulatedSeconds"`
+
+	// Reconciles is the number of reconciliations per controller.
+	Reconciles map[string]float64 `json:"reconciles"`
+
+	// ReconcilesPerSecond is the reconcile throughput of all the controllers, in wall time.
+	ReconcilesPerSecond float64 `json:"reconcilesPerSecond"`
+
+	// APIRequests is the number of requests that the controllers have issued to the API server.
+	APIRequests int64 `json:"apiRequests"`
+
+	// APIQPS is the rate of the API requests, in wall time.
+	APIQPS float64 `json:"apiQPS"`
+
+	// TimeToSchedule is the wall time from the submission of the scenario until a cluster becomes Running.
+	TimeToSchedule Percentiles `json:"timeToSchedule"`
+}
+
+// Percentiles summarizes a distribution of durations, in seconds.
+type Percentiles struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+// NewPercentiles computes the percentiles of the given samples, using the nearest-rank method.
+func NewPercentiles(samples []time.Duration) Percentiles {
+	if len(samples) == 0 {
+		return Percentiles{}
+	}
+
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := func(p float64) float64 {
+		index := int(p*float64(len(sorted))+0.5) - 1
+		if index < 0 {
+			index = 0
+		}
+
+		if index >= len(sorted) {
+			index = len(sorted) - 1
+		}
+
+		return sorted[index].Seconds()
+	}
+
+	return Percentiles{
+		P50: rank(0.5),
+		P90: rank(0.9),
+		P99: rank(0.99),
+		Max: sorted[len(sorted)-1].Seconds(),
+	}
+}
+
+// Measure runs the workload on a started simulator, and reports the performance of the controllers.
+func Measure(ctx context.Context, sim *simulator.Simulator, workload Workload) (*Report, error) {
+	reconcilesBefore, err := reconcileCounters()
+	if err != nil {
+		return nil, err
+	}
+
+	requestsBefore := sim.APIRequests()
+
+	result, err := sim.Run(ctx, workload.Namespace(), workload.Objects()...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "workload '%s' has failed", workload)
+	}
+
+	reconcilesAfter, err := reconcileCounters()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Workload:         workload,
+		Phase:            result.Scenario.Status.Phase,
+		WallSeconds:      result.Wall.Seconds(),
+		SimulatedSeconds: result.Duration.Seconds(),
+		Reconciles:       make(map[string]float64, len(reconcilesAfter)),
+		APIRequests:      sim.APIRequests() - requestsBefore,
+		TimeToSchedule:   NewPercentiles(timeToSchedule(result, workload)),
+	}
+
+	var total float64
+
+	for controller, count := range reconcilesAfter {
+		report.Reconciles[controller] = count - reconcilesBefore[controller]
+		total += report.Reconciles[controller]
+	}
+
+	if report.WallSeconds > 0 {
+		report.ReconcilesPerSecond = total / report.WallSeconds
+		report.APIQPS = float64(report.APIRequests) / report.WallSeconds
+	}
+
+	return report, nil
+}
+
+// timeToSchedule returns, for every cluster, the wall time until it was first observed in the Running phase.
+// Clusters that never ran are not included.
+func timeToSchedule(result *simulator.Result, workload Workload) []time.Duration {
+	scheduled := make(map[string]time.Duration, workload.Clusters)
+
+	for _, event := range result.Timeline {
+		if event.Action == "" || event.Phase != v1alpha1.PhaseRunning {
+			continue
+		}
+
+		if _, exists := scheduled[event.Action]; !exists {
+			scheduled[event.Action] = event.Wall
+		}
+	}
+
+	samples := make([]time.Duration, 0, len(scheduled))
+
+	for i := 0; i < workload.Clusters; i++ {
+		if wall, exists := scheduled[clusterName(i)]; exists {
+			samples = append(samples, wall)
+		}
+	}
+
+	return samples
+}
+
+// WriteJSON writes the reports in JSON format.
+func WriteJSON(w io.Writer, reports []*Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(reports)
+}
+
+// WriteTable writes the reports in human-readable format.
+func WriteTable(w io.Writer, reports []*Report) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(table, "WORKLOAD\tPHASE\tWALL(s)\tRECONCILES/s\tAPI QPS\tSCHEDULE P50(s)\tSCHEDULE P99(s)")
+
+	for _, report := range reports {
+		fmt.Fprintf(table, "%s\t%s\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\n",
+			report.Workload, report.Phase, report.WallSeconds, report.ReconcilesPerSecond, report.APIQPS,
+			report.TimeToSchedule.P50, report.TimeToSchedule.P99)
+	}
+
+	return table.Flush()
+}
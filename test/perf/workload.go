@@ -0,0 +1,142 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package perf benchmarks the Frisbee operator itself. It drives synthetic scenarios of N clusters with M jobs each
+through the simulator, and measures the reconcile throughput of the controllers, the time-to-schedule of the
+clusters, and the load that the controllers put on the API server.
+
+The results help users to size the control plane for their scenarios. They are published with every release.
+*/
+package perf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/simulator"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultJobDuration is the simulated runtime of every job.
+const DefaultJobDuration = time.Minute
+
+// Workload describes a synthetic scenario of Clusters, each of which runs Jobs in parallel.
+type Workload struct {
+	Clusters int `json:"clusters"`
+
+	Jobs int `json:"jobs"`
+
+	// JobDuration is the simulated runtime of every job.
+	JobDuration time.Duration `json:"jobDuration"`
+}
+
+func (w Workload) String() string {
+	return fmt.Sprintf("%dx%d", w.Clusters, w.Jobs)
+}
+
+// Namespace returns a distinct namespace for the workload.
+func (w Workload) Namespace() string {
+	return fmt.Sprintf("perf-%dx%d", w.Clusters, w.Jobs)
+}
+
+// ParseWorkloads parses a comma-separated list of workloads in the form NxM (e.g, "1x10,10x100").
+func ParseWorkloads(list string) ([]Workload, error) {
+	var workloads []Workload
+
+	for _, field := range strings.Split(list, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		clusters, jobs, found := strings.Cut(field, "x")
+		if !found {
+			return nil, errors.Errorf("workload '%s' is not in the form NxM", field)
+		}
+
+		n, err := strconv.Atoi(clusters)
+		if err != nil || n < 1 {
+			return nil, errors.Errorf("workload '%s' has invalid number of clusters", field)
+		}
+
+		m, err := strconv.Atoi(jobs)
+		if err != nil || m < 1 {
+			return nil, errors.Errorf("workload '%s' has invalid number of jobs", field)
+		}
+
+		workloads = append(workloads, Workload{Clusters: n, Jobs: m, JobDuration: DefaultJobDuration})
+	}
+
+	if len(workloads) == 0 {
+		return nil, errors.Errorf("no workload was given")
+	}
+
+	return workloads, nil
+}
+
+// clusterName returns the name of the i-th cluster action.
+func clusterName(i int) string {
+	return fmt.Sprintf("cluster-%d", i)
+}
+
+// Objects returns the template and the scenario of the workload.
+func (w Workload) Objects() []client.Object {
+	template := &v1alpha1.Template{
+		ObjectMeta: metav1.ObjectMeta{Name: "perf.job"},
+		Spec: v1alpha1.TemplateSpec{EmbedSpecs: &v1alpha1.EmbedSpecs{
+			Service: &v1alpha1.ServiceSpec{
+				Decorators: v1alpha1.Decorators{
+					Annotations: map[string]string{
+						simulator.DurationAnnotation: w.JobDuration.String(),
+					},
+				},
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: v1alpha1.MainContainerName, Image: "busybox"},
+					},
+				},
+			},
+		}},
+	}
+
+	scenario := &v1alpha1.Scenario{
+		ObjectMeta: metav1.ObjectMeta{Name: "perf"},
+	}
+
+	for i := 0; i < w.Clusters; i++ {
+		scenario.Spec.Actions = append(scenario.Spec.Actions, v1alpha1.Action{
+			ActionType: v1alpha1.ActionCluster,
+			Name:       clusterName(i),
+			EmbedActions: &v1alpha1.EmbedActions{
+				Cluster: &v1alpha1.ClusterSpec{
+					GenerateObjectFromTemplate: v1alpha1.GenerateObjectFromTemplate{
+						TemplateRef:  template.GetName(),
+						MaxInstances: w.Jobs,
+					},
+				},
+			},
+		})
+	}
+
+	return []client.Object{template, scenario}
+}